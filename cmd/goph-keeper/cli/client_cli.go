@@ -1,6 +1,10 @@
 package main
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
+
 	log "github.com/sirupsen/logrus"
 
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli"
@@ -12,7 +16,33 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "agent" {
+		if err = runAgent(client, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(os.Args) == 3 && os.Args[1] == "open-link" {
+		if err = client.OpenLink(os.Args[2]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if err = client.Start(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// runAgent parses the agent flags and serves the SSH keys until the process is interrupted.
+func runAgent(client *cli.AppCLI, args []string) error {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	socket := fs.String("socket", filepath.Join(os.TempDir(), "goph-keeper-agent.sock"), "path of the agent socket")
+	vault := fs.String("vault", "", "ID of the team vault collection holding the keys")
+	confirm := fs.Bool("confirm", false, "ask before every use of the key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return client.RunAgent(*socket, *vault, *confirm)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/backup"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const (
+	backupUsage  = "usage: goph-keeper-server backup FILE"
+	restoreUsage = "usage: goph-keeper-server restore [-dry-run] FILE"
+	// passphraseEnv holds the passphrase the archives are encrypted with, so it never shows in the process list.
+	passphraseEnv = "BACKUP_PASSPHRASE"
+)
+
+var (
+	errBackupUsage  = errors.New(backupUsage)
+	errRestoreUsage = errors.New(restoreUsage)
+	errPassphrase   = errors.New("the backup passphrase is missing, set it with " + passphraseEnv)
+	errNoStorage    = errors.New("the in-memory storage keeps nothing without the data directory")
+)
+
+// runBackup writes the encrypted archive of the storage to the file.
+// The archive replaces the file only when it is complete, so the failed backup never overwrites the previous one.
+func runBackup(cfg ServerConfig, args []string) error {
+	if len(args) != 1 {
+		return errBackupUsage
+	}
+	passphrase := os.Getenv(passphraseEnv)
+	if passphrase == "" {
+		return errPassphrase
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*30)
+	defer cancel()
+
+	st, bs, err := openBackupService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStorage(st)
+
+	f, err := os.CreateTemp(filepath.Dir(args[0]), filepath.Base(args[0])+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer removeFile(f.Name())
+
+	sum, err := bs.Backup(ctx, f, passphrase)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(f.Name(), args[0]); err != nil {
+		return err
+	}
+
+	log.Infof("backup written to %s", args[0])
+	return printSummary(os.Stdout, sum)
+}
+
+// runRestore restores the archive to the storage, or only verifies it with the dry-run flag.
+// The database schema is brought up to date first, so the archive can be restored to the empty database.
+func runRestore(cfg ServerConfig, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	dryRun := fs.Bool("dry-run", false, "verify the archive and roll the restore back")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return errRestoreUsage
+	}
+	passphrase := os.Getenv(passphraseEnv)
+	if passphrase == "" {
+		return errPassphrase
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := f.Close(); cErr != nil {
+			log.Error(cErr)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*30)
+	defer cancel()
+
+	st, bs, err := openBackupService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStorage(st)
+	if err = applyMigrations(st.DB()); err != nil {
+		return err
+	}
+
+	sum, err := bs.Restore(ctx, f, passphrase, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		log.Infof("%s is valid, and can be restored", fs.Arg(0))
+	} else {
+		log.Infof("%s is restored", fs.Arg(0))
+	}
+	return printSummary(os.Stdout, sum)
+}
+
+// openBackupService opens the storage the server is configured with.
+// The in-memory storage is read from the data directory, so the server must be stopped while the command runs.
+func openBackupService(ctx context.Context, cfg ServerConfig) (*storage.Storage, backup.Service, error) {
+	sCfg := cfg.GetStorageConfig()
+	if sCfg.URL == "" && sCfg.DataDir == "" {
+		return nil, backup.Service{}, errNoStorage
+	}
+
+	st, err := storage.New(ctx, sCfg)
+	if err != nil {
+		return nil, backup.Service{}, err
+	}
+
+	bs, err := newBackupService(st)
+	if err != nil {
+		closeStorage(st)
+		return nil, backup.Service{}, err
+	}
+	return st, bs, nil
+}
+
+// newBackupService builds the microservices upon the storage the server keeps them in,
+// so the in-memory repositories are restored from and persisted to the same logs the server uses.
+func newBackupService(st *storage.Storage) (backup.Service, error) {
+	w, err := st.WAL("users")
+	if err != nil {
+		return backup.Service{}, err
+	}
+	us, err := user.NewService(st.DB(), w)
+	if err != nil {
+		return backup.Service{}, err
+	}
+
+	if w, err = st.WAL("data"); err != nil {
+		return backup.Service{}, err
+	}
+	ds, err := data.NewService(st.DB(), w)
+	if err != nil {
+		return backup.Service{}, err
+	}
+
+	if w, err = st.WAL("orgs"); err != nil {
+		return backup.Service{}, err
+	}
+	orgs, err := org.NewService(st.DB(), w, ds)
+	if err != nil {
+		return backup.Service{}, err
+	}
+
+	if w, err = st.WAL("emergency"); err != nil {
+		return backup.Service{}, err
+	}
+	es, err := emergency.NewService(st.DB(), w)
+	if err != nil {
+		return backup.Service{}, err
+	}
+
+	if w, err = st.WAL("tokens"); err != nil {
+		return backup.Service{}, err
+	}
+	ts, err := apitoken.NewService(st.DB(), w)
+	if err != nil {
+		return backup.Service{}, err
+	}
+
+	if w, err = st.WAL("identities"); err != nil {
+		return backup.Service{}, err
+	}
+	ids, err := oidc.NewIdentityService(st.DB(), w)
+	if err != nil {
+		return backup.Service{}, err
+	}
+	return backup.NewService(storage.NewUnitOfWork(st.DB()), ds, us, orgs, es, ts, ids), nil
+}
+
+func printSummary(out io.Writer, s backup.Summary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tCREATED\tUSERS\tKEY PAIRS\tITEMS\tSHARES\tSEALED SHARES\tORGS\tMEMBERS\t"+
+		"COLLECTIONS\tCONTACTS\tEVENTS\tTOKENS\tIDENTITIES")
+	_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n", s.Version,
+		s.CreatedAt.Local().Format(time.RFC822), s.Users, s.KeyPairs, s.Items, s.Shares, s.SealedShares, s.Orgs,
+		s.Members, s.Collections, s.Contacts, s.Events, s.Tokens, s.Identities)
+	return w.Flush()
+}
+
+func closeStorage(st *storage.Storage) {
+	if err := st.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+// removeFile drops the temporary file left by the failed backup.
+func removeFile(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error(err)
+	}
+}
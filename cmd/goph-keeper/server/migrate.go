@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/migrate"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const migrateUsage = "usage: goph-keeper-server migrate up|down [steps]|status"
+
+var (
+	errMigrateUsage  = errors.New(migrateUsage)
+	errMigrateSQLite = errors.New("the SQLite repositories create their tables on start and need no migrations")
+)
+
+// runMigrate executes the migrate command with the arguments following it, e.g. "down 2".
+func runMigrate(cfg ServerConfig, args []string) error {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2 && args[0] != "down") {
+		return errMigrateUsage
+	}
+	if strings.HasPrefix(cfg.GetRepoURL(), storage.SQLiteScheme) {
+		return errMigrateSQLite
+	}
+
+	m, err := migrate.New(cfg.GetRepoURL())
+	if err != nil {
+		return err
+	}
+	defer closeMigrator(m)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		return migrateUp(ctx, m)
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return errMigrateUsage
+			}
+		}
+		return migrateDown(ctx, m, steps)
+	case "status":
+		return migrateStatus(ctx, m)
+	}
+	return errMigrateUsage
+}
+
+// applyMigrations brings the database schema up to date before the server starts.
+// The migrator runs on the shared storage pool, so it must not be closed here.
+// The in-memory and SQLite storages need no migrations.
+func applyMigrations(db *sql.DB) error {
+	if db == nil || storage.IsSQLite(db) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+	return migrateUp(ctx, migrate.NewMigrator(db, migrate.Migrations))
+}
+
+func migrateUp(ctx context.Context, m *migrate.Migrator) error {
+	done, err := m.Up(ctx)
+	for _, mg := range done {
+		log.Infof("applied migration %d %s", mg.Version, mg.Name)
+	}
+	return err
+}
+
+func migrateDown(ctx context.Context, m *migrate.Migrator, steps int) error {
+	done, err := m.Down(ctx, steps)
+	for _, mg := range done {
+		log.Infof("rolled back migration %d %s", mg.Version, mg.Name)
+	}
+	return err
+}
+
+func migrateStatus(ctx context.Context, m *migrate.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if !s.AppliedAt.IsZero() {
+			applied = s.AppliedAt.Local().Format(time.RFC822)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
+
+func closeMigrator(m *migrate.Migrator) {
+	if err := m.Close(); err != nil {
+		log.Error(err)
+	}
+}
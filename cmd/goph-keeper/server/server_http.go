@@ -17,6 +17,8 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/handlers"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 var (
@@ -24,18 +26,50 @@ var (
 	buildDate    string
 )
 
+// commands are run instead of the server when their name is the first argument.
+var commands = map[string]func(cfg ServerConfig, args []string) error{
+	"backup":  runBackup,
+	"migrate": runMigrate,
+	"restore": runRestore,
+}
+
 type ServerConfig interface {
 	GetRepoURL() string
+	GetStorageConfig() storage.Config
 	GetServerAddress() string
 	IsServerSecure() bool
 	GetCACertPool() (*x509.CertPool, error)
 	GetCertificatePaths() []string
+	GetCertAuthMode() string
+	GetCertAuthField() string
+	GetCertAuthUsers() map[string]string
+	GetOIDCConfig() oidc.Config
+	GetEmergencyInterval() time.Duration
+	GetRevocationCleanupInterval() time.Duration
 }
 
 func main() {
 	printCompilationInfo()
 	cfg := config.New(config.WithEnv(), config.WithFile())
-	s, err := getServer(cfg)
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(cfg, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	st, err := storage.New(ctx, cfg.GetStorageConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = applyMigrations(st.DB()); err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := getServer(ctx, cfg, st)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -45,7 +79,8 @@ func main() {
 		exit := make(chan os.Signal, 1)
 		signal.Notify(exit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGTERM)
 		<-exit
-		stopServer(s)
+		stopServer(s, st)
+		cancel()
 		close(idleConnectionsClosed)
 	}()
 
@@ -53,8 +88,20 @@ func main() {
 	<-idleConnectionsClosed
 }
 
-func getServer(cfg ServerConfig) (*http.Server, error) {
-	h, err := handlers.NewHandler(cfg.GetRepoURL())
+func getServer(ctx context.Context, cfg ServerConfig, st *storage.Storage) (*http.Server, error) {
+	opts := []func(*handlers.Handler){
+		handlers.WithEmergencyScheduler(ctx, cfg.GetEmergencyInterval()),
+		handlers.WithRevocationCleanup(ctx, cfg.GetRevocationCleanupInterval()),
+		handlers.WithWAL(st.WAL),
+	}
+	if cfg.IsServerSecure() {
+		opts = append(opts, handlers.WithCertAuth(cfg.GetCertAuthMode(), cfg.GetCertAuthField(), cfg.GetCertAuthUsers()))
+	}
+	if oidcCfg := cfg.GetOIDCConfig(); oidcCfg.Issuer != "" {
+		opts = append(opts, handlers.WithOIDC(oidcCfg))
+	}
+
+	h, err := handlers.NewHandler(st.DB(), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -90,13 +137,17 @@ func startServer(s *http.Server, cfg ServerConfig) {
 	}
 }
 
-func stopServer(s *http.Server) {
+// stopServer waits for the active requests to complete, and closes the storage connection pool afterwards.
+func stopServer(s *http.Server, st *storage.Storage) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
 	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Error(err)
 	}
+	if err := st.Close(); err != nil {
+		log.Error(err)
+	}
 }
 
 func getTLSConfig(cfg ServerConfig) (*tls.Config, error) {
@@ -3,7 +3,13 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/manifoldco/promptui"
@@ -13,6 +19,8 @@ import (
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/views"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client/config"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/sshagent"
 )
 
 type View interface {
@@ -20,11 +28,13 @@ type View interface {
 }
 
 type AppCLI struct {
-	client   client.KeeperClient
-	binary   View
-	card     View
-	password View
-	text     View
+	client    client.KeeperClient
+	sso       bool
+	account   View
+	emergency View
+	items     map[views.MenuOption]View
+	org       View
+	template  View
 }
 
 func NewCLI() (*AppCLI, error) {
@@ -34,16 +44,26 @@ func NewCLI() (*AppCLI, error) {
 		return nil, err
 	}
 
+	itemViews := make(map[views.MenuOption]View)
+	for _, t := range items.All() {
+		itemViews[views.MenuOption(t.Title())] = views.NewItemView(c, t)
+	}
+
 	return &AppCLI{
-		client:   c,
-		binary:   views.NewBinaryView(c),
-		card:     views.NewCardView(c),
-		password: views.NewPasswordView(c),
-		text:     views.NewTextView(c),
+		client:    c,
+		sso:       cfg.IsSSOEnabled(),
+		account:   views.NewAccountView(c),
+		emergency: views.NewEmergencyView(c),
+		items:     itemViews,
+		org:       views.NewOrgView(c),
+		template:  views.NewTemplateView(c),
 	}, nil
 }
 
 func (app *AppCLI) Start() error {
+	if app.client.IsTokenAuthorized() {
+		return app.mainMenu()
+	}
 	if err := app.login(); err != nil {
 		if errors.Is(err, client.ErrUnauthorized) {
 			if retry, rErr := inputs.LoginRetry(); rErr != nil {
@@ -57,7 +77,79 @@ func (app *AppCLI) Start() error {
 	return app.mainMenu()
 }
 
+// OpenLink prints the secret of the one-time link. The link can be opened without logging in.
+func (app *AppCLI) OpenLink(link string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	secret, err := app.client.OpenLink(ctx, link)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s:\n%s\n", secret.Name, secret.Data)
+	if secret.ViewsLeft > 0 {
+		fmt.Printf("The link can be opened %d more time(s).\n", secret.ViewsLeft)
+	} else {
+		fmt.Println("The link has been used up and no longer works.")
+	}
+	return nil
+}
+
+// RunAgent serves the SSH keys of the personal or team vault over the ssh-agent protocol at the socket path,
+// until the process is interrupted. With confirm set, every use of the key must be allowed in the terminal.
+func (app *AppCLI) RunAgent(socket, vault string, confirm bool) error {
+	if !app.client.IsTokenAuthorized() {
+		if err := app.login(); err != nil {
+			return err
+		}
+	}
+	app.client.UseVault(vault)
+
+	var confirmFn func(name string) bool
+	if confirm {
+		confirmFn = func(name string) bool {
+			answer, err := inputs.AgentConfirm(name)
+			return err == nil && strings.HasPrefix(strings.ToLower(answer), "y")
+		}
+	}
+	a := sshagent.New(app.client, confirmFn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	if err := a.Load(ctx); err != nil {
+		return err
+	}
+
+	if err := os.Remove(socket); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(socket) }()
+	if err = os.Chmod(socket, 0o600); err != nil {
+		_ = l.Close()
+		return err
+	}
+
+	sig, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sig.Done()
+		_ = l.Close()
+	}()
+
+	fmt.Printf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", socket)
+	return a.Serve(l)
+}
+
 func (app *AppCLI) login() error {
+	if app.sso {
+		return app.loginSSO()
+	}
+
 	user, err := inputs.Username()
 	if err != nil {
 		return err
@@ -73,10 +165,25 @@ func (app *AppCLI) login() error {
 	return app.client.Login(ctx, user, password)
 }
 
+func (app *AppCLI) loginSSO() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+	return app.client.LoginOIDC(ctx, func(url string) {
+		fmt.Printf("Open the following URL in the browser to sign in:\n%s\n", url)
+	})
+}
+
 func (app *AppCLI) mainMenu() error {
+	label := "What type of data would you like to work with?"
+	if app.client.GetVault() != "" {
+		label = "What type of team vault data would you like to work with?"
+	} else if app.client.GetEmergencyAccess() != "" {
+		label = "What type of emergency access data would you like to work with?"
+	}
+
 	mp := promptui.Select{
-		Label: "What type of data would you like to work with?",
-		Items: views.MenuList,
+		Label: label,
+		Items: views.MenuList(),
 	}
 
 	_, opt, err := mp.Run()
@@ -85,16 +192,22 @@ func (app *AppCLI) mainMenu() error {
 	}
 
 	switch views.MenuOption(opt) {
-	case views.MBinary:
-		err = app.binary.ShowMenu()
-	case views.MCard:
-		err = app.card.ShowMenu()
-	case views.MPassword:
-		err = app.password.ShowMenu()
-	case views.MText:
-		err = app.text.ShowMenu()
+	case views.MTemplate:
+		err = app.template.ShowMenu()
+	case views.MOrg:
+		err = app.org.ShowMenu()
+	case views.MEmergency:
+		err = app.emergency.ShowMenu()
+	case views.MAccount:
+		if err = app.account.ShowMenu(); errors.Is(err, views.ErrAccountDeleted) {
+			return nil
+		}
 	case views.MExit:
 		return nil
+	default:
+		if v, ok := app.items[views.MenuOption(opt)]; ok {
+			err = v.ShowMenu()
+		}
 	}
 
 	if err != nil {
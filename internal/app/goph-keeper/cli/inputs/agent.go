@@ -0,0 +1,12 @@
+package inputs
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+)
+
+func AgentConfirm(name string) (string, error) {
+	cp := promptui.Prompt{Label: fmt.Sprintf("Allow the use of the SSH key %q? (y/N)", name)}
+	return cp.Run()
+}
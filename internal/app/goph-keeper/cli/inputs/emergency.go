@@ -0,0 +1,23 @@
+package inputs
+
+import (
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func EmergencyContactID() (string, error) {
+	ip := promptui.Prompt{Label: "Enter the emergency contact ID", Validate: validators.Min(1)}
+	return ip.Run()
+}
+
+func EmergencyContactUser() (string, error) {
+	up := promptui.Prompt{Label: "Enter the username of the trusted contact", Validate: validators.Min(1)}
+	return up.Run()
+}
+
+func EmergencyWait() (string, error) {
+	wp := promptui.Prompt{Label: "Enter the number of hours to wait before the access is granted", Default: "48",
+		Validate: validators.PositiveNumber}
+	return wp.Run()
+}
@@ -1,8 +1,14 @@
 package inputs
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/manifoldco/promptui"
 
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
 )
 
@@ -11,17 +17,90 @@ func ItemID() (string, error) {
 	return ip.Run()
 }
 
-func ItemName() (string, error) {
-	np := promptui.Prompt{Label: "Enter the item name", Validate: validators.ItemName}
-	return np.Run()
+func ItemText() (string, error) {
+	tp := promptui.Prompt{Label: "Enter the text", Validate: validators.Min(1)}
+	return tp.Run()
+}
+
+// ItemField prompts for the value of the item field. The hidden values are masked,
+// and the field is marked as optional if its validation accepts the empty value.
+func ItemField(f items.Field) (string, error) {
+	validate := getFieldValidator(f)
+	label := "Enter the " + getFieldLabel(f)
+	if f.Kind == items.KindDate {
+		label += " (YYYY-MM-DD)"
+	}
+	if validate("") == nil {
+		label += " (optional)"
+	}
+
+	fp := promptui.Prompt{Label: label, Validate: validate}
+	if f.Kind == items.KindHidden {
+		fp.Mask = '*'
+	}
+	return fp.Run()
+}
+
+// ItemMultiline reads the value of the multiline item field line by line, until the line holding the single dot.
+// The value is read again if it's not valid.
+func ItemMultiline(f items.Field) (string, error) {
+	validate := getFieldValidator(f)
+	for {
+		fmt.Printf("Enter the %s, finishing it with the line holding the single dot:\n", getFieldLabel(f))
+		var lines []string
+		sc := bufio.NewScanner(os.Stdin)
+		for sc.Scan() && sc.Text() != "." {
+			lines = append(lines, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return "", err
+		}
+
+		value := strings.Join(lines, "\n")
+		err := validate(value)
+		if err == nil {
+			return value, nil
+		}
+		fmt.Println(err)
+	}
+}
+
+func CustomFieldAdd() (string, error) {
+	ap := promptui.Prompt{Label: "Would you like to add a custom field? (y/N)"}
+	return ap.Run()
 }
 
-func ItemNote() (string, error) {
-	np := promptui.Prompt{Label: "Add a note (optional)", Validate: validators.Max(50)}
+func CustomFieldName() (string, error) {
+	np := promptui.Prompt{Label: "Enter the field name", Validate: validators.Max(50)}
 	return np.Run()
 }
 
-func ItemText() (string, error) {
-	tp := promptui.Prompt{Label: "Enter the text", Validate: validators.Min(1)}
-	return tp.Run()
+func FieldKind() (items.Kind, error) {
+	kp := promptui.Select{Label: "Select the field kind", Items: items.Kinds}
+	_, kind, err := kp.Run()
+	return items.Kind(kind), err
+}
+
+// getFieldValidator returns the function checking the value of the field as the server does,
+// and with the field's own validation.
+func getFieldValidator(f items.Field) func(v string) error {
+	return func(v string) error {
+		if f.Required && v == "" {
+			return items.ErrRequired
+		}
+		if f.Validate != nil {
+			if err := f.Validate(v); err != nil {
+				return err
+			}
+		}
+		return f.Kind.Check(v)
+	}
+}
+
+// getFieldLabel returns the field label in lower case, unless it's the abbreviation, e.g. "CVV".
+func getFieldLabel(f items.Field) string {
+	if strings.ToUpper(f.Label) == f.Label {
+		return f.Label
+	}
+	return strings.ToLower(f.Label)
 }
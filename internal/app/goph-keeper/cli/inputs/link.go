@@ -0,0 +1,24 @@
+package inputs
+
+import (
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func LinkID() (string, error) {
+	ip := promptui.Prompt{Label: "Enter the link ID", Validate: validators.Min(1)}
+	return ip.Run()
+}
+
+func LinkViews() (string, error) {
+	vp := promptui.Prompt{Label: "Enter the number of times the link can be opened", Default: "1",
+		Validate: validators.PositiveNumber}
+	return vp.Run()
+}
+
+func LinkExpiry() (string, error) {
+	ep := promptui.Prompt{Label: "Enter the number of hours the link is valid", Default: "24",
+		Validate: validators.PositiveNumber}
+	return ep.Run()
+}
@@ -0,0 +1,45 @@
+package inputs
+
+import (
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+var memberRoles = []string{"owner", "admin", "member", "read-only"}
+
+func OrgID() (string, error) {
+	ip := promptui.Prompt{Label: "Enter the organization ID", Validate: validators.Min(1)}
+	return ip.Run()
+}
+
+func OrgName() (string, error) {
+	np := promptui.Prompt{Label: "Enter the organization name", Validate: validators.ItemName}
+	return np.Run()
+}
+
+func CollectionID() (string, error) {
+	ip := promptui.Prompt{Label: "Enter the collection ID", Validate: validators.Min(1)}
+	return ip.Run()
+}
+
+func CollectionName() (string, error) {
+	np := promptui.Prompt{Label: "Enter the collection name", Validate: validators.ItemName}
+	return np.Run()
+}
+
+func MemberUser() (string, error) {
+	up := promptui.Prompt{Label: "Enter the username of the member", Validate: validators.Min(1)}
+	return up.Run()
+}
+
+func MemberRole() (string, error) {
+	rp := promptui.Select{Label: "Select the member role", Items: memberRoles}
+	_, role, err := rp.Run()
+	return role, err
+}
+
+func DeleteOrgConfirm() (string, error) {
+	cp := promptui.Prompt{Label: "All the organization collections and items will be deleted. Continue? (y/N)"}
+	return cp.Run()
+}
@@ -0,0 +1,17 @@
+package inputs
+
+import (
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func ShareUser() (string, error) {
+	up := promptui.Prompt{Label: "Enter the username of the share recipient", Validate: validators.Min(1)}
+	return up.Run()
+}
+
+func ShareReadOnly() (string, error) {
+	rp := promptui.Prompt{Label: "Should the item be shared as read-only? (Y/n)"}
+	return rp.Run()
+}
@@ -0,0 +1,40 @@
+package inputs
+
+import (
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+// noTemplate is the option of creating the custom item without the template.
+const noTemplate = "(none)"
+
+func TemplateID() (string, error) {
+	ip := promptui.Prompt{Label: "Enter the template ID", Validate: validators.Min(1)}
+	return ip.Run()
+}
+
+func TemplateName() (string, error) {
+	np := promptui.Prompt{Label: "Enter the template name", Validate: validators.ItemName}
+	return np.Run()
+}
+
+// TemplateSelect returns the name of the selected template, or the empty string if no template is selected.
+func TemplateSelect(names []string) (string, error) {
+	tp := promptui.Select{Label: "Select the template", Items: append([]string{noTemplate}, names...)}
+	_, name, err := tp.Run()
+	if name == noTemplate {
+		return "", err
+	}
+	return name, err
+}
+
+func TemplateFieldAdd() (string, error) {
+	ap := promptui.Prompt{Label: "Would you like to add a template field? (Y/n)"}
+	return ap.Run()
+}
+
+func TemplateFieldRequired() (string, error) {
+	rp := promptui.Prompt{Label: "Is the field required? (y/N)"}
+	return rp.Run()
+}
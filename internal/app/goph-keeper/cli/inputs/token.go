@@ -0,0 +1,32 @@
+package inputs
+
+import (
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func TokenID() (string, error) {
+	ip := promptui.Prompt{Label: "Enter the API token ID", Validate: validators.Min(1)}
+	return ip.Run()
+}
+
+func TokenName() (string, error) {
+	np := promptui.Prompt{Label: "Enter the API token name", Validate: validators.ItemName}
+	return np.Run()
+}
+
+func TokenReadOnly() (string, error) {
+	rp := promptui.Prompt{Label: "Should the API token be read-only? (y/N)"}
+	return rp.Run()
+}
+
+func TokenTypes() (string, error) {
+	tp := promptui.Prompt{Label: "Enter the comma-separated item types the API token can access (optional)"}
+	return tp.Run()
+}
+
+func TokenExpiry() (string, error) {
+	ep := promptui.Prompt{Label: "Enter the number of days the API token is valid", Validate: validators.PositiveNumber}
+	return ep.Run()
+}
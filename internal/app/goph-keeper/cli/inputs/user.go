@@ -23,3 +23,26 @@ func LoginRetry() (string, error) {
 	}
 	return ep.Run()
 }
+
+func NewUsername() (string, error) {
+	up := promptui.Prompt{Label: "Enter the new username", Validate: validators.Min(1)}
+	return up.Run()
+}
+
+func NewPassword() (string, error) {
+	pp := promptui.Prompt{Label: "Enter the new user password", Validate: validators.Min(1)}
+	return pp.Run()
+}
+
+func DeleteAccountConfirm() (string, error) {
+	dp := promptui.Prompt{
+		Label:    "All the stored data will be lost. Are you sure you want to delete the account? (y/N)",
+		Validate: validators.Min(1),
+	}
+	return dp.Run()
+}
+
+func SessionID() (string, error) {
+	sp := promptui.Prompt{Label: "Enter the session ID", Validate: validators.Min(1)}
+	return sp.Run()
+}
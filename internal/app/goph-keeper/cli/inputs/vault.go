@@ -0,0 +1,37 @@
+package inputs
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/importers"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+var duplicatePolicies = []string{"skip", "replace", "keep"}
+
+func ArchivePassword() (string, error) {
+	pp := promptui.Prompt{Label: "Enter the archive password", Validate: validators.Min(1)}
+	return pp.Run()
+}
+
+func DuplicatesPolicy() (string, error) {
+	dp := promptui.Select{Label: "What should be done with the items named as the stored ones?", Items: duplicatePolicies}
+	_, policy, err := dp.Run()
+	return policy, err
+}
+
+func ImportFormat() (importers.Format, error) {
+	fp := promptui.Select{Label: "Which password manager is the export from?", Items: importers.Formats}
+	i, _, err := fp.Run()
+	if err != nil {
+		return "", err
+	}
+	return importers.Formats[i], nil
+}
+
+func ImportConfirm(n int) (string, error) {
+	cp := promptui.Prompt{Label: fmt.Sprintf("Import %d item(s)? (y/N)", n)}
+	return cp.Run()
+}
@@ -0,0 +1,415 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/importers"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+type AccountClient interface {
+	client.AccountClient
+	client.LinkClient
+	client.SealedClient
+	client.BatchClient
+	DeleteSession(ctx context.Context, cid string) error
+	GetSessions(ctx context.Context) ([]models.SessionResponse, error)
+}
+
+type Account struct {
+	keeper AccountClient
+}
+
+type accountOption string
+
+const (
+	aName     accountOption = "Change the username"
+	aPassword accountOption = "Change the password"
+	aSessions accountOption = "Show active sessions"
+	aRevoke   accountOption = "Revoke a session"
+	aTokens   accountOption = "Show API tokens"
+	aCreate   accountOption = "Create an API token"
+	aRevokeT  accountOption = "Revoke an API token"
+	aLink     accountOption = "Share a secret via one-time link"
+	aLinks    accountOption = "Show one-time links"
+	aRevokeL  accountOption = "Revoke a one-time link"
+	aSealed   accountOption = "Show the items sealed to you"
+	aDelSeal  accountOption = "Delete a sealed item"
+	aExport   accountOption = "Export the vault to a file"
+	aImport   accountOption = "Import the vault from a file"
+	aMigrate  accountOption = "Import from another password manager"
+	aDelete   accountOption = "Delete the account"
+	aBack     accountOption = accountOption(cBack)
+)
+
+var (
+	ErrAccountDeleted = errors.New("the account has been deleted")
+
+	accountCommandList = []accountOption{
+		aName, aPassword, aSessions, aRevoke, aTokens, aCreate, aRevokeT, aLink, aLinks, aRevokeL, aSealed, aDelSeal,
+		aExport, aImport, aMigrate, aDelete, aBack,
+	}
+	sessionHeader = []string{"ID", "Device", "IP", "User agent", "Created", "Last seen"}
+	tokenHeader   = []string{"ID", "Name", "Access", "Types", "Created", "Expires"}
+	importHeader  = []string{"Record", "Type", "Name", "ID", "Status"}
+)
+
+func NewAccountView(keeper AccountClient) *Account {
+	return &Account{keeper: keeper}
+}
+
+func (v *Account) ShowMenu() error {
+	mp := promptui.Select{
+		Label: "What would you like to do with the account?",
+		Items: accountCommandList,
+	}
+
+	_, res, err := mp.Run()
+	if err != nil {
+		return err
+	}
+
+	switch accountOption(res) {
+	case aName:
+		err = v.changeName()
+	case aPassword:
+		err = v.changePassword()
+	case aSessions:
+		err = v.getSessions()
+	case aRevoke:
+		err = v.revokeSession()
+	case aTokens:
+		err = v.getTokens()
+	case aCreate:
+		err = v.createToken()
+	case aRevokeT:
+		err = v.revokeToken()
+	case aLink:
+		err = createTextLink(v.keeper)
+	case aLinks:
+		err = getLinks(v.keeper)
+	case aRevokeL:
+		err = revokeLink(v.keeper)
+	case aSealed:
+		err = getSealedItems(v.keeper)
+	case aDelSeal:
+		err = deleteSealedItem(v.keeper)
+	case aExport:
+		err = v.exportVault()
+	case aImport:
+		err = v.importVault()
+	case aMigrate:
+		err = v.importExport()
+	case aDelete:
+		err = v.deleteAccount()
+	case aBack:
+		return nil
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrAccountDeleted) {
+			return err
+		}
+		log.Error(err)
+	}
+	return v.ShowMenu()
+}
+
+func (v *Account) changeName() error {
+	name, err := inputs.NewUsername()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.ChangeName(ctx, name); err != nil {
+		return err
+	}
+	fmt.Println("The username has been changed successfully.")
+	return nil
+}
+
+func (v *Account) changePassword() error {
+	password, err := inputs.Password()
+	if err != nil {
+		return err
+	}
+	newPassword, err := inputs.NewPassword()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.ChangePassword(ctx, password, newPassword); err != nil {
+		return err
+	}
+	fmt.Println("The password has been changed successfully. All the other sessions have been closed.")
+	return nil
+}
+
+func (v *Account) getSessions() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	sessions, err := v.keeper.GetSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(sessionHeader)
+	for _, s := range sessions {
+		table.Append(s.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Account) revokeSession() error {
+	cid, err := inputs.SessionID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteSession(ctx, cid); err != nil {
+		return err
+	}
+	fmt.Println("The session has been revoked successfully.")
+	return nil
+}
+
+func (v *Account) getTokens() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	tokens, err := v.keeper.GetTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(tokenHeader)
+	for _, t := range tokens {
+		table.Append(t.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Account) createToken() error {
+	name, err := inputs.TokenName()
+	if err != nil {
+		return err
+	}
+	readOnly, err := inputs.TokenReadOnly()
+	if err != nil {
+		return err
+	}
+	types, err := inputs.TokenTypes()
+	if err != nil {
+		return err
+	}
+	expiry, err := inputs.TokenExpiry()
+	if err != nil {
+		return err
+	}
+	days, err := strconv.Atoi(expiry)
+	if err != nil {
+		return err
+	}
+
+	scope := models.TokenScope{ReadOnly: strings.HasPrefix(strings.ToLower(readOnly), "y")}
+	for _, t := range strings.Split(types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			scope.Types = append(scope.Types, t)
+		}
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	token, err := v.keeper.CreateToken(ctx, name, scope, time.Now().AddDate(0, 0, days))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The API token has been created successfully. Copy it now, it won't be shown again:\n%s\n", token.Token)
+	return nil
+}
+
+func (v *Account) revokeToken() error {
+	id, err := inputs.TokenID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteToken(ctx, id); err != nil {
+		return err
+	}
+	fmt.Println("The API token has been revoked successfully.")
+	return nil
+}
+
+func (v *Account) exportVault() error {
+	path, err := inputs.FilePath()
+	if err != nil {
+		return err
+	}
+	password, err := inputs.ArchivePassword()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	archive, err := v.keeper.ExportVault(ctx, password)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, archive, 0o600); err != nil {
+		return err
+	}
+	fmt.Println("The vault has been exported successfully.")
+	return nil
+}
+
+func (v *Account) importVault() error {
+	path, err := inputs.FilePath()
+	if err != nil {
+		return err
+	}
+	archive, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	password, err := inputs.ArchivePassword()
+	if err != nil {
+		return err
+	}
+	duplicates, err := inputs.DuplicatesPolicy()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	sum, err := v.keeper.ImportVault(ctx, archive, password, duplicates)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The vault has been imported successfully: %d imported, %d replaced, %d skipped.\n",
+		sum.Imported, sum.Replaced, sum.Skipped)
+	if sum.UnknownSections > 0 {
+		fmt.Printf("%d sections of the unknown item types have been skipped.\n", sum.UnknownSections)
+	}
+	return nil
+}
+
+// importExport stores the items from the export of another password manager, after the user confirms the preview.
+func (v *Account) importExport() error {
+	format, err := inputs.ImportFormat()
+	if err != nil {
+		return err
+	}
+	path, err := inputs.FilePath()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	batch, err := importers.Parse(format, f)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("The following records have been found in the export:")
+	showImportOutcomes(batch.Preview())
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	confirm, err := inputs.ImportConfirm(batch.Len())
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.ToLower(confirm), "y") {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	res := importers.Store(ctx, v.keeper, batch)
+	showImportOutcomes(res)
+
+	var failed int
+	for _, o := range res {
+		if o.Err != nil {
+			failed++
+		}
+	}
+	fmt.Printf("%d item(s) have been imported, %d record(s) have failed.\n", len(res)-failed, failed)
+	return nil
+}
+
+func (v *Account) deleteAccount() error {
+	confirm, err := inputs.DeleteAccountConfirm()
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(confirm)[:1] != "y" {
+		return nil
+	}
+
+	password, err := inputs.Password()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteAccount(ctx, password); err != nil {
+		return err
+	}
+	fmt.Println("The account has been deleted successfully.")
+	return ErrAccountDeleted
+}
+
+func showImportOutcomes(outcomes []importers.Outcome) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(importHeader)
+	for _, o := range outcomes {
+		table.Append(o.TableRow())
+	}
+	table.Render()
+}
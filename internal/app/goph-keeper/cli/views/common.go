@@ -8,23 +8,28 @@ import (
 
 	"github.com/manifoldco/promptui"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
 )
 
 type viewer interface {
 	getItem() error
 	getItems() error
 	saveItem() error
+	updateItem() error
 	deleteItem() error
+	getShareClient() (client.ShareClient, string)
 }
 
 type MenuOption string
 
 const (
-	MBinary   MenuOption = "Binaries"
-	MCard     MenuOption = "Cards"
-	MPassword MenuOption = "Passwords"
-	MText     MenuOption = "Texts"
-	MExit     MenuOption = "Exit"
+	MTemplate  MenuOption = "Templates"
+	MOrg       MenuOption = "Organizations"
+	MEmergency MenuOption = "Emergency access"
+	MAccount   MenuOption = "Account"
+	MExit      MenuOption = "Exit"
 )
 
 type commandOption string
@@ -33,16 +38,30 @@ const (
 	cGet    commandOption = "Get a single item by ID"
 	cGetAll commandOption = "Get the list of items"
 	cSave   commandOption = "Store new item"
+	cUpdate commandOption = "Update the existing item"
 	cDelete commandOption = "Delete the existing item"
+	cShares commandOption = "Get the list of the item shares"
+	cShare  commandOption = "Share the item with another user"
+	cSeal   commandOption = "Share the item end-to-end encrypted"
+	cLink   commandOption = "Create a one-time link to the item"
+	cRevoke commandOption = "Revoke the item share"
 	cBack   commandOption = "Back to main menu"
 )
 
 var (
-	MenuList     = []MenuOption{MBinary, MCard, MPassword, MText, MExit}
-	commandList  = []commandOption{cGet, cGetAll, cSave, cDelete, cBack}
-	commonHeader = []string{"ID", "Name", "Data", "Note"}
+	commandList = []commandOption{cGet, cGetAll, cSave, cUpdate, cDelete, cShares, cShare, cSeal, cLink, cRevoke, cBack}
 )
 
+// MenuList returns the main menu options: the registered item types followed by the rest of the sections.
+func MenuList() []MenuOption {
+	all := items.All()
+	opts := make([]MenuOption, 0, len(all)+5)
+	for _, t := range all {
+		opts = append(opts, MenuOption(t.Title()))
+	}
+	return append(opts, MTemplate, MOrg, MEmergency, MAccount, MExit)
+}
+
 func getOptionsMenu(opt MenuOption) (commandOption, error) {
 	mp := promptui.Select{
 		Label: fmt.Sprintf("What would you like to do with %s?", strings.ToLower(string(opt))),
@@ -66,8 +85,20 @@ func showMenu(v viewer, opt MenuOption) error {
 		err = v.getItems()
 	case cSave:
 		err = v.saveItem()
+	case cUpdate:
+		err = v.updateItem()
 	case cDelete:
 		err = v.deleteItem()
+	case cShares:
+		err = getShares(v.getShareClient())
+	case cShare:
+		err = shareItem(v.getShareClient())
+	case cSeal:
+		err = sealItem(v.getShareClient())
+	case cLink:
+		err = createItemLink(v.getShareClient())
+	case cRevoke:
+		err = revokeShare(v.getShareClient())
 	case cBack:
 		return nil
 	}
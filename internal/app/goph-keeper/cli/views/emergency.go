@@ -0,0 +1,229 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+type Emergency struct {
+	keeper client.EmergencyClient
+}
+
+type emergencyOption string
+
+const (
+	eVault   emergencyOption = "Switch to the vault shared by the emergency access"
+	eGetAll  emergencyOption = "Get the list of emergency contacts"
+	eAdd     emergencyOption = "Designate a trusted contact"
+	eRemove  emergencyOption = "Remove an emergency contact"
+	eRequest emergencyOption = "Request the emergency access"
+	eApprove emergencyOption = "Approve the access request"
+	eReject  emergencyOption = "Reject the access request or revoke the access"
+	eEvents  emergencyOption = "Get the emergency access history"
+	eBack    emergencyOption = emergencyOption(cBack)
+)
+
+const ownVault = "Own vault"
+
+var (
+	ErrNoEmergencyVault = errors.New("the selected vault is not available")
+
+	emergencyCommandList = []emergencyOption{
+		eVault, eGetAll, eAdd, eRemove, eRequest, eApprove, eReject, eEvents, eBack,
+	}
+	contactHeader = []string{"ID", "Owner", "Contact", "Wait", "Status", "Grants"}
+	eventHeader   = []string{"Time", "Owner", "Contact", "Action"}
+)
+
+func NewEmergencyView(keeper client.EmergencyClient) *Emergency {
+	return &Emergency{keeper: keeper}
+}
+
+func (v *Emergency) ShowMenu() error {
+	mp := promptui.Select{
+		Label: "What would you like to do with the emergency access?",
+		Items: emergencyCommandList,
+	}
+
+	_, res, err := mp.Run()
+	if err != nil {
+		return err
+	}
+
+	switch emergencyOption(res) {
+	case eVault:
+		err = v.switchVault()
+	case eGetAll:
+		err = v.getContacts()
+	case eAdd:
+		err = v.addContact()
+	case eRemove:
+		err = v.removeContact()
+	case eRequest:
+		err = v.changeStatus(v.keeper.RequestEmergencyAccess)
+	case eApprove:
+		err = v.changeStatus(v.keeper.ApproveEmergencyRequest)
+	case eReject:
+		err = v.changeStatus(v.keeper.RejectEmergencyRequest)
+	case eEvents:
+		err = v.getEvents()
+	case eBack:
+		return nil
+	}
+
+	if err != nil {
+		log.Error(err)
+	}
+	return v.ShowMenu()
+}
+
+// switchVault lists the user's own vault along with the vaults of the owners who granted the access,
+// and switches the storage requests to the selected one.
+func (v *Emergency) switchVault() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	contacts, err := v.keeper.GetEmergencyContacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := []string{ownVault}
+	vaults := map[string]string{ownVault: ""}
+	for _, c := range contacts {
+		if c.Status != "granted" {
+			continue
+		}
+		name := fmt.Sprintf("%s's vault (granted to %s)", c.Owner, c.Grantee)
+		items = append(items, name)
+		vaults[name] = c.ID
+	}
+
+	sp := promptui.Select{Label: "Select the vault to work with", Items: items}
+	_, res, err := sp.Run()
+	if err != nil {
+		return err
+	}
+
+	id, ok := vaults[res]
+	if !ok {
+		return ErrNoEmergencyVault
+	}
+	v.keeper.UseEmergencyAccess(id)
+	fmt.Printf("Switched to %s. The vault is read-only.\n", res)
+	return nil
+}
+
+func (v *Emergency) getContacts() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	contacts, err := v.keeper.GetEmergencyContacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(contactHeader)
+	for _, c := range contacts {
+		table.Append(c.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Emergency) addContact() error {
+	user, err := inputs.EmergencyContactUser()
+	if err != nil {
+		return err
+	}
+	wait, err := inputs.EmergencyWait()
+	if err != nil {
+		return err
+	}
+	hours, err := strconv.Atoi(wait)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	c, err := v.keeper.AddEmergencyContact(ctx, user, hours)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s can now request the emergency access. The ID is %s.\n", c.Grantee, c.ID)
+	return nil
+}
+
+func (v *Emergency) removeContact() error {
+	id, err := inputs.EmergencyContactID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.RemoveEmergencyContact(ctx, id); err != nil {
+		return err
+	}
+	if v.keeper.GetEmergencyAccess() == id {
+		v.keeper.UseEmergencyAccess("")
+	}
+	fmt.Println("The emergency contact has been removed successfully.")
+	return nil
+}
+
+func (v *Emergency) getEvents() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	events, err := v.keeper.GetEmergencyEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(eventHeader)
+	for _, e := range events {
+		table.Append(e.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Emergency) changeStatus(
+	change func(ctx context.Context, id string) (models.EmergencyContactResponse, error),
+) error {
+	id, err := inputs.EmergencyContactID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	c, err := change(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(contactHeader)
+	table.Append(c.TableRow())
+	table.Render()
+	return nil
+}
@@ -0,0 +1,287 @@
+package views
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// ItemClient manages the items, and lists the templates the custom items are created with.
+type ItemClient interface {
+	client.ItemClient
+	GetTemplates(ctx context.Context) ([]models.TemplateResponse, error)
+}
+
+// Item manages the items of the single type. The prompts and the table columns are derived from the type fields.
+type Item struct {
+	keeper ItemClient
+	t      items.Type
+}
+
+func NewItemView(keeper ItemClient, t items.Type) *Item {
+	return &Item{keeper: keeper, t: t}
+}
+
+func (v *Item) ShowMenu() error {
+	return showMenu(v, MenuOption(v.t.Title()))
+}
+
+func (v *Item) getItem() error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	data, err := v.keeper.GetItemByID(ctx, v.t.Name, id)
+	if err != nil {
+		return err
+	}
+
+	v.showItems([]models.ItemResponse{data})
+	return nil
+}
+
+func (v *Item) getItems() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	data, err := v.keeper.GetAllItems(ctx, v.t.Name)
+	if err != nil {
+		return err
+	}
+
+	// The hidden values are shown with the single item only.
+	for i := range data {
+		data[i].Fields = v.t.Mask(data[i].Fields)
+		data[i].Custom = items.MaskCustom(data[i].Custom)
+	}
+	v.showItems(data)
+	return nil
+}
+
+func (v *Item) saveItem() error {
+	req, err := v.readItem()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	_, err = v.keeper.StoreItem(ctx, v.t.Name, req)
+	return err
+}
+
+func (v *Item) updateItem() error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	req, err := v.readItem()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.UpdateItem(ctx, v.t.Name, id, req); err != nil {
+		return err
+	}
+	fmt.Printf("%s item has been updated successfully.\n", v.typeName())
+	return nil
+}
+
+func (v *Item) deleteItem() error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteItem(ctx, v.t.Name, id); err != nil {
+		return err
+	}
+	fmt.Printf("%s item has been deleted successfully.\n", v.typeName())
+	return nil
+}
+
+func (v *Item) getShareClient() (client.ShareClient, string) {
+	return v.keeper, client.ItemPath(v.t.Name)
+}
+
+// readItem prompts for the fields of the type in their order, skipping the derived ones.
+// The file fields are read from the entered path.
+// The fields of the selected template are prompted next, and the extra custom fields are added on request.
+func (v *Item) readItem() (models.ItemRequest, error) {
+	req := models.ItemRequest{Fields: make(map[string]string, len(v.t.Fields))}
+	var tmpl *models.TemplateResponse
+	for _, f := range v.t.Fields {
+		if f.Derived {
+			continue
+		}
+
+		var (
+			value string
+			err   error
+		)
+		switch {
+		case f.Kind == items.KindFile:
+			value, err = readFile()
+		case f.Kind == items.KindMultiline:
+			value, err = inputs.ItemMultiline(f)
+		case f.Name == items.FieldTemplate:
+			if tmpl, err = v.selectTemplate(); tmpl != nil {
+				value = tmpl.Name
+			}
+		default:
+			value, err = inputs.ItemField(f)
+		}
+		if err != nil {
+			return req, err
+		}
+		req.Fields[f.Name] = value
+	}
+
+	// The values are checked together before sending, so the ones not matching each other are reported with the reason.
+	fields, err := v.t.Normalize(req.Fields)
+	if err == nil {
+		err = v.t.Validate(fields, nil)
+	}
+	if err != nil {
+		return req, err
+	}
+
+	if tmpl != nil {
+		for _, tf := range tmpl.Fields {
+			value, err := readCustomValue(tf.Field())
+			if err != nil {
+				return req, err
+			}
+			req.Custom = append(req.Custom, items.CustomField{Name: tf.Name, Kind: tf.Kind, Value: value})
+		}
+	}
+
+	custom, err := readCustomFields()
+	if err != nil {
+		return req, err
+	}
+	req.Custom = append(req.Custom, custom...)
+	return req, nil
+}
+
+// selectTemplate prompts for the user's template, if the user has any.
+func (v *Item) selectTemplate() (*models.TemplateResponse, error) {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	templates, err := v.keeper.GetTemplates(ctx)
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, t := range templates {
+		names = append(names, t.Name)
+	}
+	name, err := inputs.TemplateSelect(names)
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// showItems prints the items of the type. The custom fields are printed in the extra column, if any item has them.
+func (v *Item) showItems(data []models.ItemResponse) {
+	header := v.t.Header()
+	custom := false
+	for _, item := range data {
+		custom = custom || len(item.Custom) > 0
+	}
+	if custom {
+		header = append(header, "Fields")
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	for _, item := range data {
+		row := item.TableRow(v.t)
+		if custom {
+			row = append(row, item.CustomColumn())
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+func (v *Item) typeName() string {
+	return strings.ToUpper(v.t.Name[:1]) + v.t.Name[1:]
+}
+
+// readCustomFields prompts for the extra custom fields until the user declines to add more.
+func readCustomFields() ([]items.CustomField, error) {
+	var fields []items.CustomField
+	for {
+		add, err := inputs.CustomFieldAdd()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(strings.ToLower(add), "y") {
+			return fields, nil
+		}
+
+		name, err := inputs.CustomFieldName()
+		if err != nil {
+			return nil, err
+		}
+		kind, err := inputs.FieldKind()
+		if err != nil {
+			return nil, err
+		}
+		value, err := readCustomValue(items.Field{Name: name, Label: name, Kind: kind})
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, items.CustomField{Name: name, Kind: kind, Value: value})
+	}
+}
+
+// readCustomValue prompts for the value of the custom field, reading the multiline values line by line.
+func readCustomValue(f items.Field) (string, error) {
+	if f.Kind == items.KindMultiline {
+		return inputs.ItemMultiline(f)
+	}
+	return inputs.ItemField(f)
+}
+
+func readFile() (string, error) {
+	path, err := inputs.FilePath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,120 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+var linkHeader = []string{"ID", "Name", "Views", "Created", "Expires"}
+
+func createItemLink(keeper client.ShareClient, storage string) error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+	views, expiresAt, err := getLinkLimits()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	link, err := keeper.CreateItemLink(ctx, storage, id, views, expiresAt)
+	if err != nil {
+		return err
+	}
+	printLink(link)
+	return nil
+}
+
+func createTextLink(keeper client.LinkClient) error {
+	text, err := inputs.ItemText()
+	if err != nil {
+		return err
+	}
+	views, expiresAt, err := getLinkLimits()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	link, err := keeper.CreateTextLink(ctx, text, views, expiresAt)
+	if err != nil {
+		return err
+	}
+	printLink(link)
+	return nil
+}
+
+func getLinks(keeper client.LinkClient) error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	links, err := keeper.GetLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(linkHeader)
+	for _, l := range links {
+		table.Append(l.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func revokeLink(keeper client.LinkClient) error {
+	id, err := inputs.LinkID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = keeper.DeleteLink(ctx, id); err != nil {
+		return err
+	}
+	fmt.Println("The link has been revoked successfully.")
+	return nil
+}
+
+func getLinkLimits() (int, time.Time, error) {
+	views, err := inputs.LinkViews()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	n, err := strconv.Atoi(views)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	expiry, err := inputs.LinkExpiry()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	hours, err := strconv.Atoi(expiry)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return n, time.Now().Add(time.Duration(hours) * time.Hour), nil
+}
+
+func printLink(link models.LinkResponse) {
+	fmt.Printf("The link has been created successfully. Copy it now, the key in it won't be shown again:\n%s\n",
+		link.URL)
+	fmt.Printf("The link can be opened %d time(s) until %s.\n", link.MaxViews,
+		link.ExpiresAt.Local().Format(time.RFC822))
+}
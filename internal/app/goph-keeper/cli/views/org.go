@@ -0,0 +1,327 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+)
+
+type Organization struct {
+	keeper client.OrgClient
+}
+
+type orgOption string
+
+const (
+	oVault       orgOption = "Switch between the personal and team vaults"
+	oGetAll      orgOption = "Get the list of organizations"
+	oCreate      orgOption = "Create an organization"
+	oDelete      orgOption = "Delete an organization"
+	oMembers     orgOption = "Get the list of the organization members"
+	oSetMember   orgOption = "Add a member or change the member role"
+	oRemove      orgOption = "Remove a member"
+	oCollections orgOption = "Get the list of the organization collections"
+	oCreateC     orgOption = "Create a collection"
+	oDeleteC     orgOption = "Delete a collection"
+	oBack        orgOption = orgOption(cBack)
+)
+
+const personalVault = "Personal vault"
+
+var (
+	ErrNoVault = errors.New("the selected vault is not available")
+
+	orgCommandList = []orgOption{
+		oVault, oGetAll, oCreate, oDelete, oMembers, oSetMember, oRemove, oCollections, oCreateC, oDeleteC, oBack,
+	}
+	orgHeader        = []string{"ID", "Name", "Role", "Created"}
+	memberHeader     = []string{"User", "Role", "Added"}
+	collectionHeader = []string{"ID", "Name", "Created"}
+)
+
+func NewOrgView(keeper client.OrgClient) *Organization {
+	return &Organization{keeper: keeper}
+}
+
+func (v *Organization) ShowMenu() error {
+	mp := promptui.Select{
+		Label: "What would you like to do with the organizations?",
+		Items: orgCommandList,
+	}
+
+	_, res, err := mp.Run()
+	if err != nil {
+		return err
+	}
+
+	switch orgOption(res) {
+	case oVault:
+		err = v.switchVault()
+	case oGetAll:
+		err = v.getOrganizations()
+	case oCreate:
+		err = v.createOrganization()
+	case oDelete:
+		err = v.deleteOrganization()
+	case oMembers:
+		err = v.getMembers()
+	case oSetMember:
+		err = v.setMember()
+	case oRemove:
+		err = v.removeMember()
+	case oCollections:
+		err = v.getCollections()
+	case oCreateC:
+		err = v.createCollection()
+	case oDeleteC:
+		err = v.deleteCollection()
+	case oBack:
+		return nil
+	}
+
+	if err != nil {
+		log.Error(err)
+	}
+	return v.ShowMenu()
+}
+
+// switchVault lists the personal vault along with the collections of all the user's organizations,
+// and switches the storage requests to the selected one.
+func (v *Organization) switchVault() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	orgs, err := v.keeper.GetOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := []string{personalVault}
+	vaults := map[string]string{personalVault: ""}
+	for _, o := range orgs {
+		cs, cErr := v.keeper.GetCollections(ctx, o.ID)
+		if cErr != nil {
+			return cErr
+		}
+		for _, c := range cs {
+			name := fmt.Sprintf("%s / %s (%s)", o.Name, c.Name, o.Role)
+			items = append(items, name)
+			vaults[name] = c.ID
+		}
+	}
+
+	sp := promptui.Select{Label: "Select the vault to work with", Items: items}
+	_, res, err := sp.Run()
+	if err != nil {
+		return err
+	}
+
+	id, ok := vaults[res]
+	if !ok {
+		return ErrNoVault
+	}
+	v.keeper.UseVault(id)
+	fmt.Printf("Switched to %s.\n", res)
+	return nil
+}
+
+func (v *Organization) getOrganizations() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	orgs, err := v.keeper.GetOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(orgHeader)
+	for _, o := range orgs {
+		table.Append(o.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Organization) createOrganization() error {
+	name, err := inputs.OrgName()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	id, err := v.keeper.CreateOrganization(ctx, name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The organization has been created successfully. The ID is %s.\n", id)
+	return nil
+}
+
+func (v *Organization) deleteOrganization() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+	confirm, err := inputs.DeleteOrgConfirm()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.ToLower(confirm), "y") {
+		return nil
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteOrganization(ctx, id); err != nil {
+		return err
+	}
+	v.keeper.UseVault("")
+	fmt.Println("The organization has been deleted successfully. Switched to the personal vault.")
+	return nil
+}
+
+func (v *Organization) getMembers() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	members, err := v.keeper.GetMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(memberHeader)
+	for _, m := range members {
+		table.Append(m.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Organization) setMember() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+	user, err := inputs.MemberUser()
+	if err != nil {
+		return err
+	}
+	role, err := inputs.MemberRole()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.SetMember(ctx, id, user, role); err != nil {
+		return err
+	}
+	fmt.Println("The member has been saved successfully.")
+	return nil
+}
+
+func (v *Organization) removeMember() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+	user, err := inputs.MemberUser()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.RemoveMember(ctx, id, user); err != nil {
+		return err
+	}
+	fmt.Println("The member has been removed successfully.")
+	return nil
+}
+
+func (v *Organization) getCollections() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	collections, err := v.keeper.GetCollections(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(collectionHeader)
+	for _, c := range collections {
+		table.Append(c.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func (v *Organization) createCollection() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+	name, err := inputs.CollectionName()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	cid, err := v.keeper.CreateCollection(ctx, id, name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The collection has been created successfully. The ID is %s.\n", cid)
+	return nil
+}
+
+func (v *Organization) deleteCollection() error {
+	id, err := inputs.OrgID()
+	if err != nil {
+		return err
+	}
+	cid, err := inputs.CollectionID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteCollection(ctx, id, cid); err != nil {
+		return err
+	}
+	if v.keeper.GetVault() == cid {
+		v.keeper.UseVault("")
+	}
+	fmt.Println("The collection has been deleted successfully.")
+	return nil
+}
@@ -0,0 +1,142 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+)
+
+var (
+	shareHeader  = []string{"User", "Access", "Shared"}
+	sealedHeader = []string{"ID", "Owner", "Type", "Data", "Shared"}
+)
+
+func getShares(keeper client.ShareClient, storage string) error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	shares, err := keeper.GetShares(ctx, storage, id)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(shareHeader)
+	for _, s := range shares {
+		table.Append(s.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func shareItem(keeper client.ShareClient, storage string) error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	user, err := inputs.ShareUser()
+	if err != nil {
+		return err
+	}
+
+	readOnly, err := inputs.ShareReadOnly()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = keeper.ShareItem(ctx, storage, id, user, !strings.HasPrefix(strings.ToLower(readOnly), "n")); err != nil {
+		return err
+	}
+	fmt.Printf("The item has been shared with %s successfully.\n", user)
+	return nil
+}
+
+func sealItem(keeper client.ShareClient, storage string) error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	user, err := inputs.ShareUser()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = keeper.ShareItemSealed(ctx, storage, id, user); err != nil {
+		return err
+	}
+	fmt.Printf("The item has been sealed to %s successfully. Only %s can decrypt it.\n", user, user)
+	return nil
+}
+
+func getSealedItems(keeper client.SealedClient) error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	items, err := keeper.GetSealedItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(sealedHeader)
+	for _, item := range items {
+		table.Append(item.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+func deleteSealedItem(keeper client.SealedClient) error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = keeper.DeleteSealedItem(ctx, id); err != nil {
+		return err
+	}
+	fmt.Println("The sealed item has been deleted successfully.")
+	return nil
+}
+
+func revokeShare(keeper client.ShareClient, storage string) error {
+	id, err := inputs.ItemID()
+	if err != nil {
+		return err
+	}
+
+	user, err := inputs.ShareUser()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = keeper.RevokeShare(ctx, storage, id, user); err != nil {
+		return err
+	}
+	fmt.Printf("The item is no longer shared with %s.\n", user)
+	return nil
+}
@@ -0,0 +1,151 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/cli/inputs"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+type Template struct {
+	keeper client.TemplateClient
+}
+
+type templateOption string
+
+const (
+	tGetAll templateOption = "Get the list of templates"
+	tCreate templateOption = "Create a template"
+	tDelete templateOption = "Delete a template"
+	tBack   templateOption = templateOption(cBack)
+)
+
+var (
+	templateCommandList = []templateOption{tGetAll, tCreate, tDelete, tBack}
+	templateHeader      = []string{"ID", "Name", "Fields"}
+)
+
+func NewTemplateView(keeper client.TemplateClient) *Template {
+	return &Template{keeper: keeper}
+}
+
+func (v *Template) ShowMenu() error {
+	mp := promptui.Select{
+		Label: "What would you like to do with the templates of the custom items?",
+		Items: templateCommandList,
+	}
+
+	_, res, err := mp.Run()
+	if err != nil {
+		return err
+	}
+
+	switch templateOption(res) {
+	case tGetAll:
+		err = v.getTemplates()
+	case tCreate:
+		err = v.createTemplate()
+	case tDelete:
+		err = v.deleteTemplate()
+	case tBack:
+		return nil
+	}
+
+	if err != nil {
+		log.Error(err)
+	}
+	return v.ShowMenu()
+}
+
+func (v *Template) getTemplates() error {
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	templates, err := v.keeper.GetTemplates(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(templateHeader)
+	for _, t := range templates {
+		table.Append(t.TableRow())
+	}
+	table.Render()
+	return nil
+}
+
+// createTemplate prompts for the template name and its fields, until the user declines to add more fields.
+func (v *Template) createTemplate() error {
+	name, err := inputs.TemplateName()
+	if err != nil {
+		return err
+	}
+
+	req := models.TemplateRequest{Name: name}
+	for {
+		add, aErr := inputs.TemplateFieldAdd()
+		if aErr != nil {
+			return aErr
+		}
+		if strings.HasPrefix(strings.ToLower(add), "n") {
+			break
+		}
+
+		field, fErr := readTemplateField()
+		if fErr != nil {
+			return fErr
+		}
+		req.Fields = append(req.Fields, field)
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	id, err := v.keeper.CreateTemplate(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The template has been created successfully. The ID is %s.\n", id)
+	return nil
+}
+
+func (v *Template) deleteTemplate() error {
+	id, err := inputs.TemplateID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := getCtxTimeout()
+	defer cancel()
+
+	if err = v.keeper.DeleteTemplate(ctx, id); err != nil {
+		return err
+	}
+	fmt.Println("The template has been deleted successfully.")
+	return nil
+}
+
+func readTemplateField() (items.TemplateField, error) {
+	name, err := inputs.CustomFieldName()
+	if err != nil {
+		return items.TemplateField{}, err
+	}
+	kind, err := inputs.FieldKind()
+	if err != nil {
+		return items.TemplateField{}, err
+	}
+	required, err := inputs.TemplateFieldRequired()
+	if err != nil {
+		return items.TemplateField{}, err
+	}
+	return items.TemplateField{Name: name, Kind: kind, Required: strings.HasPrefix(strings.ToLower(required), "y")}, nil
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const batchPath = "/storage/batch"
+
+// ExecuteBatch executes the operations on the items of the current vault with the single request.
+// The results are returned in the order of the operations, the failed operations don't fail the request.
+func (c HTTPKeeperClient) ExecuteBatch(ctx context.Context, req models.BatchRequest) (models.BatchResponse, error) {
+	var batch models.BatchResponse
+	res, err := c.makeRequest(ctx, http.MethodPost, batchPath, req)
+	if err != nil {
+		return batch, err
+	}
+	defer closeResponseBody(res.Body)
+
+	err = json.NewDecoder(res.Body).Decode(&batch)
+	return batch, err
+}
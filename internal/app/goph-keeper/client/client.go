@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"time"
 
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/client/config"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
@@ -11,50 +12,116 @@ import (
 
 type KeeperClientConfig interface {
 	GetAPIAddress() string
+	GetAPIToken() string
 	GetCACertPool() (*x509.CertPool, error)
 	GetCertificate() (tls.Certificate, error)
 }
 
 type KeeperClient interface {
+	AccountClient
 	AuthClient
-	BinaryClient
-	CardClient
-	PasswordClient
-	TextClient
+	BatchClient
+	EmergencyClient
+	ItemClient
+	LinkClient
+	OrgClient
+	SealedClient
+	ShareClient
+	TemplateClient
+}
+
+type AccountClient interface {
+	ChangeName(ctx context.Context, name string) error
+	ChangePassword(ctx context.Context, password, newPassword string) error
+	CreateToken(ctx context.Context, name string, scope models.TokenScope, expiresAt time.Time) (models.APITokenResponse, error)
+	DeleteAccount(ctx context.Context, password string) error
+	DeleteToken(ctx context.Context, id string) error
+	ExportVault(ctx context.Context, password string) ([]byte, error)
+	GetTokens(ctx context.Context) ([]models.APITokenResponse, error)
+	ImportVault(ctx context.Context, archive []byte, password, duplicates string) (models.VaultImportResponse, error)
 }
 
 type AuthClient interface {
+	DeleteSession(ctx context.Context, cid string) error
+	GetSessions(ctx context.Context) ([]models.SessionResponse, error)
+	IsTokenAuthorized() bool
 	Login(ctx context.Context, user, password string) error
+	LoginOIDC(ctx context.Context, open func(url string)) error
 	Logout(ctx context.Context) error
 	Register(ctx context.Context, user, password string) error
 }
 
-type BinaryClient interface {
-	DeleteBinary(ctx context.Context, id string) error
-	GetAllBinaries(ctx context.Context) ([]models.BinaryResponse, error)
-	GetBinaryByID(ctx context.Context, id string) (models.BinaryResponse, error)
-	StoreBinary(ctx context.Context, name string, data []byte, note string) (string, error)
+type BatchClient interface {
+	ExecuteBatch(ctx context.Context, req models.BatchRequest) (models.BatchResponse, error)
+}
+
+// EmergencyClient manages the emergency contacts, and switches the storage requests to the vault of the owner
+// who granted the emergency access.
+type EmergencyClient interface {
+	AddEmergencyContact(ctx context.Context, user string, waitHours int) (models.EmergencyContactResponse, error)
+	ApproveEmergencyRequest(ctx context.Context, id string) (models.EmergencyContactResponse, error)
+	GetEmergencyAccess() string
+	GetEmergencyContacts(ctx context.Context) ([]models.EmergencyContactResponse, error)
+	GetEmergencyEvents(ctx context.Context) ([]models.EmergencyEventResponse, error)
+	RejectEmergencyRequest(ctx context.Context, id string) (models.EmergencyContactResponse, error)
+	RemoveEmergencyContact(ctx context.Context, id string) error
+	RequestEmergencyAccess(ctx context.Context, id string) (models.EmergencyContactResponse, error)
+	UseEmergencyAccess(id string)
+}
+
+// ItemClient manages the items of the type with the specified name, e.g. "card".
+type ItemClient interface {
+	ShareClient
+	DeleteItem(ctx context.Context, name, id string) error
+	GetAllItems(ctx context.Context, name string) ([]models.ItemResponse, error)
+	GetItemByID(ctx context.Context, name, id string) (models.ItemResponse, error)
+	StoreItem(ctx context.Context, name string, req models.ItemRequest) (string, error)
+	UpdateItem(ctx context.Context, name, id string, req models.ItemRequest) error
+}
+
+// LinkClient manages the one-time links to the secrets, and opens the links received from other users.
+type LinkClient interface {
+	CreateTextLink(ctx context.Context, text string, maxViews int, expiresAt time.Time) (models.LinkResponse, error)
+	DeleteLink(ctx context.Context, id string) error
+	GetLinks(ctx context.Context) ([]models.LinkResponse, error)
+	OpenLink(ctx context.Context, link string) (models.LinkSecretResponse, error)
+}
+
+// OrgClient manages the organizations, and switches the storage requests between the personal and team vaults.
+type OrgClient interface {
+	CreateCollection(ctx context.Context, orgID, name string) (string, error)
+	CreateOrganization(ctx context.Context, name string) (string, error)
+	DeleteCollection(ctx context.Context, orgID, id string) error
+	DeleteOrganization(ctx context.Context, id string) error
+	GetCollections(ctx context.Context, orgID string) ([]models.CollectionResponse, error)
+	GetMembers(ctx context.Context, orgID string) ([]models.MemberResponse, error)
+	GetOrganizations(ctx context.Context) ([]models.OrgResponse, error)
+	GetVault() string
+	RemoveMember(ctx context.Context, orgID, user string) error
+	SetMember(ctx context.Context, orgID, user, role string) error
+	UseVault(id string)
 }
 
-type CardClient interface {
-	DeleteCard(ctx context.Context, id string) error
-	GetAllCards(ctx context.Context) ([]models.CardResponse, error)
-	GetCardByID(ctx context.Context, id string) (models.CardResponse, error)
-	StoreCard(ctx context.Context, name, number, holder, expDate, cvv, note string) (string, error)
+// ShareClient manages the shares of the items stored at the specified storage path, e.g. ItemPath("password").
+type ShareClient interface {
+	GetShares(ctx context.Context, storage, id string) ([]models.ShareResponse, error)
+	RevokeShare(ctx context.Context, storage, id, user string) error
+	CreateItemLink(ctx context.Context, storage, id string, maxViews int, expiresAt time.Time) (models.LinkResponse, error)
+	ShareItem(ctx context.Context, storage, id, user string, readOnly bool) error
+	ShareItemSealed(ctx context.Context, storage, id, user string) error
 }
 
-type PasswordClient interface {
-	DeletePassword(ctx context.Context, id string) error
-	GetAllPasswords(ctx context.Context) ([]models.PasswordResponse, error)
-	GetPasswordByID(ctx context.Context, id string) (models.PasswordResponse, error)
-	StorePassword(ctx context.Context, name, user, password, note string) (string, error)
+// SealedClient reads the items sealed to the user's public key. The items are opened on the client only.
+type SealedClient interface {
+	DeleteSealedItem(ctx context.Context, id string) error
+	GetSealedItems(ctx context.Context) ([]models.SealedItemResponse, error)
 }
 
-type TextClient interface {
-	DeleteText(ctx context.Context, id string) error
-	GetAllTexts(ctx context.Context) ([]models.TextResponse, error)
-	GetTextByID(ctx context.Context, id string) (models.TextResponse, error)
-	StoreText(ctx context.Context, name, data, note string) (string, error)
+// TemplateClient manages the user's templates of the custom items.
+type TemplateClient interface {
+	CreateTemplate(ctx context.Context, req models.TemplateRequest) (string, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	GetTemplates(ctx context.Context) ([]models.TemplateResponse, error)
 }
 
 func NewClient(cfg *config.ClientConfig) (KeeperClient, error) {
@@ -18,6 +18,8 @@ type ClientConfig struct {
 		Port   int    `json:"port" yaml:"port" env:"API_PORT" envDefault:"8081"`
 		Route  string `json:"route" yaml:"route" env:"API_ROUTE" envDefault:"/api/v1"`
 		Secure bool   `json:"secure" yaml:"secure" env:"CLIENT_SECURE"`
+		Token  string `json:"token" yaml:"token" env:"API_TOKEN"`
+		SSO    bool   `json:"sso" yaml:"sso" env:"CLIENT_SSO"`
 	} `json:"api" yaml:"api"`
 	Cert struct {
 		CA   string `json:"ca" yaml:"ca" env:"CA_PATH"`
@@ -59,6 +61,14 @@ func (c *ClientConfig) GetAPIAddress() string {
 	return fmt.Sprintf("%s://%s:%d%s", protocol, c.API.Host, c.API.Port, c.API.Route)
 }
 
+func (c *ClientConfig) GetAPIToken() string {
+	return c.API.Token
+}
+
+func (c *ClientConfig) IsSSOEnabled() bool {
+	return c.API.SSO
+}
+
 func (c *ClientConfig) GetCACertPool() (*x509.CertPool, error) {
 	return cert.GetCertificatePool(c.Cert.Cert)
 }
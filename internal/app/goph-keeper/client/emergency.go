@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const emergencyPath = "/emergency/"
+
+// UseEmergencyAccess switches the storage requests to the vault of the owner who designated the user
+// as the emergency contact with the specified ID. The empty ID switches the requests back to the user's own vault.
+func (c HTTPKeeperClient) UseEmergencyAccess(id string) {
+	*c.vault, *c.emergency = "", id
+}
+
+// GetEmergencyAccess returns the ID of the emergency contact used by the storage requests, if any.
+func (c HTTPKeeperClient) GetEmergencyAccess() string {
+	return *c.emergency
+}
+
+func (c HTTPKeeperClient) AddEmergencyContact(ctx context.Context, user string,
+	waitHours int,
+) (models.EmergencyContactResponse, error) {
+	return c.sendEmergencyRequest(ctx, emergencyPath+"contacts/",
+		models.EmergencyContactRequest{User: user, WaitHours: waitHours})
+}
+
+func (c HTTPKeeperClient) ApproveEmergencyRequest(ctx context.Context,
+	id string,
+) (models.EmergencyContactResponse, error) {
+	return c.sendEmergencyRequest(ctx, emergencyPath+"contacts/"+url.PathEscape(id)+"/approve", nil)
+}
+
+func (c HTTPKeeperClient) GetEmergencyContacts(ctx context.Context) ([]models.EmergencyContactResponse, error) {
+	body, err := c.getAllData(ctx, emergencyPath+"contacts/")
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var contacts []models.EmergencyContactResponse
+	err = json.NewDecoder(body).Decode(&contacts)
+	return contacts, err
+}
+
+func (c HTTPKeeperClient) GetEmergencyEvents(ctx context.Context) ([]models.EmergencyEventResponse, error) {
+	body, err := c.getAllData(ctx, emergencyPath+"events")
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var events []models.EmergencyEventResponse
+	err = json.NewDecoder(body).Decode(&events)
+	return events, err
+}
+
+func (c HTTPKeeperClient) RejectEmergencyRequest(ctx context.Context,
+	id string,
+) (models.EmergencyContactResponse, error) {
+	return c.sendEmergencyRequest(ctx, emergencyPath+"contacts/"+url.PathEscape(id)+"/reject", nil)
+}
+
+func (c HTTPKeeperClient) RemoveEmergencyContact(ctx context.Context, id string) error {
+	return c.deleteData(ctx, emergencyPath+"contacts/", url.PathEscape(id))
+}
+
+func (c HTTPKeeperClient) RequestEmergencyAccess(ctx context.Context,
+	id string,
+) (models.EmergencyContactResponse, error) {
+	return c.sendEmergencyRequest(ctx, emergencyPath+"contacts/"+url.PathEscape(id)+"/request", nil)
+}
+
+func (c HTTPKeeperClient) sendEmergencyRequest(ctx context.Context, url string,
+	data any,
+) (models.EmergencyContactResponse, error) {
+	res, err := c.makeRequest(ctx, http.MethodPost, url, data)
+	if err != nil {
+		return models.EmergencyContactResponse{}, err
+	}
+	defer closeResponseBody(res.Body)
+
+	var contact models.EmergencyContactResponse
+	err = json.NewDecoder(res.Body).Decode(&contact)
+	return contact, err
+}
@@ -10,6 +10,9 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"os"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -18,17 +21,14 @@ import (
 )
 
 type HTTPKeeperClient struct {
-	http   *http.Client
-	apiURL *url.URL
+	http      *http.Client
+	apiURL    *url.URL
+	token     string
+	vault     *string
+	emergency *string
+	keys      *keyRing
 }
 
-const (
-	SBinary   string = "/storage/binary/"
-	SCard     string = "/storage/card/"
-	SPassword string = "/storage/password/"
-	SText     string = "/storage/text/"
-)
-
 var ErrUnauthorized = errors.New("incorrect username or password")
 
 func NewHTTPClient(cfg *config.ClientConfig) (HTTPKeeperClient, error) {
@@ -63,200 +63,204 @@ func NewHTTPClient(cfg *config.ClientConfig) (HTTPKeeperClient, error) {
 				},
 			},
 		},
-		apiURL: uri,
+		apiURL:    uri,
+		token:     cfg.GetAPIToken(),
+		vault:     new(string),
+		emergency: new(string),
+		keys:      &keyRing{},
 	}, nil
 }
 
-func (c HTTPKeeperClient) Login(ctx context.Context, user, password string) error {
-	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/login", models.UserRequest{
-		Name:     user,
-		Password: password,
-	})
+func (c HTTPKeeperClient) ChangeName(ctx context.Context, name string) error {
+	res, err := c.makeRequest(ctx, http.MethodPut, "/account/name", models.NameChangeRequest{Name: name})
 	if err != nil {
-		if res.StatusCode == http.StatusUnauthorized {
-			return ErrUnauthorized
-		}
 		return err
 	}
 	defer closeResponseBody(res.Body)
-
-	c.http.Jar.SetCookies(c.apiURL, res.Cookies())
 	return nil
 }
 
-func (c HTTPKeeperClient) Logout(ctx context.Context) error {
-	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/logout", nil)
+// ChangePassword replaces the password along with the private key encrypted with it.
+// The password is left unchanged if the private key can't be decrypted with the current password.
+func (c HTTPKeeperClient) ChangePassword(ctx context.Context, password, newPassword string) error {
+	keys, encrypted, err := c.rewrapKeys(ctx, password, newPassword)
 	if err != nil {
 		return err
 	}
+
+	res, err := c.makeRequest(ctx, http.MethodPut, "/account/password", models.PasswordChangeRequest{
+		Password:    password,
+		NewPassword: newPassword,
+		PrivateKey:  encrypted,
+	})
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusUnauthorized {
+			return ErrUnauthorized
+		}
+		return err
+	}
 	defer closeResponseBody(res.Body)
 
-	c.http.Jar.SetCookies(c.apiURL, nil)
+	if encrypted != nil {
+		*c.keys = keys
+	}
 	return nil
 }
 
-func (c HTTPKeeperClient) Register(ctx context.Context, user, password string) error {
-	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/register", models.UserRequest{
-		Name:     user,
-		Password: password,
+func (c HTTPKeeperClient) CreateToken(ctx context.Context, name string, scope models.TokenScope,
+	expiresAt time.Time,
+) (models.APITokenResponse, error) {
+	var token models.APITokenResponse
+	res, err := c.makeRequest(ctx, http.MethodPost, "/account/tokens/", models.APITokenRequest{
+		Name:      name,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
 	})
 	if err != nil {
-		return err
+		return token, err
 	}
 	defer closeResponseBody(res.Body)
-	return nil
+
+	err = json.NewDecoder(res.Body).Decode(&token)
+	return token, err
 }
 
-func (c HTTPKeeperClient) DeleteBinary(ctx context.Context, id string) error {
-	return c.deleteData(ctx, SBinary, id)
+func (c HTTPKeeperClient) DeleteToken(ctx context.Context, id string) error {
+	return c.deleteData(ctx, "/account/tokens/", id)
 }
 
-func (c HTTPKeeperClient) GetAllBinaries(ctx context.Context) ([]models.BinaryResponse, error) {
-	body, err := c.getAllData(ctx, SBinary)
+func (c HTTPKeeperClient) GetTokens(ctx context.Context) ([]models.APITokenResponse, error) {
+	body, err := c.getAllData(ctx, "/account/tokens/")
 	if err != nil {
 		return nil, err
 	}
 	defer closeResponseBody(body)
 
-	var bins []models.BinaryResponse
-	err = json.NewDecoder(body).Decode(&bins)
-	return bins, err
+	var tokens []models.APITokenResponse
+	err = json.NewDecoder(body).Decode(&tokens)
+	return tokens, err
 }
 
-func (c HTTPKeeperClient) GetBinaryByID(ctx context.Context, id string) (models.BinaryResponse, error) {
-	var data models.BinaryResponse
-	body, err := c.getDataByID(ctx, SBinary, id)
+func (c HTTPKeeperClient) IsTokenAuthorized() bool {
+	return c.token != ""
+}
+
+func (c HTTPKeeperClient) DeleteAccount(ctx context.Context, password string) error {
+	res, err := c.makeRequest(ctx, http.MethodDelete, "/account/", models.AccountDeleteRequest{Password: password})
 	if err != nil {
-		return data, err
+		if res != nil && res.StatusCode == http.StatusUnauthorized {
+			return ErrUnauthorized
+		}
+		return err
 	}
-	defer closeResponseBody(body)
-
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
-}
+	defer closeResponseBody(res.Body)
 
-func (c HTTPKeeperClient) StoreBinary(ctx context.Context, name string,
-	data []byte, note string,
-) (string, error) {
-	return c.storeData(ctx, SBinary, models.BinaryRequest{
-		Name: name,
-		Data: data,
-		Note: note,
-	})
+	c.http.Jar.SetCookies(c.apiURL, res.Cookies())
+	return nil
 }
 
-func (c HTTPKeeperClient) DeleteText(ctx context.Context, id string) error {
-	return c.deleteData(ctx, SText, id)
+func (c HTTPKeeperClient) DeleteSession(ctx context.Context, cid string) error {
+	res, err := c.makeRequest(ctx, http.MethodDelete, "/auth/sessions/"+cid, nil)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(res.Body)
+	return nil
 }
 
-func (c HTTPKeeperClient) GetAllTexts(ctx context.Context) ([]models.TextResponse, error) {
-	body, err := c.getAllData(ctx, SText)
+func (c HTTPKeeperClient) GetSessions(ctx context.Context) ([]models.SessionResponse, error) {
+	body, err := c.getAllData(ctx, "/auth/sessions")
 	if err != nil {
 		return nil, err
 	}
 	defer closeResponseBody(body)
 
-	var data []models.TextResponse
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
+	var sessions []models.SessionResponse
+	err = json.NewDecoder(body).Decode(&sessions)
+	return sessions, err
 }
 
-func (c HTTPKeeperClient) GetTextByID(ctx context.Context, id string) (models.TextResponse, error) {
-	var data models.TextResponse
-	body, err := c.getDataByID(ctx, SText, id)
+func (c HTTPKeeperClient) Login(ctx context.Context, user, password string) error {
+	device, err := os.Hostname()
 	if err != nil {
-		return data, err
+		log.Warn(err)
 	}
-	defer closeResponseBody(body)
-
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
-}
 
-func (c HTTPKeeperClient) StoreText(ctx context.Context, name, data, note string) (string, error) {
-	return c.storeData(ctx, SText, models.TextRequest{
-		Name: name,
-		Data: data,
-		Note: note,
+	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/login", models.UserRequest{
+		Name:     user,
+		Password: password,
+		Device:   device,
 	})
-}
-
-func (c HTTPKeeperClient) DeleteCard(ctx context.Context, id string) error {
-	return c.deleteData(ctx, SCard, id)
-}
-
-func (c HTTPKeeperClient) GetAllCards(ctx context.Context) ([]models.CardResponse, error) {
-	body, err := c.getAllData(ctx, SCard)
 	if err != nil {
-		return nil, err
+		if res.StatusCode == http.StatusUnauthorized {
+			return ErrUnauthorized
+		}
+		return err
 	}
-	defer closeResponseBody(body)
+	defer closeResponseBody(res.Body)
 
-	var data []models.CardResponse
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
+	c.http.Jar.SetCookies(c.apiURL, res.Cookies())
+	if err = c.unlockKeys(ctx, password); err != nil {
+		log.Warn(err)
+	}
+	return nil
 }
 
-func (c HTTPKeeperClient) GetCardByID(ctx context.Context, id string) (models.CardResponse, error) {
-	var data models.CardResponse
-	body, err := c.getDataByID(ctx, SCard, id)
+func (c HTTPKeeperClient) Logout(ctx context.Context) error {
+	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/logout", nil)
 	if err != nil {
-		return data, err
+		return err
 	}
-	defer closeResponseBody(body)
+	defer closeResponseBody(res.Body)
 
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
+	c.http.Jar.SetCookies(c.apiURL, nil)
+	*c.keys = keyRing{}
+	return nil
 }
 
-func (c HTTPKeeperClient) StoreCard(ctx context.Context,
-	name, number, holder, expDate, cvv, note string,
-) (string, error) {
-	return c.storeData(ctx, SCard, models.CardRequest{
-		Name:    name,
-		Number:  number,
-		Holder:  holder,
-		ExpDate: expDate,
-		CVV:     cvv,
-		Note:    note,
+func (c HTTPKeeperClient) Register(ctx context.Context, user, password string) error {
+	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/register", models.UserRequest{
+		Name:     user,
+		Password: password,
 	})
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(res.Body)
+	return nil
 }
 
-func (c HTTPKeeperClient) DeletePassword(ctx context.Context, id string) error {
-	return c.deleteData(ctx, SPassword, id)
-}
-
-func (c HTTPKeeperClient) GetAllPasswords(ctx context.Context) ([]models.PasswordResponse, error) {
-	body, err := c.getAllData(ctx, SPassword)
+func (c HTTPKeeperClient) GetShares(ctx context.Context, storage, id string) ([]models.ShareResponse, error) {
+	body, err := c.getAllData(ctx, storage+id+"/shares/")
 	if err != nil {
 		return nil, err
 	}
 	defer closeResponseBody(body)
 
-	var data []models.PasswordResponse
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
+	var shares []models.ShareResponse
+	err = json.NewDecoder(body).Decode(&shares)
+	return shares, err
 }
 
-func (c HTTPKeeperClient) GetPasswordByID(ctx context.Context, id string) (models.PasswordResponse, error) {
-	var data models.PasswordResponse
-	body, err := c.getDataByID(ctx, SPassword, id)
+func (c HTTPKeeperClient) RevokeShare(ctx context.Context, storage, id, user string) error {
+	res, err := c.makeRequest(ctx, http.MethodDelete, storage+id+"/shares/"+url.PathEscape(user), nil)
 	if err != nil {
-		return data, err
+		return err
 	}
-	defer closeResponseBody(body)
-
-	err = json.NewDecoder(body).Decode(&data)
-	return data, err
+	defer closeResponseBody(res.Body)
+	return nil
 }
 
-func (c HTTPKeeperClient) StorePassword(ctx context.Context, name, user, password, note string) (string, error) {
-	return c.storeData(ctx, SPassword, models.PasswordRequest{
-		Name:     name,
+func (c HTTPKeeperClient) ShareItem(ctx context.Context, storage, id, user string, readOnly bool) error {
+	res, err := c.makeRequest(ctx, http.MethodPost, storage+id+"/shares/", models.ShareRequest{
 		User:     user,
-		Password: password,
-		Note:     note,
+		ReadOnly: readOnly,
 	})
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(res.Body)
+	return nil
 }
 
 func (c HTTPKeeperClient) deleteData(ctx context.Context, url, id string) error {
@@ -294,6 +298,15 @@ func (c HTTPKeeperClient) storeData(ctx context.Context, url string, data any) (
 	return string(id), err
 }
 
+func (c HTTPKeeperClient) updateData(ctx context.Context, url, id string, data any) error {
+	res, err := c.makeRequest(ctx, http.MethodPut, url+id, data)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(res.Body)
+	return nil
+}
+
 func (c HTTPKeeperClient) makeRequest(ctx context.Context, method, url string, data any) (*http.Response, error) {
 	body, err := json.Marshal(data)
 	if err != nil {
@@ -309,6 +322,16 @@ func (c HTTPKeeperClient) makeRequest(ctx context.Context, method, url string, d
 	if err != nil {
 		return nil, err
 	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if strings.HasPrefix(url, "/storage/") {
+		if c.vault != nil && *c.vault != "" {
+			req.Header.Set(models.VaultHeader, *c.vault)
+		} else if c.emergency != nil && *c.emergency != "" {
+			req.Header.Set(models.EmergencyHeader, *c.emergency)
+		}
+	}
 
 	res, err := c.http.Do(req)
 	if err != nil {
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// ItemPath returns the storage path of the items of the type with the specified name, e.g. "/storage/card/".
+func ItemPath(name string) string {
+	return "/storage/" + name + "/"
+}
+
+func (c HTTPKeeperClient) DeleteItem(ctx context.Context, name, id string) error {
+	return c.deleteData(ctx, ItemPath(name), id)
+}
+
+func (c HTTPKeeperClient) GetAllItems(ctx context.Context, name string) ([]models.ItemResponse, error) {
+	body, err := c.getAllData(ctx, ItemPath(name))
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var data []models.ItemResponse
+	err = json.NewDecoder(body).Decode(&data)
+	return data, err
+}
+
+func (c HTTPKeeperClient) GetItemByID(ctx context.Context, name, id string) (models.ItemResponse, error) {
+	var data models.ItemResponse
+	body, err := c.getDataByID(ctx, ItemPath(name), id)
+	if err != nil {
+		return data, err
+	}
+	defer closeResponseBody(body)
+
+	err = json.NewDecoder(body).Decode(&data)
+	return data, err
+}
+
+func (c HTTPKeeperClient) StoreItem(ctx context.Context, name string, req models.ItemRequest) (string, error) {
+	return c.storeData(ctx, ItemPath(name), req)
+}
+
+func (c HTTPKeeperClient) UpdateItem(ctx context.Context, name, id string, req models.ItemRequest) error {
+	return c.updateData(ctx, ItemPath(name), id, req)
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+)
+
+const (
+	keysPath   = "/account/keys"
+	sealedPath = "/storage/sealed/"
+)
+
+var (
+	ErrKeysLocked     = errors.New("the keys are locked, please log in with the password")
+	ErrKeysUnreadable = errors.New("the private key can't be decrypted with the current password")
+)
+
+// keyRing holds the user's key pair unlocked with the password on login.
+type keyRing struct {
+	public  []byte
+	private []byte
+}
+
+// ShareItemSealed encrypts the copy of the item with the one-time key, and seals the key to the user's public key.
+// The server receives the opaque data only, the item can be opened by the recipient's private key.
+func (c HTTPKeeperClient) ShareItemSealed(ctx context.Context, storage, id, user string) error {
+	pub, err := c.getPublicKey(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.getDataByID(ctx, storage, id)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(body)
+
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	key, err := enc.GenerateKey()
+	if err != nil {
+		return err
+	}
+	data, err := enc.EncryptDataWithKey(key, payload)
+	if err != nil {
+		return err
+	}
+	sealed, err := enc.SealKey(pub, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.storeData(ctx, storage+id+"/shares/sealed", models.SealedShareRequest{
+		User: user,
+		Data: data,
+		Key:  sealed,
+	})
+	return err
+}
+
+// GetSealedItems returns the items sealed to the user, opened with the user's private key.
+// The returned Data holds the decrypted item.
+func (c HTTPKeeperClient) GetSealedItems(ctx context.Context) ([]models.SealedItemResponse, error) {
+	if c.keys.private == nil {
+		return nil, ErrKeysLocked
+	}
+
+	body, err := c.getAllData(ctx, sealedPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var items []models.SealedItemResponse
+	if err = json.NewDecoder(body).Decode(&items); err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		key, oErr := enc.OpenKey(c.keys.public, c.keys.private, item.Key)
+		if oErr != nil {
+			return nil, oErr
+		}
+		if items[i].Data, oErr = enc.DecryptDataWithKey(key, item.Data); oErr != nil {
+			return nil, oErr
+		}
+		items[i].Key = nil
+	}
+	return items, nil
+}
+
+func (c HTTPKeeperClient) DeleteSealedItem(ctx context.Context, id string) error {
+	return c.deleteData(ctx, sealedPath, url.PathEscape(id))
+}
+
+func (c HTTPKeeperClient) getPublicKey(ctx context.Context, user string) ([]byte, error) {
+	body, err := c.getAllData(ctx, "/users/"+url.PathEscape(user)+"/public-key")
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var key models.PublicKeyResponse
+	err = json.NewDecoder(body).Decode(&key)
+	return key.PublicKey, err
+}
+
+// unlockKeys decrypts the user's private key with the password.
+// If the user has no key pair yet, a new one is generated and stored encrypted with the password.
+func (c HTTPKeeperClient) unlockKeys(ctx context.Context, password string) error {
+	kp, found, err := c.getKeyPair(ctx)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return c.generateKeys(ctx, password)
+	}
+
+	priv, err := enc.DecryptPrivateKey(password, kp.PrivateKey)
+	if err != nil {
+		return err
+	}
+	c.keys.public, c.keys.private = kp.PublicKey, priv
+	return nil
+}
+
+// rewrapKeys decrypts the user's private key with the current password, and encrypts it with the new one.
+// The encrypted key is nil if the user has no key pair yet.
+func (c HTTPKeeperClient) rewrapKeys(ctx context.Context, password, newPassword string) (keyRing, []byte, error) {
+	kp, found, err := c.getKeyPair(ctx)
+	if err != nil || !found {
+		return keyRing{}, nil, err
+	}
+
+	priv, err := enc.DecryptPrivateKey(password, kp.PrivateKey)
+	if err != nil {
+		log.Error(err)
+		return keyRing{}, nil, ErrKeysUnreadable
+	}
+	encrypted, err := enc.EncryptPrivateKey(newPassword, priv)
+	if err != nil {
+		return keyRing{}, nil, err
+	}
+	return keyRing{public: kp.PublicKey, private: priv}, encrypted, nil
+}
+
+func (c HTTPKeeperClient) getKeyPair(ctx context.Context) (models.KeyPairResponse, bool, error) {
+	var kp models.KeyPairResponse
+	res, err := c.makeRequest(ctx, http.MethodGet, keysPath, nil)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			closeResponseBody(res.Body)
+			return kp, false, nil
+		}
+		return kp, false, err
+	}
+	defer closeResponseBody(res.Body)
+
+	err = json.NewDecoder(res.Body).Decode(&kp)
+	return kp, err == nil, err
+}
+
+func (c HTTPKeeperClient) generateKeys(ctx context.Context, password string) error {
+	pub, priv, err := enc.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	if err = c.storeKeys(ctx, password, pub, priv); err != nil {
+		return err
+	}
+	c.keys.public, c.keys.private = pub, priv
+	return nil
+}
+
+func (c HTTPKeeperClient) storeKeys(ctx context.Context, password string, pub, priv []byte) error {
+	encrypted, err := enc.EncryptPrivateKey(password, priv)
+	if err != nil {
+		return err
+	}
+	return c.updateData(ctx, keysPath, "", models.KeyPairRequest{PublicKey: pub, PrivateKey: encrypted})
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+)
+
+const linksPath = "/links/"
+
+var ErrInvalidLink = errors.New("the link is malformed or misses the decryption key")
+
+// CreateItemLink creates the one-time link to the item stored at the specified storage path, e.g. ItemPath("password").
+func (c HTTPKeeperClient) CreateItemLink(ctx context.Context, storage, id string, maxViews int,
+	expiresAt time.Time,
+) (models.LinkResponse, error) {
+	return c.createLink(ctx, models.LinkRequest{
+		Type:      strings.Trim(strings.TrimPrefix(storage, "/storage"), "/"),
+		ItemID:    id,
+		MaxViews:  maxViews,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// CreateTextLink creates the one-time link to the ad-hoc text that isn't stored in the vault.
+func (c HTTPKeeperClient) CreateTextLink(ctx context.Context, text string, maxViews int,
+	expiresAt time.Time,
+) (models.LinkResponse, error) {
+	return c.createLink(ctx, models.LinkRequest{Text: text, MaxViews: maxViews, ExpiresAt: expiresAt})
+}
+
+func (c HTTPKeeperClient) DeleteLink(ctx context.Context, id string) error {
+	return c.deleteData(ctx, linksPath, url.PathEscape(id))
+}
+
+func (c HTTPKeeperClient) GetLinks(ctx context.Context) ([]models.LinkResponse, error) {
+	body, err := c.getAllData(ctx, linksPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var links []models.LinkResponse
+	err = json.NewDecoder(body).Decode(&links)
+	return links, err
+}
+
+// OpenLink consumes the view of the link and decrypts the secret with the key from the link fragment.
+// The link is opened as is, so it works without the user being logged in.
+func (c HTTPKeeperClient) OpenLink(ctx context.Context, link string) (models.LinkSecretResponse, error) {
+	u, err := url.Parse(link)
+	if err != nil || u.Fragment == "" || path.Base(path.Dir(u.Path)) != strings.Trim(linksPath, "/") {
+		return models.LinkSecretResponse{}, ErrInvalidLink
+	}
+	key, err := base64.RawURLEncoding.DecodeString(u.Fragment)
+	if err != nil {
+		return models.LinkSecretResponse{}, ErrInvalidLink
+	}
+	u.Fragment = ""
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), http.NoBody)
+	if err != nil {
+		return models.LinkSecretResponse{}, err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return models.LinkSecretResponse{}, err
+	}
+	defer closeResponseBody(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return models.LinkSecretResponse{}, errors.New("response code")
+	}
+
+	var secret models.LinkSecretResponse
+	if err = json.NewDecoder(res.Body).Decode(&secret); err != nil {
+		return models.LinkSecretResponse{}, err
+	}
+	secret.Data, err = enc.DecryptDataWithKey(key, secret.Data)
+	return secret, err
+}
+
+func (c HTTPKeeperClient) createLink(ctx context.Context, req models.LinkRequest) (models.LinkResponse, error) {
+	var link models.LinkResponse
+	res, err := c.makeRequest(ctx, http.MethodPost, linksPath, req)
+	if err != nil {
+		return link, err
+	}
+	defer closeResponseBody(res.Body)
+
+	err = json.NewDecoder(res.Body).Decode(&link)
+	return link, err
+}
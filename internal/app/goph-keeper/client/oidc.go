@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const callbackPath = "/callback"
+
+var ErrSSODenied = errors.New("the identity provider has denied the login")
+
+type authRedirect struct {
+	code string
+	err  error
+}
+
+// LoginOIDC signs the user in through the identity provider using the loopback redirect.
+// The provider URL is passed to the open callback, so the user can visit it in the browser.
+// The method waits for the provider to redirect the browser back to the local listener until the context is done.
+func (c HTTPKeeperClient) LoginOIDC(ctx context.Context, open func(url string)) error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	res, err := c.makeRequest(ctx, http.MethodPost, "/auth/oidc/login", models.OIDCLoginRequest{
+		RedirectURI: fmt.Sprintf("http://%s%s", ln.Addr(), callbackPath),
+	})
+	if err != nil {
+		closeListener(ln)
+		return err
+	}
+
+	var login models.OIDCLoginResponse
+	err = json.NewDecoder(res.Body).Decode(&login)
+	closeResponseBody(res.Body)
+	if err != nil {
+		closeListener(ln)
+		return err
+	}
+
+	open(login.URL)
+	code, err := waitForRedirect(ctx, ln, login.State)
+	if err != nil {
+		return err
+	}
+
+	device, err := os.Hostname()
+	if err != nil {
+		log.Warn(err)
+	}
+
+	res, err = c.makeRequest(ctx, http.MethodPost, "/auth/oidc/callback", models.OIDCCallbackRequest{
+		State:  login.State,
+		Code:   code,
+		Device: device,
+	})
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusUnauthorized {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	defer closeResponseBody(res.Body)
+
+	c.http.Jar.SetCookies(c.apiURL, res.Cookies())
+	return nil
+}
+
+// waitForRedirect serves the single provider redirect on the listener and returns the authorization code.
+func waitForRedirect(ctx context.Context, ln net.Listener, state string) (string, error) {
+	redirects := make(chan authRedirect, 1)
+	srv := &http.Server{
+		ReadHeaderTimeout: 5 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if r.URL.Path != callbackPath || q.Get("state") != state {
+				http.NotFound(w, r)
+				return
+			}
+
+			res := authRedirect{code: q.Get("code")}
+			if res.code == "" {
+				res.err = fmt.Errorf("%w: %s", ErrSSODenied, q.Get("error"))
+			}
+			select {
+			case redirects <- res:
+			default:
+			}
+			_, _ = w.Write([]byte("The login is complete, you can close this window and return to the terminal."))
+		}),
+	}
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(err)
+		}
+	}()
+	defer shutdownServer(srv)
+
+	select {
+	case res := <-redirects:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func shutdownServer(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error(err)
+	}
+}
+
+func closeListener(ln net.Listener) {
+	if err := ln.Close(); err != nil {
+		log.Error(err)
+	}
+}
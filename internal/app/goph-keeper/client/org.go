@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const orgsPath = "/orgs/"
+
+// UseVault switches the storage requests to the organization collection with the specified ID.
+// The empty ID switches the requests back to the user's personal vault.
+func (c HTTPKeeperClient) UseVault(id string) {
+	*c.vault, *c.emergency = id, ""
+}
+
+// GetVault returns the ID of the organization collection used by the storage requests, if any.
+func (c HTTPKeeperClient) GetVault() string {
+	return *c.vault
+}
+
+func (c HTTPKeeperClient) CreateOrganization(ctx context.Context, name string) (string, error) {
+	return c.storeData(ctx, orgsPath, models.OrgRequest{Name: name})
+}
+
+func (c HTTPKeeperClient) DeleteOrganization(ctx context.Context, id string) error {
+	return c.deleteData(ctx, orgsPath, url.PathEscape(id))
+}
+
+func (c HTTPKeeperClient) GetOrganizations(ctx context.Context) ([]models.OrgResponse, error) {
+	body, err := c.getAllData(ctx, orgsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var orgs []models.OrgResponse
+	err = json.NewDecoder(body).Decode(&orgs)
+	return orgs, err
+}
+
+func (c HTTPKeeperClient) GetMembers(ctx context.Context, orgID string) ([]models.MemberResponse, error) {
+	body, err := c.getAllData(ctx, orgsPath+url.PathEscape(orgID)+"/members/")
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var members []models.MemberResponse
+	err = json.NewDecoder(body).Decode(&members)
+	return members, err
+}
+
+func (c HTTPKeeperClient) SetMember(ctx context.Context, orgID, user, role string) error {
+	res, err := c.makeRequest(ctx, http.MethodPost, orgsPath+url.PathEscape(orgID)+"/members/",
+		models.MemberRequest{User: user, Role: role})
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(res.Body)
+	return nil
+}
+
+func (c HTTPKeeperClient) RemoveMember(ctx context.Context, orgID, user string) error {
+	return c.deleteData(ctx, orgsPath+url.PathEscape(orgID)+"/members/", url.PathEscape(user))
+}
+
+func (c HTTPKeeperClient) CreateCollection(ctx context.Context, orgID, name string) (string, error) {
+	return c.storeData(ctx, orgsPath+url.PathEscape(orgID)+"/collections/", models.CollectionRequest{Name: name})
+}
+
+func (c HTTPKeeperClient) DeleteCollection(ctx context.Context, orgID, id string) error {
+	return c.deleteData(ctx, orgsPath+url.PathEscape(orgID)+"/collections/", url.PathEscape(id))
+}
+
+func (c HTTPKeeperClient) GetCollections(ctx context.Context, orgID string) ([]models.CollectionResponse, error) {
+	body, err := c.getAllData(ctx, orgsPath+url.PathEscape(orgID)+"/collections/")
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var collections []models.CollectionResponse
+	err = json.NewDecoder(body).Decode(&collections)
+	return collections, err
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const templatesPath = "/templates/"
+
+func (c HTTPKeeperClient) CreateTemplate(ctx context.Context, req models.TemplateRequest) (string, error) {
+	return c.storeData(ctx, templatesPath, req)
+}
+
+func (c HTTPKeeperClient) DeleteTemplate(ctx context.Context, id string) error {
+	return c.deleteData(ctx, templatesPath, id)
+}
+
+func (c HTTPKeeperClient) GetTemplates(ctx context.Context) ([]models.TemplateResponse, error) {
+	body, err := c.getAllData(ctx, templatesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(body)
+
+	var templates []models.TemplateResponse
+	err = json.NewDecoder(body).Decode(&templates)
+	return templates, err
+}
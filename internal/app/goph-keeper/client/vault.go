@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// ExportVault returns the archive of the user's personal vault, encrypted with the password.
+func (c HTTPKeeperClient) ExportVault(ctx context.Context, password string) ([]byte, error) {
+	res, err := c.makeRequest(ctx, http.MethodPost, "/account/export", models.VaultExportRequest{Password: password})
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(res.Body)
+
+	var vault models.VaultExportResponse
+	err = json.NewDecoder(res.Body).Decode(&vault)
+	return vault.Archive, err
+}
+
+// ImportVault merges the archive into the user's personal vault.
+// The duplicates policy is one of skip, replace or keep, the server skips the duplicates if it is empty.
+func (c HTTPKeeperClient) ImportVault(ctx context.Context, archive []byte, password,
+	duplicates string,
+) (models.VaultImportResponse, error) {
+	var sum models.VaultImportResponse
+	res, err := c.makeRequest(ctx, http.MethodPost, "/account/import", models.VaultImportRequest{
+		Archive:    archive,
+		Password:   password,
+		Duplicates: duplicates,
+	})
+	if err != nil {
+		return sum, err
+	}
+	defer closeResponseBody(res.Body)
+
+	err = json.NewDecoder(res.Body).Decode(&sum)
+	return sum, err
+}
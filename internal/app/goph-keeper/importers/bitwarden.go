@@ -0,0 +1,110 @@
+package importers
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// The Bitwarden item types.
+const (
+	bwTypeLogin = 1
+	bwTypeNote  = 2
+	bwTypeCard  = 3
+)
+
+type bwExport struct {
+	Encrypted bool     `json:"encrypted"`
+	Items     []bwItem `json:"items"`
+}
+
+type bwItem struct {
+	Type   int          `json:"type"`
+	Name   string       `json:"name"`
+	Notes  string       `json:"notes"`
+	Login  *bwLoginData `json:"login"`
+	Card   *bwCardData  `json:"card"`
+	Fields []bwField    `json:"fields"`
+}
+
+type bwLoginData struct {
+	Username string  `json:"username"`
+	Password string  `json:"password"`
+	TOTP     string  `json:"totp"`
+	URIs     []bwURI `json:"uris"`
+}
+
+type bwURI struct {
+	URI string `json:"uri"`
+}
+
+type bwCardData struct {
+	CardholderName string `json:"cardholderName"`
+	Number         string `json:"number"`
+	ExpMonth       string `json:"expMonth"`
+	ExpYear        string `json:"expYear"`
+	Code           string `json:"code"`
+}
+
+type bwField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// parseBitwarden maps the unencrypted JSON export of Bitwarden.
+// The logins become passwords, the cards become cards, and the secure notes become texts.
+// The URIs, TOTP secrets and custom fields missing in the items are appended to the notes.
+func parseBitwarden(r io.Reader) (Batch, error) {
+	var export bwExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return Batch{}, err
+	}
+	if export.Encrypted {
+		return Batch{}, ErrEncrypted
+	}
+
+	var b Batch
+	for i, it := range export.Items {
+		note := joinNote(it.Notes, it.fieldLabels()...)
+		switch {
+		case it.Type == bwTypeLogin && it.Login != nil:
+			uris := make([]string, 0, len(it.Login.URIs))
+			for _, u := range it.Login.URIs {
+				uris = append(uris, u.URI)
+			}
+			b.addPassword(i+1, models.ItemRequest{Fields: map[string]string{
+				"name":     it.Name,
+				"user":     it.Login.Username,
+				"password": it.Login.Password,
+				"note":     joinNote(note, "URL", strings.Join(uris, ", "), "TOTP", it.Login.TOTP),
+			}})
+		case it.Type == bwTypeCard && it.Card != nil:
+			b.addCard(i+1, models.ItemRequest{Fields: map[string]string{
+				"name":     it.Name,
+				"number":   strings.TrimSpace(it.Card.Number),
+				"holder":   strings.TrimSpace(it.Card.CardholderName),
+				"exp_date": getExpDate(it.Card.ExpMonth, it.Card.ExpYear),
+				"cvv":      strings.TrimSpace(it.Card.Code),
+				"note":     note,
+			}})
+		case it.Type == bwTypeNote:
+			b.addText(i+1, models.ItemRequest{Fields: map[string]string{
+				"name": it.Name, "data": it.Notes, "note": joinNote("", it.fieldLabels()...),
+			}})
+		default:
+			b.fail(i+1, "", getName(it.Name), ErrUnsupported)
+		}
+	}
+	return b, nil
+}
+
+// fieldLabels returns the custom fields as the pairs of the labels and values.
+func (it bwItem) fieldLabels() []string {
+	labeled := make([]string, 0, len(it.Fields)*2)
+	for _, f := range it.Fields {
+		labeled = append(labeled, f.Name, f.Value)
+	}
+	return labeled
+}
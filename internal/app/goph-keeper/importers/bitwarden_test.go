@@ -0,0 +1,72 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const bitwardenExport = `{
+  "encrypted": false,
+  "folders": [],
+  "items": [
+    {
+      "type": 1,
+      "name": "Example",
+      "notes": "note",
+      "fields": [{"name": "PIN", "value": "1234", "type": 1}],
+      "login": {
+        "username": "user",
+        "password": "secret",
+        "totp": "JBSWY3DPEHPK3PXP",
+        "uris": [{"match": null, "uri": "https://example.com"}, {"uri": "https://example.org"}]
+      }
+    },
+    {"type": 2, "name": "Note", "notes": "secret note", "secureNote": {"type": 0}},
+    {
+      "type": 3,
+      "name": "Visa",
+      "card": {
+        "cardholderName": "John Doe",
+        "brand": "Visa",
+        "number": "4111111111111111",
+        "expMonth": "3",
+        "expYear": "2030",
+        "code": "123"
+      }
+    },
+    {"type": 4, "name": "Passport", "identity": {"firstName": "John"}},
+    {"type": 1, "name": "", "login": {"username": "user", "password": "secret"}}
+  ]
+}`
+
+func TestParse_Bitwarden(t *testing.T) {
+	got, err := Parse(Bitwarden, strings.NewReader(bitwardenExport))
+	assert.NoError(t, err)
+	assert.Equal(t, Batch{
+		Cards: []Record[models.ItemRequest]{{Index: 3, Item: models.ItemRequest{Fields: map[string]string{
+			"name": "Visa", "number": "4111111111111111", "holder": "John Doe", "exp_date": "03/30", "cvv": "123",
+		}}}},
+		Passwords: []Record[models.ItemRequest]{{Index: 1, Item: models.ItemRequest{Fields: map[string]string{
+			"name":     "Example",
+			"user":     "user",
+			"password": "secret",
+			"note":     "note\nPIN: 1234\nURL: https://example.com, https://example.org\nTOTP: JBSWY3DPEHPK3PXP",
+		}}}},
+		Texts: []Record[models.ItemRequest]{{Index: 2, Item: models.ItemRequest{Fields: map[string]string{
+			"name": "Note", "data": "secret note",
+		}}}},
+		Failures: []Outcome{
+			{Index: 4, Name: "Passport", Err: ErrUnsupported},
+			{Index: 5, Type: TypePass, Err: ErrNoName},
+		},
+	}, got)
+
+	_, err = Parse(Bitwarden, strings.NewReader(`{"encrypted": true, "items": []}`))
+	assert.Equal(t, ErrEncrypted, err)
+	_, err = Parse(Bitwarden, strings.NewReader(`name,password`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package importers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// LastPass exports the secure notes with the fake URL, and keeps the type of the note in its first line.
+const (
+	lpNoteURL  = "http://sn"
+	lpCardType = "NoteType:Credit Card"
+)
+
+// utf8BOM starts the CSV exports of some managers, e.g. 1Password.
+const utf8BOM = "\ufeff"
+
+// csvRow holds the values of the record keyed by the lowercase column names.
+type csvRow map[string]string
+
+// parseOnePassword maps the CSV export of the 1Password logins.
+// The column names differ between the 1Password versions, so the known aliases are accepted.
+func parseOnePassword(r io.Reader) (Batch, error) {
+	var b Batch
+	err := b.readCSV(r, [][]string{{"title", "name"}, {"password"}}, func(index int, row csvRow) {
+		b.addPassword(index, models.ItemRequest{Fields: map[string]string{
+			"name":     row.get("title", "name"),
+			"user":     row.get("username", "user"),
+			"password": row.get("password"),
+			"note": joinNote(row.get("notes", "notesplain"),
+				"URL", row.get("url", "website", "urls"),
+				"TOTP", row.get("otpauth", "one-time password"),
+				"Tags", row.get("tags")),
+		}})
+	})
+	return b, err
+}
+
+// parseLastPass maps the CSV export of LastPass.
+// The sites become passwords, the secure notes become texts, and the credit card notes become cards.
+func parseLastPass(r io.Reader) (Batch, error) {
+	var b Batch
+	err := b.readCSV(r, [][]string{{"url"}, {"name"}, {"password"}, {"extra"}}, func(index int, row csvRow) {
+		name, extra := row.get("name"), row.get("extra")
+		if row.get("url") != lpNoteURL {
+			b.addPassword(index, models.ItemRequest{Fields: map[string]string{
+				"name":     name,
+				"user":     row.get("username"),
+				"password": row.get("password"),
+				"note":     joinNote(extra, "URL", row.get("url"), "TOTP", row.get("totp"), "Folder", row.get("grouping")),
+			}})
+			return
+		}
+
+		if strings.HasPrefix(extra, lpCardType) {
+			b.addCard(index, getLastPassCard(name, extra))
+			return
+		}
+		b.addText(index, models.ItemRequest{Fields: map[string]string{
+			"name": name, "data": extra, "note": joinNote("", "Folder", row.get("grouping")),
+		}})
+	})
+	return b, err
+}
+
+// parseBrowser maps the CSV export of the Chrome or Firefox passwords.
+// Firefox doesn't name the logins, so they are named by the host of the site.
+func parseBrowser(r io.Reader) (Batch, error) {
+	var b Batch
+	err := b.readCSV(r, [][]string{{"url"}, {"password"}}, func(index int, row csvRow) {
+		name := row.get("name")
+		if name == "" {
+			if u, err := url.Parse(row.get("url")); err == nil {
+				name = u.Hostname()
+			}
+		}
+		b.addPassword(index, models.ItemRequest{Fields: map[string]string{
+			"name":     name,
+			"user":     row.get("username"),
+			"password": row.get("password"),
+			"note":     joinNote(row.get("note"), "URL", row.get("url")),
+		}})
+	})
+	return b, err
+}
+
+// readCSV reads the export with the header, passing every record to the function along with its index.
+// Each of the required column groups must be present with one of its aliases.
+// The malformed records are reported as failures, so the import of the rest isn't blocked by them.
+func (b *Batch) readCSV(r io.Reader, required [][]string, fn func(index int, row csvRow)) error {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(len(utf8BOM)); string(bom) == utf8BOM {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return err
+	}
+	for i, h := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(h))
+	}
+	if !hasColumns(header, required) {
+		return ErrColumns
+	}
+
+	for index := 1; ; index++ {
+		rec, rErr := cr.Read()
+		if errors.Is(rErr, io.EOF) {
+			return nil
+		}
+		var pErr *csv.ParseError
+		if errors.As(rErr, &pErr) {
+			b.fail(index, "", "", pErr)
+			continue
+		}
+		if rErr != nil {
+			return rErr
+		}
+
+		row := make(csvRow, len(header))
+		for i, v := range rec {
+			if i < len(header) {
+				row[header[i]] = v
+			}
+		}
+		fn(index, row)
+	}
+}
+
+func hasColumns(header []string, required [][]string) bool {
+	present := make(map[string]bool, len(header))
+	for _, h := range header {
+		present[h] = true
+	}
+
+	for _, aliases := range required {
+		found := false
+		for _, a := range aliases {
+			found = found || present[a]
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// get returns the first non-empty value of the column aliases.
+func (r csvRow) get(cols ...string) string {
+	for _, c := range cols {
+		if v := strings.TrimSpace(r[c]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// getLastPassCard maps the credit card note, holding the fields in the "Label:Value" lines.
+// The expiration date is kept as "Month,Year", e.g. "January,2025".
+func getLastPassCard(name, extra string) models.ItemRequest {
+	fields := make(map[string]string)
+	var notes []string
+	inNotes := false
+	for _, line := range strings.Split(strings.ReplaceAll(extra, "\r\n", "\n"), "\n") {
+		if inNotes {
+			notes = append(notes, line)
+			continue
+		}
+
+		label, value, _ := strings.Cut(line, ":")
+		if label == "Notes" {
+			inNotes = true
+			notes = append(notes, value)
+			continue
+		}
+		fields[label] = strings.TrimSpace(value)
+	}
+
+	month, year, _ := strings.Cut(fields["Expiration Date"], ",")
+	return models.ItemRequest{Fields: map[string]string{
+		"name":     name,
+		"number":   fields["Number"],
+		"holder":   fields["Name on Card"],
+		"exp_date": getExpDate(getMonth(month), year),
+		"cvv":      fields["Security Code"],
+		"note":     strings.TrimSpace(strings.Join(notes, "\n")),
+	}}
+}
+
+// getMonth returns the number of the month named in English, or the passed value if it isn't the month name.
+func getMonth(name string) string {
+	for m := 1; m <= 12; m++ {
+		if strings.EqualFold(name, time.Month(m).String()) {
+			return strconv.Itoa(m)
+		}
+	}
+	return name
+}
@@ -0,0 +1,117 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+func TestParse_OnePassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		export  string
+		want    Batch
+		wantErr error
+	}{
+		{
+			name:    "Required columns are missing",
+			export:  "Title,Username\nExample,user\n",
+			wantErr: ErrColumns,
+		},
+		{
+			name: "1Password 8 export",
+			export: "\ufeff\"Title\",\"Url\",\"Username\",\"Password\",\"OTPAuth\",\"Favorite\",\"Archived\"," +
+				"\"Tags\",\"Notes\"\n" +
+				"\"Example\",\"https://example.com\",\"user\",\"secret\",\"\",\"false\",\"false\",\"work\",\"note\"\n" +
+				"\"\",\"https://example.org\",\"user\",\"secret\",\"\",\"false\",\"false\",\"\",\"\"\n",
+			want: Batch{
+				Passwords: []Record[models.ItemRequest]{{Index: 1, Item: models.ItemRequest{Fields: map[string]string{
+					"name": "Example", "user": "user", "password": "secret", "note": "note\nURL: https://example.com\nTags: work",
+				}}}},
+				Failures: []Outcome{{Index: 2, Type: TypePass, Err: ErrNoName}},
+			},
+		},
+		{
+			name:   "1Password 7 export",
+			export: "title,website,username,password,notes\nExample,https://example.com,user,secret,\n",
+			want: Batch{
+				Passwords: []Record[models.ItemRequest]{{Index: 1, Item: models.ItemRequest{Fields: map[string]string{
+					"name": "Example", "user": "user", "password": "secret", "note": "URL: https://example.com",
+				}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(OnePass, strings.NewReader(tt.export))
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_LastPass(t *testing.T) {
+	export := "url,username,password,totp,extra,name,grouping,fav\n" +
+		"https://example.com,user,secret,,note,Example,Work,0\n" +
+		"http://sn,,,,secret note,Note,,0\n" +
+		"http://sn,,,,\"NoteType:Credit Card\nLanguage:en-US\nName on Card:John Doe\nType:Visa\n" +
+		"Number:4111111111111111\nSecurity Code:123\nStart Date:,\nExpiration Date:March,2030\n" +
+		"Notes:first line\nsecond line\",Visa,,0\n"
+
+	got, err := Parse(LastPass, strings.NewReader(export))
+	assert.NoError(t, err)
+	assert.Equal(t, Batch{
+		Cards: []Record[models.ItemRequest]{{Index: 3, Item: models.ItemRequest{Fields: map[string]string{
+			"name":     "Visa",
+			"number":   "4111111111111111",
+			"holder":   "John Doe",
+			"exp_date": "03/30",
+			"cvv":      "123",
+			"note":     "first line\nsecond line",
+		}}}},
+		Passwords: []Record[models.ItemRequest]{{Index: 1, Item: models.ItemRequest{Fields: map[string]string{
+			"name": "Example", "user": "user", "password": "secret", "note": "note\nURL: https://example.com\nFolder: Work",
+		}}}},
+		Texts: []Record[models.ItemRequest]{{Index: 2, Item: models.ItemRequest{Fields: map[string]string{
+			"name": "Note", "data": "secret note",
+		}}}},
+	}, got)
+}
+
+func TestParse_Browser(t *testing.T) {
+	tests := []struct {
+		name   string
+		export string
+		want   models.ItemRequest
+	}{
+		{
+			name:   "Chrome export",
+			export: "name,url,username,password,note\nExample,https://example.com/login,user,secret,note\n",
+			want: models.ItemRequest{Fields: map[string]string{
+				"name": "Example", "user": "user", "password": "secret", "note": "note\nURL: https://example.com/login",
+			}},
+		},
+		{
+			name: "Firefox export",
+			export: `"url","username","password","httpRealm","formActionOrigin","guid","timeCreated"` + "\n" +
+				`"https://example.com","user","secret",,"https://example.com","{1}","1700000000000"` + "\n",
+			want: models.ItemRequest{Fields: map[string]string{
+				"name": "example.com", "user": "user", "password": "secret", "note": "URL: https://example.com",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(Browser, strings.NewReader(tt.export))
+			assert.NoError(t, err)
+			assert.Equal(t, Batch{Passwords: []Record[models.ItemRequest]{{Index: 1, Item: tt.want}}}, got)
+		})
+	}
+
+	got, err := Parse(Browser, strings.NewReader("url,username,password\n\"https://example.com\"x\"\",user,secret\n"))
+	assert.NoError(t, err)
+	assert.Len(t, got.Failures, 1)
+}
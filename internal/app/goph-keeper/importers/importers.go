@@ -0,0 +1,258 @@
+// Package importers maps the exports of other password managers to the goph-keeper items.
+// The exports are parsed on the client, so the server only ever receives the items.
+package importers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+type Format string
+
+const (
+	Bitwarden Format = "Bitwarden (JSON)"
+	KeePass   Format = "KeePass (XML)"
+	OnePass   Format = "1Password (CSV)"
+	LastPass  Format = "LastPass (CSV)"
+	Browser   Format = "Chrome or Firefox passwords (CSV)"
+)
+
+// The types of the imported items, named as the registered item types.
+const (
+	TypeCard = "card"
+	TypePass = "password"
+	TypeText = "text"
+)
+
+const maxNameLen = 50
+
+var (
+	ErrFormat      = errors.New("unknown export format")
+	ErrEncrypted   = errors.New("the export is encrypted, please export the data unencrypted")
+	ErrColumns     = errors.New("the export misses the required columns")
+	ErrNoName      = errors.New("the record has no name")
+	ErrUnsupported = errors.New("the record type is not supported")
+	ErrResults     = errors.New("the server has responded with the unexpected number of results")
+
+	Formats = []Format{Bitwarden, KeePass, OnePass, LastPass, Browser}
+)
+
+// Storer stores the imported items with the batch requests.
+type Storer interface {
+	ExecuteBatch(ctx context.Context, req models.BatchRequest) (models.BatchResponse, error)
+}
+
+// Record is the item mapped from the record of the export.
+// The Index is the position of the record in the export, starting with 1.
+type Record[T any] struct {
+	Index int
+	Item  T
+}
+
+// Batch holds the items mapped from the export, grouped by type, along with the records that failed to map.
+type Batch struct {
+	Cards     []Record[models.ItemRequest]
+	Passwords []Record[models.ItemRequest]
+	Texts     []Record[models.ItemRequest]
+	Failures  []Outcome
+}
+
+// Outcome is the result of importing the single record.
+// The ID of the stored item is set on success, and the Err is set on failure.
+type Outcome struct {
+	Index int
+	Type  string
+	Name  string
+	ID    string
+	Err   error
+}
+
+// Parse maps the export of the specified format to the items.
+// The records that can't be mapped are reported as failures, the error is returned for the unreadable export only.
+func Parse(f Format, r io.Reader) (Batch, error) {
+	switch f {
+	case Bitwarden:
+		return parseBitwarden(r)
+	case KeePass:
+		return parseKeePass(r)
+	case OnePass:
+		return parseOnePassword(r)
+	case LastPass:
+		return parseLastPass(r)
+	case Browser:
+		return parseBrowser(r)
+	}
+	return Batch{}, ErrFormat
+}
+
+// Store stores the items of the batch, and reports the outcome of every record, including the failed to map ones.
+// The items are sent in the non-atomic batch requests, so the failure to store the item doesn't stop the import.
+// The outcomes are ordered as the records of the export.
+func Store(ctx context.Context, s Storer, b Batch) []Outcome {
+	res := make([]Outcome, 0, b.Len()+len(b.Failures))
+	res = append(res, b.Failures...)
+
+	pending := make([]Outcome, 0, b.Len())
+	ops := make([]models.BatchOperation, 0, b.Len())
+	add := func(o Outcome, item any) {
+		op, err := models.NewBatchOperation(models.BatchCreate, o.Type, "", item)
+		if err != nil {
+			o.Err = err
+			res = append(res, o)
+			return
+		}
+		pending = append(pending, o)
+		ops = append(ops, op)
+	}
+	for _, r := range b.Cards {
+		add(Outcome{Index: r.Index, Type: TypeCard, Name: r.Item.Fields[items.FieldName]}, r.Item)
+	}
+	for _, r := range b.Passwords {
+		add(Outcome{Index: r.Index, Type: TypePass, Name: r.Item.Fields[items.FieldName]}, r.Item)
+	}
+	for _, r := range b.Texts {
+		add(Outcome{Index: r.Index, Type: TypeText, Name: r.Item.Fields[items.FieldName]}, r.Item)
+	}
+
+	for start := 0; start < len(ops); start += models.MaxBatchOperations {
+		end := start + models.MaxBatchOperations
+		if end > len(ops) {
+			end = len(ops)
+		}
+		res = append(res, storeChunk(ctx, s, ops[start:end], pending[start:end])...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Index < res[j].Index })
+	return res
+}
+
+// storeChunk sends the operations with the single request, and sets the results to the outcomes of their records.
+// The failed request fails all of its records.
+func storeChunk(ctx context.Context, s Storer, ops []models.BatchOperation, outcomes []Outcome) []Outcome {
+	batch, err := s.ExecuteBatch(ctx, models.BatchRequest{Operations: ops})
+	if err == nil && len(batch.Results) != len(ops) {
+		err = ErrResults
+	}
+
+	for i := range outcomes {
+		switch {
+		case err != nil:
+			outcomes[i].Err = err
+		case batch.Results[i].Error != "":
+			outcomes[i].Err = errors.New(batch.Results[i].Error)
+		default:
+			outcomes[i].ID = batch.Results[i].ID
+		}
+	}
+	return outcomes
+}
+
+// Len returns the number of the items mapped from the export.
+func (b *Batch) Len() int {
+	return len(b.Cards) + len(b.Passwords) + len(b.Texts)
+}
+
+// Preview lists the mapped items and the failed records in the order of the export.
+// The secrets are left out, so the preview is safe to show.
+func (b *Batch) Preview() []Outcome {
+	res := make([]Outcome, 0, b.Len()+len(b.Failures))
+	res = append(res, b.Failures...)
+	for _, r := range b.Cards {
+		res = append(res, Outcome{Index: r.Index, Type: TypeCard, Name: r.Item.Fields[items.FieldName]})
+	}
+	for _, r := range b.Passwords {
+		res = append(res, Outcome{Index: r.Index, Type: TypePass, Name: r.Item.Fields[items.FieldName]})
+	}
+	for _, r := range b.Texts {
+		res = append(res, Outcome{Index: r.Index, Type: TypeText, Name: r.Item.Fields[items.FieldName]})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Index < res[j].Index })
+	return res
+}
+
+func (b *Batch) addCard(index int, c models.ItemRequest) {
+	b.add(&b.Cards, index, TypeCard, c)
+}
+
+func (b *Batch) addPassword(index int, p models.ItemRequest) {
+	b.add(&b.Passwords, index, TypePass, p)
+}
+
+func (b *Batch) addText(index int, t models.ItemRequest) {
+	b.add(&b.Texts, index, TypeText, t)
+}
+
+// add appends the item to the records, or reports the failure if the item has no name.
+// The empty values are left out, since the missing fields are stored empty anyway.
+func (b *Batch) add(records *[]Record[models.ItemRequest], index int, t string, item models.ItemRequest) {
+	if item.Fields[items.FieldName] = getName(item.Fields[items.FieldName]); item.Fields[items.FieldName] == "" {
+		b.fail(index, t, "", ErrNoName)
+		return
+	}
+	for k, v := range item.Fields {
+		if v == "" {
+			delete(item.Fields, k)
+		}
+	}
+	*records = append(*records, Record[models.ItemRequest]{Index: index, Item: item})
+}
+
+func (b *Batch) fail(index int, t, name string, err error) {
+	b.Failures = append(b.Failures, Outcome{Index: index, Type: t, Name: name, Err: err})
+}
+
+// TableRow returns the outcome in the format of the import report.
+func (o Outcome) TableRow() []string {
+	status := "OK"
+	if o.Err != nil {
+		status = o.Err.Error()
+	}
+	return []string{fmt.Sprint(o.Index), o.Type, o.Name, o.ID, status}
+}
+
+// getName trims the name of the item to the length the items are limited to.
+func getName(name string) string {
+	name = strings.TrimSpace(name)
+	if r := []rune(name); len(r) > maxNameLen {
+		name = string(r[:maxNameLen])
+	}
+	return name
+}
+
+// joinNote appends the labeled values missing in goph-keeper items, e.g. URLs, to the note, skipping the empty ones.
+func joinNote(note string, labeled ...string) string {
+	lines := []string{strings.TrimSpace(note)}
+	for i := 0; i+1 < len(labeled); i += 2 {
+		if v := strings.TrimSpace(labeled[i+1]); v != "" {
+			lines = append(lines, labeled[i]+": "+v)
+		}
+	}
+	if lines[0] == "" {
+		lines = lines[1:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getExpDate formats the card expiration date as mm/yy, accepting both the short and the full year.
+// The unparsable dates are kept as is.
+func getExpDate(month, year string) string {
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	if month == "" && year == "" {
+		return ""
+	}
+
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 || len(year) < 2 {
+		return strings.Trim(month+"/"+year, "/")
+	}
+	return fmt.Sprintf("%02d/%s", m, year[len(year)-2:])
+}
@@ -0,0 +1,143 @@
+package importers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+var errStore = errors.New("store failed")
+
+// testStorer stores the items in memory, failing the ones named "fail".
+// The requests are recorded, so the split of the batch can be checked.
+type testStorer struct {
+	stored   []string
+	requests int
+	err      error
+}
+
+func (s *testStorer) ExecuteBatch(_ context.Context, req models.BatchRequest) (models.BatchResponse, error) {
+	s.requests++
+	if s.err != nil {
+		return models.BatchResponse{}, s.err
+	}
+
+	res := models.BatchResponse{Committed: true}
+	for _, op := range req.Operations {
+		var item struct{ Name string }
+		if err := json.Unmarshal(op.Data, &item); err != nil {
+			return models.BatchResponse{}, err
+		}
+		if item.Name == "fail" {
+			res.Results = append(res.Results, models.BatchResult{Status: http.StatusBadRequest, Error: errStore.Error()})
+			continue
+		}
+		s.stored = append(s.stored, item.Name)
+		res.Results = append(res.Results, models.BatchResult{ID: "id-" + item.Name, Status: http.StatusOK})
+	}
+	return res, nil
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse("unknown", strings.NewReader(""))
+	assert.Equal(t, ErrFormat, err)
+}
+
+func TestStore(t *testing.T) {
+	b := Batch{
+		Cards: []Record[models.ItemRequest]{
+			{Index: 3, Item: models.ItemRequest{Fields: map[string]string{"name": "card"}}},
+		},
+		Passwords: []Record[models.ItemRequest]{
+			{Index: 1, Item: models.ItemRequest{Fields: map[string]string{"name": "fail"}}},
+		},
+		Texts: []Record[models.ItemRequest]{
+			{Index: 4, Item: models.ItemRequest{Fields: map[string]string{"name": "text"}}},
+		},
+		Failures: []Outcome{{Index: 2, Err: ErrNoName}},
+	}
+
+	s := &testStorer{}
+	got := Store(context.Background(), s, b)
+	assert.Equal(t, []Outcome{
+		{Index: 1, Type: TypePass, Name: "fail", Err: errStore},
+		{Index: 2, Err: ErrNoName},
+		{Index: 3, Type: TypeCard, Name: "card", ID: "id-card"},
+		{Index: 4, Type: TypeText, Name: "text", ID: "id-text"},
+	}, got)
+	assert.Equal(t, []string{"card", "text"}, s.stored)
+	assert.Equal(t, 1, s.requests)
+
+	assert.Equal(t, []Outcome{
+		{Index: 1, Type: TypePass, Name: "fail"},
+		{Index: 2, Err: ErrNoName},
+		{Index: 3, Type: TypeCard, Name: "card"},
+		{Index: 4, Type: TypeText, Name: "text"},
+	}, b.Preview())
+	assert.Equal(t, 3, b.Len())
+}
+
+func TestStore_Chunks(t *testing.T) {
+	var b Batch
+	for i := 1; i <= models.MaxBatchOperations+1; i++ {
+		b.addText(i, models.ItemRequest{Fields: map[string]string{"name": fmt.Sprint("text", i), "data": "test"}})
+	}
+
+	s := &testStorer{}
+	got := Store(context.Background(), s, b)
+	assert.Len(t, got, models.MaxBatchOperations+1)
+	assert.Len(t, s.stored, models.MaxBatchOperations+1)
+	assert.Equal(t, 2, s.requests)
+
+	s = &testStorer{err: errStore}
+	for _, o := range Store(context.Background(), s, b) {
+		assert.Equal(t, errStore, o.Err)
+	}
+}
+
+func TestOutcome_TableRow(t *testing.T) {
+	assert.Equal(t, []string{"1", TypePass, "test", "id", "OK"},
+		Outcome{Index: 1, Type: TypePass, Name: "test", ID: "id"}.TableRow())
+	assert.Equal(t, []string{"2", "", "", "", ErrNoName.Error()}, Outcome{Index: 2, Err: ErrNoName}.TableRow())
+}
+
+func TestBatch_addPassword(t *testing.T) {
+	var b Batch
+	b.addPassword(1, models.ItemRequest{Fields: map[string]string{"name": "  "}})
+	b.addPassword(2, models.ItemRequest{Fields: map[string]string{"name": strings.Repeat("я", maxNameLen+1)}})
+	assert.Equal(t, []Outcome{{Index: 1, Type: TypePass, Err: ErrNoName}}, b.Failures)
+	assert.Equal(t, strings.Repeat("я", maxNameLen), b.Passwords[0].Item.Fields["name"])
+}
+
+func TestGetExpDate(t *testing.T) {
+	tests := []struct {
+		month string
+		year  string
+		want  string
+	}{
+		{},
+		{month: "1", year: "2025", want: "01/25"},
+		{month: "12", year: "30", want: "12/30"},
+		{month: "13", year: "2025", want: "13/2025"},
+		{year: "2025", want: "2025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.month+"/"+tt.year, func(t *testing.T) {
+			assert.Equal(t, tt.want, getExpDate(tt.month, tt.year))
+		})
+	}
+}
+
+func TestJoinNote(t *testing.T) {
+	assert.Equal(t, "", joinNote(" ", "URL", ""))
+	assert.Equal(t, "URL: https://example.com", joinNote("", "URL", "https://example.com"))
+	assert.Equal(t, "note\nURL: https://example.com", joinNote("note", "URL", "https://example.com", "TOTP", " "))
+}
@@ -0,0 +1,100 @@
+package importers
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// The KeePass standard fields, the other ones are custom.
+const (
+	kpTitle    = "Title"
+	kpUser     = "UserName"
+	kpPassword = "Password"
+	kpURL      = "URL"
+	kpNotes    = "Notes"
+)
+
+type kpFile struct {
+	Meta struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []kpGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+// kpGroup holds the entries and the nested groups.
+// The previous versions of the entries are kept in their History, and aren't imported.
+type kpGroup struct {
+	UUID    string    `xml:"UUID"`
+	Entries []kpEntry `xml:"Entry"`
+	Groups  []kpGroup `xml:"Group"`
+}
+
+type kpEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+// parseKeePass maps the XML export of KeePass 2.x.
+// The entries without the user name and password become texts holding the notes, the other ones become passwords.
+// The URLs and custom fields missing in the items are appended to the notes. The recycle bin is skipped.
+func parseKeePass(r io.Reader) (Batch, error) {
+	var f kpFile
+	if err := xml.NewDecoder(r).Decode(&f); err != nil {
+		return Batch{}, err
+	}
+
+	var (
+		b     Batch
+		index int
+	)
+	var walk func(groups []kpGroup)
+	walk = func(groups []kpGroup) {
+		for _, g := range groups {
+			if g.UUID != "" && g.UUID == f.Meta.RecycleBinUUID {
+				continue
+			}
+			for _, e := range g.Entries {
+				index++
+				b.addKeePassEntry(index, e)
+			}
+			walk(g.Groups)
+		}
+	}
+	walk(f.Root.Groups)
+	return b, nil
+}
+
+func (b *Batch) addKeePassEntry(index int, e kpEntry) {
+	fields := make(map[string]string, len(e.Strings))
+	var custom []string
+	for _, s := range e.Strings {
+		fields[s.Key] = s.Value
+		switch s.Key {
+		case kpTitle, kpUser, kpPassword, kpURL, kpNotes:
+		default:
+			custom = append(custom, s.Key, s.Value)
+		}
+	}
+
+	note := joinNote(fields[kpNotes], custom...)
+	if fields[kpUser] == "" && fields[kpPassword] == "" && fields[kpNotes] != "" {
+		b.addText(index, models.ItemRequest{Fields: map[string]string{
+			"name": fields[kpTitle],
+			"data": fields[kpNotes],
+			"note": joinNote("", append(custom, "URL", fields[kpURL])...),
+		}})
+		return
+	}
+	b.addPassword(index, models.ItemRequest{Fields: map[string]string{
+		"name":     fields[kpTitle],
+		"user":     fields[kpUser],
+		"password": fields[kpPassword],
+		"note":     joinNote(note, "URL", fields[kpURL]),
+	}})
+}
@@ -0,0 +1,70 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const keePassExport = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta>
+		<RecycleBinUUID>cmVjeWNsZQ==</RecycleBinUUID>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>cm9vdA==</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>Example</Value></String>
+				<String><Key>UserName</Key><Value>user</Value></String>
+				<String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+				<String><Key>URL</Key><Value>https://example.com</Value></String>
+				<String><Key>Notes</Key><Value>note</Value></String>
+				<String><Key>PIN</Key><Value>1234</Value></String>
+				<History>
+					<Entry>
+						<String><Key>Title</Key><Value>Old example</Value></String>
+						<String><Key>Password</Key><Value>old</Value></String>
+					</Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>bm90ZXM=</UUID>
+				<Name>Notes</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Note</Value></String>
+					<String><Key>Notes</Key><Value>secret note</Value></String>
+					<String><Key>URL</Key><Value>https://example.org</Value></String>
+				</Entry>
+			</Group>
+			<Group>
+				<UUID>cmVjeWNsZQ==</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Deleted</Value></String>
+					<String><Key>Password</Key><Value>deleted</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+func TestParse_KeePass(t *testing.T) {
+	got, err := Parse(KeePass, strings.NewReader(keePassExport))
+	assert.NoError(t, err)
+	assert.Equal(t, Batch{
+		Passwords: []Record[models.ItemRequest]{{Index: 1, Item: models.ItemRequest{Fields: map[string]string{
+			"name": "Example", "user": "user", "password": "secret", "note": "note\nPIN: 1234\nURL: https://example.com",
+		}}}},
+		Texts: []Record[models.ItemRequest]{{Index: 2, Item: models.ItemRequest{Fields: map[string]string{
+			"name": "Note", "data": "secret note", "note": "URL: https://example.org",
+		}}}},
+	}, got)
+
+	_, err = Parse(KeePass, strings.NewReader(`{"items": []}`))
+	assert.Error(t, err)
+}
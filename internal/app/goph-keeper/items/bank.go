@@ -0,0 +1,26 @@
+package items
+
+import (
+	"fmt"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+// The fields of the Bank type checked on the server.
+const (
+	FieldBankIBAN = "iban"
+	FieldBankBIC  = "bic"
+)
+
+// deriveBank checks the check digits of the IBAN and the format of the BIC, if it's set.
+func deriveBank(values map[string]string) error {
+	if err := validators.IBAN(values[FieldBankIBAN]); err != nil {
+		return fmt.Errorf("%w: %s", err, FieldBankIBAN)
+	}
+	if bic := values[FieldBankBIC]; bic != "" {
+		if err := validators.BIC(bic); err != nil {
+			return fmt.Errorf("%w: %s", err, FieldBankBIC)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package items
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func TestBank_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "Wrong IBAN check digits",
+			values:  map[string]string{"name": "test", "iban": "GB83WEST12345698765432"},
+			wantErr: validators.ErrIBANChecksum,
+		},
+		{
+			name:    "Incorrectly formatted BIC",
+			values:  map[string]string{"name": "test", "iban": "GB82WEST12345698765432", "bic": "DEUT"},
+			wantErr: validators.ErrBICFormat,
+		},
+		{
+			name:   "Account without BIC",
+			values: map[string]string{"name": "test", "iban": "DE89 3704 0044 0532 0130 00"},
+			want: map[string]string{
+				"name": "test", "holder": "", "bank": "", "iban": "DE89 3704 0044 0532 0130 00", "bic": "", "note": "",
+			},
+		},
+		{
+			name:   "Account with BIC",
+			values: map[string]string{"name": "test", "iban": "GB82WEST12345698765432", "bic": "DEUTDEFF"},
+			want: map[string]string{
+				"name": "test", "holder": "", "bank": "", "iban": "GB82WEST12345698765432", "bic": "DEUTDEFF", "note": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Bank.Normalize(tt.values)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package items
+
+import (
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+// The built-in types. Their payloads are stored with the same field names since the first version,
+// so the names must not be changed.
+var (
+	Binary = Type{
+		Name:    "binary",
+		Plural:  "binaries",
+		Storage: data.SBinary,
+		Fields: []Field{
+			nameField,
+			{Name: "data", Label: "Data", Kind: KindFile, Required: true, Hidden: true},
+			noteField,
+		},
+	}
+	Card = Type{
+		Name:    "card",
+		Plural:  "cards",
+		Storage: data.SCard,
+		Fields: []Field{
+			nameField,
+			{Name: FieldCardNumber, Label: "Number", Hidden: true, Mask: "****", Validate: validators.CardNumber},
+			{Name: FieldCardBrand, Label: "Brand", Derived: true},
+			{Name: FieldCardLast4, Label: "Last digits", Derived: true},
+			{Name: "holder", Label: "Holder", Validate: validators.Max(50)},
+			{Name: "exp_date", Label: "Expire date", Validate: validators.CardExpDate},
+			{Name: FieldCardCVV, Label: "CVV", Kind: KindHidden, Hidden: true, Mask: "***", Validate: validators.CardCVV},
+			noteField,
+		},
+		Check:  checkCard,
+		Derive: deriveCard,
+	}
+	Password = Type{
+		Name:    "password",
+		Plural:  "passwords",
+		Storage: data.SPassword,
+		Fields: []Field{
+			nameField,
+			{Name: "user", Label: "User", Validate: validators.Min(1)},
+			{Name: "password", Label: "Password", Kind: KindHidden, Hidden: true, Mask: "********",
+				Validate: validators.Min(1)},
+			noteField,
+		},
+	}
+	Text = Type{
+		Name:    "text",
+		Plural:  "texts",
+		Storage: data.SText,
+		Fields: []Field{
+			nameField,
+			{Name: "data", Label: "Text", Required: true, Hidden: true, Validate: validators.Min(1)},
+			noteField,
+		},
+	}
+
+	Note = Type{
+		Name:    "securenote",
+		Plural:  "secure notes",
+		Storage: data.SNote,
+		Fields: []Field{
+			nameField,
+			{Name: "content", Label: "Content", Kind: KindMultiline, Required: true, Hidden: true},
+			noteField,
+		},
+	}
+	Identity = Type{
+		Name:    "identity",
+		Plural:  "identities",
+		Storage: data.SIdentity,
+		Fields: []Field{
+			nameField,
+			{Name: "document", Label: "Document", Required: true, Validate: validators.Max(50)},
+			{Name: "full_name", Label: "Full name", Validate: validators.Max(100)},
+			{Name: "number", Label: "Number", Required: true, Validate: validators.Max(50)},
+			{Name: "country", Label: "Country", Validate: validators.Max(50)},
+			{Name: FieldIdentityIssue, Label: "Issue date", Kind: KindDate},
+			{Name: FieldExpiry, Label: "Expire date", Kind: KindDate, Validate: validators.Optional(validators.DocExpDate)},
+			noteField,
+		},
+		Derive: deriveIdentity,
+	}
+	Bank = Type{
+		Name:    "bank",
+		Plural:  "bank accounts",
+		Storage: data.SBank,
+		Fields: []Field{
+			nameField,
+			{Name: "holder", Label: "Holder", Validate: validators.Max(50)},
+			{Name: "bank", Label: "Bank", Validate: validators.Max(50)},
+			{Name: FieldBankIBAN, Label: "IBAN", Required: true, Validate: validators.IBAN},
+			{Name: FieldBankBIC, Label: "SWIFT/BIC", Validate: validators.Optional(validators.BIC)},
+			noteField,
+		},
+		Derive: deriveBank,
+	}
+
+	// SSHKey holds the private key read from the key file. The public key is derived from the private one
+	// when it's not entered, so the encrypted key stored without the passphrase must come with its public key,
+	// unless the key file holds it.
+	SSHKey = Type{
+		Name:    "ssh",
+		Plural:  "SSH keys",
+		Storage: data.SSSHKey,
+		Fields: []Field{
+			nameField,
+			{Name: FieldPrivateKey, Label: "Private key", Kind: KindFile, Required: true, Hidden: true},
+			{Name: FieldPassphrase, Label: "Passphrase", Kind: KindHidden, Hidden: true, Mask: "********"},
+			{Name: FieldPublicKey, Label: "Public key"},
+			{Name: FieldFingerprint, Label: "Fingerprint", Derived: true},
+			noteField,
+		},
+		Derive: deriveSSHKey,
+	}
+
+	// Custom holds the items of the user-defined templates. The values of the template fields are held
+	// by the custom fields of the item, so the type itself knows the template name only.
+	Custom = Type{
+		Name:    "custom",
+		Plural:  "custom items",
+		Storage: data.SCustom,
+		Fields: []Field{
+			nameField,
+			{Name: FieldTemplate, Label: "Template"},
+			noteField,
+		},
+	}
+
+	nameField = Field{Name: FieldName, Label: "Name", Required: true, Validate: validators.ItemName}
+	noteField = Field{Name: FieldNote, Label: "Note", Validate: validators.Max(50)}
+)
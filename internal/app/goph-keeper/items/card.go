@@ -0,0 +1,53 @@
+package items
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+// The fields of the Card type. The brand and the last digits are derived from the number,
+// so the card lists show them while the number itself is hidden.
+const (
+	FieldCardNumber = "number"
+	FieldCardBrand  = "brand"
+	FieldCardLast4  = "last4"
+	FieldCardCVV    = "cvv"
+)
+
+// checkCard checks the card number and its CVV against the card brand, if either of them is new.
+// The stored cards may have the numbers the earlier versions didn't check, so they are updated and imported as is.
+func checkCard(values, prev map[string]string) error {
+	num, cvv := values[FieldCardNumber], values[FieldCardCVV]
+	if num == "" || prev != nil && num == prev[FieldCardNumber] && cvv == prev[FieldCardCVV] {
+		return nil
+	}
+	if err := validators.CardNumber(num); err != nil {
+		return fmt.Errorf("%w: %s", err, FieldCardNumber)
+	}
+	if cvv != "" {
+		if err := validators.CardBrandCVV(num, cvv); err != nil {
+			return fmt.Errorf("%w: %s", err, FieldCardCVV)
+		}
+	}
+	return nil
+}
+
+// deriveCard sets the brand and the last digits of the card number.
+// The card without the number is stored as is, since the earlier versions didn't require it.
+func deriveCard(values map[string]string) error {
+	num := strings.ReplaceAll(values[FieldCardNumber], " ", "")
+	if num == "" {
+		return nil
+	}
+
+	if b, ok := validators.DetectCardBrand(num); ok {
+		values[FieldCardBrand] = b.Name
+	}
+	if len(num) > 4 {
+		num = num[len(num)-4:]
+	}
+	values[FieldCardLast4] = num
+	return nil
+}
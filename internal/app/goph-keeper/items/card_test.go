@@ -0,0 +1,108 @@
+package items
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func TestCard_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		prev    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "Wrong check digit",
+			values:  map[string]string{"number": "4111111111111112"},
+			wantErr: validators.ErrCardNumberChecksum,
+		},
+		{
+			name:    "CVV doesn't match the brand",
+			values:  map[string]string{"number": "378282246310005", "cvv": "123"},
+			wantErr: validators.ErrCVVLength,
+		},
+		{
+			name:   "Card without number",
+			values: map[string]string{"cvv": "123"},
+		},
+		{
+			name:   "Number is unchanged",
+			values: map[string]string{"number": "4111111111111112"},
+			prev:   map[string]string{"number": "4111111111111112"},
+		},
+		{
+			name:    "Number is changed",
+			values:  map[string]string{"number": "4111111111111113"},
+			prev:    map[string]string{"number": "4111111111111112"},
+			wantErr: validators.ErrCardNumberChecksum,
+		},
+		{
+			name:    "CVV is changed",
+			values:  map[string]string{"number": "378282246310005", "cvv": "123"},
+			prev:    map[string]string{"number": "378282246310005", "cvv": "1234"},
+			wantErr: validators.ErrCVVLength,
+		},
+		{
+			name:   "Number is correct",
+			values: map[string]string{"number": "3782 822463 10005", "cvv": "1234"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, errors.Is(Card.Validate(tt.values, tt.prev), tt.wantErr))
+		})
+	}
+}
+
+func TestCard_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:   "Card without number",
+			values: map[string]string{"name": "test", "brand": "Visa"},
+			want: map[string]string{
+				"name": "test", "number": "", "brand": "", "last4": "", "holder": "", "exp_date": "", "cvv": "", "note": "",
+			},
+		},
+		{
+			name:   "Brand and last digits are derived",
+			values: map[string]string{"name": "test", "number": "3782 822463 10005", "cvv": "1234"},
+			want: map[string]string{
+				"name": "test", "number": "3782 822463 10005", "brand": "Amex", "last4": "0005", "holder": "",
+				"exp_date": "", "cvv": "1234", "note": "",
+			},
+		},
+		{
+			name:   "Wrong number is stored as is",
+			values: map[string]string{"name": "test", "number": "4111111111111112"},
+			want: map[string]string{
+				"name": "test", "number": "4111111111111112", "brand": "Visa", "last4": "1112", "holder": "",
+				"exp_date": "", "cvv": "", "note": "",
+			},
+		},
+		{
+			name:   "Brand is unknown",
+			values: map[string]string{"name": "test", "number": "9999999999999995"},
+			want: map[string]string{
+				"name": "test", "number": "9999999999999995", "brand": "", "last4": "9995", "holder": "",
+				"exp_date": "", "cvv": "", "note": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Card.Normalize(tt.values)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package items
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldTemplate holds the name of the template the custom item is created with.
+const FieldTemplate = "template"
+
+// CustomField is the extra field added to the item on top of the fields of its type.
+type CustomField struct {
+	Name  string `json:"name"`
+	Kind  Kind   `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Template is the user-defined type of the custom items.
+// Its fields are filled in as the custom fields of the item, so the items of any template are stored alike.
+type Template struct {
+	Name   string          `json:"name"`
+	Fields []TemplateField `json:"fields"`
+}
+
+// TemplateField is the field of the Template.
+type TemplateField struct {
+	Name     string `json:"name"`
+	Kind     Kind   `json:"kind"`
+	Required bool   `json:"required"`
+}
+
+// NormalizeCustom returns the custom fields with the trimmed names, and the empty kinds set to KindText.
+// The error is returned if any name is empty or repeated, or any value doesn't match the kind of its field.
+func NormalizeCustom(fields []CustomField) ([]CustomField, error) {
+	res := make([]CustomField, 0, len(fields))
+	names := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		f.Name = strings.TrimSpace(f.Name)
+		if f.Name == "" || names[f.Name] {
+			return nil, fmt.Errorf("%w: %q", ErrField, f.Name)
+		}
+		names[f.Name] = true
+
+		if f.Kind == "" {
+			f.Kind = KindText
+		}
+		if !f.Kind.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrKind, f.Kind)
+		}
+		if err := f.Kind.Check(f.Value); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, f.Name)
+		}
+		res = append(res, f)
+	}
+	return res, nil
+}
+
+// MaskCustom returns the custom fields with the hidden values masked.
+func MaskCustom(fields []CustomField) []CustomField {
+	if len(fields) == 0 {
+		return fields
+	}
+	res := make([]CustomField, 0, len(fields))
+	for _, f := range fields {
+		if f.Kind == KindHidden {
+			f.Value = hiddenMask
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// Validate checks the template name and fields, setting the empty kinds to KindText.
+func (t *Template) Validate() error {
+	if t.Name = strings.TrimSpace(t.Name); t.Name == "" {
+		return fmt.Errorf("%w: template name", ErrRequired)
+	}
+
+	names := make(map[string]bool, len(t.Fields))
+	for i, f := range t.Fields {
+		if f.Name = strings.TrimSpace(f.Name); f.Name == "" || names[f.Name] {
+			return fmt.Errorf("%w: %q", ErrField, f.Name)
+		}
+		names[f.Name] = true
+
+		if f.Kind == "" {
+			f.Kind = KindText
+		}
+		if !f.Kind.IsValid() {
+			return fmt.Errorf("%w: %s", ErrKind, f.Kind)
+		}
+		t.Fields[i] = f
+	}
+	return nil
+}
+
+// Apply returns the custom fields of the item created with the template.
+// The template fields go first, taking their kinds from the template, and the extra fields of the item follow.
+// The error is returned if any required template field is missing or empty.
+func (t Template) Apply(fields []CustomField) ([]CustomField, error) {
+	values := make(map[string]CustomField, len(fields))
+	for _, f := range fields {
+		values[f.Name] = f
+	}
+
+	res := make([]CustomField, 0, len(fields)+len(t.Fields))
+	for _, tf := range t.Fields {
+		f, ok := values[tf.Name]
+		if tf.Required && f.Value == "" {
+			return nil, fmt.Errorf("%w: %s", ErrRequired, tf.Name)
+		}
+		delete(values, tf.Name)
+		if ok {
+			res = append(res, CustomField{Name: tf.Name, Kind: tf.Kind, Value: f.Value})
+		}
+	}
+	for _, f := range fields {
+		if _, ok := values[f.Name]; ok {
+			res = append(res, f)
+		}
+	}
+	return NormalizeCustom(res)
+}
+
+// Field returns the template field as the item field, so it's entered as the fields of the built-in types.
+func (f TemplateField) Field() Field {
+	return Field{Name: f.Name, Label: f.Name, Kind: f.Kind, Required: f.Required, Hidden: f.Kind == KindHidden}
+}
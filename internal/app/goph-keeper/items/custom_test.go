@@ -0,0 +1,146 @@
+package items
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKind_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		k       Kind
+		value   string
+		wantErr error
+	}{
+		{name: "Empty value", k: KindDate},
+		{name: "Text", k: KindText, value: "test"},
+		{name: "URL", k: KindURL, value: "https://test.com/path"},
+		{name: "URL without host", k: KindURL, value: "test.com", wantErr: ErrFormat},
+		{name: "Number", k: KindNumber, value: "-1.5"},
+		{name: "Not a number", k: KindNumber, value: "1,5", wantErr: ErrFormat},
+		{name: "Date", k: KindDate, value: "2030-01-31"},
+		{name: "Wrong date", k: KindDate, value: "31.01.2030", wantErr: ErrFormat},
+		{name: "File", k: KindFile, value: "dGVzdA=="},
+		{name: "File isn't encoded", k: KindFile, value: "test!", wantErr: ErrEncoding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, errors.Is(tt.k.Check(tt.value), tt.wantErr))
+		})
+	}
+
+	assert.True(t, KindHidden.IsValid())
+	assert.False(t, KindFile.IsValid())
+}
+
+func TestNormalizeCustom(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []CustomField
+		want    []CustomField
+		wantErr error
+	}{
+		{
+			name:    "Name is missing",
+			fields:  []CustomField{{Name: " ", Value: "test"}},
+			wantErr: ErrField,
+		},
+		{
+			name:    "Name is repeated",
+			fields:  []CustomField{{Name: "host"}, {Name: " host"}},
+			wantErr: ErrField,
+		},
+		{
+			name:    "Kind is unknown",
+			fields:  []CustomField{{Name: "host", Kind: "ip"}},
+			wantErr: ErrKind,
+		},
+		{
+			name:    "Value doesn't match the kind",
+			fields:  []CustomField{{Name: "port", Kind: KindNumber, Value: "test"}},
+			wantErr: ErrFormat,
+		},
+		{
+			name:   "Fields are normalized",
+			fields: []CustomField{{Name: " host ", Value: "db"}, {Name: "pin", Kind: KindHidden, Value: "1234"}},
+			want:   []CustomField{{Name: "host", Kind: KindText, Value: "db"}, {Name: "pin", Kind: KindHidden, Value: "1234"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeCustom(tt.fields)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMaskCustom(t *testing.T) {
+	assert.Nil(t, MaskCustom(nil))
+	assert.Equal(t,
+		[]CustomField{{Name: "host", Kind: KindText, Value: "db"}, {Name: "pin", Kind: KindHidden, Value: hiddenMask}},
+		MaskCustom([]CustomField{{Name: "host", Kind: KindText, Value: "db"}, {Name: "pin", Kind: KindHidden, Value: "1"}}),
+	)
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	tmpl := Template{Name: " db ", Fields: []TemplateField{
+		{Name: " host", Required: true}, {Name: "port", Kind: KindNumber},
+	}}
+	assert.NoError(t, tmpl.Validate())
+	assert.Equal(t, Template{Name: "db", Fields: []TemplateField{
+		{Name: "host", Kind: KindText, Required: true}, {Name: "port", Kind: KindNumber},
+	}}, tmpl)
+
+	assert.True(t, errors.Is((&Template{}).Validate(), ErrRequired))
+	assert.True(t, errors.Is((&Template{Name: "db", Fields: []TemplateField{{}}}).Validate(), ErrField))
+	tmpl = Template{Name: "db", Fields: []TemplateField{{Name: "ip", Kind: "ip"}}}
+	assert.True(t, errors.Is(tmpl.Validate(), ErrKind))
+}
+
+func TestTemplate_Apply(t *testing.T) {
+	tmpl := Template{Name: "db", Fields: []TemplateField{
+		{Name: "host", Kind: KindURL, Required: true},
+		{Name: "port", Kind: KindNumber},
+		{Name: "password", Kind: KindHidden},
+	}}
+	tests := []struct {
+		name    string
+		fields  []CustomField
+		want    []CustomField
+		wantErr error
+	}{
+		{
+			name:    "Required field is missing",
+			fields:  []CustomField{{Name: "port", Value: "5432"}},
+			wantErr: ErrRequired,
+		},
+		{
+			name:    "Value doesn't match the template kind",
+			fields:  []CustomField{{Name: "host", Value: "https://db"}, {Name: "port", Value: "test"}},
+			wantErr: ErrFormat,
+		},
+		{
+			name: "Template fields go first",
+			fields: []CustomField{
+				{Name: "note", Value: "test"},
+				{Name: "port", Kind: KindText, Value: "5432"},
+				{Name: "host", Value: "https://db"},
+			},
+			want: []CustomField{
+				{Name: "host", Kind: KindURL, Value: "https://db"},
+				{Name: "port", Kind: KindNumber, Value: "5432"},
+				{Name: "note", Kind: KindText, Value: "test"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.Apply(tt.fields)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
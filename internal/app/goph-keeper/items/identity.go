@@ -0,0 +1,18 @@
+package items
+
+import (
+	"fmt"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+// FieldIdentityIssue holds the issue date of the identity document, the expire date is held by FieldExpiry.
+const FieldIdentityIssue = "issue_date"
+
+// deriveIdentity checks that the document doesn't expire before it's issued.
+func deriveIdentity(values map[string]string) error {
+	if err := validators.DocDates(values[FieldIdentityIssue], values[FieldExpiry]); err != nil {
+		return fmt.Errorf("%w: %s", err, FieldExpiry)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package items
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/validators"
+)
+
+func TestIdentity_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "Document expires before it's issued",
+			values: map[string]string{
+				"name": "test", "document": "passport", "number": "1", "issue_date": "2020-01-01", "expiry": "2019-01-01",
+			},
+			wantErr: validators.ErrDocExpDateOrder,
+		},
+		{
+			name: "Expired document",
+			values: map[string]string{
+				"name": "test", "document": "passport", "number": "1", "issue_date": "2001-01-01", "expiry": "2011-01-01",
+			},
+			want: map[string]string{
+				"name": "test", "document": "passport", "full_name": "", "number": "1", "country": "",
+				"issue_date": "2001-01-01", "expiry": "2011-01-01", "note": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Identity.Normalize(tt.values)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Package items declares the types of the stored items.
+// The routes, services, client methods and CLI menus of the items are derived from the registered types,
+// so the new type is added by declaring its fields once.
+package items
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+// Kind tells how the field value is entered, checked and shown.
+type Kind string
+
+const (
+	KindText Kind = "text"
+	// KindHidden is entered with the masked input.
+	KindHidden Kind = "hidden"
+	KindURL    Kind = "url"
+	KindNumber Kind = "number"
+	// KindDate holds the date in the DateLayout format.
+	KindDate Kind = "date"
+	// KindMultiline is entered line by line, keeping the formatting of the text.
+	KindMultiline Kind = "multiline"
+	// KindFile holds the base64-encoded content of the file, read from the path entered by the user.
+	KindFile Kind = "file"
+)
+
+// DateLayout is the format of the date values.
+const DateLayout = "2006-01-02"
+
+// The fields every item type has.
+const (
+	FieldName = "name"
+	FieldNote = "note"
+)
+
+// FieldExpiry holds the date the item expires on.
+const FieldExpiry = "expiry"
+
+// hiddenMask replaces the hidden custom values in the CLI item lists.
+const hiddenMask = "********"
+
+// Field is the single value of the item, stored under its Name in the item payload.
+// The Validate function checks the value entered in the CLI, the server checks the Required values only.
+// The Hidden value is replaced with the Mask in the CLI item lists, so it's shown with the single item only.
+// The Derived value is never entered, it's set by the Derive function of the type.
+type Field struct {
+	Name     string
+	Label    string
+	Kind     Kind
+	Required bool
+	Hidden   bool
+	Derived  bool
+	Mask     string
+	Validate func(v string) error
+}
+
+// Type is the item type. The Name is used in the storage routes and the API token scope,
+// the Plural names the items in the CLI menu and the vault archive sections.
+// The Derive function, if any, checks the normalized values as a whole and sets the derived ones.
+// The Check function, if any, checks the values the earlier stored items may not pass, e.g. the card number checksum.
+type Type struct {
+	Name    string
+	Plural  string
+	Storage data.StorageType
+	Fields  []Field
+	Check   func(values, prev map[string]string) error
+	Derive  func(values map[string]string) error
+}
+
+var (
+	ErrDuplicate = errors.New("the item type is already registered")
+	ErrRequired  = errors.New("the required item field is empty")
+	ErrEncoding  = errors.New("the file item field is not base64-encoded")
+	ErrFormat    = errors.New("the item field value doesn't match the field kind")
+	ErrKind      = errors.New("unknown item field kind")
+	ErrField     = errors.New("the item field name is empty or duplicated")
+
+	// Kinds lists the kinds of the fields the users can define.
+	Kinds = []Kind{KindText, KindHidden, KindURL, KindNumber, KindDate, KindMultiline}
+
+	registry = []Type{Binary, Card, Password, Text, Note, Identity, Bank, SSHKey, Custom}
+)
+
+// Register adds the item type to the registry. The types are registered on start, before the routes are built.
+// Both the name and the storage type must be unique, since the items are routed and stored by them.
+func Register(t Type) error {
+	for _, r := range registry {
+		if r.Name == t.Name || r.Storage == t.Storage {
+			return fmt.Errorf("%w: %s", ErrDuplicate, t.Name)
+		}
+	}
+	registry = append(registry, t)
+	return nil
+}
+
+// All returns the registered types in the order of registration.
+func All() []Type {
+	return append([]Type(nil), registry...)
+}
+
+// Names returns the names of the registered types.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for _, t := range registry {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// Lookup returns the registered type with the specified name.
+func Lookup(name string) (Type, bool) {
+	for _, t := range registry {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// LookupStorage returns the registered type the items of the specified storage type belong to.
+func LookupStorage(st data.StorageType) (Type, bool) {
+	for _, t := range registry {
+		if t.Storage == st {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// Title returns the plural name of the type starting with the capital letter, e.g. "Cards".
+func (t Type) Title() string {
+	if t.Plural == "" {
+		return ""
+	}
+	return strings.ToUpper(t.Plural[:1]) + t.Plural[1:]
+}
+
+// Header returns the columns of the items table: the ID followed by the field labels.
+func (t Type) Header() []string {
+	header := make([]string, 0, len(t.Fields)+1)
+	header = append(header, "ID")
+	for _, f := range t.Fields {
+		header = append(header, f.Label)
+	}
+	return header
+}
+
+// Normalize returns the values of the type fields only, dropping the unknown ones and the passed derived ones.
+// The error is returned if any of the required values is empty, or any value doesn't match the kind of its field.
+func (t Type) Normalize(values map[string]string) (map[string]string, error) {
+	res := make(map[string]string, len(t.Fields))
+	for _, f := range t.Fields {
+		if f.Derived {
+			res[f.Name] = ""
+			continue
+		}
+
+		v := values[f.Name]
+		if f.Required && v == "" {
+			return nil, fmt.Errorf("%w: %s", ErrRequired, f.Name)
+		}
+		if err := f.Kind.Check(v); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, f.Name)
+		}
+		res[f.Name] = v
+	}
+
+	if t.Derive != nil {
+		if err := t.Derive(res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Validate runs the Check function of the type against the normalized values of the new or updated item.
+// The previous values are nil for the new item, so the checks of the unchanged values are skipped on update only.
+func (t Type) Validate(values, prev map[string]string) error {
+	if t.Check == nil {
+		return nil
+	}
+	return t.Check(values, prev)
+}
+
+// Mask returns the values with the hidden ones replaced by their masks.
+func (t Type) Mask(values map[string]string) map[string]string {
+	res := make(map[string]string, len(values))
+	for k, v := range values {
+		res[k] = v
+	}
+	for _, f := range t.Fields {
+		if f.Hidden {
+			res[f.Name] = f.Mask
+		}
+	}
+	return res
+}
+
+// Check returns the error if the non-empty value doesn't match the kind.
+func (k Kind) Check(v string) error {
+	if v == "" {
+		return nil
+	}
+
+	var err error
+	switch k {
+	case KindURL:
+		var u *url.URL
+		if u, err = url.ParseRequestURI(v); err == nil && (u.Scheme == "" || u.Host == "") {
+			err = ErrFormat
+		}
+	case KindNumber:
+		_, err = strconv.ParseFloat(v, 64)
+	case KindDate:
+		_, err = time.Parse(DateLayout, v)
+	case KindFile:
+		if _, dErr := base64.StdEncoding.DecodeString(v); dErr != nil {
+			return ErrEncoding
+		}
+	}
+	if err != nil {
+		return ErrFormat
+	}
+	return nil
+}
+
+// IsValid tells if the users can define the fields of the kind.
+func (k Kind) IsValid() bool {
+	for _, kind := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package items
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Type
+		wantErr error
+	}{
+		{
+			name:    "Name is registered",
+			t:       Type{Name: "card", Storage: data.StorageType(100)},
+			wantErr: ErrDuplicate,
+		},
+		{
+			name:    "Storage is registered",
+			t:       Type{Name: "note", Storage: data.SText},
+			wantErr: ErrDuplicate,
+		},
+		{
+			name: "Type is registered",
+			t:    Type{Name: "note", Storage: data.StorageType(100)},
+		},
+	}
+	defer func(r []Type) { registry = r }(All())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Register(tt.t)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			if tt.wantErr == nil {
+				got, ok := Lookup(tt.t.Name)
+				assert.True(t, ok)
+				assert.Equal(t, tt.t, got)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	got, ok := Lookup("password")
+	assert.True(t, ok)
+	assert.Equal(t, data.SPassword, got.Storage)
+
+	_, ok = Lookup("unknown")
+	assert.False(t, ok)
+
+	got, ok = LookupStorage(data.SBinary)
+	assert.True(t, ok)
+	assert.Equal(t, "binary", got.Name)
+
+	assert.Equal(t, []string{
+		"binary", "card", "password", "text", "securenote", "identity", "bank", "ssh", "custom",
+	}, Names())
+}
+
+func TestType_Header(t *testing.T) {
+	assert.Equal(t, "Passwords", Password.Title())
+	assert.Equal(t, []string{"ID", "Name", "User", "Password", "Note"}, Password.Header())
+}
+
+func TestType_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Type
+		values  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "Name is missing",
+			t:       Text,
+			values:  map[string]string{"data": "test"},
+			wantErr: ErrRequired,
+		},
+		{
+			name:    "File is not encoded",
+			t:       Binary,
+			values:  map[string]string{"name": "test", "data": "test!"},
+			wantErr: ErrEncoding,
+		},
+		{
+			name:   "Unknown fields are dropped",
+			t:      Text,
+			values: map[string]string{"name": "test", "data": "test", "id": "test"},
+			want:   map[string]string{"name": "test", "data": "test", "note": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.t.Normalize(tt.values)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestType_Mask(t *testing.T) {
+	values := map[string]string{"name": "test", "user": "user", "password": "secret"}
+	assert.Equal(t, map[string]string{"name": "test", "user": "user", "password": "********"}, Password.Mask(values))
+	assert.Equal(t, "secret", values["password"])
+}
@@ -0,0 +1,93 @@
+package items
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// The fields of the SSHKey type.
+const (
+	FieldPrivateKey  = "private_key"
+	FieldPassphrase  = "passphrase"
+	FieldPublicKey   = "public_key"
+	FieldFingerprint = "fingerprint"
+)
+
+var ErrSSHKey = errors.New("the SSH key can't be read or doesn't match its public key")
+
+// ParseSSHKey returns the private key of the SSHKey item, decrypted with the passphrase of the item.
+// The *ssh.PassphraseMissingError is returned as is if the key is encrypted and the item has no passphrase.
+func ParseSSHKey(values map[string]string) (any, error) {
+	pem, err := base64.StdEncoding.DecodeString(values[FieldPrivateKey])
+	if err != nil {
+		return nil, ErrEncoding
+	}
+
+	var key any
+	if passphrase := values[FieldPassphrase]; passphrase != "" {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(pem, []byte(passphrase))
+	} else {
+		key, err = ssh.ParseRawPrivateKey(pem)
+	}
+
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, missing
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSSHKey, err)
+	}
+	return key, nil
+}
+
+// deriveSSHKey checks the private key against the public one, and sets the public key and its fingerprint.
+func deriveSSHKey(values map[string]string) error {
+	var pub ssh.PublicKey
+	if v := values[FieldPublicKey]; v != "" {
+		var err error
+		if pub, _, _, _, err = ssh.ParseAuthorizedKey([]byte(v)); err != nil {
+			return fmt.Errorf("%w: %s", ErrSSHKey, err)
+		}
+	}
+
+	derived, err := getSSHPublicKey(values)
+	if err != nil {
+		return err
+	}
+	switch {
+	case pub == nil && derived == nil:
+		return fmt.Errorf("%w: the public key is required for the encrypted key", ErrSSHKey)
+	case pub == nil:
+		pub = derived
+	case derived != nil && !bytes.Equal(pub.Marshal(), derived.Marshal()):
+		return fmt.Errorf("%w: the keys don't match", ErrSSHKey)
+	}
+
+	values[FieldPublicKey] = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+	values[FieldFingerprint] = ssh.FingerprintSHA256(pub)
+	return nil
+}
+
+// getSSHPublicKey returns the public key of the private one. The public key of the encrypted key is read
+// from the key file without decrypting it, and it's nil if the file doesn't hold it.
+func getSSHPublicKey(values map[string]string) (ssh.PublicKey, error) {
+	key, err := ParseSSHKey(values)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return missing.PublicKey, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSSHKey, err)
+	}
+	return signer.PublicKey(), nil
+}
@@ -0,0 +1,151 @@
+package items
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHKey_Normalize(t *testing.T) {
+	key, pub := getTestSSHKey(t)
+	encKey, encPub := getTestEncryptedSSHKey(t, "secret")
+	_, otherPub := getTestSSHKey(t)
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "Key is missing",
+			values:  map[string]string{"name": "test"},
+			wantErr: ErrRequired,
+		},
+		{
+			name:    "Key can't be read",
+			values:  map[string]string{"name": "test", "private_key": "dGVzdA=="},
+			wantErr: ErrSSHKey,
+		},
+		{
+			name:    "Public key can't be read",
+			values:  map[string]string{"name": "test", "private_key": key, "public_key": "test"},
+			wantErr: ErrSSHKey,
+		},
+		{
+			name:    "Public key doesn't match",
+			values:  map[string]string{"name": "test", "private_key": key, "public_key": getAuthorizedKey(otherPub)},
+			wantErr: ErrSSHKey,
+		},
+		{
+			name:    "Passphrase is wrong",
+			values:  map[string]string{"name": "test", "private_key": encKey, "passphrase": "wrong"},
+			wantErr: ErrSSHKey,
+		},
+		{
+			name:    "Encrypted key without passphrase and public key",
+			values:  map[string]string{"name": "test", "private_key": encKey},
+			wantErr: ErrSSHKey,
+		},
+		{
+			name:   "Encrypted key with public key",
+			values: map[string]string{"name": "test", "private_key": encKey, "public_key": getAuthorizedKey(encPub) + " test"},
+			want: map[string]string{
+				"name": "test", "private_key": encKey, "passphrase": "", "note": "",
+				"public_key": getAuthorizedKey(encPub), "fingerprint": ssh.FingerprintSHA256(encPub),
+			},
+		},
+		{
+			name:   "Encrypted key with passphrase",
+			values: map[string]string{"name": "test", "private_key": encKey, "passphrase": "secret"},
+			want: map[string]string{
+				"name": "test", "private_key": encKey, "passphrase": "secret", "note": "",
+				"public_key": getAuthorizedKey(encPub), "fingerprint": ssh.FingerprintSHA256(encPub),
+			},
+		},
+		{
+			name:   "Public key and fingerprint are derived",
+			values: map[string]string{"name": "test", "private_key": key, "fingerprint": "test"},
+			want: map[string]string{
+				"name": "test", "private_key": key, "passphrase": "", "note": "",
+				"public_key": getAuthorizedKey(pub), "fingerprint": ssh.FingerprintSHA256(pub),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SSHKey.Normalize(tt.values)
+			assert.True(t, errors.Is(err, tt.wantErr))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSSHKey(t *testing.T) {
+	key, pub := getTestSSHKey(t)
+	got, err := ParseSSHKey(map[string]string{"private_key": key})
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(got)
+	assert.NoError(t, err)
+	assert.Equal(t, pub.Marshal(), signer.PublicKey().Marshal())
+
+	encKey, _ := getTestEncryptedSSHKey(t, "secret")
+	_, err = ParseSSHKey(map[string]string{"private_key": encKey})
+	var missing *ssh.PassphraseMissingError
+	assert.True(t, errors.As(err, &missing))
+}
+
+// getTestSSHKey returns the base64-encoded key file of the new key, and its public key.
+func getTestSSHKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+	pk, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), pub
+}
+
+// getTestEncryptedSSHKey returns the base64-encoded key file of the new key encrypted with the passphrase.
+// The legacy PEM encryption is used, so the file doesn't hold the public key.
+func getTestEncryptedSSHKey(t *testing.T, passphrase string) (string, ssh.PublicKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The legacy encryption is the only one the standard library writes.
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte(passphrase), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(block)), pub
+}
+
+func getAuthorizedKey(pub ssh.PublicKey) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+}
@@ -0,0 +1,16 @@
+package models
+
+type NameChangeRequest struct {
+	Name string `json:"name"`
+}
+
+// PasswordChangeRequest holds the user's private key encrypted with the new password, if the user has the key pair.
+type PasswordChangeRequest struct {
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+	PrivateKey  []byte `json:"private_key,omitempty"`
+}
+
+type AccountDeleteRequest struct {
+	Password string `json:"password"`
+}
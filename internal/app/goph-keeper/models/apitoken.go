@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type TokenScope struct {
+	ReadOnly bool     `json:"read_only"`
+	Types    []string `json:"types,omitempty"`
+}
+
+type APITokenRequest struct {
+	Name      string     `json:"name"`
+	Scope     TokenScope `json:"scope"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+type APITokenResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scope     TokenScope `json:"scope"`
+	Token     string     `json:"token,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// Allows checks if the scope permits the access to the items of the specified type.
+// The read-only scope permits reading only, the empty types list permits all the item types.
+func (s TokenScope) Allows(itemType string, write bool) bool {
+	if write && s.ReadOnly {
+		return false
+	}
+	if len(s.Types) == 0 {
+		return true
+	}
+	for _, t := range s.Types {
+		if t == itemType {
+			return true
+		}
+	}
+	return false
+}
+
+func (t APITokenResponse) TableRow() []string {
+	access := "read-write"
+	if t.Scope.ReadOnly {
+		access = "read-only"
+	}
+	types := "all"
+	if len(t.Scope.Types) > 0 {
+		types = strings.Join(t.Scope.Types, ", ")
+	}
+	return []string{
+		t.ID, t.Name, access, types,
+		t.CreatedAt.Local().Format(time.RFC822), t.ExpiresAt.Local().Format(time.RFC822),
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import "encoding/json"
+
+// MaxBatchOperations limits the number of operations in the batch request.
+const MaxBatchOperations = 1000
+
+// The operations of the batch request.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation creates, updates or deletes the single item.
+// The Type is one of the item types of the API token scope, e.g. "card".
+// The Data holds the item request of the type, and is omitted by the delete operation.
+type BatchOperation struct {
+	Op   string          `json:"op"`
+	Type string          `json:"type"`
+	ID   string          `json:"id,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// BatchRequest holds the operations executed in order.
+// The atomic batch is rolled back completely on the first failed operation.
+type BatchRequest struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult is the outcome of the operation, with the status code the single item request would respond with.
+type BatchResult struct {
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchResponse holds the results in the order of the operations.
+// The atomic batch isn't committed if any of the operations failed.
+type BatchResponse struct {
+	Committed bool          `json:"committed"`
+	Results   []BatchResult `json:"results"`
+}
+
+// NewBatchOperation returns the operation with the item request encoded as its data.
+func NewBatchOperation(op, itemType, id string, data any) (BatchOperation, error) {
+	o := BatchOperation{Op: op, Type: itemType, ID: id}
+	if data == nil {
+		return o, nil
+	}
+
+	b, err := json.Marshal(data)
+	o.Data = b
+	return o, err
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// EmergencyHeader is the request header selecting the emergency contact whose granted access the request uses.
+// The storage requests with the header read the owner's personal vault, and can't change it.
+const EmergencyHeader = "X-Emergency-Access"
+
+type EmergencyContactRequest struct {
+	User      string `json:"user"`
+	WaitHours int    `json:"wait_hours"`
+}
+
+type EmergencyContactResponse struct {
+	ID          string    `json:"id"`
+	Owner       string    `json:"owner"`
+	Grantee     string    `json:"grantee"`
+	WaitHours   int       `json:"wait_hours"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at,omitempty"`
+	GrantsAt    time.Time `json:"grants_at,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type EmergencyEventResponse struct {
+	ContactID string    `json:"contact_id"`
+	Owner     string    `json:"owner"`
+	Grantee   string    `json:"grantee"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c EmergencyContactResponse) TableRow() []string {
+	grantsAt := ""
+	if !c.GrantsAt.IsZero() {
+		grantsAt = c.GrantsAt.Local().Format(time.RFC822)
+	}
+	return []string{c.ID, c.Owner, c.Grantee, strconv.Itoa(c.WaitHours) + "h", c.Status, grantsAt}
+}
+
+func (e EmergencyEventResponse) TableRow() []string {
+	return []string{e.CreatedAt.Local().Format(time.RFC822), e.Owner, e.Grantee, e.Action}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+)
+
+// ItemRequest holds the values of the item fields keyed by the field names, along with the custom fields.
+// It's encoded as the flat object, e.g. {"name": "...", "note": "...", "custom": [...]}.
+type ItemRequest struct {
+	Fields map[string]string
+	Custom []items.CustomField
+}
+
+// ItemResponse is the stored item. It's encoded as the flat object holding the field values
+// along with the ID, the custom fields and the sharing flags, so the payloads of all the types look alike.
+type ItemResponse struct {
+	ID       string
+	Fields   map[string]string
+	Custom   []items.CustomField
+	Shared   bool
+	ReadOnly bool
+}
+
+type itemMeta struct {
+	ID       string              `json:"id"`
+	Custom   []items.CustomField `json:"custom"`
+	Shared   bool                `json:"shared"`
+	ReadOnly bool                `json:"read_only"`
+}
+
+func (i ItemRequest) MarshalJSON() ([]byte, error) {
+	return marshalItem(i.Fields, i.Custom, nil)
+}
+
+func (i *ItemRequest) UnmarshalJSON(b []byte) error {
+	var meta itemMeta
+	fields, err := unmarshalItem(b, &meta)
+	if err != nil {
+		return err
+	}
+	*i = ItemRequest{Fields: fields, Custom: meta.Custom}
+	return nil
+}
+
+func (i ItemResponse) MarshalJSON() ([]byte, error) {
+	return marshalItem(i.Fields, i.Custom, map[string]any{"id": i.ID, "shared": i.Shared, "read_only": i.ReadOnly})
+}
+
+func (i *ItemResponse) UnmarshalJSON(b []byte) error {
+	var meta itemMeta
+	fields, err := unmarshalItem(b, &meta)
+	if err != nil {
+		return err
+	}
+	*i = ItemResponse{ID: meta.ID, Fields: fields, Custom: meta.Custom, Shared: meta.Shared, ReadOnly: meta.ReadOnly}
+	return nil
+}
+
+// TableRow returns the values in the order of the type header.
+// The files are shown by their size, since their content isn't printable.
+func (i ItemResponse) TableRow(t items.Type) []string {
+	row := make([]string, 0, len(t.Fields)+1)
+	row = append(row, i.ID)
+	for _, f := range t.Fields {
+		v := i.Fields[f.Name]
+		switch {
+		case f.Name == items.FieldName:
+			v = getItemName(v, i.Shared, i.ReadOnly)
+		case f.Kind == items.KindFile && v != "":
+			if b, err := base64.StdEncoding.DecodeString(v); err == nil {
+				v = fmt.Sprintf("%d bytes", len(b))
+			}
+		}
+		row = append(row, v)
+	}
+	return row
+}
+
+// CustomColumn returns the custom fields as the single table column, one "name: value" line per field.
+func (i ItemResponse) CustomColumn() string {
+	lines := make([]string, 0, len(i.Custom))
+	for _, f := range i.Custom {
+		lines = append(lines, f.Name+": "+f.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func marshalItem(fields map[string]string, custom []items.CustomField, meta map[string]any) ([]byte, error) {
+	obj := make(map[string]any, len(fields)+len(meta)+1)
+	for k, v := range fields {
+		obj[k] = v
+	}
+	if len(custom) > 0 {
+		obj["custom"] = custom
+	}
+	for k, v := range meta {
+		obj[k] = v
+	}
+	return json.Marshal(obj)
+}
+
+// unmarshalItem decodes the metadata of the flat item object, and returns the rest of its string values.
+func unmarshalItem(b []byte, meta *itemMeta) (map[string]string, error) {
+	if err := json.Unmarshal(b, meta); err != nil {
+		return nil, err
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if s, ok := v.(string); ok && k != "id" {
+			fields[k] = s
+		}
+	}
+	return fields, nil
+}
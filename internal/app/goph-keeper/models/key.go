@@ -0,0 +1,18 @@
+package models
+
+// KeyPairRequest holds the user's X25519 key pair.
+// The private key is encrypted with the user's password on the client, the server never sees it in plain.
+type KeyPairRequest struct {
+	PublicKey  []byte `json:"public_key"`
+	PrivateKey []byte `json:"private_key"`
+}
+
+type KeyPairResponse struct {
+	PublicKey  []byte `json:"public_key"`
+	PrivateKey []byte `json:"private_key"`
+}
+
+type PublicKeyResponse struct {
+	User      string `json:"user"`
+	PublicKey []byte `json:"public_key"`
+}
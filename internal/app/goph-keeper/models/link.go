@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// LinkRequest creates the one-time link either to the stored item of the specified type, or to the ad-hoc text.
+type LinkRequest struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	ItemID    string    `json:"item_id"`
+	Text      string    `json:"text"`
+	MaxViews  int       `json:"max_views"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type LinkResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url,omitempty"`
+	MaxViews  int       `json:"max_views"`
+	Views     int       `json:"views"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LinkSecretResponse holds the secret encrypted with the key from the link URL fragment.
+type LinkSecretResponse struct {
+	Name      string    `json:"name"`
+	Data      []byte    `json:"data"`
+	ViewsLeft int       `json:"views_left"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (l LinkResponse) TableRow() []string {
+	return []string{
+		l.ID, l.Name, strconv.Itoa(l.Views) + "/" + strconv.Itoa(l.MaxViews),
+		l.CreatedAt.Local().Format(time.RFC822), l.ExpiresAt.Local().Format(time.RFC822),
+	}
+}
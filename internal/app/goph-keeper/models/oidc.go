@@ -0,0 +1,16 @@
+package models
+
+type OIDCLoginRequest struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+type OIDCLoginResponse struct {
+	URL   string `json:"url"`
+	State string `json:"state"`
+}
+
+type OIDCCallbackRequest struct {
+	State  string `json:"state"`
+	Code   string `json:"code"`
+	Device string `json:"device,omitempty"`
+}
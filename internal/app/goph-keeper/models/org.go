@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// VaultHeader is the request header selecting the organization collection the storage request operates on.
+// The requests without the header operate on the user's personal vault.
+const VaultHeader = "X-Vault"
+
+type OrgRequest struct {
+	Name string `json:"name"`
+}
+
+type OrgResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemberRequest struct {
+	User string `json:"user"`
+	Role string `json:"role"`
+}
+
+type MemberResponse struct {
+	User      string    `json:"user"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CollectionRequest struct {
+	Name string `json:"name"`
+}
+
+type CollectionResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (o OrgResponse) TableRow() []string {
+	return []string{o.ID, o.Name, o.Role, o.CreatedAt.Local().Format(time.RFC822)}
+}
+
+func (m MemberResponse) TableRow() []string {
+	return []string{m.User, m.Role, m.CreatedAt.Local().Format(time.RFC822)}
+}
+
+func (c CollectionResponse) TableRow() []string {
+	return []string{c.ID, c.Name, c.CreatedAt.Local().Format(time.RFC822)}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type ClientInfo struct {
+	Device    string
+	IP        string
+	UserAgent string
+}
+
+type SessionResponse struct {
+	CID       string    `json:"cid"`
+	Device    string    `json:"device"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current"`
+}
+
+func (s SessionResponse) TableRow() []string {
+	cid := s.CID
+	if s.Current {
+		cid += " (current)"
+	}
+	return []string{
+		cid, s.Device, s.IP, s.UserAgent,
+		s.CreatedAt.Local().Format(time.RFC822), s.LastSeen.Local().Format(time.RFC822),
+	}
+}
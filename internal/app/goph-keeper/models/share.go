@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type ShareRequest struct {
+	User     string `json:"user"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+type ShareResponse struct {
+	User      string    `json:"user"`
+	ReadOnly  bool      `json:"read_only"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s ShareResponse) TableRow() []string {
+	access := "read-write"
+	if s.ReadOnly {
+		access = "read-only"
+	}
+	return []string{s.User, access, s.CreatedAt.Local().Format(time.RFC822)}
+}
+
+// SealedShareRequest holds the item encrypted on the owner's client with the one-time item key.
+// The item key is sealed to the recipient's public key.
+type SealedShareRequest struct {
+	User string `json:"user"`
+	Data []byte `json:"data"`
+	Key  []byte `json:"key"`
+}
+
+type SealedItemResponse struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"item_id"`
+	Owner     string    `json:"owner"`
+	Type      string    `json:"type"`
+	Data      []byte    `json:"data"`
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s SealedItemResponse) TableRow() []string {
+	return []string{s.ID, s.Owner, s.Type, string(s.Data), s.CreatedAt.Local().Format(time.RFC822)}
+}
+
+func getItemName(name string, shared, readOnly bool) string {
+	if !shared {
+		return name
+	}
+	if readOnly {
+		return name + " (shared, read-only)"
+	}
+	return name + " (shared)"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+)
+
+type TemplateRequest struct {
+	Name   string                `json:"name"`
+	Fields []items.TemplateField `json:"fields"`
+}
+
+type TemplateResponse struct {
+	ID     string                `json:"id"`
+	Name   string                `json:"name"`
+	Fields []items.TemplateField `json:"fields"`
+}
+
+// TableRow returns the template with its fields listed as "name (kind)", the required ones marked with "*".
+func (t TemplateResponse) TableRow() []string {
+	fields := make([]string, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		field := fmt.Sprintf("%s (%s)", f.Name, f.Kind)
+		if f.Required {
+			field += "*"
+		}
+		fields = append(fields, field)
+	}
+	return []string{t.ID, t.Name, strings.Join(fields, ", ")}
+}
@@ -3,6 +3,7 @@ package models
 type UserRequest struct {
 	Name     string `json:"name"`
 	Password string `json:"password"`
+	Device   string `json:"device,omitempty"`
 }
 
 type UserResponse struct {
@@ -0,0 +1,26 @@
+package models
+
+// VaultExportRequest holds the password the exported archive is encrypted with.
+// It is chosen for the archive and isn't related to the account password.
+type VaultExportRequest struct {
+	Password string `json:"password"`
+}
+
+type VaultExportResponse struct {
+	Archive []byte `json:"archive"`
+}
+
+// VaultImportRequest holds the archive merged into the account.
+// Duplicates is the policy for the items named as the stored ones of the same type: skip, replace or keep.
+type VaultImportRequest struct {
+	Archive    []byte `json:"archive"`
+	Password   string `json:"password"`
+	Duplicates string `json:"duplicates"`
+}
+
+type VaultImportResponse struct {
+	Imported        int `json:"imported"`
+	Replaced        int `json:"replaced"`
+	Skipped         int `json:"skipped"`
+	UnknownSections int `json:"unknown_sections"`
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"crypto/x509"
 	"fmt"
+	"time"
 
 	"github.com/agodlevskii/goph-keeper/internal/pkg/cert"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 
 	log "github.com/sirupsen/logrus"
 
@@ -19,19 +22,52 @@ type ServerConfig struct {
 		Secure bool   `json:"secure" yaml:"secure" env:"SERVER_SECURE"`
 	} `json:"server" yaml:"server"`
 	Database struct {
+		URL      string `json:"url" yaml:"url" env:"DB_URL"`
 		Host     string `json:"host" yaml:"host" env:"DB_HOST"`
 		Port     int    `json:"port" yaml:"port" env:"DB_PORT"`
 		Name     string `json:"name" yaml:"name" env:"DB_NAME"`
 		User     string `json:"user" yaml:"user" env:"DB_USER"`
 		Password string `json:"password" yaml:"password" env:"DB_PASSWORD"`
+		Pool     struct {
+			MaxConns        int           `json:"max_conns" yaml:"max_conns" env:"DB_MAX_CONNS"`
+			MaxConnLifetime time.Duration `json:"max_conn_lifetime" yaml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME"`
+			MaxConnIdleTime time.Duration `json:"max_conn_idle_time" yaml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME"`
+			ConnectTimeout  time.Duration `json:"connect_timeout" yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT"`
+		} `json:"pool" yaml:"pool"`
 	} `json:"database" yaml:"database"`
+	Storage struct {
+		DataDir          string        `json:"data_dir" yaml:"data_dir" env:"DATA_DIR"`
+		SnapshotInterval time.Duration `json:"snapshot_interval" yaml:"snapshot_interval" env:"SNAPSHOT_INTERVAL"`
+	} `json:"storage" yaml:"storage"`
 	Cert struct {
 		CA   string `json:"ca" yaml:"ca" env:"CA_PATH"`
 		Cert string `json:"cert" yaml:"cert" env:"SERVER_CERT_PATH"`
 		Key  string `json:"key" yaml:"key" env:"SERVER_KEY_PATH"`
 	} `json:"cert" yaml:"cert"`
+	CertAuth struct {
+		Mode  string            `json:"mode" yaml:"mode" env:"CERT_AUTH_MODE"`
+		Field string            `json:"field" yaml:"field" env:"CERT_AUTH_FIELD"`
+		Users map[string]string `json:"users" yaml:"users"`
+	} `json:"cert_auth" yaml:"cert_auth"`
+	OIDC struct {
+		Issuer       string `json:"issuer" yaml:"issuer" env:"OIDC_ISSUER"`
+		ClientID     string `json:"client_id" yaml:"client_id" env:"OIDC_CLIENT_ID"`
+		ClientSecret string `json:"client_secret" yaml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+	} `json:"oidc" yaml:"oidc"`
+	Emergency struct {
+		Interval time.Duration `json:"interval" yaml:"interval" env:"EMERGENCY_CHECK_INTERVAL"`
+	} `json:"emergency" yaml:"emergency"`
+	Revocation struct {
+		CleanupInterval time.Duration `json:"cleanup_interval" yaml:"cleanup_interval" env:"REVOCATION_CLEANUP_INTERVAL"`
+	} `json:"revocation" yaml:"revocation"`
 }
 
+const (
+	defaultEmergencyInterval = time.Minute
+	defaultRevocationCleanup = time.Hour
+	defaultSnapshotInterval  = time.Minute * 5
+)
+
 func New(opts ...func(*ServerConfig)) *ServerConfig {
 	cfg := &ServerConfig{}
 	for _, o := range opts {
@@ -57,7 +93,12 @@ func WithFile() func(cfg *ServerConfig) {
 	}
 }
 
+// GetRepoURL returns the explicit database URL, e.g. sqlite:///var/lib/goph-keeper/keeper.db,
+// or builds the PostgreSQL one from the connection parameters.
 func (c *ServerConfig) GetRepoURL() string {
+	if c.Database.URL != "" {
+		return c.Database.URL
+	}
 	if c.Database.Host == "" {
 		return ""
 	}
@@ -67,6 +108,25 @@ func (c *ServerConfig) GetRepoURL() string {
 		db.User, db.Password, db.Host, db.Port, db.Name)
 }
 
+// GetStorageConfig returns the database URL along with the limits of the connection pool shared by the repositories,
+// and the data directory persisting the in-memory repositories used without the database.
+func (c *ServerConfig) GetStorageConfig() storage.Config {
+	pool := c.Database.Pool
+	cfg := storage.Config{
+		URL:              c.GetRepoURL(),
+		MaxConns:         pool.MaxConns,
+		MaxConnLifetime:  pool.MaxConnLifetime,
+		MaxConnIdleTime:  pool.MaxConnIdleTime,
+		ConnectTimeout:   pool.ConnectTimeout,
+		DataDir:          c.Storage.DataDir,
+		SnapshotInterval: c.Storage.SnapshotInterval,
+	}
+	if cfg.DataDir != "" && cfg.SnapshotInterval <= 0 {
+		cfg.SnapshotInterval = defaultSnapshotInterval
+	}
+	return cfg
+}
+
 func (c *ServerConfig) GetServerAddress() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
 }
@@ -82,3 +142,39 @@ func (c *ServerConfig) GetCACertPool() (*x509.CertPool, error) {
 func (c *ServerConfig) GetCertificatePaths() []string {
 	return []string{c.Cert.Cert, c.Cert.Key}
 }
+
+func (c *ServerConfig) GetCertAuthMode() string {
+	return c.CertAuth.Mode
+}
+
+func (c *ServerConfig) GetCertAuthField() string {
+	return c.CertAuth.Field
+}
+
+func (c *ServerConfig) GetCertAuthUsers() map[string]string {
+	return c.CertAuth.Users
+}
+
+func (c *ServerConfig) GetOIDCConfig() oidc.Config {
+	return oidc.Config{
+		Issuer:       c.OIDC.Issuer,
+		ClientID:     c.OIDC.ClientID,
+		ClientSecret: c.OIDC.ClientSecret,
+	}
+}
+
+// GetEmergencyInterval returns how often the server grants the due emergency access requests.
+func (c *ServerConfig) GetEmergencyInterval() time.Duration {
+	if c.Emergency.Interval <= 0 {
+		return defaultEmergencyInterval
+	}
+	return c.Emergency.Interval
+}
+
+// GetRevocationCleanupInterval returns how often the server evicts the expired tokens from the revocation list.
+func (c *ServerConfig) GetRevocationCleanupInterval() time.Duration {
+	if c.Revocation.CleanupInterval <= 0 {
+		return defaultRevocationCleanup
+	}
+	return c.Revocation.CleanupInterval
+}
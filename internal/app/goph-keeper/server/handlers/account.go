@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func (h Handler) ChangeName() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.NameChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.accountService.ChangeName(r.Context(), uid, req); err != nil {
+			if errors.Is(err, user.ErrExists) {
+				handleHTTPError(w, err, http.StatusConflict)
+			} else {
+				handleHTTPError(w, err, h.getErrorCode(err))
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(""))
+	}
+}
+
+func (h Handler) ChangePassword() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		cid := getClientID(r)
+
+		var req models.PasswordChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.accountService.ChangePassword(r.Context(), uid, cid, req); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(""))
+	}
+}
+
+func (h Handler) DeleteAccount() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.AccountDeleteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.accountService.DeleteAccount(r.Context(), uid, req); err != nil {
+			if errors.Is(err, org.ErrLastOwner) {
+				handleHTTPError(w, err, http.StatusConflict)
+			} else {
+				handleHTTPError(w, err, h.getErrorCode(err))
+			}
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "cid", Path: "/", MaxAge: -1})
+		http.SetCookie(w, &http.Cookie{Name: "uid", Path: "/", MaxAge: -1})
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(""))
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestHandler_ChangeName(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.NameChangeRequest
+		want httpRes
+	}{
+		{
+			name: "Missing name",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Name is taken",
+			req:  models.NameChangeRequest{Name: "test1"},
+			want: httpRes{code: http.StatusConflict},
+		},
+		{
+			name: "Name is changed",
+			req:  models.NameChangeRequest{Name: "test2"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, uid, _ := initAccountHandler(t)
+			r := initTestRequest(t, http.MethodPut, accountURL+"/name", "", uid, tt.req)
+			w := httptest.NewRecorder()
+
+			h.ChangeName()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.PasswordChangeRequest
+		want httpRes
+	}{
+		{
+			name: "Missing new password",
+			req:  models.PasswordChangeRequest{Password: "test"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Wrong current password",
+			req:  models.PasswordChangeRequest{Password: "wrong", NewPassword: "test2"},
+			want: httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name: "Password is changed",
+			req:  models.PasswordChangeRequest{Password: "test", NewPassword: "test2"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, uid, cid := initAccountHandler(t)
+			r := initTestRequest(t, http.MethodPut, accountURL+"/password", "", uid, tt.req)
+			r.AddCookie(&http.Cookie{Name: clientCookieName, Value: cid, Path: "/"})
+			w := httptest.NewRecorder()
+
+			h.ChangePassword()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_DeleteAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       models.AccountDeleteRequest
+		lastOwner bool
+		want      httpRes
+	}{
+		{
+			name: "Missing password",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Wrong password",
+			req:  models.AccountDeleteRequest{Password: "wrong"},
+			want: httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:      "Last owner of the organization",
+			req:       models.AccountDeleteRequest{Password: "test"},
+			lastOwner: true,
+			want:      httpRes{code: http.StatusConflict},
+		},
+		{
+			name: "Account is deleted",
+			req:  models.AccountDeleteRequest{Password: "test"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, uid, _ := initAccountHandler(t)
+			if tt.lastOwner {
+				_, err := h.orgService.CreateOrganization(context.Background(), uid, models.OrgRequest{Name: "team"})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := initTestRequest(t, http.MethodDelete, accountURL, "", uid, tt.req)
+			w := httptest.NewRecorder()
+
+			h.DeleteAccount()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func initAccountHandler(t *testing.T) (Handler, string, string) {
+	ss, us := initSessionUserMS(t)
+	as, _ := initAuthServiceWithMS(t, ss, us, models.UserRequest{Name: "test1", Password: "test1"})
+	if err := as.Register(context.Background(), models.UserRequest{Name: "test", Password: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, cid, err := as.Login(context.Background(), "", models.UserRequest{Name: "test", Password: "test"},
+		models.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, err := as.Authorize(context.Background(), cid, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := initDataMS(t)
+	em, err := emergency.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lm, err := link.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im, err := oidc.NewIdentityService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	om, err := org.NewService(nil, nil, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Handler{
+		authService:    as,
+		accountService: services.NewAccountService(storage.UnitOfWork{}, initAPITokenMS(t), ds, em, lm, im, om, ss, us),
+		orgService:     services.NewOrgService(om, us),
+	}, uid, cid
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+)
+
+func (h Handler) CreateToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.APITokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		token, err := h.apiTokenService.CreateToken(r.Context(), uid, req)
+		if err != nil {
+			if errors.Is(err, apitoken.ErrExists) {
+				handleHTTPError(w, err, http.StatusConflict)
+			} else {
+				handleHTTPError(w, err, h.getErrorCode(err))
+			}
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(token); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) DeleteToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		id := chi.URLParam(r, "id")
+
+		if err := h.apiTokenService.DeleteToken(r.Context(), uid, id); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("API token is revoked successfully"))
+	}
+}
+
+func (h Handler) GetTokens() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		tokens, err := h.apiTokenService.GetTokens(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(tokens); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
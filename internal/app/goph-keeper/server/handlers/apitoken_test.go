@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+)
+
+const tokensURL = accountURL + "/tokens"
+
+func TestHandler_CreateToken(t *testing.T) {
+	tests := []struct {
+		name string
+		req  any
+		want httpRes
+	}{
+		{
+			name: "No payload",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Missing name",
+			req:  models.APITokenRequest{ExpiresAt: time.Now().Add(time.Hour)},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Name is taken",
+			req:  models.APITokenRequest{Name: "ci", ExpiresAt: time.Now().Add(time.Hour)},
+			want: httpRes{code: http.StatusConflict},
+		},
+		{
+			name: "Token is created",
+			req:  models.APITokenRequest{Name: "deploy", ExpiresAt: time.Now().Add(time.Hour)},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := initAPITokenHandler(t)
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodPost, tokensURL, "", "test_id", tt.req)
+
+			h.CreateToken()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_DeleteToken(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want httpRes
+	}{
+		{
+			name: "Unknown ID",
+			id:   "unknown",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Existing ID",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, token := initAPITokenHandler(t)
+			if tt.id == "" {
+				tt.id = token.ID
+			}
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodDelete, tokensURL, tt.id, "test_id", nil)
+
+			h.DeleteToken()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_GetTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		want httpRes
+	}{
+		{
+			name: "Missing user ID",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "User tokens",
+			uid:  "test_id",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := initAPITokenHandler(t)
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodGet, tokensURL, "", tt.uid, nil)
+
+			h.GetTokens()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_AuthBearer(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		want   httpRes
+	}{
+		{
+			name:   "Unknown token",
+			token:  apitoken.Prefix + "unknown",
+			method: http.MethodGet,
+			want:   httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:   "Read request in scope",
+			method: http.MethodGet,
+			want:   httpRes{code: http.StatusOK},
+		},
+		{
+			name:   "Write request for read-only scope",
+			method: http.MethodPost,
+			want:   httpRes{code: http.StatusForbidden},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, token := initAPITokenHandler(t)
+			if tt.token == "" {
+				tt.token = token.Token
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, pStorageURL, nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+
+			h.Auth(h.RequireScope("password")(next)).ServeHTTP(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_RequireScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		itemType string
+		want     httpRes
+	}{
+		{
+			name:     "Type out of scope",
+			itemType: "card",
+			want:     httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:     "Type in scope",
+			itemType: "password",
+			want:     httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Handler{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, pStorageURL, nil)
+			scope := models.TokenScope{ReadOnly: true, Types: []string{"password"}}
+			r = r.WithContext(context.WithValue(r.Context(), scopeKey, scope))
+
+			h.RequireScope(tt.itemType)(next).ServeHTTP(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_RequireSession(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope bool
+		want  httpRes
+	}{
+		{
+			name:  "API token request",
+			scope: true,
+			want:  httpRes{code: http.StatusForbidden},
+		},
+		{
+			name: "Session request",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Handler{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tokensURL, nil)
+			if tt.scope {
+				r = r.WithContext(context.WithValue(r.Context(), scopeKey, models.TokenScope{}))
+			}
+
+			h.RequireSession(next).ServeHTTP(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func initAPITokenHandler(t *testing.T) (Handler, models.APITokenResponse) {
+	ts := services.NewAPITokenService(initAPITokenMS(t))
+	token, err := ts.CreateToken(context.Background(), "test_id", models.APITokenRequest{
+		Name:      "ci",
+		Scope:     models.TokenScope{ReadOnly: true, Types: []string{"password"}},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Handler{apiTokenService: ts}, token
+}
+
+func initAPITokenMS(t *testing.T) apitoken.Service {
+	ts, err := apitoken.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
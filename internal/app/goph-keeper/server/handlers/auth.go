@@ -4,36 +4,136 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
+	"strings"
 
-	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/go-chi/chi/v5"
 
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
 	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
 )
 
-type UserID string
+type (
+	UserID     string
+	TokenScope string
+	VaultID    string
+)
+
+const (
+	uidKey   = UserID("uid")
+	scopeKey = TokenScope("scope")
+	vaultKey = VaultID("vault")
+)
+
+// vaultAccess is the organization collection selected by the request, along with the user's role in it.
+// The emergency access selects the personal vault of the owner, so the items shared with the owner are excluded.
+type vaultAccess struct {
+	id        string
+	role      org.Role
+	emergency bool
+}
 
-const uidKey = UserID("uid")
+var (
+	errCredentialMissing = errors.New("the request credentials are missing")
+	errSessionRequired   = errors.New("the request requires an interactive session")
+	errTokenScope        = errors.New("the API token scope doesn't permit the request")
+	errVaultRole         = errors.New("the organization role doesn't permit the request")
+)
 
+// Auth authorizes the request by the API token or the session cookie.
+// Depending on the certificate auth mode, the client certificate may replace the missing credentials,
+// or be required to belong to the authorized user.
+// The requests authorized by the certificate alone are treated as the unrestricted API token requests.
+// If the request selects the organization vault, the user must be a member of the organization owning it.
+// The request with the emergency access header reads the owner's personal vault once the access is granted.
 func (h Handler) Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("uid")
-		if err != nil {
-			handleHTTPError(w, err, http.StatusUnauthorized)
-			return
+		uid, scope, err := h.authorizeCredentials(r)
+		if errors.Is(err, errCredentialMissing) && h.certAuth.mode == CertAuthIdentity {
+			uid, err = h.authorizeCertificate(r)
+			scope = &models.TokenScope{}
+		} else if err == nil && h.certAuth.mode == CertAuthSecondFactor {
+			var certUID string
+			if certUID, err = h.authorizeCertificate(r); err == nil && certUID != uid {
+				err = errCertificateMismatch
+			}
 		}
 
-		uid, err := h.authService.Authorize(cookie.Value)
 		if err != nil {
 			handleHTTPError(w, err, http.StatusUnauthorized)
 			return
 		}
 
 		ctx := context.WithValue(r.Context(), uidKey, uid)
+		if scope != nil {
+			ctx = context.WithValue(ctx, scopeKey, *scope)
+		}
+
+		if id := r.Header.Get(models.VaultHeader); id != "" {
+			role, vErr := h.orgService.AuthorizeVault(r.Context(), uid, id)
+			if vErr != nil {
+				handleHTTPError(w, vErr, h.getErrorCode(vErr))
+				return
+			}
+			ctx = context.WithValue(ctx, vaultKey, vaultAccess{id: id, role: role})
+		} else if id = r.Header.Get(models.EmergencyHeader); id != "" {
+			owner, eErr := h.emergencyService.AuthorizeAccess(r.Context(), uid, id)
+			if eErr != nil {
+				handleHTTPError(w, eErr, h.getErrorCode(eErr))
+				return
+			}
+			ctx = context.WithValue(ctx, vaultKey, vaultAccess{id: owner, role: org.RoleReadOnly, emergency: true})
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// RequireScope rejects the API token requests whose scope doesn't permit the access to the specified item type.
+// The requests authorized by the session cookie are not limited.
+// The changes in the organization vault are also rejected if the user's role doesn't permit writing.
+func (h Handler) RequireScope(itemType string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write := r.Method != http.MethodGet
+			scope, ok := r.Context().Value(scopeKey).(models.TokenScope)
+			if ok && !scope.Allows(itemType, write) {
+				handleHTTPError(w, errTokenScope, http.StatusForbidden)
+				return
+			}
+			if vault, vOK := r.Context().Value(vaultKey).(vaultAccess); vOK && write && !vault.role.CanWrite() {
+				handleHTTPError(w, errVaultRole, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireVaultManager rejects the organization vault requests of the users who can't manage the organization.
+// The requests to the personal vault are not limited.
+func (h Handler) RequireVaultManager(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if vault, ok := r.Context().Value(vaultKey).(vaultAccess); ok && !vault.role.CanManage() {
+			handleHTTPError(w, errVaultRole, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireSession rejects the requests authorized by an API token.
+func (h Handler) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(scopeKey).(models.TokenScope); ok {
+			handleHTTPError(w, errSessionRequired, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (h Handler) Login() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cid := getClientID(r)
@@ -44,7 +144,11 @@ func (h Handler) Login() http.HandlerFunc {
 			return
 		}
 
-		token, cid, err := h.authService.Login(r.Context(), cid, req)
+		token, cid, err := h.authService.Login(r.Context(), cid, req, models.ClientInfo{
+			Device:    req.Device,
+			IP:        getClientIP(r),
+			UserAgent: r.UserAgent(),
+		})
 		if err != nil {
 			handleHTTPError(w, err, h.getErrorCode(err))
 			return
@@ -73,6 +177,34 @@ func (h Handler) Logout() http.HandlerFunc {
 	}
 }
 
+func (h Handler) GetSessions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		sessions, err := h.authService.GetSessions(r.Context(), uid, getClientID(r))
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(sessions); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) DeleteSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		if err := h.authService.DeleteSession(r.Context(), uid, chi.URLParam(r, "cid")); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("Session is revoked successfully"))
+	}
+}
+
 func (h Handler) Register() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var u models.UserRequest
@@ -95,6 +227,36 @@ func (h Handler) Register() http.HandlerFunc {
 	}
 }
 
+func (h Handler) authorizeCredentials(r *http.Request) (string, *models.TokenScope, error) {
+	if token, ok := getBearerToken(r); ok {
+		uid, scope, err := h.apiTokenService.Authorize(r.Context(), token)
+		return uid, &scope, err
+	}
+
+	cookie, err := r.Cookie("uid")
+	if err != nil {
+		return "", nil, errCredentialMissing
+	}
+
+	uid, err := h.authService.Authorize(r.Context(), getClientID(r), cookie.Value)
+	return uid, nil, err
+}
+
+// getOwnerID returns the ID of the storage items owner, which is the selected organization vault, if any,
+// or the authorized user otherwise.
+func getOwnerID(r *http.Request) string {
+	if vault, ok := r.Context().Value(vaultKey).(vaultAccess); ok {
+		return vault.id
+	}
+	return r.Context().Value(uidKey).(string)
+}
+
+// isEmergencyAccess checks if the request reads the owner's vault through the emergency access.
+func isEmergencyAccess(r *http.Request) bool {
+	vault, ok := r.Context().Value(vaultKey).(vaultAccess)
+	return ok && vault.emergency
+}
+
 func getClientID(r *http.Request) string {
 	cid, err := r.Cookie("cid")
 	if err != nil {
@@ -102,3 +264,19 @@ func getClientID(r *http.Request) string {
 	}
 	return cid.Value
 }
+
+func getBearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return "", false
+	}
+	return strings.TrimPrefix(header, "Bearer "), true
+}
+
+func getClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
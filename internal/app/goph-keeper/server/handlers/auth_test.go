@@ -2,25 +2,30 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
 	"github.com/agodlevskii/goph-keeper/internal/pkg/jwt"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/session"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
 )
 
 func TestHandler_Auth(t *testing.T) {
-	token, err := jwt.EncodeToken("test_id", 0)
+	expToken, err := jwt.EncodeToken("bad_id", -1*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
-	expToken, err := jwt.EncodeToken("bad_id", -1*time.Hour)
+	revToken, err := jwt.EncodeToken("revoked_id", 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -36,7 +41,7 @@ func TestHandler_Auth(t *testing.T) {
 		},
 		{
 			name:   "Valid token cookie",
-			cookie: &http.Cookie{Name: userCookieName, Value: token, Path: "/"},
+			cookie: &http.Cookie{Name: userCookieName, Path: "/"},
 			want:   httpRes{code: http.StatusOK},
 		},
 		{
@@ -44,25 +49,115 @@ func TestHandler_Auth(t *testing.T) {
 			cookie: &http.Cookie{Name: userCookieName, Value: expToken, Path: "/"},
 			want:   httpRes{code: http.StatusUnauthorized},
 		},
+		{
+			name:   "Revoked session cookie",
+			cookie: &http.Cookie{Name: userCookieName, Value: revToken, Path: "/"},
+			want:   httpRes{code: http.StatusUnauthorized},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			bs, _ := initBinaryService(t, nil)
-			as, _ := initAuthService(t, models.UserRequest{})
+			is, _ := initItemService(t, nil)
+			as, token, cid := initLoggedAuthService(t)
 			h := Handler{
-				authService:   as,
-				binaryService: bs,
+				authService: as,
+				itemService: is,
 			}
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodGet, binaryURL, nil)
+			r.AddCookie(&http.Cookie{Name: clientCookieName, Value: cid, Path: "/"})
 			if tt.cookie != nil {
+				if tt.cookie.Value == "" {
+					tt.cookie.Value = token
+				}
 				r.AddCookie(tt.cookie)
 			}
 
-			h.Auth(h.GetAllBinaries()).ServeHTTP(w, r)
+			h.Auth(h.GetAllItems(items.Binary)).ServeHTTP(w, r)
+			got := w.Result()
+			assert.Equal(t, tt.want.code, got.StatusCode)
+		})
+	}
+}
+
+func TestHandler_DeleteSession(t *testing.T) {
+	tests := []struct {
+		name string
+		cid  string
+		want httpRes
+	}{
+		{
+			name: "Missing client ID",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown client ID",
+			cid:  "unknown",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Existing client ID",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, token, cid := initLoggedAuthService(t)
+			uid, err := as.Authorize(context.Background(), cid, token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want.code == http.StatusOK {
+				tt.cid = cid
+			}
+
+			h := Handler{authService: as}
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodDelete, authURL+"/sessions/"+tt.cid, "", uid, nil)
+			chi.RouteContext(r.Context()).URLParams.Add("cid", tt.cid)
+
+			h.DeleteSession()(w, r)
+			got := w.Result()
+			assert.Equal(t, tt.want.code, got.StatusCode)
+		})
+	}
+}
+
+func TestHandler_GetSessions(t *testing.T) {
+	tests := []struct {
+		name string
+		want httpRes
+	}{
+		{
+			name: "User sessions",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, token, cid := initLoggedAuthService(t)
+			uid, err := as.Authorize(context.Background(), cid, token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			h := Handler{authService: as}
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodGet, authURL+"/sessions", "", uid, nil)
+			r.AddCookie(&http.Cookie{Name: clientCookieName, Value: cid, Path: "/"})
+
+			h.GetSessions()(w, r)
 			got := w.Result()
 			assert.Equal(t, tt.want.code, got.StatusCode)
+
+			var sessions []models.SessionResponse
+			if err = json.NewDecoder(got.Body).Decode(&sessions); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, 1, len(sessions))
+			assert.Equal(t, cid, sessions[0].CID)
+			assert.True(t, sessions[0].Current)
 		})
 	}
 }
@@ -233,20 +328,53 @@ func TestHandler_Register(t *testing.T) {
 }
 
 func initAuthService(t *testing.T, req models.UserRequest) (*services.AuthService, string) {
-	as, err := services.NewAuthService("")
-	if err != nil {
-		t.Fatal(err)
-	}
+	ss, us := initSessionUserMS(t)
+	return initAuthServiceWithMS(t, ss, us, req)
+}
+
+func initAuthServiceWithMS(t *testing.T, ss session.Service, us user.Service,
+	req models.UserRequest,
+) (*services.AuthService, string) {
+	as := services.NewAuthService(ss, us)
 
-	var cid string
+	var (
+		cid string
+		err error
+	)
 	if req.Name != "" {
 		if err = as.Register(context.Background(), req); err != nil {
 			t.Fatal(err)
 		}
-		_, cid, err = as.Login(context.Background(), "", req)
+		_, cid, err = as.Login(context.Background(), "", req, models.ClientInfo{})
 		if err != nil {
 			t.Fatal(err)
 		}
 	}
 	return as, cid
 }
+
+func initLoggedAuthService(t *testing.T) (*services.AuthService, string, string) {
+	as, _ := initAuthService(t, models.UserRequest{})
+	u := models.UserRequest{Name: "test", Password: "test"}
+	if err := as.Register(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+
+	token, cid, err := as.Login(context.Background(), "", u, models.ClientInfo{Device: "test-device"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return as, token, cid
+}
+
+func initSessionUserMS(t *testing.T) (session.Service, user.Service) {
+	ss, err := session.NewService(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	us, err := user.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ss, us
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// ExecuteBatch executes the mixed operations on the items of the personal or selected organization vault.
+// The batch is rejected as a whole if the API token scope or the organization role doesn't permit any of them.
+func (h Handler) ExecuteBatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+
+		var req models.BatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if vault, ok := r.Context().Value(vaultKey).(vaultAccess); ok && !vault.role.CanWrite() {
+			handleHTTPError(w, errVaultRole, http.StatusForbidden)
+			return
+		}
+		if scope, ok := r.Context().Value(scopeKey).(models.TokenScope); ok {
+			for _, op := range req.Operations {
+				if !scope.Allows(op.Type, true) {
+					handleHTTPError(w, errTokenScope, http.StatusForbidden)
+					return
+				}
+			}
+		}
+
+		results, committed, err := h.batchService.ExecuteBatch(r.Context(), uid, req)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		res := models.BatchResponse{Committed: committed, Results: make([]models.BatchResult, 0, len(results))}
+		for _, br := range results {
+			if br.Err != nil {
+				code := h.getErrorCode(br.Err)
+				res.Results = append(res.Results, models.BatchResult{Status: code, Error: http.StatusText(code)})
+				continue
+			}
+			res.Results = append(res.Results, models.BatchResult{ID: br.ID, Status: http.StatusOK})
+		}
+
+		if err = json.NewEncoder(w).Encode(res); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestHandler_ExecuteBatch(t *testing.T) {
+	req := models.ItemRequest{Fields: map[string]string{"name": "text", "data": "test"}}
+	text, err := models.NewBatchOperation(models.BatchCreate, "text", "", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := models.BatchOperation{Op: models.BatchDelete, Type: "card", ID: "unknown"}
+
+	tests := []struct {
+		name  string
+		req   any
+		scope *models.TokenScope
+		vault *vaultAccess
+		want  httpRes
+		res   models.BatchResponse
+	}{
+		{
+			name: "Request is malformed",
+			req:  "batch",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Operations are missing",
+			req:  models.BatchRequest{},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name:  "Token scope doesn't permit one of the types",
+			req:   models.BatchRequest{Operations: []models.BatchOperation{text, missing}},
+			scope: &models.TokenScope{Types: []string{"text"}},
+			want:  httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:  "Organization role doesn't permit writing",
+			req:   models.BatchRequest{Operations: []models.BatchOperation{text}},
+			vault: &vaultAccess{id: "vaultID", role: org.RoleReadOnly},
+			want:  httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:  "Batch is executed",
+			req:   models.BatchRequest{Operations: []models.BatchOperation{text, missing}},
+			scope: &models.TokenScope{},
+			want:  httpRes{code: http.StatusOK},
+			res: models.BatchResponse{Committed: true, Results: []models.BatchResult{
+				{Status: http.StatusOK},
+				{Status: http.StatusNotFound, Error: http.StatusText(http.StatusNotFound)},
+			}},
+		},
+		{
+			name: "Atomic batch is rolled back",
+			req:  models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{text, missing}},
+			want: httpRes{code: http.StatusOK},
+			res: models.BatchResponse{Results: []models.BatchResult{
+				{Status: http.StatusFailedDependency, Error: http.StatusText(http.StatusFailedDependency)},
+				{Status: http.StatusNotFound, Error: http.StatusText(http.StatusNotFound)},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Handler{batchService: services.NewBatchService(storage.UnitOfWork{}, initDataMS(t))}
+			r := initTestRequest(t, http.MethodPost, "/api/v1/storage/batch", "", "testID", tt.req)
+			if tt.scope != nil {
+				r = r.WithContext(context.WithValue(r.Context(), scopeKey, *tt.scope))
+			}
+			if tt.vault != nil {
+				r = r.WithContext(context.WithValue(r.Context(), vaultKey, *tt.vault))
+			}
+			w := httptest.NewRecorder()
+
+			h.ExecuteBatch()(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+			if res.StatusCode != http.StatusOK {
+				return
+			}
+
+			var got models.BatchResponse
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			if tt.res.Committed {
+				assert.NotEmpty(t, got.Results[0].ID)
+				got.Results[0].ID = ""
+			}
+			assert.Equal(t, tt.res, got)
+		})
+	}
+}
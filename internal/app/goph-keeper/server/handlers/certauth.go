@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/cert"
+)
+
+type CertAuthMode string
+
+const (
+	// CertAuthOff ignores the client certificate after the TLS handshake.
+	CertAuthOff CertAuthMode = ""
+	// CertAuthIdentity authenticates the requests without credentials by the mapped client certificate.
+	CertAuthIdentity CertAuthMode = "identity"
+	// CertAuthSecondFactor requires the mapped client certificate to match the user authenticated by credentials.
+	CertAuthSecondFactor CertAuthMode = "second-factor"
+)
+
+type certAuthConfig struct {
+	mode  CertAuthMode
+	field string
+	users map[string]string
+}
+
+var (
+	ErrCertAuthMode = errors.New("unknown certificate auth mode")
+
+	errCertificateMismatch = errors.New("the client certificate doesn't belong to the authorized user")
+	errCertificateMissing  = errors.New("the verified client certificate is missing")
+	errCertificateUnmapped = errors.New("the client certificate isn't mapped to any user")
+)
+
+// WithCertAuth enables the client certificate auth in the specified mode.
+// The certificate identities from the specified field are mapped to the user IDs,
+// so renaming the user doesn't pass its certificates to the user taking the name.
+func WithCertAuth(mode, field string, users map[string]string) func(*Handler) {
+	return func(h *Handler) {
+		if field == "" {
+			field = cert.FieldSubject
+		}
+		h.certAuth = certAuthConfig{mode: CertAuthMode(mode), field: field, users: users}
+	}
+}
+
+func (c certAuthConfig) validate() error {
+	switch c.mode {
+	case CertAuthOff:
+		return nil
+	case CertAuthIdentity, CertAuthSecondFactor:
+		if c.field != cert.FieldSubject && c.field != cert.FieldSAN {
+			return ErrCertAuthMode
+		}
+		return nil
+	}
+	return ErrCertAuthMode
+}
+
+func (h Handler) authorizeCertificate(r *http.Request) (string, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", errCertificateMissing
+	}
+
+	for _, id := range cert.GetIdentities(r.TLS.VerifiedChains[0][0], h.certAuth.field) {
+		if uid, ok := h.certAuth.users[id]; ok {
+			return h.authService.AuthorizeCertificate(r.Context(), uid)
+		}
+	}
+	return "", errCertificateUnmapped
+}
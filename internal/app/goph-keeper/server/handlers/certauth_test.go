@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/cert"
+)
+
+func TestHandler_Auth_Certificate(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        CertAuthMode
+		commonName  string
+		withCookie  bool
+		requireSess bool
+		want        httpRes
+	}{
+		{
+			name:       "Mode is off, certificate only",
+			commonName: "ci-runner",
+			want:       httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:       "Identity mode, mapped certificate",
+			mode:       CertAuthIdentity,
+			commonName: "ci-runner",
+			want:       httpRes{code: http.StatusOK},
+		},
+		{
+			name:       "Identity mode, unmapped certificate",
+			mode:       CertAuthIdentity,
+			commonName: "unknown",
+			want:       httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name: "Identity mode, missing certificate",
+			mode: CertAuthIdentity,
+			want: httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:        "Identity mode, session-only route",
+			mode:        CertAuthIdentity,
+			commonName:  "ci-runner",
+			requireSess: true,
+			want:        httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:        "Identity mode, session cookie takes precedence",
+			mode:        CertAuthIdentity,
+			commonName:  "ci-runner",
+			withCookie:  true,
+			requireSess: true,
+			want:        httpRes{code: http.StatusOK},
+		},
+		{
+			name:       "Second factor mode, missing certificate",
+			mode:       CertAuthSecondFactor,
+			withCookie: true,
+			want:       httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:       "Second factor mode, certificate of another user",
+			mode:       CertAuthSecondFactor,
+			commonName: "other-runner",
+			withCookie: true,
+			want:       httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:       "Second factor mode, certificate without credentials",
+			mode:       CertAuthSecondFactor,
+			commonName: "ci-runner",
+			want:       httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name:       "Second factor mode, matching certificate",
+			mode:       CertAuthSecondFactor,
+			commonName: "ci-runner",
+			withCookie: true,
+			want:       httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, token, cid := initLoggedAuthService(t)
+			if err := as.Register(context.Background(), models.UserRequest{Name: "other", Password: "other"}); err != nil {
+				t.Fatal(err)
+			}
+			users := make(map[string]string, 2)
+			for id, u := range map[string]models.UserRequest{
+				"ci-runner":    {Name: "test", Password: "test"},
+				"other-runner": {Name: "other", Password: "other"},
+			} {
+				users[id] = getUserID(t, as, u)
+			}
+
+			h := Handler{authService: as}
+			WithCertAuth(string(tt.mode), "", users)(&h)
+
+			r := httptest.NewRequest(http.MethodGet, binaryURL, nil)
+			if tt.commonName != "" {
+				c := &x509.Certificate{Subject: pkix.Name{CommonName: tt.commonName}}
+				r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{c}}}
+			}
+			if tt.withCookie {
+				r.AddCookie(&http.Cookie{Name: clientCookieName, Value: cid, Path: "/"})
+				r.AddCookie(&http.Cookie{Name: userCookieName, Value: token, Path: "/"})
+			}
+
+			var next http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			if tt.requireSess {
+				next = h.RequireSession(next)
+			}
+
+			w := httptest.NewRecorder()
+			h.Auth(next).ServeHTTP(w, r)
+			assert.Equal(t, tt.want.code, w.Result().StatusCode)
+		})
+	}
+}
+
+// getUserID logs the user in, and returns the authorized user ID.
+func getUserID(t *testing.T, as *services.AuthService, u models.UserRequest) string {
+	t.Helper()
+	token, cid, err := as.Login(context.Background(), "", u, models.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, err := as.Authorize(context.Background(), cid, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uid
+}
+
+func TestWithCertAuth(t *testing.T) {
+	users := map[string]string{"ci-runner": "test"}
+	tests := []struct {
+		name  string
+		mode  string
+		field string
+		want  certAuthConfig
+	}{
+		{
+			name: "Default field",
+			mode: "identity",
+			want: certAuthConfig{mode: CertAuthIdentity, field: cert.FieldSubject, users: users},
+		},
+		{
+			name:  "SAN field",
+			mode:  "second-factor",
+			field: cert.FieldSAN,
+			want:  certAuthConfig{mode: CertAuthSecondFactor, field: cert.FieldSAN, users: users},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h Handler
+			WithCertAuth(tt.mode, tt.field, users)(&h)
+			assert.Equal(t, tt.want, h.certAuth)
+		})
+	}
+}
+
+func Test_certAuthConfig_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     certAuthConfig
+		wantErr error
+	}{
+		{
+			name: "Mode is off",
+		},
+		{
+			name:    "Unknown mode",
+			cfg:     certAuthConfig{mode: "password", field: cert.FieldSubject},
+			wantErr: ErrCertAuthMode,
+		},
+		{
+			name:    "Unknown field",
+			cfg:     certAuthConfig{mode: CertAuthIdentity, field: "issuer"},
+			wantErr: ErrCertAuthMode,
+		},
+		{
+			name: "Known mode and field",
+			cfg:  certAuthConfig{mode: CertAuthSecondFactor, field: cert.FieldSAN},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.cfg.validate())
+		})
+	}
+}
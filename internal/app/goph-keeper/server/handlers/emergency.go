@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+)
+
+// WithEmergencyScheduler starts the scheduler granting the emergency access requests with the waiting period passed.
+// The requests are also granted on the first access attempt, so the interval only affects the recorded grant time.
+func WithEmergencyScheduler(ctx context.Context, interval time.Duration) func(*Handler) {
+	return func(h *Handler) {
+		h.emergencyScheduler = scheduler{ctx: ctx, interval: interval}
+	}
+}
+
+func (h Handler) AddEmergencyContact() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.EmergencyContactRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		c, err := h.emergencyService.AddContact(r.Context(), uid, req)
+		if err != nil {
+			h.handleEmergencyError(w, err)
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(c); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) ApproveEmergencyRequest() http.HandlerFunc {
+	return h.changeEmergencyStatus(h.emergencyService.ApproveRequest)
+}
+
+func (h Handler) DeleteEmergencyContact() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		if err := h.emergencyService.RemoveContact(r.Context(), uid, chi.URLParam(r, "id")); err != nil {
+			h.handleEmergencyError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("Emergency contact is removed successfully"))
+	}
+}
+
+func (h Handler) GetEmergencyContacts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		contacts, err := h.emergencyService.GetContacts(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(contacts); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) GetEmergencyEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		events, err := h.emergencyService.GetEvents(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(events); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) RejectEmergencyRequest() http.HandlerFunc {
+	return h.changeEmergencyStatus(h.emergencyService.RejectRequest)
+}
+
+func (h Handler) RequestEmergencyAccess() http.HandlerFunc {
+	return h.changeEmergencyStatus(h.emergencyService.RequestAccess)
+}
+
+func (h Handler) changeEmergencyStatus(
+	change func(ctx context.Context, uid, id string) (models.EmergencyContactResponse, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		c, err := change(r.Context(), uid, chi.URLParam(r, "id"))
+		if err != nil {
+			h.handleEmergencyError(w, err)
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(c); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) handleEmergencyError(w http.ResponseWriter, err error) {
+	if errors.Is(err, emergency.ErrExists) || errors.Is(err, emergency.ErrInvalidStatus) ||
+		errors.Is(err, emergency.ErrStatusChanged) {
+		handleHTTPError(w, err, http.StatusConflict)
+	} else {
+		handleHTTPError(w, err, h.getErrorCode(err))
+	}
+}
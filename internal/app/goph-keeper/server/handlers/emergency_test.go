@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+)
+
+const emergencyURL = "/api/v1/emergency/contacts"
+
+func TestHandler_AddEmergencyContact(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.EmergencyContactRequest
+		want httpRes
+	}{
+		{
+			name: "Missing user",
+			req:  models.EmergencyContactRequest{WaitHours: 24},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown user",
+			req:  models.EmergencyContactRequest{User: "unknown", WaitHours: 24},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Contact exists",
+			req:  models.EmergencyContactRequest{User: "user", WaitHours: 24},
+			want: httpRes{code: http.StatusConflict},
+		},
+		{
+			name: "Contact is added",
+			req:  models.EmergencyContactRequest{User: "other", WaitHours: 24},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, owner, _, _ := initEmergencyHandler(t)
+			r := initTestRequest(t, http.MethodPost, emergencyURL, "", owner, tt.req)
+			w := httptest.NewRecorder()
+
+			h.AddEmergencyContact()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_AuthEmergency(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		granted bool
+		shared  bool
+		want    httpRes
+	}{
+		{
+			name:   "Access is not granted",
+			method: http.MethodGet,
+			want:   httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:    "Grantee reads the owner's vault",
+			method:  http.MethodGet,
+			granted: true,
+			want:    httpRes{code: http.StatusOK},
+		},
+		{
+			name:    "Grantee can't read the items shared with the owner",
+			method:  http.MethodGet,
+			granted: true,
+			shared:  true,
+			want:    httpRes{code: http.StatusNotFound},
+		},
+		{
+			name:    "Grantee can't write to the owner's vault",
+			method:  http.MethodPost,
+			granted: true,
+			want:    httpRes{code: http.StatusForbidden},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, owner, creds, id := initEmergencyHandler(t)
+			if tt.granted {
+				if _, err := h.emergencyService.ApproveRequest(context.Background(), owner, id); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var (
+				body   any
+				iid    string
+				handle http.Handler = h.GetAllItems(items.Text)
+			)
+			if tt.method == http.MethodPost {
+				body = models.ItemRequest{Fields: map[string]string{"name": "test", "data": "test"}}
+				handle = h.StoreItem(items.Text)
+			}
+			if tt.shared {
+				iid = getSharedItemID(t, h, owner)
+				handle = h.GetItemByID(items.Text)
+			}
+
+			r := initTestRequest(t, tt.method, textURL, iid, "", body)
+			r.Header.Set(models.EmergencyHeader, id)
+			r.AddCookie(&http.Cookie{Name: userCookieName, Value: creds[0], Path: "/"})
+			r.AddCookie(&http.Cookie{Name: clientCookieName, Value: creds[1], Path: "/"})
+			w := httptest.NewRecorder()
+
+			h.Auth(h.RequireScope("text")(handle)).ServeHTTP(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+
+			if tt.method == http.MethodGet && !tt.shared && res.StatusCode == http.StatusOK {
+				var list []models.ItemResponse
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+				if assert.Len(t, list, 1) {
+					assert.False(t, list[0].Shared)
+				}
+			}
+		})
+	}
+}
+
+// getSharedItemID returns the ID of the item shared with the owner.
+func getSharedItemID(t *testing.T, h Handler, owner string) string {
+	t.Helper()
+	list, err := h.itemService.GetAllItems(context.Background(), owner, items.Text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range list {
+		if i.Shared {
+			return i.ID
+		}
+	}
+	t.Fatal("the item shared with the owner is missing")
+	return ""
+}
+
+func TestHandler_RequestEmergencyAccess(t *testing.T) {
+	h, _, creds, id := initEmergencyHandler(t)
+	grantee, err := h.authService.Authorize(context.Background(), creds[1], creds[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []int{http.StatusNotFound, http.StatusConflict} {
+		r := initTestRequest(t, http.MethodPost, emergencyURL, "unknown", grantee, nil)
+		if want == http.StatusConflict {
+			r = initTestRequest(t, http.MethodPost, emergencyURL, id, grantee, nil)
+		}
+		w := httptest.NewRecorder()
+
+		h.RequestEmergencyAccess()(w, r)
+		assert.Equal(t, want, w.Result().StatusCode)
+	}
+}
+
+// initEmergencyHandler returns the handler with the owner's text and the pending access request of the grantee,
+// along with the owner's ID, the grantee's session credentials, and the emergency contact ID.
+func initEmergencyHandler(t *testing.T) (Handler, string, [2]string, string) {
+	ds := initDataMS(t)
+	ss, us := initSessionUserMS(t)
+	as := services.NewAuthService(ss, us)
+
+	creds := make(map[string][2]string)
+	for _, name := range []string{"owner", "user", "other"} {
+		u := models.UserRequest{Name: name, Password: "test"}
+		if err := as.Register(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+		token, cid, err := as.Login(context.Background(), "", u, models.ClientInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		creds[name] = [2]string{token, cid}
+	}
+
+	owner, err := as.Authorize(context.Background(), creds["owner"][1], creds["owner"][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	grantee, err := as.Authorize(context.Background(), creds["user"][1], creds["user"][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := as.Authorize(context.Background(), creds["other"][1], creds["other"][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The owner has the item of its own, and the item shared by the third party.
+	is := services.NewItemService(ds)
+	req := models.ItemRequest{Fields: map[string]string{"name": "test", "data": "test"}}
+	if _, err = is.StoreItem(context.Background(), owner, items.Text, req); err != nil {
+		t.Fatal(err)
+	}
+	sid, err := is.StoreItem(context.Background(), other, items.Text, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ds.ShareData(context.Background(), other, sid, owner, true); err != nil {
+		t.Fatal(err)
+	}
+
+	em, err := emergency.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := services.NewEmergencyService(em, us)
+	c, err := es.AddContact(context.Background(), owner, models.EmergencyContactRequest{User: "user", WaitHours: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = es.RequestAccess(context.Background(), grantee, c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	return Handler{authService: as, emergencyService: es, itemService: is}, owner, creds["user"], c.ID
+}
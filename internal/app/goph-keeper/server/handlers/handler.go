@@ -2,66 +2,177 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
 	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/session"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
+type IAccountService interface {
+	ChangeName(ctx context.Context, uid string, req models.NameChangeRequest) error
+	ChangePassword(ctx context.Context, uid, cid string, req models.PasswordChangeRequest) error
+	DeleteAccount(ctx context.Context, uid string, req models.AccountDeleteRequest) error
+}
+
+type IAPITokenService interface {
+	Authorize(ctx context.Context, token string) (string, models.TokenScope, error)
+	CreateToken(ctx context.Context, uid string, req models.APITokenRequest) (models.APITokenResponse, error)
+	DeleteToken(ctx context.Context, uid, id string) error
+	GetTokens(ctx context.Context, uid string) ([]models.APITokenResponse, error)
+}
+
 type IAuthService interface {
-	Authorize(token string) (string, error)
-	Login(ctx context.Context, cid string, user models.UserRequest) (string, string, error)
+	Authorize(ctx context.Context, cid, token string) (string, error)
+	AuthorizeCertificate(ctx context.Context, uid string) (string, error)
+	DeleteSession(ctx context.Context, uid, cid string) error
+	GetSessions(ctx context.Context, uid, cid string) ([]models.SessionResponse, error)
+	Login(ctx context.Context, cid string, user models.UserRequest, client models.ClientInfo) (string, string, error)
 	Logout(ctx context.Context, cid string) (bool, error)
 	Register(ctx context.Context, user models.UserRequest) error
 }
 
-type IBinaryService interface {
-	DeleteBinary(ctx context.Context, uid, id string) error
-	GetAllBinaries(ctx context.Context, uid string) ([]models.BinaryResponse, error)
-	GetBinaryByID(ctx context.Context, uid, id string) (models.BinaryResponse, error)
-	StoreBinary(ctx context.Context, uid string, data models.BinaryRequest) (string, error)
+type IBatchService interface {
+	ExecuteBatch(ctx context.Context, uid string, req models.BatchRequest) ([]services.BatchResult, bool, error)
+}
+
+type IEmergencyService interface {
+	AddContact(ctx context.Context, uid string,
+		req models.EmergencyContactRequest) (models.EmergencyContactResponse, error)
+	ApproveRequest(ctx context.Context, uid, id string) (models.EmergencyContactResponse, error)
+	AuthorizeAccess(ctx context.Context, uid, id string) (string, error)
+	GetContacts(ctx context.Context, uid string) ([]models.EmergencyContactResponse, error)
+	GetEvents(ctx context.Context, uid string) ([]models.EmergencyEventResponse, error)
+	RejectRequest(ctx context.Context, uid, id string) (models.EmergencyContactResponse, error)
+	RemoveContact(ctx context.Context, uid, id string) error
+	RequestAccess(ctx context.Context, uid, id string) (models.EmergencyContactResponse, error)
+}
+
+type IItemService interface {
+	DeleteItem(ctx context.Context, uid string, t items.Type, id string) error
+	GetAllItems(ctx context.Context, uid string, t items.Type) ([]models.ItemResponse, error)
+	GetItemByID(ctx context.Context, uid string, t items.Type, id string) (models.ItemResponse, error)
+	StoreItem(ctx context.Context, uid string, t items.Type, req models.ItemRequest) (string, error)
+	UpdateItem(ctx context.Context, uid string, t items.Type, id string, req models.ItemRequest) error
+}
+
+type IKeyService interface {
+	GetKeyPair(ctx context.Context, uid string) (models.KeyPairResponse, error)
+	GetPublicKey(ctx context.Context, name string) (models.PublicKeyResponse, error)
+	SetKeyPair(ctx context.Context, uid string, req models.KeyPairRequest) error
+}
+
+type ILinkService interface {
+	ConsumeLink(ctx context.Context, id string) (models.LinkSecretResponse, error)
+	CreateLink(ctx context.Context, uid string, req models.LinkRequest) (models.LinkResponse, string, error)
+	DeleteLink(ctx context.Context, uid, id string) error
+	GetLinks(ctx context.Context, uid string) ([]models.LinkResponse, error)
 }
 
-type ICardService interface {
-	DeleteCard(ctx context.Context, uid, id string) error
-	GetAllCards(ctx context.Context, uid string) ([]models.CardResponse, error)
-	GetCardByID(ctx context.Context, uid, id string) (models.CardResponse, error)
-	StoreCard(ctx context.Context, uid string, data models.CardRequest) (string, error)
+type IOIDCService interface {
+	FinishLogin(ctx context.Context, req models.OIDCCallbackRequest, client models.ClientInfo) (string, string, error)
+	StartLogin(ctx context.Context, req models.OIDCLoginRequest) (models.OIDCLoginResponse, error)
 }
 
-type IPasswordService interface {
-	DeletePassword(ctx context.Context, uid, id string) error
-	GetAllPasswords(ctx context.Context, uid string) ([]models.PasswordResponse, error)
-	GetPasswordByID(ctx context.Context, uid, id string) (models.PasswordResponse, error)
-	StorePassword(ctx context.Context, uid string, data models.PasswordRequest) (string, error)
+type IOrgService interface {
+	AuthorizeVault(ctx context.Context, uid, id string) (org.Role, error)
+	CreateCollection(ctx context.Context, uid, orgID string, req models.CollectionRequest) (string, error)
+	CreateOrganization(ctx context.Context, uid string, req models.OrgRequest) (string, error)
+	DeleteCollection(ctx context.Context, uid, orgID, id string) error
+	DeleteOrganization(ctx context.Context, uid, orgID string) error
+	GetCollections(ctx context.Context, uid, orgID string) ([]models.CollectionResponse, error)
+	GetMembers(ctx context.Context, uid, orgID string) ([]models.MemberResponse, error)
+	GetOrganizations(ctx context.Context, uid string) ([]models.OrgResponse, error)
+	RemoveMember(ctx context.Context, uid, orgID, name string) error
+	SetMember(ctx context.Context, uid, orgID string, req models.MemberRequest) error
 }
 
-type ITextService interface {
-	DeleteText(ctx context.Context, uid, id string) error
-	GetAllTexts(ctx context.Context, uid string) ([]models.TextResponse, error)
-	GetTextByID(ctx context.Context, uid, id string) (models.TextResponse, error)
-	StoreText(ctx context.Context, uid string, data models.TextRequest) (string, error)
+type IShareService interface {
+	DeleteSealedItem(ctx context.Context, uid, id string) error
+	GetSealedItems(ctx context.Context, uid string) ([]models.SealedItemResponse, error)
+	GetShares(ctx context.Context, uid, id string, t data.StorageType) ([]models.ShareResponse, error)
+	RevokeShare(ctx context.Context, uid, id, name string, t data.StorageType) error
+	ShareItem(ctx context.Context, uid, id string, t data.StorageType, req models.ShareRequest) error
+	ShareItemSealed(ctx context.Context, uid, id string, t data.StorageType, req models.SealedShareRequest) (string, error)
+}
+
+type ITemplateService interface {
+	DeleteTemplate(ctx context.Context, uid, id string) error
+	GetTemplates(ctx context.Context, uid string) ([]models.TemplateResponse, error)
+	StoreTemplate(ctx context.Context, uid string, req models.TemplateRequest) (string, error)
+}
+
+type IVaultService interface {
+	ExportVault(ctx context.Context, uid string, req models.VaultExportRequest) (models.VaultExportResponse, error)
+	ImportVault(ctx context.Context, uid string, req models.VaultImportRequest) (models.VaultImportResponse, error)
 }
 
 type Handler struct {
-	authService     IAuthService
-	binaryService   IBinaryService
-	cardService     ICardService
-	passwordService IPasswordService
-	textService     ITextService
+	authService      IAuthService
+	accountService   IAccountService
+	apiTokenService  IAPITokenService
+	batchService     IBatchService
+	emergencyService IEmergencyService
+	itemService      IItemService
+	keyService       IKeyService
+	linkService      ILinkService
+	oidcService      IOIDCService
+	orgService       IOrgService
+	shareService     IShareService
+	templateService  ITemplateService
+	vaultService     IVaultService
+	certAuth         certAuthConfig
+	oidcConfig       oidc.Config
+	openWAL          func(name string) (*storage.WAL, error)
+
+	emergencyScheduler scheduler
+	revocationCleanup  scheduler
+}
+
+// scheduler runs the background job every interval until the context is canceled.
+type scheduler struct {
+	ctx      context.Context
+	interval time.Duration
+}
+
+// WithRevocationCleanup starts the cleanup evicting the expired tokens from the revocation list.
+func WithRevocationCleanup(ctx context.Context, interval time.Duration) func(*Handler) {
+	return func(h *Handler) {
+		h.revocationCleanup = scheduler{ctx: ctx, interval: interval}
+	}
+}
+
+// WithWAL persists all in-memory repositories with the logs opened by the function, one log per microservice.
+func WithWAL(open func(name string) (*storage.WAL, error)) func(*Handler) {
+	return func(h *Handler) {
+		h.openWAL = open
+	}
 }
 
-func NewHandler(repoURL string) (*chi.Mux, error) {
-	h, err := initHandler(repoURL)
+func NewHandler(db *sql.DB, opts ...func(*Handler)) (*chi.Mux, error) {
+	h, err := initHandler(db, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if err = h.certAuth.validate(); err != nil {
+		return nil, err
+	}
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger, middleware.Compress(5, "/*"))
@@ -71,60 +182,203 @@ func NewHandler(repoURL string) (*chi.Mux, error) {
 			r.Post("/login", h.Login())
 			r.Post("/logout", h.Logout())
 			r.Post("/register", h.Register())
+			r.With(h.Auth, h.RequireSession).Get("/sessions", h.GetSessions())
+			r.With(h.Auth, h.RequireSession).Delete("/sessions/{cid}", h.DeleteSession())
+
+			if h.oidcService != nil {
+				r.Post("/oidc/login", h.StartOIDCLogin())
+				r.Post("/oidc/callback", h.FinishOIDCLogin())
+			}
 		})
 
-		r.With(h.Auth).Route("/storage", func(r chi.Router) {
-			r.Route("/binary", func(r chi.Router) {
-				r.Get("/", h.GetAllBinaries())
-				r.Get("/{id}", h.GetBinaryByID())
-				r.Post("/", h.StoreBinary())
-				r.Delete("/{id}", h.DeleteBinary())
+		r.With(h.Auth, h.RequireSession).Route("/account", func(r chi.Router) {
+			r.Delete("/", h.DeleteAccount())
+			r.Put("/name", h.ChangeName())
+			r.Put("/password", h.ChangePassword())
+			r.Get("/keys", h.GetKeyPair())
+			r.Put("/keys", h.SetKeyPair())
+			r.Post("/export", h.ExportVault())
+			r.Post("/import", h.ImportVault())
+
+			r.Route("/tokens", func(r chi.Router) {
+				r.Get("/", h.GetTokens())
+				r.Post("/", h.CreateToken())
+				r.Delete("/{id}", h.DeleteToken())
+			})
+		})
+
+		r.With(h.Auth).Get("/users/{name}/public-key", h.GetPublicKey())
+
+		r.With(h.Auth, h.RequireSession).Route("/emergency", func(r chi.Router) {
+			r.Get("/contacts", h.GetEmergencyContacts())
+			r.Post("/contacts", h.AddEmergencyContact())
+			r.Delete("/contacts/{id}", h.DeleteEmergencyContact())
+			r.Post("/contacts/{id}/request", h.RequestEmergencyAccess())
+			r.Post("/contacts/{id}/approve", h.ApproveEmergencyRequest())
+			r.Post("/contacts/{id}/reject", h.RejectEmergencyRequest())
+			r.Get("/events", h.GetEmergencyEvents())
+		})
+
+		r.Route("/links", func(r chi.Router) {
+			r.Post("/{id}", h.ConsumeLink())
+			r.Group(func(r chi.Router) {
+				r.Use(h.Auth, h.RequireSession)
+				r.Get("/", h.GetLinks())
+				r.Post("/", h.CreateLink())
+				r.Delete("/{id}", h.DeleteLink())
 			})
+		})
+
+		r.With(h.Auth, h.RequireSession).Route("/orgs", func(r chi.Router) {
+			r.Get("/", h.GetOrganizations())
+			r.Post("/", h.CreateOrganization())
+			r.Delete("/{org}", h.DeleteOrganization())
 
-			r.Route("/card", func(r chi.Router) {
-				r.Get("/", h.GetAllCards())
-				r.Get("/{id}", h.GetCardByID())
-				r.Post("/", h.StoreCard())
-				r.Delete("/{id}", h.DeleteCard())
+			r.Route("/{org}/members", func(r chi.Router) {
+				r.Get("/", h.GetMembers())
+				r.Post("/", h.SetMember())
+				r.Put("/{user}", h.SetMember())
+				r.Delete("/{user}", h.RemoveMember())
 			})
 
-			r.Route("/password", func(r chi.Router) {
-				r.Get("/", h.GetAllPasswords())
-				r.Get("/{id}", h.GetPasswordByID())
-				r.Post("/", h.StorePassword())
-				r.Delete("/{id}", h.DeletePassword())
+			r.Route("/{org}/collections", func(r chi.Router) {
+				r.Get("/", h.GetCollections())
+				r.Post("/", h.CreateCollection())
+				r.Delete("/{id}", h.DeleteCollection())
 			})
+		})
+
+		r.With(h.Auth, h.RequireSession).Route("/templates", func(r chi.Router) {
+			r.Get("/", h.GetTemplates())
+			r.Post("/", h.CreateTemplate())
+			r.Delete("/{id}", h.DeleteTemplate())
+		})
+
+		r.With(h.Auth).Route("/storage", func(r chi.Router) {
+			r.Post("/batch", h.ExecuteBatch())
 
-			r.Route("/text", func(r chi.Router) {
-				r.Get("/", h.GetAllTexts())
-				r.Get("/{id}", h.GetTextByID())
-				r.Post("/", h.StoreText())
-				r.Delete("/{id}", h.DeleteText())
+			r.With(h.RequireSession).Route("/sealed", func(r chi.Router) {
+				r.Get("/", h.GetSealedItems())
+				r.Delete("/{id}", h.DeleteSealedItem())
 			})
+
+			for _, t := range items.All() {
+				r.With(h.RequireScope(t.Name)).Route("/"+t.Name, h.itemRoutes(t))
+			}
 		})
 	})
 
 	return r, nil
 }
 
-func initHandler(repoURL string) (Handler, error) {
-	dataMS, err := data.NewService(repoURL)
+func initHandler(db *sql.DB, opts ...func(*Handler)) (Handler, error) {
+	var h Handler
+	for _, o := range opts {
+		o(&h)
+	}
+
+	w, err := h.getWAL("users")
+	if err != nil {
+		return Handler{}, err
+	}
+	userMS, err := user.NewService(db, w)
+	if err != nil {
+		return Handler{}, err
+	}
+
+	if w, err = h.getWAL("sessions"); err != nil {
+		return Handler{}, err
+	}
+	rw, err := h.getWAL("revocations")
+	if err != nil {
+		return Handler{}, err
+	}
+	sessionMS, err := session.NewService(db, w, rw)
+	if err != nil {
+		return Handler{}, err
+	}
+	if h.revocationCleanup.ctx != nil {
+		go sessionMS.RunRevocationCleanup(h.revocationCleanup.ctx, h.revocationCleanup.interval)
+	}
+
+	if w, err = h.getWAL("data"); err != nil {
+		return Handler{}, err
+	}
+	dataMS, err := data.NewService(db, w)
+	if err != nil {
+		return Handler{}, err
+	}
+
+	if w, err = h.getWAL("tokens"); err != nil {
+		return Handler{}, err
+	}
+	tokenMS, err := apitoken.NewService(db, w)
+	if err != nil {
+		return Handler{}, err
+	}
+
+	if w, err = h.getWAL("links"); err != nil {
+		return Handler{}, err
+	}
+	linkMS, err := link.NewService(db, w)
+	if err != nil {
+		return Handler{}, err
+	}
+
+	if w, err = h.getWAL("orgs"); err != nil {
+		return Handler{}, err
+	}
+	orgMS, err := org.NewService(db, w, dataMS)
 	if err != nil {
 		return Handler{}, err
 	}
 
-	authService, err := services.NewAuthService(repoURL)
+	if w, err = h.getWAL("emergency"); err != nil {
+		return Handler{}, err
+	}
+	emergencyMS, err := emergency.NewService(db, w)
 	if err != nil {
 		return Handler{}, err
 	}
+	if h.emergencyScheduler.ctx != nil {
+		go emergencyMS.RunScheduler(h.emergencyScheduler.ctx, h.emergencyScheduler.interval)
+	}
+
+	// The linked identities are removed with the user account even if the provider isn't configured anymore.
+	if w, err = h.getWAL("identities"); err != nil {
+		return Handler{}, err
+	}
+	var oidcMS oidc.Service
+	if h.oidcConfig.Issuer != "" {
+		if oidcMS, err = oidc.NewService(db, w, h.oidcConfig, userMS); err != nil {
+			return Handler{}, err
+		}
+		h.oidcService = services.NewOIDCService(oidcMS, sessionMS, userMS)
+	} else if oidcMS, err = oidc.NewIdentityService(db, w); err != nil {
+		return Handler{}, err
+	}
+
+	h.authService = services.NewAuthService(sessionMS, userMS)
+	h.accountService = services.NewAccountService(storage.NewUnitOfWork(db), tokenMS, dataMS, emergencyMS, linkMS,
+		oidcMS, orgMS, sessionMS, userMS)
+	h.apiTokenService = services.NewAPITokenService(tokenMS)
+	h.batchService = services.NewBatchService(storage.NewUnitOfWork(db), dataMS)
+	h.emergencyService = services.NewEmergencyService(emergencyMS, userMS)
+	h.itemService = services.NewItemService(dataMS)
+	h.keyService = services.NewKeyService(userMS)
+	h.linkService = services.NewLinkService(linkMS, dataMS)
+	h.orgService = services.NewOrgService(orgMS, userMS)
+	h.shareService = services.NewShareService(dataMS, orgMS, userMS)
+	h.templateService = services.NewTemplateService(dataMS)
+	h.vaultService = services.NewVaultService(storage.NewUnitOfWork(db), dataMS)
+	return h, nil
+}
 
-	return Handler{
-		authService:     authService,
-		binaryService:   services.NewBinaryService(dataMS),
-		cardService:     services.NewCardService(dataMS),
-		passwordService: services.NewPasswordService(dataMS),
-		textService:     services.NewTextService(dataMS),
-	}, nil
+func (h Handler) getWAL(name string) (*storage.WAL, error) {
+	if h.openWAL == nil {
+		return nil, nil
+	}
+	return h.openWAL(name)
 }
 
 func (h Handler) getErrorCode(err error) int {
@@ -134,10 +388,21 @@ func (h Handler) getErrorCode(err error) int {
 	if errors.Is(err, services.ErrWrongCredential) {
 		return http.StatusUnauthorized
 	}
-	if errors.Is(err, services.ErrBinaryNotFound) ||
-		errors.Is(err, services.ErrCardNotFound) ||
-		errors.Is(err, services.ErrPasswordNotFound) ||
-		errors.Is(err, services.ErrTextNotFound) {
+	if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrItemReadOnly) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, services.ErrBatchRolledBack) {
+		return http.StatusFailedDependency
+	}
+	if errors.Is(err, services.ErrContactNotFound) ||
+		errors.Is(err, services.ErrItemNotFound) ||
+		errors.Is(err, services.ErrKeyNotFound) ||
+		errors.Is(err, services.ErrLinkNotFound) ||
+		errors.Is(err, services.ErrOrgNotFound) ||
+		errors.Is(err, services.ErrSessionNotFound) ||
+		errors.Is(err, services.ErrTemplateNotFound) ||
+		errors.Is(err, services.ErrTokenNotFound) ||
+		errors.Is(err, services.ErrUserNotFound) {
 		return http.StatusNotFound
 	}
 	return http.StatusInternalServerError
@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,6 +28,7 @@ type httpRes struct {
 const (
 	userCookieName   = "uid"
 	clientCookieName = "cid"
+	accountURL       = "/api/v1/account"
 	authURL          = "/api/v1/auth"
 	binaryURL        = "/api/v1/storage/binary"
 	cardURL          = "/api/v1/storage/card"
@@ -65,7 +67,7 @@ func TestNewHandler(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		repoURL string
+		db      *sql.DB
 		want    want
 		wantErr bool
 	}{
@@ -79,7 +81,7 @@ func TestNewHandler(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewHandler(tt.repoURL)
+			got, err := NewHandler(tt.db)
 			log.Info(len(got.Routes()[0].Handlers))
 			if len(got.Routes()) > 0 {
 				route := got.Routes()[0]
@@ -97,7 +99,7 @@ func Test_initHandler(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		repoURL string
+		db      *sql.DB
 		want    want
 		wantErr bool
 	}{
@@ -110,7 +112,7 @@ func Test_initHandler(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := initHandler(tt.repoURL)
+			got, err := initHandler(tt.db)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			if err == nil {
@@ -139,7 +141,7 @@ func TestHandler_getErrorCode(t *testing.T) {
 		},
 		{
 			name: "Data not found",
-			err:  services.ErrBinaryNotFound,
+			err:  services.ErrItemNotFound,
 			want: http.StatusNotFound,
 		},
 		{
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+)
+
+func (h Handler) DeleteItem(t items.Type) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+
+		if err := h.itemService.DeleteItem(r.Context(), uid, t, id); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(""))
+	}
+}
+
+// GetAllItems lists the items of the type. The emergency access lists the items owned by the vault owner only,
+// since the items the third parties shared with the owner were never granted to the grantee.
+func (h Handler) GetAllItems(t items.Type) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		res, err := h.itemService.GetAllItems(r.Context(), uid, t)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+		if isEmergencyAccess(r) {
+			res = getOwnedItems(res)
+		}
+
+		if err = json.NewEncoder(w).Encode(res); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) GetItemByID(t items.Type) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+
+		res, err := h.itemService.GetItemByID(r.Context(), uid, t, id)
+		if err == nil && res.Shared && isEmergencyAccess(r) {
+			err = services.ErrItemNotFound
+		}
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(res); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) StoreItem(t items.Type) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+
+		var req models.ItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.itemService.StoreItem(r.Context(), uid, t, req)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(id))
+	}
+}
+
+func (h Handler) UpdateItem(t items.Type) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+
+		var req models.ItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.itemService.UpdateItem(r.Context(), uid, t, id, req); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(id))
+	}
+}
+
+// getOwnedItems returns the items excluding the ones shared with their owner.
+func getOwnedItems(list []models.ItemResponse) []models.ItemResponse {
+	res := make([]models.ItemResponse, 0, len(list))
+	for _, i := range list {
+		if !i.Shared {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// itemRoutes registers the routes managing the items of the specified type, along with their shares.
+func (h Handler) itemRoutes(t items.Type) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", h.GetAllItems(t))
+		r.Get("/{id}", h.GetItemByID(t))
+		r.Post("/", h.StoreItem(t))
+		r.Put("/{id}", h.UpdateItem(t))
+		r.Delete("/{id}", h.DeleteItem(t))
+		r.Route("/{id}/shares", h.shareRoutes(t.Storage))
+	}
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+func TestHandler_DeleteItem(t *testing.T) {
+	type args struct {
+		uid string
+		id  string
+	}
+	tests := []struct {
+		name string
+		repo map[string]string
+		args args
+		want httpRes
+	}{
+		{
+			name: "UID is missing",
+			args: args{id: "test"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "ID is missing",
+			args: args{uid: "testID"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Data is not present",
+			repo: map[string]string{"test1": "test1"},
+			args: args{uid: "test", id: "test"},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Data is present and deleted",
+			repo: map[string]string{"test": "test"},
+			args: args{uid: "test", id: "test"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, ids := initItemService(t, tt.repo)
+			if id, ok := ids[tt.args.id]; ok {
+				tt.args.id = id
+			}
+
+			h := Handler{itemService: is}
+			r := initTestRequest(t, http.MethodDelete, textURL, tt.args.id, tt.args.uid, nil)
+			w := httptest.NewRecorder()
+
+			h.DeleteItem(items.Text)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_GetAllItems(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		repo map[string]string
+		want httpRes
+	}{
+		{
+			name: "Missing UID",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "No data",
+			uid:  "test1",
+			repo: map[string]string{"test": "test"},
+			want: httpRes{code: http.StatusOK, resp: "[]\n"},
+		},
+		{
+			name: "Data found",
+			uid:  "test",
+			repo: map[string]string{"test": "test", "test1": "test1"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, _ := initItemService(t, tt.repo)
+			h := Handler{itemService: is}
+			r := initTestRequest(t, http.MethodGet, textURL, "", tt.uid, nil)
+			w := httptest.NewRecorder()
+
+			h.GetAllItems(items.Text)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+			if tt.want.resp != "" {
+				assert.Equal(t, tt.want.resp, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetItemByID(t *testing.T) {
+	type args struct {
+		uid string
+		id  string
+	}
+	tests := []struct {
+		name string
+		args args
+		repo map[string]string
+		want httpRes
+	}{
+		{
+			name: "Missing arguments",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Missing UID",
+			args: args{id: "test"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Missing ID",
+			args: args{uid: "test"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "No data",
+			args: args{uid: "test", id: "test"},
+			repo: map[string]string{"test1": "test1"},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Data found",
+			args: args{uid: "test", id: "test"},
+			repo: map[string]string{"test": "test"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, ids := initItemService(t, tt.repo)
+			if id, ok := ids[tt.args.id]; ok {
+				tt.args.id = id
+			}
+
+			h := Handler{itemService: is}
+			r := initTestRequest(t, http.MethodGet, textURL, tt.args.id, tt.args.uid, nil)
+			w := httptest.NewRecorder()
+
+			h.GetItemByID(items.Text)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_StoreItem(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		t    items.Type
+		req  models.ItemRequest
+		want httpRes
+	}{
+		{
+			name: "Missing UID",
+			t:    items.Text,
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Empty request",
+			uid:  "test",
+			t:    items.Text,
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Empty name",
+			uid:  "test",
+			t:    items.Binary,
+			req:  models.ItemRequest{Fields: map[string]string{"data": "dGVzdA=="}},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Empty data",
+			uid:  "test",
+			t:    items.Binary,
+			req:  models.ItemRequest{Fields: map[string]string{"name": "test"}},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Data is not encoded",
+			uid:  "test",
+			t:    items.Binary,
+			req:  models.ItemRequest{Fields: map[string]string{"name": "test", "data": "test!"}},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Data saved",
+			uid:  "test",
+			t:    items.Binary,
+			req:  models.ItemRequest{Fields: map[string]string{"name": "test", "data": "dGVzdA=="}},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, _ := initItemService(t, nil)
+			h := Handler{itemService: is}
+			r := initTestRequest(t, http.MethodPost, binaryURL, "", tt.uid, tt.req)
+			w := httptest.NewRecorder()
+
+			h.StoreItem(tt.t)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_UpdateItem(t *testing.T) {
+	type args struct {
+		uid string
+		id  string
+		req models.ItemRequest
+	}
+	tests := []struct {
+		name string
+		repo map[string]string
+		args args
+		want httpRes
+	}{
+		{
+			name: "Missing arguments",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "No data",
+			repo: map[string]string{"test1": "test1"},
+			args: args{uid: "test", id: "test1", req: models.ItemRequest{Fields: map[string]string{
+				"name": "test2", "data": "test",
+			}}},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Data is updated",
+			repo: map[string]string{"test": "test"},
+			args: args{uid: "test", id: "test", req: models.ItemRequest{Fields: map[string]string{
+				"name": "test2", "data": "test",
+			}}},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, ids := initItemService(t, tt.repo)
+			if id, ok := ids[tt.args.id]; ok {
+				tt.args.id = id
+			}
+
+			h := Handler{itemService: is}
+			r := initTestRequest(t, http.MethodPut, textURL, tt.args.id, tt.args.uid, tt.args.req)
+			w := httptest.NewRecorder()
+
+			h.UpdateItem(items.Text)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+// TestHandler_Items runs the items of the types the API has served since the first version through all their routes.
+func TestHandler_Items(t *testing.T) {
+	tests := []struct {
+		url    string
+		t      items.Type
+		fields map[string]string
+		hidden string
+	}{
+		{
+			url:    binaryURL,
+			t:      items.Binary,
+			fields: map[string]string{"name": "test", "data": "dGVzdA==", "note": "test"},
+			hidden: "data",
+		},
+		{
+			url:    cardURL,
+			t:      items.Card,
+			fields: map[string]string{"name": "test", "number": "4111111111111111", "cvv": "123", "exp_date": "12/30"},
+			hidden: "number",
+		},
+		{
+			url:    pStorageURL,
+			t:      items.Password,
+			fields: map[string]string{"name": "test", "user": "test", "password": "secret"},
+			hidden: "password",
+		},
+		{
+			url:    textURL,
+			t:      items.Text,
+			fields: map[string]string{"name": "test", "data": "secret"},
+			hidden: "data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.t.Name, func(t *testing.T) {
+			is, _ := initItemService(t, nil)
+			h := Handler{itemService: is}
+			req := models.ItemRequest{Fields: tt.fields}
+
+			w := httptest.NewRecorder()
+			h.StoreItem(tt.t)(w, initTestRequest(t, http.MethodPost, tt.url, "", "test", req))
+			assert.Equal(t, http.StatusOK, w.Code)
+			id := w.Body.String()
+
+			w = httptest.NewRecorder()
+			h.GetAllItems(tt.t)(w, initTestRequest(t, http.MethodGet, tt.url, "", "test", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			var list []models.ItemResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+			assert.Len(t, list, 1)
+
+			w = httptest.NewRecorder()
+			h.GetItemByID(tt.t)(w, initTestRequest(t, http.MethodGet, tt.url, id, "test", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			var got models.ItemResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+			assert.Equal(t, id, got.ID)
+			assert.Equal(t, tt.fields[tt.hidden], got.Fields[tt.hidden])
+			if len(list) == 1 {
+				assert.Equal(t, got, list[0])
+			}
+
+			req.Fields["name"] = "test2"
+			w = httptest.NewRecorder()
+			h.UpdateItem(tt.t)(w, initTestRequest(t, http.MethodPut, tt.url, id, "test", req))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			w = httptest.NewRecorder()
+			h.DeleteItem(tt.t)(w, initTestRequest(t, http.MethodDelete, tt.url, id, "test", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			w = httptest.NewRecorder()
+			h.GetItemByID(tt.t)(w, initTestRequest(t, http.MethodGet, tt.url, id, "test", nil))
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
+
+// initItemService stores the texts named by the repo keys for the users the keys are mapped to.
+// It returns the server-side IDs of the texts, keyed by their names.
+func initItemService(t *testing.T, repo map[string]string) (*services.ItemService, map[string]string) {
+	s := services.NewItemService(initDataMS(t))
+	ids := make(map[string]string, len(repo))
+	for name, uid := range repo {
+		id, err := s.StoreItem(context.Background(), uid, items.Text, models.ItemRequest{Fields: map[string]string{
+			"name": name, "data": "test",
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = id
+	}
+	return s, ids
+}
+
+func initDataMS(t *testing.T) data.Service {
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
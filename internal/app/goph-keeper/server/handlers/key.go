@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+func (h Handler) GetKeyPair() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		kp, err := h.keyService.GetKeyPair(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(kp); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) GetPublicKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		key, err := h.keyService.GetPublicKey(r.Context(), name)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(key); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) SetKeyPair() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.KeyPairRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.keyService.SetKeyPair(r.Context(), uid, req); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("The key pair is stored successfully"))
+	}
+}
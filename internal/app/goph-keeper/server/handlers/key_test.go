@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestHandler_SetKeyPair(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.KeyPairRequest
+		want httpRes
+	}{
+		{
+			name: "Invalid key pair",
+			req:  models.KeyPairRequest{PublicKey: []byte("public")},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Key pair is stored",
+			req:  models.KeyPairRequest{PublicKey: bytes.Repeat([]byte{1}, 32), PrivateKey: []byte("private")},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, uid := initKeyHandler(t)
+			r := initTestRequest(t, http.MethodPut, accountURL+"/keys", "", uid, tt.req)
+			w := httptest.NewRecorder()
+
+			h.SetKeyPair()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_GetPublicKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		withKey bool
+		want    httpRes
+	}{
+		{
+			name: "Unknown user",
+			user: "unknown",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "User without the key pair",
+			user: "test",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name:    "Public key is returned",
+			user:    "test",
+			withKey: true,
+			want:    httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, uid := initKeyHandler(t)
+			if tt.withKey {
+				err := h.keyService.SetKeyPair(context.Background(), uid, models.KeyPairRequest{
+					PublicKey:  bytes.Repeat([]byte{1}, 32),
+					PrivateKey: []byte("private"),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := initTestRequest(t, http.MethodGet, "/api/v1/users/"+tt.user+"/public-key", "", uid, nil)
+			chi.RouteContext(r.Context()).URLParams.Add("name", tt.user)
+			w := httptest.NewRecorder()
+
+			h.GetPublicKey()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func initKeyHandler(t *testing.T) (Handler, string) {
+	_, us := initSessionUserMS(t)
+	if err := us.AddUser(context.Background(), user.User{Name: "test", Password: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := us.GetUserByName(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Handler{keyService: services.NewKeyService(us)}, u.ID
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+const linksPath = "/api/v1/links/"
+
+// ConsumeLink returns the encrypted secret of the link and counts the view.
+// The route doesn't require the authorization, and is served for POST only,
+// so the link previews fetching the URL don't burn the views.
+func (h Handler) ConsumeLink() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		secret, err := h.linkService.ConsumeLink(r.Context(), id)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(secret); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+// CreateLink returns the link URL with the decryption key in the fragment.
+// The key is never stored on the server, and the fragment isn't sent back when the link is opened.
+func (h Handler) CreateLink() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.LinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		l, key, err := h.linkService.CreateLink(r.Context(), uid, req)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		l.URL = getLinkURL(r, l.ID, key)
+		if err = json.NewEncoder(w).Encode(l); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) DeleteLink() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		id := chi.URLParam(r, "id")
+
+		if err := h.linkService.DeleteLink(r.Context(), uid, id); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("The link is revoked successfully"))
+	}
+}
+
+func (h Handler) GetLinks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		links, err := h.linkService.GetLinks(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(links); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func getLinkURL(r *http.Request, id, key string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + linksPath + id + "#" + key
+}
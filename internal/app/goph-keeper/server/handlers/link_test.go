@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+)
+
+const linkURL = "/api/v1/links"
+
+func TestHandler_CreateLink(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.LinkRequest
+		want httpRes
+	}{
+		{
+			name: "Missing secret",
+			req:  models.LinkRequest{MaxViews: 1, ExpiresAt: time.Now().Add(time.Hour)},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown item",
+			req:  models.LinkRequest{Type: "text", ItemID: "unknown", MaxViews: 1, ExpiresAt: time.Now().Add(time.Hour)},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Link is created",
+			req:  models.LinkRequest{Text: "secret", MaxViews: 1, ExpiresAt: time.Now().Add(time.Hour)},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := initLinkHandler(t)
+			r := initTestRequest(t, http.MethodPost, linkURL, "", "owner", tt.req)
+			w := httptest.NewRecorder()
+
+			h.CreateLink()(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+			if res.StatusCode != http.StatusOK {
+				return
+			}
+
+			var l models.LinkResponse
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&l))
+			assert.True(t, strings.HasPrefix(l.URL, "http://example.com"+linksPath+l.ID+"#"))
+		})
+	}
+}
+
+func TestHandler_ConsumeLink(t *testing.T) {
+	h := initLinkHandler(t)
+	l, _, err := h.linkService.CreateLink(context.Background(), "owner", models.LinkRequest{
+		Text:      "secret",
+		MaxViews:  1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []int{http.StatusOK, http.StatusNotFound} {
+		r := initTestRequest(t, http.MethodPost, linkURL, l.ID, "", nil)
+		w := httptest.NewRecorder()
+
+		h.ConsumeLink()(w, r)
+		res := w.Result()
+		assert.Equal(t, code, res.StatusCode)
+		_ = res.Body.Close()
+	}
+}
+
+func initLinkHandler(t *testing.T) Handler {
+	ls, err := link.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Handler{linkService: services.NewLinkService(ls, initDataMS(t))}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+)
+
+// WithOIDC enables the single sign-on through the specified OpenID Connect provider.
+func WithOIDC(cfg oidc.Config) func(*Handler) {
+	return func(h *Handler) {
+		h.oidcConfig = cfg
+	}
+}
+
+func (h Handler) StartOIDCLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.OIDCLoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		res, err := h.oidcService.StartLogin(r.Context(), req)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(res); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) FinishOIDCLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.OIDCCallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		token, cid, err := h.oidcService.FinishLogin(r.Context(), req, models.ClientInfo{
+			Device:    req.Device,
+			IP:        getClientIP(r),
+			UserAgent: r.UserAgent(),
+		})
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "cid", Value: cid, Path: "/"})
+		http.SetCookie(w, &http.Cookie{Name: "uid", Value: token, Path: "/"})
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(token))
+	}
+}
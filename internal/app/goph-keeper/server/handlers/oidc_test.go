@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc/oidctest"
+)
+
+const oidcRedirectURI = "http://127.0.0.1:8080/callback"
+
+func TestHandler_StartOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.OIDCLoginRequest
+		want httpRes
+	}{
+		{
+			name: "Missing redirect URI",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Login is started",
+			req:  models.OIDCLoginRequest{RedirectURI: oidcRedirectURI},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := initOIDCHandler(t)
+			r := initTestRequest(t, http.MethodPost, authURL+"/oidc/login", "", "", tt.req)
+			w := httptest.NewRecorder()
+
+			h.StartOIDCLogin()(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+
+			if res.StatusCode == http.StatusOK {
+				var got models.OIDCLoginResponse
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+				assert.NotEmpty(t, got.URL)
+				assert.NotEmpty(t, got.State)
+			}
+		})
+	}
+}
+
+func TestHandler_FinishOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.OIDCCallbackRequest
+		want httpRes
+	}{
+		{
+			name: "Missing code",
+			req:  models.OIDCCallbackRequest{State: "state"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown state",
+			req:  models.OIDCCallbackRequest{State: "unknown", Code: "code"},
+			want: httpRes{code: http.StatusUnauthorized},
+		},
+		{
+			name: "User is logged in",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, idp := initOIDCHandler(t)
+			if tt.req.State == "" {
+				tt.req = startTestOIDCLogin(t, h, idp)
+			}
+
+			r := initTestRequest(t, http.MethodPost, authURL+"/oidc/callback", "", "", tt.req)
+			w := httptest.NewRecorder()
+
+			h.FinishOIDCLogin()(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+			assert.Equal(t, res.StatusCode == http.StatusOK, len(res.Cookies()) == 2)
+		})
+	}
+}
+
+func initOIDCHandler(t *testing.T) (Handler, *oidctest.Server) {
+	idp, err := oidctest.NewServer("goph-keeper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+
+	ss, us := initSessionUserMS(t)
+	oidcMS, err := oidc.NewService(nil, nil, oidc.Config{Issuer: idp.URL, ClientID: idp.ClientID}, us)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Handler{oidcService: services.NewOIDCService(oidcMS, ss, us)}, idp
+}
+
+func startTestOIDCLogin(t *testing.T, h Handler, idp *oidctest.Server) models.OIDCCallbackRequest {
+	r := initTestRequest(t, http.MethodPost, authURL+"/oidc/login", "", "",
+		models.OIDCLoginRequest{RedirectURI: oidcRedirectURI})
+	w := httptest.NewRecorder()
+	h.StartOIDCLogin()(w, r)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	var login models.OIDCLoginResponse
+	if err := json.NewDecoder(res.Body).Decode(&login); err != nil {
+		t.Fatal(err)
+	}
+
+	redirect, err := idp.Authorize(login.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.OIDCCallbackRequest{State: redirect.Query().Get("state"), Code: redirect.Query().Get("code")}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+)
+
+func (h Handler) CreateOrganization() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.OrgRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.orgService.CreateOrganization(r.Context(), uid, req)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(id))
+	}
+}
+
+func (h Handler) DeleteOrganization() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		if err := h.orgService.DeleteOrganization(r.Context(), uid, chi.URLParam(r, "org")); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("Organization is deleted successfully"))
+	}
+}
+
+func (h Handler) GetOrganizations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		orgs, err := h.orgService.GetOrganizations(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(orgs); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) GetMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		members, err := h.orgService.GetMembers(r.Context(), uid, chi.URLParam(r, "org"))
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(members); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+// SetMember adds the member to the organization or changes the member's role.
+// If the route specifies the user name, it takes precedence over the one in the request body.
+func (h Handler) SetMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.MemberRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+		if name := chi.URLParam(r, "user"); name != "" {
+			req.User = name
+		}
+
+		if err := h.orgService.SetMember(r.Context(), uid, chi.URLParam(r, "org"), req); err != nil {
+			h.handleOrgError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("Member is saved successfully"))
+	}
+}
+
+func (h Handler) RemoveMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		err := h.orgService.RemoveMember(r.Context(), uid, chi.URLParam(r, "org"), chi.URLParam(r, "user"))
+		if err != nil {
+			h.handleOrgError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("Member is removed successfully"))
+	}
+}
+
+func (h Handler) CreateCollection() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+
+		var req models.CollectionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.orgService.CreateCollection(r.Context(), uid, chi.URLParam(r, "org"), req)
+		if err != nil {
+			h.handleOrgError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(id))
+	}
+}
+
+func (h Handler) DeleteCollection() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		err := h.orgService.DeleteCollection(r.Context(), uid, chi.URLParam(r, "org"), chi.URLParam(r, "id"))
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("Collection is deleted successfully"))
+	}
+}
+
+func (h Handler) GetCollections() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		collections, err := h.orgService.GetCollections(r.Context(), uid, chi.URLParam(r, "org"))
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(collections); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) handleOrgError(w http.ResponseWriter, err error) {
+	if errors.Is(err, org.ErrExists) || errors.Is(err, org.ErrLastOwner) {
+		handleHTTPError(w, err, http.StatusConflict)
+	} else {
+		handleHTTPError(w, err, h.getErrorCode(err))
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+)
+
+const orgsURL = "/api/v1/orgs"
+
+type testOrg struct {
+	id         string
+	collection string
+	creds      map[string][2]string
+}
+
+func TestHandler_AuthVault(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   string
+		method string
+		vault  string
+		want   httpRes
+	}{
+		{
+			name:   "Unknown vault",
+			user:   "owner",
+			method: http.MethodGet,
+			vault:  "unknown",
+			want:   httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:   "User is not a member",
+			user:   "outsider",
+			method: http.MethodGet,
+			want:   httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:   "Read-only member reads the vault",
+			user:   "reader",
+			method: http.MethodGet,
+			want:   httpRes{code: http.StatusOK},
+		},
+		{
+			name:   "Read-only member can't write to the vault",
+			user:   "reader",
+			method: http.MethodPost,
+			want:   httpRes{code: http.StatusForbidden},
+		},
+		{
+			name:   "Owner writes to the vault",
+			user:   "owner",
+			method: http.MethodPost,
+			want:   httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, o := initOrgHandler(t)
+			if tt.vault == "" {
+				tt.vault = o.collection
+			}
+
+			var (
+				body   any
+				handle http.Handler = h.GetAllItems(items.Text)
+			)
+			if tt.method == http.MethodPost {
+				body = models.ItemRequest{Fields: map[string]string{"name": "team", "data": "secret"}}
+				handle = h.StoreItem(items.Text)
+			}
+
+			r := initTestRequest(t, tt.method, textURL, "", "", body)
+			r.Header.Set(models.VaultHeader, tt.vault)
+			r.AddCookie(&http.Cookie{Name: userCookieName, Value: o.creds[tt.user][0], Path: "/"})
+			r.AddCookie(&http.Cookie{Name: clientCookieName, Value: o.creds[tt.user][1], Path: "/"})
+			w := httptest.NewRecorder()
+
+			h.Auth(h.RequireScope("text")(handle)).ServeHTTP(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_SetMember(t *testing.T) {
+	tests := []struct {
+		name string
+		user string
+		req  models.MemberRequest
+		want httpRes
+	}{
+		{
+			name: "Missing user",
+			req:  models.MemberRequest{Role: "member"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown user",
+			req:  models.MemberRequest{User: "unknown", Role: "member"},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Invalid role",
+			req:  models.MemberRequest{User: "outsider", Role: "guest"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Last owner is demoted",
+			user: "owner",
+			req:  models.MemberRequest{Role: "admin"},
+			want: httpRes{code: http.StatusConflict},
+		},
+		{
+			name: "Member is added",
+			req:  models.MemberRequest{User: "outsider", Role: "member"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, o := initOrgHandler(t)
+			owner, err := h.authService.Authorize(context.Background(), o.creds["owner"][1], o.creds["owner"][0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := initTestRequest(t, http.MethodPut, orgsURL+"/"+o.id+"/members", tt.user, owner, tt.req)
+			rctx := chi.RouteContext(r.Context())
+			rctx.URLParams = chi.RouteParams{}
+			rctx.URLParams.Add("org", o.id)
+			if tt.user != "" {
+				rctx.URLParams.Add("user", tt.user)
+			}
+			w := httptest.NewRecorder()
+
+			h.SetMember()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func initOrgHandler(t *testing.T) (Handler, testOrg) {
+	ds := initDataMS(t)
+	ss, us := initSessionUserMS(t)
+	as := services.NewAuthService(ss, us)
+
+	o := testOrg{creds: make(map[string][2]string)}
+	for _, name := range []string{"owner", "reader", "outsider"} {
+		u := models.UserRequest{Name: name, Password: "test"}
+		if err := as.Register(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+		token, cid, err := as.Login(context.Background(), "", u, models.ClientInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.creds[name] = [2]string{token, cid}
+	}
+
+	om, err := org.NewService(nil, nil, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os := services.NewOrgService(om, us)
+
+	owner, err := as.Authorize(context.Background(), o.creds["owner"][1], o.creds["owner"][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.id, err = os.CreateOrganization(context.Background(), owner, models.OrgRequest{Name: "team"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.SetMember(context.Background(), owner, o.id,
+		models.MemberRequest{User: "reader", Role: string(org.RoleReadOnly)}); err != nil {
+		t.Fatal(err)
+	}
+	o.collection, err = os.CreateCollection(context.Background(), owner, o.id, models.CollectionRequest{Name: "infra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Handler{authService: as, orgService: os, itemService: services.NewItemService(ds)}, o
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+func (h Handler) GetShares(t data.StorageType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+
+		shares, err := h.shareService.GetShares(r.Context(), uid, id, t)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(shares); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) RevokeShare(t data.StorageType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+		name := chi.URLParam(r, "user")
+
+		if err := h.shareService.RevokeShare(r.Context(), uid, id, name, t); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("The item share is revoked successfully"))
+	}
+}
+
+func (h Handler) ShareItem(t data.StorageType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+
+		var req models.ShareRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.shareService.ShareItem(r.Context(), uid, id, t, req); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("The item is shared successfully"))
+	}
+}
+
+func (h Handler) ShareItemSealed(t data.StorageType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := getOwnerID(r)
+		id := chi.URLParam(r, "id")
+
+		var req models.SealedShareRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		sid, err := h.shareService.ShareItemSealed(r.Context(), uid, id, t, req)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(sid))
+	}
+}
+
+func (h Handler) GetSealedItems() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		items, err := h.shareService.GetSealedItems(r.Context(), uid)
+		if err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		if err = json.NewEncoder(w).Encode(items); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
+	}
+}
+
+func (h Handler) DeleteSealedItem() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.Context().Value(uidKey).(string)
+		id := chi.URLParam(r, "id")
+
+		if err := h.shareService.DeleteSealedItem(r.Context(), uid, id); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("The sealed item is deleted successfully"))
+	}
+}
+
+// shareRoutes registers the routes managing the shares of the items of the specified type.
+// The shares can be managed within the user session only, and by the organization managers in the team vaults.
+func (h Handler) shareRoutes(t data.StorageType) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Use(h.RequireSession, h.RequireVaultManager)
+		r.Get("/", h.GetShares(t))
+		r.Post("/", h.ShareItem(t))
+		r.Post("/sealed", h.ShareItemSealed(t))
+		r.Delete("/{user}", h.RevokeShare(t))
+	}
+}
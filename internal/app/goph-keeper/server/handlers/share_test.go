@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestHandler_ShareItem(t *testing.T) {
+	tests := []struct {
+		name string
+		t    data.StorageType
+		req  models.ShareRequest
+		want httpRes
+	}{
+		{
+			name: "Missing user",
+			t:    data.SText,
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown user",
+			t:    data.SText,
+			req:  models.ShareRequest{User: "unknown"},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Item of another type",
+			t:    data.SCard,
+			req:  models.ShareRequest{User: "user"},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Item is shared",
+			t:    data.SText,
+			req:  models.ShareRequest{User: "user", ReadOnly: true},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, owner, _, id := initShareHandler(t)
+			r := initTestRequest(t, http.MethodPost, textURL, id, owner, tt.req)
+			w := httptest.NewRecorder()
+
+			h.ShareItem(tt.t)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_RevokeShare(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared bool
+		want   httpRes
+	}{
+		{
+			name: "Item is not shared",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name:   "Item share is revoked",
+			shared: true,
+			want:   httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, owner, _, id := initShareHandler(t)
+			if tt.shared {
+				err := h.shareService.ShareItem(context.Background(), owner, id, data.SText,
+					models.ShareRequest{User: "user"})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := initTestRequest(t, http.MethodDelete, textURL, id, owner, nil)
+			chi.RouteContext(r.Context()).URLParams.Add("user", "user")
+			w := httptest.NewRecorder()
+
+			h.RevokeShare(data.SText)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_UpdateSharedItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		want     httpRes
+	}{
+		{
+			name:     "Item is shared as read-only",
+			readOnly: true,
+			want:     httpRes{code: http.StatusForbidden},
+		},
+		{
+			name: "Item is shared for writing",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, owner, recipient, id := initShareHandler(t)
+			err := h.shareService.ShareItem(context.Background(), owner, id, data.SText,
+				models.ShareRequest{User: "user", ReadOnly: tt.readOnly})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := initTestRequest(t, http.MethodPut, textURL, id, recipient, models.ItemRequest{Fields: map[string]string{
+				"name": "test2",
+				"data": "test2",
+			}})
+			w := httptest.NewRecorder()
+
+			h.UpdateItem(items.Text)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_ShareItemSealed(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.SealedShareRequest
+		want httpRes
+	}{
+		{
+			name: "Missing sealed data",
+			req:  models.SealedShareRequest{User: "user"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown user",
+			req:  models.SealedShareRequest{User: "unknown", Data: []byte("data"), Key: []byte("key")},
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Item is sealed",
+			req:  models.SealedShareRequest{User: "user", Data: []byte("data"), Key: []byte("key")},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, owner, recipient, id := initShareHandler(t)
+			r := initTestRequest(t, http.MethodPost, textURL, id, owner, tt.req)
+			w := httptest.NewRecorder()
+
+			h.ShareItemSealed(data.SText)(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+			if res.StatusCode != http.StatusOK {
+				return
+			}
+
+			r = initTestRequest(t, http.MethodGet, "/api/v1/storage/sealed", "", recipient, nil)
+			w = httptest.NewRecorder()
+			h.GetSealedItems()(w, r)
+
+			var items []models.SealedItemResponse
+			assert.NoError(t, json.NewDecoder(w.Result().Body).Decode(&items))
+			assert.Len(t, items, 1)
+		})
+	}
+}
+
+func initShareHandler(t *testing.T) (Handler, string, string, string) {
+	ds := initDataMS(t)
+	_, us := initSessionUserMS(t)
+	for _, name := range []string{"owner", "user"} {
+		if err := us.AddUser(context.Background(), user.User{Name: name, Password: "test"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	owner, err := us.GetUserByName(context.Background(), "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := us.GetUserByName(context.Background(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	is := services.NewItemService(ds)
+	id, err := is.StoreItem(context.Background(), owner.ID, items.Text, models.ItemRequest{Fields: map[string]string{
+		"name": "test", "data": "test",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	om, err := org.NewService(nil, nil, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := Handler{shareService: services.NewShareService(ds, om, us), itemService: is}
+	return h, owner.ID, recipient.ID, id
+}
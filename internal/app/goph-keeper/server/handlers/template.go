@@ -2,85 +2,68 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/go-chi/chi/v5"
 
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
 	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
-
-	"github.com/go-chi/chi/v5"
 )
 
-func (h Handler) DeleteCard() http.HandlerFunc {
+// CreateTemplate stores the user's template of the custom items and returns its ID.
+// The template names are unique per user, so the template with the taken name is rejected as conflicting.
+func (h Handler) CreateTemplate() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		uid := r.Context().Value(uidKey).(string)
-		id := chi.URLParam(r, "id")
 
-		if err := h.cardService.DeleteCard(r.Context(), uid, id); err != nil {
-			handleHTTPError(w, err, h.getErrorCode(err))
+		var req models.TemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handleHTTPError(w, err, http.StatusBadRequest)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(""))
-	}
-}
-
-func (h Handler) GetAllCards() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		uid := r.Context().Value(uidKey).(string)
-		cs, err := h.cardService.GetAllCards(r.Context(), uid)
+		id, err := h.templateService.StoreTemplate(r.Context(), uid, req)
 		if err != nil {
-			handleHTTPError(w, err, h.getErrorCode(err))
+			if errors.Is(err, services.ErrTemplateExists) {
+				handleHTTPError(w, err, http.StatusConflict)
+			} else {
+				handleHTTPError(w, err, h.getErrorCode(err))
+			}
 			return
 		}
 
-		if err = json.NewEncoder(w).Encode(cs); err != nil {
-			handleHTTPError(w, err, h.getErrorCode(err))
-		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(id))
 	}
 }
 
-func (h Handler) GetCardByID() http.HandlerFunc {
+func (h Handler) DeleteTemplate() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		uid := r.Context().Value(uidKey).(string)
 		id := chi.URLParam(r, "id")
 
-		c, err := h.cardService.GetCardByID(r.Context(), uid, id)
-		if err != nil {
+		if err := h.templateService.DeleteTemplate(r.Context(), uid, id); err != nil {
 			handleHTTPError(w, err, h.getErrorCode(err))
 			return
 		}
 
-		if c.ID == "" {
-			handleHTTPError(w, services.ErrCardNotFound, http.StatusNotFound)
-			return
-		}
-
 		w.WriteHeader(http.StatusOK)
-		if err = json.NewEncoder(w).Encode(c); err != nil {
-			handleHTTPError(w, err, h.getErrorCode(err))
-		}
+		_, _ = w.Write([]byte(""))
 	}
 }
 
-func (h Handler) StoreCard() http.HandlerFunc {
+func (h Handler) GetTemplates() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		uid := r.Context().Value(uidKey).(string)
-
-		var req models.CardRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			handleHTTPError(w, err, http.StatusBadRequest)
-			return
-		}
-
-		id, err := h.cardService.StoreCard(r.Context(), uid, req)
+		templates, err := h.templateService.GetTemplates(r.Context(), uid)
 		if err != nil {
 			handleHTTPError(w, err, h.getErrorCode(err))
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(id))
+		if err = json.NewEncoder(w).Encode(templates); err != nil {
+			handleHTTPError(w, err, h.getErrorCode(err))
+		}
 	}
 }
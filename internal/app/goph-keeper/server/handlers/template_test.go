@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+)
+
+const templatesURL = "/api/v1/templates"
+
+func TestHandler_CreateTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  any
+		want httpRes
+	}{
+		{
+			name: "No payload",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Missing name",
+			req:  models.TemplateRequest{Fields: []items.TemplateField{{Name: "host"}}},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Unknown field kind",
+			req:  models.TemplateRequest{Name: "api", Fields: []items.TemplateField{{Name: "host", Kind: "ip"}}},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Name is taken",
+			req:  models.TemplateRequest{Name: "db"},
+			want: httpRes{code: http.StatusConflict},
+		},
+		{
+			name: "Template is created",
+			req:  models.TemplateRequest{Name: "api", Fields: []items.TemplateField{{Name: "host", Kind: items.KindURL}}},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := initTemplateHandler(t)
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodPost, templatesURL, "", "test_id", tt.req)
+
+			h.CreateTemplate()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_DeleteTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		uid  string
+		want httpRes
+	}{
+		{
+			name: "Unknown ID",
+			id:   "unknown",
+			uid:  "test_id",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Template of another user",
+			uid:  "other_id",
+			want: httpRes{code: http.StatusNotFound},
+		},
+		{
+			name: "Existing ID",
+			uid:  "test_id",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, id := initTemplateHandler(t)
+			if tt.id == "" {
+				tt.id = id
+			}
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodDelete, templatesURL, tt.id, tt.uid, nil)
+
+			h.DeleteTemplate()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestHandler_GetTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		want httpRes
+	}{
+		{
+			name: "Missing user ID",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "User templates",
+			uid:  "test_id",
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := initTemplateHandler(t)
+			w := httptest.NewRecorder()
+			r := initTestRequest(t, http.MethodGet, templatesURL, "", tt.uid, nil)
+
+			h.GetTemplates()(w, r)
+			res := w.Result()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+		})
+	}
+}
+
+func initTemplateHandler(t *testing.T) (Handler, string) {
+	ts := services.NewTemplateService(initDataMS(t))
+	id, err := ts.StoreTemplate(context.Background(), "test_id", models.TemplateRequest{
+		Name:   "db",
+		Fields: []items.TemplateField{{Name: "host", Kind: items.KindURL, Required: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Handler{templateService: ts}, id
+}
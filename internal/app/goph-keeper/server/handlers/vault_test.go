@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/server/services"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestHandler_ExportVault(t *testing.T) {
+	tests := []struct {
+		name string
+		req  any
+		want httpRes
+	}{
+		{
+			name: "Request is malformed",
+			req:  "password",
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Password is missing",
+			req:  models.VaultExportRequest{},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Vault is exported",
+			req:  models.VaultExportRequest{Password: "test"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := initVaultHandler(t)
+			r := initTestRequest(t, http.MethodPost, accountURL+"/export", "", "testID", tt.req)
+			w := httptest.NewRecorder()
+
+			h.ExportVault()(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+
+			if res.StatusCode == http.StatusOK {
+				var got models.VaultExportResponse
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+				assert.NotEmpty(t, got.Archive)
+			}
+		})
+	}
+}
+
+func TestHandler_ImportVault(t *testing.T) {
+	h := initVaultHandler(t)
+	exported, err := h.vaultService.ExportVault(context.Background(), "testID",
+		models.VaultExportRequest{Password: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  models.VaultImportRequest
+		want httpRes
+	}{
+		{
+			name: "Password is wrong",
+			req:  models.VaultImportRequest{Archive: exported.Archive, Password: "wrong"},
+			want: httpRes{code: http.StatusBadRequest},
+		},
+		{
+			name: "Vault is imported",
+			req:  models.VaultImportRequest{Archive: exported.Archive, Password: "test", Duplicates: "keep"},
+			want: httpRes{code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initTestRequest(t, http.MethodPost, accountURL+"/import", "", "testID1", tt.req)
+			w := httptest.NewRecorder()
+
+			h.ImportVault()(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.want.code, res.StatusCode)
+
+			if res.StatusCode == http.StatusOK {
+				var got models.VaultImportResponse
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+				assert.Equal(t, models.VaultImportResponse{Imported: 1}, got)
+			}
+		})
+	}
+}
+
+func initVaultHandler(t *testing.T) Handler {
+	ds := initDataMS(t)
+	is := services.NewItemService(ds)
+	req := models.ItemRequest{Fields: map[string]string{"name": "test", "data": "test"}}
+	if _, err := is.StoreItem(context.Background(), "testID", items.Text, req); err != nil {
+		t.Fatal(err)
+	}
+	return Handler{vaultService: services.NewVaultService(storage.UnitOfWork{}, ds)}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/account"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/session"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type AccountService struct {
+	accountMS account.Service
+}
+
+// NewAccountService returns an instance of the AccountService with pre-defined account microservice.
+func NewAccountService(uow storage.UnitOfWork, tokenMS apitoken.Service, dataMS data.Service,
+	emergencyMS emergency.Service, linkMS link.Service, oidcMS oidc.Service, orgMS org.Service,
+	sessionMS session.Service, userMS user.Service,
+) *AccountService {
+	return &AccountService{
+		accountMS: account.NewService(uow, tokenMS, dataMS, emergencyMS, linkMS, oidcMS, orgMS, sessionMS, userMS),
+	}
+}
+
+// ChangeName renames the user with the unique ID.
+// If another user with the specified name already exists, the method returns an error.
+func (s *AccountService) ChangeName(ctx context.Context, uid string, req models.NameChangeRequest) error {
+	if uid == "" || req.Name == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.accountMS.ChangeName(ctx, uid, req.Name))
+}
+
+// ChangePassword replaces the user's password, if the current one matches the stored password.
+// The private key encrypted with the new password is required if the user has the key pair.
+// All the user's sessions, except the one associated with the passed client ID, get revoked.
+func (s *AccountService) ChangePassword(ctx context.Context, uid, cid string, req models.PasswordChangeRequest) error {
+	if uid == "" || req.Password == "" || req.NewPassword == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.accountMS.ChangePassword(ctx, uid, cid, account.Payload{
+		Password:    req.Password,
+		NewPassword: req.NewPassword,
+		PrivateKey:  req.PrivateKey,
+	}))
+}
+
+// DeleteAccount removes the user with the unique ID, if the passed password matches the stored one.
+// All the user's stored data, sessions and the records linking the user to others get removed as well.
+// The last owner of an organization can't be removed until the ownership is transferred.
+func (s *AccountService) DeleteAccount(ctx context.Context, uid string, req models.AccountDeleteRequest) error {
+	if uid == "" || req.Password == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.accountMS.DeleteAccount(ctx, uid, req.Password))
+}
+
+func (s *AccountService) mapError(err error) error {
+	if errors.Is(err, account.ErrWrongCredential) {
+		return ErrWrongCredential
+	}
+	if errors.Is(err, account.ErrPrivateKeyMissing) {
+		return ErrBadArguments
+	}
+	return err
+}
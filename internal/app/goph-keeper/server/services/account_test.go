@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/account"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestNewAccountService(t *testing.T) {
+	ts := initAPITokenMS(t)
+	ds := initDataMS(t)
+	es := initEmergencyMS(t)
+	ls := initLinkMS(t)
+	ids := initIdentityMS(t)
+	os := initOrgMS(t)
+	ss, us := initSessionUserMS(t)
+	tests := []struct {
+		name string
+		want *AccountService
+	}{
+		{
+			name: "Service creation",
+			want: &AccountService{
+				accountMS: account.NewService(storage.UnitOfWork{}, ts, ds, es, ls, ids, os, ss, us),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewAccountService(storage.UnitOfWork{}, ts, ds, es, ls, ids, os, ss, us))
+		})
+	}
+}
+
+func TestAccountService_ChangeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.NameChangeRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing name",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Name is taken",
+			req:     models.NameChangeRequest{Name: "test1"},
+			wantErr: user.ErrExists,
+		},
+		{
+			name: "Name is changed",
+			req:  models.NameChangeRequest{Name: "test2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, uid, _ := initAccountService(t, initOrgMS(t))
+			assert.Equal(t, tt.wantErr, s.ChangeName(context.Background(), uid, tt.req))
+		})
+	}
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.PasswordChangeRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing new password",
+			req:     models.PasswordChangeRequest{Password: "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Wrong current password",
+			req:     models.PasswordChangeRequest{Password: "wrong", NewPassword: "test2"},
+			wantErr: ErrWrongCredential,
+		},
+		{
+			name: "Password is changed",
+			req:  models.PasswordChangeRequest{Password: "test", NewPassword: "test2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, as, uid, cid := initAccountService(t, initOrgMS(t))
+			assert.Equal(t, tt.wantErr, s.ChangePassword(context.Background(), uid, cid, tt.req))
+
+			if tt.wantErr == nil {
+				_, _, err := as.Login(context.Background(), "", models.UserRequest{Name: "test", Password: "test2"},
+					models.ClientInfo{})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAccountService_DeleteAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.AccountDeleteRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing password",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Wrong password",
+			req:     models.AccountDeleteRequest{Password: "wrong"},
+			wantErr: ErrWrongCredential,
+		},
+		{
+			name: "Account is deleted",
+			req:  models.AccountDeleteRequest{Password: "test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, as, uid, _ := initAccountService(t, initOrgMS(t))
+			assert.Equal(t, tt.wantErr, s.DeleteAccount(context.Background(), uid, tt.req))
+
+			if tt.wantErr == nil {
+				_, _, err := as.Login(context.Background(), "", models.UserRequest{Name: "test", Password: "test"},
+					models.ClientInfo{})
+				assert.Equal(t, ErrWrongCredential, err)
+			}
+		})
+	}
+}
+
+func TestAccountService_DeleteAccount_LastOwner(t *testing.T) {
+	os := initOrgMS(t)
+	s, as, uid, _ := initAccountService(t, os)
+	if _, err := os.CreateOrganization(context.Background(), uid, "team"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.DeleteAccount(context.Background(), uid, models.AccountDeleteRequest{Password: "test"})
+	assert.Equal(t, org.ErrLastOwner, err)
+	_, _, err = as.Login(context.Background(), "", models.UserRequest{Name: "test", Password: "test"},
+		models.ClientInfo{})
+	assert.NoError(t, err)
+}
+
+func initAccountService(t *testing.T, os org.Service) (*AccountService, *AuthService, string, string) {
+	ss, us := initSessionUserMS(t)
+	as := NewAuthService(ss, us)
+	for _, u := range []models.UserRequest{{Name: "test", Password: "test"}, {Name: "test1", Password: "test1"}} {
+		if err := as.Register(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	token, cid, err := as.Login(context.Background(), "", models.UserRequest{Name: "test", Password: "test"},
+		models.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, err := as.Authorize(context.Background(), cid, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewAccountService(storage.UnitOfWork{}, initAPITokenMS(t), initDataMS(t), initEmergencyMS(t), initLinkMS(t),
+		initIdentityMS(t), os, ss, us), as, uid, cid
+}
+
+func initIdentityMS(t *testing.T) oidc.Service {
+	ids, err := oidc.NewIdentityService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+)
+
+type APITokenService struct {
+	tokenMS apitoken.Service
+}
+
+var ErrTokenNotFound = errors.New("requested api token not found")
+
+// NewAPITokenService returns an instance of the APITokenService with pre-defined API token microservice.
+func NewAPITokenService(tokenMS apitoken.Service) *APITokenService {
+	return &APITokenService{tokenMS: tokenMS}
+}
+
+// Authorize looks for the stored API token and returns the user ID and scope associated with it.
+// If the token is unknown or expired, the method returns an error.
+func (s *APITokenService) Authorize(ctx context.Context, token string) (string, models.TokenScope, error) {
+	if token == "" {
+		return "", models.TokenScope{}, ErrBadArguments
+	}
+
+	t, err := s.tokenMS.Authenticate(ctx, token)
+	if err != nil {
+		if errors.Is(err, apitoken.ErrNotFound) || errors.Is(err, apitoken.ErrExpired) {
+			return "", models.TokenScope{}, ErrWrongCredential
+		}
+		return "", models.TokenScope{}, err
+	}
+	return t.UID, models.TokenScope{ReadOnly: t.ReadOnly, Types: t.Types}, nil
+}
+
+// CreateToken generates a new API token for the user.
+// The token string is returned only once and can't be restored later.
+func (s *APITokenService) CreateToken(ctx context.Context, uid string,
+	req models.APITokenRequest,
+) (models.APITokenResponse, error) {
+	if uid == "" || req.Name == "" || req.ExpiresAt.IsZero() || !s.isScopeValid(req.Scope) {
+		return models.APITokenResponse{}, ErrBadArguments
+	}
+
+	raw, t, err := s.tokenMS.CreateToken(ctx, uid, req.Name, apitoken.Scope{
+		ReadOnly: req.Scope.ReadOnly,
+		Types:    req.Scope.Types,
+	}, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, apitoken.ErrInvalidExpiry) {
+			return models.APITokenResponse{}, ErrBadArguments
+		}
+		return models.APITokenResponse{}, err
+	}
+
+	resp := s.getResponseFromModel(t)
+	resp.Token = raw
+	return resp, nil
+}
+
+// DeleteToken revokes the user's API token with the unique ID.
+func (s *APITokenService) DeleteToken(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	err := s.tokenMS.DeleteToken(ctx, uid, id)
+	if errors.Is(err, apitoken.ErrNotFound) {
+		return ErrTokenNotFound
+	}
+	return err
+}
+
+// GetTokens returns all the user's API tokens without the token strings.
+func (s *APITokenService) GetTokens(ctx context.Context, uid string) ([]models.APITokenResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	ts, err := s.tokenMS.GetUserTokens(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]models.APITokenResponse, 0, len(ts))
+	for _, t := range ts {
+		tokens = append(tokens, s.getResponseFromModel(t))
+	}
+	return tokens, nil
+}
+
+func (s *APITokenService) getResponseFromModel(t apitoken.Token) models.APITokenResponse {
+	return models.APITokenResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scope:     models.TokenScope{ReadOnly: t.ReadOnly, Types: t.Types},
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+	}
+}
+
+// isScopeValid checks if the scope lists the registered item types only.
+func (s *APITokenService) isScopeValid(scope models.TokenScope) bool {
+	for _, t := range scope.Types {
+		if _, ok := items.Lookup(t); !ok {
+			return false
+		}
+	}
+	return true
+}
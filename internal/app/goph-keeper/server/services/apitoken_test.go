@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+)
+
+func TestNewAPITokenService(t *testing.T) {
+	ts := initAPITokenMS(t)
+	tests := []struct {
+		name string
+		want *APITokenService
+	}{
+		{
+			name: "Service creation",
+			want: &APITokenService{tokenMS: ts},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewAPITokenService(ts))
+		})
+	}
+}
+
+func TestAPITokenService_Authorize(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "Missing token",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown token",
+			token:   apitoken.Prefix + "unknown",
+			wantErr: ErrWrongCredential,
+		},
+		{
+			name: "Valid token",
+			want: "test_id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAPITokenService(initAPITokenMS(t))
+			res, err := s.CreateToken(context.Background(), "test_id", models.APITokenRequest{
+				Name:      "ci",
+				Scope:     models.TokenScope{ReadOnly: true, Types: []string{"password"}},
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != "" {
+				tt.token = res.Token
+			}
+
+			got, scope, err := s.Authorize(context.Background(), tt.token)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, res.Scope, scope)
+			}
+		})
+	}
+}
+
+func TestAPITokenService_CreateToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.APITokenRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing name",
+			req:     models.APITokenRequest{ExpiresAt: time.Now().Add(time.Hour)},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Missing expiry time",
+			req:     models.APITokenRequest{Name: "ci"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Expiry time in the past",
+			req:     models.APITokenRequest{Name: "ci", ExpiresAt: time.Now().Add(-time.Hour)},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Unknown item type",
+			req: models.APITokenRequest{
+				Name:      "ci",
+				Scope:     models.TokenScope{Types: []string{"unknown"}},
+				ExpiresAt: time.Now().Add(time.Hour),
+			},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Token is created",
+			req: models.APITokenRequest{
+				Name:      "ci",
+				Scope:     models.TokenScope{Types: []string{"card", "text"}},
+				ExpiresAt: time.Now().Add(time.Hour),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAPITokenService(initAPITokenMS(t))
+			got, err := s.CreateToken(context.Background(), "test_id", tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got.Token != "")
+		})
+	}
+}
+
+func TestAPITokenService_DeleteToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr error
+	}{
+		{
+			name:    "Missing ID",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown ID",
+			id:      "unknown",
+			wantErr: ErrTokenNotFound,
+		},
+		{
+			name: "Existing ID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAPITokenService(initAPITokenMS(t))
+			res, err := s.CreateToken(context.Background(), "test_id", models.APITokenRequest{
+				Name:      "ci",
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.name == "Existing ID" {
+				tt.id = res.ID
+			}
+
+			assert.Equal(t, tt.wantErr, s.DeleteToken(context.Background(), "test_id", tt.id))
+		})
+	}
+}
+
+func TestAPITokenService_GetTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		wantLen int
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "User tokens",
+			uid:     "test_id",
+			wantLen: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAPITokenService(initAPITokenMS(t))
+			if _, err := s.CreateToken(context.Background(), "test_id", models.APITokenRequest{
+				Name:      "ci",
+				ExpiresAt: time.Now().Add(time.Hour),
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.GetTokens(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantLen, len(got))
+			for _, v := range got {
+				assert.Empty(t, v.Token)
+			}
+		})
+	}
+}
+
+func initAPITokenMS(t *testing.T) apitoken.Service {
+	ts, err := apitoken.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
@@ -14,41 +14,89 @@ type AuthService struct {
 	authMS auth.Service
 }
 
-var ErrWrongCredential = errors.New("invalid username or password")
+var (
+	ErrSessionNotFound = errors.New("requested session not found")
+	ErrWrongCredential = errors.New("invalid username or password")
+)
 
-// NewAuthService returns an instance of the BinaryService with pre-defined auth microservice.
-func NewAuthService(repoURL string) (*AuthService, error) {
-	sessionMS, err := session.NewService(repoURL)
-	if err != nil {
-		return nil, err
+// NewAuthService returns an instance of the AuthService with pre-defined auth microservice.
+func NewAuthService(sessionMS session.Service, userMS user.Service) *AuthService {
+	return &AuthService{authMS: auth.NewService(sessionMS, userMS)}
+}
+
+// Authorize parses the passed token string and returns the user ID associated with it.
+// If the token is empty, expired or doesn't belong to the active client session, the method returns an error.
+func (s *AuthService) Authorize(ctx context.Context, cid, token string) (string, error) {
+	if token == "" {
+		return "", ErrBadArguments
+	}
+	return s.authMS.Authorize(ctx, cid, token)
+}
+
+// AuthorizeCertificate checks that the user the verified client certificate is mapped to exists, and returns its ID.
+func (s *AuthService) AuthorizeCertificate(ctx context.Context, uid string) (string, error) {
+	if uid == "" {
+		return "", ErrBadArguments
 	}
 
-	userMS, err := user.NewService(repoURL)
+	uid, err := s.authMS.AuthorizeCertificate(ctx, uid)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, auth.ErrWrongCredential) {
+			return "", ErrWrongCredential
+		}
+		return "", err
 	}
+	return uid, nil
+}
 
-	return &AuthService{authMS: auth.NewService(sessionMS, userMS)}, nil
+// DeleteSession revokes the user's session associated with the passed client ID.
+func (s *AuthService) DeleteSession(ctx context.Context, uid, cid string) error {
+	if uid == "" || cid == "" {
+		return ErrBadArguments
+	}
+	if err := s.authMS.DeleteSession(ctx, uid, cid); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
 }
 
-// Authorize parses the passed token string and returns the user ID associated with it.
-// If the token is empty or expired, the method returns an error.
-func (s *AuthService) Authorize(token string) (string, error) {
-	if token == "" {
-		return "", ErrBadArguments
+// GetSessions returns all the active user's sessions.
+// The session associated with the passed client ID is marked as current.
+func (s *AuthService) GetSessions(ctx context.Context, uid, cid string) ([]models.SessionResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	ss, err := s.authMS.GetSessions(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.SessionResponse, 0, len(ss))
+	for _, v := range ss {
+		sessions = append(sessions, s.getSessionResponse(v, cid))
 	}
-	return s.authMS.Authorize(token)
+	return sessions, nil
 }
 
 // Login establishes the user session based on the client ID and user credential.
 // If the client ID is passed, the method looks for the associated stored session.
 // If the client ID is empty, or the associated token is not found or expired, the method performs login by credential.
 // If the credential doesn't match, or another unknown error has occurred, the method returns an error.
-func (s *AuthService) Login(ctx context.Context, cid string, user models.UserRequest) (string, string, error) {
+func (s *AuthService) Login(ctx context.Context, cid string, user models.UserRequest,
+	client models.ClientInfo,
+) (string, string, error) {
 	if user.Name == "" || user.Password == "" {
 		return "", "", ErrBadArguments
 	}
-	token, cid, err := s.authMS.Login(ctx, cid, s.getPayloadFromRequest(user))
+	token, cid, err := s.authMS.Login(ctx, cid, s.getPayloadFromRequest(user), session.Client{
+		Device:    client.Device,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	})
 	if err != nil {
 		if errors.Is(err, auth.ErrWrongCredential) {
 			return "", "", ErrWrongCredential
@@ -82,3 +130,15 @@ func (s *AuthService) getPayloadFromRequest(req models.UserRequest) auth.Payload
 		Password: req.Password,
 	}
 }
+
+func (s *AuthService) getSessionResponse(ss session.Session, cid string) models.SessionResponse {
+	return models.SessionResponse{
+		CID:       ss.CID,
+		Device:    ss.Device,
+		IP:        ss.IP,
+		UserAgent: ss.UserAgent,
+		CreatedAt: ss.CreatedAt,
+		LastSeen:  ss.LastSeen,
+		Current:   ss.CID == cid,
+	}
+}
@@ -17,9 +17,8 @@ import (
 func TestNewAuthService(t *testing.T) {
 	ss, us := initSessionUserMS(t)
 	tests := []struct {
-		name    string
-		want    *AuthService
-		wantErr bool
+		name string
+		want *AuthService
 	}{
 		{
 			name: "Service creation",
@@ -28,19 +27,36 @@ func TestNewAuthService(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			as, err := NewAuthService("")
-			assert.Equal(t, tt.want, as)
-			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, NewAuthService(ss, us))
 		})
 	}
 }
 
+func initAuthService(t *testing.T) *AuthService {
+	ss, us := initSessionUserMS(t)
+	return NewAuthService(ss, us)
+}
+
+func initLoggedAuthService(t *testing.T) (*AuthService, string, string) {
+	s := initAuthService(t)
+	u := models.UserRequest{Name: "test", Password: "test"}
+	if err := s.Register(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+
+	token, cid, err := s.Login(context.Background(), "", u, models.ClientInfo{Device: "test-device"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, token, cid
+}
+
 func initSessionUserMS(t *testing.T) (session.Service, user.Service) {
-	ss, err := session.NewService("")
+	ss, err := session.NewService(nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	us, err := user.NewService("")
+	us, err := user.NewService(nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -85,12 +101,9 @@ func TestAuthService_Register(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, err := NewAuthService("")
-			if err != nil {
-				t.Fatal(err)
-			}
+			s := initAuthService(t)
 			if tt.args.user.Name != "" {
-				if err = s.Register(context.Background(), tt.args.user); err != nil {
+				if err := s.Register(context.Background(), tt.args.user); err != nil {
 					t.Fatal(err)
 				}
 			}
@@ -100,21 +113,22 @@ func TestAuthService_Register(t *testing.T) {
 }
 
 func TestAuthService_Authorize(t *testing.T) {
-	token, err := jwt.EncodeToken("test_id", 0)
+	expToken, err := jwt.EncodeToken("bad_id", -1*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
-	expToken, err := jwt.EncodeToken("bad_id", -1*time.Hour)
+	revToken, err := jwt.EncodeToken("revoked_id", 0)
 	if err != nil {
 		t.Fatal(err)
 	}
 	type args struct {
+		cid   string
 		token string
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    string
+		want    bool
 		wantErr error
 	}{
 		{
@@ -123,28 +137,151 @@ func TestAuthService_Authorize(t *testing.T) {
 		},
 		{
 			name: "Valid token",
-			args: args{token: token},
-			want: "test_id",
+			want: true,
 		},
 		{
 			name:    "Expired token",
 			args:    args{token: expToken},
 			wantErr: auth.ErrSessionExpired,
 		},
+		{
+			name:    "Revoked session",
+			args:    args{token: revToken},
+			wantErr: auth.ErrSessionRevoked,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, sErr := NewAuthService("")
-			if sErr != nil {
-				t.Fatal(sErr)
+			s, token, cid := initLoggedAuthService(t)
+			if tt.want {
+				tt.args = args{cid: cid, token: token}
 			}
-			got, sErr := s.Authorize(tt.args.token)
-			assert.Equal(t, tt.want, got)
+
+			got, sErr := s.Authorize(context.Background(), tt.args.cid, tt.args.token)
+			assert.Equal(t, tt.want, got != "")
 			assert.Equal(t, tt.wantErr, sErr)
 		})
 	}
 }
 
+func TestAuthService_AuthorizeCertificate(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		known   bool
+		want    bool
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown user",
+			uid:     "test1",
+			wantErr: ErrWrongCredential,
+		},
+		{
+			name:  "Known user",
+			known: true,
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, token, cid := initLoggedAuthService(t)
+			if tt.known {
+				var err error
+				if tt.uid, err = s.Authorize(context.Background(), cid, token); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := s.AuthorizeCertificate(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got != "")
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestAuthService_DeleteSession(t *testing.T) {
+	type args struct {
+		uid string
+		cid string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Missing arguments",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown session",
+			args:    args{uid: "test_id", cid: "unknown"},
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "Existing session",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, token, cid := initLoggedAuthService(t)
+			if tt.name == "Existing session" {
+				uid, err := s.Authorize(context.Background(), cid, token)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tt.args = args{uid: uid, cid: cid}
+			}
+
+			assert.Equal(t, tt.wantErr, s.DeleteSession(context.Background(), tt.args.uid, tt.args.cid))
+		})
+	}
+}
+
+func TestAuthService_GetSessions(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		want    []bool
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Current session is marked",
+			want: []bool{true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, token, cid := initLoggedAuthService(t)
+			if tt.want != nil {
+				uid, err := s.Authorize(context.Background(), cid, token)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tt.uid = uid
+			}
+
+			got, err := s.GetSessions(context.Background(), tt.uid, cid)
+			assert.Equal(t, tt.wantErr, err)
+
+			var current []bool
+			for _, v := range got {
+				current = append(current, v.Current)
+			}
+			assert.Equal(t, tt.want, current)
+		})
+	}
+}
+
 func TestAuthService_Login(t *testing.T) {
 	type args struct {
 		cid  string
@@ -170,11 +307,8 @@ func TestAuthService_Login(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, sErr := NewAuthService("")
-			if sErr != nil {
-				t.Fatal(sErr)
-			}
-			got, got1, err := s.Login(context.Background(), tt.args.cid, tt.args.user)
+			s := initAuthService(t)
+			got, got1, err := s.Login(context.Background(), tt.args.cid, tt.args.user, models.ClientInfo{})
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.want1, got1)
 			assert.Equal(t, tt.wantErr, err)
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BatchService struct {
+	uow         storage.UnitOfWork
+	itemService *ItemService
+}
+
+// BatchResult is the outcome of the operation, holding the ID of the created or changed item on success.
+type BatchResult struct {
+	ID  string
+	Err error
+}
+
+var ErrBatchRolledBack = errors.New("the operation is rolled back along with the failed one")
+
+// NewBatchService returns an instance of the BatchService with pre-defined item service.
+// The operations behave exactly as the single item requests they replace, since they are served by the same service.
+// The unit of work rolls the atomic batches back.
+func NewBatchService(uow storage.UnitOfWork, dataMS data.Service) *BatchService {
+	return &BatchService{uow: uow, itemService: NewItemService(dataMS)}
+}
+
+// ExecuteBatch executes the operations in order, and returns their results in the same order.
+// The failed operation doesn't stop the rest of the batch, unless the batch is atomic.
+// The atomic batch is rolled back on the first failure, so the rest of its operations report ErrBatchRolledBack.
+func (s *BatchService) ExecuteBatch(ctx context.Context, uid string,
+	req models.BatchRequest,
+) ([]BatchResult, bool, error) {
+	if uid == "" || len(req.Operations) == 0 || len(req.Operations) > models.MaxBatchOperations {
+		return nil, false, ErrBadArguments
+	}
+
+	res := make([]BatchResult, len(req.Operations))
+	if !req.Atomic {
+		for i, op := range req.Operations {
+			res[i].ID, res[i].Err = s.execute(ctx, uid, op)
+		}
+		return res, true, nil
+	}
+
+	failed := -1
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		for i, op := range req.Operations {
+			if res[i].ID, res[i].Err = s.execute(ctx, uid, op); res[i].Err != nil {
+				failed = i
+				return res[i].Err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return res, true, nil
+	}
+	if failed < 0 {
+		return nil, false, err
+	}
+
+	for i := range res {
+		if i != failed {
+			res[i] = BatchResult{Err: ErrBatchRolledBack}
+		}
+	}
+	return res, false, nil
+}
+
+func (s *BatchService) execute(ctx context.Context, uid string, op models.BatchOperation) (string, error) {
+	t, ok := items.Lookup(op.Type)
+	if !ok {
+		return "", ErrBadArguments
+	}
+	if op.Op == models.BatchDelete {
+		return op.ID, s.itemService.DeleteItem(ctx, uid, t, op.ID)
+	}
+
+	var req models.ItemRequest
+	if err := json.Unmarshal(op.Data, &req); err != nil {
+		return "", ErrBadArguments
+	}
+
+	switch op.Op {
+	case models.BatchCreate:
+		return s.itemService.StoreItem(ctx, uid, t, req)
+	case models.BatchUpdate:
+		return op.ID, s.itemService.UpdateItem(ctx, uid, t, op.ID, req)
+	}
+	return "", ErrBadArguments
+}
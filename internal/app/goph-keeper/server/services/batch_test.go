@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBatchService_ExecuteBatch(t *testing.T) {
+	text := getTestBatchOperation(t, models.BatchCreate, "text", "",
+		models.ItemRequest{Fields: map[string]string{"name": "text", "data": "test"}})
+	card := getTestBatchOperation(t, models.BatchCreate, "card", "",
+		models.ItemRequest{Fields: map[string]string{"name": "card"}})
+	unknown := getTestBatchOperation(t, models.BatchUpdate, "password", "unknown",
+		models.ItemRequest{Fields: map[string]string{"name": "pass"}})
+
+	tests := []struct {
+		name          string
+		req           models.BatchRequest
+		want          []error
+		wantCommitted bool
+		wantTexts     int
+		wantErr       error
+	}{
+		{
+			name:    "Operations are missing",
+			req:     models.BatchRequest{},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Too many operations",
+			req:     models.BatchRequest{Operations: make([]models.BatchOperation, models.MaxBatchOperations+1)},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Failed operations don't stop the batch",
+			req: models.BatchRequest{Operations: []models.BatchOperation{
+				text,
+				{Op: models.BatchCreate, Type: "unknown", Data: text.Data},
+				{Op: models.BatchCreate, Type: "text", Data: json.RawMessage(`"text"`)},
+				{Op: "merge", Type: "text", Data: text.Data},
+				unknown,
+				card,
+			}},
+			want:          []error{nil, ErrBadArguments, ErrBadArguments, ErrBadArguments, ErrItemNotFound, nil},
+			wantCommitted: true,
+			wantTexts:     1,
+		},
+		{
+			name:          "Atomic batch is committed",
+			req:           models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{text, card, text}},
+			want:          []error{nil, nil, nil},
+			wantCommitted: true,
+			wantTexts:     2,
+		},
+		{
+			name:      "Atomic batch is rolled back",
+			req:       models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{text, unknown, card}},
+			want:      []error{ErrBatchRolledBack, ErrItemNotFound, ErrBatchRolledBack},
+			wantTexts: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := initDataMS(t)
+			s := NewBatchService(storage.UnitOfWork{}, ds)
+			got, committed, err := s.ExecuteBatch(context.Background(), "testID", tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCommitted, committed)
+			if err != nil {
+				return
+			}
+
+			errs := make([]error, 0, len(got))
+			for _, r := range got {
+				errs = append(errs, r.Err)
+				if r.Err == nil {
+					assert.NotEmpty(t, r.ID)
+				}
+			}
+			assert.Equal(t, tt.want, errs)
+
+			texts, gErr := NewItemService(ds).GetAllItems(context.Background(), "testID", items.Text)
+			assert.NoError(t, gErr)
+			assert.Len(t, texts, tt.wantTexts)
+		})
+	}
+}
+
+func TestBatchService_ExecuteBatch_UpdateDelete(t *testing.T) {
+	ds := initDataMS(t)
+	s := NewBatchService(storage.UnitOfWork{}, ds)
+	is := NewItemService(ds)
+	id, err := is.StoreItem(context.Background(), "testID", items.Text, models.ItemRequest{Fields: map[string]string{
+		"name": "text", "data": "test",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, committed, err := s.ExecuteBatch(context.Background(), "testID", models.BatchRequest{
+		Atomic: true,
+		Operations: []models.BatchOperation{
+			getTestBatchOperation(t, models.BatchUpdate, "text", id, models.ItemRequest{Fields: map[string]string{
+				"name": "text", "data": "updated",
+			}}),
+			{Op: models.BatchDelete, Type: "text", ID: id},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, []BatchResult{{ID: id}, {ID: id}}, got)
+
+	_, err = is.GetItemByID(context.Background(), "testID", items.Text, id)
+	assert.Equal(t, ErrItemNotFound, err)
+}
+
+func getTestBatchOperation(t *testing.T, op, itemType, id string, data any) models.BatchOperation {
+	t.Helper()
+	o, err := models.NewBatchOperation(op, itemType, id, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
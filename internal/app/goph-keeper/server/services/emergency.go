@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+type EmergencyService struct {
+	emergencyMS emergency.Service
+	userMS      user.Service
+}
+
+var ErrContactNotFound = errors.New("requested emergency contact not found")
+
+// NewEmergencyService returns an instance of the EmergencyService with pre-defined emergency and user microservices.
+func NewEmergencyService(emergencyMS emergency.Service, userMS user.Service) *EmergencyService {
+	return &EmergencyService{emergencyMS: emergencyMS, userMS: userMS}
+}
+
+// AddContact designates the user with the specified name as the emergency contact of the owner.
+func (s *EmergencyService) AddContact(ctx context.Context, uid string,
+	req models.EmergencyContactRequest,
+) (models.EmergencyContactResponse, error) {
+	if uid == "" || req.User == "" {
+		return models.EmergencyContactResponse{}, ErrBadArguments
+	}
+
+	u, err := s.userMS.GetUserByName(ctx, req.User)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return models.EmergencyContactResponse{}, ErrUserNotFound
+		}
+		return models.EmergencyContactResponse{}, err
+	}
+
+	c, err := s.emergencyMS.AddContact(ctx, uid, u.ID, time.Duration(req.WaitHours)*time.Hour)
+	if err != nil {
+		return models.EmergencyContactResponse{}, s.mapError(err)
+	}
+	return s.getContactResponse(ctx, c)
+}
+
+// ApproveRequest grants the requested access to the owner's vault without waiting for the period to pass.
+func (s *EmergencyService) ApproveRequest(ctx context.Context, uid, id string,
+) (models.EmergencyContactResponse, error) {
+	if uid == "" || id == "" {
+		return models.EmergencyContactResponse{}, ErrBadArguments
+	}
+
+	c, err := s.emergencyMS.ApproveRequest(ctx, uid, id)
+	if err != nil {
+		return models.EmergencyContactResponse{}, s.mapError(err)
+	}
+	return s.getContactResponse(ctx, c)
+}
+
+// AuthorizeAccess returns the ID of the owner whose vault the grantee may read with the emergency access.
+func (s *EmergencyService) AuthorizeAccess(ctx context.Context, uid, id string) (string, error) {
+	if uid == "" || id == "" {
+		return "", ErrBadArguments
+	}
+
+	owner, err := s.emergencyMS.AuthorizeAccess(ctx, uid, id)
+	if errors.Is(err, emergency.ErrNotGranted) {
+		return "", ErrForbidden
+	}
+	return owner, s.mapError(err)
+}
+
+// GetContacts returns the user's emergency contacts, and the users who designated the user as theirs.
+func (s *EmergencyService) GetContacts(ctx context.Context, uid string) ([]models.EmergencyContactResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	cs, err := s.emergencyMS.GetContacts(ctx, uid)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	contacts := make([]models.EmergencyContactResponse, 0, len(cs))
+	for _, c := range cs {
+		res, rErr := s.getContactResponse(ctx, c)
+		if rErr != nil {
+			return nil, rErr
+		}
+		contacts = append(contacts, res)
+	}
+	return contacts, nil
+}
+
+// GetEvents returns the history of the emergency access requests the user took part in.
+func (s *EmergencyService) GetEvents(ctx context.Context, uid string) ([]models.EmergencyEventResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	es, err := s.emergencyMS.GetEvents(ctx, uid)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	names := make(map[string]string)
+	events := make([]models.EmergencyEventResponse, 0, len(es))
+	for _, e := range es {
+		owner, oErr := s.getUserName(ctx, names, e.Owner)
+		if oErr != nil {
+			return nil, oErr
+		}
+		grantee, gErr := s.getUserName(ctx, names, e.Grantee)
+		if gErr != nil {
+			return nil, gErr
+		}
+
+		events = append(events, models.EmergencyEventResponse{
+			ContactID: e.ContactID,
+			Owner:     owner,
+			Grantee:   grantee,
+			Action:    string(e.Action),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return events, nil
+}
+
+// RejectRequest rejects the pending access request, or revokes the granted access.
+func (s *EmergencyService) RejectRequest(ctx context.Context, uid, id string) (models.EmergencyContactResponse, error) {
+	if uid == "" || id == "" {
+		return models.EmergencyContactResponse{}, ErrBadArguments
+	}
+
+	c, err := s.emergencyMS.RejectRequest(ctx, uid, id)
+	if err != nil {
+		return models.EmergencyContactResponse{}, s.mapError(err)
+	}
+	return s.getContactResponse(ctx, c)
+}
+
+// RemoveContact removes the contact on behalf of either the owner or the grantee.
+func (s *EmergencyService) RemoveContact(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.emergencyMS.RemoveContact(ctx, uid, id))
+}
+
+// RequestAccess starts the waiting period, after which the grantee can read the owner's vault.
+func (s *EmergencyService) RequestAccess(ctx context.Context, uid, id string) (models.EmergencyContactResponse, error) {
+	if uid == "" || id == "" {
+		return models.EmergencyContactResponse{}, ErrBadArguments
+	}
+
+	c, err := s.emergencyMS.RequestAccess(ctx, uid, id)
+	if err != nil {
+		return models.EmergencyContactResponse{}, s.mapError(err)
+	}
+	return s.getContactResponse(ctx, c)
+}
+
+func (s *EmergencyService) getContactResponse(ctx context.Context,
+	c emergency.Contact,
+) (models.EmergencyContactResponse, error) {
+	owner, err := s.userMS.GetUserByID(ctx, c.Owner)
+	if err != nil {
+		return models.EmergencyContactResponse{}, err
+	}
+	grantee, err := s.userMS.GetUserByID(ctx, c.Grantee)
+	if err != nil {
+		return models.EmergencyContactResponse{}, err
+	}
+
+	return models.EmergencyContactResponse{
+		ID:          c.ID,
+		Owner:       owner.Name,
+		Grantee:     grantee.Name,
+		WaitHours:   int(c.WaitPeriod / time.Hour),
+		Status:      string(c.Status),
+		RequestedAt: c.RequestedAt,
+		GrantsAt:    c.GrantsAt,
+		CreatedAt:   c.CreatedAt,
+	}, nil
+}
+
+// getUserName resolves the user name by ID. The events outlive the accounts, so the removed users are reported by ID.
+func (s *EmergencyService) getUserName(ctx context.Context, names map[string]string, uid string) (string, error) {
+	if name, ok := names[uid]; ok {
+		return name, nil
+	}
+
+	u, err := s.userMS.GetUserByID(ctx, uid)
+	if errors.Is(err, user.ErrNotFound) {
+		u.Name, err = uid, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	names[uid] = u.Name
+	return u.Name, nil
+}
+
+func (s *EmergencyService) mapError(err error) error {
+	switch {
+	case errors.Is(err, emergency.ErrNotFound), errors.Is(err, emergency.ErrNotAllowed):
+		return ErrContactNotFound
+	case errors.Is(err, emergency.ErrInvalidWait), errors.Is(err, emergency.ErrSelfContact),
+		errors.Is(err, emergency.ErrMissingArgs):
+		return ErrBadArguments
+	}
+	return err
+}
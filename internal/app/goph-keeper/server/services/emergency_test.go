@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestNewEmergencyService(t *testing.T) {
+	es := initEmergencyMS(t)
+	_, us := initSessionUserMS(t)
+	assert.Equal(t, &EmergencyService{emergencyMS: es, userMS: us}, NewEmergencyService(es, us))
+}
+
+func TestEmergencyService_AddContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.EmergencyContactRequest
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown user",
+			req:     models.EmergencyContactRequest{User: "unknown", WaitHours: 24},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name:    "Owner is the contact",
+			req:     models.EmergencyContactRequest{User: "owner", WaitHours: 24},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "No waiting period",
+			req:     models.EmergencyContactRequest{User: "user"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Contact is added",
+			req:  models.EmergencyContactRequest{User: "user", WaitHours: 24},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, owner, _ := initEmergencyService(t)
+			got, err := s.AddContact(context.Background(), owner, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, "owner", got.Owner)
+			assert.Equal(t, "user", got.Grantee)
+			assert.Equal(t, 24, got.WaitHours)
+			assert.Equal(t, string(emergency.StatusIdle), got.Status)
+		})
+	}
+}
+
+func TestEmergencyService_AuthorizeAccess(t *testing.T) {
+	s, owner, grantee := initEmergencyService(t)
+	c, err := s.AddContact(context.Background(), owner, models.EmergencyContactRequest{User: "user", WaitHours: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.AuthorizeAccess(context.Background(), owner, c.ID)
+	assert.Equal(t, ErrContactNotFound, err)
+	_, err = s.AuthorizeAccess(context.Background(), grantee, c.ID)
+	assert.Equal(t, ErrForbidden, err)
+
+	_, err = s.RequestAccess(context.Background(), grantee, c.ID)
+	assert.NoError(t, err)
+	_, err = s.AuthorizeAccess(context.Background(), grantee, c.ID)
+	assert.Equal(t, ErrForbidden, err)
+
+	_, err = s.ApproveRequest(context.Background(), owner, c.ID)
+	assert.NoError(t, err)
+	got, err := s.AuthorizeAccess(context.Background(), grantee, c.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, owner, got)
+}
+
+func TestEmergencyService_GetEvents(t *testing.T) {
+	s, owner, grantee := initEmergencyService(t)
+	c, err := s.AddContact(context.Background(), owner, models.EmergencyContactRequest{User: "user", WaitHours: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.RequestAccess(context.Background(), grantee, c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.RejectRequest(context.Background(), owner, c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.RemoveContact(context.Background(), grantee, c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := s.GetEvents(context.Background(), owner)
+	assert.NoError(t, err)
+
+	actions := make([]string, 0, len(events))
+	for _, e := range events {
+		assert.Equal(t, "owner", e.Owner)
+		assert.Equal(t, "user", e.Grantee)
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{"designated", "requested", "rejected", "removed"}, actions)
+}
+
+func initEmergencyMS(t *testing.T) emergency.Service {
+	es, err := emergency.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return es
+}
+
+func initEmergencyService(t *testing.T) (*EmergencyService, string, string) {
+	_, us := initSessionUserMS(t)
+	ids := make([]string, 0, 2)
+	for _, name := range []string{"owner", "user"} {
+		if err := us.AddUser(context.Background(), user.User{Name: name, Password: "test"}); err != nil {
+			t.Fatal(err)
+		}
+		u, err := us.GetUserByName(context.Background(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, u.ID)
+	}
+	return NewEmergencyService(initEmergencyMS(t), us), ids[0], ids[1]
+}
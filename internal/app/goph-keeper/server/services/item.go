@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/item"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/template"
+)
+
+type ItemService struct {
+	itemMS     item.Service
+	templateMS template.Service
+}
+
+var ErrBadArguments = errors.New("the required arguments are not present")
+
+// NewItemService returns an instance of the ItemService with pre-defined item and template microservices.
+// The service serves the items of all the registered types.
+func NewItemService(dataMS data.Service) *ItemService {
+	return &ItemService{itemMS: item.NewService(dataMS), templateMS: template.NewService(dataMS)}
+}
+
+// DeleteItem removes the stored item of the type with the unique ID.
+// The method removes the item of the specified user only.
+func (s *ItemService) DeleteItem(ctx context.Context, uid string, t items.Type, id string) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.itemMS.DeleteItem(ctx, uid, id, t.Storage))
+}
+
+// GetAllItems returns all the user's stored items of the type.
+func (s *ItemService) GetAllItems(ctx context.Context, uid string, t items.Type) ([]models.ItemResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+	res, err := s.itemMS.GetAllItems(ctx, uid, t.Storage)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	resp := make([]models.ItemResponse, 0, len(res))
+	for _, i := range res {
+		resp = append(resp, s.getResponseFromModel(i))
+	}
+	return resp, nil
+}
+
+// GetItemByID returns the stored item of the type by the unique ID.
+// The method returns the item of the specified user only.
+func (s *ItemService) GetItemByID(ctx context.Context, uid string, t items.Type,
+	id string,
+) (models.ItemResponse, error) {
+	if uid == "" || id == "" {
+		return models.ItemResponse{}, ErrBadArguments
+	}
+	res, err := s.itemMS.GetItemByID(ctx, uid, id, t.Storage)
+	if err != nil {
+		return models.ItemResponse{}, s.mapError(err)
+	}
+	return s.getResponseFromModel(res), nil
+}
+
+// StoreItem stores the original item of the type via the associated item microservice.
+// The values of the fields unknown to the type are dropped.
+// The item created with the template must have the required fields of the user's template with that name.
+func (s *ItemService) StoreItem(ctx context.Context, uid string, t items.Type,
+	req models.ItemRequest,
+) (string, error) {
+	if uid == "" {
+		return "", ErrBadArguments
+	}
+	i, err := s.getItemFromRequest(ctx, uid, t, req, true)
+	if err != nil {
+		return "", err
+	}
+	if err = t.Validate(i.Fields, nil); err != nil {
+		return "", ErrBadArguments
+	}
+	return s.itemMS.StoreItem(ctx, i)
+}
+
+// UpdateItem replaces the stored item of the type via the associated item microservice.
+// The item shared with the user can be updated unless it is shared as read-only.
+// The type checks are run against the changed values only, so the earlier stored items can be updated.
+func (s *ItemService) UpdateItem(ctx context.Context, uid string, t items.Type, id string,
+	req models.ItemRequest,
+) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	i, err := s.getItemFromRequest(ctx, uid, t, req, false)
+	if err != nil {
+		return err
+	}
+	prev, err := s.itemMS.GetItemByID(ctx, uid, id, t.Storage)
+	if err != nil {
+		return s.mapError(err)
+	}
+	if err = t.Validate(i.Fields, prev.Fields); err != nil {
+		return ErrBadArguments
+	}
+	i.ID = id
+	return s.mapError(s.itemMS.UpdateItem(ctx, i))
+}
+
+// NormalizePayload checks the imported item payload as the item request, and returns the payload to store.
+// The payloads of the storage types other than the item types, e.g. the templates, are returned as is.
+func (s *ItemService) NormalizePayload(ctx context.Context, uid string, st data.StorageType,
+	payload map[string]any,
+) (map[string]any, error) {
+	t, ok := items.LookupStorage(st)
+	if !ok {
+		return payload, nil
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var req models.ItemRequest
+	if err = json.Unmarshal(b, &req); err != nil {
+		return nil, ErrBadArguments
+	}
+
+	i, err := s.getItemFromRequest(ctx, uid, t, req, false)
+	if err != nil {
+		return nil, err
+	}
+	return item.Payload(i), nil
+}
+
+// getItemFromRequest returns the item of the type with the normalized field values and custom fields.
+// The custom fields of the item created with the template are checked against the user's template.
+// The shared item may be created with the template of its owner, so the unknown template is rejected on store only.
+func (s *ItemService) getItemFromRequest(ctx context.Context, uid string, t items.Type, req models.ItemRequest,
+	strict bool,
+) (item.Item, error) {
+	fields, err := t.Normalize(req.Fields)
+	if err != nil {
+		return item.Item{}, ErrBadArguments
+	}
+	custom, err := items.NormalizeCustom(req.Custom)
+	if err != nil {
+		return item.Item{}, ErrBadArguments
+	}
+
+	if name := fields[items.FieldTemplate]; name != "" {
+		tmpl, tErr := s.templateMS.GetTemplateByName(ctx, uid, name)
+		switch {
+		case tErr == nil:
+			if custom, err = getTemplateFromModel(tmpl).Apply(custom); err != nil {
+				return item.Item{}, ErrBadArguments
+			}
+		case !errors.Is(tErr, template.ErrNotFound):
+			return item.Item{}, tErr
+		case strict:
+			return item.Item{}, ErrBadArguments
+		}
+	}
+	return item.Item{UID: uid, Type: t.Storage, Fields: fields, Custom: getCustomModel(custom)}, nil
+}
+
+func (s *ItemService) getResponseFromModel(i item.Item) models.ItemResponse {
+	return models.ItemResponse{
+		ID:       i.ID,
+		Fields:   i.Fields,
+		Custom:   getCustomFromModel(i.Custom),
+		Shared:   i.Shared,
+		ReadOnly: i.ReadOnly,
+	}
+}
+
+func (s *ItemService) mapError(err error) error {
+	if errors.Is(err, item.ErrNotFound) {
+		return ErrItemNotFound
+	}
+	if errors.Is(err, data.ErrReadOnly) {
+		return ErrItemReadOnly
+	}
+	return err
+}
+
+func getCustomFromModel(fields []item.CustomField) []items.CustomField {
+	if len(fields) == 0 {
+		return nil
+	}
+	res := make([]items.CustomField, 0, len(fields))
+	for _, f := range fields {
+		res = append(res, items.CustomField{Name: f.Name, Kind: items.Kind(f.Kind), Value: f.Value})
+	}
+	return res
+}
+
+func getCustomModel(fields []items.CustomField) []item.CustomField {
+	if len(fields) == 0 {
+		return nil
+	}
+	res := make([]item.CustomField, 0, len(fields))
+	for _, f := range fields {
+		res = append(res, item.CustomField{Name: f.Name, Kind: string(f.Kind), Value: f.Value})
+	}
+	return res
+}
+
+// getTypeName returns the name of the registered type the items of the storage type belong to.
+func getTypeName(st data.StorageType) string {
+	t, _ := items.LookupStorage(st)
+	return t.Name
+}
+
+// getStorageType returns the storage type of the items of the registered type with the name.
+func getStorageType(name string) (data.StorageType, bool) {
+	t, ok := items.Lookup(name)
+	return t.Storage, ok
+}
@@ -0,0 +1,603 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/item"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/template"
+)
+
+type testItem struct {
+	uid    string
+	t      items.Type
+	fields models.ItemRequest
+}
+
+func TestNewItemService(t *testing.T) {
+	ds := initDataMS(t)
+	assert.Equal(t, &ItemService{itemMS: item.NewService(ds), templateMS: template.NewService(ds)}, NewItemService(ds))
+}
+
+func TestItemService_DeleteItem(t *testing.T) {
+	type args struct {
+		uid string
+		t   items.Type
+		id  string
+	}
+	tests := []struct {
+		name    string
+		repo    map[string]testItem
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "ID is empty",
+			args:    args{uid: "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "User ID is empty",
+			args:    args{id: "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Data is not present",
+			repo:    map[string]testItem{"test1": {uid: "test1", t: items.Text, fields: getTestText()}},
+			args:    args{uid: "test", t: items.Text, id: "test"},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:    "Data is of another type",
+			repo:    map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			args:    args{uid: "test", t: items.Card, id: "test"},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name: "Data is present and deleted",
+			repo: map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			args: args{uid: "test", t: items.Text, id: "test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids, _ := initItemService(t, tt.repo)
+			if id, ok := ids[tt.args.id]; ok {
+				tt.args.id = id
+			}
+
+			err := s.DeleteItem(context.Background(), tt.args.uid, tt.args.t, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestItemService_GetAllItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		t       items.Type
+		repo    map[string]testItem
+		want    []models.ItemResponse
+		wantErr error
+	}{
+		{
+			name:    "Missing UID",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "No data",
+			uid:  "test1",
+			t:    items.Text,
+			repo: map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			want: []models.ItemResponse{},
+		},
+		{
+			name: "Hidden values are listed",
+			uid:  "test",
+			t:    items.Card,
+			repo: map[string]testItem{
+				"test": {uid: "test", t: items.Card, fields: models.ItemRequest{Fields: map[string]string{
+					"name": "test", "cvv": "123",
+				}}},
+				"text": {uid: "test", t: items.Text, fields: getTestText()},
+				"test1": {uid: "test1", t: items.Card, fields: models.ItemRequest{Fields: map[string]string{
+					"name": "test1", "cvv": "123",
+				}}},
+			},
+			want: []models.ItemResponse{{ID: "test", Fields: map[string]string{
+				"name": "test", "number": "", "brand": "", "last4": "", "holder": "", "exp_date": "", "cvv": "123",
+				"note": "",
+			}}},
+		},
+		{
+			name: "Hidden custom values are listed",
+			uid:  "test",
+			t:    items.Text,
+			repo: map[string]testItem{"test": {uid: "test", t: items.Text, fields: models.ItemRequest{
+				Fields: map[string]string{"name": "test", "data": "test"},
+				Custom: []items.CustomField{
+					{Name: "pin", Kind: items.KindHidden, Value: "1234"},
+					{Name: "site", Kind: items.KindURL, Value: "https://test.com"},
+				},
+			}}},
+			want: []models.ItemResponse{{
+				ID:     "test",
+				Fields: map[string]string{"name": "test", "data": "test", "note": ""},
+				Custom: []items.CustomField{
+					{Name: "pin", Kind: items.KindHidden, Value: "1234"},
+					{Name: "site", Kind: items.KindURL, Value: "https://test.com"},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids, _ := initItemService(t, tt.repo)
+			for i := range tt.want {
+				tt.want[i].ID = ids[tt.want[i].ID]
+			}
+
+			got, err := s.GetAllItems(context.Background(), tt.uid, tt.t)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestItemService_GetItemByID(t *testing.T) {
+	type args struct {
+		uid string
+		t   items.Type
+		id  string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		repo    map[string]testItem
+		want    models.ItemResponse
+		wantErr error
+	}{
+		{
+			name:    "Missing arguments",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Missing UID",
+			args:    args{id: "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Missing ID",
+			args:    args{uid: "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "No data",
+			args:    args{uid: "test1", t: items.Text, id: "test"},
+			repo:    map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name: "Data found",
+			args: args{uid: "test", t: items.Text, id: "test"},
+			repo: map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			want: models.ItemResponse{ID: "test", Fields: map[string]string{"name": "test", "data": "test", "note": ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids, _ := initItemService(t, tt.repo)
+			if id, ok := ids[tt.args.id]; ok {
+				tt.args.id = id
+				if tt.wantErr == nil {
+					tt.want.ID = id
+				}
+			}
+
+			got, err := s.GetItemByID(context.Background(), tt.args.uid, tt.args.t, tt.args.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestItemService_NormalizePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       data.StorageType
+		payload map[string]any
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name:    "Required field is missing",
+			t:       data.SText,
+			payload: map[string]any{"name": "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Card with wrong number is imported as is",
+			t:       data.SCard,
+			payload: map[string]any{"name": "test", "number": "4111111111111112"},
+			want: map[string]any{
+				"name": "test", "number": "4111111111111112", "brand": "Visa", "last4": "1112", "holder": "",
+				"exp_date": "", "cvv": "", "note": "",
+			},
+		},
+		{
+			name:    "Item is normalized",
+			t:       data.SText,
+			payload: map[string]any{"name": "test", "data": "test", "extra": "test"},
+			want:    map[string]any{"name": "test", "data": "test", "note": ""},
+		},
+		{
+			name:    "Template is stored as is",
+			t:       data.STemplate,
+			payload: map[string]any{"name": "db", "extra": "test"},
+			want:    map[string]any{"name": "db", "extra": "test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := initItemService(t, nil)
+			got, err := s.NormalizePayload(context.Background(), "test", tt.t, tt.payload)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestItemService_StoreItem(t *testing.T) {
+	type args struct {
+		uid string
+		t   items.Type
+		req models.ItemRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Missing UID",
+			args:    args{t: items.Text, req: getTestText()},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Empty request",
+			args:    args{uid: "test", t: items.Text},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Empty name",
+			args:    args{uid: "test", t: items.Text, req: models.ItemRequest{Fields: map[string]string{"data": "test"}}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Empty data",
+			args:    args{uid: "test", t: items.Text, req: models.ItemRequest{Fields: map[string]string{"name": "test"}}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "File is not encoded",
+			args: args{uid: "test", t: items.Binary, req: models.ItemRequest{Fields: map[string]string{
+				"name": "test", "data": "not base64",
+			}}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Card CVV doesn't match the brand",
+			args: args{uid: "test", t: items.Card, req: models.ItemRequest{Fields: map[string]string{
+				"name": "test", "number": "378282246310005", "cvv": "123",
+			}}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Custom field is unnamed",
+			args: args{uid: "test", t: items.Text, req: models.ItemRequest{
+				Fields: getTestText().Fields,
+				Custom: []items.CustomField{{Value: "test"}},
+			}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Custom value doesn't match the kind",
+			args: args{uid: "test", t: items.Text, req: models.ItemRequest{
+				Fields: getTestText().Fields,
+				Custom: []items.CustomField{{Name: "port", Kind: items.KindNumber, Value: "test"}},
+			}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Template is unknown",
+			args:    args{uid: "test", t: items.Custom, req: getTestCustom(nil)},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Data saved",
+			args: args{uid: "test", t: items.Text, req: getTestText()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := initItemService(t, nil)
+			_, err := s.StoreItem(context.Background(), tt.args.uid, tt.args.t, tt.args.req)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	t.Run("Unknown fields are dropped", func(t *testing.T) {
+		s, _, _ := initItemService(t, nil)
+		req := models.ItemRequest{Fields: map[string]string{
+			"name": "test", "user": "test", "password": "test", "extra": "test",
+		}}
+		id, err := s.StoreItem(context.Background(), "test", items.Password, req)
+		assert.NoError(t, err)
+
+		got, err := s.GetItemByID(context.Background(), "test", items.Password, id)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"name": "test", "user": "test", "password": "test", "note": ""}, got.Fields)
+	})
+
+	t.Run("Card brand is listed with the number", func(t *testing.T) {
+		s, _, _ := initItemService(t, nil)
+		req := models.ItemRequest{Fields: map[string]string{"name": "test", "number": "4111 1111 1111 1111"}}
+		if _, err := s.StoreItem(context.Background(), "test", items.Card, req); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.GetAllItems(context.Background(), "test", items.Card)
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "4111 1111 1111 1111", got[0].Fields["number"])
+		assert.Equal(t, "Visa", got[0].Fields["brand"])
+		assert.Equal(t, "1111", got[0].Fields["last4"])
+	})
+}
+
+func TestItemService_StoreItem_Template(t *testing.T) {
+	s, _, ds := initItemService(t, nil)
+	_, err := NewTemplateService(ds).StoreTemplate(context.Background(), "test", models.TemplateRequest{
+		Name:   "db",
+		Fields: []items.TemplateField{{Name: "host", Kind: items.KindURL, Required: true}, {Name: "password"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.StoreItem(context.Background(), "test", items.Custom, getTestCustom(nil))
+	assert.Equal(t, ErrBadArguments, err)
+
+	id, err := s.StoreItem(context.Background(), "test", items.Custom, getTestCustom([]items.CustomField{
+		{Name: "port", Kind: items.KindNumber, Value: "5432"},
+		{Name: "password", Kind: items.KindHidden, Value: "test"},
+		{Name: "host", Value: "https://db"},
+	}))
+	assert.NoError(t, err)
+
+	got, err := s.GetItemByID(context.Background(), "test", items.Custom, id)
+	assert.NoError(t, err)
+	assert.Equal(t, []items.CustomField{
+		{Name: "host", Kind: items.KindURL, Value: "https://db"},
+		{Name: "password", Kind: items.KindText, Value: "test"},
+		{Name: "port", Kind: items.KindNumber, Value: "5432"},
+	}, got.Custom)
+
+	// The template of the owner is unknown to the users the item is shared with, so their updates aren't checked.
+	err = s.UpdateItem(context.Background(), "test1", items.Custom, id, getTestCustom(nil))
+	assert.Equal(t, ErrItemNotFound, err)
+}
+
+func TestItemService_UpdateItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     map[string]testItem
+		uid      string
+		t        items.Type
+		id       string
+		req      models.ItemRequest
+		readOnly bool
+		wantErr  error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Required value is empty",
+			repo:    map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			uid:     "test",
+			t:       items.Text,
+			id:      "test",
+			req:     models.ItemRequest{Fields: map[string]string{"name": "test2"}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Data is not present",
+			repo:    map[string]testItem{"test1": {uid: "test1", t: items.Text, fields: getTestText()}},
+			uid:     "test",
+			t:       items.Text,
+			id:      "test1",
+			req:     models.ItemRequest{Fields: map[string]string{"name": "test2", "data": "test"}},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:     "Data is shared as read-only",
+			repo:     map[string]testItem{"test1": {uid: "test1", t: items.Text, fields: getTestText()}},
+			uid:      "test",
+			t:        items.Text,
+			id:       "test1",
+			req:      models.ItemRequest{Fields: map[string]string{"name": "test2", "data": "test"}},
+			readOnly: true,
+			wantErr:  ErrItemReadOnly,
+		},
+		{
+			name: "Data is updated",
+			repo: map[string]testItem{"test": {uid: "test", t: items.Text, fields: getTestText()}},
+			uid:  "test",
+			t:    items.Text,
+			id:   "test",
+			req:  models.ItemRequest{Fields: map[string]string{"name": "test2", "data": "test"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids, ds := initItemService(t, tt.repo)
+			if id, ok := ids[tt.id]; ok {
+				tt.id = id
+			}
+			if tt.readOnly {
+				if err := ds.ShareData(context.Background(), "test1", tt.id, tt.uid, true); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := s.UpdateItem(context.Background(), tt.uid, tt.t, tt.id, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestItemService_UpdateItem_Card(t *testing.T) {
+	s, _, ds := initItemService(t, nil)
+	// The card stored by the earlier versions may have the number failing the checks.
+	id, err := ds.StoreSecureDataFromPayload(context.Background(), "test", map[string]string{
+		"name": "test", "number": "4111111111111112",
+	}, data.SCard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := models.ItemRequest{Fields: map[string]string{"name": "test2", "number": "4111111111111112"}}
+	assert.NoError(t, s.UpdateItem(context.Background(), "test", items.Card, id, req))
+
+	req.Fields["number"] = "4111111111111113"
+	assert.Equal(t, ErrBadArguments, s.UpdateItem(context.Background(), "test", items.Card, id, req))
+
+	req.Fields["number"] = "4111111111111111"
+	assert.NoError(t, s.UpdateItem(context.Background(), "test", items.Card, id, req))
+}
+
+// TestItemService_Types runs the item of every registered type through the whole lifecycle,
+// so the new types are covered as soon as they are registered.
+func TestItemService_Types(t *testing.T) {
+	requests := getTestRequests(t)
+	for _, it := range items.All() {
+		t.Run(it.Name, func(t *testing.T) {
+			req, ok := requests[it.Name]
+			if !ok {
+				t.Fatalf("the test request of the %s type is missing", it.Name)
+			}
+
+			s, _, _ := initItemService(t, nil)
+			id, err := s.StoreItem(context.Background(), "test", it, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.GetItemByID(context.Background(), "test", it, id)
+			assert.NoError(t, err)
+			for k, v := range req.Fields {
+				assert.Equal(t, v, got.Fields[k])
+			}
+			assert.Equal(t, req.Custom, got.Custom)
+
+			list, err := s.GetAllItems(context.Background(), "test", it)
+			assert.NoError(t, err)
+			assert.Equal(t, []models.ItemResponse{got}, list)
+
+			req.Fields[items.FieldName] = "test2"
+			assert.NoError(t, s.UpdateItem(context.Background(), "test", it, id, req))
+			got, err = s.GetItemByID(context.Background(), "test", it, id)
+			assert.NoError(t, err)
+			assert.Equal(t, "test2", got.Fields[items.FieldName])
+
+			assert.NoError(t, s.DeleteItem(context.Background(), "test", it, id))
+			_, err = s.GetItemByID(context.Background(), "test", it, id)
+			assert.Equal(t, ErrItemNotFound, err)
+		})
+	}
+}
+
+func getTestText() models.ItemRequest {
+	return models.ItemRequest{Fields: map[string]string{"name": "test", "data": "test"}}
+}
+
+// getTestCustom returns the custom item created with the "db" template.
+func getTestCustom(custom []items.CustomField) models.ItemRequest {
+	return models.ItemRequest{Fields: map[string]string{"name": "test", "template": "db"}, Custom: custom}
+}
+
+// getTestRequests returns the valid item request of each built-in type, keyed by the type name.
+func getTestRequests(t *testing.T) map[string]models.ItemRequest {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshKey := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	return map[string]models.ItemRequest{
+		items.Binary.Name: {Fields: map[string]string{"name": "test", "data": "dGVzdA==", "note": "test"}},
+		items.Card.Name: {Fields: map[string]string{
+			"name": "test", "number": "4111111111111111", "holder": "test", "exp_date": "12/30", "cvv": "123",
+		}},
+		items.Password.Name: {Fields: map[string]string{"name": "test", "user": "test", "password": "test"}},
+		items.Text.Name:     getTestText(),
+		items.Note.Name:     {Fields: map[string]string{"name": "test", "content": "test\ntest"}},
+		items.Identity.Name: {Fields: map[string]string{
+			"name": "test", "document": "passport", "number": "123456",
+			items.FieldIdentityIssue: "2020-01-01", items.FieldExpiry: "2030-01-01",
+		}},
+		items.Bank.Name: {Fields: map[string]string{
+			"name": "test", "iban": "GB82WEST12345698765432", "bic": "DEUTDEFF",
+		}},
+		items.SSHKey.Name: {Fields: map[string]string{"name": "test", "private_key": sshKey}},
+		items.Custom.Name: {
+			Fields: map[string]string{"name": "test"},
+			Custom: []items.CustomField{{Name: "pin", Kind: items.KindHidden, Value: "1234"}},
+		},
+	}
+}
+
+func initItemService(t *testing.T, repo map[string]testItem) (*ItemService, map[string]string, data.Service) {
+	t.Helper()
+	ds := initDataMS(t)
+	s := NewItemService(ds)
+	ids := make(map[string]string, len(repo))
+	for iid, v := range repo {
+		id, err := s.StoreItem(context.Background(), v.uid, v.t, v.fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[iid] = id
+	}
+	return s, ids, ds
+}
+
+func initDataMS(t *testing.T) data.Service {
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
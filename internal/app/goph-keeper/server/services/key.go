@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+type KeyService struct {
+	userMS user.Service
+}
+
+var ErrKeyNotFound = errors.New("requested key pair not found")
+
+// NewKeyService returns an instance of the KeyService with pre-defined user microservice.
+func NewKeyService(userMS user.Service) *KeyService {
+	return &KeyService{userMS: userMS}
+}
+
+// GetKeyPair returns the user's public key along with the encrypted private key.
+func (s *KeyService) GetKeyPair(ctx context.Context, uid string) (models.KeyPairResponse, error) {
+	if uid == "" {
+		return models.KeyPairResponse{}, ErrBadArguments
+	}
+
+	kp, err := s.userMS.GetKeyPair(ctx, uid)
+	if err != nil {
+		return models.KeyPairResponse{}, s.mapError(err)
+	}
+	return models.KeyPairResponse{PublicKey: kp.PublicKey, PrivateKey: kp.PrivateKey}, nil
+}
+
+// GetPublicKey returns the public key of the user with the specified name.
+// The key is used to seal the shared items to the user.
+func (s *KeyService) GetPublicKey(ctx context.Context, name string) (models.PublicKeyResponse, error) {
+	if name == "" {
+		return models.PublicKeyResponse{}, ErrBadArguments
+	}
+
+	key, err := s.userMS.GetPublicKey(ctx, name)
+	if err != nil {
+		return models.PublicKeyResponse{}, s.mapError(err)
+	}
+	return models.PublicKeyResponse{User: name, PublicKey: key}, nil
+}
+
+// SetKeyPair stores the user's key pair, replacing the previous one.
+// The private key is expected to be encrypted with the user's password.
+func (s *KeyService) SetKeyPair(ctx context.Context, uid string, req models.KeyPairRequest) error {
+	if uid == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.userMS.SetKeyPair(ctx, user.KeyPair{
+		UID:        uid,
+		PublicKey:  req.PublicKey,
+		PrivateKey: req.PrivateKey,
+	}))
+}
+
+func (s *KeyService) mapError(err error) error {
+	if errors.Is(err, user.ErrNoKeyPair) {
+		return ErrKeyNotFound
+	}
+	if errors.Is(err, user.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	if errors.Is(err, user.ErrInvalidKeyPair) {
+		return ErrBadArguments
+	}
+	return err
+}
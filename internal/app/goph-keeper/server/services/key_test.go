@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestNewKeyService(t *testing.T) {
+	_, us := initSessionUserMS(t)
+	assert.Equal(t, &KeyService{userMS: us}, NewKeyService(us))
+}
+
+func TestKeyService_SetKeyPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.KeyPairRequest
+		wantErr error
+	}{
+		{
+			name:    "Public key of wrong size",
+			req:     models.KeyPairRequest{PublicKey: []byte("public"), PrivateKey: []byte("private")},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Missing private key",
+			req:     models.KeyPairRequest{PublicKey: bytes.Repeat([]byte{1}, 32)},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Key pair is stored",
+			req:  models.KeyPairRequest{PublicKey: bytes.Repeat([]byte{1}, 32), PrivateKey: []byte("private")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, uid := initKeyService(t)
+			_, err := s.GetKeyPair(context.Background(), uid)
+			assert.Equal(t, ErrKeyNotFound, err)
+
+			err = s.SetKeyPair(context.Background(), uid, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			kp, gErr := s.GetKeyPair(context.Background(), uid)
+			assert.NoError(t, gErr)
+			assert.Equal(t, models.KeyPairResponse{PublicKey: tt.req.PublicKey, PrivateKey: tt.req.PrivateKey}, kp)
+
+			pk, gErr := s.GetPublicKey(context.Background(), "test")
+			assert.NoError(t, gErr)
+			assert.Equal(t, models.PublicKeyResponse{User: "test", PublicKey: tt.req.PublicKey}, pk)
+		})
+	}
+}
+
+func TestKeyService_GetPublicKey(t *testing.T) {
+	s, _ := initKeyService(t)
+
+	_, err := s.GetPublicKey(context.Background(), "")
+	assert.Equal(t, ErrBadArguments, err)
+	_, err = s.GetPublicKey(context.Background(), "unknown")
+	assert.Equal(t, ErrUserNotFound, err)
+	_, err = s.GetPublicKey(context.Background(), "test")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func initKeyService(t *testing.T) (*KeyService, string) {
+	_, us := initSessionUserMS(t)
+	if err := us.AddUser(context.Background(), user.User{Name: "test", Password: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := us.GetUserByName(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKeyService(us), u.ID
+}
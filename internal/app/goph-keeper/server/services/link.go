@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+)
+
+const adHocLinkName = "secret"
+
+type LinkService struct {
+	linkMS link.Service
+	dataMS data.Service
+}
+
+var ErrLinkNotFound = errors.New("requested link not found or expired")
+
+// NewLinkService returns an instance of the LinkService with pre-defined link and data microservices.
+func NewLinkService(linkMS link.Service, dataMS data.Service) *LinkService {
+	return &LinkService{linkMS: linkMS, dataMS: dataMS}
+}
+
+// CreateLink creates the one-time link to the user's item or to the ad-hoc text.
+// The method returns the key the secret is encrypted with. The key isn't stored and can't be restored later.
+func (s *LinkService) CreateLink(ctx context.Context, uid string,
+	req models.LinkRequest,
+) (models.LinkResponse, string, error) {
+	if uid == "" || req.MaxViews <= 0 || req.ExpiresAt.IsZero() || (req.ItemID == "") == (req.Text == "") {
+		return models.LinkResponse{}, "", ErrBadArguments
+	}
+
+	secret, name, err := s.getSecret(ctx, uid, req)
+	if err != nil {
+		return models.LinkResponse{}, "", err
+	}
+	if req.Name != "" {
+		name = req.Name
+	}
+
+	key, l, err := s.linkMS.CreateLink(ctx, uid, name, secret, req.MaxViews, req.ExpiresAt)
+	if err != nil {
+		return models.LinkResponse{}, "", s.mapError(err)
+	}
+	return s.getResponseFromModel(l), key, nil
+}
+
+// ConsumeLink counts the view of the link and returns the encrypted secret.
+// The link can be consumed by anyone knowing its ID, the secret can only be decrypted with the link key.
+func (s *LinkService) ConsumeLink(ctx context.Context, id string) (models.LinkSecretResponse, error) {
+	if id == "" {
+		return models.LinkSecretResponse{}, ErrBadArguments
+	}
+
+	l, err := s.linkMS.ConsumeLink(ctx, id)
+	if err != nil {
+		return models.LinkSecretResponse{}, s.mapError(err)
+	}
+	return models.LinkSecretResponse{
+		Name:      l.Name,
+		Data:      l.Data,
+		ViewsLeft: l.MaxViews - l.Views,
+		ExpiresAt: l.ExpiresAt,
+	}, nil
+}
+
+// DeleteLink revokes the user's link with the unique ID.
+func (s *LinkService) DeleteLink(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.linkMS.DeleteLink(ctx, uid, id))
+}
+
+// GetLinks returns the user's active links.
+func (s *LinkService) GetLinks(ctx context.Context, uid string) ([]models.LinkResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	links, err := s.linkMS.GetUserLinks(ctx, uid)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	resp := make([]models.LinkResponse, 0, len(links))
+	for _, l := range links {
+		resp = append(resp, s.getResponseFromModel(l))
+	}
+	return resp, nil
+}
+
+// getSecret returns the decrypted payload of the user's own item, or the ad-hoc text.
+// The items shared with the user can't be shared further.
+func (s *LinkService) getSecret(ctx context.Context, uid string, req models.LinkRequest) ([]byte, string, error) {
+	if req.ItemID == "" {
+		return []byte(req.Text), adHocLinkName, nil
+	}
+
+	t, ok := getStorageType(req.Type)
+	if !ok {
+		return nil, "", ErrBadArguments
+	}
+
+	d, err := s.dataMS.GetDataByID(ctx, uid, req.ItemID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, "", ErrItemNotFound
+		}
+		return nil, "", err
+	}
+	if d.Shared || d.Type != t {
+		return nil, "", ErrItemNotFound
+	}
+
+	secret, err := s.dataMS.DecryptSecureData(uid, d)
+	return secret, req.Type, err
+}
+
+func (s *LinkService) getResponseFromModel(l link.Link) models.LinkResponse {
+	return models.LinkResponse{
+		ID:        l.ID,
+		Name:      l.Name,
+		MaxViews:  l.MaxViews,
+		Views:     l.Views,
+		CreatedAt: l.CreatedAt,
+		ExpiresAt: l.ExpiresAt,
+	}
+}
+
+func (s *LinkService) mapError(err error) error {
+	if errors.Is(err, link.ErrNotFound) {
+		return ErrLinkNotFound
+	}
+	if errors.Is(err, link.ErrInvalidExpiry) || errors.Is(err, link.ErrInvalidViews) ||
+		errors.Is(err, link.ErrMissingArgs) {
+		return ErrBadArguments
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+)
+
+func TestNewLinkService(t *testing.T) {
+	ls := initLinkMS(t)
+	ds := initDataMS(t)
+	assert.Equal(t, &LinkService{linkMS: ls, dataMS: ds}, NewLinkService(ls, ds))
+}
+
+func TestLinkService_CreateLink(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name       string
+		req        models.LinkRequest
+		wantName   string
+		wantSecret string
+		wantErr    error
+	}{
+		{
+			name:    "Missing views limit",
+			req:     models.LinkRequest{Text: "secret", ExpiresAt: expiresAt},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Both item and text are passed",
+			req:     models.LinkRequest{Type: "text", ItemID: "item", Text: "secret", MaxViews: 1, ExpiresAt: expiresAt},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Expiry in the past",
+			req:     models.LinkRequest{Text: "secret", MaxViews: 1, ExpiresAt: time.Now().Add(-time.Hour)},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown item type",
+			req:     models.LinkRequest{Type: "unknown", ItemID: "item", MaxViews: 1, ExpiresAt: expiresAt},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Item of another type",
+			req:     models.LinkRequest{Type: "card", ItemID: "item", MaxViews: 1, ExpiresAt: expiresAt},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:       "Link to the ad-hoc text",
+			req:        models.LinkRequest{Text: "secret", MaxViews: 1, ExpiresAt: expiresAt},
+			wantName:   adHocLinkName,
+			wantSecret: "secret",
+		},
+		{
+			name:       "Link to the item",
+			req:        models.LinkRequest{Type: "text", ItemID: "item", MaxViews: 2, ExpiresAt: expiresAt},
+			wantName:   "text",
+			wantSecret: "test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, uid, id := initLinkService(t)
+			if tt.req.ItemID == "item" {
+				tt.req.ItemID = id
+			}
+
+			l, key, err := s.CreateLink(context.Background(), uid, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantName, l.Name)
+
+			secret, err := s.ConsumeLink(context.Background(), l.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.req.MaxViews-1, secret.ViewsLeft)
+
+			k, err := base64.RawURLEncoding.DecodeString(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := enc.DecryptDataWithKey(k, secret.Data)
+			assert.NoError(t, err)
+
+			if tt.req.ItemID != "" {
+				var text models.ItemRequest
+				assert.NoError(t, json.Unmarshal(b, &text))
+				b = []byte(text.Fields["data"])
+			}
+			assert.Equal(t, tt.wantSecret, string(b))
+		})
+	}
+}
+
+func TestLinkService_DeleteLink(t *testing.T) {
+	s, uid, _ := initLinkService(t)
+	l, _, err := s.CreateLink(context.Background(), uid, models.LinkRequest{
+		Text:      "secret",
+		MaxViews:  1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links, err := s.GetLinks(context.Background(), uid)
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+
+	assert.Equal(t, ErrBadArguments, s.DeleteLink(context.Background(), uid, ""))
+	assert.Equal(t, ErrLinkNotFound, s.DeleteLink(context.Background(), "another", l.ID))
+	assert.NoError(t, s.DeleteLink(context.Background(), uid, l.ID))
+
+	_, err = s.ConsumeLink(context.Background(), l.ID)
+	assert.Equal(t, ErrLinkNotFound, err)
+}
+
+func initLinkService(t *testing.T) (*LinkService, string, string) {
+	ds := initDataMS(t)
+	id, err := ds.StoreSecureDataFromPayload(context.Background(), "owner", models.ItemRequest{Fields: map[string]string{
+		"name": "test",
+		"data": "test",
+	}}, data.SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewLinkService(initLinkMS(t), ds), "owner", id
+}
+
+func initLinkMS(t *testing.T) link.Service {
+	ls, err := link.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ls
+}
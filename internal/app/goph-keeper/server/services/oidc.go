@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/auth"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/session"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+type OIDCService struct {
+	authMS auth.Service
+	oidcMS oidc.Service
+}
+
+// NewOIDCService returns an instance of the OIDCService with pre-defined auth and OIDC microservices.
+func NewOIDCService(oidcMS oidc.Service, sessionMS session.Service, userMS user.Service) *OIDCService {
+	return &OIDCService{authMS: auth.NewService(sessionMS, userMS), oidcMS: oidcMS}
+}
+
+// StartLogin returns the identity provider URL the user should visit to sign in.
+// The provider redirects back to the passed URI with the authorization code and the returned state.
+func (s *OIDCService) StartLogin(ctx context.Context, req models.OIDCLoginRequest) (models.OIDCLoginResponse, error) {
+	if u, err := url.Parse(req.RedirectURI); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return models.OIDCLoginResponse{}, ErrBadArguments
+	}
+
+	authURL, state, err := s.oidcMS.StartLogin(ctx, req.RedirectURI)
+	if err != nil {
+		return models.OIDCLoginResponse{}, err
+	}
+	return models.OIDCLoginResponse{URL: authURL, State: state}, nil
+}
+
+// FinishLogin redeems the authorization code and establishes the session of the linked user.
+// The user signing in for the first time is provisioned automatically.
+func (s *OIDCService) FinishLogin(ctx context.Context, req models.OIDCCallbackRequest,
+	client models.ClientInfo,
+) (string, string, error) {
+	if req.State == "" || req.Code == "" {
+		return "", "", ErrBadArguments
+	}
+
+	uid, err := s.oidcMS.FinishLogin(ctx, req.State, req.Code)
+	if err != nil {
+		if errors.Is(err, oidc.ErrNotFound) || errors.Is(err, oidc.ErrStateExpired) ||
+			errors.Is(err, oidc.ErrIDToken) || errors.Is(err, oidc.ErrProvider) {
+			return "", "", ErrWrongCredential
+		}
+		return "", "", err
+	}
+
+	return s.authMS.LoginByID(ctx, uid, session.Client{
+		Device:    client.Device,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	})
+}
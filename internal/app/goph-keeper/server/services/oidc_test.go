@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc/oidctest"
+)
+
+const testRedirectURI = "http://127.0.0.1:8080/callback"
+
+func TestOIDCService_StartLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.OIDCLoginRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing redirect URI",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Relative redirect URI",
+			req:     models.OIDCLoginRequest{RedirectURI: "/callback"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Login is started",
+			req:  models.OIDCLoginRequest{RedirectURI: testRedirectURI},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initOIDCService(t)
+			got, err := s.StartLogin(context.Background(), tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got.URL != "" && got.State != "")
+		})
+	}
+}
+
+func TestOIDCService_FinishLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.OIDCCallbackRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing code",
+			req:     models.OIDCCallbackRequest{State: "state"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown state",
+			req:     models.OIDCCallbackRequest{State: "unknown", Code: "code"},
+			wantErr: ErrWrongCredential,
+		},
+		{
+			name: "Session is established",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, idp := initOIDCService(t)
+			if tt.req.State == "" {
+				tt.req = startOIDCLogin(t, s, idp)
+			}
+
+			token, cid, err := s.FinishLogin(context.Background(), tt.req, models.ClientInfo{Device: "laptop"})
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				uid, aErr := s.authMS.Authorize(context.Background(), cid, token)
+				assert.NoError(t, aErr)
+				assert.NotEmpty(t, uid)
+			}
+		})
+	}
+}
+
+func initOIDCService(t *testing.T) (*OIDCService, *oidctest.Server) {
+	idp, err := oidctest.NewServer("goph-keeper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+
+	ss, us := initSessionUserMS(t)
+	oidcMS, err := oidc.NewService(nil, nil, oidc.Config{Issuer: idp.URL, ClientID: idp.ClientID}, us)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewOIDCService(oidcMS, ss, us), idp
+}
+
+func startOIDCLogin(t *testing.T, s *OIDCService, idp *oidctest.Server) models.OIDCCallbackRequest {
+	res, err := s.StartLogin(context.Background(), models.OIDCLoginRequest{RedirectURI: testRedirectURI})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redirect, err := idp.Authorize(res.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.OIDCCallbackRequest{State: redirect.Query().Get("state"), Code: redirect.Query().Get("code")}
+}
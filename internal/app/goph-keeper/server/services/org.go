@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+type OrgService struct {
+	orgMS  org.Service
+	userMS user.Service
+}
+
+var (
+	ErrForbidden   = errors.New("the organization role doesn't permit the request")
+	ErrOrgNotFound = errors.New("requested organization or collection not found")
+)
+
+// NewOrgService returns an instance of the OrgService with pre-defined organization and user microservices.
+func NewOrgService(orgMS org.Service, userMS user.Service) *OrgService {
+	return &OrgService{orgMS: orgMS, userMS: userMS}
+}
+
+// AuthorizeVault checks if the user is a member of the organization owning the collection,
+// and returns the user's role in it.
+func (s *OrgService) AuthorizeVault(ctx context.Context, uid, id string) (org.Role, error) {
+	if uid == "" || id == "" {
+		return "", ErrBadArguments
+	}
+
+	role, err := s.orgMS.AuthorizeVault(ctx, uid, id)
+	if errors.Is(err, org.ErrNotFound) {
+		return "", ErrForbidden
+	}
+	return role, s.mapError(err)
+}
+
+// CreateOrganization stores a new organization with the user as its owner.
+func (s *OrgService) CreateOrganization(ctx context.Context, uid string, req models.OrgRequest) (string, error) {
+	if uid == "" || req.Name == "" {
+		return "", ErrBadArguments
+	}
+	id, err := s.orgMS.CreateOrganization(ctx, uid, req.Name)
+	return id, s.mapError(err)
+}
+
+// DeleteOrganization removes the organization along with all its collections and items.
+func (s *OrgService) DeleteOrganization(ctx context.Context, uid, orgID string) error {
+	if uid == "" || orgID == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.orgMS.DeleteOrganization(ctx, uid, orgID))
+}
+
+// GetOrganizations returns the list of organizations the user is a member of, along with the user's role.
+func (s *OrgService) GetOrganizations(ctx context.Context, uid string) ([]models.OrgResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	ms, err := s.orgMS.GetOrganizations(ctx, uid)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	orgs := make([]models.OrgResponse, 0, len(ms))
+	for _, m := range ms {
+		orgs = append(orgs, models.OrgResponse{ID: m.ID, Name: m.Name, Role: string(m.Role), CreatedAt: m.CreatedAt})
+	}
+	return orgs, nil
+}
+
+// GetMembers returns the list of the organization members with their names and roles.
+func (s *OrgService) GetMembers(ctx context.Context, uid, orgID string) ([]models.MemberResponse, error) {
+	if uid == "" || orgID == "" {
+		return nil, ErrBadArguments
+	}
+
+	ms, err := s.orgMS.GetMembers(ctx, uid, orgID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	members := make([]models.MemberResponse, 0, len(ms))
+	for _, m := range ms {
+		u, uErr := s.userMS.GetUserByID(ctx, m.UID)
+		if uErr != nil {
+			return nil, uErr
+		}
+		members = append(members, models.MemberResponse{User: u.Name, Role: string(m.Role), CreatedAt: m.CreatedAt})
+	}
+	return members, nil
+}
+
+// SetMember adds the user with the specified name to the organization, or changes the role of the member.
+func (s *OrgService) SetMember(ctx context.Context, uid, orgID string, req models.MemberRequest) error {
+	if uid == "" || orgID == "" || req.User == "" {
+		return ErrBadArguments
+	}
+
+	u, err := s.getUserByName(ctx, req.User)
+	if err != nil {
+		return err
+	}
+	return s.mapError(s.orgMS.SetMember(ctx, uid, orgID, u.ID, org.Role(req.Role)))
+}
+
+// RemoveMember removes the user with the specified name from the organization.
+func (s *OrgService) RemoveMember(ctx context.Context, uid, orgID, name string) error {
+	if uid == "" || orgID == "" || name == "" {
+		return ErrBadArguments
+	}
+
+	u, err := s.getUserByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.mapError(s.orgMS.RemoveMember(ctx, uid, orgID, u.ID))
+}
+
+// CreateCollection stores a new collection in the organization.
+func (s *OrgService) CreateCollection(ctx context.Context, uid, orgID string,
+	req models.CollectionRequest,
+) (string, error) {
+	if uid == "" || orgID == "" || req.Name == "" {
+		return "", ErrBadArguments
+	}
+	id, err := s.orgMS.CreateCollection(ctx, uid, orgID, req.Name)
+	return id, s.mapError(err)
+}
+
+// DeleteCollection removes the organization collection along with its items.
+func (s *OrgService) DeleteCollection(ctx context.Context, uid, orgID, id string) error {
+	if uid == "" || orgID == "" || id == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.orgMS.DeleteCollection(ctx, uid, orgID, id))
+}
+
+// GetCollections returns the list of the organization collections.
+func (s *OrgService) GetCollections(ctx context.Context, uid, orgID string) ([]models.CollectionResponse, error) {
+	if uid == "" || orgID == "" {
+		return nil, ErrBadArguments
+	}
+
+	cs, err := s.orgMS.GetCollections(ctx, uid, orgID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	collections := make([]models.CollectionResponse, 0, len(cs))
+	for _, c := range cs {
+		collections = append(collections, models.CollectionResponse{ID: c.ID, Name: c.Name, CreatedAt: c.CreatedAt})
+	}
+	return collections, nil
+}
+
+func (s *OrgService) getUserByName(ctx context.Context, name string) (user.User, error) {
+	u, err := s.userMS.GetUserByName(ctx, name)
+	if errors.Is(err, user.ErrNotFound) {
+		return user.User{}, ErrUserNotFound
+	}
+	return u, err
+}
+
+func (s *OrgService) mapError(err error) error {
+	switch {
+	case errors.Is(err, org.ErrNotFound):
+		return ErrOrgNotFound
+	case errors.Is(err, org.ErrForbidden):
+		return ErrForbidden
+	case errors.Is(err, org.ErrInvalidRole), errors.Is(err, org.ErrMissingArgs):
+		return ErrBadArguments
+	}
+	return err
+}
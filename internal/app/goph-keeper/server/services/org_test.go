@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestNewOrgService(t *testing.T) {
+	os := initOrgMS(t)
+	_, us := initSessionUserMS(t)
+	assert.Equal(t, &OrgService{orgMS: os, userMS: us}, NewOrgService(os, us))
+}
+
+func TestOrgService_AuthorizeVault(t *testing.T) {
+	s, owner, orgID := initOrgService(t)
+	cid, err := s.CreateCollection(context.Background(), owner, orgID, models.CollectionRequest{Name: "infra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.userMS.GetUserByName(context.Background(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uid     string
+		id      string
+		want    org.Role
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown collection",
+			uid:     owner,
+			id:      "unknown",
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "User is not a member",
+			uid:     u.ID,
+			id:      cid,
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "Member is authorized",
+			uid:  owner,
+			id:   cid,
+			want: org.RoleOwner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.AuthorizeVault(context.Background(), tt.uid, tt.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestOrgService_SetMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.MemberRequest
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "User is not present",
+			req:     models.MemberRequest{User: "unknown", Role: "member"},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name:    "Role is invalid",
+			req:     models.MemberRequest{User: "user", Role: "guest"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Member is added",
+			req:  models.MemberRequest{User: "user", Role: "read-only"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, owner, orgID := initOrgService(t)
+			err := s.SetMember(context.Background(), owner, orgID, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			members, gErr := s.GetMembers(context.Background(), owner, orgID)
+			assert.NoError(t, gErr)
+			if assert.Len(t, members, 2) {
+				assert.Equal(t, "owner", members[0].User)
+				assert.Equal(t, tt.req.User, members[1].User)
+				assert.Equal(t, tt.req.Role, members[1].Role)
+			}
+		})
+	}
+}
+
+func TestOrgService_RemoveMember(t *testing.T) {
+	s, owner, orgID := initOrgService(t)
+	if err := s.SetMember(context.Background(), owner, orgID,
+		models.MemberRequest{User: "user", Role: "member"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.userMS.GetUserByName(context.Background(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ErrForbidden, s.RemoveMember(context.Background(), u.ID, orgID, "owner"))
+	assert.Equal(t, org.ErrLastOwner, s.RemoveMember(context.Background(), owner, orgID, "owner"))
+	assert.NoError(t, s.RemoveMember(context.Background(), owner, orgID, "user"))
+
+	_, err = s.GetCollections(context.Background(), u.ID, orgID)
+	assert.Equal(t, ErrOrgNotFound, err)
+}
+
+func TestOrgService_GetOrganizations(t *testing.T) {
+	s, owner, orgID := initOrgService(t)
+	got, err := s.GetOrganizations(context.Background(), owner)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, orgID, got[0].ID)
+		assert.Equal(t, "team", got[0].Name)
+		assert.Equal(t, string(org.RoleOwner), got[0].Role)
+	}
+}
+
+func initOrgMS(t *testing.T) org.Service {
+	os, err := org.NewService(nil, nil, initDataMS(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os
+}
+
+func initOrgService(t *testing.T) (*OrgService, string, string) {
+	_, us := initSessionUserMS(t)
+	for _, name := range []string{"owner", "user"} {
+		if err := us.AddUser(context.Background(), user.User{Name: name, Password: "test"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	owner, err := us.GetUserByName(context.Background(), "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewOrgService(initOrgMS(t), us)
+	id, err := s.CreateOrganization(context.Background(), owner.ID, models.OrgRequest{Name: "team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, owner.ID, id
+}
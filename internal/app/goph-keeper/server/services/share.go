@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+type ShareService struct {
+	dataMS data.Service
+	orgMS  org.Service
+	userMS user.Service
+}
+
+var (
+	ErrItemNotFound = errors.New("requested item not found")
+	ErrItemReadOnly = errors.New("the item is shared as read-only")
+	ErrUserNotFound = errors.New("requested user not found")
+)
+
+// NewShareService returns an instance of the ShareService with pre-defined data, organization and user microservices.
+// The organization microservice names the collections sharing the items stored in them.
+func NewShareService(dataMS data.Service, orgMS org.Service, userMS user.Service) *ShareService {
+	return &ShareService{dataMS: dataMS, orgMS: orgMS, userMS: userMS}
+}
+
+// GetShares returns the list of the users the item is shared with.
+// The method returns the shares of the items owned by the specified user or collection only.
+// The shares of the removed users are skipped.
+func (s *ShareService) GetShares(ctx context.Context, uid, id string,
+	t data.StorageType,
+) ([]models.ShareResponse, error) {
+	if uid == "" || id == "" {
+		return nil, ErrBadArguments
+	}
+	if err := s.checkOwnedItem(ctx, uid, id, t); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.dataMS.GetShares(ctx, uid, id)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	resp := make([]models.ShareResponse, 0, len(shares))
+	for _, sh := range shares {
+		u, uErr := s.userMS.GetUserByID(ctx, sh.UID)
+		if errors.Is(uErr, user.ErrNotFound) {
+			continue
+		}
+		if uErr != nil {
+			return nil, uErr
+		}
+		resp = append(resp, models.ShareResponse{User: u.Name, ReadOnly: sh.ReadOnly, CreatedAt: sh.CreatedAt})
+	}
+	return resp, nil
+}
+
+// RevokeShare removes the access to the item from the user with the specified name.
+// The access is removed immediately, the item is no longer listed for the user.
+func (s *ShareService) RevokeShare(ctx context.Context, uid, id, name string, t data.StorageType) error {
+	if uid == "" || id == "" || name == "" {
+		return ErrBadArguments
+	}
+	if err := s.checkOwnedItem(ctx, uid, id, t); err != nil {
+		return err
+	}
+
+	u, err := s.getUserByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.mapError(s.dataMS.RevokeShare(ctx, uid, id, u.ID))
+}
+
+// ShareItem grants the access to the item to the user with the specified name.
+// Sharing the item with the same user again replaces the access level.
+func (s *ShareService) ShareItem(ctx context.Context, uid, id string, t data.StorageType,
+	req models.ShareRequest,
+) error {
+	if uid == "" || id == "" || req.User == "" {
+		return ErrBadArguments
+	}
+	if err := s.checkOwnedItem(ctx, uid, id, t); err != nil {
+		return err
+	}
+
+	u, err := s.getUserByName(ctx, req.User)
+	if err != nil {
+		return err
+	}
+	return s.mapError(s.dataMS.ShareData(ctx, uid, id, u.ID, req.ReadOnly))
+}
+
+// ShareItemSealed stores the copy of the item sealed to the user with the specified name.
+// The copy is encrypted on the owner's client, so the server only keeps the opaque data.
+func (s *ShareService) ShareItemSealed(ctx context.Context, uid, id string, t data.StorageType,
+	req models.SealedShareRequest,
+) (string, error) {
+	if uid == "" || id == "" || req.User == "" {
+		return "", ErrBadArguments
+	}
+	if err := s.checkOwnedItem(ctx, uid, id, t); err != nil {
+		return "", err
+	}
+
+	u, err := s.getUserByName(ctx, req.User)
+	if err != nil {
+		return "", err
+	}
+
+	sid, err := s.dataMS.ShareSealedData(ctx, data.SealedShare{
+		ItemID: id,
+		Owner:  uid,
+		UID:    u.ID,
+		Data:   req.Data,
+		Key:    req.Key,
+	})
+	return sid, s.mapError(err)
+}
+
+// GetSealedItems returns the sealed copies of the items shared with the user.
+// The copies shared from the organization collections are labelled with the collection name,
+// and the ones whose owner is removed are skipped.
+func (s *ShareService) GetSealedItems(ctx context.Context, uid string) ([]models.SealedItemResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+
+	shares, err := s.dataMS.GetSealedShares(ctx, uid)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	resp := make([]models.SealedItemResponse, 0, len(shares))
+	for _, sh := range shares {
+		owner, oErr := s.getOwnerName(ctx, sh.Owner)
+		if errors.Is(oErr, user.ErrNotFound) {
+			continue
+		}
+		if oErr != nil {
+			return nil, oErr
+		}
+		resp = append(resp, models.SealedItemResponse{
+			ID:        sh.ID,
+			ItemID:    sh.ItemID,
+			Owner:     owner,
+			Type:      getTypeName(sh.Type),
+			Data:      sh.Data,
+			Key:       sh.Key,
+			CreatedAt: sh.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// DeleteSealedItem removes the sealed copy of the item shared with the user.
+func (s *ShareService) DeleteSealedItem(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.dataMS.DeleteSealedShare(ctx, uid, id))
+}
+
+// checkOwnedItem makes sure the item of the specified type belongs to the user.
+// The items shared with the user can't be shared further.
+func (s *ShareService) checkOwnedItem(ctx context.Context, uid, id string, t data.StorageType) error {
+	d, err := s.dataMS.GetDataByID(ctx, uid, id)
+	if err != nil {
+		return s.mapError(err)
+	}
+	if d.Shared || d.Type != t {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// getOwnerName returns the name of the user owning the shared item, or the label of the collection storing it.
+// The user.ErrNotFound is returned if the owner is neither of them.
+func (s *ShareService) getOwnerName(ctx context.Context, id string) (string, error) {
+	u, err := s.userMS.GetUserByID(ctx, id)
+	if !errors.Is(err, user.ErrNotFound) {
+		return u.Name, err
+	}
+
+	c, err := s.orgMS.GetCollection(ctx, id)
+	if errors.Is(err, org.ErrNotFound) {
+		return "", user.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s (collection)", c.Name), nil
+}
+
+func (s *ShareService) getUserByName(ctx context.Context, name string) (user.User, error) {
+	u, err := s.userMS.GetUserByName(ctx, name)
+	if errors.Is(err, user.ErrNotFound) {
+		return user.User{}, ErrUserNotFound
+	}
+	return u, err
+}
+
+func (s *ShareService) mapError(err error) error {
+	if errors.Is(err, data.ErrNotFound) {
+		return ErrItemNotFound
+	}
+	if errors.Is(err, data.ErrShareSelf) || errors.Is(err, data.ErrMissingArgs) ||
+		errors.Is(err, data.ErrEmpty) {
+		return ErrBadArguments
+	}
+	return err
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestNewShareService(t *testing.T) {
+	ds := initDataMS(t)
+	_, us := initSessionUserMS(t)
+	om := initOrgMS(t)
+	assert.Equal(t, &ShareService{dataMS: ds, orgMS: om, userMS: us}, NewShareService(ds, om, us))
+}
+
+func TestShareService_ShareItem(t *testing.T) {
+	type args struct {
+		id  string
+		t   data.StorageType
+		req models.ShareRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Item is not present",
+			args:    args{id: "test", t: data.SPassword, req: models.ShareRequest{User: "user"}},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:    "Item of another type",
+			args:    args{id: "item", t: data.SCard, req: models.ShareRequest{User: "user"}},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:    "User is not present",
+			args:    args{id: "item", t: data.SPassword, req: models.ShareRequest{User: "unknown"}},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name:    "Item is shared with the owner",
+			args:    args{id: "item", t: data.SPassword, req: models.ShareRequest{User: "owner"}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Item is shared",
+			args: args{id: "item", t: data.SPassword, req: models.ShareRequest{User: "user", ReadOnly: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, owner, id := initShareService(t)
+			if tt.args.id == "item" {
+				tt.args.id = id
+			}
+
+			err := s.ShareItem(context.Background(), owner, tt.args.id, tt.args.t, tt.args.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			shares, gErr := s.GetShares(context.Background(), owner, id, data.SPassword)
+			assert.NoError(t, gErr)
+			if assert.Len(t, shares, 1) {
+				assert.Equal(t, tt.args.req.User, shares[0].User)
+				assert.Equal(t, tt.args.req.ReadOnly, shares[0].ReadOnly)
+			}
+		})
+	}
+}
+
+func TestShareService_RevokeShare(t *testing.T) {
+	s, owner, id := initShareService(t)
+	if err := s.ShareItem(context.Background(), owner, id, data.SPassword, models.ShareRequest{User: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.userMS.GetUserByName(context.Background(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ErrBadArguments, s.RevokeShare(context.Background(), owner, id, "", data.SPassword))
+	assert.Equal(t, ErrItemNotFound, s.RevokeShare(context.Background(), u.ID, id, "user", data.SPassword))
+	assert.Equal(t, ErrUserNotFound, s.RevokeShare(context.Background(), owner, id, "unknown", data.SPassword))
+
+	_, err = s.dataMS.GetDataByID(context.Background(), u.ID, id)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.RevokeShare(context.Background(), owner, id, "user", data.SPassword))
+	_, err = s.dataMS.GetDataByID(context.Background(), u.ID, id)
+	assert.Equal(t, data.ErrNotFound, err)
+	assert.Equal(t, ErrItemNotFound, s.RevokeShare(context.Background(), owner, id, "user", data.SPassword))
+}
+
+func TestShareService_ShareItemSealed(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       data.StorageType
+		req     models.SealedShareRequest
+		wantErr error
+	}{
+		{
+			name:    "Missing user",
+			t:       data.SPassword,
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Item of another type",
+			t:       data.SCard,
+			req:     models.SealedShareRequest{User: "user", Data: []byte("data"), Key: []byte("key")},
+			wantErr: ErrItemNotFound,
+		},
+		{
+			name:    "Missing sealed data",
+			t:       data.SPassword,
+			req:     models.SealedShareRequest{User: "user"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Item is sealed to the owner",
+			t:       data.SPassword,
+			req:     models.SealedShareRequest{User: "owner", Data: []byte("data"), Key: []byte("key")},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Item is sealed",
+			t:    data.SPassword,
+			req:  models.SealedShareRequest{User: "user", Data: []byte("data"), Key: []byte("key")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, owner, id := initShareService(t)
+			sid, err := s.ShareItemSealed(context.Background(), owner, id, tt.t, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			u, err := s.userMS.GetUserByName(context.Background(), tt.req.User)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			items, gErr := s.GetSealedItems(context.Background(), u.ID)
+			assert.NoError(t, gErr)
+			if assert.Len(t, items, 1) {
+				assert.Equal(t, sid, items[0].ID)
+				assert.Equal(t, "owner", items[0].Owner)
+				assert.Equal(t, "password", items[0].Type)
+				assert.Equal(t, tt.req.Data, items[0].Data)
+			}
+
+			assert.Equal(t, ErrItemNotFound, s.DeleteSealedItem(context.Background(), u.ID, "unknown"))
+			assert.NoError(t, s.DeleteSealedItem(context.Background(), u.ID, sid))
+		})
+	}
+}
+
+func TestShareService_GetSealedItems_Owners(t *testing.T) {
+	ctx := context.Background()
+	s, owner, id := initShareService(t)
+	orgID, err := s.orgMS.CreateOrganization(ctx, owner, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cid, err := s.orgMS.CreateCollection(ctx, owner, orgID, "vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vid, err := s.dataMS.StoreSecureDataFromPayload(ctx, cid, models.ItemRequest{Fields: map[string]string{
+		"name": "test",
+	}}, data.SPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := models.SealedShareRequest{User: "user", Data: []byte("data"), Key: []byte("key")}
+	for oid, iid := range map[string]string{owner: id, cid: vid} {
+		if _, err = s.ShareItemSealed(ctx, oid, iid, data.SPassword, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u, err := s.userMS.GetUserByName(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetSealedItems(ctx, u.ID)
+	assert.NoError(t, err)
+	owners := make([]string, 0, len(got))
+	for _, i := range got {
+		owners = append(owners, i.Owner)
+	}
+	assert.ElementsMatch(t, []string{"owner", "vault (collection)"}, owners)
+
+	// The copies of the removed owner are skipped rather than failing the list.
+	if err = s.userMS.DeleteUser(ctx, owner); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.GetSealedItems(ctx, u.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "vault (collection)", got[0].Owner)
+	}
+}
+
+func TestShareService_GetShares_RemovedUser(t *testing.T) {
+	ctx := context.Background()
+	s, owner, id := initShareService(t)
+	if err := s.ShareItem(ctx, owner, id, data.SPassword, models.ShareRequest{User: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.userMS.GetUserByName(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.userMS.DeleteUser(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetShares(ctx, owner, id, data.SPassword)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func initShareService(t *testing.T) (*ShareService, string, string) {
+	ds := initDataMS(t)
+	_, us := initSessionUserMS(t)
+	for _, name := range []string{"owner", "user"} {
+		if err := us.AddUser(context.Background(), user.User{Name: name, Password: "test"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	owner, err := us.GetUserByName(context.Background(), "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := ds.StoreSecureDataFromPayload(context.Background(), owner.ID, models.ItemRequest{Fields: map[string]string{
+		"name": "test",
+	}},
+		data.SPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewShareService(ds, initOrgMS(t), us), owner.ID, id
+}
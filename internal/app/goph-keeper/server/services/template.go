@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/template"
+)
+
+type TemplateService struct {
+	templateMS template.Service
+}
+
+var (
+	ErrTemplateExists   = errors.New("the template with the same name already exists")
+	ErrTemplateNotFound = errors.New("requested template not found")
+)
+
+// NewTemplateService returns an instance of the TemplateService with pre-defined template microservice.
+func NewTemplateService(dataMS data.Service) *TemplateService {
+	return &TemplateService{templateMS: template.NewService(dataMS)}
+}
+
+// DeleteTemplate removes the user's template. The items created with it keep their custom fields.
+func (s *TemplateService) DeleteTemplate(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrBadArguments
+	}
+	return s.mapError(s.templateMS.DeleteTemplate(ctx, uid, id))
+}
+
+// GetTemplates returns all the user's templates.
+func (s *TemplateService) GetTemplates(ctx context.Context, uid string) ([]models.TemplateResponse, error) {
+	if uid == "" {
+		return nil, ErrBadArguments
+	}
+	res, err := s.templateMS.GetTemplates(ctx, uid)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	resp := make([]models.TemplateResponse, 0, len(res))
+	for _, t := range res {
+		tmpl := getTemplateFromModel(t)
+		resp = append(resp, models.TemplateResponse{ID: t.ID, Name: tmpl.Name, Fields: tmpl.Fields})
+	}
+	return resp, nil
+}
+
+// StoreTemplate stores the template after checking its name and fields.
+// The template names are unique per user, since the items refer to their templates by name.
+func (s *TemplateService) StoreTemplate(ctx context.Context, uid string, req models.TemplateRequest) (string, error) {
+	tmpl := items.Template{Name: req.Name, Fields: req.Fields}
+	if uid == "" || tmpl.Validate() != nil {
+		return "", ErrBadArguments
+	}
+
+	fields := make([]template.Field, 0, len(tmpl.Fields))
+	for _, f := range tmpl.Fields {
+		fields = append(fields, template.Field{Name: f.Name, Kind: string(f.Kind), Required: f.Required})
+	}
+	id, err := s.templateMS.StoreTemplate(ctx, template.Template{UID: uid, Name: tmpl.Name, Fields: fields})
+	return id, s.mapError(err)
+}
+
+func (s *TemplateService) mapError(err error) error {
+	if errors.Is(err, template.ErrNotFound) {
+		return ErrTemplateNotFound
+	}
+	if errors.Is(err, template.ErrExists) {
+		return ErrTemplateExists
+	}
+	return err
+}
+
+func getTemplateFromModel(t template.Template) items.Template {
+	fields := make([]items.TemplateField, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		fields = append(fields, items.TemplateField{Name: f.Name, Kind: items.Kind(f.Kind), Required: f.Required})
+	}
+	return items.Template{Name: t.Name, Fields: fields}
+}
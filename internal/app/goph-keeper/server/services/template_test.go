@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/template"
+)
+
+func TestNewTemplateService(t *testing.T) {
+	ds := initDataMS(t)
+	assert.Equal(t, &TemplateService{templateMS: template.NewService(ds)}, NewTemplateService(ds))
+}
+
+func TestTemplateService_DeleteTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		id      string
+		wantErr error
+	}{
+		{
+			name:    "Missing arguments",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Unknown ID",
+			uid:     "test",
+			id:      "unknown",
+			wantErr: ErrTemplateNotFound,
+		},
+		{
+			name:    "Template of another user",
+			uid:     "other",
+			wantErr: ErrTemplateNotFound,
+		},
+		{
+			name: "Template deleted",
+			uid:  "test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, id := initTemplateService(t)
+			if tt.id == "" && tt.uid != "" {
+				tt.id = id
+			}
+			assert.Equal(t, tt.wantErr, s.DeleteTemplate(context.Background(), tt.uid, tt.id))
+		})
+	}
+}
+
+func TestTemplateService_GetTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "No templates",
+			uid:  "other",
+			want: []string{},
+		},
+		{
+			name: "User templates",
+			uid:  "test",
+			want: []string{"db"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initTemplateService(t)
+			got, err := s.GetTemplates(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			names := make([]string, 0, len(got))
+			for _, tmpl := range got {
+				names = append(names, tmpl.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestTemplateService_StoreTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		req     models.TemplateRequest
+		want    []items.TemplateField
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			req:     models.TemplateRequest{Name: "api"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Missing name",
+			uid:     "test",
+			req:     models.TemplateRequest{Name: " "},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Repeated field",
+			uid:     "test",
+			req:     models.TemplateRequest{Name: "api", Fields: []items.TemplateField{{Name: "host"}, {Name: "host "}}},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Name is taken",
+			uid:     "test",
+			req:     models.TemplateRequest{Name: "db"},
+			wantErr: ErrTemplateExists,
+		},
+		{
+			name: "Template saved",
+			uid:  "test",
+			req: models.TemplateRequest{Name: " api ", Fields: []items.TemplateField{
+				{Name: "host", Kind: items.KindURL, Required: true}, {Name: "key"},
+			}},
+			want: []items.TemplateField{
+				{Name: "host", Kind: items.KindURL, Required: true}, {Name: "key", Kind: items.KindText},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initTemplateService(t)
+			id, err := s.StoreTemplate(context.Background(), tt.uid, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			got, err := s.GetTemplates(context.Background(), tt.uid)
+			assert.NoError(t, err)
+			assert.Contains(t, got, models.TemplateResponse{ID: id, Name: "api", Fields: tt.want})
+		})
+	}
+}
+
+func initTemplateService(t *testing.T) (*TemplateService, string) {
+	t.Helper()
+	s := NewTemplateService(initDataMS(t))
+	id, err := s.StoreTemplate(context.Background(), "test", models.TemplateRequest{
+		Name:   "db",
+		Fields: []items.TemplateField{{Name: "host", Kind: items.KindURL, Required: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, id
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/archive"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/vault"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type VaultService struct {
+	vaultMS vault.Service
+}
+
+// NewVaultService returns an instance of the VaultService with pre-defined vault microservice.
+// The archives hold the section of every registered item type, named by the name of the type,
+// and the section of the user's templates. The earlier archives named the sections by the plural names.
+// The imported items are checked by the item service, exactly as the single item requests.
+func NewVaultService(uow storage.UnitOfWork, dataMS data.Service) *VaultService {
+	all := items.All()
+	sections := make([]vault.Section, 0, len(all)+1)
+	for _, t := range all {
+		sections = append(sections, vault.Section{Name: t.Name, Aliases: []string{t.Plural}, Storage: t.Storage})
+	}
+	sections = append(sections, vault.Section{Name: "templates", Storage: data.STemplate})
+	return &VaultService{vaultMS: vault.NewService(uow, dataMS, sections, NewItemService(dataMS))}
+}
+
+// ExportVault returns the archive of all items owned by the user, encrypted with the passed password.
+func (s *VaultService) ExportVault(ctx context.Context, uid string,
+	req models.VaultExportRequest,
+) (models.VaultExportResponse, error) {
+	if uid == "" || req.Password == "" {
+		return models.VaultExportResponse{}, ErrBadArguments
+	}
+
+	var buf bytes.Buffer
+	if err := s.vaultMS.Export(ctx, uid, &buf, req.Password); err != nil {
+		return models.VaultExportResponse{}, s.mapError(err)
+	}
+	return models.VaultExportResponse{Archive: buf.Bytes()}, nil
+}
+
+// ImportVault merges the archive into the user's account, handling the duplicates with the requested policy.
+func (s *VaultService) ImportVault(ctx context.Context, uid string,
+	req models.VaultImportRequest,
+) (models.VaultImportResponse, error) {
+	if uid == "" || req.Password == "" || len(req.Archive) == 0 {
+		return models.VaultImportResponse{}, ErrBadArguments
+	}
+
+	sum, err := s.vaultMS.Import(ctx, uid, bytes.NewReader(req.Archive), req.Password, vault.Duplicates(req.Duplicates))
+	if err != nil {
+		return models.VaultImportResponse{}, s.mapError(err)
+	}
+	return models.VaultImportResponse{
+		Imported:        sum.Imported,
+		Replaced:        sum.Replaced,
+		Skipped:         sum.Skipped,
+		UnknownSections: sum.UnknownSections,
+	}, nil
+}
+
+func (s *VaultService) mapError(err error) error {
+	if errors.Is(err, archive.ErrFormat) ||
+		errors.Is(err, archive.ErrIntegrity) ||
+		errors.Is(err, archive.ErrVersion) ||
+		errors.Is(err, enc.ErrPasswordLength) ||
+		errors.Is(err, vault.ErrDuplicates) ||
+		errors.Is(err, data.ErrEmpty) {
+		return ErrBadArguments
+	}
+	return err
+}
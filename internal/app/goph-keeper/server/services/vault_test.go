@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/vault"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestNewVaultService(t *testing.T) {
+	ds := initDataMS(t)
+	want := &VaultService{vaultMS: vault.NewService(storage.UnitOfWork{}, ds, []vault.Section{
+		{Name: "binary", Aliases: []string{"binaries"}, Storage: data.SBinary},
+		{Name: "card", Aliases: []string{"cards"}, Storage: data.SCard},
+		{Name: "password", Aliases: []string{"passwords"}, Storage: data.SPassword},
+		{Name: "text", Aliases: []string{"texts"}, Storage: data.SText},
+		{Name: "securenote", Aliases: []string{"secure notes"}, Storage: data.SNote},
+		{Name: "identity", Aliases: []string{"identities"}, Storage: data.SIdentity},
+		{Name: "bank", Aliases: []string{"bank accounts"}, Storage: data.SBank},
+		{Name: "ssh", Aliases: []string{"SSH keys"}, Storage: data.SSSHKey},
+		{Name: "custom", Aliases: []string{"custom items"}, Storage: data.SCustom},
+		{Name: "templates", Storage: data.STemplate},
+	}, NewItemService(ds))}
+	assert.Equal(t, want, NewVaultService(storage.UnitOfWork{}, ds))
+}
+
+func TestVaultService_ExportVault(t *testing.T) {
+	s := NewVaultService(storage.UnitOfWork{}, initDataMS(t))
+
+	_, err := s.ExportVault(context.Background(), "", models.VaultExportRequest{Password: "test"})
+	assert.Equal(t, ErrBadArguments, err)
+	_, err = s.ExportVault(context.Background(), "testID", models.VaultExportRequest{})
+	assert.Equal(t, ErrBadArguments, err)
+
+	got, err := s.ExportVault(context.Background(), "testID", models.VaultExportRequest{Password: "test"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, got.Archive)
+}
+
+func TestVaultService_ImportVault(t *testing.T) {
+	s, is := initVaultService(t)
+	res, err := s.ExportVault(context.Background(), "testID", models.VaultExportRequest{Password: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		req     models.VaultImportRequest
+		want    models.VaultImportResponse
+		wantErr error
+	}{
+		{
+			name:    "Archive is missing",
+			req:     models.VaultImportRequest{Password: "test"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Password is wrong",
+			req:     models.VaultImportRequest{Archive: res.Archive, Password: "wrong"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name:    "Policy is unknown",
+			req:     models.VaultImportRequest{Archive: res.Archive, Password: "test", Duplicates: "merge"},
+			wantErr: ErrBadArguments,
+		},
+		{
+			name: "Archive is imported",
+			req:  models.VaultImportRequest{Archive: res.Archive, Password: "test"},
+			want: models.VaultImportResponse{Imported: 1},
+		},
+		{
+			name: "Duplicate is skipped",
+			req:  models.VaultImportRequest{Archive: res.Archive, Password: "test"},
+			want: models.VaultImportResponse{Skipped: 1},
+		},
+		{
+			name: "Duplicate is replaced",
+			req:  models.VaultImportRequest{Archive: res.Archive, Password: "test", Duplicates: "replace"},
+			want: models.VaultImportResponse{Replaced: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, iErr := s.ImportVault(context.Background(), "testID1", tt.req)
+			assert.Equal(t, tt.wantErr, iErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	texts, err := is.GetAllItems(context.Background(), "testID1", items.Text)
+	assert.NoError(t, err)
+	assert.Len(t, texts, 1)
+	got, err := is.GetItemByID(context.Background(), "testID1", items.Text, texts[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ItemResponse{
+		ID:     texts[0].ID,
+		Fields: map[string]string{"name": "test", "data": "test", "note": ""},
+	}, got)
+}
+
+func TestVaultService_ImportVault_Invalid(t *testing.T) {
+	ds := initDataMS(t)
+	s := NewVaultService(storage.UnitOfWork{}, ds)
+
+	// The text stored bypassing the item checks fails the import, as the text request without the data does.
+	_, err := ds.StoreSecureDataFromPayload(context.Background(), "testID", map[string]string{
+		"name": "text",
+	}, data.SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := s.ExportVault(context.Background(), "testID", models.VaultExportRequest{Password: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.ImportVault(context.Background(), "testID1", models.VaultImportRequest{
+		Archive: res.Archive, Password: "test",
+	})
+	assert.Equal(t, ErrBadArguments, err)
+	sd, err := ds.GetAllDataByType(context.Background(), "testID1", data.SText)
+	assert.NoError(t, err)
+	assert.Empty(t, sd)
+}
+
+func initVaultService(t *testing.T) (*VaultService, *ItemService) {
+	ds := initDataMS(t)
+	is := NewItemService(ds)
+	_, err := is.StoreItem(context.Background(), "testID", items.Text, models.ItemRequest{Fields: map[string]string{
+		"name": "test", "data": "test",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewVaultService(storage.UnitOfWork{}, ds), is
+}
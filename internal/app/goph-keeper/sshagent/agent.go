@@ -0,0 +1,181 @@
+// Package sshagent serves the SSH keys stored in goph-keeper over the ssh-agent protocol,
+// so the keys are used by the SSH clients without being written to disk.
+package sshagent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+// KeyClient fetches the SSH key items. The keys are read one by one, since the item lists hide them.
+type KeyClient interface {
+	GetAllItems(ctx context.Context, name string) ([]models.ItemResponse, error)
+	GetItemByID(ctx context.Context, name, id string) (models.ItemResponse, error)
+}
+
+var (
+	ErrReadOnly = errors.New("the keys are managed in goph-keeper and can't be changed through the agent")
+	ErrDenied   = errors.New("the use of the key has been denied")
+	ErrNotFound = errors.New("the key is not found")
+)
+
+// Agent is the read-only ssh-agent holding the keys of the keeper. The keys are fetched again whenever
+// the client lists them, so the keys added or removed in the keeper are picked up without the restart.
+// The Confirm function, if any, is asked before every signature and gets the name of the key.
+type Agent struct {
+	keeper  KeyClient
+	confirm func(name string) bool
+	timeout time.Duration
+
+	mu   sync.Mutex
+	keys agent.ExtendedAgent
+}
+
+// New returns the Agent serving the keys of the keeper. The confirm function may be nil.
+func New(keeper KeyClient, confirm func(name string) bool) *Agent {
+	return &Agent{
+		keeper:  keeper,
+		confirm: confirm,
+		timeout: time.Second * 30,
+		keys:    agent.NewKeyring().(agent.ExtendedAgent),
+	}
+}
+
+// Serve accepts the agent connections until the listener is closed.
+func (a *Agent) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if sErr := agent.ServeAgent(a, conn); sErr != nil && !errors.Is(sErr, net.ErrClosed) {
+				log.Debug(sErr)
+			}
+		}()
+	}
+}
+
+// Load replaces the agent keys with the keys stored in the keeper.
+// The keys that can't be used, e.g. the encrypted ones stored without the passphrase, are skipped.
+func (a *Agent) Load(ctx context.Context) error {
+	list, err := a.keeper.GetAllItems(ctx, items.SSHKey.Name)
+	if err != nil {
+		return err
+	}
+
+	keys := agent.NewKeyring()
+	for _, i := range list {
+		item, gErr := a.keeper.GetItemByID(ctx, items.SSHKey.Name, i.ID)
+		if gErr != nil {
+			return gErr
+		}
+		key, pErr := items.ParseSSHKey(item.Fields)
+		if pErr == nil {
+			pErr = keys.Add(agent.AddedKey{PrivateKey: key, Comment: item.Fields[items.FieldName]})
+		}
+		if pErr != nil {
+			log.Warnf("SSH key %q is skipped: %v", item.Fields[items.FieldName], pErr)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = keys.(agent.ExtendedAgent)
+	return nil
+}
+
+// List returns the keys stored in the keeper. The keys loaded before are returned if the keeper is unavailable.
+func (a *Agent) List() ([]*agent.Key, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	if err := a.Load(ctx); err != nil {
+		log.Error(err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.keys.List()
+}
+
+func (a *Agent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags signs the data with the key after the user confirms it.
+// The agent is locked while the user answers, so the confirmations of the parallel connections don't mix.
+func (a *Agent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.confirm != nil {
+		name, err := a.getKeyName(key)
+		if err != nil {
+			return nil, err
+		}
+		if !a.confirm(name) {
+			return nil, ErrDenied
+		}
+	}
+	return a.keys.SignWithFlags(key, data, flags)
+}
+
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.keys.Signers()
+}
+
+func (a *Agent) Add(agent.AddedKey) error {
+	return ErrReadOnly
+}
+
+func (a *Agent) Remove(ssh.PublicKey) error {
+	return ErrReadOnly
+}
+
+func (a *Agent) RemoveAll() error {
+	return ErrReadOnly
+}
+
+func (a *Agent) Lock([]byte) error {
+	return ErrReadOnly
+}
+
+func (a *Agent) Unlock([]byte) error {
+	return ErrReadOnly
+}
+
+func (a *Agent) Extension(string, []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
+
+// getKeyName returns the name of the loaded key. The caller must hold the lock.
+func (a *Agent) getKeyName(key ssh.PublicKey) (string, error) {
+	keys, err := a.keys.List()
+	if err != nil {
+		return "", err
+	}
+	wanted := key.Marshal()
+	for _, k := range keys {
+		if bytes.Equal(k.Blob, wanted) {
+			return k.Comment, nil
+		}
+	}
+	return "", ErrNotFound
+}
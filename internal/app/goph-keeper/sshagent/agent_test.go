@@ -0,0 +1,193 @@
+package sshagent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/items"
+	"github.com/agodlevskii/goph-keeper/internal/app/goph-keeper/models"
+)
+
+var errKeeper = errors.New("keeper is unavailable")
+
+type testKeeper struct {
+	items map[string]models.ItemResponse
+	err   error
+}
+
+func (k *testKeeper) GetAllItems(context.Context, string) ([]models.ItemResponse, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	res := make([]models.ItemResponse, 0, len(k.items))
+	for _, i := range k.items {
+		res = append(res, models.ItemResponse{ID: i.ID, Fields: items.SSHKey.Mask(i.Fields)})
+	}
+	return res, nil
+}
+
+func (k *testKeeper) GetItemByID(_ context.Context, _, id string) (models.ItemResponse, error) {
+	return k.items[id], k.err
+}
+
+func TestAgent_List(t *testing.T) {
+	key, pub := getTestKey(t)
+	keeper := &testKeeper{items: map[string]models.ItemResponse{
+		"test":    {ID: "test", Fields: map[string]string{"name": "test", "private_key": key}},
+		"invalid": {ID: "invalid", Fields: map[string]string{"name": "invalid", "private_key": "dGVzdA=="}},
+	}}
+	c := initAgentClient(t, New(keeper, nil))
+
+	got, err := c.List()
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "test", got[0].Comment)
+	assert.Equal(t, pub.Marshal(), got[0].Blob)
+
+	other, _ := getTestKey(t)
+	keeper.items["other"] = models.ItemResponse{
+		ID:     "other",
+		Fields: map[string]string{"name": "other", "private_key": other},
+	}
+	got, err = c.List()
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	keeper.err = errKeeper
+	got, err = c.List()
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestAgent_Sign(t *testing.T) {
+	key, pub := getTestKey(t)
+	keeper := &testKeeper{items: map[string]models.ItemResponse{
+		"test": {ID: "test", Fields: map[string]string{"name": "test", "private_key": key}},
+	}}
+	tests := []struct {
+		name    string
+		allow   bool
+		confirm bool
+		wantErr bool
+	}{
+		{
+			name: "Confirmation is off",
+		},
+		{
+			name:    "Use is allowed",
+			confirm: true,
+			allow:   true,
+		},
+		{
+			name:    "Use is denied",
+			confirm: true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var asked []string
+			var confirm func(name string) bool
+			if tt.confirm {
+				confirm = func(name string) bool {
+					asked = append(asked, name)
+					return tt.allow
+				}
+			}
+			a := New(keeper, confirm)
+			if err := a.Load(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			sig, err := initAgentClient(t, a).Sign(pub, []byte("test"))
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.confirm {
+				assert.Equal(t, []string{"test"}, asked)
+			}
+			if err == nil {
+				assert.NoError(t, pub.Verify([]byte("test"), sig))
+			}
+		})
+	}
+}
+
+func TestAgent_Serve(t *testing.T) {
+	key, _ := getTestKey(t)
+	a := New(&testKeeper{items: map[string]models.ItemResponse{
+		"test": {ID: "test", Fields: map[string]string{"name": "test", "private_key": key}},
+	}}, nil)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- a.Serve(l) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := agent.NewClient(conn).List()
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.NoError(t, conn.Close())
+
+	assert.NoError(t, l.Close())
+	assert.NoError(t, <-done)
+}
+
+func TestAgent_ReadOnly(t *testing.T) {
+	a := New(&testKeeper{}, nil)
+	key, _ := getTestKey(t)
+	raw, err := items.ParseSSHKey(map[string]string{"private_key": key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrReadOnly, a.Add(agent.AddedKey{PrivateKey: raw}))
+	assert.Equal(t, ErrReadOnly, a.RemoveAll())
+	assert.Equal(t, ErrReadOnly, a.Lock([]byte("test")))
+
+	signers, err := a.Signers()
+	assert.NoError(t, err)
+	assert.Empty(t, signers)
+}
+
+func initAgentClient(t *testing.T, a *Agent) agent.ExtendedAgent {
+	t.Helper()
+	server, client := net.Pipe()
+	go func() { _ = agent.ServeAgent(a, server) }()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+	return agent.NewClient(client)
+}
+
+// getTestKey returns the base64-encoded key file of the new key, and its public key.
+func getTestKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+	pk, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), pub
+}
@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrIBANFormat   = errors.New("IBAN must start with the country code and check digits, e.g. GB82WEST12345698765432")
+	ErrIBANChecksum = errors.New("IBAN check digits don't match the account number")
+	ErrBICFormat    = errors.New("SWIFT/BIC must be an 8 or 11 characters code, e.g. DEUTDEFF")
+)
+
+// IBAN checks the format and the check digits of the IBAN. The groups may be separated with spaces.
+func IBAN(iban string) error {
+	iban = strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+	if ok, err := regexp.MatchString("^[A-Z]{2}\\d{2}[A-Z0-9]{11,30}$", iban); err != nil || !ok {
+		return ErrIBANFormat
+	}
+
+	// The country code and check digits are moved to the end, the letters are replaced with the numbers
+	// from 10 to 35, and the resulting number must give 1 as the remainder of the division by 97.
+	rem := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' {
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		} else {
+			rem = (rem*10 + int(c-'0')) % 97
+		}
+	}
+	if rem != 1 {
+		return ErrIBANChecksum
+	}
+	return nil
+}
+
+// BIC checks the format of the SWIFT/BIC code. The code is case-insensitive and may be surrounded with spaces.
+func BIC(bic string) error {
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+	if ok, err := regexp.MatchString("^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$", bic); err != nil || !ok {
+		return ErrBICFormat
+	}
+	return nil
+}
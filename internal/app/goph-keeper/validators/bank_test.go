@@ -0,0 +1,88 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIBAN(t *testing.T) {
+	tests := []struct {
+		name    string
+		iban    string
+		wantErr error
+	}{
+		{
+			name:    "Missing IBAN",
+			wantErr: ErrIBANFormat,
+		},
+		{
+			name:    "Missing country code",
+			iban:    "8937040044053201300000",
+			wantErr: ErrIBANFormat,
+		},
+		{
+			name:    "IBAN is too short",
+			iban:    "DE89 3704",
+			wantErr: ErrIBANFormat,
+		},
+		{
+			name:    "Wrong check digits",
+			iban:    "DE88 3704 0044 0532 0130 00",
+			wantErr: ErrIBANChecksum,
+		},
+		{
+			name: "Correct IBAN",
+			iban: "GB82WEST12345698765432",
+		},
+		{
+			name: "Correct IBAN with spaces",
+			iban: "DE89 3704 0044 0532 0130 00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, IBAN(tt.iban))
+		})
+	}
+}
+
+func TestBIC(t *testing.T) {
+	tests := []struct {
+		name    string
+		bic     string
+		wantErr error
+	}{
+		{
+			name:    "Missing BIC",
+			wantErr: ErrBICFormat,
+		},
+		{
+			name:    "Wrong length",
+			bic:     "DEUTDEFF5",
+			wantErr: ErrBICFormat,
+		},
+		{
+			name:    "Digits in the bank code",
+			bic:     "DE1TDEFF",
+			wantErr: ErrBICFormat,
+		},
+		{
+			name: "Correct BIC",
+			bic:  "DEUTDEFF",
+		},
+		{
+			name: "Correct BIC with branch code",
+			bic:  "DEUTDEFF500",
+		},
+		{
+			name: "Lowercase BIC with spaces",
+			bic:  " deutdeff500 ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, BIC(tt.bic))
+		})
+	}
+}
@@ -3,30 +3,101 @@ package validators
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 )
 
 var (
-	ErrCardNumberFormat = errors.New("card number must match the format xxxx xxxx xxxx xxxx")
-	ErrCVVFormat        = errors.New("card's CardCVV must be a 3-digits value")
-	ErrExpDateFormat    = errors.New("card's expire date must match the format mm/yy")
-	ErrExpDateInPast    = errors.New("card's expire date must not be in the past")
+	ErrCardNumberFormat   = errors.New("card number must be 12 to 19 digits, optionally grouped with spaces")
+	ErrCardNumberLength   = errors.New("card number length doesn't match the card brand")
+	ErrCardNumberChecksum = errors.New("card number check digit is wrong")
+	ErrCVVFormat          = errors.New("card's CVV must be a 3 or 4-digits value")
+	ErrCVVLength          = errors.New("card's CVV length doesn't match the card brand")
+	ErrExpDateFormat      = errors.New("card's expire date must match the format mm/yy")
+	ErrExpDateInPast      = errors.New("card's expire date must not be in the past")
 )
 
+// CardBrand is the payment system the card belongs to. The brand is told by the leading digits of the card number,
+// and it defines the allowed lengths of the number and its CVV.
+type CardBrand struct {
+	Name      string
+	Lengths   []int
+	CVVLength int
+	// ranges hold the first and the last leading digits of the brand numbers, having the same length.
+	ranges [][2]string
+}
+
+// cardBrands are checked in order, so the narrower ranges go before the wider ones, e.g. Discover before UnionPay.
+var cardBrands = []CardBrand{
+	{Name: "Visa", Lengths: []int{13, 16, 19}, CVVLength: 3, ranges: [][2]string{{"4", "4"}}},
+	{Name: "Mastercard", Lengths: []int{16}, CVVLength: 3, ranges: [][2]string{{"51", "55"}, {"2221", "2720"}}},
+	{Name: "Amex", Lengths: []int{15}, CVVLength: 4, ranges: [][2]string{{"34", "34"}, {"37", "37"}}},
+	{Name: "MIR", Lengths: []int{16, 17, 18, 19}, CVVLength: 3, ranges: [][2]string{{"2200", "2204"}}},
+	{
+		Name:      "Discover",
+		Lengths:   []int{16, 17, 18, 19},
+		CVVLength: 3,
+		ranges:    [][2]string{{"6011", "6011"}, {"622126", "622925"}, {"644", "649"}, {"65", "65"}},
+	},
+	{Name: "UnionPay", Lengths: []int{16, 17, 18, 19}, CVVLength: 3, ranges: [][2]string{{"62", "62"}}},
+}
+
+// DetectCardBrand returns the brand of the card number, ignoring the spaces between the digit groups.
+func DetectCardBrand(num string) (CardBrand, bool) {
+	num = strings.ReplaceAll(num, " ", "")
+	for _, b := range cardBrands {
+		for _, r := range b.ranges {
+			if len(num) >= len(r[0]) && num[:len(r[0])] >= r[0] && num[:len(r[0])] <= r[1] {
+				return b, true
+			}
+		}
+	}
+	return CardBrand{}, false
+}
+
+// CardNumber checks the card number length against its brand, and the check digit with the Luhn algorithm.
+// The numbers of the unknown brands are checked with the Luhn algorithm only.
 func CardNumber(num string) error {
-	if ok, err := regexp.MatchString("^(\\d{4} ?){4}$", num); err != nil || !ok {
+	if ok, err := regexp.MatchString("^\\d+( \\d+)*$", num); err != nil || !ok {
+		return ErrCardNumberFormat
+	}
+	num = strings.ReplaceAll(num, " ", "")
+	if len(num) < 12 || len(num) > 19 {
 		return ErrCardNumberFormat
 	}
+
+	if b, ok := DetectCardBrand(num); ok && !b.hasLength(len(num)) {
+		return ErrCardNumberLength
+	}
+	if !isLuhnValid(num) {
+		return ErrCardNumberChecksum
+	}
 	return nil
 }
 
+// CardCVV checks the CVV format. Its length is checked against the card brand with CardBrandCVV.
 func CardCVV(cvv string) error {
-	if ok, err := regexp.MatchString("^\\d{3}$", cvv); err != nil || !ok {
+	if ok, err := regexp.MatchString("^\\d{3,4}$", cvv); err != nil || !ok {
 		return ErrCVVFormat
 	}
 	return nil
 }
 
+// CardBrandCVV checks the CVV of the card with the number. The CVV of the unknown brand must be 3 digits long.
+func CardBrandCVV(num, cvv string) error {
+	if err := CardCVV(cvv); err != nil {
+		return err
+	}
+	length := 3
+	if b, ok := DetectCardBrand(num); ok {
+		length = b.CVVLength
+	}
+	if len(cvv) != length {
+		return ErrCVVLength
+	}
+	return nil
+}
+
 func CardExpDate(date string) error {
 	if ok, err := regexp.MatchString("\\d{2}/\\d{2}", date); err != nil || !ok {
 		return ErrExpDateFormat
@@ -42,3 +113,28 @@ func CardExpDate(date string) error {
 	}
 	return nil
 }
+
+func (b CardBrand) hasLength(n int) bool {
+	for _, l := range b.Lengths {
+		if l == n {
+			return true
+		}
+	}
+	return false
+}
+
+// isLuhnValid tells if the digits pass the Luhn check: every second digit from the right is doubled,
+// the digits of the products are summed up with the rest ones, and the sum must be divisible by 10.
+func isLuhnValid(num string) bool {
+	sum := 0
+	for i := range num {
+		d := int(num[len(num)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -23,7 +23,7 @@ func TestCardCVV(t *testing.T) {
 		},
 		{
 			name:    "Long CVV",
-			cvv:     "1234",
+			cvv:     "12345",
 			wantErr: ErrCVVFormat,
 		},
 		{
@@ -35,6 +35,10 @@ func TestCardCVV(t *testing.T) {
 			name: "Correct CVV",
 			cvv:  "123",
 		},
+		{
+			name: "Correct 4-digits CVV",
+			cvv:  "1234",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -43,6 +47,55 @@ func TestCardCVV(t *testing.T) {
 	}
 }
 
+func TestCardBrandCVV(t *testing.T) {
+	tests := []struct {
+		name    string
+		num     string
+		cvv     string
+		wantErr error
+	}{
+		{
+			name:    "Alphabetical CVV",
+			num:     "4111111111111111",
+			cvv:     "abc",
+			wantErr: ErrCVVFormat,
+		},
+		{
+			name:    "Amex with 3-digits CVV",
+			num:     "378282246310005",
+			cvv:     "123",
+			wantErr: ErrCVVLength,
+		},
+		{
+			name:    "Visa with 4-digits CVV",
+			num:     "4111111111111111",
+			cvv:     "1234",
+			wantErr: ErrCVVLength,
+		},
+		{
+			name:    "Unknown brand with 4-digits CVV",
+			num:     "9999999999999995",
+			cvv:     "1234",
+			wantErr: ErrCVVLength,
+		},
+		{
+			name: "Amex with 4-digits CVV",
+			num:  "3782 822463 10005",
+			cvv:  "1234",
+		},
+		{
+			name: "Visa with 3-digits CVV",
+			num:  "4111111111111111",
+			cvv:  "123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, CardBrandCVV(tt.num, tt.cvv))
+		})
+	}
+}
+
 func TestCardExpDate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -90,13 +143,45 @@ func TestCardNumber(t *testing.T) {
 			num:     "1234 5678 9012 3456 7890",
 			wantErr: ErrCardNumberFormat,
 		},
+		{
+			name:    "Number is too short",
+			num:     "4111 1111",
+			wantErr: ErrCardNumberFormat,
+		},
+		{
+			name:    "Number has letters",
+			num:     "4111 1111 1111 111a",
+			wantErr: ErrCardNumberFormat,
+		},
+		{
+			name:    "Length doesn't match the brand",
+			num:     "3782822463100050",
+			wantErr: ErrCardNumberLength,
+		},
+		{
+			name:    "Wrong check digit",
+			num:     "4111111111111112",
+			wantErr: ErrCardNumberChecksum,
+		},
 		{
 			name: "Correct number",
-			num:  "1234567891023732",
+			num:  "4111111111111111",
 		},
 		{
 			name: "Correct number with spaces",
-			num:  "1234 5678 9012 3456",
+			num:  "5555 5555 5555 4444",
+		},
+		{
+			name: "Correct 13-digits number",
+			num:  "4222222222222",
+		},
+		{
+			name: "Correct 15-digits number",
+			num:  "3782 822463 10005",
+		},
+		{
+			name: "Correct number of unknown brand",
+			num:  "9999999999999995",
 		},
 	}
 	for _, tt := range tests {
@@ -105,3 +190,27 @@ func TestCardNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectCardBrand(t *testing.T) {
+	tests := []struct {
+		num  string
+		want string
+	}{
+		{num: "4111111111111111", want: "Visa"},
+		{num: "5555555555554444", want: "Mastercard"},
+		{num: "2223003122003222", want: "Mastercard"},
+		{num: "3782 822463 10005", want: "Amex"},
+		{num: "6011111111111117", want: "Discover"},
+		{num: "6221260000000000", want: "Discover"},
+		{num: "6200000000000005", want: "UnionPay"},
+		{num: "2200000000000004", want: "MIR"},
+		{num: "9999999999999995"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.num, func(t *testing.T) {
+			got, ok := DetectCardBrand(tt.num)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, got.Name)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package validators
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 )
 
+var ErrPositiveNumber = errors.New("the value must be a positive number")
+
 func Min(limit int) func(v string) error {
 	return func(v string) error {
 		if len(v) < limit {
@@ -22,9 +26,26 @@ func Max(limit int) func(v string) error {
 	}
 }
 
+// Optional accepts the empty value, and checks the other ones with the validate function.
+func Optional(validate func(v string) error) func(v string) error {
+	return func(v string) error {
+		if v == "" {
+			return nil
+		}
+		return validate(v)
+	}
+}
+
 func ItemName(name string) error {
 	if err := Min(3)(name); err != nil {
 		return err
 	}
 	return Max(50)(name)
 }
+
+func PositiveNumber(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+		return ErrPositiveNumber
+	}
+	return nil
+}
@@ -34,3 +34,41 @@ func TestItemName(t *testing.T) {
 		})
 	}
 }
+
+func TestPositiveNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{
+			name:    "Missing value",
+			wantErr: ErrPositiveNumber,
+		},
+		{
+			name:    "Alphabetical value",
+			value:   "abc",
+			wantErr: ErrPositiveNumber,
+		},
+		{
+			name:    "Zero value",
+			value:   "0",
+			wantErr: ErrPositiveNumber,
+		},
+		{
+			name:  "Correct value",
+			value: "30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, PositiveNumber(tt.value))
+		})
+	}
+}
+
+func TestOptional(t *testing.T) {
+	assert.NoError(t, Optional(BIC)(""))
+	assert.Equal(t, ErrBICFormat, Optional(BIC)("test"))
+	assert.NoError(t, Optional(BIC)("DEUTDEFF"))
+}
@@ -0,0 +1,46 @@
+package validators
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDocExpDateFormat   = errors.New("document's expire date must match the format YYYY-MM-DD")
+	ErrDocExpDateInPast   = errors.New("document's expire date must not be in the past")
+	ErrDocExpDateOrder    = errors.New("document's expire date must not be before its issue date")
+	ErrDocIssueDateFormat = errors.New("document's issue date must match the format YYYY-MM-DD")
+)
+
+// DocExpDate checks the expire date of the document. The document is valid through the whole expiry day.
+func DocExpDate(date string) error {
+	dateTime, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return ErrDocExpDateFormat
+	}
+	if time.Now().After(dateTime.AddDate(0, 0, 1)) {
+		return ErrDocExpDateInPast
+	}
+	return nil
+}
+
+// DocDates checks the format of the issue and expire dates of the document, and their order.
+// Either date may be empty. The expire date in the past is accepted, since the stored documents expire anyway.
+func DocDates(issue, expiry string) error {
+	var issued, expires time.Time
+	var err error
+	if issue != "" {
+		if issued, err = time.Parse("2006-01-02", issue); err != nil {
+			return ErrDocIssueDateFormat
+		}
+	}
+	if expiry != "" {
+		if expires, err = time.Parse("2006-01-02", expiry); err != nil {
+			return ErrDocExpDateFormat
+		}
+	}
+	if issue != "" && expiry != "" && expires.Before(issued) {
+		return ErrDocExpDateOrder
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocExpDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		date    string
+		wantErr error
+	}{
+		{
+			name:    "Missing date",
+			wantErr: ErrDocExpDateFormat,
+		},
+		{
+			name:    "Incorrectly formatted date",
+			date:    "01/30",
+			wantErr: ErrDocExpDateFormat,
+		},
+		{
+			name:    "Date in the past",
+			date:    "2011-11-11",
+			wantErr: ErrDocExpDateInPast,
+		},
+		{
+			name: "Document expires today",
+			date: time.Now().Format("2006-01-02"),
+		},
+		{
+			name: "Correct date",
+			date: "2068-12-31",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, DocExpDate(tt.date))
+		})
+	}
+}
+
+func TestDocDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		issue   string
+		expiry  string
+		wantErr error
+	}{
+		{
+			name: "Missing dates",
+		},
+		{
+			name:    "Incorrectly formatted issue date",
+			issue:   "11/11",
+			wantErr: ErrDocIssueDateFormat,
+		},
+		{
+			name:    "Incorrectly formatted expire date",
+			expiry:  "01/30",
+			wantErr: ErrDocExpDateFormat,
+		},
+		{
+			name:    "Document expires before it's issued",
+			issue:   "2020-01-01",
+			expiry:  "2019-12-31",
+			wantErr: ErrDocExpDateOrder,
+		},
+		{
+			name:   "Expired document",
+			issue:  "2001-01-01",
+			expiry: "2011-11-11",
+		},
+		{
+			name:   "Expire date only",
+			expiry: "2068-12-31",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, DocDates(tt.issue, tt.expiry))
+		})
+	}
+}
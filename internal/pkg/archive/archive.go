@@ -0,0 +1,120 @@
+// Package archive seals the versioned documents into the files protected with the passphrase.
+// The file opens with the magic of the document kind and its version in plain, so the unrelated files
+// and the unsupported versions are rejected before the key derivation.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+)
+
+const versionSize = 2
+
+var (
+	ErrFormat    = errors.New("the file is not a goph-keeper archive")
+	ErrVersion   = errors.New("the archive version is not supported")
+	ErrIntegrity = errors.New("the archive is corrupted, or the passphrase is wrong")
+)
+
+// Header describes the sealed document.
+type Header struct {
+	Version   int
+	CreatedAt time.Time
+}
+
+// envelope is the encrypted part of the file.
+// The checksum covers the contents as they are encoded, so any change to them is detected on read.
+type envelope struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Checksum  string    `json:"checksum"`
+	Contents  []byte    `json:"contents"`
+}
+
+// Write encodes the document as JSON, and writes it compressed and encrypted with the key derived from the passphrase.
+func Write(w io.Writer, magic string, version int, passphrase string, v any) (Header, error) {
+	if passphrase == "" {
+		return Header{}, enc.ErrPasswordLength
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return Header{}, err
+	}
+	sum := sha256.Sum256(raw)
+	e := envelope{Version: version, CreatedAt: time.Now().UTC(), Checksum: hex.EncodeToString(sum[:]), Contents: raw}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err = json.NewEncoder(zw).Encode(e); err != nil {
+		return Header{}, err
+	}
+	if err = zw.Close(); err != nil {
+		return Header{}, err
+	}
+	body, err := enc.EncryptWithPassword(passphrase, buf.Bytes())
+	if err != nil {
+		return Header{}, err
+	}
+
+	header := make([]byte, len(magic)+versionSize)
+	copy(header, magic)
+	binary.BigEndian.PutUint16(header[len(magic):], uint16(version))
+	if _, err = w.Write(append(header, body...)); err != nil {
+		return Header{}, err
+	}
+	return Header{Version: e.Version, CreatedAt: e.CreatedAt}, nil
+}
+
+// Read verifies the archive of the document kind with the magic, and decodes the document into v.
+// The versions from the first one up to the latest are accepted, the caller migrates the older documents.
+func Read(r io.Reader, magic string, latest int, passphrase string, v any) (Header, error) {
+	if passphrase == "" {
+		return Header{}, enc.ErrPasswordLength
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Header{}, err
+	}
+	headerSize := len(magic) + versionSize
+	if len(b) < headerSize || string(b[:len(magic)]) != magic {
+		return Header{}, ErrFormat
+	}
+	version := int(binary.BigEndian.Uint16(b[len(magic):headerSize]))
+	if version < 1 || version > latest {
+		return Header{}, ErrVersion
+	}
+
+	plain, err := enc.DecryptWithPassword(passphrase, b[headerSize:])
+	if err != nil {
+		return Header{}, ErrIntegrity
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return Header{}, ErrIntegrity
+	}
+
+	var e envelope
+	if err = json.NewDecoder(zr).Decode(&e); err != nil {
+		return Header{}, ErrIntegrity
+	}
+	// The header is not encrypted, so its version must match the one sealed with the contents.
+	sum := sha256.Sum256(e.Contents)
+	if e.Version != version || e.Checksum != hex.EncodeToString(sum[:]) {
+		return Header{}, ErrIntegrity
+	}
+	if err = json.Unmarshal(e.Contents, v); err != nil {
+		return Header{}, ErrIntegrity
+	}
+	return Header{Version: e.Version, CreatedAt: e.CreatedAt}, nil
+}
@@ -0,0 +1,130 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+)
+
+const (
+	testMagic      = "GKTEST"
+	testPassphrase = "passphrase"
+)
+
+type testDocument struct {
+	Name  string
+	Items []string
+}
+
+func TestWrite(t *testing.T) {
+	_, err := Write(&bytes.Buffer{}, testMagic, 1, "", testDocument{})
+	assert.Equal(t, enc.ErrPasswordLength, err)
+
+	var buf bytes.Buffer
+	h, err := Write(&buf, testMagic, 1, testPassphrase, testDocument{Name: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h.Version)
+	assert.False(t, h.CreatedAt.IsZero())
+	assert.Equal(t, testMagic, buf.String()[:len(testMagic)])
+}
+
+func TestRead(t *testing.T) {
+	doc := testDocument{Name: "test", Items: []string{"a", "b"}}
+	v1 := writeTestArchive(t, 1, doc)
+	v2 := writeTestArchive(t, 2, doc)
+
+	tests := []struct {
+		name       string
+		file       []byte
+		passphrase string
+		want       testDocument
+		wantErr    error
+	}{
+		{
+			name:    "Passphrase is missing",
+			file:    v1,
+			wantErr: enc.ErrPasswordLength,
+		},
+		{
+			name:       "File is not an archive",
+			file:       []byte("not an archive"),
+			passphrase: testPassphrase,
+			wantErr:    ErrFormat,
+		},
+		{
+			name:       "File is too short",
+			file:       []byte(testMagic),
+			passphrase: testPassphrase,
+			wantErr:    ErrFormat,
+		},
+		{
+			name:       "Version is newer than supported",
+			file:       withVersion(v1, 3),
+			passphrase: testPassphrase,
+			wantErr:    ErrVersion,
+		},
+		{
+			name:       "Version in the header is forged",
+			file:       withVersion(v2, 1),
+			passphrase: testPassphrase,
+			wantErr:    ErrIntegrity,
+		},
+		{
+			name:       "Passphrase is wrong",
+			file:       v1,
+			passphrase: "wrong",
+			wantErr:    ErrIntegrity,
+		},
+		{
+			name:       "Archive is corrupted",
+			file:       withFlippedByte(v1, len(v1)-1),
+			passphrase: testPassphrase,
+			wantErr:    ErrIntegrity,
+		},
+		{
+			name:       "Previous version is read",
+			file:       v1,
+			passphrase: testPassphrase,
+			want:       doc,
+		},
+		{
+			name:       "Latest version is read",
+			file:       v2,
+			passphrase: testPassphrase,
+			want:       doc,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got testDocument
+			_, err := Read(bytes.NewReader(tt.file), testMagic, 2, tt.passphrase, &got)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func writeTestArchive(t *testing.T, version int, doc testDocument) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := Write(&buf, testMagic, version, testPassphrase, doc); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func withVersion(file []byte, version uint16) []byte {
+	b := append([]byte(nil), file...)
+	binary.BigEndian.PutUint16(b[len(testMagic):], version)
+	return b
+}
+
+func withFlippedByte(file []byte, i int) []byte {
+	b := append([]byte(nil), file...)
+	b[i] ^= 0xff
+	return b
+}
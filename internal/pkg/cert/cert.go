@@ -20,3 +20,33 @@ func GetCertificatePool(path string) (*x509.CertPool, error) {
 func GetClientCertificate(cert string, key string) (tls.Certificate, error) {
 	return tls.LoadX509KeyPair(cert, key)
 }
+
+const (
+	FieldSubject = "subject"
+	FieldSAN     = "san"
+)
+
+// GetIdentities returns the identities declared in the specified certificate field.
+// The subject field provides the common name, the SAN field provides the DNS names, emails, and URIs.
+func GetIdentities(c *x509.Certificate, field string) []string {
+	if c == nil {
+		return nil
+	}
+
+	switch field {
+	case FieldSubject:
+		if c.Subject.CommonName == "" {
+			return nil
+		}
+		return []string{c.Subject.CommonName}
+	case FieldSAN:
+		ids := make([]string, 0, len(c.DNSNames)+len(c.EmailAddresses)+len(c.URIs))
+		ids = append(ids, c.DNSNames...)
+		ids = append(ids, c.EmailAddresses...)
+		for _, u := range c.URIs {
+			ids = append(ids, u.String())
+		}
+		return ids
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package cert
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
 	"os"
 	"testing"
 
@@ -45,3 +48,54 @@ func TestGetCertificatePool(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIdentities(t *testing.T) {
+	uri, err := url.Parse("spiffe://keeper/ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "ci-runner"},
+		DNSNames:       []string{"ci.example.com"},
+		EmailAddresses: []string{"ci@example.com"},
+		URIs:           []*url.URL{uri},
+	}
+
+	tests := []struct {
+		name  string
+		cert  *x509.Certificate
+		field string
+		want  []string
+	}{
+		{
+			name:  "Missing certificate",
+			field: FieldSubject,
+		},
+		{
+			name: "Unknown field",
+			cert: c,
+		},
+		{
+			name:  "Missing common name",
+			cert:  &x509.Certificate{},
+			field: FieldSubject,
+		},
+		{
+			name:  "Subject field",
+			cert:  c,
+			field: FieldSubject,
+			want:  []string{"ci-runner"},
+		},
+		{
+			name:  "SAN field",
+			cert:  c,
+			field: FieldSAN,
+			want:  []string{"ci.example.com", "ci@example.com", "spiffe://keeper/ci"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetIdentities(tt.cert, tt.field))
+		})
+	}
+}
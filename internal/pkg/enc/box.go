@@ -0,0 +1,117 @@
+package enc
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// PublicKeySize is the size of the X25519 public key in bytes.
+const PublicKeySize = 32
+
+const (
+	saltSize     = 16
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+var (
+	ErrPublicKey  = errors.New("enc: the public key is invalid")
+	ErrPrivateKey = errors.New("enc: the private key is invalid")
+)
+
+// GenerateKeyPair returns a new X25519 key pair used to seal the item keys shared with the user.
+func GenerateKeyPair() ([]byte, []byte, error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub[:], priv[:], nil
+}
+
+// SealKey encrypts the item key to the recipient's public key.
+// Only the owner of the matching private key can open it, the sender's identity is not revealed.
+func SealKey(pub, key []byte) ([]byte, error) {
+	if len(pub) != PublicKeySize {
+		return nil, ErrPublicKey
+	}
+	if len(key) == 0 {
+		return nil, ErrDataLength
+	}
+
+	var rcpt [PublicKeySize]byte
+	copy(rcpt[:], pub)
+	return box.SealAnonymous(nil, key, &rcpt, rand.Reader)
+}
+
+// OpenKey decrypts the item key sealed to the public key with the matching private key.
+func OpenKey(pub, priv, sealed []byte) ([]byte, error) {
+	if len(pub) != PublicKeySize {
+		return nil, ErrPublicKey
+	}
+	if len(priv) != curve25519.ScalarSize {
+		return nil, ErrPrivateKey
+	}
+
+	var pk, sk [PublicKeySize]byte
+	copy(pk[:], pub)
+	copy(sk[:], priv)
+
+	key, ok := box.OpenAnonymous(nil, sealed, &pk, &sk)
+	if !ok {
+		return nil, ErrDecryption
+	}
+	return key, nil
+}
+
+// EncryptPrivateKey encrypts the private key with the key derived from the master password.
+func EncryptPrivateKey(password string, priv []byte) ([]byte, error) {
+	if len(priv) != curve25519.ScalarSize {
+		return nil, ErrPrivateKey
+	}
+	return EncryptWithPassword(password, priv)
+}
+
+// DecryptPrivateKey decrypts the private key encrypted with the master password.
+func DecryptPrivateKey(password string, data []byte) ([]byte, error) {
+	return DecryptWithPassword(password, data)
+}
+
+// EncryptWithPassword encrypts the data with the key derived from the password.
+// The random salt is prepended to the result, so the same password produces different outputs.
+func EncryptWithPassword(password string, data []byte) ([]byte, error) {
+	if password == "" {
+		return nil, ErrPasswordLength
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	enc, err := EncryptDataWithKey(derivePasswordKey(password, salt), data)
+	if err != nil {
+		return nil, err
+	}
+	return append(salt, enc...), nil
+}
+
+// DecryptWithPassword decrypts the data encrypted with the password.
+func DecryptWithPassword(password string, data []byte) ([]byte, error) {
+	if password == "" {
+		return nil, ErrPasswordLength
+	}
+	if len(data) <= saltSize {
+		return nil, ErrDataLength
+	}
+	return DecryptDataWithKey(derivePasswordKey(password, data[:saltSize]), data[saltSize:])
+}
+
+func derivePasswordKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, keySize)
+}
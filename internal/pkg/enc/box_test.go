@@ -0,0 +1,209 @@
+package enc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateKeyPair(t *testing.T) {
+	pub, priv, err := GenerateKeyPair()
+	assert.NoError(t, err)
+	assert.Len(t, pub, PublicKeySize)
+	assert.Len(t, priv, PublicKeySize)
+
+	pub2, _, err := GenerateKeyPair()
+	assert.NoError(t, err)
+	assert.NotEqual(t, pub, pub2)
+}
+
+func TestOpenKey(t *testing.T) {
+	pub, priv, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, otherPriv, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := SealKey(pub, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pub     []byte
+		priv    []byte
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:    "Public key is invalid",
+			pub:     []byte("short"),
+			priv:    priv,
+			wantErr: ErrPublicKey,
+		},
+		{
+			name:    "Private key is invalid",
+			pub:     pub,
+			priv:    []byte("short"),
+			wantErr: ErrPrivateKey,
+		},
+		{
+			name:    "Key is sealed to another user",
+			pub:     otherPub,
+			priv:    otherPriv,
+			wantErr: ErrDecryption,
+		},
+		{
+			name: "Key is sealed to the user",
+			pub:  pub,
+			priv: priv,
+			want: key,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, oErr := OpenKey(tt.pub, tt.priv, sealed)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, oErr)
+		})
+	}
+}
+
+func TestSealKey(t *testing.T) {
+	pub, _, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = SealKey([]byte("short"), []byte("key"))
+	assert.Equal(t, ErrPublicKey, err)
+	_, err = SealKey(pub, nil)
+	assert.Equal(t, ErrDataLength, err)
+
+	s1, err := SealKey(pub, []byte("key"))
+	assert.NoError(t, err)
+	s2, err := SealKey(pub, []byte("key"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, s1, s2)
+}
+
+func TestDecryptPrivateKey(t *testing.T) {
+	_, priv, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := EncryptPrivateKey("master", priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		data     []byte
+		want     []byte
+		wantErr  error
+	}{
+		{
+			name:    "Password is missing",
+			data:    encrypted,
+			wantErr: ErrPasswordLength,
+		},
+		{
+			name:     "Data is too short",
+			password: "master",
+			data:     encrypted[:saltSize],
+			wantErr:  ErrDataLength,
+		},
+		{
+			name:     "Wrong password",
+			password: "wrong",
+			data:     encrypted,
+			wantErr:  ErrDecryption,
+		},
+		{
+			name:     "Correct password",
+			password: "master",
+			data:     encrypted,
+			want:     priv,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dErr := DecryptPrivateKey(tt.password, tt.data)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, dErr)
+		})
+	}
+}
+
+func TestEncryptPrivateKey(t *testing.T) {
+	_, priv, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = EncryptPrivateKey("", priv)
+	assert.Equal(t, ErrPasswordLength, err)
+	_, err = EncryptPrivateKey("master", []byte("short"))
+	assert.Equal(t, ErrPrivateKey, err)
+
+	e1, err := EncryptPrivateKey("master", priv)
+	assert.NoError(t, err)
+	e2, err := EncryptPrivateKey("master", priv)
+	assert.NoError(t, err)
+	assert.NotEqual(t, e1, e2)
+}
+
+func TestDecryptWithPassword(t *testing.T) {
+	data, err := EncryptWithPassword("passphrase", []byte("backup"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		data     []byte
+		want     []byte
+		wantErr  error
+	}{
+		{
+			name:    "Password is missing",
+			data:    data,
+			wantErr: ErrPasswordLength,
+		},
+		{
+			name:     "Data is too short",
+			password: "passphrase",
+			data:     data[:saltSize],
+			wantErr:  ErrDataLength,
+		},
+		{
+			name:     "Password is wrong",
+			password: "wrong",
+			data:     data,
+			wantErr:  ErrDecryption,
+		},
+		{
+			name:     "Data is decrypted",
+			password: "passphrase",
+			data:     data,
+			want:     []byte("backup"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dErr := DecryptWithPassword(tt.password, tt.data)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, dErr)
+		})
+	}
+}
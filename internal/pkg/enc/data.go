@@ -17,11 +17,21 @@ var secret = []byte("f91j&famF*kf_PgjJ1Yfv$_0f1A8BB#2")
 
 // EncryptData transforms an original slice of bytes into an encoded one.
 func EncryptData(data []byte) ([]byte, error) {
+	return EncryptDataWithKey(secret, data)
+}
+
+// DecryptData transforms an encrypted slice of bytes into an original one.
+func DecryptData(data []byte) ([]byte, error) {
+	return DecryptDataWithKey(secret, data)
+}
+
+// EncryptDataWithKey transforms an original slice of bytes into an encoded one using the specified key.
+func EncryptDataWithKey(key, data []byte) ([]byte, error) {
 	if len(data) == 0 {
 		return nil, ErrDataLength
 	}
 
-	c, err := aes.NewCipher(secret)
+	c, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
@@ -39,9 +49,9 @@ func EncryptData(data []byte) ([]byte, error) {
 	return gcm.Seal(nonce, nonce, data, nil), nil
 }
 
-// DecryptData transforms an encrypted slice of bytes into an original one.
-func DecryptData(data []byte) ([]byte, error) {
-	c, err := aes.NewCipher(secret)
+// DecryptDataWithKey transforms an encrypted slice of bytes into an original one using the specified key.
+func DecryptDataWithKey(key, data []byte) ([]byte, error) {
+	c, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, ErrDecryption
 	}
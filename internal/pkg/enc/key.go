@@ -0,0 +1,54 @@
+package enc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const keySize = 32
+
+var ErrMissingOwner = errors.New("enc: the key owner is not specified")
+
+// GenerateKey returns a new random key for the item data encryption.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// WrapKey encrypts the item key for the specified user.
+// Every user has its own wrapping key, so the same item key is wrapped differently for each of them.
+func WrapKey(uid string, key []byte) ([]byte, error) {
+	kek, err := getWrappingKey(uid)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptDataWithKey(kek, key)
+}
+
+// UnwrapKey decrypts the item key wrapped for the specified user.
+func UnwrapKey(uid string, wrapped []byte) ([]byte, error) {
+	kek, err := getWrappingKey(uid)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptDataWithKey(kek, wrapped)
+}
+
+func getWrappingKey(uid string) ([]byte, error) {
+	if uid == "" {
+		return nil, ErrMissingOwner
+	}
+
+	kek := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte("goph-keeper/item-key/"+uid)), kek); err != nil {
+		return nil, err
+	}
+	return kek, nil
+}
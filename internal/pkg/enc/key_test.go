@@ -0,0 +1,77 @@
+package enc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateKey(t *testing.T) {
+	k1, err := GenerateKey()
+	assert.NoError(t, err)
+	assert.Len(t, k1, keySize)
+
+	k2, err := GenerateKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, k1, k2)
+}
+
+func TestUnwrapKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := WrapKey("owner", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uid     string
+		wrapped []byte
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:    "Owner is missing",
+			wrapped: wrapped,
+			wantErr: ErrMissingOwner,
+		},
+		{
+			name:    "Key is wrapped for another user",
+			uid:     "recipient",
+			wrapped: wrapped,
+			wantErr: ErrDecryption,
+		},
+		{
+			name:    "Key is wrapped for the user",
+			uid:     "owner",
+			wrapped: wrapped,
+			want:    key,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, uErr := UnwrapKey(tt.uid, tt.wrapped)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, uErr)
+		})
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = WrapKey("", key)
+	assert.Equal(t, ErrMissingOwner, err)
+
+	w1, err := WrapKey("owner", key)
+	assert.NoError(t, err)
+	w2, err := WrapKey("recipient", key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, w1, w2)
+}
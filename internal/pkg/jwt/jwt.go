@@ -5,17 +5,24 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 var secret = []byte("j6hsdQ$pj9_ymLQ0")
 
+type Claims struct {
+	ID        string
+	UID       string
+	ExpiresAt time.Time
+}
+
 var (
 	ErrTokenExpired = errors.New("jwt: token expired")
 	ErrTokenSigning = errors.New("jwt: unexpected token signing method")
 	ErrTokenClaims  = errors.New("jwt: failed to extract claims from a token")
 )
 
-// EncodeToken creates a token string with encoded user ID and expiry time.
+// EncodeToken creates a token string with encoded user ID, unique token ID and expiry time.
 func EncodeToken(uid string, expTime time.Duration) (string, error) {
 	if uid == "" {
 		return "", ErrTokenClaims
@@ -26,6 +33,7 @@ func EncodeToken(uid string, expTime time.Duration) (string, error) {
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"exp": time.Now().Add(expTime).Unix(),
+		"jti": uuid.NewString(),
 		"sub": uid,
 	})
 
@@ -41,6 +49,22 @@ func GetUserIDFromToken(token string) (string, error) {
 	return claims["sub"].(string), nil
 }
 
+// GetTokenClaims returns the token ID, user ID and expiry time encoded in a token string.
+func GetTokenClaims(token string) (Claims, error) {
+	claims, err := getClaims(token)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	jti, jOk := claims["jti"].(string)
+	sub, sOk := claims["sub"].(string)
+	exp, eOk := claims["exp"].(float64)
+	if !jOk || !sOk || !eOk {
+		return Claims{}, ErrTokenClaims
+	}
+	return Claims{ID: jti, UID: sub, ExpiresAt: time.Unix(int64(exp), 0).UTC()}, nil
+}
+
 // IsTokenExpired checks if a token is expired.
 func IsTokenExpired(token string) (bool, error) {
 	claims, err := getClaims(token)
@@ -54,3 +54,40 @@ func TestEncodeToken(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTokenClaims(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		expTime time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "Expired token",
+			uid:     "test",
+			expTime: -1 * time.Minute,
+			wantErr: true,
+		},
+		{
+			name:    "Valid token",
+			uid:     "test",
+			expTime: time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := EncodeToken(tt.uid, tt.expTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := GetTokenClaims(token)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tt.uid, got.UID)
+				assert.Equal(t, 36, len(got.ID))
+				assert.WithinDuration(t, time.Now().Add(tt.expTime), got.ExpiresAt, time.Second)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // SQL driver
+	log "github.com/sirupsen/logrus"
+)
+
+// Migration is the versioned schema change. The statements are executed in order within a single transaction.
+type Migration struct {
+	Version int
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// Status is the migration along with the time it was applied at. The pending migrations have zero AppliedAt.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// lockID is the key of the advisory lock preventing the concurrent migrations, e.g. by several starting servers.
+const lockID = 1_764_358_223
+
+const (
+	CreateMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations(
+    	version BIGINT,
+    	name VARCHAR(100) NOT NULL,
+    	applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    	PRIMARY KEY (version))`
+	DeleteMigration = "DELETE FROM schema_migrations WHERE version = $1"
+	GetMigrations   = "SELECT version, applied_at FROM schema_migrations ORDER BY version"
+	Lock            = "SELECT pg_advisory_lock($1)"
+	StoreMigration  = "INSERT INTO schema_migrations(version, name, applied_at) VALUES ($1, $2, $3)"
+	Unlock          = "SELECT pg_advisory_unlock($1)"
+)
+
+var (
+	ErrDBMissingURL   = errors.New("migrations db url is missing")
+	ErrInvalidSteps   = errors.New("the number of migrations to roll back must be positive")
+	ErrUnknownVersion = errors.New("the database schema is newer than the known migrations")
+)
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New returns the Migrator of the database with the specified URL, using the application schema migrations.
+func New(url string) (*Migrator, error) {
+	if url == "" {
+		return nil, ErrDBMissingURL
+	}
+
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(db, Migrations), nil
+}
+
+// NewMigrator returns the Migrator applying the migrations to the database. The migrations must be ordered by version.
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Down rolls back the specified number of the latest applied migrations, and returns the rolled back ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, ErrInvalidSteps
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.getApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if err = m.apply(ctx, conn, mg.Down, DeleteMigration, mg.Version); err != nil {
+				return fmt.Errorf("migration %d %s: %w", mg.Version, mg.Name, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status returns all known migrations, with the applied ones marked by the time they were applied at.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.getApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]Status, 0, len(m.migrations))
+		for _, mg := range m.migrations {
+			statuses = append(statuses, Status{Version: mg.Version, Name: mg.Name, AppliedAt: applied[mg.Version]})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Up applies all pending migrations in the version order, and returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.getApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if err = m.apply(ctx, conn, mg.Up, StoreMigration, mg.Version, mg.Name, time.Now().UTC()); err != nil {
+				return fmt.Errorf("migration %d %s: %w", mg.Version, mg.Name, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// apply executes the migration statements along with the schema_migrations change in a single transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, stmts []string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range stmts {
+		if _, err = tx.ExecContext(ctx, q); err != nil {
+			m.rollback(tx)
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		m.rollback(tx)
+		return err
+	}
+	return tx.Commit()
+}
+
+// getApplied returns the applied migration versions along with the time they were applied at.
+// The versions unknown to the Migrator are reported as an error, since the schema can't be changed safely.
+func (m *Migrator) getApplied(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	if _, err := conn.ExecContext(ctx, CreateMigrationsTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, GetMigrations)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer m.closeRows(rows)
+
+	known := make(map[int]bool, len(m.migrations))
+	for _, mg := range m.migrations {
+		known[mg.Version] = true
+	}
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			v int
+			t time.Time
+		)
+		if err = rows.Scan(&v, &t); err != nil {
+			return nil, err
+		}
+		if !known[v] {
+			return nil, fmt.Errorf("%w: version %d", ErrUnknownVersion, v)
+		}
+		applied[v] = t
+	}
+	return applied, nil
+}
+
+// withLock runs the function holding the advisory lock. The lock is bound to the connection,
+// so the function must use the passed connection only.
+func (m *Migrator) withLock(ctx context.Context, f func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.closeConn(conn)
+
+	if _, err = conn.ExecContext(ctx, Lock, lockID); err != nil {
+		return err
+	}
+	defer func() {
+		if _, uErr := conn.ExecContext(context.Background(), Unlock, lockID); uErr != nil {
+			log.Error(uErr)
+		}
+	}()
+	return f(conn)
+}
+
+func (m *Migrator) closeConn(conn *sql.Conn) {
+	if err := conn.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func (m *Migrator) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func (m *Migrator) rollback(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil {
+		log.Error(err)
+	}
+}
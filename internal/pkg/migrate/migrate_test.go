@@ -0,0 +1,284 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testApplied    = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testMigrations = []Migration{
+		{Version: 1, Name: "create_a", Up: []string{"CREATE TABLE a(id INT)"}, Down: []string{"DROP TABLE a"}},
+		{Version: 2, Name: "create_b", Up: []string{"CREATE TABLE b(id INT)"}, Down: []string{"DROP TABLE b"}},
+	}
+)
+
+func TestMigrations(t *testing.T) {
+	names := make(map[string]bool, len(Migrations))
+	for i, m := range Migrations {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.False(t, names[m.Name], m.Name)
+		assert.NotEmpty(t, m.Up, m.Name)
+		assert.NotEmpty(t, m.Down, m.Name)
+		names[m.Name] = true
+	}
+}
+
+func TestMigrations_UpgradeBaseline(t *testing.T) {
+	// The schema the servers created on start before the migrations were introduced.
+	baseline := testSchema{
+		"users":    {"id": true, "name": true, "password": true},
+		"sessions": {"cid": true, "token": true},
+		"storage":  {"id": true, "uid": true, "data": true, "type": true},
+	}
+	fresh := testSchema{}
+	for _, m := range Migrations {
+		fresh.apply(t, m.Up)
+		baseline.apply(t, m.Up)
+	}
+	assert.Equal(t, fresh, baseline)
+	assert.Contains(t, baseline["sessions"], "last_seen")
+	assert.Contains(t, baseline["storage"], "key")
+
+	for i := len(Migrations) - 1; i >= 0; i-- {
+		baseline.apply(t, Migrations[i].Down)
+	}
+	assert.Empty(t, baseline)
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{
+			name:    "Empty repo URL",
+			wantErr: ErrDBMissingURL,
+		},
+		{
+			name: "Repo URL is present",
+			url:  "postgres://localhost:5432/test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.url)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, Migrations, got.migrations)
+			}
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied []int
+		steps   int
+		want    []Migration
+		wantErr error
+	}{
+		{
+			name:    "No steps passed",
+			wantErr: ErrInvalidSteps,
+		},
+		{
+			name:  "Nothing is applied",
+			steps: 1,
+		},
+		{
+			name:    "Latest migration is rolled back",
+			applied: []int{1, 2},
+			steps:   1,
+			want:    testMigrations[1:],
+		},
+		{
+			name:    "All migrations are rolled back",
+			applied: []int{1, 2},
+			steps:   5,
+			want:    []Migration{testMigrations[1], testMigrations[0]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := initMigrator(t)
+			if tt.steps > 0 {
+				expectApplied(mock, tt.applied...)
+				for _, mg := range tt.want {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(mg.Down[0])).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectExec(regexp.QuoteMeta(DeleteMigration)).WithArgs(mg.Version).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				}
+				expectUnlock(mock)
+			}
+
+			got, err := m.Down(context.Background(), tt.steps)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, mock := initMigrator(t)
+	expectApplied(mock, 1)
+	expectUnlock(mock)
+
+	got, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Status{
+		{Version: 1, Name: "create_a", AppliedAt: testApplied},
+		{Version: 2, Name: "create_b"},
+	}, got)
+	checkMetExpectations(t, mock)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	errFailed := errors.New("syntax error")
+	tests := []struct {
+		name    string
+		applied []int
+		fail    bool
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "All migrations are applied",
+			want: testMigrations,
+		},
+		{
+			name:    "Pending migration is applied",
+			applied: []int{1},
+			want:    testMigrations[1:],
+		},
+		{
+			name:    "Schema is up to date",
+			applied: []int{1, 2},
+		},
+		{
+			name:    "Unknown version is applied",
+			applied: []int{1, 3},
+			wantErr: true,
+		},
+		{
+			name:    "Migration fails",
+			applied: []int{1},
+			fail:    true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := initMigrator(t)
+			expectApplied(mock, tt.applied...)
+			if tt.fail {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(testMigrations[1].Up[0])).WillReturnError(errFailed)
+				mock.ExpectRollback()
+			}
+			for _, mg := range tt.want {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(mg.Up[0])).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(StoreMigration)).
+					WithArgs(mg.Version, mg.Name, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+			expectUnlock(mock)
+
+			got, err := m.Up(context.Background())
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+// testSchema keeps the table columns the migrations statements produce.
+type testSchema map[string]map[string]bool
+
+var (
+	createTableRe = regexp.MustCompile(`(?s)^CREATE TABLE IF NOT EXISTS (\w+)\((.*)\)$`)
+	dropTableRe   = regexp.MustCompile(`^DROP TABLE IF EXISTS (\w+)$`)
+	addColumnRe   = regexp.MustCompile(`^ALTER TABLE (\w+) ADD COLUMN IF NOT EXISTS (\w+)`)
+	dropColumnRe  = regexp.MustCompile(`^ALTER TABLE (\w+) DROP COLUMN IF EXISTS (\w+)$`)
+	alterTableRe  = regexp.MustCompile(`^ALTER TABLE (\w+) (ALTER COLUMN|DROP CONSTRAINT)`)
+	columnNameRe  = regexp.MustCompile(`^\w+`)
+)
+
+func (s testSchema) apply(t *testing.T, statements []string) {
+	for _, stmt := range statements {
+		stmt = strings.TrimSpace(stmt)
+		if m := createTableRe.FindStringSubmatch(stmt); m != nil {
+			if _, ok := s[m[1]]; !ok {
+				s[m[1]] = getTestColumns(m[2])
+			}
+		} else if m = dropTableRe.FindStringSubmatch(stmt); m != nil {
+			delete(s, m[1])
+		} else if m = addColumnRe.FindStringSubmatch(stmt); m != nil {
+			assert.Contains(t, s, m[1], stmt)
+			if s[m[1]] != nil {
+				s[m[1]][m[2]] = true
+			}
+		} else if m = dropColumnRe.FindStringSubmatch(stmt); m != nil {
+			delete(s[m[1]], m[2])
+		} else if m = alterTableRe.FindStringSubmatch(stmt); m != nil {
+			assert.Contains(t, s, m[1], stmt)
+		} else {
+			t.Errorf("unsupported statement: %s", stmt)
+		}
+	}
+}
+
+func getTestColumns(body string) map[string]bool {
+	columns := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		switch name := columnNameRe.FindString(strings.TrimSpace(line)); name {
+		case "", "PRIMARY", "UNIQUE", "CONSTRAINT", "FOREIGN", "REFERENCES", "ON":
+		default:
+			columns[name] = true
+		}
+	}
+	return columns
+}
+
+func initMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewMigrator(db, testMigrations), mock
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectExec(regexp.QuoteMeta(Lock)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(CreateMigrationsTable)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := mock.NewRows([]string{"version", "applied_at"})
+	for _, v := range versions {
+		rows.AddRow(v, testApplied)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(GetMigrations)).WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(Unlock)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,263 @@
+package migrate
+
+// Migrations are the application schema changes ordered by version.
+// The first migration is the schema the servers created before the migrations were introduced, so their databases
+// are brought up to date by the later ones. The columns added since then use ADD COLUMN IF NOT EXISTS,
+// since the servers between the baseline and the migrations created some of them on start.
+// The applied migrations must never be edited, add a new one instead.
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_baseline",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS users(
+    			id UUID DEFAULT gen_random_uuid(),
+    			name VARCHAR(255),
+    			password VARCHAR(255),
+    			UNIQUE(name),
+    			PRIMARY KEY(id))`,
+			`CREATE TABLE IF NOT EXISTS sessions(
+    			cid VARCHAR(50),
+	   			token VARCHAR(165),
+	   			PRIMARY KEY (cid)
+			)`,
+			`CREATE TABLE IF NOT EXISTS storage(
+    			id UUID DEFAULT gen_random_uuid(),
+    			uid UUID,
+    			data BYTEA,
+    			type INT,
+    			PRIMARY KEY(id),
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+		},
+		Down: []string{"DROP TABLE IF EXISTS storage", "DROP TABLE IF EXISTS sessions", "DROP TABLE IF EXISTS users"},
+	},
+	{
+		Version: 2,
+		Name:    "extend_users_and_sessions",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS user_keys(
+    			uid UUID,
+    			public_key BYTEA NOT NULL,
+    			private_key BYTEA NOT NULL,
+    			PRIMARY KEY(uid),
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+			// The tokens outgrew the baseline column once they got the token ID and the session claims.
+			"ALTER TABLE sessions ALTER COLUMN token TYPE TEXT",
+			"ALTER TABLE sessions ADD COLUMN IF NOT EXISTS uid UUID",
+			"ALTER TABLE sessions ADD COLUMN IF NOT EXISTS device VARCHAR(100) NOT NULL DEFAULT ''",
+			"ALTER TABLE sessions ADD COLUMN IF NOT EXISTS ip VARCHAR(45) NOT NULL DEFAULT ''",
+			"ALTER TABLE sessions ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE sessions ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+			"ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_seen TIMESTAMPTZ NOT NULL DEFAULT now()",
+			`CREATE TABLE IF NOT EXISTS revoked_tokens(
+    			jti VARCHAR(50),
+    			expires_at TIMESTAMPTZ NOT NULL,
+    			PRIMARY KEY (jti))`,
+		},
+		Down: []string{
+			"DROP TABLE IF EXISTS revoked_tokens",
+			"ALTER TABLE sessions DROP COLUMN IF EXISTS last_seen",
+			"ALTER TABLE sessions DROP COLUMN IF EXISTS created_at",
+			"ALTER TABLE sessions DROP COLUMN IF EXISTS user_agent",
+			"ALTER TABLE sessions DROP COLUMN IF EXISTS ip",
+			"ALTER TABLE sessions DROP COLUMN IF EXISTS device",
+			"ALTER TABLE sessions DROP COLUMN IF EXISTS uid",
+			"DROP TABLE IF EXISTS user_keys",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "extend_storage",
+		Up: []string{
+			"ALTER TABLE storage ADD COLUMN IF NOT EXISTS key BYTEA",
+			// The items are owned by the organization collections too, not only by the users.
+			"ALTER TABLE storage DROP CONSTRAINT IF EXISTS fk_user",
+			`CREATE TABLE IF NOT EXISTS shares(
+    			item_id UUID,
+    			owner UUID,
+    			uid UUID,
+    			key BYTEA NOT NULL,
+    			read_only BOOLEAN NOT NULL DEFAULT true,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY(item_id, uid),
+				CONSTRAINT fk_item
+		    		FOREIGN KEY (item_id)
+		        		REFERENCES storage(id)
+                    		ON DELETE CASCADE,
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+			`CREATE TABLE IF NOT EXISTS sealed_shares(
+    			id UUID DEFAULT gen_random_uuid(),
+    			item_id UUID NOT NULL,
+    			owner UUID NOT NULL,
+    			uid UUID NOT NULL,
+    			type INT NOT NULL,
+    			data BYTEA NOT NULL,
+    			key BYTEA NOT NULL,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY(id),
+    			UNIQUE(item_id, uid),
+				CONSTRAINT fk_item
+		    		FOREIGN KEY (item_id)
+		        		REFERENCES storage(id)
+                    		ON DELETE CASCADE,
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+		},
+		// The user constraint isn't restored, since the collection items would violate it.
+		Down: []string{
+			"DROP TABLE IF EXISTS sealed_shares",
+			"DROP TABLE IF EXISTS shares",
+			"ALTER TABLE storage DROP COLUMN IF EXISTS key",
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create_api_tokens",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS api_tokens(
+    			id UUID DEFAULT gen_random_uuid(),
+    			uid UUID,
+    			name VARCHAR(50) NOT NULL,
+    			hash VARCHAR(64) NOT NULL UNIQUE,
+    			read_only BOOLEAN NOT NULL DEFAULT false,
+    			types TEXT NOT NULL DEFAULT '',
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			expires_at TIMESTAMPTZ NOT NULL,
+    			PRIMARY KEY (id),
+    			UNIQUE (uid, name),
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+		},
+		Down: []string{"DROP TABLE IF EXISTS api_tokens"},
+	},
+	{
+		Version: 5,
+		Name:    "create_oidc",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS oidc_states(
+    			state VARCHAR(64),
+    			verifier VARCHAR(128) NOT NULL,
+    			nonce VARCHAR(64) NOT NULL,
+    			redirect_uri TEXT NOT NULL,
+    			expires_at TIMESTAMPTZ NOT NULL,
+    			PRIMARY KEY (state))`,
+			`CREATE TABLE IF NOT EXISTS user_identities(
+    			issuer TEXT,
+    			subject TEXT,
+    			uid UUID NOT NULL,
+    			PRIMARY KEY (issuer, subject),
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+		},
+		Down: []string{"DROP TABLE IF EXISTS user_identities", "DROP TABLE IF EXISTS oidc_states"},
+	},
+	{
+		Version: 6,
+		Name:    "create_organizations",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS organizations(
+    			id UUID DEFAULT gen_random_uuid(),
+    			name VARCHAR(50) NOT NULL,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY (id))`,
+			`CREATE TABLE IF NOT EXISTS org_members(
+    			org_id UUID,
+    			uid UUID,
+    			role VARCHAR(20) NOT NULL,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY (org_id, uid),
+				CONSTRAINT fk_org
+		    		FOREIGN KEY (org_id)
+		        		REFERENCES organizations(id)
+                    		ON DELETE CASCADE,
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+			`CREATE TABLE IF NOT EXISTS collections(
+    			id UUID DEFAULT gen_random_uuid(),
+    			org_id UUID NOT NULL,
+    			name VARCHAR(50) NOT NULL,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY (id),
+    			UNIQUE (org_id, name),
+				CONSTRAINT fk_org
+		    		FOREIGN KEY (org_id)
+		        		REFERENCES organizations(id)
+                    		ON DELETE CASCADE )`,
+		},
+		Down: []string{
+			"DROP TABLE IF EXISTS collections", "DROP TABLE IF EXISTS org_members", "DROP TABLE IF EXISTS organizations",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create_share_links",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS share_links(
+    			id UUID DEFAULT gen_random_uuid(),
+    			uid UUID NOT NULL,
+    			name VARCHAR(100) NOT NULL,
+    			data BYTEA NOT NULL,
+    			max_views INT NOT NULL,
+    			views INT NOT NULL DEFAULT 0,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			expires_at TIMESTAMPTZ NOT NULL,
+    			PRIMARY KEY (id),
+				CONSTRAINT fk_user
+		    		FOREIGN KEY (uid)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+		},
+		Down: []string{"DROP TABLE IF EXISTS share_links"},
+	},
+	{
+		Version: 8,
+		Name:    "create_emergency_access",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS emergency_contacts(
+    			id UUID DEFAULT gen_random_uuid(),
+    			owner UUID NOT NULL,
+    			grantee UUID NOT NULL,
+    			wait_period BIGINT NOT NULL,
+    			status VARCHAR(20) NOT NULL,
+    			requested_at TIMESTAMPTZ NOT NULL,
+    			grants_at TIMESTAMPTZ NOT NULL,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY (id),
+    			UNIQUE (owner, grantee),
+				CONSTRAINT fk_owner
+		    		FOREIGN KEY (owner)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE,
+				CONSTRAINT fk_grantee
+		    		FOREIGN KEY (grantee)
+		        		REFERENCES users(id)
+                    		ON DELETE CASCADE )`,
+			`CREATE TABLE IF NOT EXISTS emergency_events(
+    			id UUID DEFAULT gen_random_uuid(),
+    			contact_id UUID NOT NULL,
+    			owner UUID NOT NULL,
+    			grantee UUID NOT NULL,
+    			action VARCHAR(20) NOT NULL,
+    			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    			PRIMARY KEY (id))`,
+		},
+		Down: []string{"DROP TABLE IF EXISTS emergency_events", "DROP TABLE IF EXISTS emergency_contacts"},
+	},
+}
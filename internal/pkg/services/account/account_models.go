@@ -0,0 +1,7 @@
+package account
+
+type Payload struct {
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+	PrivateKey  []byte `json:"private_key,omitempty"`
+}
@@ -0,0 +1,136 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/session"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type Service struct {
+	uow              storage.UnitOfWork
+	apiTokenService  apitoken.Service
+	dataService      data.Service
+	emergencyService emergency.Service
+	linkService      link.Service
+	oidcService      oidc.Service
+	orgService       org.Service
+	sessionService   session.Service
+	userService      user.Service
+}
+
+var (
+	ErrPrivateKeyMissing = errors.New("the private key encrypted with the new password is missing")
+	ErrWrongCredential   = errors.New("invalid username or password")
+)
+
+// NewService returns an instance of the Service with the associated API token, data, emergency, link, OIDC,
+// organization, session and user microservices.
+// The unit of work makes the changes spanning several microservices atomic.
+func NewService(uow storage.UnitOfWork, ts apitoken.Service, ds data.Service, es emergency.Service, ls link.Service,
+	ids oidc.Service, ors org.Service, ss session.Service, us user.Service,
+) Service {
+	return Service{
+		uow:              uow,
+		apiTokenService:  ts,
+		dataService:      ds,
+		emergencyService: es,
+		linkService:      ls,
+		oidcService:      ids,
+		orgService:       ors,
+		sessionService:   ss,
+		userService:      us,
+	}
+}
+
+// ChangeName renames the user with the unique ID.
+// If another user with the specified name already exists, the method returns an error.
+func (s Service) ChangeName(ctx context.Context, uid, name string) error {
+	err := s.userService.UpdateName(ctx, uid, name)
+	if errors.Is(err, user.ErrNotFound) {
+		return ErrWrongCredential
+	}
+	return err
+}
+
+// ChangePassword replaces the user's password, if the current one matches the stored password.
+// If the user has the key pair, the private key encrypted with the new password replaces the stored one,
+// so the password can't be changed without it.
+// All the user's sessions, except the one associated with the passed client ID, get revoked.
+// Nothing is changed if any step fails.
+func (s Service) ChangePassword(ctx context.Context, uid, cid string, payload Payload) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.userService.UpdatePassword(ctx, uid, payload.Password, payload.NewPassword); err != nil {
+			if errors.Is(err, user.ErrNotFound) {
+				return ErrWrongCredential
+			}
+			return err
+		}
+		if err := s.updatePrivateKey(ctx, uid, payload.PrivateKey); err != nil {
+			return err
+		}
+		return s.sessionService.DeleteUserSessions(ctx, uid, cid)
+	})
+}
+
+func (s Service) updatePrivateKey(ctx context.Context, uid string, key []byte) error {
+	kp, err := s.userService.GetKeyPair(ctx, uid)
+	if errors.Is(err, user.ErrNoKeyPair) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return ErrPrivateKeyMissing
+	}
+
+	kp.PrivateKey = key
+	return s.userService.SetKeyPair(ctx, kp)
+}
+
+// DeleteAccount removes the user with the unique ID, if the passed password matches the stored one.
+// All the user's stored data, sessions, API tokens, links, emergency contacts, organization memberships
+// and linked identities get removed as well, or nothing is removed if any step fails.
+// The last owner of an organization must transfer the ownership first, otherwise org.ErrLastOwner is returned.
+func (s Service) DeleteAccount(ctx context.Context, uid, password string) error {
+	if err := s.userService.VerifyPassword(ctx, uid, password); err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return ErrWrongCredential
+		}
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.orgService.DeleteUserMemberships(ctx, uid); err != nil {
+			return err
+		}
+		if err := s.emergencyService.DeleteUserContacts(ctx, uid); err != nil {
+			return err
+		}
+		if err := s.linkService.DeleteUserLinks(ctx, uid); err != nil {
+			return err
+		}
+		if err := s.oidcService.DeleteUserIdentities(ctx, uid); err != nil {
+			return err
+		}
+		if err := s.dataService.DeleteAllSecureData(ctx, uid); err != nil {
+			return err
+		}
+		if err := s.sessionService.DeleteUserSessions(ctx, uid, ""); err != nil {
+			return err
+		}
+		if err := s.apiTokenService.DeleteUserTokens(ctx, uid); err != nil {
+			return err
+		}
+		return s.userService.DeleteUser(ctx, uid)
+	})
+}
@@ -0,0 +1,303 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/link"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/session"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestNewService(t *testing.T) {
+	ms := initMS(t)
+	tests := []struct {
+		name string
+		want Service
+	}{
+		{
+			name: "Service creation",
+			want: ms,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewService(storage.UnitOfWork{}, ms.apiTokenService, ms.dataService,
+				ms.emergencyService, ms.linkService, ms.oidcService, ms.orgService, ms.sessionService, ms.userService))
+		})
+	}
+}
+
+func TestService_ChangeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		newName string
+		wantErr error
+	}{
+		{
+			name:    "Missing name",
+			wantErr: user.ErrCredMissing,
+		},
+		{
+			name:    "Unknown user",
+			uid:     "unknown",
+			newName: "test2",
+			wantErr: ErrWrongCredential,
+		},
+		{
+			name:    "Name is taken",
+			newName: "test1",
+			wantErr: user.ErrExists,
+		},
+		{
+			name:    "Name is changed",
+			newName: "Test2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, uid, _ := initService(t)
+			if tt.uid == "" {
+				tt.uid = uid
+			}
+			assert.Equal(t, tt.wantErr, s.ChangeName(context.Background(), tt.uid, tt.newName))
+		})
+	}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    Payload
+		keyPair    bool
+		keepOthers bool
+		wantKey    []byte
+		wantErr    error
+	}{
+		{
+			name:       "Missing new password",
+			payload:    Payload{Password: "test"},
+			keepOthers: true,
+			wantErr:    user.ErrCredMissing,
+		},
+		{
+			name:       "Wrong current password",
+			payload:    Payload{Password: "wrong", NewPassword: "test2"},
+			keepOthers: true,
+			wantErr:    ErrWrongCredential,
+		},
+		{
+			name:    "Password is changed",
+			payload: Payload{Password: "test", NewPassword: "test2"},
+		},
+		{
+			name:       "Private key is missing",
+			payload:    Payload{Password: "test", NewPassword: "test2"},
+			keyPair:    true,
+			keepOthers: true,
+			wantKey:    []byte("old"),
+			wantErr:    ErrPrivateKeyMissing,
+		},
+		{
+			name:    "Private key is replaced",
+			payload: Payload{Password: "test", NewPassword: "test2", PrivateKey: []byte("new")},
+			keyPair: true,
+			wantKey: []byte("new"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, uid, cids := initService(t)
+			if tt.keyPair {
+				kp := user.KeyPair{UID: uid, PublicKey: make([]byte, enc.PublicKeySize), PrivateKey: []byte("old")}
+				if err := s.userService.SetKeyPair(context.Background(), kp); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := s.ChangePassword(context.Background(), uid, cids[0], tt.payload)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err != nil, s.userService.VerifyPassword(context.Background(), uid, "test") == nil)
+			if tt.keyPair {
+				kp, kErr := s.userService.GetKeyPair(context.Background(), uid)
+				assert.NoError(t, kErr)
+				assert.Equal(t, tt.wantKey, kp.PrivateKey)
+			}
+
+			_, cErr := s.sessionService.RestoreSession(context.Background(), cids[0])
+			assert.NoError(t, cErr)
+			_, oErr := s.sessionService.RestoreSession(context.Background(), cids[1])
+			assert.Equal(t, tt.keepOthers, oErr == nil)
+		})
+	}
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		lastOwner bool
+		wantErr   error
+	}{
+		{
+			name:     "Wrong password",
+			password: "wrong",
+			wantErr:  ErrWrongCredential,
+		},
+		{
+			name:      "Last owner of the organization",
+			password:  "test",
+			lastOwner: true,
+			wantErr:   org.ErrLastOwner,
+		},
+		{
+			name:     "Account is deleted",
+			password: "test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, uid, cids := initService(t)
+			id, err := s.dataService.StoreSecureDataFromPayload(ctx, uid, "test", data.SText)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = s.apiTokenService.CreateToken(ctx, uid, "ci", apitoken.Scope{}, time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			other := initRelations(t, s, uid, tt.lastOwner)
+
+			err = s.DeleteAccount(ctx, uid, tt.password)
+			assert.Equal(t, tt.wantErr, err)
+
+			deleted := err == nil
+			_, uErr := s.userService.GetUserByID(ctx, uid)
+			assert.Equal(t, deleted, uErr != nil)
+			_, dErr := s.dataService.GetDataByID(ctx, uid, id)
+			assert.Equal(t, deleted, dErr != nil)
+			tokens, _ := s.apiTokenService.GetUserTokens(ctx, uid)
+			assert.Equal(t, deleted, len(tokens) == 0)
+			for _, cid := range cids {
+				_, sErr := s.sessionService.RestoreSession(ctx, cid)
+				assert.Equal(t, deleted, sErr != nil)
+			}
+			links, _ := s.linkService.GetUserLinks(ctx, uid)
+			assert.Equal(t, deleted, len(links) == 0)
+			orgs, _ := s.orgService.GetOrganizations(ctx, uid)
+			assert.Equal(t, deleted, len(orgs) == 0)
+			contacts, _ := s.emergencyService.GetContacts(ctx, other)
+			assert.Equal(t, deleted, len(contacts) == 0)
+		})
+	}
+}
+
+// initRelations links the user to another one through the organization and the emergency contacts
+// in both directions, and creates the user's link. Unless the user is meant to be the last owner,
+// the other user owns the organization too. The ID of the other user is returned.
+func initRelations(t *testing.T, s Service, uid string, lastOwner bool) string {
+	ctx := context.Background()
+	other, err := s.userService.GetUserByName(ctx, "test1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orgID, err := s.orgService.CreateOrganization(ctx, uid, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lastOwner {
+		if err = s.orgService.SetMember(ctx, uid, orgID, other.ID, org.RoleOwner); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = s.emergencyService.AddContact(ctx, uid, other.ID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.emergencyService.AddContact(ctx, other.ID, uid, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = s.linkService.CreateLink(ctx, uid, "text", []byte("secret"), 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	return other.ID
+}
+
+func initService(t *testing.T) (Service, string, []string) {
+	ms := initMS(t)
+	us, ss := ms.userService, ms.sessionService
+	for _, u := range []user.User{{Name: "test", Password: "test"}, {Name: "test1", Password: "test1"}} {
+		if err := us.AddUser(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, err := us.GetUser(context.Background(), user.User{Name: "test", Password: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cids := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		token, tErr := ss.GenerateToken(u.ID)
+		if tErr != nil {
+			t.Fatal(tErr)
+		}
+		cid, sErr := ss.StoreSession(context.Background(), u.ID, token, session.Client{})
+		if sErr != nil {
+			t.Fatal(sErr)
+		}
+		cids = append(cids, cid)
+	}
+
+	return ms, u.ID, cids
+}
+
+func initMS(t *testing.T) Service {
+	ts, err := apitoken.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es, err := emergency.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := link.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := oidc.NewIdentityService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ors, err := org.NewService(nil, nil, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := session.NewService(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	us, err := user.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(storage.UnitOfWork{}, ts, ds, es, ls, ids, ors, ss, us)
+}
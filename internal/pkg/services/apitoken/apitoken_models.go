@@ -0,0 +1,24 @@
+package apitoken
+
+import "time"
+
+type Scope struct {
+	ReadOnly bool     `json:"read_only"`
+	Types    []string `json:"types"`
+}
+
+type Token struct {
+	Scope
+	ID        string    `json:"id"`
+	UID       string    `json:"-"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Dump is the complete content of the repository, moved between the repositories with the IDs preserved.
+// Only the token hashes are stored, so the restored tokens are still usable by their holders.
+type Dump struct {
+	Tokens []Token
+}
@@ -0,0 +1,30 @@
+package apitoken
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrDBMissing   = errors.New("api token db is missing")
+	ErrExists      = errors.New("the api token with specified name already exists")
+	ErrMissingArgs = errors.New("user id, token name or hash is not specified")
+	ErrNotFound    = errors.New("api token not found")
+)
+
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
+		return NewBasicRepo(), nil
+	}
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
+}
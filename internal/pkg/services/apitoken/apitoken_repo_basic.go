@@ -0,0 +1,144 @@
+package apitoken
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BasicRepo struct {
+	tokens *sync.Map
+}
+
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{tokens: &sync.Map{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[Token](w, "tokens", r.tokens); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteToken(ctx context.Context, uid, id string) error {
+	if t, ok := r.tokens.Load(id); ok && t.(Token).UID == uid {
+		_, err := storage.Delete(ctx, r.tokens, id)
+		return err
+	}
+	return ErrNotFound
+}
+
+func (r *BasicRepo) DeleteUserTokens(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	var err error
+	r.tokens.Range(func(k, v any) bool {
+		if v.(Token).UID == uid {
+			_, err = storage.Delete(ctx, r.tokens, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// Export returns the tokens sorted by their IDs.
+func (r *BasicRepo) Export(_ context.Context) (Dump, error) {
+	var dump Dump
+	r.tokens.Range(func(_, v any) bool {
+		dump.Tokens = append(dump.Tokens, v.(Token))
+		return true
+	})
+
+	sort.Slice(dump.Tokens, func(i, j int) bool { return dump.Tokens[i].ID < dump.Tokens[j].ID })
+	return dump, nil
+}
+
+func (r *BasicRepo) GetTokenByHash(_ context.Context, hash string) (Token, error) {
+	var token Token
+	r.tokens.Range(func(_, v any) bool {
+		if t := v.(Token); t.Hash == hash {
+			token = t
+			return false
+		}
+		return true
+	})
+
+	if hash == "" || token.ID == "" {
+		return Token{}, ErrNotFound
+	}
+	return token, nil
+}
+
+func (r *BasicRepo) GetUserTokens(_ context.Context, uid string) ([]Token, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	tokens := make([]Token, 0)
+	r.tokens.Range(func(_, v any) bool {
+		if t := v.(Token); t.UID == uid {
+			tokens = append(tokens, t)
+		}
+		return true
+	})
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+// Import stores the tokens with their IDs. The changes are undone on failure within the unit of work only.
+func (r *BasicRepo) Import(ctx context.Context, dump Dump) error {
+	for _, t := range dump.Tokens {
+		if t.ID == "" || t.UID == "" || t.Name == "" || t.Hash == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.tokens.Load(t.ID); ok || r.hasToken(t) {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.tokens, t.ID, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BasicRepo) StoreToken(ctx context.Context, token Token) (string, error) {
+	if token.UID == "" || token.Name == "" || token.Hash == "" {
+		return "", ErrMissingArgs
+	}
+
+	if r.hasToken(token) {
+		return "", ErrExists
+	}
+
+	token.ID = uuid.NewString()
+	if err := storage.Store(ctx, r.tokens, token.ID, token); err != nil {
+		return "", err
+	}
+	return token.ID, nil
+}
+
+// hasToken checks if the user has the token with the same name, or any user has the token with the same hash.
+func (r *BasicRepo) hasToken(token Token) bool {
+	var exists bool
+	r.tokens.Range(func(_, v any) bool {
+		t := v.(Token)
+		exists = (t.UID == token.UID && t.Name == token.Name) || t.Hash == token.Hash
+		return !exists
+	})
+	return exists
+}
@@ -0,0 +1,176 @@
+package apitoken
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBasicRepo_DeleteToken(t *testing.T) {
+	for _, tt := range getDeleteTokenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			err := r.DeleteToken(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_DeleteUserTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "No user ID passed",
+			want:    []string{"testID", "testID1", "testID2"},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "User tokens are deleted",
+			uid:  "testUser",
+			want: []string{"testID2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(getTestTokens())
+			err := r.DeleteUserTokens(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+
+			var got []string
+			r.tokens.Range(func(k, _ any) bool {
+				got = append(got, k.(string))
+				return true
+			})
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasicRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	tokens := getTestTokens()
+	dump := Dump{Tokens: []Token{tokens["testID"], tokens["testID1"], tokens["testID2"]}}
+
+	r := initBasicRepo(nil)
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+
+	dup := tokens["testID"]
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Tokens: []Token{dup}}))
+	dup.ID, dup.Name = "testID3", "other"
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Tokens: []Token{dup}}))
+	dup.Hash = ""
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Tokens: []Token{dup}}))
+}
+
+func TestBasicRepo_GetTokenByHash(t *testing.T) {
+	for _, tt := range getGetTokenByHashCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetTokenByHash(context.Background(), tt.hash)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_GetUserTokens(t *testing.T) {
+	for _, tt := range getGetUserTokensCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetUserTokens(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_StoreToken(t *testing.T) {
+	for _, tt := range getStoreTokenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.StoreToken(context.Background(), tt.token)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestNewBasicRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantField     string
+		wantFieldType string
+		wantType      string
+	}{
+		{
+			name:          "Basic repo is created",
+			wantField:     "tokens",
+			wantFieldType: "*sync.Map",
+			wantType:      "*apitoken.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBasicRepo()
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.wantType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.wantField, rField.Name)
+			assert.Equal(t, tt.wantFieldType, rField.Type.String())
+		})
+	}
+}
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := Token{
+		Scope:     Scope{ReadOnly: true, Types: []string{"text"}},
+		UID:       "testUser",
+		Name:      "ci",
+		Hash:      "testHash",
+		CreatedAt: created,
+		ExpiresAt: created.Add(time.Hour),
+	}
+
+	r := initPersistentRepo(t, dir)
+	id, err := r.StoreToken(ctx, token)
+	assert.NoError(t, err)
+	id1, err := r.StoreToken(ctx, Token{UID: "testUser", Name: "backup", Hash: "testHash1"})
+	assert.NoError(t, err)
+	assert.NoError(t, r.DeleteToken(ctx, "testUser", id1))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetUserTokens(ctx, "testUser")
+	assert.NoError(t, err)
+	token.ID = id
+	assert.Equal(t, []Token{token}, got)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "tokens", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,197 @@
+package apitoken
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const uniqueViolation = "23505"
+
+type DBRepo struct {
+	db *sql.DB
+}
+
+const (
+	DeleteToken      = "DELETE FROM api_tokens WHERE uid = $1 AND id = $2"
+	DeleteUserTokens = "DELETE FROM api_tokens WHERE uid = $1"
+	ExportTokens     = `
+		SELECT id, uid, name, hash, read_only, types, created_at, expires_at FROM api_tokens ORDER BY id
+	`
+	GetTokenByHash = `
+		SELECT id, uid, name, hash, read_only, types, created_at, expires_at FROM api_tokens WHERE hash = $1
+	`
+	GetUserTokens = `
+		SELECT id, uid, name, hash, read_only, types, created_at, expires_at FROM api_tokens
+		WHERE uid = $1 ORDER BY created_at
+	`
+	ImportToken = `
+		INSERT INTO api_tokens(id, uid, name, hash, read_only, types, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	StoreToken = `
+		INSERT INTO api_tokens(uid, name, hash, read_only, types, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
+	`
+)
+
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
+	}
+	return &DBRepo{db: db}, nil
+}
+
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *DBRepo) DeleteToken(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteToken, uid, id)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *DBRepo) DeleteUserTokens(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteUserTokens, uid)
+	return err
+}
+
+// Export returns the tokens sorted by their IDs.
+func (r *DBRepo) Export(ctx context.Context) (Dump, error) {
+	tokens, err := r.queryTokens(ctx, ExportTokens)
+	if err != nil {
+		return Dump{}, err
+	}
+	return Dump{Tokens: tokens}, nil
+}
+
+func (r *DBRepo) GetTokenByHash(ctx context.Context, hash string) (Token, error) {
+	if hash == "" {
+		return Token{}, ErrNotFound
+	}
+
+	var (
+		t     Token
+		types string
+	)
+	err := r.conn(ctx).QueryRowContext(ctx, GetTokenByHash, hash).Scan(&t.ID, &t.UID, &t.Name, &t.Hash,
+		&t.ReadOnly, &types, &t.CreatedAt, &t.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Token{}, ErrNotFound
+		}
+		return Token{}, err
+	}
+
+	t.Types = splitTypes(types)
+	return t, nil
+}
+
+func (r *DBRepo) GetUserTokens(ctx context.Context, uid string) ([]Token, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	return r.queryTokens(ctx, GetUserTokens, uid)
+}
+
+func (r *DBRepo) Import(ctx context.Context, dump Dump) error {
+	for _, t := range dump.Tokens {
+		if t.ID == "" || t.UID == "" || t.Name == "" || t.Hash == "" {
+			return ErrMissingArgs
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, ImportToken, t.ID, t.UID, t.Name, t.Hash, t.ReadOnly,
+			strings.Join(t.Types, ","), t.CreatedAt, t.ExpiresAt)
+		if err != nil {
+			return mapDBError(err)
+		}
+	}
+	return nil
+}
+
+func (r *DBRepo) StoreToken(ctx context.Context, token Token) (string, error) {
+	if token.UID == "" || token.Name == "" || token.Hash == "" {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, StoreToken, token.UID, token.Name, token.Hash, token.ReadOnly,
+		strings.Join(token.Types, ","), token.CreatedAt, token.ExpiresAt).Scan(&id)
+	if err != nil {
+		return "", mapDBError(err)
+	}
+	return id, nil
+}
+
+func (r *DBRepo) queryTokens(ctx context.Context, query string, args ...any) ([]Token, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	tokens := make([]Token, 0)
+	for rows.Next() {
+		var (
+			t     Token
+			types string
+		)
+		if err = rows.Scan(&t.ID, &t.UID, &t.Name, &t.Hash, &t.ReadOnly, &types,
+			&t.CreatedAt, &t.ExpiresAt); err != nil {
+			return nil, err
+		}
+		t.Types = splitTypes(types)
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+func (r *DBRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func splitTypes(types string) []string {
+	if types == "" {
+		return nil
+	}
+	return strings.Split(types, ",")
+}
+
+func mapDBError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrExists
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+package apitoken
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBRepo_DeleteToken(t *testing.T) {
+	for _, tt := range getDeleteTokenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.args.uid != "" && tt.args.id != "" {
+				var rows int64
+				if tt.repo[tt.args.id].UID == tt.args.uid {
+					rows = 1
+				}
+				mock.ExpectExec(regexp.QuoteMeta(DeleteToken)).WithArgs(tt.args.uid, tt.args.id).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.DeleteToken(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_ExportImport(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := getTestTokens()
+	dump := Dump{Tokens: []Token{tokens["testID"], tokens["testID1"], tokens["testID2"]}}
+	mock.ExpectQuery(regexp.QuoteMeta(ExportTokens)).WillReturnRows(getTokenRows(mock, dump.Tokens...))
+	for _, tk := range dump.Tokens[:2] {
+		mock.ExpectExec(regexp.QuoteMeta(ImportToken)).WithArgs(tk.ID, tk.UID, tk.Name, tk.Hash, tk.ReadOnly,
+			strings.Join(tk.Types, ","), tk.CreatedAt, tk.ExpiresAt).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec(regexp.QuoteMeta(ImportToken)).WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+
+	got, err := r.Export(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+	assert.Equal(t, ErrExists, r.Import(context.Background(), dump))
+	assert.Equal(t, ErrMissingArgs, r.Import(context.Background(), Dump{Tokens: []Token{{UID: "testUser"}}}))
+	checkMetExpectations(t, mock)
+}
+
+func TestDBRepo_GetTokenByHash(t *testing.T) {
+	for _, tt := range getGetTokenByHashCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.hash != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(GetTokenByHash)).WithArgs(tt.hash)
+				if tt.want.ID != "" {
+					eq.WillReturnRows(getTokenRows(mock, tt.want))
+				} else {
+					eq.WillReturnError(sql.ErrNoRows)
+				}
+			}
+
+			got, err := r.GetTokenByHash(context.Background(), tt.hash)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_GetUserTokens(t *testing.T) {
+	for _, tt := range getGetUserTokensCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				mock.ExpectQuery(regexp.QuoteMeta(GetUserTokens)).WithArgs(tt.uid).
+					WillReturnRows(getTokenRows(mock, tt.want...))
+			}
+
+			got, err := r.GetUserTokens(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreToken(t *testing.T) {
+	for _, tt := range getStoreTokenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tk := tt.token
+			if tk.UID != "" && tk.Name != "" && tk.Hash != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(StoreToken)).WithArgs(tk.UID, tk.Name, tk.Hash,
+					tk.ReadOnly, strings.Join(tk.Types, ","), tk.CreatedAt, tk.ExpiresAt)
+				if tt.wantErr != nil {
+					eq.WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+				} else {
+					eq.WillReturnRows(mock.NewRows([]string{"id"}).AddRow("testID3"))
+				}
+			}
+
+			got, err := r.StoreToken(context.Background(), tt.token)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestNewDBRepo(t *testing.T) {
+	type want struct {
+		repoType  string
+		fieldName string
+		fieldType string
+	}
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "DB is missing",
+			wantErr: true,
+			want: want{
+				repoType:  "*apitoken.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: want{
+				repoType:  "*apitoken.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDBRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want.repoType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.want.fieldName, rField.Name)
+			assert.Equal(t, tt.want.fieldType, rField.Type.String())
+		})
+	}
+}
+
+func getTokenRows(mock sqlmock.Sqlmock, tokens ...Token) *sqlmock.Rows {
+	rows := mock.NewRows([]string{"id", "uid", "name", "hash", "read_only", "types", "created_at", "expires_at"})
+	for _, t := range tokens {
+		rows.AddRow(t.ID, t.UID, t.Name, t.Hash, t.ReadOnly, strings.Join(t.Types, ","), t.CreatedAt, t.ExpiresAt)
+	}
+	return rows
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
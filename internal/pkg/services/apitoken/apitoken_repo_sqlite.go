@@ -0,0 +1,199 @@
+package apitoken
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteTokens = `CREATE TABLE IF NOT EXISTS api_tokens(
+		id TEXT PRIMARY KEY,
+		uid TEXT NOT NULL,
+		name TEXT NOT NULL,
+		hash TEXT NOT NULL UNIQUE,
+		read_only BOOLEAN NOT NULL DEFAULT false,
+		types TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		UNIQUE(uid, name))`
+	sqliteDeleteToken      = "DELETE FROM api_tokens WHERE uid = ?1 AND id = ?2"
+	sqliteDeleteUserTokens = "DELETE FROM api_tokens WHERE uid = ?1"
+	sqliteExportTokens     = `
+		SELECT id, uid, name, hash, read_only, types, created_at, expires_at FROM api_tokens ORDER BY id
+	`
+	sqliteGetTokenByHash = `
+		SELECT id, uid, name, hash, read_only, types, created_at, expires_at FROM api_tokens WHERE hash = ?1
+	`
+	sqliteGetUserTokens = `
+		SELECT id, uid, name, hash, read_only, types, created_at, expires_at FROM api_tokens
+		WHERE uid = ?1 ORDER BY created_at
+	`
+	sqliteStoreToken = `
+		INSERT INTO api_tokens(id, uid, name, hash, read_only, types, created_at, expires_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+	`
+)
+
+// NewSQLiteRepo returns the repository keeping the API tokens in the SQLite database,
+// and creates its table if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	if _, err := db.ExecContext(context.Background(), createSQLiteTokens); err != nil {
+		return &SQLiteRepo{}, err
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteToken(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteToken, uid, id)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) DeleteUserTokens(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteUserTokens, uid)
+	return err
+}
+
+// Export returns the tokens sorted by their IDs.
+func (r *SQLiteRepo) Export(ctx context.Context) (Dump, error) {
+	tokens, err := r.queryTokens(ctx, sqliteExportTokens)
+	if err != nil {
+		return Dump{}, err
+	}
+	return Dump{Tokens: tokens}, nil
+}
+
+func (r *SQLiteRepo) GetTokenByHash(ctx context.Context, hash string) (Token, error) {
+	if hash == "" {
+		return Token{}, ErrNotFound
+	}
+
+	var (
+		t     Token
+		types string
+	)
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetTokenByHash, hash).Scan(&t.ID, &t.UID, &t.Name, &t.Hash,
+		&t.ReadOnly, &types, &t.CreatedAt, &t.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Token{}, ErrNotFound
+		}
+		return Token{}, err
+	}
+
+	t.Types = splitTypes(types)
+	return t, nil
+}
+
+func (r *SQLiteRepo) GetUserTokens(ctx context.Context, uid string) ([]Token, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	return r.queryTokens(ctx, sqliteGetUserTokens, uid)
+}
+
+func (r *SQLiteRepo) Import(ctx context.Context, dump Dump) error {
+	for _, t := range dump.Tokens {
+		if t.ID == "" || t.UID == "" || t.Name == "" || t.Hash == "" {
+			return ErrMissingArgs
+		}
+		if err := r.storeToken(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) StoreToken(ctx context.Context, token Token) (string, error) {
+	if token.UID == "" || token.Name == "" || token.Hash == "" {
+		return "", ErrMissingArgs
+	}
+
+	token.ID = uuid.NewString()
+	if err := r.storeToken(ctx, token); err != nil {
+		return "", err
+	}
+	return token.ID, nil
+}
+
+func (r *SQLiteRepo) storeToken(ctx context.Context, t Token) error {
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreToken, t.ID, t.UID, t.Name, t.Hash, t.ReadOnly,
+		strings.Join(t.Types, ","), t.CreatedAt.UTC(), t.ExpiresAt.UTC())
+	return mapSQLiteError(err)
+}
+
+func (r *SQLiteRepo) queryTokens(ctx context.Context, query string, args ...any) ([]Token, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	tokens := make([]Token, 0)
+	for rows.Next() {
+		var (
+			t     Token
+			types string
+		)
+		if err = rows.Scan(&t.ID, &t.UID, &t.Name, &t.Hash, &t.ReadOnly, &types,
+			&t.CreatedAt, &t.ExpiresAt); err != nil {
+			return nil, err
+		}
+		t.Types = splitTypes(types)
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey ||
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique) {
+		return ErrExists
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package apitoken
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteToken(t *testing.T) {
+	for _, tt := range getDeleteTokenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			err := r.DeleteToken(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_DeleteUserTokens(t *testing.T) {
+	r := initSQLiteRepo(t, getTestTokens())
+	assert.Equal(t, ErrMissingArgs, r.DeleteUserTokens(context.Background(), ""))
+	assert.NoError(t, r.DeleteUserTokens(context.Background(), "testUser"))
+
+	dump, err := r.Export(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{getTestTokens()["testID2"]}, dump.Tokens)
+}
+
+func TestSQLiteRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	tokens := getTestTokens()
+	dump := Dump{Tokens: []Token{tokens["testID"], tokens["testID1"], tokens["testID2"]}}
+
+	r := initSQLiteRepo(t, nil)
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+
+	dup := tokens["testID"]
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Tokens: []Token{dup}}))
+	dup.ID, dup.Name = "testID3", "other"
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Tokens: []Token{dup}}))
+	dup.Hash = ""
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Tokens: []Token{dup}}))
+}
+
+func TestSQLiteRepo_GetTokenByHash(t *testing.T) {
+	for _, tt := range getGetTokenByHashCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.GetTokenByHash(context.Background(), tt.hash)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_GetUserTokens(t *testing.T) {
+	for _, tt := range getGetUserTokensCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.GetUserTokens(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreToken(t *testing.T) {
+	for _, tt := range getStoreTokenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.StoreToken(context.Background(), tt.token)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*apitoken.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*apitoken.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, data map[string]Token) *SQLiteRepo {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dump Dump
+	for id, token := range data {
+		token.ID = id
+		dump.Tokens = append(dump.Tokens, token)
+	}
+	if err = r.Import(context.Background(), dump); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
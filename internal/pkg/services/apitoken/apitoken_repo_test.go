@@ -0,0 +1,227 @@
+package apitoken
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type deleteTokenArgs struct {
+	uid string
+	id  string
+}
+
+type deleteTokenCase struct {
+	name    string
+	repo    map[string]Token
+	args    deleteTokenArgs
+	wantErr error
+}
+
+type getTokenByHashCase struct {
+	name    string
+	repo    map[string]Token
+	hash    string
+	want    Token
+	wantErr error
+}
+
+type getUserTokensCase struct {
+	name    string
+	repo    map[string]Token
+	uid     string
+	want    []Token
+	wantErr error
+}
+
+type storeTokenCase struct {
+	name    string
+	repo    map[string]Token
+	token   Token
+	wantErr error
+}
+
+var testCreated = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DB is missing",
+			want: "*apitoken.BasicRepo",
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*apitoken.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*apitoken.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRepo(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want, rGot.Type().String())
+		})
+	}
+}
+
+func initBasicRepo(data map[string]Token) *BasicRepo {
+	tokens := &sync.Map{}
+	for id, token := range data {
+		token.ID = id
+		tokens.Store(id, token)
+	}
+	return &BasicRepo{tokens: tokens}
+}
+
+func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	return &DBRepo{db: db}, mock, err
+}
+
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getTestTokens() map[string]Token {
+	return map[string]Token{
+		"testID": {
+			ID:        "testID",
+			UID:       "testUser",
+			Name:      "ci",
+			Hash:      "testHash",
+			Scope:     Scope{ReadOnly: true, Types: []string{"password"}},
+			CreatedAt: testCreated,
+			ExpiresAt: testCreated.Add(time.Hour),
+		},
+		"testID1": {
+			ID:        "testID1",
+			UID:       "testUser",
+			Name:      "deploy",
+			Hash:      "testHash1",
+			CreatedAt: testCreated.Add(time.Minute),
+			ExpiresAt: testCreated.Add(time.Hour),
+		},
+		"testID2": {
+			ID:        "testID2",
+			UID:       "testUser1",
+			Name:      "ci",
+			Hash:      "testHash2",
+			CreatedAt: testCreated,
+			ExpiresAt: testCreated.Add(time.Hour),
+		},
+	}
+}
+
+func getDeleteTokenCases() []deleteTokenCase {
+	return []deleteTokenCase{
+		{
+			name:    "No arguments passed",
+			repo:    getTestTokens(),
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Token of another user",
+			repo:    getTestTokens(),
+			args:    deleteTokenArgs{uid: "testUser1", id: "testID"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Token of the user",
+			repo: getTestTokens(),
+			args: deleteTokenArgs{uid: "testUser", id: "testID"},
+		},
+	}
+}
+
+func getGetTokenByHashCases() []getTokenByHashCase {
+	tr := getTestTokens()
+	return []getTokenByHashCase{
+		{
+			name:    "No hash passed",
+			repo:    tr,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Unknown hash",
+			repo:    tr,
+			hash:    "unknown",
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Known hash",
+			repo: tr,
+			hash: "testHash",
+			want: tr["testID"],
+		},
+	}
+}
+
+func getGetUserTokensCases() []getUserTokensCase {
+	tr := getTestTokens()
+	return []getUserTokensCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "No tokens for user present",
+			repo: tr,
+			uid:  "testUser0",
+			want: []Token{},
+		},
+		{
+			name: "User tokens are returned",
+			repo: tr,
+			uid:  "testUser",
+			want: []Token{tr["testID"], tr["testID1"]},
+		},
+	}
+}
+
+func getStoreTokenCases() []storeTokenCase {
+	return []storeTokenCase{
+		{
+			name:    "No arguments passed",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "No hash passed",
+			token:   Token{UID: "testUser", Name: "ci"},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Name is taken",
+			repo:    getTestTokens(),
+			token:   Token{UID: "testUser", Name: "ci", Hash: "testHash3"},
+			wantErr: ErrExists,
+		},
+		{
+			name:  "All arguments are correct",
+			repo:  getTestTokens(),
+			token: Token{UID: "testUser", Name: "backup", Hash: "testHash3", ExpiresAt: testCreated},
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+// Prefix marks the goph-keeper API tokens, so they can be told apart from other credentials.
+const Prefix = "gk_"
+
+var (
+	ErrExpired       = errors.New("api token expired")
+	ErrInvalidExpiry = errors.New("api token expiry time must be in the future")
+)
+
+type IRepository interface {
+	DeleteToken(ctx context.Context, uid, id string) error
+	DeleteUserTokens(ctx context.Context, uid string) error
+	Export(ctx context.Context) (Dump, error)
+	GetTokenByHash(ctx context.Context, hash string) (Token, error)
+	GetUserTokens(ctx context.Context, uid string) ([]Token, error)
+	Import(ctx context.Context, dump Dump) error
+	StoreToken(ctx context.Context, token Token) (string, error)
+}
+
+type Service struct {
+	db IRepository
+}
+
+// NewService returns an instance of the Service with the associated repository.
+// Without the database handle, the tokens are persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo}, err
+}
+
+// Authenticate looks for the stored token matching the passed token string.
+// If the token is expired, the method deletes it from the repository and returns an error.
+func (s Service) Authenticate(ctx context.Context, token string) (Token, error) {
+	if !strings.HasPrefix(token, Prefix) {
+		return Token{}, ErrNotFound
+	}
+
+	t, err := s.db.GetTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		return Token{}, err
+	}
+
+	if !t.ExpiresAt.After(time.Now()) {
+		_ = s.db.DeleteToken(ctx, t.UID, t.ID)
+		return Token{}, ErrExpired
+	}
+	return t, nil
+}
+
+// CreateToken generates a new named token for the user with the specified scope and expiry time.
+// Only the token hash is stored, so the returned token string can't be restored later.
+func (s Service) CreateToken(ctx context.Context, uid, name string, scope Scope,
+	expiresAt time.Time,
+) (string, Token, error) {
+	now := time.Now().UTC()
+	if !expiresAt.After(now) {
+		return "", Token{}, ErrInvalidExpiry
+	}
+
+	raw, err := generateToken()
+	if err != nil {
+		return "", Token{}, err
+	}
+
+	t := Token{
+		Scope:     scope,
+		UID:       uid,
+		Name:      name,
+		Hash:      hashToken(raw),
+		CreatedAt: now,
+		ExpiresAt: expiresAt.UTC(),
+	}
+	if t.ID, err = s.db.StoreToken(ctx, t); err != nil {
+		return "", Token{}, err
+	}
+	return raw, t, nil
+}
+
+// DeleteToken revokes the user's token with the unique ID.
+func (s Service) DeleteToken(ctx context.Context, uid, id string) error {
+	return s.db.DeleteToken(ctx, uid, id)
+}
+
+// DeleteUserTokens revokes all the user's tokens.
+func (s Service) DeleteUserTokens(ctx context.Context, uid string) error {
+	return s.db.DeleteUserTokens(ctx, uid)
+}
+
+// Export returns the tokens of all users.
+func (s Service) Export(ctx context.Context) (Dump, error) {
+	return s.db.Export(ctx)
+}
+
+// Import stores the exported tokens with their IDs and hashes, as is.
+// Run it within the unit of work, so the failed import leaves no partial changes behind.
+func (s Service) Import(ctx context.Context, dump Dump) error {
+	return s.db.Import(ctx, dump)
+}
+
+// GetUserTokens returns all the user's tokens ordered by the creation time.
+func (s Service) GetUserTokens(ctx context.Context, uid string) ([]Token, error) {
+	return s.db.GetUserTokens(ctx, uid)
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,135 @@
+package apitoken
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *sql.DB
+		wantRepoType string
+		wantErr      bool
+	}{
+		{
+			name:         "DB is missing",
+			wantRepoType: "*apitoken.BasicRepo",
+		},
+		{
+			name:         "DB is present",
+			db:           initDB(t),
+			wantRepoType: "*apitoken.DBRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rRepo := reflect.ValueOf(got.db)
+			assert.Equal(t, tt.wantRepoType, rRepo.Type().String())
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		expired bool
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "Token without prefix",
+			token:   "token",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Unknown token",
+			token:   Prefix + "unknown",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Expired token",
+			expired: true,
+			wantErr: ErrExpired,
+		},
+		{
+			name: "Valid token",
+			want: "testUser",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil)
+			s := Service{db: r}
+			raw, tk, err := s.CreateToken(context.Background(), "testUser", "ci", Scope{}, time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.expired {
+				tk.ExpiresAt = time.Now().Add(-time.Minute)
+				r.tokens.Store(tk.ID, tk)
+			}
+			if tt.token == "" {
+				tt.token = raw
+			}
+
+			got, err := s.Authenticate(context.Background(), tt.token)
+			assert.Equal(t, tt.want, got.UID)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_CreateToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenName string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{
+			name:      "Expiry time in the past",
+			tokenName: "ci",
+			expiresAt: time.Now().Add(-time.Hour),
+			wantErr:   ErrInvalidExpiry,
+		},
+		{
+			name:      "Missing name",
+			expiresAt: time.Now().Add(time.Hour),
+			wantErr:   ErrMissingArgs,
+		},
+		{
+			name:      "Token is created",
+			tokenName: "ci",
+			expiresAt: time.Now().Add(time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil)
+			s := Service{db: r}
+			raw, tk, err := s.CreateToken(context.Background(), "testUser", tt.tokenName,
+				Scope{ReadOnly: true}, tt.expiresAt)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				assert.True(t, strings.HasPrefix(raw, Prefix))
+				assert.Equal(t, hashToken(raw), tk.Hash)
+				assert.NotContains(t, tk.Hash, raw)
+
+				stored, _ := r.GetTokenByHash(context.Background(), tk.Hash)
+				assert.Equal(t, tk.ID, stored.ID)
+			}
+		})
+	}
+}
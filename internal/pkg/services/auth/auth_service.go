@@ -16,6 +16,8 @@ type Service struct {
 
 var (
 	ErrSessionExpired  = errors.New("the session has expired, please re-login")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("the session has been revoked, please re-login")
 	ErrWrongCredential = errors.New("invalid username or password")
 )
 
@@ -29,7 +31,8 @@ func NewService(ss session.Service, us user.Service) Service {
 
 // Authorize parses the passed token string and returns the user ID associated with it.
 // If the token is empty or expired, the method returns an error.
-func (s Service) Authorize(token string) (string, error) {
+// The token must not be revoked and must belong to the active session of the specified client.
+func (s Service) Authorize(ctx context.Context, cid, token string) (string, error) {
 	if token == "" {
 		return "", ErrWrongCredential
 	}
@@ -40,14 +43,68 @@ func (s Service) Authorize(token string) (string, error) {
 		}
 		return "", err
 	}
-	return s.sessionService.GetUIDFromToken(token)
+
+	if rev, err := s.sessionService.IsTokenRevoked(ctx, token); err != nil || rev {
+		if err != nil {
+			return "", err
+		}
+		return "", ErrSessionRevoked
+	}
+
+	uid, err := s.sessionService.GetUIDFromToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	ss, err := s.sessionService.GetSession(ctx, cid)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return "", ErrSessionRevoked
+		}
+		return "", err
+	}
+	if ss.Token != token || ss.UID != uid {
+		return "", ErrSessionRevoked
+	}
+
+	return uid, s.sessionService.TouchSession(ctx, ss)
+}
+
+// AuthorizeCertificate returns the ID of the user the verified client certificate is mapped to.
+// The certificates are bound to the user IDs rather than the names, since the names can be changed.
+func (s Service) AuthorizeCertificate(ctx context.Context, uid string) (string, error) {
+	u, err := s.userService.GetUserByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return "", ErrWrongCredential
+		}
+		return "", err
+	}
+	return u.ID, nil
+}
+
+// DeleteSession revokes the user's session associated with the passed client ID.
+func (s Service) DeleteSession(ctx context.Context, uid, cid string) error {
+	if err := s.sessionService.DeleteUserSession(ctx, uid, cid); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetSessions returns all the active user's sessions.
+func (s Service) GetSessions(ctx context.Context, uid string) ([]session.Session, error) {
+	return s.sessionService.GetUserSessions(ctx, uid)
 }
 
 // Login establishes the user session based on the client ID and user credential.
 // If the client ID is passed, the method looks for the associated stored session.
 // If the client ID is empty, or the associated token is not found or expired, the method performs login by credential.
 // If the credential doesn't match, or another unknown error has occurred, the method returns an error.
-func (s Service) Login(ctx context.Context, cid string, req Payload) (string, string, error) {
+// The client details are stored with the newly created session.
+func (s Service) Login(ctx context.Context, cid string, req Payload, client session.Client) (string, string, error) {
 	if cid != "" {
 		t, err := s.sessionService.RestoreSession(ctx, cid)
 		if err == nil {
@@ -67,12 +124,18 @@ func (s Service) Login(ctx context.Context, cid string, req Payload) (string, st
 		return "", "", err
 	}
 
-	token, err := s.sessionService.GenerateToken(su.ID)
+	return s.LoginByID(ctx, su.ID, client)
+}
+
+// LoginByID establishes a new session of the user whose identity has been already verified,
+// e.g. by the external identity provider.
+func (s Service) LoginByID(ctx context.Context, uid string, client session.Client) (string, string, error) {
+	token, err := s.sessionService.GenerateToken(uid)
 	if err != nil {
 		return "", "", err
 	}
 
-	cid, err = s.sessionService.StoreSession(ctx, token)
+	cid, err := s.sessionService.StoreSession(ctx, uid, token, client)
 	if err != nil {
 		return "", "", err
 	}
@@ -41,6 +41,11 @@ func TestService_Authorize(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	revToken, err := jwt.EncodeToken("test-revoked3", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
 		name    string
 		repo    map[string]string
@@ -57,22 +62,96 @@ func TestService_Authorize(t *testing.T) {
 			token:   expToken,
 			wantErr: ErrSessionExpired,
 		},
+		{
+			name:    "Revoked session",
+			repo:    map[string]string{token: "test-valid1"},
+			token:   revToken,
+			wantErr: ErrSessionRevoked,
+		},
+		{
+			name:    "Logged out token",
+			repo:    map[string]string{token: "test-valid1"},
+			token:   token,
+			wantErr: ErrSessionRevoked,
+		},
 		{
 			name:  "Valid token",
+			repo:  map[string]string{token: "test-valid1"},
 			token: token,
 			want:  "test-valid1",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, _ := initService(t, tt.repo, nil)
-			got, aErr := s.Authorize(tt.token)
+			s, r := initService(t, tt.repo, nil)
+			if tt.name == "Logged out token" {
+				if _, err = s.Logout(context.Background(), r[tt.token]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, aErr := s.Authorize(context.Background(), r[tt.token], tt.token)
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, aErr)
 		})
 	}
 }
 
+func TestService_AuthorizeCertificate(t *testing.T) {
+	tests := []struct {
+		name     string
+		users    map[string]user.User
+		userName string
+		byName   bool
+		rename   string
+		want     bool
+		wantErr  error
+	}{
+		{
+			name:     "Unknown user",
+			userName: "test",
+			wantErr:  ErrWrongCredential,
+		},
+		{
+			name:     "User is mapped by the name",
+			users:    map[string]user.User{"test": {Name: "test", Password: "test"}},
+			userName: "test",
+			byName:   true,
+			wantErr:  ErrWrongCredential,
+		},
+		{
+			name:     "Known user",
+			users:    map[string]user.User{"test": {Name: "test", Password: "test"}},
+			userName: "test",
+			want:     true,
+		},
+		{
+			name:     "Renamed user",
+			users:    map[string]user.User{"test": {Name: "test", Password: "test"}},
+			userName: "test",
+			rename:   "test2",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initService(t, nil, tt.users)
+			uid := tt.userName
+			if u, err := s.userService.GetUserByName(context.Background(), tt.userName); err == nil && !tt.byName {
+				uid = u.ID
+			}
+			if tt.rename != "" {
+				if err := s.userService.UpdateName(context.Background(), uid, tt.rename); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := s.AuthorizeCertificate(context.Background(), uid)
+			assert.Equal(t, tt.want, got != "")
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
 func TestService_Login(t *testing.T) {
 	token, err := jwt.EncodeToken("test-user", 0)
 	if err != nil {
@@ -116,14 +195,14 @@ func TestService_Login(t *testing.T) {
 				cid: "wrong",
 				req: Payload{Name: "test", Password: "test"},
 			},
-			want:  165,
+			want:  225,
 			want1: 27,
 		},
 		{
 			name:  "Right CID",
 			repo:  repo{sessions: map[string]string{token: "id"}},
 			args:  args{cid: "right"},
-			want:  129,
+			want:  189,
 			want1: 27,
 		},
 	}
@@ -134,7 +213,7 @@ func TestService_Login(t *testing.T) {
 				tt.args.cid = r[token]
 			}
 
-			got, got1, lErr := s.Login(context.Background(), tt.args.cid, tt.args.req)
+			got, got1, lErr := s.Login(context.Background(), tt.args.cid, tt.args.req, session.Client{})
 			assert.Equal(t, tt.want, len(got))
 			assert.Equal(t, tt.want1, len(got1))
 			assert.Equal(t, tt.wantErr, lErr)
@@ -142,6 +221,112 @@ func TestService_Login(t *testing.T) {
 	}
 }
 
+func TestService_LoginByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			wantErr: session.ErrEmptyUID,
+		},
+		{
+			name: "Session is created",
+			uid:  "test-user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initService(t, nil, nil)
+			token, cid, err := s.LoginByID(context.Background(), tt.uid, session.Client{Device: "ci"})
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				got, aErr := s.Authorize(context.Background(), cid, token)
+				assert.NoError(t, aErr)
+				assert.Equal(t, tt.uid, got)
+			}
+		})
+	}
+}
+
+func TestService_DeleteSession(t *testing.T) {
+	token, err := jwt.EncodeToken("test-user", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uid     string
+		cid     string
+		wantErr error
+	}{
+		{
+			name:    "Missing CID",
+			uid:     "test-user",
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name:    "Session of another user",
+			uid:     "test-user1",
+			cid:     "right",
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "Session of the user",
+			uid:  "test-user",
+			cid:  "right",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r := initService(t, map[string]string{token: "test-user"}, nil)
+			if tt.cid == "right" {
+				tt.cid = r[token]
+			}
+			assert.Equal(t, tt.wantErr, s.DeleteSession(context.Background(), tt.uid, tt.cid))
+		})
+	}
+}
+
+func TestService_GetSessions(t *testing.T) {
+	token, err := jwt.EncodeToken("test-user", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uid     string
+		wantLen int
+		wantErr error
+	}{
+		{
+			name:    "Missing UID",
+			wantErr: session.ErrIncorrectData,
+		},
+		{
+			name: "No sessions",
+			uid:  "test-user1",
+		},
+		{
+			name:    "User sessions",
+			uid:     "test-user",
+			wantLen: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initService(t, map[string]string{token: "test-user"}, nil)
+			got, gErr := s.GetSessions(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantLen, len(got))
+			assert.Equal(t, tt.wantErr, gErr)
+		})
+	}
+}
+
 func TestService_Logout(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -247,7 +432,7 @@ func initService(t *testing.T, sessions map[string]string, users map[string]user
 }
 
 func initSessionService(t *testing.T, sessions map[string]string) (session.Service, map[string]string) {
-	s, err := session.NewService("")
+	s, err := session.NewService(nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -256,8 +441,8 @@ func initSessionService(t *testing.T, sessions map[string]string) (session.Servi
 	}
 
 	repo := make(map[string]string, len(sessions))
-	for v := range sessions {
-		cid, sErr := s.StoreSession(context.Background(), v)
+	for v, uid := range sessions {
+		cid, sErr := s.StoreSession(context.Background(), uid, v, session.Client{})
 		if sErr != nil {
 			t.Fatal(err)
 		}
@@ -268,7 +453,7 @@ func initSessionService(t *testing.T, sessions map[string]string) (session.Servi
 }
 
 func initUserService(t *testing.T, users map[string]user.User) user.Service {
-	s, err := user.NewService("")
+	s, err := user.NewService(nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
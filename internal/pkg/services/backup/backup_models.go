@@ -0,0 +1,168 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/archive"
+)
+
+// Version is the current version of the archive format.
+// The archives of the newer versions are rejected, the older ones are read as long as they are supported.
+// The version 2 archives hold the organizations, emergency contacts and API tokens as well,
+// and the version 3 ones hold the linked provider identities.
+const Version = 3
+
+// magic tells the server backups from the other archives.
+const magic = "GKBACKUP"
+
+// Summary describes the content of the archive.
+type Summary struct {
+	Version      int
+	CreatedAt    time.Time
+	Users        int
+	KeyPairs     int
+	Items        int
+	Shares       int
+	SealedShares int
+	Orgs         int
+	Members      int
+	Collections  int
+	Contacts     int
+	Events       int
+	Tokens       int
+	Identities   int
+}
+
+// contents mirrors the repository records in the format of its own, so the archives stay readable
+// regardless of the changes to the repository models and backends.
+type contents struct {
+	Users        []userRecord        `json:"users"`
+	KeyPairs     []keyPairRecord     `json:"key_pairs"`
+	Items        []itemRecord        `json:"items"`
+	Shares       []shareRecord       `json:"shares"`
+	SealedShares []sealedShareRecord `json:"sealed_shares"`
+	Orgs         []orgRecord         `json:"orgs,omitempty"`
+	Members      []memberRecord      `json:"members,omitempty"`
+	Collections  []collectionRecord  `json:"collections,omitempty"`
+	Contacts     []contactRecord     `json:"contacts,omitempty"`
+	Events       []eventRecord       `json:"events,omitempty"`
+	Tokens       []tokenRecord       `json:"tokens,omitempty"`
+	Identities   []identityRecord    `json:"identities,omitempty"`
+}
+
+type userRecord struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type keyPairRecord struct {
+	UID        string `json:"uid"`
+	PublicKey  []byte `json:"public_key"`
+	PrivateKey []byte `json:"private_key"`
+}
+
+type itemRecord struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"`
+	Type  int    `json:"type"`
+	Data  []byte `json:"data"`
+	Key   []byte `json:"key"`
+}
+
+type shareRecord struct {
+	ItemID    string    `json:"item_id"`
+	Owner     string    `json:"owner"`
+	UID       string    `json:"uid"`
+	Key       []byte    `json:"key"`
+	ReadOnly  bool      `json:"read_only"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type sealedShareRecord struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"item_id"`
+	Owner     string    `json:"owner"`
+	UID       string    `json:"uid"`
+	Data      []byte    `json:"data"`
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type orgRecord struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type memberRecord struct {
+	OrgID     string    `json:"org_id"`
+	UID       string    `json:"uid"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// collectionRecord is the team vault, its items are archived along with the users' ones, owned by the collection ID.
+type collectionRecord struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type contactRecord struct {
+	ID          string        `json:"id"`
+	Owner       string        `json:"owner"`
+	Grantee     string        `json:"grantee"`
+	WaitPeriod  time.Duration `json:"wait_period"`
+	Status      string        `json:"status"`
+	RequestedAt time.Time     `json:"requested_at"`
+	GrantsAt    time.Time     `json:"grants_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type eventRecord struct {
+	ID        string    `json:"id"`
+	ContactID string    `json:"contact_id"`
+	Owner     string    `json:"owner"`
+	Grantee   string    `json:"grantee"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type tokenRecord struct {
+	ID        string    `json:"id"`
+	UID       string    `json:"uid"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	ReadOnly  bool      `json:"read_only"`
+	Types     []string  `json:"types"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// identityRecord links the subject of the provider with the issuer to the user.
+type identityRecord struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+	UID     string `json:"uid"`
+}
+
+func (c contents) summary(h archive.Header) Summary {
+	return Summary{
+		Version:      h.Version,
+		CreatedAt:    h.CreatedAt,
+		Users:        len(c.Users),
+		KeyPairs:     len(c.KeyPairs),
+		Items:        len(c.Items),
+		Shares:       len(c.Shares),
+		SealedShares: len(c.SealedShares),
+		Orgs:         len(c.Orgs),
+		Members:      len(c.Members),
+		Collections:  len(c.Collections),
+		Contacts:     len(c.Contacts),
+		Events:       len(c.Events),
+		Tokens:       len(c.Tokens),
+		Identities:   len(c.Identities),
+	}
+}
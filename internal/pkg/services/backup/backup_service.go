@@ -0,0 +1,281 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/archive"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type Service struct {
+	uow              storage.UnitOfWork
+	dataService      data.Service
+	userService      user.Service
+	orgService       org.Service
+	emergencyService emergency.Service
+	tokenService     apitoken.Service
+	identityService  oidc.Service
+}
+
+// dumps is the content of all repositories the archive is made of.
+type dumps struct {
+	users      user.Dump
+	data       data.Dump
+	orgs       org.Dump
+	emergency  emergency.Dump
+	tokens     apitoken.Dump
+	identities oidc.Dump
+}
+
+// errDryRun rolls back the unit of work of the dry-run restore.
+var errDryRun = errors.New("dry run")
+
+// NewService returns an instance of the Service with the associated microservices.
+// The unit of work makes the restore atomic, so the failed one leaves the repositories untouched.
+func NewService(uow storage.UnitOfWork, ds data.Service, us user.Service, orgs org.Service, es emergency.Service,
+	ts apitoken.Service, ids oidc.Service,
+) Service {
+	return Service{
+		uow:              uow,
+		dataService:      ds,
+		userService:      us,
+		orgService:       orgs,
+		emergencyService: es,
+		tokenService:     ts,
+		identityService:  ids,
+	}
+}
+
+// Backup writes the encrypted archive of all users, their key pairs, items and shares,
+// along with the organizations, emergency contacts, API tokens and linked provider identities.
+// The templates and the items of the organization collections are archived as the items.
+// The archive is encrypted with the key derived from the passphrase, and can be restored to any repository backend.
+// The item keys stay wrapped with the server secret, so the archive is only usable by the server sharing it.
+func (s Service) Backup(ctx context.Context, w io.Writer, passphrase string) (Summary, error) {
+	if passphrase == "" {
+		return Summary{}, enc.ErrPasswordLength
+	}
+
+	// The repositories are exported within the snapshot, so the archive is consistent while the server runs.
+	var d dumps
+	err := s.uow.Snapshot(ctx, func(ctx context.Context) error {
+		var err error
+		if d.users, err = s.userService.Export(ctx); err != nil {
+			return err
+		}
+		if d.data, err = s.dataService.Export(ctx); err != nil {
+			return err
+		}
+		if d.orgs, err = s.orgService.Export(ctx); err != nil {
+			return err
+		}
+		if d.emergency, err = s.emergencyService.Export(ctx); err != nil {
+			return err
+		}
+		if d.tokens, err = s.tokenService.Export(ctx); err != nil {
+			return err
+		}
+		d.identities, err = s.identityService.Export(ctx)
+		return err
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+
+	c := newContents(d)
+	h, err := archive.Write(w, magic, Version, passphrase, c)
+	if err != nil {
+		return Summary{}, err
+	}
+	return c.summary(h), nil
+}
+
+// Restore verifies the archive, and imports its content into the repositories with all IDs preserved.
+// Nothing is restored if any of the records conflicts with the existing ones.
+// The dry run performs the whole restore, and rolls it back afterwards, so it reports the same errors.
+func (s Service) Restore(ctx context.Context, r io.Reader, passphrase string, dryRun bool) (Summary, error) {
+	var c contents
+	h, err := archive.Read(r, magic, Version, passphrase, &c)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	d := c.dumps()
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.userService.Import(ctx, d.users); err != nil {
+			return err
+		}
+		if err := s.orgService.Import(ctx, d.orgs); err != nil {
+			return err
+		}
+		if err := s.dataService.Import(ctx, d.data); err != nil {
+			return err
+		}
+		if err := s.emergencyService.Import(ctx, d.emergency); err != nil {
+			return err
+		}
+		if err := s.tokenService.Import(ctx, d.tokens); err != nil {
+			return err
+		}
+		if err := s.identityService.Import(ctx, d.identities); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return Summary{}, err
+	}
+	return c.summary(h), nil
+}
+
+func newContents(d dumps) contents {
+	var c contents
+	for _, u := range d.users.Users {
+		c.Users = append(c.Users, userRecord{ID: u.ID, Name: u.Name, Password: u.Password})
+	}
+	for _, kp := range d.users.KeyPairs {
+		c.KeyPairs = append(c.KeyPairs, keyPairRecord{UID: kp.UID, PublicKey: kp.PublicKey, PrivateKey: kp.PrivateKey})
+	}
+	for _, it := range d.data.Data {
+		c.Items = append(c.Items, itemRecord{ID: it.ID, Owner: it.UID, Type: int(it.Type), Data: it.Data, Key: it.Key})
+	}
+	for _, sh := range d.data.Shares {
+		c.Shares = append(c.Shares, shareRecord{
+			ItemID: sh.ItemID, Owner: sh.Owner, UID: sh.UID, Key: sh.Key, ReadOnly: sh.ReadOnly, CreatedAt: sh.CreatedAt,
+		})
+	}
+	for _, sh := range d.data.Sealed {
+		c.SealedShares = append(c.SealedShares, sealedShareRecord{
+			ID: sh.ID, ItemID: sh.ItemID, Owner: sh.Owner, UID: sh.UID, Data: sh.Data, Key: sh.Key, CreatedAt: sh.CreatedAt,
+		})
+	}
+	c.addOrgs(d.orgs)
+	c.addEmergency(d.emergency)
+	for _, t := range d.tokens.Tokens {
+		c.Tokens = append(c.Tokens, tokenRecord{
+			ID: t.ID, UID: t.UID, Name: t.Name, Hash: t.Hash, ReadOnly: t.ReadOnly, Types: t.Types, CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+		})
+	}
+	for _, id := range d.identities.Identities {
+		c.Identities = append(c.Identities, identityRecord{Issuer: id.Issuer, Subject: id.Subject, UID: id.UID})
+	}
+	return c
+}
+
+func (c *contents) addOrgs(d org.Dump) {
+	for _, o := range d.Organizations {
+		c.Orgs = append(c.Orgs, orgRecord{ID: o.ID, Name: o.Name, CreatedAt: o.CreatedAt})
+	}
+	for _, m := range d.Members {
+		c.Members = append(c.Members, memberRecord{OrgID: m.OrgID, UID: m.UID, Role: string(m.Role), CreatedAt: m.CreatedAt})
+	}
+	for _, col := range d.Collections {
+		c.Collections = append(c.Collections, collectionRecord{
+			ID: col.ID, OrgID: col.OrgID, Name: col.Name, CreatedAt: col.CreatedAt,
+		})
+	}
+}
+
+func (c *contents) addEmergency(d emergency.Dump) {
+	for _, ct := range d.Contacts {
+		c.Contacts = append(c.Contacts, contactRecord{
+			ID: ct.ID, Owner: ct.Owner, Grantee: ct.Grantee, WaitPeriod: ct.WaitPeriod, Status: string(ct.Status),
+			RequestedAt: ct.RequestedAt, GrantsAt: ct.GrantsAt, CreatedAt: ct.CreatedAt,
+		})
+	}
+	for _, e := range d.Events {
+		c.Events = append(c.Events, eventRecord{
+			ID: e.ID, ContactID: e.ContactID, Owner: e.Owner, Grantee: e.Grantee, Action: string(e.Action),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+}
+
+func (c contents) dumps() dumps {
+	var d dumps
+	for _, u := range c.Users {
+		d.users.Users = append(d.users.Users, user.User{ID: u.ID, Name: u.Name, Password: u.Password})
+	}
+	for _, kp := range c.KeyPairs {
+		d.users.KeyPairs = append(d.users.KeyPairs, user.KeyPair{
+			UID: kp.UID, PublicKey: kp.PublicKey, PrivateKey: kp.PrivateKey,
+		})
+	}
+	for _, it := range c.Items {
+		d.data.Data = append(d.data.Data, data.SecureData{
+			ID: it.ID, UID: it.Owner, Type: data.StorageType(it.Type), Data: it.Data, Key: it.Key,
+		})
+	}
+	for _, sh := range c.Shares {
+		d.data.Shares = append(d.data.Shares, data.Share{
+			ItemID: sh.ItemID, Owner: sh.Owner, UID: sh.UID, Key: sh.Key, ReadOnly: sh.ReadOnly, CreatedAt: sh.CreatedAt,
+		})
+	}
+	for _, sh := range c.SealedShares {
+		d.data.Sealed = append(d.data.Sealed, data.SealedShare{
+			ID: sh.ID, ItemID: sh.ItemID, Owner: sh.Owner, UID: sh.UID, Data: sh.Data, Key: sh.Key, CreatedAt: sh.CreatedAt,
+		})
+	}
+	d.orgs = c.orgDump()
+	d.emergency = c.emergencyDump()
+	for _, t := range c.Tokens {
+		d.tokens.Tokens = append(d.tokens.Tokens, apitoken.Token{
+			Scope: apitoken.Scope{ReadOnly: t.ReadOnly, Types: t.Types}, ID: t.ID, UID: t.UID, Name: t.Name,
+			Hash: t.Hash, CreatedAt: t.CreatedAt, ExpiresAt: t.ExpiresAt,
+		})
+	}
+	for _, id := range c.Identities {
+		d.identities.Identities = append(d.identities.Identities, oidc.Identity{
+			Issuer: id.Issuer, Subject: id.Subject, UID: id.UID,
+		})
+	}
+	return d
+}
+
+func (c contents) orgDump() org.Dump {
+	var d org.Dump
+	for _, o := range c.Orgs {
+		d.Organizations = append(d.Organizations, org.Organization{ID: o.ID, Name: o.Name, CreatedAt: o.CreatedAt})
+	}
+	for _, m := range c.Members {
+		d.Members = append(d.Members, org.Member{
+			OrgID: m.OrgID, UID: m.UID, Role: org.Role(m.Role), CreatedAt: m.CreatedAt,
+		})
+	}
+	for _, col := range c.Collections {
+		d.Collections = append(d.Collections, org.Collection{
+			ID: col.ID, OrgID: col.OrgID, Name: col.Name, CreatedAt: col.CreatedAt,
+		})
+	}
+	return d
+}
+
+func (c contents) emergencyDump() emergency.Dump {
+	var d emergency.Dump
+	for _, ct := range c.Contacts {
+		d.Contacts = append(d.Contacts, emergency.Contact{
+			ID: ct.ID, Owner: ct.Owner, Grantee: ct.Grantee, WaitPeriod: ct.WaitPeriod, Status: emergency.Status(ct.Status),
+			RequestedAt: ct.RequestedAt, GrantsAt: ct.GrantsAt, CreatedAt: ct.CreatedAt,
+		})
+	}
+	for _, e := range c.Events {
+		d.Events = append(d.Events, emergency.Event{
+			ID: e.ID, ContactID: e.ContactID, Owner: e.Owner, Grantee: e.Grantee, Action: emergency.Action(e.Action),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return d
+}
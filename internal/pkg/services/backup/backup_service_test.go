@@ -0,0 +1,323 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/archive"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/apitoken"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/emergency"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/org"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const passphrase = "passphrase"
+
+// testMS is the set of the microservices the backup is made of.
+type testMS struct {
+	ds   data.Service
+	us   user.Service
+	orgs org.Service
+	es   emergency.Service
+	ts   apitoken.Service
+	ids  oidc.Service
+}
+
+// testSource is the content of the source repositories the restored ones are checked against.
+type testSource struct {
+	share      data.Share
+	collection string
+	item       string
+	token      string
+	identity   oidc.Identity
+}
+
+func TestNewService(t *testing.T) {
+	ms := initMS(t, nil)
+	want := Service{dataService: ms.ds, userService: ms.us, orgService: ms.orgs, emergencyService: ms.es,
+		tokenService: ms.ts, identityService: ms.ids}
+	assert.Equal(t, want, ms.service(storage.UnitOfWork{}))
+}
+
+func TestService_Backup(t *testing.T) {
+	s, _ := initSource(t)
+
+	_, err := s.Backup(context.Background(), &bytes.Buffer{}, "")
+	assert.Equal(t, enc.ErrPasswordLength, err)
+
+	var buf bytes.Buffer
+	got, err := s.Backup(context.Background(), &buf, passphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, Version, got.Version)
+	assert.False(t, got.CreatedAt.IsZero())
+	assert.Equal(t, Summary{
+		Version: Version, CreatedAt: got.CreatedAt, Users: 2, KeyPairs: 1, Items: 2, Shares: 1, Orgs: 1, Members: 2,
+		Collections: 1, Contacts: 1, Events: 1, Tokens: 1, Identities: 1,
+	}, got)
+	assert.Equal(t, magic, buf.String()[:len(magic)])
+}
+
+func TestService_Restore(t *testing.T) {
+	s, _ := initSource(t)
+	var buf bytes.Buffer
+	want, err := s.Backup(context.Background(), &buf, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived := buf.Bytes()
+
+	tests := []struct {
+		name       string
+		file       []byte
+		passphrase string
+		wantErr    error
+	}{
+		{
+			name:    "Passphrase is missing",
+			file:    archived,
+			wantErr: enc.ErrPasswordLength,
+		},
+		{
+			name:       "Passphrase is wrong",
+			file:       archived,
+			passphrase: "wrong",
+			wantErr:    archive.ErrIntegrity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _ := initTarget(t, nil)
+			_, rErr := target.Restore(context.Background(), bytes.NewReader(tt.file), tt.passphrase, false)
+			assert.Equal(t, tt.wantErr, rErr)
+		})
+	}
+
+	t.Run("Archive of the earlier version is restored", func(t *testing.T) {
+		var v1 bytes.Buffer
+		c := contents{Users: []userRecord{{ID: "testID", Name: "test", Password: "test"}}}
+		h, wErr := archive.Write(&v1, magic, 1, passphrase, c)
+		if wErr != nil {
+			t.Fatal(wErr)
+		}
+
+		target, _ := initTarget(t, nil)
+		got, rErr := target.Restore(context.Background(), &v1, passphrase, false)
+		assert.NoError(t, rErr)
+		assert.Equal(t, Summary{Version: 1, CreatedAt: h.CreatedAt, Users: 1}, got)
+	})
+
+	t.Run("Dry run leaves the repositories untouched", func(t *testing.T) {
+		target, ds := initTarget(t, nil)
+		got, rErr := target.Restore(context.Background(), bytes.NewReader(archived), passphrase, true)
+		assert.NoError(t, rErr)
+		assert.Equal(t, want, got)
+
+		dump, dErr := ds.Export(context.Background())
+		assert.NoError(t, dErr)
+		assert.Empty(t, dump.Data)
+	})
+
+	t.Run("Conflicting restore is rolled back", func(t *testing.T) {
+		restored, ds := initTarget(t, nil)
+		_, rErr := restored.Restore(context.Background(), bytes.NewReader(archived), passphrase, false)
+		assert.NoError(t, rErr)
+
+		// The users are restored to the empty repository, and then the items conflict with the restored ones.
+		ms := initMS(t, nil)
+		us := ms.us
+		ms.ds = ds
+		target := ms.service(storage.UnitOfWork{})
+		_, rErr = target.Restore(context.Background(), bytes.NewReader(archived), passphrase, false)
+		assert.Equal(t, data.ErrExists, rErr)
+
+		dump, dErr := us.Export(context.Background())
+		assert.NoError(t, dErr)
+		assert.Empty(t, dump.Users)
+	})
+}
+
+func TestService_Restore_Organizations(t *testing.T) {
+	ctx := context.Background()
+	s, src := initSource(t)
+	var buf bytes.Buffer
+	if _, err := s.Backup(ctx, &buf, passphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	ms := initMS(t, nil)
+	_, err := ms.service(storage.UnitOfWork{}).Restore(ctx, &buf, passphrase, false)
+	assert.NoError(t, err)
+
+	// The members keep their roles, and the collection items are readable by the collection, as on the source server.
+	for uid, want := range map[string]org.Role{src.share.Owner: org.RoleOwner, src.share.UID: org.RoleMember} {
+		role, aErr := ms.orgs.AuthorizeVault(ctx, uid, src.collection)
+		assert.NoError(t, aErr)
+		assert.Equal(t, want, role)
+	}
+	d, err := ms.ds.GetDataByID(ctx, src.collection, src.item)
+	assert.NoError(t, err)
+	got, err := ms.ds.DecryptSecureData(src.collection, d)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`"team secret"`), got)
+
+	contacts, err := ms.es.GetContacts(ctx, src.share.UID)
+	assert.NoError(t, err)
+	assert.Len(t, contacts, 1)
+	events, err := ms.es.GetEvents(ctx, src.share.Owner)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	token, err := ms.ts.Authenticate(ctx, src.token)
+	assert.NoError(t, err)
+	assert.Equal(t, src.share.Owner, token.UID)
+
+	ids, err := ms.ids.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []oidc.Identity{src.identity}, ids.Identities)
+}
+
+func TestService_Restore_SQLite(t *testing.T) {
+	ctx := context.Background()
+	s, src := initSource(t)
+	var buf bytes.Buffer
+	if _, err := s.Backup(ctx, &buf, passphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := storage.New(ctx, storage.Config{URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "keeper.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	target, ds := initTarget(t, st.DB())
+	_, err = target.Restore(ctx, &buf, passphrase, false)
+	assert.NoError(t, err)
+
+	// The items are readable by their owners and the users they are shared with, as on the source server.
+	for _, uid := range []string{src.share.Owner, src.share.UID} {
+		d, gErr := ds.GetDataByID(ctx, uid, src.share.ItemID)
+		assert.NoError(t, gErr)
+		got, dErr := ds.DecryptSecureData(uid, d)
+		assert.NoError(t, dErr)
+		assert.Equal(t, []byte(`"secret"`), got)
+	}
+}
+
+func initMS(t *testing.T, db *sql.DB) testMS {
+	t.Helper()
+	var (
+		ms  testMS
+		err error
+	)
+	if ms.ds, err = data.NewService(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ms.us, err = user.NewService(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ms.orgs, err = org.NewService(db, nil, ms.ds); err != nil {
+		t.Fatal(err)
+	}
+	if ms.es, err = emergency.NewService(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ms.ts, err = apitoken.NewService(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ms.ids, err = oidc.NewIdentityService(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	return ms
+}
+
+func (ms testMS) service(uow storage.UnitOfWork) Service {
+	return NewService(uow, ms.ds, ms.us, ms.orgs, ms.es, ms.ts, ms.ids)
+}
+
+func initTarget(t *testing.T, db *sql.DB) (Service, data.Service) {
+	t.Helper()
+	ms := initMS(t, db)
+	return ms.service(storage.NewUnitOfWork(db)), ms.ds
+}
+
+// initSource fills the repositories with the users, one of them owning the key pair and the item shared with the other.
+// The owner adds the other user to the organization vault, designates them as the emergency contact,
+// creates the API token, and links the provider identity.
+func initSource(t *testing.T) (Service, testSource) {
+	t.Helper()
+	ctx := context.Background()
+	ms := initMS(t, nil)
+	ds, us := ms.ds, ms.us
+
+	for _, name := range []string{"owner", "recipient"} {
+		if err := us.AddUser(ctx, user.User{Name: name, Password: "test"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owner, err := us.GetUserByName(ctx, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := us.GetUserByName(ctx, "recipient")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, priv, err := enc.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv, err = enc.EncryptPrivateKey("master", priv); err != nil {
+		t.Fatal(err)
+	}
+	if err = us.SetKeyPair(ctx, user.KeyPair{UID: owner.ID, PublicKey: pub, PrivateKey: priv}); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := ds.StoreSecureDataFromPayload(ctx, owner.ID, "secret", data.SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ds.ShareData(ctx, owner.ID, id, recipient.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	src := testSource{share: data.Share{ItemID: id, Owner: owner.ID, UID: recipient.ID}}
+
+	orgID, err := ms.orgs.CreateOrganization(ctx, owner.ID, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ms.orgs.SetMember(ctx, owner.ID, orgID, recipient.ID, org.RoleMember); err != nil {
+		t.Fatal(err)
+	}
+	if src.collection, err = ms.orgs.CreateCollection(ctx, owner.ID, orgID, "infra"); err != nil {
+		t.Fatal(err)
+	}
+	if src.item, err = ds.StoreSecureDataFromPayload(ctx, src.collection, "team secret", data.SText); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ms.es.AddContact(ctx, owner.ID, recipient.ID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	src.token, _, err = ms.ts.CreateToken(ctx, owner.ID, "ci", apitoken.Scope{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.identity = oidc.Identity{Issuer: "https://idp.example.com", Subject: "owner", UID: owner.ID}
+	if err = ms.ids.Import(ctx, oidc.Dump{Identities: []oidc.Identity{src.identity}}); err != nil {
+		t.Fatal(err)
+	}
+	return ms.service(storage.UnitOfWork{}), src
+}
@@ -1,5 +1,7 @@
 package data
 
+import "time"
+
 type StorageType int
 
 const (
@@ -7,11 +9,54 @@ const (
 	SCard
 	SPassword
 	SText
+	SCustom
+	STemplate
+	SSSHKey
+	SNote
+	SIdentity
+	SBank
 )
 
+// SecureData is the encrypted item.
+// The Key is the item key wrapped for the user requesting the item,
+// so it differs for the owner and the users the item is shared with.
 type SecureData struct {
-	UID  string      `json:"-"`
-	ID   string      `json:"id"`
-	Data []byte      `json:"data"`
-	Type StorageType `json:"-"`
+	UID      string      `json:"-"`
+	ID       string      `json:"id"`
+	Data     []byte      `json:"data"`
+	Type     StorageType `json:"-"`
+	Key      []byte      `json:"-"`
+	Shared   bool        `json:"shared"`
+	ReadOnly bool        `json:"read_only"`
+}
+
+// Share is the access to the item granted by its owner to another user.
+type Share struct {
+	ItemID    string    `json:"item_id"`
+	Owner     string    `json:"-"`
+	UID       string    `json:"uid"`
+	Key       []byte    `json:"-"`
+	ReadOnly  bool      `json:"read_only"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SealedShare is the copy of the item encrypted by the owner's client for the recipient.
+// The item key is sealed to the recipient's public key, so the server can't decrypt the shared data.
+type SealedShare struct {
+	ID        string      `json:"id"`
+	ItemID    string      `json:"item_id"`
+	Owner     string      `json:"-"`
+	UID       string      `json:"-"`
+	Type      StorageType `json:"-"`
+	Data      []byte      `json:"data"`
+	Key       []byte      `json:"key"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Dump is the complete content of the repository, moved between the repositories with the IDs preserved.
+// The item keys stay wrapped for their users, so the dump is only readable by the server holding the same secret.
+type Dump struct {
+	Data   []SecureData
+	Shares []Share
+	Sealed []SealedShare
 }
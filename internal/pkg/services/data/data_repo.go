@@ -1,19 +1,33 @@
 package data
 
 import (
+	"database/sql"
 	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 var (
-	ErrDBMissingURL = errors.New("data db url is missing")
-	ErrNotFound     = errors.New("data not found")
-	ErrEmpty        = errors.New("data is missing or empty")
-	ErrMissingArgs  = errors.New("user id or data type is not specified")
+	ErrDBMissing   = errors.New("data db is missing")
+	ErrNotFound    = errors.New("data not found")
+	ErrEmpty       = errors.New("data is missing or empty")
+	ErrExists      = errors.New("data with specified id already exists")
+	ErrMissingArgs = errors.New("user id or data type is not specified")
+	ErrReadOnly    = errors.New("data is shared as read-only")
+	ErrShareSelf   = errors.New("data can't be shared with its owner")
 )
 
-func NewRepo(repoURL string) (IRepository, error) {
-	if repoURL == "" {
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
 		return NewBasicRepo(), nil
 	}
-	return NewDBRepo(repoURL)
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
 }
@@ -2,33 +2,120 @@ package data
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
+// BasicRepo keeps the items keyed by their IDs, so every map holds the plain records only.
 type BasicRepo struct {
-	data *sync.Map
+	data   *sync.Map
+	shares *sync.Map
+	sealed *sync.Map
 }
 
-type Storage struct {
-	user *sync.Map
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{data: &sync.Map{}, shares: &sync.Map{}, sealed: &sync.Map{}}
 }
 
-func NewBasicRepo() *BasicRepo {
-	return &BasicRepo{data: &sync.Map{}}
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[SecureData](w, "data", r.data); err != nil {
+		return nil, err
+	}
+	if err := storage.Track[Share](w, "shares", r.shares); err != nil {
+		return nil, err
+	}
+	if err := storage.Track[SealedShare](w, "sealed", r.sealed); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteData(ctx context.Context, uid, id string) error {
+	if _, ok := r.getOwnedData(uid, id); !ok {
+		return ErrNotFound
+	}
+
+	if _, err := storage.Delete(ctx, r.data, id); err != nil {
+		return err
+	}
+	if err := r.deleteShares(ctx, func(s Share) bool { return s.ItemID == id }); err != nil {
+		return err
+	}
+	return r.deleteSealedShares(ctx, func(s SealedShare) bool { return s.ItemID == id })
 }
 
-func (r *BasicRepo) DeleteData(_ context.Context, uid, id string) error {
-	if us, ok := r.data.Load(uid); ok {
-		if _, ok = us.(Storage).user.Load(id); ok {
-			us.(Storage).user.Delete(id)
-			return nil
+func (r *BasicRepo) DeleteAllData(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	var err error
+	r.data.Range(func(k, v any) bool {
+		if v.(SecureData).UID == uid {
+			_, err = storage.Delete(ctx, r.data, k)
 		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.deleteShares(ctx, func(s Share) bool { return s.Owner == uid || s.UID == uid }); err != nil {
+		return err
+	}
+	return r.deleteSealedShares(ctx, func(s SealedShare) bool { return s.Owner == uid || s.UID == uid })
+}
+
+func (r *BasicRepo) DeleteSealedShare(ctx context.Context, uid, id string) error {
+	if s, ok := r.sealed.Load(id); ok && (s.(SealedShare).Owner == uid || s.(SealedShare).UID == uid) {
+		_, err := storage.Delete(ctx, r.sealed, id)
+		return err
+	}
+	return ErrNotFound
+}
+
+func (r *BasicRepo) DeleteShare(ctx context.Context, owner, id, uid string) error {
+	k := shareKey(id, uid)
+	if s, ok := r.shares.Load(k); ok && s.(Share).Owner == owner {
+		_, err := storage.Delete(ctx, r.shares, k)
+		return err
 	}
 	return ErrNotFound
 }
 
+// Export returns the items with the keys wrapped for their owners, and all shares of them.
+func (r *BasicRepo) Export(_ context.Context) (Dump, error) {
+	var dump Dump
+	r.data.Range(func(_, v any) bool {
+		dump.Data = append(dump.Data, v.(SecureData))
+		return true
+	})
+	r.shares.Range(func(_, v any) bool {
+		dump.Shares = append(dump.Shares, v.(Share))
+		return true
+	})
+	r.sealed.Range(func(_, v any) bool {
+		dump.Sealed = append(dump.Sealed, v.(SealedShare))
+		return true
+	})
+
+	sort.Slice(dump.Data, func(i, j int) bool { return dump.Data[i].ID < dump.Data[j].ID })
+	sort.Slice(dump.Shares, func(i, j int) bool {
+		return shareKey(dump.Shares[i].ItemID, dump.Shares[i].UID) < shareKey(dump.Shares[j].ItemID, dump.Shares[j].UID)
+	})
+	sort.Slice(dump.Sealed, func(i, j int) bool { return dump.Sealed[i].ID < dump.Sealed[j].ID })
+	return dump, nil
+}
+
 func (r *BasicRepo) GetAllDataByType(_ context.Context, uid string,
 	t StorageType,
 ) ([]SecureData, error) {
@@ -37,48 +124,232 @@ func (r *BasicRepo) GetAllDataByType(_ context.Context, uid string,
 	}
 
 	var data []SecureData
-	if us, ok := r.data.Load(uid); ok {
-		us.(Storage).user.Range(func(_, v any) bool {
-			d := v.(SecureData)
-			if d.Type == t {
-				data = append(data, d)
-			}
+	r.data.Range(func(_, v any) bool {
+		if d := v.(SecureData); d.UID == uid && d.Type == t {
+			data = append(data, d)
+		}
+		return true
+	})
+
+	r.shares.Range(func(_, v any) bool {
+		s := v.(Share)
+		if s.UID != uid {
 			return true
-		})
-	}
+		}
+		if d, ok := r.getSharedData(s); ok && d.Type == t {
+			data = append(data, d)
+		}
+		return true
+	})
 	return data, nil
 }
 
 func (r *BasicRepo) GetDataByID(_ context.Context, uid, id string) (SecureData, error) {
-	var (
-		us any
-		d  any
-		ok bool
-	)
+	if d, ok := r.getOwnedData(uid, id); ok {
+		return d, nil
+	}
 
-	if us, ok = r.data.Load(uid); ok {
-		if d, ok = us.(Storage).user.Load(id); ok {
-			return d.(SecureData), nil
+	if s, ok := r.shares.Load(shareKey(id, uid)); ok {
+		if d, dOk := r.getSharedData(s.(Share)); dOk {
+			return d, nil
 		}
 	}
 	return SecureData{}, ErrNotFound
 }
 
-func (r *BasicRepo) StoreData(_ context.Context, data SecureData) (string, error) {
+func (r *BasicRepo) GetSealedShares(_ context.Context, uid string) ([]SealedShare, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	shares := make([]SealedShare, 0)
+	r.sealed.Range(func(_, v any) bool {
+		if s := v.(SealedShare); s.UID == uid {
+			shares = append(shares, s)
+		}
+		return true
+	})
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+	return shares, nil
+}
+
+func (r *BasicRepo) GetShares(_ context.Context, owner, id string) ([]Share, error) {
+	if owner == "" || id == "" {
+		return nil, ErrMissingArgs
+	}
+
+	var shares []Share
+	r.shares.Range(func(_, v any) bool {
+		if s := v.(Share); s.Owner == owner && s.ItemID == id {
+			shares = append(shares, s)
+		}
+		return true
+	})
+	return shares, nil
+}
+
+// Import stores the items before their shares, so the shares of the unknown items are rejected.
+// The changes are undone on failure within the unit of work only.
+func (r *BasicRepo) Import(ctx context.Context, dump Dump) error {
+	for _, d := range dump.Data {
+		if d.ID == "" || d.UID == "" || d.Data == nil {
+			return ErrEmpty
+		}
+		if _, ok := r.data.Load(d.ID); ok {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.data, d.ID, d); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range dump.Shares {
+		k := shareKey(s.ItemID, s.UID)
+		if _, ok := r.shares.Load(k); ok {
+			return ErrExists
+		}
+		if _, ok := r.getOwnedData(s.Owner, s.ItemID); !ok {
+			return ErrNotFound
+		}
+		if err := storage.Store(ctx, r.shares, k, s); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range dump.Sealed {
+		if s.ID == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.sealed.Load(s.ID); ok {
+			return ErrExists
+		}
+		d, ok := r.getOwnedData(s.Owner, s.ItemID)
+		if !ok {
+			return ErrNotFound
+		}
+		s.Type = d.Type
+		if err := storage.Store(ctx, r.sealed, s.ID, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BasicRepo) StoreData(ctx context.Context, data SecureData) (string, error) {
 	if data.Data == nil || data.UID == "" {
 		return "", ErrEmpty
 	}
 
-	id := uuid.NewString()
-	data.ID = id
+	data.ID = uuid.NewString()
+	if err := storage.Store(ctx, r.data, data.ID, data); err != nil {
+		return "", err
+	}
+	return data.ID, nil
+}
+
+func (r *BasicRepo) StoreSealedShare(ctx context.Context, share SealedShare) (string, error) {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" || share.Data == nil || share.Key == nil {
+		return "", ErrMissingArgs
+	}
+
+	d, ok := r.getOwnedData(share.Owner, share.ItemID)
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	share.ID = uuid.NewString()
+	share.Type = d.Type
+	share.CreatedAt = time.Now().UTC()
+	err := r.deleteSealedShares(ctx, func(s SealedShare) bool {
+		return s.ItemID == share.ItemID && s.UID == share.UID
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = storage.Store(ctx, r.sealed, share.ID, share); err != nil {
+		return "", err
+	}
+	return share.ID, nil
+}
 
-	if us, ok := r.data.Load(data.UID); !ok {
-		sd := &sync.Map{}
-		sd.Store(id, data)
-		r.data.Store(data.UID, Storage{user: sd})
+func (r *BasicRepo) StoreShare(ctx context.Context, share Share) error {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" || share.Key == nil {
+		return ErrMissingArgs
+	}
+	if _, ok := r.getOwnedData(share.Owner, share.ItemID); !ok {
+		return ErrNotFound
+	}
+
+	k := shareKey(share.ItemID, share.UID)
+	if s, ok := r.shares.Load(k); ok {
+		share.CreatedAt = s.(Share).CreatedAt
 	} else {
-		us.(Storage).user.Store(id, data)
+		share.CreatedAt = time.Now().UTC()
+	}
+	return storage.Store(ctx, r.shares, k, share)
+}
+
+func (r *BasicRepo) UpdateData(ctx context.Context, data SecureData) error {
+	if data.Data == nil || data.UID == "" {
+		return ErrEmpty
+	}
+
+	d, ok := r.getOwnedData(data.UID, data.ID)
+	if !ok {
+		return ErrNotFound
+	}
+
+	d.Data = data.Data
+	if data.Key != nil {
+		d.Key = data.Key
+	}
+	return storage.Store(ctx, r.data, d.ID, d)
+}
+
+func (r *BasicRepo) getOwnedData(uid, id string) (SecureData, bool) {
+	if d, ok := r.data.Load(id); ok && d.(SecureData).UID == uid {
+		return d.(SecureData), true
+	}
+	return SecureData{}, false
+}
+
+func (r *BasicRepo) getSharedData(s Share) (SecureData, bool) {
+	d, ok := r.getOwnedData(s.Owner, s.ItemID)
+	if !ok {
+		return SecureData{}, false
 	}
 
-	return id, nil
+	d.Key = s.Key
+	d.Shared = true
+	d.ReadOnly = s.ReadOnly
+	return d, true
+}
+
+func (r *BasicRepo) deleteShares(ctx context.Context, match func(s Share) bool) error {
+	var err error
+	r.shares.Range(func(k, v any) bool {
+		if match(v.(Share)) {
+			_, err = storage.Delete(ctx, r.shares, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (r *BasicRepo) deleteSealedShares(ctx context.Context, match func(s SealedShare) bool) error {
+	var err error
+	r.sealed.Range(func(k, v any) bool {
+		if match(v.(SealedShare)) {
+			_, err = storage.Delete(ctx, r.sealed, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func shareKey(id, uid string) string {
+	return id + "|" + uid
 }
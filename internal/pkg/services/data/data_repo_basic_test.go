@@ -6,49 +6,52 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
+func TestBasicRepo_DeleteAllData(t *testing.T) {
+	testDeleteAllData(t, initTestBasicRepo)
+}
+
 func TestBasicRepo_DeleteData(t *testing.T) {
-	for _, tt := range getDeleteDataCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			err := r.DeleteData(context.Background(), tt.args.uid, tt.args.id)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testDeleteData(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_DeleteShare(t *testing.T) {
+	testDeleteShare(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_ExportImport(t *testing.T) {
+	testExportImport(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_GetAllDataByType(t *testing.T) {
-	for _, tt := range getGetAllDataByTypeCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.GetAllDataByType(context.Background(), tt.args.uid, tt.args.t)
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testGetAllDataByType(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_GetDataByID(t *testing.T) {
-	for _, tt := range getGetDataByIDCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.GetDataByID(context.Background(), tt.args.uid, tt.args.id)
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testGetDataByID(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_GetShares(t *testing.T) {
+	testGetShares(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_StoreData(t *testing.T) {
-	for _, tt := range getStoreDataCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.StoreData(context.Background(), tt.data)
-			assert.Equal(t, tt.wantLen, len(got))
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testStoreData(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_StoreShare(t *testing.T) {
+	testStoreShare(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_StoreSealedShare(t *testing.T) {
+	testStoreSealedShare(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_UpdateData(t *testing.T) {
+	testUpdateData(t, initTestBasicRepo)
 }
 
 func TestNewBasicRepo(t *testing.T) {
@@ -77,3 +80,43 @@ func TestNewBasicRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	r := initPersistentRepo(t, dir)
+	id, err := r.StoreData(ctx, SecureData{UID: "testUser", Data: []byte("data"), Type: SText, Key: []byte("key")})
+	assert.NoError(t, err)
+	assert.NoError(t, r.StoreShare(ctx, Share{ItemID: id, Owner: "testUser", UID: "testUser1", Key: []byte("key1")}))
+	_, err = r.StoreSealedShare(ctx, SealedShare{
+		ItemID: id, Owner: "testUser", UID: "testUser2", Data: []byte("sealed"), Key: []byte("key2"),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, r.UpdateData(ctx, SecureData{ID: id, UID: "testUser", Data: []byte("updated")}))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetDataByID(ctx, "testUser", id)
+	assert.NoError(t, err)
+	assert.Equal(t, SecureData{UID: "testUser", ID: id, Data: []byte("updated"), Type: SText, Key: []byte("key")}, got)
+	got, err = r.GetDataByID(ctx, "testUser1", id)
+	assert.NoError(t, err)
+	assert.True(t, got.Shared)
+	sealed, err := r.GetSealedShares(ctx, "testUser2")
+	assert.NoError(t, err)
+	assert.Len(t, sealed, 1)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "data", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
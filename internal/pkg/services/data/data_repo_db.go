@@ -4,9 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib" // SQL driver
+	"github.com/jackc/pgx/v5/pgconn"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
 )
 
 type DBRepo struct {
@@ -14,36 +22,73 @@ type DBRepo struct {
 }
 
 const (
-	CreateStorageTable = `CREATE TABLE IF NOT EXISTS storage(
-    	id UUID DEFAULT gen_random_uuid(),
-    	uid UUID,
-    	data BYTEA,
-    	type INT,
-    	PRIMARY KEY(id),
-		CONSTRAINT fk_user
-		    FOREIGN KEY (uid)
-		        REFERENCES users(id)
-                    ON DELETE CASCADE )`
-	DeleteData       = "DELETE FROM storage WHERE uid = $1 AND id = $2"
-	GetAllDataByType = "SELECT * FROM storage WHERE uid = $1 AND type = $2"
-	GetDataByID      = "SELECT * FROM storage WHERE uid = $1 AND id = $2"
-	StoreData        = `
-		INSERT INTO storage(uid, data, type) VALUES($1, $2, $3) ON CONFLICT DO NOTHING RETURNING id
+	DeleteAllData     = "DELETE FROM storage WHERE uid = $1"
+	DeleteSealedShare = "DELETE FROM sealed_shares WHERE id = $2 AND (owner = $1 OR uid = $1)"
+	DeleteData        = "DELETE FROM storage WHERE uid = $1 AND id = $2"
+	DeleteShare       = "DELETE FROM shares WHERE owner = $1 AND item_id = $2 AND uid = $3"
+	ExportData        = "SELECT id, uid, data, type, key FROM storage ORDER BY id"
+	ExportSealed      = `
+		SELECT id, item_id, owner, uid, type, data, key, created_at FROM sealed_shares ORDER BY id
+	`
+	ExportShares = `
+		SELECT item_id, owner, uid, key, read_only, created_at FROM shares ORDER BY item_id, uid
+	`
+	GetAllDataByType = `
+		SELECT id, uid, data, type, key, false, false FROM storage WHERE uid = $1 AND type = $2
+		UNION ALL
+		SELECT s.id, s.uid, s.data, s.type, sh.key, true, sh.read_only FROM storage s
+		JOIN shares sh ON sh.item_id = s.id WHERE sh.uid = $1 AND s.type = $2
+	`
+	GetDataByID = `
+		SELECT id, uid, data, type, key, false, false FROM storage WHERE uid = $1 AND id = $2
+		UNION ALL
+		SELECT s.id, s.uid, s.data, s.type, sh.key, true, sh.read_only FROM storage s
+		JOIN shares sh ON sh.item_id = s.id WHERE sh.uid = $1 AND s.id = $2
+	`
+	GetSealedShares = `
+		SELECT id, item_id, owner, uid, type, data, key, created_at FROM sealed_shares
+		WHERE uid = $1 ORDER BY created_at
+	`
+	GetShares = `
+		SELECT item_id, owner, uid, key, read_only, created_at FROM shares
+		WHERE owner = $1 AND item_id = $2 ORDER BY created_at
+	`
+	ImportData   = "INSERT INTO storage(id, uid, data, type, key) VALUES ($1, $2, $3, $4, $5)"
+	ImportSealed = `
+		INSERT INTO sealed_shares(id, item_id, owner, uid, type, data, key, created_at)
+		SELECT $3, id, uid, $4, type, $5, $6, $7 FROM storage WHERE uid = $1 AND id = $2
+	`
+	ImportShare = `
+		INSERT INTO shares(item_id, owner, uid, key, read_only, created_at)
+		SELECT id, uid, $3, $4, $5, $6 FROM storage WHERE uid = $1 AND id = $2
 	`
+	StoreData = `
+		INSERT INTO storage(uid, data, type, key) VALUES($1, $2, $3, $4) ON CONFLICT DO NOTHING RETURNING id
+	`
+	StoreSealedShare = `
+		INSERT INTO sealed_shares(item_id, owner, uid, type, data, key, created_at)
+		SELECT id, uid, $3, type, $4, $5, $6 FROM storage WHERE uid = $1 AND id = $2
+		ON CONFLICT (item_id, uid) DO UPDATE SET data = EXCLUDED.data, key = EXCLUDED.key,
+			created_at = EXCLUDED.created_at
+		RETURNING id
+	`
+	StoreShare = `
+		INSERT INTO shares(item_id, owner, uid, key, read_only, created_at)
+		SELECT id, uid, $3, $4, $5, $6 FROM storage WHERE uid = $1 AND id = $2
+		ON CONFLICT (item_id, uid) DO UPDATE SET key = EXCLUDED.key, read_only = EXCLUDED.read_only
+	`
+	UpdateData = "UPDATE storage SET data = $3, key = COALESCE($4, key) WHERE uid = $1 AND id = $2"
 )
 
-func NewDBRepo(url string) (*DBRepo, error) {
-	if url == "" {
-		return &DBRepo{}, ErrDBMissingURL
-	}
-
-	db, err := sql.Open("pgx", url)
-	if err != nil {
-		return &DBRepo{}, err
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
 	}
+	return &DBRepo{db: db}, nil
+}
 
-	_, err = db.ExecContext(context.Background(), CreateStorageTable)
-	return &DBRepo{db: db}, err
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
 }
 
 func (r *DBRepo) DeleteData(ctx context.Context, uid, id string) error {
@@ -51,20 +96,62 @@ func (r *DBRepo) DeleteData(ctx context.Context, uid, id string) error {
 		return ErrNotFound
 	}
 
-	res, err := r.db.ExecContext(ctx, DeleteData, uid, id)
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteData, uid, id)
 	if err != nil {
 		return err
 	}
+	return r.checkAffected(res)
+}
 
-	ra, err := res.RowsAffected()
+func (r *DBRepo) DeleteAllData(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteAllData, uid)
+	return err
+}
+
+func (r *DBRepo) DeleteShare(ctx context.Context, owner, id, uid string) error {
+	if owner == "" || id == "" || uid == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteShare, owner, id, uid)
 	if err != nil {
 		return err
 	}
-	if ra == 0 {
+	return r.checkAffected(res)
+}
+
+func (r *DBRepo) DeleteSealedShare(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
 		return ErrNotFound
 	}
 
-	return nil
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteSealedShare, uid, id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+// Export returns the items with the keys wrapped for their owners, and all shares of them.
+func (r *DBRepo) Export(ctx context.Context) (Dump, error) {
+	var (
+		dump Dump
+		err  error
+	)
+	if dump.Data, err = r.exportData(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Shares, err = r.exportShares(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Sealed, err = r.exportSealed(ctx); err != nil {
+		return Dump{}, err
+	}
+	return dump, nil
 }
 
 func (r *DBRepo) GetAllDataByType(ctx context.Context, uid string,
@@ -74,7 +161,7 @@ func (r *DBRepo) GetAllDataByType(ctx context.Context, uid string,
 		return nil, ErrMissingArgs
 	}
 
-	rows, err := r.db.QueryContext(ctx, GetAllDataByType, uid, t)
+	rows, err := r.conn(ctx).QueryContext(ctx, GetAllDataByType, uid, t)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -89,7 +176,8 @@ func (r *DBRepo) GetAllDataByType(ctx context.Context, uid string,
 	var data []SecureData
 	for rows.Next() {
 		var piece SecureData
-		if err = rows.Scan(&piece.ID, &piece.UID, &piece.Data, &piece.Type); err != nil {
+		if err = rows.Scan(&piece.ID, &piece.UID, &piece.Data, &piece.Type, &piece.Key, &piece.Shared,
+			&piece.ReadOnly); err != nil {
 			return nil, err
 		}
 		data = append(data, piece)
@@ -103,25 +191,231 @@ func (r *DBRepo) GetDataByID(ctx context.Context, uid, id string) (SecureData, e
 	}
 
 	var data SecureData
-	err := r.db.QueryRowContext(ctx, GetDataByID, uid, id).Scan(&data.ID, &data.UID, &data.Data, &data.Type)
+	err := r.conn(ctx).QueryRowContext(ctx, GetDataByID, uid, id).
+		Scan(&data.ID, &data.UID, &data.Data, &data.Type, &data.Key, &data.Shared, &data.ReadOnly)
 	if errors.Is(err, sql.ErrNoRows) {
 		return SecureData{}, ErrNotFound
 	}
 	return data, err
 }
 
+func (r *DBRepo) GetSealedShares(ctx context.Context, uid string) ([]SealedShare, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetSealedShares, uid)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	shares := make([]SealedShare, 0)
+	for rows.Next() {
+		var s SealedShare
+		if err = rows.Scan(&s.ID, &s.ItemID, &s.Owner, &s.UID, &s.Type, &s.Data, &s.Key, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+func (r *DBRepo) GetShares(ctx context.Context, owner, id string) ([]Share, error) {
+	if owner == "" || id == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetShares, owner, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var shares []Share
+	for rows.Next() {
+		var s Share
+		if err = rows.Scan(&s.ItemID, &s.Owner, &s.UID, &s.Key, &s.ReadOnly, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+// Import stores the items before their shares, so the shares of the unknown items are rejected.
+func (r *DBRepo) Import(ctx context.Context, dump Dump) error {
+	for _, d := range dump.Data {
+		if d.ID == "" || d.UID == "" || d.Data == nil {
+			return ErrEmpty
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportData, d.ID, d.UID, d.Data, d.Type, d.Key); err != nil {
+			return mapPgError(err)
+		}
+	}
+	for _, s := range dump.Shares {
+		res, err := r.conn(ctx).ExecContext(ctx, ImportShare, s.Owner, s.ItemID, s.UID, s.Key, s.ReadOnly, s.CreatedAt)
+		if err != nil {
+			return mapPgError(err)
+		}
+		if err = r.checkAffected(res); err != nil {
+			return err
+		}
+	}
+	for _, s := range dump.Sealed {
+		res, err := r.conn(ctx).ExecContext(ctx, ImportSealed, s.Owner, s.ItemID, s.ID, s.UID, s.Data, s.Key, s.CreatedAt)
+		if err != nil {
+			return mapPgError(err)
+		}
+		if err = r.checkAffected(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *DBRepo) StoreData(ctx context.Context, data SecureData) (string, error) {
 	if data.Data == nil || data.UID == "" {
 		return "", ErrEmpty
 	}
 
 	var id string
-	err := r.db.QueryRowContext(ctx, StoreData, data.UID, data.Data, data.Type).Scan(&id)
+	err := r.conn(ctx).QueryRowContext(ctx, StoreData, data.UID, data.Data, data.Type, data.Key).Scan(&id)
 	return id, err
 }
 
+func (r *DBRepo) StoreSealedShare(ctx context.Context, share SealedShare) (string, error) {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" || share.Data == nil || share.Key == nil {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, StoreSealedShare, share.Owner, share.ItemID, share.UID, share.Data, share.Key,
+		time.Now().UTC()).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *DBRepo) StoreShare(ctx context.Context, share Share) error {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" || share.Key == nil {
+		return ErrMissingArgs
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, StoreShare, share.Owner, share.ItemID, share.UID, share.Key, share.ReadOnly,
+		time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+func (r *DBRepo) UpdateData(ctx context.Context, data SecureData) error {
+	if data.Data == nil || data.UID == "" {
+		return ErrEmpty
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, UpdateData, data.UID, data.ID, data.Data, data.Key)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+func (r *DBRepo) exportData(ctx context.Context) ([]SecureData, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportData)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var data []SecureData
+	for rows.Next() {
+		var d SecureData
+		if err = rows.Scan(&d.ID, &d.UID, &d.Data, &d.Type, &d.Key); err != nil {
+			return nil, err
+		}
+		data = append(data, d)
+	}
+	return data, rows.Err()
+}
+
+func (r *DBRepo) exportShares(ctx context.Context) ([]Share, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportShares)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var shares []Share
+	for rows.Next() {
+		var s Share
+		if err = rows.Scan(&s.ItemID, &s.Owner, &s.UID, &s.Key, &s.ReadOnly, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+func (r *DBRepo) exportSealed(ctx context.Context) ([]SealedShare, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportSealed)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var shares []SealedShare
+	for rows.Next() {
+		var s SealedShare
+		if err = rows.Scan(&s.ID, &s.ItemID, &s.Owner, &s.UID, &s.Type, &s.Data, &s.Key, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+func (r *DBRepo) checkAffected(res sql.Result) error {
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (r *DBRepo) closeRows(rows *sql.Rows) {
 	if err := rows.Close(); err != nil {
 		log.Error(err)
 	}
 }
+
+// mapPgError reports the duplicate and the recipient missing from the users as the repository errors.
+func mapPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return ErrExists
+		case foreignKeyViolation:
+			return ErrNotFound
+		}
+	}
+	return err
+}
@@ -11,6 +11,32 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestDBRepo_DeleteAllData(t *testing.T) {
+	for _, tt := range getDeleteAllDataCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				var rows int64
+				for _, v := range tt.repo {
+					if v.UID == tt.uid {
+						rows++
+					}
+				}
+				mock.ExpectExec(regexp.QuoteMeta(DeleteAllData)).WithArgs(tt.uid).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.DeleteAllData(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestDBRepo_DeleteData(t *testing.T) {
 	for _, tt := range getDeleteDataCases() {
 		t.Run(tt.name, func(t *testing.T) {
@@ -36,6 +62,32 @@ func TestDBRepo_DeleteData(t *testing.T) {
 	}
 }
 
+func TestDBRepo_DeleteShare(t *testing.T) {
+	for _, tt := range getDeleteShareCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.args.owner != "" && tt.args.id != "" && tt.args.uid != "" {
+				var rows int64
+				for _, sh := range tt.shares {
+					if sh.Owner == tt.args.owner && sh.ItemID == tt.args.id && sh.UID == tt.args.uid {
+						rows++
+					}
+				}
+				mock.ExpectExec(regexp.QuoteMeta(DeleteShare)).WithArgs(tt.args.owner, tt.args.id, tt.args.uid).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.DeleteShare(context.Background(), tt.args.owner, tt.args.id, tt.args.uid)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestDBRepo_GetAllDataByType(t *testing.T) {
 	for _, tt := range getGetAllDataByTypeCases() {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,11 +98,17 @@ func TestDBRepo_GetAllDataByType(t *testing.T) {
 
 			if tt.args.uid != "" {
 				eq := mock.ExpectQuery(regexp.QuoteMeta(GetAllDataByType)).WithArgs(tt.args.uid, tt.args.t)
-				rows := mock.NewRows([]string{"id", "uid", "data", "type"})
+				rows := mock.NewRows([]string{"id", "uid", "data", "type", "key", "shared", "read_only"})
 				var rowsLen int
 				for _, v := range tt.repo {
 					if v.UID == tt.args.uid && v.Type == tt.args.t {
-						rows.AddRow(v.ID, v.UID, v.Data, v.Type)
+						rows.AddRow(v.ID, v.UID, v.Data, v.Type, v.Key, v.Shared, v.ReadOnly)
+						rowsLen++
+					}
+				}
+				for _, sh := range tt.shares {
+					if v := tt.repo[sh.ItemID]; sh.UID == tt.args.uid && v.Type == tt.args.t {
+						rows.AddRow(v.ID, v.UID, v.Data, v.Type, sh.Key, true, sh.ReadOnly)
 						rowsLen++
 					}
 				}
@@ -80,11 +138,17 @@ func TestDBRepo_GetDataByID(t *testing.T) {
 
 			if tt.args.uid != "" && tt.args.id != "" {
 				eq := mock.ExpectQuery(regexp.QuoteMeta(GetDataByID)).WithArgs(tt.args.uid, tt.args.id)
-				rows := mock.NewRows([]string{"id", "uid", "data", "type"})
+				rows := mock.NewRows([]string{"id", "uid", "data", "type", "key", "shared", "read_only"})
 				var rowsLen int
 				for _, v := range tt.repo {
 					if v.UID == tt.args.uid && v.ID == tt.args.id {
-						rows.AddRow(v.ID, v.UID, v.Data, v.Type)
+						rows.AddRow(v.ID, v.UID, v.Data, v.Type, v.Key, v.Shared, v.ReadOnly)
+						rowsLen++
+					}
+				}
+				for _, sh := range tt.shares {
+					if v := tt.repo[sh.ItemID]; sh.UID == tt.args.uid && sh.ItemID == tt.args.id {
+						rows.AddRow(v.ID, v.UID, v.Data, v.Type, sh.Key, true, sh.ReadOnly)
 						rowsLen++
 					}
 				}
@@ -104,6 +168,33 @@ func TestDBRepo_GetDataByID(t *testing.T) {
 	}
 }
 
+func TestDBRepo_GetShares(t *testing.T) {
+	for _, tt := range getGetSharesCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.args.owner != "" && tt.args.id != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(GetShares)).WithArgs(tt.args.owner, tt.args.id)
+				rows := mock.NewRows([]string{"item_id", "owner", "uid", "key", "read_only", "created_at"})
+				for _, sh := range tt.shares {
+					if sh.Owner == tt.args.owner && sh.ItemID == tt.args.id {
+						rows.AddRow(sh.ItemID, sh.Owner, sh.UID, sh.Key, sh.ReadOnly, sh.CreatedAt)
+					}
+				}
+				eq.WillReturnRows(rows)
+			}
+
+			got, err := r.GetShares(context.Background(), tt.args.owner, tt.args.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestDBRepo_StoreData(t *testing.T) {
 	for _, tt := range getStoreDataCases() {
 		t.Run(tt.name, func(t *testing.T) {
@@ -113,7 +204,8 @@ func TestDBRepo_StoreData(t *testing.T) {
 			}
 
 			if tt.data.UID != "" && tt.data.Data != nil {
-				eq := mock.ExpectQuery(regexp.QuoteMeta(StoreData)).WithArgs(tt.data.UID, tt.data.Data, tt.data.Type)
+				eq := mock.ExpectQuery(regexp.QuoteMeta(StoreData)).WithArgs(tt.data.UID, tt.data.Data, tt.data.Type,
+					tt.data.Key)
 				rows := mock.NewRows([]string{"id"}).AddRow("123456789012345678901234567890123456")
 				eq.WillReturnRows(rows)
 			}
@@ -126,6 +218,83 @@ func TestDBRepo_StoreData(t *testing.T) {
 	}
 }
 
+func TestDBRepo_StoreShare(t *testing.T) {
+	for _, tt := range getStoreShareCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sh := tt.share
+			if sh.Owner != "" && sh.ItemID != "" && sh.UID != "" && sh.Key != nil {
+				var rows int64
+				if v := tt.repo[sh.ItemID]; v.UID == sh.Owner {
+					rows = 1
+				}
+				mock.ExpectExec(regexp.QuoteMeta(StoreShare)).
+					WithArgs(sh.Owner, sh.ItemID, sh.UID, sh.Key, sh.ReadOnly, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.StoreShare(context.Background(), tt.share)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreSealedShare(t *testing.T) {
+	for _, tt := range getStoreSealedShareCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sh := tt.share
+			if sh.Owner != "" && sh.ItemID != "" && sh.UID != "" && sh.Data != nil && sh.Key != nil {
+				e := mock.ExpectQuery(regexp.QuoteMeta(StoreSealedShare)).
+					WithArgs(sh.Owner, sh.ItemID, sh.UID, sh.Data, sh.Key, sqlmock.AnyArg())
+				if v := tt.repo[sh.ItemID]; v.UID == sh.Owner {
+					e.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("sealedID"))
+				} else {
+					e.WillReturnError(sql.ErrNoRows)
+				}
+			}
+
+			_, err = r.StoreSealedShare(context.Background(), tt.share)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_UpdateData(t *testing.T) {
+	for _, tt := range getUpdateDataCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := tt.data
+			if d.UID != "" && d.Data != nil {
+				var rows int64
+				if v := tt.repo[d.ID]; v.UID == d.UID {
+					rows = 1
+				}
+				mock.ExpectExec(regexp.QuoteMeta(UpdateData)).WithArgs(d.UID, d.ID, d.Data, d.Key).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.UpdateData(context.Background(), tt.data)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestNewDBRepo(t *testing.T) {
 	type want struct {
 		repoType  string
@@ -134,12 +303,12 @@ func TestNewDBRepo(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		url     string
+		db      *sql.DB
 		want    want
 		wantErr bool
 	}{
 		{
-			name:    "Empty repo URL",
+			name:    "DB is missing",
 			wantErr: true,
 			want: want{
 				repoType:  "*data.DBRepo",
@@ -148,19 +317,18 @@ func TestNewDBRepo(t *testing.T) {
 			},
 		},
 		{
-			name: "Wrong Repo URL is present",
-			url:  "postgres://localhost:5432/test",
+			name: "DB is present",
+			db:   initDB(t),
 			want: want{
 				repoType:  "*data.DBRepo",
 				fieldName: "db",
 				fieldType: "*sql.DB",
 			},
-			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewDBRepo(tt.url)
+			got, err := NewDBRepo(tt.db)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rGot := reflect.ValueOf(got)
@@ -0,0 +1,440 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+// The items are owned by the organization collections too, so the owners are not bound to the users table.
+const (
+	createSQLiteStorage = `CREATE TABLE IF NOT EXISTS storage(
+		id TEXT PRIMARY KEY,
+		uid TEXT NOT NULL,
+		data BLOB,
+		type INTEGER NOT NULL,
+		key BLOB)`
+	createSQLiteShares = `CREATE TABLE IF NOT EXISTS shares(
+		item_id TEXT NOT NULL REFERENCES storage(id) ON DELETE CASCADE,
+		owner TEXT NOT NULL,
+		uid TEXT NOT NULL,
+		key BLOB NOT NULL,
+		read_only BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY(item_id, uid))`
+	createSQLiteSealedShares = `CREATE TABLE IF NOT EXISTS sealed_shares(
+		id TEXT PRIMARY KEY,
+		item_id TEXT NOT NULL REFERENCES storage(id) ON DELETE CASCADE,
+		owner TEXT NOT NULL,
+		uid TEXT NOT NULL,
+		type INTEGER NOT NULL,
+		data BLOB NOT NULL,
+		key BLOB NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		UNIQUE(item_id, uid))`
+	sqliteDeleteAllData     = "DELETE FROM storage WHERE uid = ?1"
+	sqliteDeleteAllShares   = "DELETE FROM shares WHERE owner = ?1 OR uid = ?1"
+	sqliteDeleteAllSealed   = "DELETE FROM sealed_shares WHERE owner = ?1 OR uid = ?1"
+	sqliteDeleteSealedShare = "DELETE FROM sealed_shares WHERE id = ?2 AND (owner = ?1 OR uid = ?1)"
+	sqliteDeleteData        = "DELETE FROM storage WHERE uid = ?1 AND id = ?2"
+	sqliteDeleteShare       = "DELETE FROM shares WHERE owner = ?1 AND item_id = ?2 AND uid = ?3"
+	sqliteExportData        = "SELECT id, uid, data, type, key FROM storage ORDER BY id"
+	sqliteExportSealed      = `
+		SELECT id, item_id, owner, uid, type, data, key, created_at FROM sealed_shares ORDER BY id
+	`
+	sqliteExportShares = `
+		SELECT item_id, owner, uid, key, read_only, created_at FROM shares ORDER BY item_id, uid
+	`
+	sqliteGetAllDataByType = `
+		SELECT id, uid, data, type, key, false, false FROM storage WHERE uid = ?1 AND type = ?2
+		UNION ALL
+		SELECT s.id, s.uid, s.data, s.type, sh.key, true, sh.read_only FROM storage s
+		JOIN shares sh ON sh.item_id = s.id WHERE sh.uid = ?1 AND s.type = ?2
+	`
+	sqliteGetDataByID = `
+		SELECT id, uid, data, type, key, false, false FROM storage WHERE uid = ?1 AND id = ?2
+		UNION ALL
+		SELECT s.id, s.uid, s.data, s.type, sh.key, true, sh.read_only FROM storage s
+		JOIN shares sh ON sh.item_id = s.id WHERE sh.uid = ?1 AND s.id = ?2
+	`
+	sqliteGetSealedShares = `
+		SELECT id, item_id, owner, uid, type, data, key, created_at FROM sealed_shares
+		WHERE uid = ?1 ORDER BY created_at
+	`
+	sqliteGetShares = `
+		SELECT item_id, owner, uid, key, read_only, created_at FROM shares
+		WHERE owner = ?1 AND item_id = ?2 ORDER BY created_at
+	`
+	sqliteImportData   = "INSERT INTO storage(id, uid, data, type, key) VALUES (?1, ?2, ?3, ?4, ?5)"
+	sqliteImportSealed = `
+		INSERT INTO sealed_shares(id, item_id, owner, uid, type, data, key, created_at)
+		SELECT ?3, id, uid, ?4, type, ?5, ?6, ?7 FROM storage WHERE uid = ?1 AND id = ?2
+	`
+	sqliteImportShare = `
+		INSERT INTO shares(item_id, owner, uid, key, read_only, created_at)
+		SELECT id, uid, ?3, ?4, ?5, ?6 FROM storage WHERE uid = ?1 AND id = ?2
+	`
+	sqliteStoreData        = "INSERT INTO storage(id, uid, data, type, key) VALUES(?1, ?2, ?3, ?4, ?5)"
+	sqliteStoreSealedShare = `
+		INSERT INTO sealed_shares(id, item_id, owner, uid, type, data, key, created_at)
+		SELECT ?7, id, uid, ?3, type, ?4, ?5, ?6 FROM storage WHERE uid = ?1 AND id = ?2
+		ON CONFLICT (item_id, uid) DO UPDATE SET id = excluded.id, data = excluded.data, key = excluded.key,
+			created_at = excluded.created_at
+		RETURNING id
+	`
+	sqliteStoreShare = `
+		INSERT INTO shares(item_id, owner, uid, key, read_only, created_at)
+		SELECT id, uid, ?3, ?4, ?5, ?6 FROM storage WHERE uid = ?1 AND id = ?2
+		ON CONFLICT (item_id, uid) DO UPDATE SET key = excluded.key, read_only = excluded.read_only
+	`
+	sqliteUpdateData = "UPDATE storage SET data = ?3, key = COALESCE(?4, key) WHERE uid = ?1 AND id = ?2"
+)
+
+// NewSQLiteRepo returns the repository keeping the data in the SQLite database, and creates its tables if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+
+	for _, q := range []string{createSQLiteStorage, createSQLiteShares, createSQLiteSealedShares} {
+		if _, err := db.ExecContext(context.Background(), q); err != nil {
+			return &SQLiteRepo{}, err
+		}
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteData(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteData, uid, id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+// DeleteAllData removes the user's items along with their shares, and the shares received by the user.
+func (r *SQLiteRepo) DeleteAllData(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	for _, q := range []string{sqliteDeleteAllShares, sqliteDeleteAllSealed, sqliteDeleteAllData} {
+		if _, err := r.conn(ctx).ExecContext(ctx, q, uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) DeleteShare(ctx context.Context, owner, id, uid string) error {
+	if owner == "" || id == "" || uid == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteShare, owner, id, uid)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+func (r *SQLiteRepo) DeleteSealedShare(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteSealedShare, uid, id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+// Export returns the items with the keys wrapped for their owners, and all shares of them.
+func (r *SQLiteRepo) Export(ctx context.Context) (Dump, error) {
+	var (
+		dump Dump
+		err  error
+	)
+	if dump.Data, err = r.exportData(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Shares, err = r.exportShares(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Sealed, err = r.exportSealed(ctx); err != nil {
+		return Dump{}, err
+	}
+	return dump, nil
+}
+
+func (r *SQLiteRepo) GetAllDataByType(ctx context.Context, uid string, t StorageType) ([]SecureData, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteGetAllDataByType, uid, t)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var data []SecureData
+	for rows.Next() {
+		var piece SecureData
+		if err = rows.Scan(&piece.ID, &piece.UID, &piece.Data, &piece.Type, &piece.Key, &piece.Shared,
+			&piece.ReadOnly); err != nil {
+			return nil, err
+		}
+		data = append(data, piece)
+	}
+	return data, rows.Err()
+}
+
+func (r *SQLiteRepo) GetDataByID(ctx context.Context, uid, id string) (SecureData, error) {
+	if uid == "" || id == "" {
+		return SecureData{}, ErrNotFound
+	}
+
+	var data SecureData
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetDataByID, uid, id).
+		Scan(&data.ID, &data.UID, &data.Data, &data.Type, &data.Key, &data.Shared, &data.ReadOnly)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SecureData{}, ErrNotFound
+	}
+	return data, err
+}
+
+func (r *SQLiteRepo) GetSealedShares(ctx context.Context, uid string) ([]SealedShare, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteGetSealedShares, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	shares := make([]SealedShare, 0)
+	for rows.Next() {
+		var s SealedShare
+		if err = rows.Scan(&s.ID, &s.ItemID, &s.Owner, &s.UID, &s.Type, &s.Data, &s.Key, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+func (r *SQLiteRepo) GetShares(ctx context.Context, owner, id string) ([]Share, error) {
+	if owner == "" || id == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteGetShares, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var shares []Share
+	for rows.Next() {
+		var s Share
+		if err = rows.Scan(&s.ItemID, &s.Owner, &s.UID, &s.Key, &s.ReadOnly, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+// Import stores the items before their shares, so the shares of the unknown items are rejected.
+func (r *SQLiteRepo) Import(ctx context.Context, dump Dump) error {
+	for _, d := range dump.Data {
+		if d.ID == "" || d.UID == "" || d.Data == nil {
+			return ErrEmpty
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, sqliteImportData, d.ID, d.UID, d.Data, d.Type, d.Key); err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	for _, s := range dump.Shares {
+		res, err := r.conn(ctx).ExecContext(ctx, sqliteImportShare, s.Owner, s.ItemID, s.UID, s.Key, s.ReadOnly,
+			s.CreatedAt)
+		if err != nil {
+			return mapSQLiteError(err)
+		}
+		if err = r.checkAffected(res); err != nil {
+			return err
+		}
+	}
+	for _, s := range dump.Sealed {
+		res, err := r.conn(ctx).ExecContext(ctx, sqliteImportSealed, s.Owner, s.ItemID, s.ID, s.UID, s.Data, s.Key,
+			s.CreatedAt)
+		if err != nil {
+			return mapSQLiteError(err)
+		}
+		if err = r.checkAffected(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) StoreData(ctx context.Context, data SecureData) (string, error) {
+	if data.Data == nil || data.UID == "" {
+		return "", ErrEmpty
+	}
+
+	id := uuid.NewString()
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreData, id, data.UID, data.Data, data.Type, data.Key)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepo) StoreSealedShare(ctx context.Context, share SealedShare) (string, error) {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" || share.Data == nil || share.Key == nil {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteStoreSealedShare, share.Owner, share.ItemID, share.UID, share.Data,
+		share.Key, time.Now().UTC(), uuid.NewString()).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return id, err
+}
+
+func (r *SQLiteRepo) StoreShare(ctx context.Context, share Share) error {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" || share.Key == nil {
+		return ErrMissingArgs
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteStoreShare, share.Owner, share.ItemID, share.UID, share.Key,
+		share.ReadOnly, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+func (r *SQLiteRepo) UpdateData(ctx context.Context, data SecureData) error {
+	if data.Data == nil || data.UID == "" {
+		return ErrEmpty
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteUpdateData, data.UID, data.ID, data.Data, data.Key)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res)
+}
+
+func (r *SQLiteRepo) exportData(ctx context.Context) ([]SecureData, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteExportData)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var data []SecureData
+	for rows.Next() {
+		var d SecureData
+		if err = rows.Scan(&d.ID, &d.UID, &d.Data, &d.Type, &d.Key); err != nil {
+			return nil, err
+		}
+		data = append(data, d)
+	}
+	return data, rows.Err()
+}
+
+func (r *SQLiteRepo) exportShares(ctx context.Context) ([]Share, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteExportShares)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var shares []Share
+	for rows.Next() {
+		var s Share
+		if err = rows.Scan(&s.ItemID, &s.Owner, &s.UID, &s.Key, &s.ReadOnly, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+func (r *SQLiteRepo) exportSealed(ctx context.Context) ([]SealedShare, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteExportSealed)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	var shares []SealedShare
+	for rows.Next() {
+		var s SealedShare
+		if err = rows.Scan(&s.ID, &s.ItemID, &s.Owner, &s.UID, &s.Type, &s.Data, &s.Key, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+func (r *SQLiteRepo) checkAffected(res sql.Result) error {
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintUnique:
+		return ErrExists
+	case sqlite3.ErrConstraintForeignKey:
+		return ErrNotFound
+	}
+	return err
+}
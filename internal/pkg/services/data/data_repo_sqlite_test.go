@@ -0,0 +1,123 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteAllData(t *testing.T) {
+	testDeleteAllData(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_DeleteData(t *testing.T) {
+	testDeleteData(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_DeleteShare(t *testing.T) {
+	testDeleteShare(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_ExportImport(t *testing.T) {
+	testExportImport(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetAllDataByType(t *testing.T) {
+	testGetAllDataByType(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetDataByID(t *testing.T) {
+	testGetDataByID(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetShares(t *testing.T) {
+	testGetShares(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_StoreData(t *testing.T) {
+	testStoreData(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_StoreShare(t *testing.T) {
+	testStoreShare(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_StoreSealedShare(t *testing.T) {
+	testStoreSealedShare(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_UpdateData(t *testing.T) {
+	testUpdateData(t, initSQLiteRepo)
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*data.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*data.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, data map[string]SecureData, shares ...Share) IRepository {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for id, d := range data {
+		if _, err = r.db.ExecContext(ctx, sqliteStoreData, id, d.UID, d.Data, d.Type, d.Key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, sh := range shares {
+		if _, err = r.db.ExecContext(ctx,
+			"INSERT INTO shares(item_id, owner, uid, key, read_only, created_at) VALUES (?1, ?2, ?3, ?4, ?5, ?6)",
+			sh.ItemID, sh.Owner, sh.UID, sh.Key, sh.ReadOnly, sh.CreatedAt.UTC()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
@@ -1,14 +1,24 @@
 package data
 
 import (
+	"context"
+	"database/sql"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
+type deleteAllDataCase struct {
+	name    string
+	repo    map[string]SecureData
+	uid     string
+	wantErr error
+}
+
 type deleteDataArgs struct {
 	uid string
 	id  string
@@ -29,6 +39,7 @@ type getAllDataByTypeArgs struct {
 type getAllDataByTypeCase struct {
 	name    string
 	repo    map[string]SecureData
+	shares  []Share
 	args    getAllDataByTypeArgs
 	want    []SecureData
 	wantErr error
@@ -42,11 +53,62 @@ type getDataByIDArgs struct {
 type getDataByIDCase struct {
 	name    string
 	repo    map[string]SecureData
+	shares  []Share
 	args    getDataByIDArgs
 	want    SecureData
 	wantErr error
 }
 
+type deleteShareArgs struct {
+	owner string
+	id    string
+	uid   string
+}
+
+type deleteShareCase struct {
+	name    string
+	repo    map[string]SecureData
+	shares  []Share
+	args    deleteShareArgs
+	wantErr error
+}
+
+type getSharesArgs struct {
+	owner string
+	id    string
+}
+
+type getSharesCase struct {
+	name    string
+	repo    map[string]SecureData
+	shares  []Share
+	args    getSharesArgs
+	want    []Share
+	wantErr error
+}
+
+type storeShareCase struct {
+	name    string
+	repo    map[string]SecureData
+	share   Share
+	wantErr error
+}
+
+type storeSealedShareCase struct {
+	name    string
+	repo    map[string]SecureData
+	share   SealedShare
+	wantErr error
+}
+
+type updateDataCase struct {
+	name    string
+	repo    map[string]SecureData
+	data    SecureData
+	want    SecureData
+	wantErr error
+}
+
 type storeDataCase struct {
 	name    string
 	repo    map[string]SecureData
@@ -55,27 +117,34 @@ type storeDataCase struct {
 	wantErr error
 }
 
+// repoInit creates the repository of the tested backend filled with the items keyed by their IDs, and the shares.
+type repoInit func(t *testing.T, data map[string]SecureData, shares ...Share) IRepository
+
 func TestNewRepo(t *testing.T) {
 	tests := []struct {
 		name    string
-		repoURL string
+		db      *sql.DB
 		want    string
 		wantErr bool
 	}{
 		{
-			name: "Repo URL is missing",
+			name: "DB is missing",
 			want: "*data.BasicRepo",
 		},
 		{
-			name:    "Wrong Repo URL is present",
-			repoURL: "postgres://localhost:5432/test",
-			want:    "*data.DBRepo",
-			wantErr: true,
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*data.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*data.SQLiteRepo",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewRepo(tt.repoURL)
+			got, err := NewRepo(tt.db, nil)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rGot := reflect.ValueOf(got)
@@ -84,18 +153,196 @@ func TestNewRepo(t *testing.T) {
 	}
 }
 
-func initBasicRepo(data map[string]SecureData) *BasicRepo {
+func testDeleteAllData(t *testing.T, initRepo repoInit) {
+	for _, tt := range getDeleteAllDataCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.DeleteAllData(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+
+			for _, v := range tt.repo {
+				_, gErr := r.GetDataByID(context.Background(), v.UID, v.ID)
+				assert.Equal(t, err == nil && v.UID == tt.uid, gErr != nil)
+			}
+		})
+	}
+}
+
+func testDeleteData(t *testing.T, initRepo repoInit) {
+	for _, tt := range getDeleteDataCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.DeleteData(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testDeleteShare(t *testing.T, initRepo repoInit) {
+	for _, tt := range getDeleteShareCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo, tt.shares...)
+			err := r.DeleteShare(context.Background(), tt.args.owner, tt.args.id, tt.args.uid)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				_, gErr := r.GetDataByID(context.Background(), tt.args.uid, tt.args.id)
+				assert.Equal(t, ErrNotFound, gErr)
+			}
+		})
+	}
+}
+
+func testExportImport(t *testing.T, initRepo repoInit) {
+	ctx := context.Background()
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	dump := Dump{
+		Data: []SecureData{
+			{ID: "testID", UID: "owner", Data: []byte("text"), Type: SText, Key: []byte("key")},
+			{ID: "testID1", UID: "owner", Data: []byte("card"), Type: SCard, Key: []byte("key1")},
+		},
+		Shares: []Share{
+			{ItemID: "testID", Owner: "owner", UID: "uid", Key: []byte("shared"), ReadOnly: true, CreatedAt: created},
+		},
+		Sealed: []SealedShare{{
+			ID: "sealedID", ItemID: "testID1", Owner: "owner", UID: "uid", Type: SCard, Data: []byte("sealed"),
+			Key: []byte("sealed"), CreatedAt: created,
+		}},
+	}
+
+	r := initRepo(t, nil)
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Data: dump.Data[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Shares: dump.Shares}))
+	assert.Equal(t, ErrEmpty, r.Import(ctx, Dump{Data: []SecureData{{ID: "testID2", UID: "owner"}}}))
+
+	foreign := Share{ItemID: "testID", Owner: "other", UID: "uid1", Key: []byte("shared"), CreatedAt: created}
+	assert.Equal(t, ErrNotFound, r.Import(ctx, Dump{Shares: []Share{foreign}}))
+}
+
+func testGetAllDataByType(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetAllDataByTypeCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo, tt.shares...)
+			got, err := r.GetAllDataByType(context.Background(), tt.args.uid, tt.args.t)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testGetDataByID(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetDataByIDCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo, tt.shares...)
+			got, err := r.GetDataByID(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testGetShares(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetSharesCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo, tt.shares...)
+			got, err := r.GetShares(context.Background(), tt.args.owner, tt.args.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testStoreData(t *testing.T, initRepo repoInit) {
+	for _, tt := range getStoreDataCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			got, err := r.StoreData(context.Background(), tt.data)
+			assert.Equal(t, tt.wantLen, len(got))
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testStoreShare(t *testing.T, initRepo repoInit) {
+	for _, tt := range getStoreShareCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.StoreShare(context.Background(), tt.share)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				got, gErr := r.GetDataByID(context.Background(), tt.share.UID, tt.share.ItemID)
+				assert.NoError(t, gErr)
+				assert.Equal(t, tt.share.Key, got.Key)
+				assert.True(t, got.Shared)
+			}
+		})
+	}
+}
+
+func testStoreSealedShare(t *testing.T, initRepo repoInit) {
+	for _, tt := range getStoreSealedShareCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			id, err := r.StoreSealedShare(context.Background(), tt.share)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			got, gErr := r.GetSealedShares(context.Background(), tt.share.UID)
+			assert.NoError(t, gErr)
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, id, got[0].ID)
+				assert.Equal(t, tt.repo[tt.share.ItemID].Type, got[0].Type)
+			}
+
+			assert.Equal(t, ErrNotFound, r.DeleteSealedShare(context.Background(), "testUser0", id))
+			assert.NoError(t, r.DeleteData(context.Background(), tt.share.Owner, tt.share.ItemID))
+			got, gErr = r.GetSealedShares(context.Background(), tt.share.UID)
+			assert.NoError(t, gErr)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func testUpdateData(t *testing.T, initRepo repoInit) {
+	for _, tt := range getUpdateDataCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.UpdateData(context.Background(), tt.data)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				got, gErr := r.GetDataByID(context.Background(), tt.data.UID, tt.data.ID)
+				assert.NoError(t, gErr)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func initBasicRepo(data map[string]SecureData, shares ...Share) *BasicRepo {
 	ds := &sync.Map{}
 	for id, d := range data {
-		if us, ok := ds.Load(d.UID); !ok {
-			sd := &sync.Map{}
-			sd.Store(id, d)
-			ds.Store(d.UID, Storage{user: sd})
-		} else {
-			us.(Storage).user.Store(id, d)
-		}
+		d.ID = id
+		ds.Store(id, d)
 	}
-	return &BasicRepo{data: ds}
+
+	ss := &sync.Map{}
+	for _, sh := range shares {
+		ss.Store(shareKey(sh.ItemID, sh.UID), sh)
+	}
+	return &BasicRepo{data: ds, shares: ss, sealed: &sync.Map{}}
+}
+
+func initTestBasicRepo(_ *testing.T, data map[string]SecureData, shares ...Share) IRepository {
+	return initBasicRepo(data, shares...)
 }
 
 func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
@@ -103,6 +350,41 @@ func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
 	return &DBRepo{db: db}, mock, err
 }
 
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getDeleteAllDataCases() []deleteAllDataCase {
+	tr := map[string]SecureData{
+		"testID":  {UID: "testUser", ID: "testID", Type: SCard},
+		"testID1": {UID: "testUser", ID: "testID1", Type: SPassword},
+		"testID2": {UID: "testUser1", ID: "testID2", Type: SText},
+	}
+
+	return []deleteAllDataCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "No data for user present",
+			repo: tr,
+			uid:  "testUser0",
+		},
+		{
+			name: "User data present",
+			repo: tr,
+			uid:  "testUser",
+		},
+	}
+}
+
 func getDeleteDataCases() []deleteDataCase {
 	return []deleteDataCase{
 		{
@@ -164,6 +446,15 @@ func getGetAllDataByTypeCases() []getAllDataByTypeCase {
 			args: getAllDataByTypeArgs{uid: "testUser", t: SCard},
 			want: []SecureData{{UID: "testUser", ID: "testID", Type: SCard}},
 		},
+		{
+			name:   "Shared data present",
+			repo:   tr,
+			shares: []Share{{ItemID: "testID1", Owner: "testUser", UID: "testUser1", Key: []byte("key"), ReadOnly: true}},
+			args:   getAllDataByTypeArgs{uid: "testUser1", t: SPassword},
+			want: []SecureData{{
+				UID: "testUser", ID: "testID1", Type: SPassword, Key: []byte("key"), Shared: true, ReadOnly: true,
+			}},
+		},
 	}
 }
 
@@ -200,6 +491,155 @@ func getGetDataByIDCases() []getDataByIDCase {
 			repo: map[string]SecureData{td.ID: td},
 			want: td,
 		},
+		{
+			name:   "Shared data is present",
+			args:   getDataByIDArgs{uid: "testUser1", id: "testID"},
+			repo:   map[string]SecureData{td.ID: td},
+			shares: []Share{{ItemID: "testID", Owner: "testUser", UID: "testUser1", Key: []byte("key")}},
+			want:   SecureData{UID: "testUser", ID: "testID", Data: []byte("test"), Key: []byte("key"), Shared: true},
+		},
+	}
+}
+
+func getDeleteShareCases() []deleteShareCase {
+	tr := map[string]SecureData{"testID": {UID: "testUser", ID: "testID"}}
+	ts := []Share{{ItemID: "testID", Owner: "testUser", UID: "testUser1", Key: []byte("key")}}
+
+	return []deleteShareCase{
+		{
+			name:    "No arguments passed",
+			repo:    tr,
+			shares:  ts,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Share is not present",
+			repo:    tr,
+			shares:  ts,
+			args:    deleteShareArgs{owner: "testUser", id: "testID", uid: "testUser2"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Share of another owner",
+			repo:    tr,
+			shares:  ts,
+			args:    deleteShareArgs{owner: "testUser2", id: "testID", uid: "testUser1"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:   "Share is present",
+			repo:   tr,
+			shares: ts,
+			args:   deleteShareArgs{owner: "testUser", id: "testID", uid: "testUser1"},
+		},
+	}
+}
+
+func getGetSharesCases() []getSharesCase {
+	tr := map[string]SecureData{"testID": {UID: "testUser", ID: "testID"}}
+	ts := []Share{{ItemID: "testID", Owner: "testUser", UID: "testUser1", Key: []byte("key")}}
+
+	return []getSharesCase{
+		{
+			name:    "No arguments passed",
+			repo:    tr,
+			shares:  ts,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:   "Shares of another owner",
+			repo:   tr,
+			shares: ts,
+			args:   getSharesArgs{owner: "testUser1", id: "testID"},
+		},
+		{
+			name:   "Shares are present",
+			repo:   tr,
+			shares: ts,
+			args:   getSharesArgs{owner: "testUser", id: "testID"},
+			want:   ts,
+		},
+	}
+}
+
+func getStoreShareCases() []storeShareCase {
+	tr := map[string]SecureData{"testID": {UID: "testUser", ID: "testID"}}
+
+	return []storeShareCase{
+		{
+			name:    "No share passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Data of another owner",
+			repo:    tr,
+			share:   Share{ItemID: "testID", Owner: "testUser1", UID: "testUser2", Key: []byte("key")},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:  "Data is shared",
+			repo:  tr,
+			share: Share{ItemID: "testID", Owner: "testUser", UID: "testUser1", Key: []byte("key")},
+		},
+	}
+}
+
+func getStoreSealedShareCases() []storeSealedShareCase {
+	tr := map[string]SecureData{"testID": {UID: "testUser", ID: "testID", Type: SText}}
+
+	return []storeSealedShareCase{
+		{
+			name:    "No share passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "Data of another owner",
+			repo: tr,
+			share: SealedShare{
+				ItemID: "testID", Owner: "testUser1", UID: "testUser2", Data: []byte("data"), Key: []byte("key"),
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Data is shared",
+			repo: tr,
+			share: SealedShare{
+				ItemID: "testID", Owner: "testUser", UID: "testUser1", Data: []byte("data"), Key: []byte("key"),
+			},
+		},
+	}
+}
+
+func getUpdateDataCases() []updateDataCase {
+	td := SecureData{UID: "testUser", ID: "testID", Data: []byte("test"), Key: []byte("key")}
+
+	return []updateDataCase{
+		{
+			name:    "No data passed",
+			repo:    map[string]SecureData{td.ID: td},
+			data:    SecureData{UID: "testUser", ID: "testID"},
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "Data of another user",
+			repo:    map[string]SecureData{td.ID: td},
+			data:    SecureData{UID: "testUser1", ID: "testID", Data: []byte("test1")},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Data is updated and the key is kept",
+			repo: map[string]SecureData{td.ID: td},
+			data: SecureData{UID: "testUser", ID: "testID", Data: []byte("test1")},
+			want: SecureData{UID: "testUser", ID: "testID", Data: []byte("test1"), Key: []byte("key")},
+		},
+		{
+			name: "Data and key are updated",
+			repo: map[string]SecureData{td.ID: td},
+			data: SecureData{UID: "testUser", ID: "testID", Data: []byte("test1"), Key: []byte("key1")},
+			want: SecureData{UID: "testUser", ID: "testID", Data: []byte("test1"), Key: []byte("key1")},
+		},
 	}
 }
 
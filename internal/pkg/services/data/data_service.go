@@ -2,16 +2,28 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 
 	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 type IRepository interface {
+	DeleteAllData(ctx context.Context, uid string) error
 	DeleteData(ctx context.Context, uid, id string) error
+	DeleteSealedShare(ctx context.Context, uid, id string) error
+	DeleteShare(ctx context.Context, owner, id, uid string) error
+	Export(ctx context.Context) (Dump, error)
 	GetAllDataByType(ctx context.Context, uid string, t StorageType) ([]SecureData, error)
 	GetDataByID(ctx context.Context, uid, id string) (SecureData, error)
+	GetSealedShares(ctx context.Context, uid string) ([]SealedShare, error)
+	GetShares(ctx context.Context, owner, id string) ([]Share, error)
+	Import(ctx context.Context, dump Dump) error
 	StoreData(ctx context.Context, data SecureData) (string, error)
+	StoreSealedShare(ctx context.Context, share SealedShare) (string, error)
+	StoreShare(ctx context.Context, share Share) error
+	UpdateData(ctx context.Context, data SecureData) error
 }
 
 type Service struct {
@@ -19,34 +31,56 @@ type Service struct {
 }
 
 // NewService returns an instance of the Service with the associated repository.
-// The repository gets created in accordance with the passed URL.
-func NewService(repoURL string) (Service, error) {
-	db, err := NewRepo(repoURL)
-	return Service{db: db}, err
+// The repository is built upon the passed database handle, or kept in memory if the handle is nil.
+// The in-memory repository is persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo}, err
 }
 
-// GetAllDataByType returns all the user's stored data.
+// Export returns all stored items owned by the users, along with their shares.
+func (s Service) Export(ctx context.Context) (Dump, error) {
+	return s.db.Export(ctx)
+}
+
+// Import stores the exported items and shares with their IDs, as is.
+// Run it within the unit of work, so the failed import leaves no partial changes behind.
+func (s Service) Import(ctx context.Context, dump Dump) error {
+	return s.db.Import(ctx, dump)
+}
+
+// GetAllDataByType returns all the user's stored data, including the data shared with the user.
 func (s Service) GetAllDataByType(ctx context.Context, uid string, t StorageType) ([]SecureData, error) {
 	return s.db.GetAllDataByType(ctx, uid, t)
 }
 
 // GetDataByID returns the stored data by the unique ID.
-// The method returns the data of the specified user only.
+// The method returns the data of the specified user, or the data shared with the user, only.
 func (s Service) GetDataByID(ctx context.Context, uid, id string) (SecureData, error) {
 	return s.db.GetDataByID(ctx, uid, id)
 }
 
 // StoreSecureDataFromPayload processes payload of any type into a slice of bytes,
-// encodes the slice, and stores the content in the DB.
+// encodes the slice with a new item key, and stores the content in the DB.
+// The item key is stored wrapped for the user.
 func (s Service) StoreSecureDataFromPayload(ctx context.Context, uid string,
 	payload any, t StorageType,
 ) (string, error) {
-	data, err := json.Marshal(payload)
+	if uid == "" {
+		return "", ErrEmpty
+	}
+
+	key, err := enc.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+
+	encData, err := encryptPayload(key, payload)
 	if err != nil {
 		return "", err
 	}
 
-	encData, err := enc.EncryptData(data)
+	wrapped, err := enc.WrapKey(uid, key)
 	if err != nil {
 		return "", err
 	}
@@ -55,17 +89,185 @@ func (s Service) StoreSecureDataFromPayload(ctx context.Context, uid string,
 		UID:  uid,
 		Data: encData,
 		Type: t,
+		Key:  wrapped,
 	}
 	return s.db.StoreData(ctx, sd)
 }
 
+// UpdateSecureDataFromPayload replaces the content of the stored data with the passed payload.
+// The data can be updated by its owner, or by the user it is shared with for writing.
+func (s Service) UpdateSecureDataFromPayload(ctx context.Context, uid, id string,
+	payload any, t StorageType,
+) error {
+	d, err := s.db.GetDataByID(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	if d.Type != t {
+		return ErrNotFound
+	}
+	if d.ReadOnly {
+		return ErrReadOnly
+	}
+
+	key, wrapped, err := s.getItemKey(uid, d)
+	if err != nil {
+		return err
+	}
+
+	if d.Data, err = encryptPayload(key, payload); err != nil {
+		return err
+	}
+	d.Key = wrapped
+	return s.db.UpdateData(ctx, d)
+}
+
 // DeleteSecureData removes the stored data with the unique ID.
-// The method removes the data of the specified user only.
+// The method removes the data of the specified user only, along with all its shares.
 func (s Service) DeleteSecureData(ctx context.Context, uid, id string) error {
 	return s.db.DeleteData(ctx, uid, id)
 }
 
-// GetDataFromBytes transforms the encrypted slice of bytes into the original one.
-func (s Service) GetDataFromBytes(b []byte) ([]byte, error) {
-	return enc.DecryptData(b)
+// DeleteAllSecureData removes all the data stored by the specified user.
+func (s Service) DeleteAllSecureData(ctx context.Context, uid string) error {
+	return s.db.DeleteAllData(ctx, uid)
+}
+
+// DecryptSecureData transforms the encrypted data requested by the user into the original slice of bytes.
+// The data stored before the item keys were introduced is decrypted with the common key.
+func (s Service) DecryptSecureData(uid string, d SecureData) ([]byte, error) {
+	if d.Key == nil {
+		return enc.DecryptData(d.Data)
+	}
+
+	key, err := enc.UnwrapKey(uid, d.Key)
+	if err != nil {
+		return nil, err
+	}
+	return enc.DecryptDataWithKey(key, d.Data)
+}
+
+// ShareData grants the access to the owner's data to another user.
+// The item key is re-wrapped for the recipient, so the recipient never gets the owner's wrapped key.
+// Sharing the data with the same user again replaces the previous grant.
+func (s Service) ShareData(ctx context.Context, owner, id, uid string, readOnly bool) error {
+	if owner == "" || id == "" || uid == "" {
+		return ErrMissingArgs
+	}
+	if owner == uid {
+		return ErrShareSelf
+	}
+
+	d, err := s.getOwnedData(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	key, wrapped, err := s.getItemKey(owner, d)
+	if err != nil {
+		return err
+	}
+	if wrapped != nil {
+		if err = s.migrateData(ctx, d, key, wrapped); err != nil {
+			return err
+		}
+	}
+
+	shareKey, err := enc.WrapKey(uid, key)
+	if err != nil {
+		return err
+	}
+	return s.db.StoreShare(ctx, Share{ItemID: id, Owner: owner, UID: uid, Key: shareKey, ReadOnly: readOnly})
+}
+
+// RevokeShare removes the access to the owner's data from the user it was shared with.
+func (s Service) RevokeShare(ctx context.Context, owner, id, uid string) error {
+	return s.db.DeleteShare(ctx, owner, id, uid)
+}
+
+// GetShares returns the list of the users the owner's data is shared with.
+func (s Service) GetShares(ctx context.Context, owner, id string) ([]Share, error) {
+	if _, err := s.getOwnedData(ctx, owner, id); err != nil {
+		return nil, err
+	}
+	return s.db.GetShares(ctx, owner, id)
+}
+
+// ShareSealedData stores the copy of the owner's data encrypted for the recipient on the client side.
+// The data key is sealed to the recipient's public key, so the service only keeps the opaque bytes.
+// Sharing the data with the same user again replaces the previous copy.
+func (s Service) ShareSealedData(ctx context.Context, share SealedShare) (string, error) {
+	if share.Owner == "" || share.ItemID == "" || share.UID == "" {
+		return "", ErrMissingArgs
+	}
+	if share.Owner == share.UID {
+		return "", ErrShareSelf
+	}
+	if len(share.Data) == 0 || len(share.Key) == 0 {
+		return "", ErrEmpty
+	}
+	if _, err := s.getOwnedData(ctx, share.Owner, share.ItemID); err != nil {
+		return "", err
+	}
+	return s.db.StoreSealedShare(ctx, share)
+}
+
+// GetSealedShares returns the sealed copies of the data shared with the user.
+func (s Service) GetSealedShares(ctx context.Context, uid string) ([]SealedShare, error) {
+	return s.db.GetSealedShares(ctx, uid)
+}
+
+// DeleteSealedShare removes the sealed copy. Both the owner and the recipient are allowed to remove it.
+func (s Service) DeleteSealedShare(ctx context.Context, uid, id string) error {
+	return s.db.DeleteSealedShare(ctx, uid, id)
+}
+
+func (s Service) getOwnedData(ctx context.Context, owner, id string) (SecureData, error) {
+	d, err := s.db.GetDataByID(ctx, owner, id)
+	if err != nil {
+		return SecureData{}, err
+	}
+	if d.Shared {
+		return SecureData{}, ErrNotFound
+	}
+	return d, nil
+}
+
+// getItemKey returns the unwrapped key of the data requested by the user.
+// If the data has no item key yet, a new one is generated and returned along with its owner-wrapped copy,
+// so the caller can re-encrypt the data with it.
+func (s Service) getItemKey(uid string, d SecureData) ([]byte, []byte, error) {
+	if d.Key != nil {
+		key, err := enc.UnwrapKey(uid, d.Key)
+		return key, nil, err
+	}
+
+	key, err := enc.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := enc.WrapKey(d.UID, key)
+	return key, wrapped, err
+}
+
+// migrateData re-encrypts the data stored with the common key using the passed item key.
+func (s Service) migrateData(ctx context.Context, d SecureData, key, wrapped []byte) error {
+	b, err := enc.DecryptData(d.Data)
+	if err != nil {
+		return err
+	}
+
+	if d.Data, err = enc.EncryptDataWithKey(key, b); err != nil {
+		return err
+	}
+	d.Key = wrapped
+	return s.db.UpdateData(ctx, d)
+}
+
+func encryptPayload(key []byte, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return enc.EncryptDataWithKey(key, data)
 }
@@ -2,33 +2,35 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
 )
 
 func TestNewService(t *testing.T) {
 	tests := []struct {
 		name         string
-		repoURL      string
+		db           *sql.DB
 		wantRepoType string
 		wantErr      bool
 	}{
 		{
-			name:         "Repo URL is missing",
+			name:         "DB is missing",
 			wantRepoType: "*data.BasicRepo",
 		},
 		{
-			name:         "Wrong Repo URL is present",
-			repoURL:      "postgres://localhost:5432/test",
+			name:         "DB is present",
+			db:           initDB(t),
 			wantRepoType: "*data.DBRepo",
-			wantErr:      true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewService(tt.repoURL)
+			got, err := NewService(tt.db, nil)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rRepo := reflect.ValueOf(got.db)
@@ -65,3 +67,262 @@ func TestService_StoreSecureDataFromPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestService_DecryptSecureData(t *testing.T) {
+	s := Service{db: initBasicRepo(nil)}
+	id, err := s.StoreSecureDataFromPayload(context.Background(), "owner", "test", SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.GetDataByID(context.Background(), "owner", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := enc.EncryptData([]byte(`"test"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uid     string
+		d       SecureData
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "Data without the item key",
+			uid:  "owner",
+			d:    SecureData{UID: "owner", Data: legacy},
+			want: []byte(`"test"`),
+		},
+		{
+			name:    "Item key of another user",
+			uid:     "user",
+			d:       d,
+			wantErr: enc.ErrDecryption,
+		},
+		{
+			name: "Item key of the user",
+			uid:  "owner",
+			d:    d,
+			want: []byte(`"test"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dErr := s.DecryptSecureData(tt.uid, tt.d)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, dErr)
+		})
+	}
+}
+
+func TestService_ShareData(t *testing.T) {
+	legacy, err := enc.EncryptData([]byte(`"test"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		owner    string
+		id       string
+		uid      string
+		readOnly bool
+	}
+	tests := []struct {
+		name    string
+		repo    map[string]SecureData
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Missing arguments",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Sharing with the owner",
+			args:    args{owner: "owner", id: "testID", uid: "owner"},
+			wantErr: ErrShareSelf,
+		},
+		{
+			name:    "Data of another user",
+			repo:    map[string]SecureData{"testID": {UID: "user1", ID: "testID", Data: legacy}},
+			args:    args{owner: "owner", id: "testID", uid: "user"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Data without the item key is shared",
+			repo: map[string]SecureData{"testID": {UID: "owner", ID: "testID", Data: legacy}},
+			args: args{owner: "owner", id: "testID", uid: "user", readOnly: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(tt.repo)}
+			err = s.ShareData(context.Background(), tt.args.owner, tt.args.id, tt.args.uid, tt.args.readOnly)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			for _, uid := range []string{tt.args.owner, tt.args.uid} {
+				d, gErr := s.GetDataByID(context.Background(), uid, tt.args.id)
+				assert.NoError(t, gErr)
+				assert.Equal(t, uid == tt.args.uid, d.Shared)
+
+				b, dErr := s.DecryptSecureData(uid, d)
+				assert.NoError(t, dErr)
+				assert.Equal(t, []byte(`"test"`), b)
+			}
+		})
+	}
+}
+
+func TestService_RevokeShare(t *testing.T) {
+	s := Service{db: initBasicRepo(nil)}
+	id, err := s.StoreSecureDataFromPayload(context.Background(), "owner", "test", SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.ShareData(context.Background(), "owner", id, "user", false); err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := s.GetShares(context.Background(), "owner", id)
+	assert.NoError(t, err)
+	assert.Len(t, shares, 1)
+
+	_, err = s.GetShares(context.Background(), "user", id)
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, ErrNotFound, s.RevokeShare(context.Background(), "user", id, "user"))
+
+	assert.NoError(t, s.RevokeShare(context.Background(), "owner", id, "user"))
+	_, err = s.GetDataByID(context.Background(), "user", id)
+	assert.Equal(t, ErrNotFound, err)
+
+	data, err := s.GetAllDataByType(context.Background(), "user", SText)
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestService_ShareSealedData(t *testing.T) {
+	tests := []struct {
+		name    string
+		share   SealedShare
+		wantErr error
+	}{
+		{
+			name:    "Missing arguments",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Sharing with the owner",
+			share:   SealedShare{ItemID: "testID", Owner: "owner", UID: "owner"},
+			wantErr: ErrShareSelf,
+		},
+		{
+			name:    "Missing sealed data",
+			share:   SealedShare{ItemID: "testID", Owner: "owner", UID: "user"},
+			wantErr: ErrEmpty,
+		},
+		{
+			name:    "Data of another user",
+			share:   SealedShare{ItemID: "testID", Owner: "user1", UID: "user", Data: []byte("d"), Key: []byte("k")},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:  "Sealed data is shared",
+			share: SealedShare{ItemID: "testID", Owner: "owner", UID: "user", Data: []byte("d"), Key: []byte("k")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(map[string]SecureData{"testID": {UID: "owner", ID: "testID"}})}
+			id, err := s.ShareSealedData(context.Background(), tt.share)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			shares, gErr := s.GetSealedShares(context.Background(), tt.share.UID)
+			assert.NoError(t, gErr)
+			assert.Len(t, shares, 1)
+			assert.NoError(t, s.DeleteSealedShare(context.Background(), tt.share.UID, id))
+		})
+	}
+}
+
+func TestService_UpdateSecureDataFromPayload(t *testing.T) {
+	s := Service{db: initBasicRepo(nil)}
+	id, err := s.StoreSecureDataFromPayload(context.Background(), "owner", "test", SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.ShareData(context.Background(), "owner", id, "reader", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.ShareData(context.Background(), "owner", id, "writer", false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uid     string
+		t       StorageType
+		payload string
+		wantErr error
+	}{
+		{
+			name:    "Data of another type",
+			uid:     "owner",
+			t:       SPassword,
+			payload: "test1",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data of another user",
+			uid:     "user",
+			t:       SText,
+			payload: "test1",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data shared as read-only",
+			uid:     "reader",
+			t:       SText,
+			payload: "test1",
+			wantErr: ErrReadOnly,
+		},
+		{
+			name:    "Data shared for writing",
+			uid:     "writer",
+			t:       SText,
+			payload: "test2",
+		},
+		{
+			name:    "Data of the owner",
+			uid:     "owner",
+			t:       SText,
+			payload: "test3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err = s.UpdateSecureDataFromPayload(context.Background(), tt.uid, id, tt.payload, tt.t)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			for _, uid := range []string{"owner", "reader", "writer"} {
+				d, gErr := s.GetDataByID(context.Background(), uid, id)
+				assert.NoError(t, gErr)
+
+				b, dErr := s.DecryptSecureData(uid, d)
+				assert.NoError(t, dErr)
+				assert.Equal(t, []byte(`"`+tt.payload+`"`), b)
+			}
+		})
+	}
+}
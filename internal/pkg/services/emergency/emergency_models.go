@@ -0,0 +1,53 @@
+package emergency
+
+import "time"
+
+type Status string
+
+const (
+	StatusIdle      Status = "idle"
+	StatusRequested Status = "requested"
+	StatusGranted   Status = "granted"
+)
+
+type Action string
+
+const (
+	ActionDesignated Action = "designated"
+	ActionRequested  Action = "requested"
+	ActionApproved   Action = "approved"
+	ActionGranted    Action = "granted"
+	ActionRejected   Action = "rejected"
+	ActionRevoked    Action = "revoked"
+	ActionRemoved    Action = "removed"
+)
+
+// Contact is the trusted user who may request the emergency access to the owner's vault.
+// The requested access is granted automatically once GrantsAt passes, unless the owner rejects it.
+type Contact struct {
+	ID          string        `json:"id"`
+	Owner       string        `json:"owner"`
+	Grantee     string        `json:"grantee"`
+	WaitPeriod  time.Duration `json:"wait_period"`
+	Status      Status        `json:"status"`
+	RequestedAt time.Time     `json:"requested_at"`
+	GrantsAt    time.Time     `json:"grants_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// Event is the recorded step of the emergency access lifecycle.
+// The events are kept after the contact is removed.
+type Event struct {
+	ID        string    `json:"id"`
+	ContactID string    `json:"contact_id"`
+	Owner     string    `json:"owner"`
+	Grantee   string    `json:"grantee"`
+	Action    Action    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Dump is the complete content of the repository, moved between the repositories with the IDs preserved.
+type Dump struct {
+	Contacts []Contact
+	Events   []Event
+}
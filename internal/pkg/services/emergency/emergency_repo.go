@@ -0,0 +1,31 @@
+package emergency
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrDBMissing     = errors.New("emergency db is missing")
+	ErrExists        = errors.New("the user is already designated as the emergency contact")
+	ErrMissingArgs   = errors.New("contact owner, grantee or id is not specified")
+	ErrNotFound      = errors.New("emergency contact not found")
+	ErrStatusChanged = errors.New("emergency access status has been changed concurrently")
+)
+
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
+		return NewBasicRepo(), nil
+	}
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
+}
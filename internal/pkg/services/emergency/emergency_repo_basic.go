@@ -0,0 +1,211 @@
+package emergency
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BasicRepo struct {
+	contacts *sync.Map
+	events   *sync.Map
+	mu       *sync.Mutex
+}
+
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{contacts: &sync.Map{}, events: &sync.Map{}, mu: &sync.Mutex{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[Contact](w, "contacts", r.contacts); err != nil {
+		return nil, err
+	}
+	if err := storage.Track[Event](w, "events", r.events); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteContact(ctx context.Context, id string) error {
+	ok, err := storage.Delete(ctx, r.contacts, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Export returns the contacts and events sorted by their IDs.
+func (r *BasicRepo) Export(_ context.Context) (Dump, error) {
+	var dump Dump
+	r.contacts.Range(func(_, v any) bool {
+		dump.Contacts = append(dump.Contacts, v.(Contact))
+		return true
+	})
+	r.events.Range(func(_, v any) bool {
+		dump.Events = append(dump.Events, v.(Event))
+		return true
+	})
+
+	sort.Slice(dump.Contacts, func(i, j int) bool { return dump.Contacts[i].ID < dump.Contacts[j].ID })
+	sort.Slice(dump.Events, func(i, j int) bool { return dump.Events[i].ID < dump.Events[j].ID })
+	return dump, nil
+}
+
+func (r *BasicRepo) GetContact(_ context.Context, id string) (Contact, error) {
+	if c, ok := r.contacts.Load(id); ok {
+		return c.(Contact), nil
+	}
+	return Contact{}, ErrNotFound
+}
+
+func (r *BasicRepo) GetContacts(_ context.Context, uid string) ([]Contact, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	contacts := make([]Contact, 0)
+	r.contacts.Range(func(_, v any) bool {
+		if c := v.(Contact); c.Owner == uid || c.Grantee == uid {
+			contacts = append(contacts, c)
+		}
+		return true
+	})
+
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].CreatedAt.Before(contacts[j].CreatedAt)
+	})
+	return contacts, nil
+}
+
+func (r *BasicRepo) GetDueContacts(_ context.Context, t time.Time) ([]Contact, error) {
+	contacts := make([]Contact, 0)
+	r.contacts.Range(func(_, v any) bool {
+		if c := v.(Contact); c.Status == StatusRequested && !c.GrantsAt.After(t) {
+			contacts = append(contacts, c)
+		}
+		return true
+	})
+	return contacts, nil
+}
+
+func (r *BasicRepo) GetEvents(_ context.Context, uid string) ([]Event, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	events := make([]Event, 0)
+	r.events.Range(func(_, v any) bool {
+		if e := v.(Event); e.Owner == uid || e.Grantee == uid {
+			events = append(events, e)
+		}
+		return true
+	})
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+// Import stores the contacts and events with their IDs.
+// The events are kept after the contacts are removed, so they are imported regardless of the contacts.
+// The changes are undone on failure within the unit of work only.
+func (r *BasicRepo) Import(ctx context.Context, dump Dump) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range dump.Contacts {
+		if c.ID == "" || c.Owner == "" || c.Grantee == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.contacts.Load(c.ID); ok || r.hasContact(c.Owner, c.Grantee) {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.contacts, c.ID, c); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range dump.Events {
+		if e.ID == "" || e.ContactID == "" || e.Owner == "" || e.Grantee == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.events.Load(e.ID); ok {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.events, e.ID, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BasicRepo) StoreContact(ctx context.Context, contact Contact) (string, error) {
+	if contact.Owner == "" || contact.Grantee == "" {
+		return "", ErrMissingArgs
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasContact(contact.Owner, contact.Grantee) {
+		return "", ErrExists
+	}
+
+	contact.ID = uuid.NewString()
+	if err := storage.Store(ctx, r.contacts, contact.ID, contact); err != nil {
+		return "", err
+	}
+	return contact.ID, nil
+}
+
+func (r *BasicRepo) StoreEvent(ctx context.Context, event Event) error {
+	if event.ContactID == "" || event.Owner == "" || event.Grantee == "" {
+		return ErrMissingArgs
+	}
+
+	event.ID = uuid.NewString()
+	return storage.Store(ctx, r.events, event.ID, event)
+}
+
+func (r *BasicRepo) UpdateStatus(ctx context.Context, contact Contact, from Status) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.contacts.Load(contact.ID)
+	if !ok {
+		return ErrNotFound
+	}
+
+	c := v.(Contact)
+	if c.Status != from {
+		return ErrStatusChanged
+	}
+
+	c.Status, c.RequestedAt, c.GrantsAt = contact.Status, contact.RequestedAt, contact.GrantsAt
+	return storage.Store(ctx, r.contacts, c.ID, c)
+}
+
+// hasContact checks if the grantee is designated as the owner's contact. Call it with the mutex locked.
+func (r *BasicRepo) hasContact(owner, grantee string) bool {
+	var exists bool
+	r.contacts.Range(func(_, v any) bool {
+		c := v.(Contact)
+		exists = c.Owner == owner && c.Grantee == grantee
+		return !exists
+	})
+	return exists
+}
@@ -0,0 +1,157 @@
+package emergency
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBasicRepo_DeleteContact(t *testing.T) {
+	r := initBasicRepo(getTestContacts())
+	assert.Equal(t, ErrNotFound, r.DeleteContact(context.Background(), "testID0"))
+	assert.NoError(t, r.DeleteContact(context.Background(), "testID"))
+
+	_, err := r.GetContact(context.Background(), "testID")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestBasicRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	dump := getTestDump()
+
+	r := initBasicRepo(nil)
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Contacts: dump.Contacts[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Events: dump.Events[:1]}))
+	dup := dump.Contacts[0]
+	dup.ID = "testID3"
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Contacts: []Contact{dup}}))
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Contacts: []Contact{{Owner: "testOwner", Grantee: "test"}}}))
+}
+
+func TestBasicRepo_GetContacts(t *testing.T) {
+	for _, tt := range getGetContactsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetContacts(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_GetDueContacts(t *testing.T) {
+	for _, tt := range getGetDueContactsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetDueContacts(context.Background(), tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasicRepo_StoreContact(t *testing.T) {
+	for _, tt := range getStoreContactCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.StoreContact(context.Background(), tt.contact)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestBasicRepo_UpdateStatus(t *testing.T) {
+	for _, tt := range getUpdateStatusCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			err := r.UpdateStatus(context.Background(), tt.contact, tt.from)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			got, err := r.GetContact(context.Background(), tt.contact.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contact, got)
+		})
+	}
+}
+
+func TestNewBasicRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantField     string
+		wantFieldType string
+		wantType      string
+	}{
+		{
+			name:          "Basic repo is created",
+			wantField:     "contacts",
+			wantFieldType: "*sync.Map",
+			wantType:      "*emergency.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBasicRepo()
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.wantType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.wantField, rField.Name)
+			assert.Equal(t, tt.wantFieldType, rField.Type.String())
+		})
+	}
+}
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := Contact{Owner: "testOwner", Grantee: "testGrantee", WaitPeriod: time.Hour, Status: StatusIdle, CreatedAt: created}
+	e := Event{Owner: "testOwner", Grantee: "testGrantee", Action: ActionDesignated, CreatedAt: created}
+
+	r := initPersistentRepo(t, dir)
+	id, err := r.StoreContact(ctx, c)
+	assert.NoError(t, err)
+	c.ID, e.ContactID = id, id
+	assert.NoError(t, r.StoreEvent(ctx, e))
+	c.Status, c.RequestedAt, c.GrantsAt = StatusRequested, created, created.Add(time.Hour)
+	assert.NoError(t, r.UpdateStatus(ctx, c, StatusIdle))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetContact(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, c, got)
+	events, err := r.GetEvents(ctx, "testOwner")
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		e.ID = events[0].ID
+		assert.Equal(t, e, events[0])
+	}
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "emergency", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,270 @@
+package emergency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const uniqueViolation = "23505"
+
+type DBRepo struct {
+	db *sql.DB
+}
+
+const (
+	DeleteContact  = "DELETE FROM emergency_contacts WHERE id = $1"
+	ExportContacts = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts ORDER BY id
+	`
+	ExportEvents = "SELECT id, contact_id, owner, grantee, action, created_at FROM emergency_events ORDER BY id"
+	GetContact   = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts WHERE id = $1
+	`
+	GetContacts = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts WHERE owner = $1 OR grantee = $1 ORDER BY created_at
+	`
+	GetDueContacts = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts WHERE status = 'requested' AND grants_at <= $1
+	`
+	GetEvents = `
+		SELECT id, contact_id, owner, grantee, action, created_at FROM emergency_events
+		WHERE owner = $1 OR grantee = $1 ORDER BY created_at
+	`
+	ImportContact = `
+		INSERT INTO emergency_contacts(id, owner, grantee, wait_period, status, requested_at, grants_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	ImportEvent = `
+		INSERT INTO emergency_events(id, contact_id, owner, grantee, action, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	StoreContact = `
+		INSERT INTO emergency_contacts(owner, grantee, wait_period, status, requested_at, grants_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
+	`
+	StoreEvent = `
+		INSERT INTO emergency_events(contact_id, owner, grantee, action, created_at) VALUES ($1, $2, $3, $4, $5)
+	`
+	UpdateStatus = `
+		UPDATE emergency_contacts SET status = $3, requested_at = $4, grants_at = $5 WHERE id = $1 AND status = $2
+	`
+)
+
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
+	}
+	return &DBRepo{db: db}, nil
+}
+
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *DBRepo) DeleteContact(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteContact, id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrNotFound)
+}
+
+// Export returns the contacts and events sorted by their IDs.
+func (r *DBRepo) Export(ctx context.Context) (Dump, error) {
+	contacts, err := r.queryContacts(ctx, ExportContacts)
+	if err != nil {
+		return Dump{}, err
+	}
+	events, err := r.queryEvents(ctx, ExportEvents)
+	if err != nil {
+		return Dump{}, err
+	}
+	return Dump{Contacts: contacts, Events: events}, nil
+}
+
+func (r *DBRepo) GetContact(ctx context.Context, id string) (Contact, error) {
+	if id == "" {
+		return Contact{}, ErrNotFound
+	}
+
+	c, err := r.scanContact(r.conn(ctx).QueryRowContext(ctx, GetContact, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Contact{}, ErrNotFound
+	}
+	return c, err
+}
+
+func (r *DBRepo) GetContacts(ctx context.Context, uid string) ([]Contact, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+	return r.queryContacts(ctx, GetContacts, uid)
+}
+
+func (r *DBRepo) GetDueContacts(ctx context.Context, t time.Time) ([]Contact, error) {
+	return r.queryContacts(ctx, GetDueContacts, t)
+}
+
+func (r *DBRepo) GetEvents(ctx context.Context, uid string) ([]Event, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	return r.queryEvents(ctx, GetEvents, uid)
+}
+
+// Import stores the contacts and events with their IDs.
+// The events are kept after the contacts are removed, so they are imported regardless of the contacts.
+func (r *DBRepo) Import(ctx context.Context, dump Dump) error {
+	for _, c := range dump.Contacts {
+		if c.ID == "" || c.Owner == "" || c.Grantee == "" {
+			return ErrMissingArgs
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, ImportContact, c.ID, c.Owner, c.Grantee, int64(c.WaitPeriod), c.Status,
+			c.RequestedAt, c.GrantsAt, c.CreatedAt)
+		if err != nil {
+			return mapDBError(err)
+		}
+	}
+	for _, e := range dump.Events {
+		if e.ID == "" || e.ContactID == "" || e.Owner == "" || e.Grantee == "" {
+			return ErrMissingArgs
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, ImportEvent, e.ID, e.ContactID, e.Owner, e.Grantee, e.Action, e.CreatedAt)
+		if err != nil {
+			return mapDBError(err)
+		}
+	}
+	return nil
+}
+
+func (r *DBRepo) StoreContact(ctx context.Context, contact Contact) (string, error) {
+	if contact.Owner == "" || contact.Grantee == "" {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, StoreContact, contact.Owner, contact.Grantee, int64(contact.WaitPeriod),
+		contact.Status, contact.RequestedAt, contact.GrantsAt, contact.CreatedAt).Scan(&id)
+	if err != nil {
+		return "", mapDBError(err)
+	}
+	return id, nil
+}
+
+func (r *DBRepo) StoreEvent(ctx context.Context, event Event) error {
+	if event.ContactID == "" || event.Owner == "" || event.Grantee == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, StoreEvent, event.ContactID, event.Owner, event.Grantee, event.Action,
+		event.CreatedAt)
+	return err
+}
+
+func (r *DBRepo) UpdateStatus(ctx context.Context, contact Contact, from Status) error {
+	if contact.ID == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, UpdateStatus, contact.ID, from, contact.Status, contact.RequestedAt,
+		contact.GrantsAt)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrStatusChanged)
+}
+
+func (r *DBRepo) queryContacts(ctx context.Context, query string, args ...any) ([]Contact, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	contacts := make([]Contact, 0)
+	for rows.Next() {
+		c, sErr := r.scanContact(rows)
+		if sErr != nil {
+			return nil, sErr
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, nil
+}
+
+func (r *DBRepo) queryEvents(ctx context.Context, query string, args ...any) ([]Event, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	events := make([]Event, 0)
+	for rows.Next() {
+		var e Event
+		if err = rows.Scan(&e.ID, &e.ContactID, &e.Owner, &e.Grantee, &e.Action, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (r *DBRepo) scanContact(row interface{ Scan(dest ...any) error }) (Contact, error) {
+	var (
+		c    Contact
+		wait int64
+	)
+	if err := row.Scan(&c.ID, &c.Owner, &c.Grantee, &wait, &c.Status, &c.RequestedAt, &c.GrantsAt,
+		&c.CreatedAt); err != nil {
+		return Contact{}, err
+	}
+	c.WaitPeriod = time.Duration(wait)
+	return c, nil
+}
+
+func (r *DBRepo) checkAffected(res sql.Result, errNone error) error {
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return errNone
+	}
+	return nil
+}
+
+func (r *DBRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapDBError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrExists
+	}
+	return err
+}
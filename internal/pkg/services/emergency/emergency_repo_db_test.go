@@ -0,0 +1,246 @@
+package emergency
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+var contactColumns = []string{
+	"id", "owner", "grantee", "wait_period", "status", "requested_at", "grants_at", "created_at",
+}
+
+func TestDBRepo_Export(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dump := getTestDump()
+	events := mock.NewRows([]string{"id", "contact_id", "owner", "grantee", "action", "created_at"})
+	for _, e := range dump.Events {
+		events.AddRow(e.ID, e.ContactID, e.Owner, e.Grantee, e.Action, e.CreatedAt)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(ExportContacts)).WillReturnRows(getContactRows(mock, dump.Contacts))
+	mock.ExpectQuery(regexp.QuoteMeta(ExportEvents)).WillReturnRows(events)
+
+	got, err := r.Export(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+	checkMetExpectations(t, mock)
+}
+
+func TestDBRepo_GetContacts(t *testing.T) {
+	for _, tt := range getGetContactsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				mock.ExpectQuery(regexp.QuoteMeta(GetContacts)).WithArgs(tt.uid).
+					WillReturnRows(getContactRows(mock, tt.want))
+			}
+
+			got, err := r.GetContacts(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_GetDueContacts(t *testing.T) {
+	for _, tt := range getGetDueContactsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mock.ExpectQuery(regexp.QuoteMeta(GetDueContacts)).WithArgs(tt.t).
+				WillReturnRows(getContactRows(mock, tt.want))
+
+			got, err := r.GetDueContacts(context.Background(), tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_Import(t *testing.T) {
+	tests := []struct {
+		name    string
+		dump    Dump
+		wantErr error
+	}{
+		{
+			name:    "Contact ID is missing",
+			dump:    Dump{Contacts: []Contact{{Owner: "testOwner", Grantee: "testUser"}}},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Contact exists",
+			dump:    getTestDump(),
+			wantErr: ErrExists,
+		},
+		{
+			name: "Contacts are imported",
+			dump: getTestDump(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != ErrMissingArgs {
+				c := tt.dump.Contacts[0]
+				ee := mock.ExpectExec(regexp.QuoteMeta(ImportContact)).WithArgs(c.ID, c.Owner, c.Grantee,
+					int64(c.WaitPeriod), c.Status, c.RequestedAt, c.GrantsAt, c.CreatedAt)
+				if tt.wantErr == ErrExists {
+					ee.WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+				} else {
+					ee.WillReturnResult(sqlmock.NewResult(1, 1))
+					for range tt.dump.Contacts[1:] {
+						mock.ExpectExec(regexp.QuoteMeta(ImportContact)).WillReturnResult(sqlmock.NewResult(1, 1))
+					}
+					for _, e := range tt.dump.Events {
+						mock.ExpectExec(regexp.QuoteMeta(ImportEvent)).
+							WithArgs(e.ID, e.ContactID, e.Owner, e.Grantee, e.Action, e.CreatedAt).
+							WillReturnResult(sqlmock.NewResult(1, 1))
+					}
+				}
+			}
+
+			assert.Equal(t, tt.wantErr, r.Import(context.Background(), tt.dump))
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreContact(t *testing.T) {
+	for _, tt := range getStoreContactCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c := tt.contact
+			if c.Owner != "" && c.Grantee != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(StoreContact)).WithArgs(c.Owner, c.Grantee,
+					int64(c.WaitPeriod), c.Status, c.RequestedAt, c.GrantsAt, c.CreatedAt)
+				if tt.wantErr == ErrExists {
+					eq.WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+				} else {
+					eq.WillReturnRows(mock.NewRows([]string{"id"}).AddRow("testID3"))
+				}
+			}
+
+			got, err := r.StoreContact(context.Background(), tt.contact)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_UpdateStatus(t *testing.T) {
+	for _, tt := range getUpdateStatusCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rows int64
+			if c, ok := tt.repo[tt.contact.ID]; ok && c.Status == tt.from {
+				rows = 1
+			}
+			c := tt.contact
+			mock.ExpectExec(regexp.QuoteMeta(UpdateStatus)).
+				WithArgs(c.ID, tt.from, c.Status, c.RequestedAt, c.GrantsAt).
+				WillReturnResult(sqlmock.NewResult(0, rows))
+
+			// The conditional update cannot tell the missing contact from the changed one.
+			err = r.UpdateStatus(context.Background(), tt.contact, tt.from)
+			if tt.wantErr == ErrNotFound {
+				assert.Equal(t, ErrStatusChanged, err)
+			} else {
+				assert.Equal(t, tt.wantErr, err)
+			}
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestNewDBRepo(t *testing.T) {
+	type want struct {
+		repoType  string
+		fieldName string
+		fieldType string
+	}
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "DB is missing",
+			wantErr: true,
+			want: want{
+				repoType:  "*emergency.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: want{
+				repoType:  "*emergency.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDBRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want.repoType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.want.fieldName, rField.Name)
+			assert.Equal(t, tt.want.fieldType, rField.Type.String())
+		})
+	}
+}
+
+func getContactRows(mock sqlmock.Sqlmock, contacts []Contact) *sqlmock.Rows {
+	rows := mock.NewRows(contactColumns)
+	for _, c := range contacts {
+		rows.AddRow(c.ID, c.Owner, c.Grantee, int64(c.WaitPeriod), c.Status, c.RequestedAt, c.GrantsAt, c.CreatedAt)
+	}
+	return rows
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,288 @@
+package emergency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+// The events are kept after the contacts are removed, so they are not bound to the contacts table.
+const (
+	createSQLiteContacts = `CREATE TABLE IF NOT EXISTS emergency_contacts(
+		id TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		grantee TEXT NOT NULL,
+		wait_period INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		requested_at TIMESTAMP NOT NULL,
+		grants_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		UNIQUE(owner, grantee))`
+	createSQLiteEvents = `CREATE TABLE IF NOT EXISTS emergency_events(
+		id TEXT PRIMARY KEY,
+		contact_id TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		grantee TEXT NOT NULL,
+		action TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL)`
+	sqliteDeleteContact  = "DELETE FROM emergency_contacts WHERE id = ?1"
+	sqliteExportContacts = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts ORDER BY id
+	`
+	sqliteExportEvents = "SELECT id, contact_id, owner, grantee, action, created_at FROM emergency_events ORDER BY id"
+	sqliteGetContact   = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts WHERE id = ?1
+	`
+	sqliteGetContacts = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts WHERE owner = ?1 OR grantee = ?1 ORDER BY created_at
+	`
+	sqliteGetDueContacts = `
+		SELECT id, owner, grantee, wait_period, status, requested_at, grants_at, created_at
+		FROM emergency_contacts WHERE status = 'requested' AND grants_at <= ?1
+	`
+	sqliteGetEvents = `
+		SELECT id, contact_id, owner, grantee, action, created_at FROM emergency_events
+		WHERE owner = ?1 OR grantee = ?1 ORDER BY created_at
+	`
+	sqliteStoreContact = `
+		INSERT INTO emergency_contacts(id, owner, grantee, wait_period, status, requested_at, grants_at, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+	`
+	sqliteStoreEvent = `
+		INSERT INTO emergency_events(id, contact_id, owner, grantee, action, created_at) VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+	`
+	sqliteUpdateStatus = `
+		UPDATE emergency_contacts SET status = ?3, requested_at = ?4, grants_at = ?5 WHERE id = ?1 AND status = ?2
+	`
+)
+
+// NewSQLiteRepo returns the repository keeping the contacts and events in the SQLite database,
+// and creates its tables if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	for _, q := range []string{createSQLiteContacts, createSQLiteEvents} {
+		if _, err := db.ExecContext(context.Background(), q); err != nil {
+			return &SQLiteRepo{}, err
+		}
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteContact(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteContact, id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrNotFound)
+}
+
+// Export returns the contacts and events sorted by their IDs.
+func (r *SQLiteRepo) Export(ctx context.Context) (Dump, error) {
+	contacts, err := r.queryContacts(ctx, sqliteExportContacts)
+	if err != nil {
+		return Dump{}, err
+	}
+	events, err := r.queryEvents(ctx, sqliteExportEvents)
+	if err != nil {
+		return Dump{}, err
+	}
+	return Dump{Contacts: contacts, Events: events}, nil
+}
+
+func (r *SQLiteRepo) GetContact(ctx context.Context, id string) (Contact, error) {
+	if id == "" {
+		return Contact{}, ErrNotFound
+	}
+
+	c, err := r.scanContact(r.conn(ctx).QueryRowContext(ctx, sqliteGetContact, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Contact{}, ErrNotFound
+	}
+	return c, err
+}
+
+func (r *SQLiteRepo) GetContacts(ctx context.Context, uid string) ([]Contact, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+	return r.queryContacts(ctx, sqliteGetContacts, uid)
+}
+
+func (r *SQLiteRepo) GetDueContacts(ctx context.Context, t time.Time) ([]Contact, error) {
+	return r.queryContacts(ctx, sqliteGetDueContacts, t.UTC())
+}
+
+func (r *SQLiteRepo) GetEvents(ctx context.Context, uid string) ([]Event, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	return r.queryEvents(ctx, sqliteGetEvents, uid)
+}
+
+// Import stores the contacts and events with their IDs.
+// The events are kept after the contacts are removed, so they are imported regardless of the contacts.
+func (r *SQLiteRepo) Import(ctx context.Context, dump Dump) error {
+	for _, c := range dump.Contacts {
+		if c.ID == "" || c.Owner == "" || c.Grantee == "" {
+			return ErrMissingArgs
+		}
+		if err := r.storeContact(ctx, c); err != nil {
+			return err
+		}
+	}
+	for _, e := range dump.Events {
+		if e.ID == "" || e.ContactID == "" || e.Owner == "" || e.Grantee == "" {
+			return ErrMissingArgs
+		}
+		if err := r.storeEvent(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) StoreContact(ctx context.Context, contact Contact) (string, error) {
+	if contact.Owner == "" || contact.Grantee == "" {
+		return "", ErrMissingArgs
+	}
+
+	contact.ID = uuid.NewString()
+	if err := r.storeContact(ctx, contact); err != nil {
+		return "", err
+	}
+	return contact.ID, nil
+}
+
+func (r *SQLiteRepo) StoreEvent(ctx context.Context, event Event) error {
+	if event.ContactID == "" || event.Owner == "" || event.Grantee == "" {
+		return ErrMissingArgs
+	}
+
+	event.ID = uuid.NewString()
+	return r.storeEvent(ctx, event)
+}
+
+func (r *SQLiteRepo) UpdateStatus(ctx context.Context, contact Contact, from Status) error {
+	if contact.ID == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteUpdateStatus, contact.ID, from, contact.Status,
+		contact.RequestedAt.UTC(), contact.GrantsAt.UTC())
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrStatusChanged)
+}
+
+func (r *SQLiteRepo) storeContact(ctx context.Context, c Contact) error {
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreContact, c.ID, c.Owner, c.Grantee, int64(c.WaitPeriod), c.Status,
+		c.RequestedAt.UTC(), c.GrantsAt.UTC(), c.CreatedAt.UTC())
+	return mapSQLiteError(err)
+}
+
+func (r *SQLiteRepo) storeEvent(ctx context.Context, e Event) error {
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreEvent, e.ID, e.ContactID, e.Owner, e.Grantee, e.Action,
+		e.CreatedAt.UTC())
+	return mapSQLiteError(err)
+}
+
+func (r *SQLiteRepo) queryContacts(ctx context.Context, query string, args ...any) ([]Contact, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	contacts := make([]Contact, 0)
+	for rows.Next() {
+		c, sErr := r.scanContact(rows)
+		if sErr != nil {
+			return nil, sErr
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+func (r *SQLiteRepo) queryEvents(ctx context.Context, query string, args ...any) ([]Event, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	events := make([]Event, 0)
+	for rows.Next() {
+		var e Event
+		if err = rows.Scan(&e.ID, &e.ContactID, &e.Owner, &e.Grantee, &e.Action, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *SQLiteRepo) scanContact(row interface{ Scan(dest ...any) error }) (Contact, error) {
+	var (
+		c    Contact
+		wait int64
+	)
+	if err := row.Scan(&c.ID, &c.Owner, &c.Grantee, &wait, &c.Status, &c.RequestedAt, &c.GrantsAt,
+		&c.CreatedAt); err != nil {
+		return Contact{}, err
+	}
+	c.WaitPeriod = time.Duration(wait)
+	return c, nil
+}
+
+func (r *SQLiteRepo) checkAffected(res sql.Result, errNone error) error {
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return errNone
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey ||
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique) {
+		return ErrExists
+	}
+	return err
+}
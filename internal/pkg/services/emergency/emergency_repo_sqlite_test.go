@@ -0,0 +1,171 @@
+package emergency
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteContact(t *testing.T) {
+	r := initSQLiteRepo(t, getTestContacts())
+	assert.Equal(t, ErrNotFound, r.DeleteContact(context.Background(), "testID0"))
+	assert.NoError(t, r.DeleteContact(context.Background(), "testID"))
+
+	_, err := r.GetContact(context.Background(), "testID")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSQLiteRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	dump := getTestDump()
+
+	r := initSQLiteRepo(t, nil)
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Contacts: dump.Contacts[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Events: dump.Events[:1]}))
+	dup := dump.Contacts[0]
+	dup.ID = "testID3"
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Contacts: []Contact{dup}}))
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Contacts: []Contact{{Owner: "testOwner", Grantee: "test"}}}))
+}
+
+func TestSQLiteRepo_GetContacts(t *testing.T) {
+	for _, tt := range getGetContactsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.GetContacts(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_GetDueContacts(t *testing.T) {
+	for _, tt := range getGetDueContactsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.GetDueContacts(context.Background(), tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreContact(t *testing.T) {
+	for _, tt := range getStoreContactCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.StoreContact(context.Background(), tt.contact)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreEvent(t *testing.T) {
+	r := initSQLiteRepo(t, nil)
+	assert.Equal(t, ErrMissingArgs, r.StoreEvent(context.Background(), Event{}))
+
+	e := getTestDump().Events[0]
+	assert.NoError(t, r.StoreEvent(context.Background(), e))
+	got, err := r.GetEvents(context.Background(), e.Owner)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		e.ID = got[0].ID
+		assert.Equal(t, e, got[0])
+	}
+}
+
+func TestSQLiteRepo_UpdateStatus(t *testing.T) {
+	for _, tt := range getUpdateStatusCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+
+			// The conditional update cannot tell the missing contact from the changed one.
+			err := r.UpdateStatus(context.Background(), tt.contact, tt.from)
+			if tt.wantErr == ErrNotFound {
+				assert.Equal(t, ErrStatusChanged, err)
+				return
+			}
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			got, err := r.GetContact(context.Background(), tt.contact.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contact, got)
+		})
+	}
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*emergency.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*emergency.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, data map[string]Contact) *SQLiteRepo {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dump Dump
+	for id, c := range data {
+		c.ID = id
+		dump.Contacts = append(dump.Contacts, c)
+	}
+	if err = r.Import(context.Background(), dump); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
@@ -0,0 +1,238 @@
+package emergency
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type getContactsCase struct {
+	name    string
+	repo    map[string]Contact
+	uid     string
+	want    []Contact
+	wantErr error
+}
+
+type getDueContactsCase struct {
+	name string
+	repo map[string]Contact
+	t    time.Time
+	want []Contact
+}
+
+type storeContactCase struct {
+	name    string
+	repo    map[string]Contact
+	contact Contact
+	wantErr error
+}
+
+type updateStatusCase struct {
+	name    string
+	repo    map[string]Contact
+	contact Contact
+	from    Status
+	wantErr error
+}
+
+var testCreated = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DB is missing",
+			want: "*emergency.BasicRepo",
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*emergency.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*emergency.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRepo(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want, rGot.Type().String())
+		})
+	}
+}
+
+func initBasicRepo(data map[string]Contact) *BasicRepo {
+	contacts := &sync.Map{}
+	for id, c := range data {
+		c.ID = id
+		contacts.Store(id, c)
+	}
+	return &BasicRepo{contacts: contacts, events: &sync.Map{}, mu: &sync.Mutex{}}
+}
+
+func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	return &DBRepo{db: db}, mock, err
+}
+
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getTestContacts() map[string]Contact {
+	return map[string]Contact{
+		"testID": {
+			ID:         "testID",
+			Owner:      "testOwner",
+			Grantee:    "testUser",
+			WaitPeriod: time.Hour,
+			Status:     StatusIdle,
+			CreatedAt:  testCreated,
+		},
+		"testID1": {
+			ID:          "testID1",
+			Owner:       "testOwner",
+			Grantee:     "testUser1",
+			WaitPeriod:  time.Hour,
+			Status:      StatusRequested,
+			RequestedAt: testCreated,
+			GrantsAt:    testCreated.Add(time.Hour),
+			CreatedAt:   testCreated.Add(time.Minute),
+		},
+		"testID2": {
+			ID:         "testID2",
+			Owner:      "testUser1",
+			Grantee:    "testUser2",
+			WaitPeriod: time.Minute,
+			Status:     StatusGranted,
+			CreatedAt:  testCreated,
+		},
+	}
+}
+
+// getTestDump returns the test contacts sorted by their IDs, along with the event of each one.
+func getTestDump() Dump {
+	contacts := getTestContacts()
+	var dump Dump
+	for _, id := range []string{"testID", "testID1", "testID2"} {
+		c := contacts[id]
+		dump.Contacts = append(dump.Contacts, c)
+		dump.Events = append(dump.Events, Event{
+			ID: "event" + id, ContactID: id, Owner: c.Owner, Grantee: c.Grantee, Action: ActionDesignated,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	return dump
+}
+
+func getGetContactsCases() []getContactsCase {
+	tr := getTestContacts()
+	return []getContactsCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "No contacts for user present",
+			repo: tr,
+			uid:  "testUser0",
+			want: []Contact{},
+		},
+		{
+			name: "Owner and grantee contacts are returned",
+			repo: tr,
+			uid:  "testUser1",
+			want: []Contact{tr["testID2"], tr["testID1"]},
+		},
+	}
+}
+
+func getGetDueContactsCases() []getDueContactsCase {
+	tr := getTestContacts()
+	return []getDueContactsCase{
+		{
+			name: "Waiting period has not passed",
+			repo: tr,
+			t:    testCreated.Add(30 * time.Minute),
+			want: []Contact{},
+		},
+		{
+			name: "Waiting period has passed",
+			repo: tr,
+			t:    testCreated.Add(time.Hour),
+			want: []Contact{tr["testID1"]},
+		},
+	}
+}
+
+func getStoreContactCases() []storeContactCase {
+	return []storeContactCase{
+		{
+			name:    "No arguments passed",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Contact exists",
+			repo:    getTestContacts(),
+			contact: Contact{Owner: "testOwner", Grantee: "testUser", WaitPeriod: time.Hour, Status: StatusIdle},
+			wantErr: ErrExists,
+		},
+		{
+			name:    "Contact is stored",
+			repo:    getTestContacts(),
+			contact: Contact{Owner: "testUser", Grantee: "testOwner", WaitPeriod: time.Hour, Status: StatusIdle},
+		},
+	}
+}
+
+func getUpdateStatusCases() []updateStatusCase {
+	tr := getTestContacts()
+	requested := tr["testID"]
+	requested.Status = StatusRequested
+	requested.RequestedAt = testCreated
+	requested.GrantsAt = testCreated.Add(time.Hour)
+
+	return []updateStatusCase{
+		{
+			name:    "Contact is missing",
+			repo:    tr,
+			contact: Contact{ID: "testID0", Status: StatusRequested},
+			from:    StatusIdle,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Status has been changed",
+			repo:    tr,
+			contact: tr["testID1"],
+			from:    StatusIdle,
+			wantErr: ErrStatusChanged,
+		},
+		{
+			name:    "Status is updated",
+			repo:    tr,
+			contact: requested,
+			from:    StatusIdle,
+		},
+	}
+}
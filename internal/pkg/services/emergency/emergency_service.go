@@ -0,0 +1,256 @@
+package emergency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrInvalidStatus = errors.New("emergency access is not in the expected state")
+	ErrInvalidWait   = errors.New("emergency access waiting period must be positive")
+	ErrNotAllowed    = errors.New("the user is not allowed to manage the emergency contact")
+	ErrNotGranted    = errors.New("emergency access is not granted")
+	ErrSelfContact   = errors.New("the user cannot be their own emergency contact")
+)
+
+type IRepository interface {
+	DeleteContact(ctx context.Context, id string) error
+	Export(ctx context.Context) (Dump, error)
+	GetContact(ctx context.Context, id string) (Contact, error)
+	GetContacts(ctx context.Context, uid string) ([]Contact, error)
+	GetDueContacts(ctx context.Context, t time.Time) ([]Contact, error)
+	GetEvents(ctx context.Context, uid string) ([]Event, error)
+	Import(ctx context.Context, dump Dump) error
+	StoreContact(ctx context.Context, contact Contact) (string, error)
+	StoreEvent(ctx context.Context, event Event) error
+	UpdateStatus(ctx context.Context, contact Contact, from Status) error
+}
+
+type Service struct {
+	db IRepository
+}
+
+// NewService returns an instance of the Service with the associated repository.
+// Without the database handle, the contacts and their events are persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo}, err
+}
+
+// Export returns all emergency contacts along with the recorded events.
+func (s Service) Export(ctx context.Context) (Dump, error) {
+	return s.db.Export(ctx)
+}
+
+// Import stores the exported contacts and events with their IDs and statuses, as is.
+// Run it within the unit of work, so the failed import leaves no partial changes behind.
+func (s Service) Import(ctx context.Context, dump Dump) error {
+	return s.db.Import(ctx, dump)
+}
+
+// AddContact designates the grantee as the owner's emergency contact with the specified waiting period.
+func (s Service) AddContact(ctx context.Context, owner, grantee string, wait time.Duration) (Contact, error) {
+	if owner == grantee {
+		return Contact{}, ErrSelfContact
+	}
+	if wait <= 0 {
+		return Contact{}, ErrInvalidWait
+	}
+
+	c := Contact{Owner: owner, Grantee: grantee, WaitPeriod: wait, Status: StatusIdle, CreatedAt: time.Now().UTC()}
+	id, err := s.db.StoreContact(ctx, c)
+	if err != nil {
+		return Contact{}, err
+	}
+
+	c.ID = id
+	return c, s.record(ctx, c, ActionDesignated)
+}
+
+// ApproveRequest lets the owner grant the requested access without waiting for the period to pass.
+func (s Service) ApproveRequest(ctx context.Context, owner, id string) (Contact, error) {
+	c, err := s.getContact(ctx, id, owner, "")
+	if err != nil {
+		return Contact{}, err
+	}
+	return s.transit(ctx, c, StatusRequested, StatusGranted, ActionApproved)
+}
+
+// AuthorizeAccess returns the ID of the owner whose vault the grantee may read with the emergency access.
+// The due request is granted right away, so the access does not depend on the scheduler interval.
+func (s Service) AuthorizeAccess(ctx context.Context, grantee, id string) (string, error) {
+	c, err := s.getContact(ctx, id, "", grantee)
+	if err != nil {
+		return "", err
+	}
+
+	if c.Status == StatusRequested && !c.GrantsAt.After(time.Now().UTC()) {
+		if c, err = s.transit(ctx, c, StatusRequested, StatusGranted, ActionGranted); err != nil {
+			return "", err
+		}
+	}
+	if c.Status != StatusGranted {
+		return "", ErrNotGranted
+	}
+	return c.Owner, nil
+}
+
+// DeleteUserContacts removes the contacts where the user is either the owner or the grantee,
+// e.g. when the user account is deleted. The removal of each contact is recorded.
+func (s Service) DeleteUserContacts(ctx context.Context, uid string) error {
+	contacts, err := s.db.GetContacts(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range contacts {
+		if err = s.db.DeleteContact(ctx, c.ID); err != nil {
+			return err
+		}
+		if err = s.record(ctx, c, ActionRemoved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetContacts returns the contacts where the user is either the owner or the grantee.
+func (s Service) GetContacts(ctx context.Context, uid string) ([]Contact, error) {
+	return s.db.GetContacts(ctx, uid)
+}
+
+// GetEvents returns the recorded emergency access events of the user, both as the owner and the grantee.
+func (s Service) GetEvents(ctx context.Context, uid string) ([]Event, error) {
+	return s.db.GetEvents(ctx, uid)
+}
+
+// GrantDue grants the access for all requests with the waiting period passed by the time t.
+// The requests that change concurrently are skipped, since they are no longer due.
+func (s Service) GrantDue(ctx context.Context, t time.Time) (int, error) {
+	contacts, err := s.db.GetDueContacts(ctx, t)
+	if err != nil {
+		return 0, err
+	}
+
+	granted := 0
+	for _, c := range contacts {
+		if _, err = s.transit(ctx, c, StatusRequested, StatusGranted, ActionGranted); err != nil {
+			if errors.Is(err, ErrStatusChanged) {
+				continue
+			}
+			return granted, err
+		}
+		granted++
+	}
+	return granted, nil
+}
+
+// RejectRequest lets the owner reject the pending request or revoke the granted access.
+func (s Service) RejectRequest(ctx context.Context, owner, id string) (Contact, error) {
+	c, err := s.getContact(ctx, id, owner, "")
+	if err != nil {
+		return Contact{}, err
+	}
+
+	switch c.Status {
+	case StatusRequested:
+		return s.transit(ctx, c, StatusRequested, StatusIdle, ActionRejected)
+	case StatusGranted:
+		return s.transit(ctx, c, StatusGranted, StatusIdle, ActionRevoked)
+	default:
+		return Contact{}, ErrInvalidStatus
+	}
+}
+
+// RemoveContact deletes the contact on behalf of either the owner or the grantee.
+func (s Service) RemoveContact(ctx context.Context, uid, id string) error {
+	c, err := s.db.GetContact(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.Owner != uid && c.Grantee != uid {
+		return ErrNotAllowed
+	}
+
+	if err = s.db.DeleteContact(ctx, id); err != nil {
+		return err
+	}
+	return s.record(ctx, c, ActionRemoved)
+}
+
+// RequestAccess starts the waiting period of the grantee's request.
+func (s Service) RequestAccess(ctx context.Context, grantee, id string) (Contact, error) {
+	c, err := s.getContact(ctx, id, "", grantee)
+	if err != nil {
+		return Contact{}, err
+	}
+	return s.transit(ctx, c, StatusIdle, StatusRequested, ActionRequested)
+}
+
+// RunScheduler grants the due requests every interval until the context is canceled.
+func (s Service) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			n, err := s.GrantDue(ctx, t.UTC())
+			if err != nil {
+				log.Error(err)
+			}
+			if n > 0 {
+				log.Infof("emergency access granted to %d contact(s)", n)
+			}
+		}
+	}
+}
+
+func (s Service) getContact(ctx context.Context, id, owner, grantee string) (Contact, error) {
+	c, err := s.db.GetContact(ctx, id)
+	if err != nil {
+		return Contact{}, err
+	}
+	if (owner != "" && c.Owner != owner) || (grantee != "" && c.Grantee != grantee) {
+		return Contact{}, ErrNotAllowed
+	}
+	return c, nil
+}
+
+func (s Service) record(ctx context.Context, c Contact, action Action) error {
+	return s.db.StoreEvent(ctx, Event{
+		ContactID: c.ID,
+		Owner:     c.Owner,
+		Grantee:   c.Grantee,
+		Action:    action,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (s Service) transit(ctx context.Context, c Contact, from, to Status, action Action) (Contact, error) {
+	if c.Status != from {
+		return Contact{}, ErrInvalidStatus
+	}
+
+	c.Status = to
+	switch to {
+	case StatusRequested:
+		c.RequestedAt = time.Now().UTC()
+		c.GrantsAt = c.RequestedAt.Add(c.WaitPeriod)
+	case StatusIdle:
+		c.RequestedAt, c.GrantsAt = time.Time{}, time.Time{}
+	}
+
+	if err := s.db.UpdateStatus(ctx, c, from); err != nil {
+		return Contact{}, err
+	}
+	return c, s.record(ctx, c, action)
+}
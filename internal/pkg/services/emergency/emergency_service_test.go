@@ -0,0 +1,259 @@
+package emergency
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *sql.DB
+		wantRepoType string
+		wantErr      bool
+	}{
+		{
+			name:         "DB is missing",
+			wantRepoType: "*emergency.BasicRepo",
+		},
+		{
+			name:         "DB is present",
+			db:           initDB(t),
+			wantRepoType: "*emergency.DBRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rRepo := reflect.ValueOf(got.db)
+			assert.Equal(t, tt.wantRepoType, rRepo.Type().String())
+		})
+	}
+}
+
+func TestService_AddContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		grantee string
+		wait    time.Duration
+		wantErr error
+	}{
+		{
+			name:    "Owner is the grantee",
+			grantee: "testOwner",
+			wait:    time.Hour,
+			wantErr: ErrSelfContact,
+		},
+		{
+			name:    "No waiting period",
+			grantee: "testUser",
+			wantErr: ErrInvalidWait,
+		},
+		{
+			name:    "Contact is added",
+			grantee: "testUser",
+			wait:    time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(nil)}
+			c, err := s.AddContact(context.Background(), "testOwner", tt.grantee, tt.wait)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, StatusIdle, c.Status)
+			assertActions(t, s, "testOwner", ActionDesignated)
+		})
+	}
+}
+
+func TestService_AuthorizeAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		wait    time.Duration
+		grantee string
+		request bool
+		wantErr error
+	}{
+		{
+			name:    "Another user",
+			wait:    time.Hour,
+			grantee: "testUser1",
+			wantErr: ErrNotAllowed,
+		},
+		{
+			name:    "Access is not requested",
+			wait:    time.Hour,
+			grantee: "testUser",
+			wantErr: ErrNotGranted,
+		},
+		{
+			name:    "Waiting period has not passed",
+			wait:    time.Hour,
+			grantee: "testUser",
+			request: true,
+			wantErr: ErrNotGranted,
+		},
+		{
+			name:    "Waiting period has passed",
+			wait:    time.Nanosecond,
+			grantee: "testUser",
+			request: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(nil)}
+			c, err := s.AddContact(context.Background(), "testOwner", "testUser", tt.wait)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.request {
+				if _, err = s.RequestAccess(context.Background(), "testUser", c.ID); err != nil {
+					t.Fatal(err)
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			got, err := s.AuthorizeAccess(context.Background(), tt.grantee, c.ID)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, "testOwner", got)
+			}
+		})
+	}
+}
+
+func TestService_DeleteUserContacts(t *testing.T) {
+	s := Service{db: initBasicRepo(getTestContacts())}
+	assert.Equal(t, ErrMissingArgs, s.DeleteUserContacts(context.Background(), ""))
+	assert.NoError(t, s.DeleteUserContacts(context.Background(), "testUser1"))
+
+	for _, uid := range []string{"testUser1", "testUser2"} {
+		contacts, err := s.GetContacts(context.Background(), uid)
+		assert.NoError(t, err)
+		assert.Empty(t, contacts)
+	}
+	contacts, err := s.GetContacts(context.Background(), "testOwner")
+	assert.NoError(t, err)
+	assert.Len(t, contacts, 1)
+	assertActions(t, s, "testUser1", ActionRemoved, ActionRemoved)
+}
+
+func TestService_GrantDue(t *testing.T) {
+	s := Service{db: initBasicRepo(getTestContacts())}
+
+	n, err := s.GrantDue(context.Background(), testCreated.Add(30*time.Minute))
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = s.GrantDue(context.Background(), testCreated.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	owner, err := s.AuthorizeAccess(context.Background(), "testUser1", "testID1")
+	assert.NoError(t, err)
+	assert.Equal(t, "testOwner", owner)
+	assertActions(t, s, "testUser1", ActionGranted)
+}
+
+func TestService_RejectRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		owner   string
+		want    Action
+		wantErr error
+	}{
+		{
+			name:    "Another user",
+			id:      "testID1",
+			owner:   "testUser1",
+			wantErr: ErrNotAllowed,
+		},
+		{
+			name:    "Access is not requested",
+			id:      "testID",
+			owner:   "testOwner",
+			wantErr: ErrInvalidStatus,
+		},
+		{
+			name:  "Request is rejected",
+			id:    "testID1",
+			owner: "testOwner",
+			want:  ActionRejected,
+		},
+		{
+			name:  "Granted access is revoked",
+			id:    "testID2",
+			owner: "testUser1",
+			want:  ActionRevoked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(getTestContacts())}
+			c, err := s.RejectRequest(context.Background(), tt.owner, tt.id)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, StatusIdle, c.Status)
+			assert.True(t, c.GrantsAt.IsZero())
+			assertActions(t, s, c.Grantee, tt.want)
+		})
+	}
+}
+
+func TestService_RemoveContact(t *testing.T) {
+	s := Service{db: initBasicRepo(getTestContacts())}
+	assert.Equal(t, ErrNotAllowed, s.RemoveContact(context.Background(), "testUser2", "testID"))
+	assert.NoError(t, s.RemoveContact(context.Background(), "testUser", "testID"))
+
+	contacts, err := s.GetContacts(context.Background(), "testUser")
+	assert.NoError(t, err)
+	assert.Empty(t, contacts)
+	assertActions(t, s, "testUser", ActionRemoved)
+}
+
+func TestService_RequestAccess(t *testing.T) {
+	s := Service{db: initBasicRepo(getTestContacts())}
+
+	_, err := s.RequestAccess(context.Background(), "testOwner", "testID")
+	assert.Equal(t, ErrNotAllowed, err)
+	_, err = s.RequestAccess(context.Background(), "testUser1", "testID1")
+	assert.Equal(t, ErrInvalidStatus, err)
+
+	c, err := s.RequestAccess(context.Background(), "testUser", "testID")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusRequested, c.Status)
+	assert.Equal(t, c.RequestedAt.Add(time.Hour), c.GrantsAt)
+	assertActions(t, s, "testUser", ActionRequested)
+
+	c, err = s.ApproveRequest(context.Background(), "testOwner", "testID")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusGranted, c.Status)
+	assertActions(t, s, "testUser", ActionRequested, ActionApproved)
+}
+
+func assertActions(t *testing.T, s Service, uid string, want ...Action) {
+	events, err := s.GetEvents(context.Background(), uid)
+	assert.NoError(t, err)
+
+	got := make([]Action, 0, len(events))
+	for _, e := range events {
+		got = append(got, e.Action)
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,26 @@
+package item
+
+import "github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+
+// Item is the decrypted item of any type, holding its field values keyed by the field names.
+type Item struct {
+	UID      string
+	ID       string
+	Type     data.StorageType
+	Fields   map[string]string
+	Custom   []CustomField
+	Shared   bool
+	ReadOnly bool
+}
+
+// CustomField is the extra field of the item. It's stored in the item payload under the "custom" key.
+type CustomField struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// customPayload decodes the custom fields of the stored payload.
+type customPayload struct {
+	Custom []CustomField `json:"custom"`
+}
@@ -0,0 +1,156 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+type Service struct {
+	dataService data.Service
+}
+
+var (
+	ErrInvalid  = errors.New("passed item data is invalid")
+	ErrNotFound = errors.New("requested item data not found")
+)
+
+// NewService returns an instance of the Service with pre-defined data microservice.
+func NewService(dataService data.Service) Service {
+	return Service{dataService: dataService}
+}
+
+// DeleteItem removes the stored item of the type with the unique ID.
+// The method removes the item of the specified user only.
+func (s Service) DeleteItem(ctx context.Context, uid, id string, t data.StorageType) error {
+	if _, err := s.getData(ctx, uid, id, t); err != nil {
+		return err
+	}
+
+	err := s.dataService.DeleteSecureData(ctx, uid, id)
+	if errors.Is(err, data.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// GetAllItems returns all the user's stored items of the type.
+func (s Service) GetAllItems(ctx context.Context, uid string, t data.StorageType) ([]Item, error) {
+	if uid == "" {
+		return nil, ErrNotFound
+	}
+
+	sd, err := s.dataService.GetAllDataByType(ctx, uid, t)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(sd))
+	for _, d := range sd {
+		item, dErr := s.getItemFromSecureData(uid, d)
+		if dErr != nil {
+			return nil, dErr
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetItemByID returns the stored item of the type by the unique ID.
+// The method returns the item of the specified user only.
+func (s Service) GetItemByID(ctx context.Context, uid, id string, t data.StorageType) (Item, error) {
+	d, err := s.getData(ctx, uid, id, t)
+	if err != nil {
+		return Item{}, err
+	}
+	return s.getItemFromSecureData(uid, d)
+}
+
+// StoreItem stores the original item via the associated data microservice.
+func (s Service) StoreItem(ctx context.Context, item Item) (string, error) {
+	return s.dataService.StoreSecureDataFromPayload(ctx, item.UID, Payload(item), item.Type)
+}
+
+// UpdateItem replaces the stored item via the associated data microservice.
+// The item shared with the user can be updated only if it isn't shared as read-only.
+func (s Service) UpdateItem(ctx context.Context, item Item) error {
+	err := s.dataService.UpdateSecureDataFromPayload(ctx, item.UID, item.ID, Payload(item), item.Type)
+	if errors.Is(err, data.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// getData returns the stored data, if it holds the item of the type.
+func (s Service) getData(ctx context.Context, uid, id string, t data.StorageType) (data.SecureData, error) {
+	if uid == "" || id == "" {
+		return data.SecureData{}, ErrNotFound
+	}
+
+	d, err := s.dataService.GetDataByID(ctx, uid, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return data.SecureData{}, ErrNotFound
+		}
+		return data.SecureData{}, err
+	}
+	if d.Type != t {
+		return data.SecureData{}, ErrNotFound
+	}
+	return d, nil
+}
+
+// getItemFromSecureData decrypts the payload of the item.
+// The payloads stored by the earlier versions hold the item ID as well, so the non-string values are skipped.
+func (s Service) getItemFromSecureData(uid string, d data.SecureData) (Item, error) {
+	if len(d.Data) == 0 {
+		return Item{}, ErrInvalid
+	}
+
+	b, err := s.dataService.DecryptSecureData(uid, d)
+	if err != nil {
+		return Item{}, err
+	}
+
+	var payload map[string]any
+	if err = json.Unmarshal(b, &payload); err != nil {
+		return Item{}, err
+	}
+	var custom customPayload
+	if err = json.Unmarshal(b, &custom); err != nil {
+		return Item{}, err
+	}
+
+	fields := make(map[string]string, len(payload))
+	for k, v := range payload {
+		if str, ok := v.(string); ok && k != "id" {
+			fields[k] = str
+		}
+	}
+	return Item{
+		UID:      d.UID,
+		ID:       d.ID,
+		Type:     d.Type,
+		Fields:   fields,
+		Custom:   custom.Custom,
+		Shared:   d.Shared,
+		ReadOnly: d.ReadOnly,
+	}, nil
+}
+
+// Payload returns the fields of the item along with its custom fields, if there are any, as they are stored.
+func Payload(item Item) map[string]any {
+	payload := make(map[string]any, len(item.Fields)+1)
+	for k, v := range item.Fields {
+		payload[k] = v
+	}
+	if len(item.Custom) > 0 {
+		payload["custom"] = item.Custom
+	}
+	return payload
+}
@@ -0,0 +1,289 @@
+package item
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+func TestNewService(t *testing.T) {
+	bds := initBasicDataService(t)
+	assert.Equal(t, Service{dataService: bds}, NewService(bds))
+}
+
+func TestService_DeleteItem(t *testing.T) {
+	type args struct {
+		uid string
+		id  string
+		t   data.StorageType
+	}
+	tests := []struct {
+		name    string
+		repo    map[string]Item
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "ID is empty",
+			args:    args{uid: "test"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "User ID is empty",
+			args:    args{id: "test"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data is not present",
+			repo:    map[string]Item{"test1": {UID: "test1", Type: data.SCard}},
+			args:    args{uid: "test", id: "test", t: data.SCard},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data is of another type",
+			repo:    map[string]Item{"test": {UID: "test", Type: data.SText}},
+			args:    args{uid: "test", id: "test", t: data.SCard},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Data is present and deleted",
+			repo: map[string]Item{"test": {UID: "test", Type: data.SCard}},
+			args: args{uid: "test", id: "test", t: data.SCard},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids := initService(t, tt.repo)
+			if v, ok := ids[tt.args.id]; ok {
+				tt.args.id = v.ID
+			}
+
+			err := s.DeleteItem(context.Background(), tt.args.uid, tt.args.id, tt.args.t)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_GetAllItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		repo    map[string]Item
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "Missing UID",
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "No data",
+			uid:  "test1",
+			repo: map[string]Item{"test": {UID: "test", Type: data.SCard}},
+			want: []string{},
+		},
+		{
+			name: "Data found",
+			uid:  "test",
+			repo: map[string]Item{
+				"test":  {UID: "test", Type: data.SCard, Fields: map[string]string{"name": "test"}},
+				"text":  {UID: "test", Type: data.SText, Fields: map[string]string{"name": "text"}},
+				"test1": {UID: "test1", Type: data.SCard, Fields: map[string]string{"name": "test1"}},
+			},
+			want: []string{"test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initService(t, tt.repo)
+			got, err := s.GetAllItems(context.Background(), tt.uid, data.SCard)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			names := make([]string, 0, len(got))
+			for _, item := range got {
+				names = append(names, item.Fields["name"])
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestService_GetItemByID(t *testing.T) {
+	type args struct {
+		uid string
+		id  string
+		t   data.StorageType
+	}
+	tests := []struct {
+		name    string
+		args    args
+		repo    map[string]Item
+		want    Item
+		wantErr error
+	}{
+		{
+			name:    "Missing arguments",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Missing UID",
+			args:    args{id: "test"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Missing ID",
+			args:    args{uid: "test"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "No data",
+			args:    args{uid: "test", id: "test", t: data.SText},
+			repo:    map[string]Item{"test1": {UID: "test1", Type: data.SText}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data is of another type",
+			args:    args{uid: "test", id: "test", t: data.SCard},
+			repo:    map[string]Item{"test": {UID: "test", Type: data.SText}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Data found",
+			args: args{uid: "test", id: "test", t: data.SText},
+			repo: map[string]Item{"test": {UID: "test", Type: data.SText, Fields: map[string]string{"data": "test"}}},
+			want: Item{UID: "test", Type: data.SText, Fields: map[string]string{"data": "test"}},
+		},
+		{
+			name: "Data with custom fields found",
+			args: args{uid: "test", id: "test", t: data.SCustom},
+			repo: map[string]Item{"test": {
+				UID:    "test",
+				Type:   data.SCustom,
+				Fields: map[string]string{"name": "db"},
+				Custom: []CustomField{{Name: "host", Kind: "url", Value: "https://db"}},
+			}},
+			want: Item{
+				UID:    "test",
+				Type:   data.SCustom,
+				Fields: map[string]string{"name": "db"},
+				Custom: []CustomField{{Name: "host", Kind: "url", Value: "https://db"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids := initService(t, tt.repo)
+			if v, ok := ids[tt.args.id]; ok {
+				tt.args.id = v.ID
+				if tt.wantErr == nil {
+					tt.want.ID = v.ID
+				}
+			}
+
+			got, err := s.GetItemByID(context.Background(), tt.args.uid, tt.args.id, tt.args.t)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_UpdateItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    map[string]Item
+		item    Item
+		wantErr error
+	}{
+		{
+			name:    "Data of another user",
+			repo:    map[string]Item{"test1": {UID: "test1", Type: data.SPassword}},
+			item:    Item{UID: "test", ID: "test1", Type: data.SPassword, Fields: map[string]string{"name": "test2"}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data is of another type",
+			repo:    map[string]Item{"test": {UID: "test", Type: data.SPassword}},
+			item:    Item{UID: "test", ID: "test", Type: data.SCard, Fields: map[string]string{"name": "test2"}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Data is updated",
+			repo: map[string]Item{"test": {UID: "test", Type: data.SPassword}},
+			item: Item{UID: "test", ID: "test", Type: data.SPassword, Fields: map[string]string{"name": "test2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids := initService(t, tt.repo)
+			if v, ok := ids[tt.item.ID]; ok {
+				tt.item.ID = v.ID
+			}
+
+			err := s.UpdateItem(context.Background(), tt.item)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				got, gErr := s.GetItemByID(context.Background(), tt.item.UID, tt.item.ID, tt.item.Type)
+				assert.NoError(t, gErr)
+				assert.Equal(t, tt.item.Fields, got.Fields)
+			}
+		})
+	}
+}
+
+func TestService_getItemFromSecureData(t *testing.T) {
+	s, _ := initService(t, nil)
+	got, err := s.getItemFromSecureData("test", data.SecureData{UID: "test", ID: "test"})
+	assert.Equal(t, Item{}, got)
+	assert.Equal(t, ErrInvalid, err)
+
+	// The earlier versions stored the typed payloads, holding the item ID and non-string values.
+	id, err := s.dataService.StoreSecureDataFromPayload(context.Background(), "test", struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Data []byte `json:"data"`
+		Size int    `json:"size"`
+	}{ID: "old", Name: "test", Data: []byte("test"), Size: 4}, data.SBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.GetItemByID(context.Background(), "test", id, data.SBinary)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "test", "data": "dGVzdA=="}, got.Fields)
+}
+
+func initService(t *testing.T, repo map[string]Item) (Service, map[string]Item) {
+	t.Helper()
+	s := Service{dataService: initBasicDataService(t)}
+	newRepo := make(map[string]Item, len(repo))
+	for iid, v := range repo {
+		if v.Fields == nil {
+			v.Fields = map[string]string{}
+		}
+		id, err := s.StoreItem(context.Background(), v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.ID = id
+		newRepo[iid] = v
+	}
+	return s, newRepo
+}
+
+func initBasicDataService(t *testing.T) data.Service {
+	t.Helper()
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
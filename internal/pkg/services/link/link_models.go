@@ -0,0 +1,16 @@
+package link
+
+import "time"
+
+// Link is the one-time share of the secret encrypted with the key that isn't stored.
+// The link gets deleted once it's viewed MaxViews times or expires.
+type Link struct {
+	ID        string    `json:"id"`
+	UID       string    `json:"-"`
+	Name      string    `json:"name"`
+	Data      []byte    `json:"-"`
+	MaxViews  int       `json:"max_views"`
+	Views     int       `json:"views"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
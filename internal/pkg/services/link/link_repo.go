@@ -0,0 +1,29 @@
+package link
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrDBMissing   = errors.New("link db is missing")
+	ErrMissingArgs = errors.New("user id, link name or data is not specified")
+	ErrNotFound    = errors.New("link not found")
+)
+
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
+		return NewBasicRepo(), nil
+	}
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
+}
@@ -0,0 +1,104 @@
+package link
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BasicRepo struct {
+	links *sync.Map
+	mu    *sync.Mutex
+}
+
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{links: &sync.Map{}, mu: &sync.Mutex{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[Link](w, "links", r.links); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) ConsumeLink(ctx context.Context, id string, t time.Time) (Link, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.links.Load(id)
+	if !ok {
+		return Link{}, ErrNotFound
+	}
+
+	l := v.(Link)
+	if l.Views >= l.MaxViews || !l.ExpiresAt.After(t) {
+		return Link{}, ErrNotFound
+	}
+
+	l.Views++
+	if err := storage.Store(ctx, r.links, id, l); err != nil {
+		return Link{}, err
+	}
+	return l, nil
+}
+
+func (r *BasicRepo) DeleteExpiredLinks(ctx context.Context, t time.Time) error {
+	var err error
+	r.links.Range(func(k, v any) bool {
+		if l := v.(Link); l.Views >= l.MaxViews || !l.ExpiresAt.After(t) {
+			_, err = storage.Delete(ctx, r.links, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (r *BasicRepo) DeleteLink(ctx context.Context, uid, id string) error {
+	if l, ok := r.links.Load(id); ok && l.(Link).UID == uid {
+		_, err := storage.Delete(ctx, r.links, id)
+		return err
+	}
+	return ErrNotFound
+}
+
+func (r *BasicRepo) GetUserLinks(_ context.Context, uid string) ([]Link, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	links := make([]Link, 0)
+	r.links.Range(func(_, v any) bool {
+		if l := v.(Link); l.UID == uid {
+			links = append(links, l)
+		}
+		return true
+	})
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].CreatedAt.Before(links[j].CreatedAt)
+	})
+	return links, nil
+}
+
+func (r *BasicRepo) StoreLink(ctx context.Context, link Link) (string, error) {
+	if link.UID == "" || link.Name == "" || link.Data == nil {
+		return "", ErrMissingArgs
+	}
+
+	link.ID = uuid.NewString()
+	if err := storage.Store(ctx, r.links, link.ID, link); err != nil {
+		return "", err
+	}
+	return link.ID, nil
+}
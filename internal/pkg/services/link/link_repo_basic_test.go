@@ -0,0 +1,129 @@
+package link
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBasicRepo_ConsumeLink(t *testing.T) {
+	for _, tt := range getConsumeLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.ConsumeLink(context.Background(), tt.id, testCreated.Add(30*time.Minute))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_DeleteExpiredLinks(t *testing.T) {
+	r := initBasicRepo(getTestLinks())
+	assert.NoError(t, r.DeleteExpiredLinks(context.Background(), testCreated.Add(30*time.Minute)))
+
+	for id, want := range map[string]bool{"testID": true, "testID1": false, "testID2": false} {
+		_, ok := r.links.Load(id)
+		assert.Equal(t, want, ok, id)
+	}
+}
+
+func TestBasicRepo_DeleteLink(t *testing.T) {
+	for _, tt := range getDeleteLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			err := r.DeleteLink(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_GetUserLinks(t *testing.T) {
+	for _, tt := range getGetUserLinksCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetUserLinks(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_StoreLink(t *testing.T) {
+	for _, tt := range getStoreLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil)
+			got, err := r.StoreLink(context.Background(), tt.link)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestNewBasicRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantField     string
+		wantFieldType string
+		wantType      string
+	}{
+		{
+			name:          "Basic repo is created",
+			wantField:     "links",
+			wantFieldType: "*sync.Map",
+			wantType:      "*link.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBasicRepo()
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.wantType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.wantField, rField.Name)
+			assert.Equal(t, tt.wantFieldType, rField.Type.String())
+		})
+	}
+}
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := Link{UID: "testUser", Name: "wifi", Data: []byte("data"), MaxViews: 2, CreatedAt: created,
+		ExpiresAt: created.Add(time.Hour)}
+
+	r := initPersistentRepo(t, dir)
+	id, err := r.StoreLink(ctx, l)
+	assert.NoError(t, err)
+	id1, err := r.StoreLink(ctx, Link{UID: "testUser", Name: "door", Data: []byte("data1"), CreatedAt: created})
+	assert.NoError(t, err)
+	assert.NoError(t, r.DeleteLink(ctx, "testUser", id1))
+	_, err = r.ConsumeLink(ctx, id, created)
+	assert.NoError(t, err)
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetUserLinks(ctx, "testUser")
+	assert.NoError(t, err)
+	l.ID, l.Views = id, 1
+	assert.Equal(t, []Link{l}, got)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "links", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
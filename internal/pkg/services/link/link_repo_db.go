@@ -0,0 +1,130 @@
+package link
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type DBRepo struct {
+	db *sql.DB
+}
+
+const (
+	ConsumeLink = `
+		UPDATE share_links SET views = views + 1
+		WHERE id = $1 AND views < max_views AND expires_at > $2
+		RETURNING id, uid, name, data, max_views, views, created_at, expires_at
+	`
+	DeleteExpiredLinks = "DELETE FROM share_links WHERE views >= max_views OR expires_at <= $1"
+	DeleteLink         = "DELETE FROM share_links WHERE uid = $1 AND id = $2"
+	GetUserLinks       = `
+		SELECT id, uid, name, max_views, views, created_at, expires_at FROM share_links
+		WHERE uid = $1 ORDER BY created_at
+	`
+	StoreLink = `
+		INSERT INTO share_links(uid, name, data, max_views, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+	`
+)
+
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
+	}
+	return &DBRepo{db: db}, nil
+}
+
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *DBRepo) ConsumeLink(ctx context.Context, id string, t time.Time) (Link, error) {
+	if id == "" {
+		return Link{}, ErrNotFound
+	}
+
+	var l Link
+	err := r.conn(ctx).QueryRowContext(ctx, ConsumeLink, id, t).Scan(&l.ID, &l.UID, &l.Name, &l.Data, &l.MaxViews,
+		&l.Views, &l.CreatedAt, &l.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Link{}, ErrNotFound
+		}
+		return Link{}, err
+	}
+	return l, nil
+}
+
+func (r *DBRepo) DeleteExpiredLinks(ctx context.Context, t time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteExpiredLinks, t)
+	return err
+}
+
+func (r *DBRepo) DeleteLink(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteLink, uid, id)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *DBRepo) GetUserLinks(ctx context.Context, uid string) ([]Link, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetUserLinks, uid)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	links := make([]Link, 0)
+	for rows.Next() {
+		var l Link
+		if err = rows.Scan(&l.ID, &l.UID, &l.Name, &l.MaxViews, &l.Views, &l.CreatedAt, &l.ExpiresAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+func (r *DBRepo) StoreLink(ctx context.Context, link Link) (string, error) {
+	if link.UID == "" || link.Name == "" || link.Data == nil {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, StoreLink, link.UID, link.Name, link.Data, link.MaxViews, link.CreatedAt,
+		link.ExpiresAt).Scan(&id)
+	return id, err
+}
+
+func (r *DBRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
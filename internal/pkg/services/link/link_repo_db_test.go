@@ -0,0 +1,167 @@
+package link
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBRepo_ConsumeLink(t *testing.T) {
+	for _, tt := range getConsumeLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			now := testCreated.Add(30 * time.Minute)
+			if tt.id != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(ConsumeLink)).WithArgs(tt.id, now)
+				if tt.want.ID != "" {
+					eq.WillReturnRows(mock.NewRows([]string{
+						"id", "uid", "name", "data", "max_views", "views", "created_at", "expires_at",
+					}).AddRow(tt.want.ID, tt.want.UID, tt.want.Name, tt.want.Data, tt.want.MaxViews, tt.want.Views,
+						tt.want.CreatedAt, tt.want.ExpiresAt))
+				} else {
+					eq.WillReturnError(sql.ErrNoRows)
+				}
+			}
+
+			got, err := r.ConsumeLink(context.Background(), tt.id, now)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_DeleteLink(t *testing.T) {
+	for _, tt := range getDeleteLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.args.uid != "" && tt.args.id != "" {
+				var rows int64
+				if tt.repo[tt.args.id].UID == tt.args.uid {
+					rows = 1
+				}
+				mock.ExpectExec(regexp.QuoteMeta(DeleteLink)).WithArgs(tt.args.uid, tt.args.id).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.DeleteLink(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_GetUserLinks(t *testing.T) {
+	for _, tt := range getGetUserLinksCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := tt.want
+			if tt.uid != "" {
+				rows := mock.NewRows([]string{"id", "uid", "name", "max_views", "views", "created_at", "expires_at"})
+				for i, l := range tt.want {
+					rows.AddRow(l.ID, l.UID, l.Name, l.MaxViews, l.Views, l.CreatedAt, l.ExpiresAt)
+					want[i].Data = nil
+				}
+				mock.ExpectQuery(regexp.QuoteMeta(GetUserLinks)).WithArgs(tt.uid).WillReturnRows(rows)
+			}
+
+			got, err := r.GetUserLinks(context.Background(), tt.uid)
+			assert.Equal(t, want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreLink(t *testing.T) {
+	for _, tt := range getStoreLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l := tt.link
+			if l.UID != "" && l.Name != "" && l.Data != nil {
+				mock.ExpectQuery(regexp.QuoteMeta(StoreLink)).
+					WithArgs(l.UID, l.Name, l.Data, l.MaxViews, l.CreatedAt, l.ExpiresAt).
+					WillReturnRows(mock.NewRows([]string{"id"}).AddRow("testID3"))
+			}
+
+			got, err := r.StoreLink(context.Background(), tt.link)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestNewDBRepo(t *testing.T) {
+	type want struct {
+		repoType  string
+		fieldName string
+		fieldType string
+	}
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "DB is missing",
+			wantErr: true,
+			want: want{
+				repoType:  "*link.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: want{
+				repoType:  "*link.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDBRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want.repoType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.want.fieldName, rField.Name)
+			assert.Equal(t, tt.want.fieldType, rField.Type.String())
+		})
+	}
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,143 @@
+package link
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteShareLinks = `CREATE TABLE IF NOT EXISTS share_links(
+		id TEXT PRIMARY KEY,
+		uid TEXT NOT NULL,
+		name TEXT NOT NULL,
+		data BLOB NOT NULL,
+		max_views INTEGER NOT NULL,
+		views INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		expires_at TIMESTAMP NOT NULL)`
+	sqliteConsumeLink = `
+		UPDATE share_links SET views = views + 1
+		WHERE id = ?1 AND views < max_views AND expires_at > ?2
+		RETURNING id, uid, name, data, max_views, views, created_at, expires_at
+	`
+	sqliteDeleteExpiredLinks = "DELETE FROM share_links WHERE views >= max_views OR expires_at <= ?1"
+	sqliteDeleteLink         = "DELETE FROM share_links WHERE uid = ?1 AND id = ?2"
+	sqliteGetUserLinks       = `
+		SELECT id, uid, name, max_views, views, created_at, expires_at FROM share_links
+		WHERE uid = ?1 ORDER BY created_at
+	`
+	sqliteStoreLink = `
+		INSERT INTO share_links(id, uid, name, data, max_views, created_at, expires_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	`
+)
+
+// NewSQLiteRepo returns the repository keeping the links in the SQLite database, and creates its table if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	if _, err := db.ExecContext(context.Background(), createSQLiteShareLinks); err != nil {
+		return &SQLiteRepo{}, err
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) ConsumeLink(ctx context.Context, id string, t time.Time) (Link, error) {
+	if id == "" {
+		return Link{}, ErrNotFound
+	}
+
+	var l Link
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteConsumeLink, id, t.UTC()).Scan(&l.ID, &l.UID, &l.Name, &l.Data,
+		&l.MaxViews, &l.Views, &l.CreatedAt, &l.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Link{}, ErrNotFound
+		}
+		return Link{}, err
+	}
+	return l, nil
+}
+
+func (r *SQLiteRepo) DeleteExpiredLinks(ctx context.Context, t time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteExpiredLinks, t.UTC())
+	return err
+}
+
+func (r *SQLiteRepo) DeleteLink(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteLink, uid, id)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) GetUserLinks(ctx context.Context, uid string) ([]Link, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteGetUserLinks, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	links := make([]Link, 0)
+	for rows.Next() {
+		var l Link
+		if err = rows.Scan(&l.ID, &l.UID, &l.Name, &l.MaxViews, &l.Views, &l.CreatedAt, &l.ExpiresAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+func (r *SQLiteRepo) StoreLink(ctx context.Context, link Link) (string, error) {
+	if link.UID == "" || link.Name == "" || link.Data == nil {
+		return "", ErrMissingArgs
+	}
+
+	link.ID = uuid.NewString()
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreLink, link.ID, link.UID, link.Name, link.Data, link.MaxViews,
+		link.CreatedAt.UTC(), link.ExpiresAt.UTC())
+	if err != nil {
+		return "", err
+	}
+	return link.ID, nil
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
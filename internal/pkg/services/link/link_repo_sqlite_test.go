@@ -0,0 +1,149 @@
+package link
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const testImportLink = `
+	INSERT INTO share_links(id, uid, name, data, max_views, views, created_at, expires_at)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+`
+
+func TestSQLiteRepo_ConsumeLink(t *testing.T) {
+	for _, tt := range getConsumeLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.ConsumeLink(context.Background(), tt.id, testCreated.Add(30*time.Minute))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_DeleteExpiredLinks(t *testing.T) {
+	r := initSQLiteRepo(t, getTestLinks())
+	assert.NoError(t, r.DeleteExpiredLinks(context.Background(), testCreated.Add(30*time.Minute)))
+
+	got, err := r.GetUserLinks(context.Background(), "testUser")
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "testID", got[0].ID)
+
+	got, err = r.GetUserLinks(context.Background(), "testUser1")
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestSQLiteRepo_DeleteLink(t *testing.T) {
+	for _, tt := range getDeleteLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			err := r.DeleteLink(context.Background(), tt.args.uid, tt.args.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_GetUserLinks(t *testing.T) {
+	for _, tt := range getGetUserLinksCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+
+			want := tt.want
+			for i := range want {
+				want[i].Data = nil
+			}
+
+			got, err := r.GetUserLinks(context.Background(), tt.uid)
+			assert.Equal(t, want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreLink(t *testing.T) {
+	for _, tt := range getStoreLinkCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, nil)
+			got, err := r.StoreLink(context.Background(), tt.link)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+
+			if err == nil {
+				l, cErr := r.ConsumeLink(context.Background(), got, testCreated)
+				assert.NoError(t, cErr)
+				assert.Equal(t, tt.link.Data, l.Data)
+			}
+		})
+	}
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*link.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*link.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+// initSQLiteRepo stores the links with their IDs and views count, as is.
+func initSQLiteRepo(t *testing.T, data map[string]Link) *SQLiteRepo {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, l := range data {
+		_, err = r.db.ExecContext(context.Background(), testImportLink, id, l.UID, l.Name, l.Data, l.MaxViews, l.Views,
+			l.CreatedAt, l.ExpiresAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
@@ -0,0 +1,238 @@
+package link
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type consumeLinkCase struct {
+	name    string
+	repo    map[string]Link
+	id      string
+	want    Link
+	wantErr error
+}
+
+type deleteLinkArgs struct {
+	uid string
+	id  string
+}
+
+type deleteLinkCase struct {
+	name    string
+	repo    map[string]Link
+	args    deleteLinkArgs
+	wantErr error
+}
+
+type getUserLinksCase struct {
+	name    string
+	repo    map[string]Link
+	uid     string
+	want    []Link
+	wantErr error
+}
+
+type storeLinkCase struct {
+	name    string
+	link    Link
+	wantErr error
+}
+
+var testCreated = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DB is missing",
+			want: "*link.BasicRepo",
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*link.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*link.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRepo(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want, rGot.Type().String())
+		})
+	}
+}
+
+func initBasicRepo(data map[string]Link) *BasicRepo {
+	links := &sync.Map{}
+	for id, l := range data {
+		l.ID = id
+		links.Store(id, l)
+	}
+	return &BasicRepo{links: links, mu: &sync.Mutex{}}
+}
+
+func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	return &DBRepo{db: db}, mock, err
+}
+
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getTestLinks() map[string]Link {
+	return map[string]Link{
+		"testID": {
+			ID:        "testID",
+			UID:       "testUser",
+			Name:      "text",
+			Data:      []byte("data"),
+			MaxViews:  2,
+			CreatedAt: testCreated,
+			ExpiresAt: testCreated.Add(time.Hour),
+		},
+		"testID1": {
+			ID:        "testID1",
+			UID:       "testUser",
+			Name:      "password",
+			Data:      []byte("data1"),
+			MaxViews:  1,
+			Views:     1,
+			CreatedAt: testCreated.Add(time.Minute),
+			ExpiresAt: testCreated.Add(time.Hour),
+		},
+		"testID2": {
+			ID:        "testID2",
+			UID:       "testUser1",
+			Name:      "card",
+			Data:      []byte("data2"),
+			MaxViews:  1,
+			CreatedAt: testCreated,
+			ExpiresAt: testCreated.Add(time.Minute),
+		},
+	}
+}
+
+func getConsumeLinkCases() []consumeLinkCase {
+	tr := getTestLinks()
+	viewed := tr["testID"]
+	viewed.Views++
+
+	return []consumeLinkCase{
+		{
+			name:    "No link ID passed",
+			repo:    tr,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Link views limit is reached",
+			repo:    tr,
+			id:      "testID1",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Link is expired",
+			repo:    tr,
+			id:      "testID2",
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Link view is counted",
+			repo: tr,
+			id:   "testID",
+			want: viewed,
+		},
+	}
+}
+
+func getDeleteLinkCases() []deleteLinkCase {
+	return []deleteLinkCase{
+		{
+			name:    "No arguments passed",
+			repo:    getTestLinks(),
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Link of another user",
+			repo:    getTestLinks(),
+			args:    deleteLinkArgs{uid: "testUser1", id: "testID"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Link of the user",
+			repo: getTestLinks(),
+			args: deleteLinkArgs{uid: "testUser", id: "testID"},
+		},
+	}
+}
+
+func getGetUserLinksCases() []getUserLinksCase {
+	tr := getTestLinks()
+	return []getUserLinksCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "No links for user present",
+			repo: tr,
+			uid:  "testUser0",
+			want: []Link{},
+		},
+		{
+			name: "User links are returned",
+			repo: tr,
+			uid:  "testUser",
+			want: []Link{tr["testID"], tr["testID1"]},
+		},
+	}
+}
+
+func getStoreLinkCases() []storeLinkCase {
+	return []storeLinkCase{
+		{
+			name:    "No arguments passed",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "No data passed",
+			link:    Link{UID: "testUser", Name: "text"},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "Link is stored",
+			link: Link{
+				UID:       "testUser",
+				Name:      "text",
+				Data:      []byte("data"),
+				MaxViews:  1,
+				CreatedAt: testCreated,
+				ExpiresAt: testCreated.Add(time.Hour),
+			},
+		},
+	}
+}
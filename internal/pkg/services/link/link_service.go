@@ -0,0 +1,113 @@
+package link
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrInvalidExpiry = errors.New("link expiry time must be in the future")
+	ErrInvalidViews  = errors.New("link views limit must be a positive number")
+)
+
+type IRepository interface {
+	ConsumeLink(ctx context.Context, id string, t time.Time) (Link, error)
+	DeleteExpiredLinks(ctx context.Context, t time.Time) error
+	DeleteLink(ctx context.Context, uid, id string) error
+	GetUserLinks(ctx context.Context, uid string) ([]Link, error)
+	StoreLink(ctx context.Context, link Link) (string, error)
+}
+
+type Service struct {
+	db IRepository
+}
+
+// NewService returns an instance of the Service with the associated repository.
+// Without the database handle, the links are persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo}, err
+}
+
+// CreateLink encrypts the secret with a new random key and stores the encrypted secret only.
+// The returned key is meant to be passed in the link URL fragment, so it never reaches the server again.
+func (s Service) CreateLink(ctx context.Context, uid, name string, secret []byte, maxViews int,
+	expiresAt time.Time,
+) (string, Link, error) {
+	now := time.Now().UTC()
+	if !expiresAt.After(now) {
+		return "", Link{}, ErrInvalidExpiry
+	}
+	if maxViews <= 0 {
+		return "", Link{}, ErrInvalidViews
+	}
+
+	key, err := enc.GenerateKey()
+	if err != nil {
+		return "", Link{}, err
+	}
+	data, err := enc.EncryptDataWithKey(key, secret)
+	if err != nil {
+		return "", Link{}, err
+	}
+
+	if err = s.db.DeleteExpiredLinks(ctx, now); err != nil {
+		return "", Link{}, err
+	}
+
+	l := Link{UID: uid, Name: name, Data: data, MaxViews: maxViews, CreatedAt: now, ExpiresAt: expiresAt.UTC()}
+	if l.ID, err = s.db.StoreLink(ctx, l); err != nil {
+		return "", Link{}, err
+	}
+	return base64.RawURLEncoding.EncodeToString(key), l, nil
+}
+
+// ConsumeLink counts the view of the link and returns the encrypted secret.
+// The link is deleted once its views limit is reached. The expired links are reported as missing.
+func (s Service) ConsumeLink(ctx context.Context, id string) (Link, error) {
+	l, err := s.db.ConsumeLink(ctx, id, time.Now().UTC())
+	if err != nil {
+		return Link{}, err
+	}
+
+	if l.Views >= l.MaxViews {
+		if err = s.db.DeleteLink(ctx, l.UID, l.ID); err != nil {
+			return Link{}, err
+		}
+	}
+	return l, nil
+}
+
+// DeleteLink revokes the user's link with the unique ID.
+func (s Service) DeleteLink(ctx context.Context, uid, id string) error {
+	return s.db.DeleteLink(ctx, uid, id)
+}
+
+// DeleteUserLinks revokes all the user's links, e.g. when the user account is deleted.
+func (s Service) DeleteUserLinks(ctx context.Context, uid string) error {
+	links, err := s.db.GetUserLinks(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range links {
+		if err = s.db.DeleteLink(ctx, uid, l.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetUserLinks returns the user's active links ordered by the creation time.
+func (s Service) GetUserLinks(ctx context.Context, uid string) ([]Link, error) {
+	if err := s.db.DeleteExpiredLinks(ctx, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.db.GetUserLinks(ctx, uid)
+}
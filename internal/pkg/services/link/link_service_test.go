@@ -0,0 +1,126 @@
+package link
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+)
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *sql.DB
+		wantRepoType string
+		wantErr      bool
+	}{
+		{
+			name:         "DB is missing",
+			wantRepoType: "*link.BasicRepo",
+		},
+		{
+			name:         "DB is present",
+			db:           initDB(t),
+			wantRepoType: "*link.DBRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rRepo := reflect.ValueOf(got.db)
+			assert.Equal(t, tt.wantRepoType, rRepo.Type().String())
+		})
+	}
+}
+
+func TestService_CreateLink(t *testing.T) {
+	type args struct {
+		maxViews  int
+		expiresAt time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Expiry in the past",
+			args:    args{maxViews: 1, expiresAt: time.Now().Add(-time.Hour)},
+			wantErr: ErrInvalidExpiry,
+		},
+		{
+			name:    "No views allowed",
+			args:    args{expiresAt: time.Now().Add(time.Hour)},
+			wantErr: ErrInvalidViews,
+		},
+		{
+			name: "Link is created",
+			args: args{maxViews: 2, expiresAt: time.Now().Add(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(nil)}
+			key, l, err := s.CreateLink(context.Background(), "testUser", "text", []byte("secret"),
+				tt.args.maxViews, tt.args.expiresAt)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			k, err := base64.RawURLEncoding.DecodeString(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.NotContains(t, string(l.Data), "secret")
+
+			got, err := enc.DecryptDataWithKey(k, l.Data)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("secret"), got)
+		})
+	}
+}
+
+func TestService_ConsumeLink(t *testing.T) {
+	s := Service{db: initBasicRepo(nil)}
+	_, l, err := s.CreateLink(context.Background(), "testUser", "text", []byte("secret"), 2,
+		time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for views := 1; views <= 2; views++ {
+		got, cErr := s.ConsumeLink(context.Background(), l.ID)
+		assert.NoError(t, cErr)
+		assert.Equal(t, views, got.Views)
+		assert.Equal(t, l.Data, got.Data)
+	}
+
+	_, err = s.ConsumeLink(context.Background(), l.ID)
+	assert.Equal(t, ErrNotFound, err)
+
+	links, err := s.GetUserLinks(context.Background(), "testUser")
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+}
+
+func TestService_DeleteUserLinks(t *testing.T) {
+	s := Service{db: initBasicRepo(getTestLinks())}
+	assert.Equal(t, ErrMissingArgs, s.DeleteUserLinks(context.Background(), ""))
+	assert.NoError(t, s.DeleteUserLinks(context.Background(), "testUser"))
+
+	links, err := s.db.GetUserLinks(context.Background(), "testUser")
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+	links, err = s.db.GetUserLinks(context.Background(), "testUser1")
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+}
@@ -0,0 +1,39 @@
+package oidc
+
+import "time"
+
+// Config describes the OpenID Connect provider and the client registered with it.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+}
+
+// Claims represent the verified ID token claims the user is identified by.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	PreferredUsername string
+}
+
+// Identity links the provider's subject to the goph-keeper user.
+type Identity struct {
+	Issuer  string
+	Subject string
+	UID     string
+}
+
+// Dump holds the linked identities of all users, e.g. for the backup.
+type Dump struct {
+	Identities []Identity
+}
+
+// State represents the pending login started by the client.
+type State struct {
+	State       string
+	Verifier    string
+	Nonce       string
+	RedirectURI string
+	ExpiresAt   time.Time
+}
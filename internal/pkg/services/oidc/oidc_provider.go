@@ -0,0 +1,255 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+var (
+	ErrIDToken  = errors.New("the id token is invalid")
+	ErrProvider = errors.New("the oidc provider responded with an error")
+)
+
+type Provider struct {
+	cfg  Config
+	http *http.Client
+
+	mu   sync.Mutex
+	meta *providerMetadata
+}
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// NewProvider returns an instance of the Provider communicating with the configured issuer.
+// The provider metadata is discovered lazily on the first request.
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Provider{cfg: cfg, http: client}
+}
+
+// AuthCodeURL returns the provider's authorization endpoint URL with the PKCE challenge.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, challenge, redirectURI string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("scope", "openid email profile")
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange redeems the authorization code with the PKCE verifier and returns the raw ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, redirectURI string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"code_verifier": {verifier},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {p.cfg.ClientID},
+	}
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err = p.doJSON(req, &tokens); err != nil {
+		return "", err
+	}
+	if tokens.IDToken == "" {
+		return "", ErrIDToken
+	}
+	return tokens.IDToken, nil
+}
+
+// VerifyIDToken checks the ID token signature against the provider's keys,
+// and validates its issuer, audience, expiry and nonce.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	keys, err := p.getKeys(ctx, meta.JWKSURI)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	var claims idTokenClaims
+	_, err = jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		if len(keys) == 1 && kid == "" {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, ErrIDToken
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+	if err != nil {
+		log.Error(err)
+		return Claims{}, ErrIDToken
+	}
+
+	if !claims.VerifyIssuer(p.cfg.Issuer, true) || !claims.VerifyAudience(p.cfg.ClientID, true) ||
+		claims.Subject == "" || claims.Nonce != nonce {
+		return Claims{}, ErrIDToken
+	}
+
+	return Claims{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (providerMetadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return *p.meta, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(p.cfg.Issuer, "/")+discoveryPath, http.NoBody)
+	if err != nil {
+		return providerMetadata{}, err
+	}
+
+	var meta providerMetadata
+	if err = p.doJSON(req, &meta); err != nil {
+		return providerMetadata{}, err
+	}
+	if meta.Issuer != p.cfg.Issuer || meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" ||
+		meta.JWKSURI == "" {
+		return providerMetadata{}, ErrProvider
+	}
+
+	p.meta = &meta
+	return meta, nil
+}
+
+func (p *Provider) getKeys(ctx context.Context, uri string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = p.doJSON(req, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		key, kErr := getRSAKey(k)
+		if kErr != nil {
+			return nil, kErr
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, v any) error {
+	res, err := p.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrProvider, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func getRSAKey(k jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+func closeResponseBody(body io.ReadCloser) {
+	if err := body.Close(); err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc/oidctest"
+)
+
+const (
+	testClientID = "goph-keeper"
+	testRedirect = "http://127.0.0.1:8080/callback"
+)
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	idp := initIDP(t)
+	tests := []struct {
+		name    string
+		issuer  string
+		want    url.Values
+		wantErr bool
+	}{
+		{
+			name:    "Unknown issuer",
+			issuer:  idp.URL + "/unknown",
+			wantErr: true,
+		},
+		{
+			name:   "Known issuer",
+			issuer: idp.URL,
+			want: url.Values{
+				"response_type":         {"code"},
+				"client_id":             {testClientID},
+				"redirect_uri":          {testRedirect},
+				"scope":                 {"openid email profile"},
+				"state":                 {"testState"},
+				"nonce":                 {"testNonce"},
+				"code_challenge":        {"testChallenge"},
+				"code_challenge_method": {"S256"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(Config{Issuer: tt.issuer, ClientID: testClientID}, nil)
+			got, err := p.AuthCodeURL(context.Background(), "testState", "testNonce", "testChallenge", testRedirect)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			if err == nil {
+				u, pErr := url.Parse(got)
+				assert.NoError(t, pErr)
+				assert.Equal(t, idp.URL+oidctest.AuthorizePath, u.Scheme+"://"+u.Host+u.Path)
+				assert.Equal(t, tt.want, u.Query())
+			}
+		})
+	}
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		wantErr  bool
+	}{
+		{
+			name:     "Wrong verifier",
+			verifier: "wrong",
+			wantErr:  true,
+		},
+		{
+			name:     "Correct verifier",
+			verifier: "testVerifier",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := initIDP(t)
+			p := NewProvider(Config{Issuer: idp.URL, ClientID: testClientID}, nil)
+			code := getTestCode(t, idp, p, "testVerifier", "testNonce")
+
+			got, err := p.Exchange(context.Background(), code, tt.verifier, testRedirect)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantErr, got == "")
+		})
+	}
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	idp := initIDP(t)
+	claims := func(aud, nonce string, exp time.Duration) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                idp.URL,
+			"sub":                "testSubject",
+			"aud":                aud,
+			"exp":                time.Now().Add(exp).Unix(),
+			"nonce":              nonce,
+			"email":              "test@example.com",
+			"preferred_username": "test",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		claims  jwt.MapClaims
+		want    Claims
+		wantErr error
+	}{
+		{
+			name:    "Another audience",
+			claims:  claims("another", "testNonce", time.Hour),
+			wantErr: ErrIDToken,
+		},
+		{
+			name:    "Wrong nonce",
+			claims:  claims(testClientID, "wrong", time.Hour),
+			wantErr: ErrIDToken,
+		},
+		{
+			name:    "Expired token",
+			claims:  claims(testClientID, "testNonce", -time.Hour),
+			wantErr: ErrIDToken,
+		},
+		{
+			name:   "Valid token",
+			claims: claims(testClientID, "testNonce", time.Hour),
+			want: Claims{
+				Issuer:            idp.URL,
+				Subject:           "testSubject",
+				Email:             "test@example.com",
+				PreferredUsername: "test",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := idp.SignIDToken(tt.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := NewProvider(Config{Issuer: idp.URL, ClientID: testClientID}, nil)
+			got, err := p.VerifyIDToken(context.Background(), token, "testNonce")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func initIDP(t *testing.T) *oidctest.Server {
+	idp, err := oidctest.NewServer(testClientID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func getTestCode(t *testing.T, idp *oidctest.Server, p *Provider, verifier, nonce string) string {
+	authURL, err := p.AuthCodeURL(context.Background(), "testState", nonce, getChallenge(verifier), testRedirect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redirect, err := idp.Authorize(authURL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return redirect.Query().Get("code")
+}
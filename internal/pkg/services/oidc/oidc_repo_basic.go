@@ -0,0 +1,123 @@
+package oidc
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BasicRepo struct {
+	identities *sync.Map
+	states     *sync.Map
+}
+
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{identities: &sync.Map{}, states: &sync.Map{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+// The login states live for minutes, so they are not persisted, and the logins in progress are restarted.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[Identity](w, "identities", r.identities); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteExpiredStates(ctx context.Context, t time.Time) error {
+	var err error
+	r.states.Range(func(k, v any) bool {
+		if !v.(State).ExpiresAt.After(t) {
+			_, err = storage.Delete(ctx, r.states, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (r *BasicRepo) DeleteUserIdentities(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	var err error
+	r.identities.Range(func(k, v any) bool {
+		if v.(Identity).UID == uid {
+			_, err = storage.Delete(ctx, r.identities, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// Export returns the identities sorted by their issuers and subjects.
+func (r *BasicRepo) Export(_ context.Context) (Dump, error) {
+	var dump Dump
+	r.identities.Range(func(_, v any) bool {
+		dump.Identities = append(dump.Identities, v.(Identity))
+		return true
+	})
+
+	sort.Slice(dump.Identities, func(i, j int) bool {
+		a, b := dump.Identities[i], dump.Identities[j]
+		return a.Issuer < b.Issuer || a.Issuer == b.Issuer && a.Subject < b.Subject
+	})
+	return dump, nil
+}
+
+func (r *BasicRepo) GetIdentity(_ context.Context, issuer, subject string) (Identity, error) {
+	if id, ok := r.identities.Load(identityKey(issuer, subject)); ok {
+		return id.(Identity), nil
+	}
+	return Identity{}, ErrNotFound
+}
+
+func (r *BasicRepo) Import(ctx context.Context, dump Dump) error {
+	for _, id := range dump.Identities {
+		if id.Issuer == "" || id.Subject == "" || id.UID == "" {
+			return ErrMissingArgs
+		}
+		k := identityKey(id.Issuer, id.Subject)
+		if _, ok := r.identities.Load(k); ok {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.identities, k, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BasicRepo) PopState(_ context.Context, state string) (State, error) {
+	if s, ok := r.states.LoadAndDelete(state); ok {
+		return s.(State), nil
+	}
+	return State{}, ErrNotFound
+}
+
+func (r *BasicRepo) StoreIdentity(ctx context.Context, id Identity) error {
+	if id.Issuer == "" || id.Subject == "" || id.UID == "" {
+		return ErrMissingArgs
+	}
+
+	return storage.Store(ctx, r.identities, identityKey(id.Issuer, id.Subject), id)
+}
+
+func (r *BasicRepo) StoreState(ctx context.Context, state State) error {
+	if state.State == "" || state.Verifier == "" || state.RedirectURI == "" {
+		return ErrMissingArgs
+	}
+
+	return storage.Store(ctx, r.states, state.State, state)
+}
+
+func identityKey(issuer, subject string) string {
+	return issuer + "|" + subject
+}
@@ -0,0 +1,167 @@
+package oidc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBasicRepo_DeleteExpiredStates(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{
+			name: "Expired states are deleted",
+			want: []string{"testState"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil, getTestStates())
+			err := r.DeleteExpiredStates(context.Background(), testExpires.Add(-time.Minute))
+			assert.NoError(t, err)
+
+			var got []string
+			r.states.Range(func(k, _ any) bool {
+				got = append(got, k.(string))
+				return true
+			})
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasicRepo_DeleteUserIdentities(t *testing.T) {
+	for _, tt := range getDeleteUserIdentitiesCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(getTestIdentities(), nil)
+			assert.Equal(t, tt.wantErr, r.DeleteUserIdentities(context.Background(), tt.uid))
+
+			var got []Identity
+			r.identities.Range(func(_, v any) bool {
+				got = append(got, v.(Identity))
+				return true
+			})
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasicRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	ids := getTestIdentities()
+
+	r := NewBasicRepo()
+	assert.NoError(t, r.Import(ctx, Dump{Identities: []Identity{ids[1], ids[0]}}))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{Identities: ids}, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Identities: ids[:1]}))
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Identities: []Identity{{Issuer: ids[0].Issuer}}}))
+}
+
+func TestBasicRepo_GetIdentity(t *testing.T) {
+	for _, tt := range getGetIdentityCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo, nil)
+			got, err := r.GetIdentity(context.Background(), tt.issuer, tt.subject)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_PopState(t *testing.T) {
+	for _, tt := range getPopStateCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil, tt.repo)
+			got, err := r.PopState(context.Background(), tt.state)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+
+			_, err = r.PopState(context.Background(), tt.state)
+			assert.Equal(t, ErrNotFound, err)
+		})
+	}
+}
+
+func TestBasicRepo_StoreIdentity(t *testing.T) {
+	for _, tt := range getStoreIdentityCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil, nil)
+			err := r.StoreIdentity(context.Background(), tt.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_StoreState(t *testing.T) {
+	for _, tt := range getStoreStateCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(nil, nil)
+			err := r.StoreState(context.Background(), tt.state)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestNewBasicRepo(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{
+			name: "Basic repo creation",
+			want: "*oidc.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBasicRepo()
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	id := Identity{Issuer: "https://issuer", Subject: "testSubject", UID: "testUser"}
+
+	r := initPersistentRepo(t, dir)
+	assert.NoError(t, r.StoreIdentity(ctx, id))
+	assert.NoError(t, r.StoreIdentity(ctx, Identity{Issuer: "https://issuer", Subject: "testSubject1", UID: "testUser1"}))
+	assert.NoError(t, r.DeleteUserIdentities(ctx, "testUser1"))
+	assert.NoError(t, r.StoreState(ctx, State{State: "testState", Verifier: "testVerifier", RedirectURI: "http://cb",
+		ExpiresAt: time.Now().Add(time.Minute)}))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetIdentity(ctx, id.Issuer, id.Subject)
+	assert.NoError(t, err)
+	assert.Equal(t, id, got)
+	_, err = r.GetIdentity(ctx, id.Issuer, "testSubject1")
+	assert.Equal(t, ErrNotFound, err)
+	_, err = r.PopState(ctx, "testState")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "identities", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
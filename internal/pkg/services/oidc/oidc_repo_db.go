@@ -0,0 +1,154 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const uniqueViolation = "23505"
+
+type DBRepo struct {
+	db *sql.DB
+}
+
+const (
+	DeleteExpiredStates  = "DELETE FROM oidc_states WHERE expires_at <= $1"
+	DeleteUserIdentities = "DELETE FROM user_identities WHERE uid = $1"
+	ExportIdentities     = "SELECT issuer, subject, uid FROM user_identities ORDER BY issuer, subject"
+	GetIdentity          = "SELECT issuer, subject, uid FROM user_identities WHERE issuer = $1 AND subject = $2"
+	ImportIdentity       = "INSERT INTO user_identities(issuer, subject, uid) VALUES ($1, $2, $3)"
+	PopState             = `
+		DELETE FROM oidc_states WHERE state = $1 RETURNING state, verifier, nonce, redirect_uri, expires_at
+	`
+	StoreIdentity = `
+		INSERT INTO user_identities(issuer, subject, uid) VALUES ($1, $2, $3)
+		ON CONFLICT (issuer, subject) DO UPDATE SET uid = EXCLUDED.uid
+	`
+	StoreState = `
+		INSERT INTO oidc_states(state, verifier, nonce, redirect_uri, expires_at) VALUES ($1, $2, $3, $4, $5)
+	`
+)
+
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
+	}
+	return &DBRepo{db: db}, nil
+}
+
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *DBRepo) DeleteExpiredStates(ctx context.Context, t time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteExpiredStates, t)
+	return err
+}
+
+func (r *DBRepo) DeleteUserIdentities(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteUserIdentities, uid)
+	return err
+}
+
+// Export returns the identities sorted by their issuers and subjects.
+func (r *DBRepo) Export(ctx context.Context) (Dump, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportIdentities)
+	if err != nil {
+		return Dump{}, err
+	}
+	if rows.Err() != nil {
+		return Dump{}, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var dump Dump
+	for rows.Next() {
+		var id Identity
+		if err = rows.Scan(&id.Issuer, &id.Subject, &id.UID); err != nil {
+			return Dump{}, err
+		}
+		dump.Identities = append(dump.Identities, id)
+	}
+	return dump, nil
+}
+
+func (r *DBRepo) GetIdentity(ctx context.Context, issuer, subject string) (Identity, error) {
+	var id Identity
+	err := r.conn(ctx).QueryRowContext(ctx, GetIdentity, issuer, subject).Scan(&id.Issuer, &id.Subject, &id.UID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Identity{}, ErrNotFound
+		}
+		return Identity{}, err
+	}
+	return id, nil
+}
+
+func (r *DBRepo) Import(ctx context.Context, dump Dump) error {
+	for _, id := range dump.Identities {
+		if id.Issuer == "" || id.Subject == "" || id.UID == "" {
+			return ErrMissingArgs
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportIdentity, id.Issuer, id.Subject, id.UID); err != nil {
+			return mapDBError(err)
+		}
+	}
+	return nil
+}
+
+func (r *DBRepo) PopState(ctx context.Context, state string) (State, error) {
+	var s State
+	err := r.conn(ctx).QueryRowContext(ctx, PopState, state).Scan(&s.State, &s.Verifier, &s.Nonce, &s.RedirectURI,
+		&s.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return State{}, ErrNotFound
+		}
+		return State{}, err
+	}
+	return s, nil
+}
+
+func (r *DBRepo) StoreIdentity(ctx context.Context, id Identity) error {
+	if id.Issuer == "" || id.Subject == "" || id.UID == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, StoreIdentity, id.Issuer, id.Subject, id.UID)
+	return err
+}
+
+func (r *DBRepo) StoreState(ctx context.Context, state State) error {
+	if state.State == "" || state.Verifier == "" || state.RedirectURI == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, StoreState, state.State, state.Verifier, state.Nonce, state.RedirectURI,
+		state.ExpiresAt)
+	return err
+}
+
+func (r *DBRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapDBError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrExists
+	}
+	return err
+}
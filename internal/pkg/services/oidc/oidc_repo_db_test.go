@@ -0,0 +1,209 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBRepo_DeleteExpiredStates(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(DeleteExpiredStates)).WithArgs(testExpires).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.DeleteExpiredStates(context.Background(), testExpires))
+	checkMetExpectations(t, mock)
+}
+
+func TestDBRepo_DeleteUserIdentities(t *testing.T) {
+	for _, tt := range getDeleteUserIdentitiesCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				mock.ExpectExec(regexp.QuoteMeta(DeleteUserIdentities)).WithArgs(tt.uid).
+					WillReturnResult(sqlmock.NewResult(0, int64(len(getTestIdentities())-len(tt.want))))
+			}
+			assert.Equal(t, tt.wantErr, r.DeleteUserIdentities(context.Background(), tt.uid))
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_ExportImport(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := getTestIdentities()
+	rows := sqlmock.NewRows([]string{"issuer", "subject", "uid"})
+	for _, id := range ids {
+		rows.AddRow(id.Issuer, id.Subject, id.UID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(ExportIdentities)).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(ImportIdentity)).WithArgs(ids[0].Issuer, ids[0].Subject, ids[0].UID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(ImportIdentity)).WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+
+	got, err := r.Export(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{Identities: ids}, got)
+	assert.Equal(t, ErrExists, r.Import(context.Background(), Dump{Identities: ids}))
+	assert.Equal(t, ErrMissingArgs, r.Import(context.Background(), Dump{Identities: []Identity{{UID: "testUser"}}}))
+	checkMetExpectations(t, mock)
+}
+
+func TestDBRepo_GetIdentity(t *testing.T) {
+	for _, tt := range getGetIdentityCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			eq := mock.ExpectQuery(regexp.QuoteMeta(GetIdentity)).WithArgs(tt.issuer, tt.subject)
+			if tt.want.UID != "" {
+				eq.WillReturnRows(mock.NewRows([]string{"issuer", "subject", "uid"}).
+					AddRow(tt.want.Issuer, tt.want.Subject, tt.want.UID))
+			} else {
+				eq.WillReturnError(sql.ErrNoRows)
+			}
+
+			got, err := r.GetIdentity(context.Background(), tt.issuer, tt.subject)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_PopState(t *testing.T) {
+	for _, tt := range getPopStateCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			eq := mock.ExpectQuery(regexp.QuoteMeta(PopState)).WithArgs(tt.state)
+			if tt.want.State != "" {
+				eq.WillReturnRows(mock.NewRows([]string{"state", "verifier", "nonce", "redirect_uri", "expires_at"}).
+					AddRow(tt.want.State, tt.want.Verifier, tt.want.Nonce, tt.want.RedirectURI, tt.want.ExpiresAt))
+			} else {
+				eq.WillReturnError(sql.ErrNoRows)
+			}
+
+			got, err := r.PopState(context.Background(), tt.state)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreIdentity(t *testing.T) {
+	for _, tt := range getStoreIdentityCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr == nil {
+				mock.ExpectExec(regexp.QuoteMeta(StoreIdentity)).WithArgs(tt.id.Issuer, tt.id.Subject, tt.id.UID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+
+			err = r.StoreIdentity(context.Background(), tt.id)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreState(t *testing.T) {
+	for _, tt := range getStoreStateCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			st := tt.state
+			if tt.wantErr == nil {
+				mock.ExpectExec(regexp.QuoteMeta(StoreState)).
+					WithArgs(st.State, st.Verifier, st.Nonce, st.RedirectURI, st.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+
+			err = r.StoreState(context.Background(), tt.state)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestNewDBRepo(t *testing.T) {
+	type want struct {
+		repoType  string
+		fieldName string
+		fieldType string
+	}
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "DB is missing",
+			wantErr: true,
+			want: want{
+				repoType:  "*oidc.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: want{
+				repoType:  "*oidc.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDBRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want.repoType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.want.fieldName, rField.Name)
+			assert.Equal(t, tt.want.fieldType, rField.Type.String())
+		})
+	}
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
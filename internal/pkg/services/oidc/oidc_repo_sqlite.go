@@ -0,0 +1,170 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteStates = `CREATE TABLE IF NOT EXISTS oidc_states(
+		state TEXT PRIMARY KEY,
+		verifier TEXT NOT NULL,
+		nonce TEXT NOT NULL,
+		redirect_uri TEXT NOT NULL,
+		expires_at TIMESTAMP NOT NULL)`
+	createSQLiteIdentities = `CREATE TABLE IF NOT EXISTS user_identities(
+		issuer TEXT NOT NULL,
+		subject TEXT NOT NULL,
+		uid TEXT NOT NULL,
+		PRIMARY KEY(issuer, subject))`
+	sqliteDeleteExpiredStates  = "DELETE FROM oidc_states WHERE expires_at <= ?1"
+	sqliteDeleteUserIdentities = "DELETE FROM user_identities WHERE uid = ?1"
+	sqliteExportIdentities     = "SELECT issuer, subject, uid FROM user_identities ORDER BY issuer, subject"
+	sqliteGetIdentity          = "SELECT issuer, subject, uid FROM user_identities WHERE issuer = ?1 AND subject = ?2"
+	sqliteImportIdentity       = "INSERT INTO user_identities(issuer, subject, uid) VALUES (?1, ?2, ?3)"
+	sqlitePopState             = `
+		DELETE FROM oidc_states WHERE state = ?1 RETURNING state, verifier, nonce, redirect_uri, expires_at
+	`
+	sqliteStoreIdentity = `
+		INSERT INTO user_identities(issuer, subject, uid) VALUES (?1, ?2, ?3)
+		ON CONFLICT (issuer, subject) DO UPDATE SET uid = excluded.uid
+	`
+	sqliteStoreState = `
+		INSERT INTO oidc_states(state, verifier, nonce, redirect_uri, expires_at) VALUES (?1, ?2, ?3, ?4, ?5)
+	`
+)
+
+// NewSQLiteRepo returns the repository keeping the login states and the linked identities in the SQLite database,
+// and creates its tables if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	for _, q := range []string{createSQLiteStates, createSQLiteIdentities} {
+		if _, err := db.ExecContext(context.Background(), q); err != nil {
+			return &SQLiteRepo{}, err
+		}
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteExpiredStates(ctx context.Context, t time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteExpiredStates, t.UTC())
+	return err
+}
+
+func (r *SQLiteRepo) DeleteUserIdentities(ctx context.Context, uid string) error {
+	if uid == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteUserIdentities, uid)
+	return err
+}
+
+// Export returns the identities sorted by their issuers and subjects.
+func (r *SQLiteRepo) Export(ctx context.Context) (Dump, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteExportIdentities)
+	if err != nil {
+		return Dump{}, err
+	}
+	if rows.Err() != nil {
+		return Dump{}, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var dump Dump
+	for rows.Next() {
+		var id Identity
+		if err = rows.Scan(&id.Issuer, &id.Subject, &id.UID); err != nil {
+			return Dump{}, err
+		}
+		dump.Identities = append(dump.Identities, id)
+	}
+	return dump, nil
+}
+
+func (r *SQLiteRepo) GetIdentity(ctx context.Context, issuer, subject string) (Identity, error) {
+	var id Identity
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetIdentity, issuer, subject).Scan(&id.Issuer, &id.Subject, &id.UID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Identity{}, ErrNotFound
+		}
+		return Identity{}, err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepo) Import(ctx context.Context, dump Dump) error {
+	for _, id := range dump.Identities {
+		if id.Issuer == "" || id.Subject == "" || id.UID == "" {
+			return ErrMissingArgs
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, sqliteImportIdentity, id.Issuer, id.Subject, id.UID); err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) PopState(ctx context.Context, state string) (State, error) {
+	var s State
+	err := r.conn(ctx).QueryRowContext(ctx, sqlitePopState, state).Scan(&s.State, &s.Verifier, &s.Nonce,
+		&s.RedirectURI, &s.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return State{}, ErrNotFound
+		}
+		return State{}, err
+	}
+	return s, nil
+}
+
+func (r *SQLiteRepo) StoreIdentity(ctx context.Context, id Identity) error {
+	if id.Issuer == "" || id.Subject == "" || id.UID == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreIdentity, id.Issuer, id.Subject, id.UID)
+	return err
+}
+
+func (r *SQLiteRepo) StoreState(ctx context.Context, state State) error {
+	if state.State == "" || state.Verifier == "" || state.RedirectURI == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreState, state.State, state.Verifier, state.Nonce,
+		state.RedirectURI, state.ExpiresAt.UTC())
+	return err
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return ErrExists
+	}
+	return err
+}
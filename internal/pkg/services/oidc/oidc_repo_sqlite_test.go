@@ -0,0 +1,169 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteExpiredStates(t *testing.T) {
+	r := initSQLiteRepo(t, nil, getTestStates())
+	assert.NoError(t, r.DeleteExpiredStates(context.Background(), testExpires.Add(-time.Minute)))
+
+	_, err := r.PopState(context.Background(), "testState")
+	assert.NoError(t, err)
+	_, err = r.PopState(context.Background(), "testState1")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSQLiteRepo_DeleteUserIdentities(t *testing.T) {
+	for _, tt := range getDeleteUserIdentitiesCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, getTestIdentities(), nil)
+			assert.Equal(t, tt.wantErr, r.DeleteUserIdentities(context.Background(), tt.uid))
+
+			for _, id := range getTestIdentities() {
+				_, err := r.GetIdentity(context.Background(), id.Issuer, id.Subject)
+				assert.Equal(t, containsIdentity(tt.want, id), err == nil)
+			}
+		})
+	}
+}
+
+func TestSQLiteRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	ids := getTestIdentities()
+
+	r := initSQLiteRepo(t, nil, nil)
+	assert.NoError(t, r.Import(ctx, Dump{Identities: []Identity{ids[1], ids[0]}}))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{Identities: ids}, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Identities: ids[:1]}))
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Identities: []Identity{{Issuer: ids[0].Issuer}}}))
+}
+
+func TestSQLiteRepo_GetIdentity(t *testing.T) {
+	for _, tt := range getGetIdentityCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo, nil)
+			got, err := r.GetIdentity(context.Background(), tt.issuer, tt.subject)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_PopState(t *testing.T) {
+	for _, tt := range getPopStateCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, nil, tt.repo)
+			got, err := r.PopState(context.Background(), tt.state)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+
+			_, err = r.PopState(context.Background(), tt.state)
+			assert.Equal(t, ErrNotFound, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreIdentity(t *testing.T) {
+	for _, tt := range getStoreIdentityCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, nil, nil)
+			err := r.StoreIdentity(context.Background(), tt.id)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreState(t *testing.T) {
+	for _, tt := range getStoreStateCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, nil, nil)
+			err := r.StoreState(context.Background(), tt.state)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*oidc.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*oidc.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, ids []Identity, states []State) *SQLiteRepo {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range ids {
+		if err = r.StoreIdentity(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, s := range states {
+		if err = r.StoreState(context.Background(), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
+
+func containsIdentity(ids []Identity, id Identity) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package oidc
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type deleteUserIdentitiesCase struct {
+	name    string
+	uid     string
+	want    []Identity
+	wantErr error
+}
+
+type getIdentityCase struct {
+	name    string
+	repo    []Identity
+	issuer  string
+	subject string
+	want    Identity
+	wantErr error
+}
+
+type popStateCase struct {
+	name    string
+	repo    []State
+	state   string
+	want    State
+	wantErr error
+}
+
+type storeIdentityCase struct {
+	name    string
+	id      Identity
+	wantErr error
+}
+
+type storeStateCase struct {
+	name    string
+	state   State
+	wantErr error
+}
+
+var testExpires = time.Date(2023, 1, 1, 0, 10, 0, 0, time.UTC)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DB is missing",
+			want: "*oidc.BasicRepo",
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*oidc.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*oidc.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRepo(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want, rGot.Type().String())
+		})
+	}
+}
+
+func initBasicRepo(ids []Identity, states []State) *BasicRepo {
+	r := &BasicRepo{identities: &sync.Map{}, states: &sync.Map{}}
+	for _, id := range ids {
+		r.identities.Store(identityKey(id.Issuer, id.Subject), id)
+	}
+	for _, s := range states {
+		r.states.Store(s.State, s)
+	}
+	return r
+}
+
+func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	return &DBRepo{db: db}, mock, err
+}
+
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getTestIdentities() []Identity {
+	return []Identity{
+		{Issuer: "https://idp.example.com", Subject: "testSubject", UID: "testUser"},
+		{Issuer: "https://idp.example.com", Subject: "testSubject1", UID: "testUser1"},
+	}
+}
+
+func getTestStates() []State {
+	return []State{
+		{
+			State:       "testState",
+			Verifier:    "testVerifier",
+			Nonce:       "testNonce",
+			RedirectURI: "http://127.0.0.1:8080/callback",
+			ExpiresAt:   testExpires,
+		},
+		{
+			State:       "testState1",
+			Verifier:    "testVerifier1",
+			Nonce:       "testNonce1",
+			RedirectURI: "http://127.0.0.1:8080/callback",
+			ExpiresAt:   testExpires.Add(-time.Hour),
+		},
+	}
+}
+
+func getDeleteUserIdentitiesCases() []deleteUserIdentitiesCase {
+	ids := getTestIdentities()
+	return []deleteUserIdentitiesCase{
+		{
+			name:    "No user ID passed",
+			want:    ids,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "Unknown user",
+			uid:  "unknown",
+			want: ids,
+		},
+		{
+			name: "User identities are deleted",
+			uid:  "testUser",
+			want: ids[1:],
+		},
+	}
+}
+
+func getGetIdentityCases() []getIdentityCase {
+	ids := getTestIdentities()
+	return []getIdentityCase{
+		{
+			name:    "Unknown subject",
+			repo:    ids,
+			issuer:  "https://idp.example.com",
+			subject: "unknown",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Subject of another issuer",
+			repo:    ids,
+			issuer:  "https://idp1.example.com",
+			subject: "testSubject",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Known subject",
+			repo:    ids,
+			issuer:  "https://idp.example.com",
+			subject: "testSubject",
+			want:    ids[0],
+		},
+	}
+}
+
+func getPopStateCases() []popStateCase {
+	states := getTestStates()
+	return []popStateCase{
+		{
+			name:    "Unknown state",
+			repo:    states,
+			state:   "unknown",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:  "Known state",
+			repo:  states,
+			state: "testState",
+			want:  states[0],
+		},
+	}
+}
+
+func getStoreIdentityCases() []storeIdentityCase {
+	return []storeIdentityCase{
+		{
+			name:    "No arguments passed",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "No user ID passed",
+			id:      Identity{Issuer: "https://idp.example.com", Subject: "testSubject"},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "All arguments are correct",
+			id:   Identity{Issuer: "https://idp.example.com", Subject: "testSubject", UID: "testUser"},
+		},
+	}
+}
+
+func getStoreStateCases() []storeStateCase {
+	return []storeStateCase{
+		{
+			name:    "No arguments passed",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "No verifier passed",
+			state:   State{State: "testState", RedirectURI: "http://127.0.0.1:8080/callback"},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:  "All arguments are correct",
+			state: getTestStates()[0],
+		},
+	}
+}
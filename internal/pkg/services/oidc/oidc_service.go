@@ -0,0 +1,235 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type IRepository interface {
+	DeleteExpiredStates(ctx context.Context, t time.Time) error
+	DeleteUserIdentities(ctx context.Context, uid string) error
+	Export(ctx context.Context) (Dump, error)
+	GetIdentity(ctx context.Context, issuer, subject string) (Identity, error)
+	Import(ctx context.Context, dump Dump) error
+	PopState(ctx context.Context, state string) (State, error)
+	StoreIdentity(ctx context.Context, id Identity) error
+	StoreState(ctx context.Context, state State) error
+}
+
+type Service struct {
+	db          IRepository
+	provider    *Provider
+	userService user.Service
+}
+
+const (
+	maxNameLength = 50
+	stateTTL      = 10 * time.Minute
+)
+
+var (
+	ErrMissingConfig = errors.New("the oidc issuer or client id is not configured")
+	ErrStateExpired  = errors.New("the login state has expired, please start over")
+)
+
+// NewService returns an instance of the Service with the associated repository and provider.
+// The user microservice is used to provision the users signing in for the first time.
+// Without the database handle, the linked identities are persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL, cfg Config, us user.Service) (Service, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" {
+		return Service{}, ErrMissingConfig
+	}
+
+	repo, err := NewRepo(db, w)
+	return Service{db: repo, provider: NewProvider(cfg, nil), userService: us}, err
+}
+
+// NewIdentityService returns an instance of the Service managing the linked identities only.
+// It's used to remove the user's identities when the provider isn't configured; the login is refused with it.
+func NewIdentityService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo}, err
+}
+
+// DeleteUserIdentities unlinks all the provider identities of the user, e.g. when the user account is deleted.
+func (s Service) DeleteUserIdentities(ctx context.Context, uid string) error {
+	return s.db.DeleteUserIdentities(ctx, uid)
+}
+
+// Export returns the linked identities of all users.
+func (s Service) Export(ctx context.Context) (Dump, error) {
+	return s.db.Export(ctx)
+}
+
+// Import links the exported identities to their users, as is.
+// Run it within the unit of work, so the failed import leaves no partial changes behind.
+func (s Service) Import(ctx context.Context, dump Dump) error {
+	return s.db.Import(ctx, dump)
+}
+
+// StartLogin stores a new login state with the PKCE verifier and returns the provider authorization URL.
+// The state is returned to the client to match the provider redirect.
+func (s Service) StartLogin(ctx context.Context, redirectURI string) (string, string, error) {
+	if s.provider == nil {
+		return "", "", ErrMissingConfig
+	}
+	if redirectURI == "" {
+		return "", "", ErrMissingArgs
+	}
+
+	st, err := newState(redirectURI)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, st.State, st.Nonce, getChallenge(st.Verifier), st.RedirectURI)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = s.db.DeleteExpiredStates(ctx, time.Now().UTC()); err != nil {
+		return "", "", err
+	}
+	if err = s.db.StoreState(ctx, st); err != nil {
+		return "", "", err
+	}
+	return authURL, st.State, nil
+}
+
+// FinishLogin redeems the authorization code of the pending login and returns the ID of the linked user.
+// The state can only be used once. If the provider subject isn't linked yet, a new user is provisioned.
+func (s Service) FinishLogin(ctx context.Context, state, code string) (string, error) {
+	if s.provider == nil {
+		return "", ErrMissingConfig
+	}
+	if state == "" || code == "" {
+		return "", ErrMissingArgs
+	}
+
+	st, err := s.db.PopState(ctx, state)
+	if err != nil {
+		return "", err
+	}
+	if !st.ExpiresAt.After(time.Now()) {
+		return "", ErrStateExpired
+	}
+
+	idToken, err := s.provider.Exchange(ctx, code, st.Verifier, st.RedirectURI)
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := s.provider.VerifyIDToken(ctx, idToken, st.Nonce)
+	if err != nil {
+		return "", err
+	}
+	return s.getLinkedUser(ctx, claims)
+}
+
+func (s Service) getLinkedUser(ctx context.Context, claims Claims) (string, error) {
+	id, err := s.db.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		_, err = s.userService.GetUserByID(ctx, id.UID)
+		if err == nil {
+			return id.UID, nil
+		}
+	}
+	// The user is provisioned only if the identity isn't linked yet, or the linked user has been deleted.
+	if !errors.Is(err, ErrNotFound) && !errors.Is(err, user.ErrNotFound) {
+		return "", err
+	}
+
+	u, err := s.provisionUser(ctx, claims)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.db.StoreIdentity(ctx, Identity{Issuer: claims.Issuer, Subject: claims.Subject, UID: u.ID})
+	return u.ID, err
+}
+
+// provisionUser creates the user named after the provider claims.
+// If the name is already taken, the name is suffixed with the hash of the provider subject.
+// The password is random, so the provisioned user can only sign in through the provider.
+func (s Service) provisionUser(ctx context.Context, claims Claims) (user.User, error) {
+	password, err := generateRandomString(32)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	name := getUserName(claims)
+	for _, n := range []string{name, getSuffixedName(name, claims)} {
+		err = s.userService.AddUser(ctx, user.User{Name: n, Password: password})
+		if err == nil {
+			return s.userService.GetUserByName(ctx, n)
+		}
+		if !errors.Is(err, user.ErrExists) {
+			return user.User{}, err
+		}
+	}
+	return user.User{}, user.ErrExists
+}
+
+func newState(redirectURI string) (State, error) {
+	st := State{RedirectURI: redirectURI, ExpiresAt: time.Now().UTC().Add(stateTTL)}
+
+	var err error
+	if st.State, err = generateRandomString(16); err != nil {
+		return State{}, err
+	}
+	if st.Verifier, err = generateRandomString(32); err != nil {
+		return State{}, err
+	}
+	if st.Nonce, err = generateRandomString(16); err != nil {
+		return State{}, err
+	}
+	return st, nil
+}
+
+func getUserName(claims Claims) string {
+	name := claims.PreferredUsername
+	if name == "" {
+		name = claims.Email
+	}
+	if name == "" {
+		name = claims.Subject
+	}
+
+	name = strings.ToLower(name)
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
+
+func getSuffixedName(name string, claims Claims) string {
+	sum := sha256.Sum256([]byte(claims.Issuer + "|" + claims.Subject))
+	suffix := "-" + hex.EncodeToString(sum[:])[:6]
+	if len(name)+len(suffix) > maxNameLength {
+		name = name[:maxNameLength-len(suffix)]
+	}
+	return name + suffix
+}
+
+func getChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func generateRandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
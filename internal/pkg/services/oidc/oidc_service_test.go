@@ -0,0 +1,257 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/oidc/oidctest"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/user"
+)
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *sql.DB
+		cfg          Config
+		wantRepoType string
+		wantErr      error
+	}{
+		{
+			name:    "Missing config",
+			wantErr: ErrMissingConfig,
+		},
+		{
+			name:         "DB is missing",
+			cfg:          Config{Issuer: "https://idp.example.com", ClientID: testClientID},
+			wantRepoType: "*oidc.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.db, nil, tt.cfg, user.Service{})
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				assert.Equal(t, tt.wantRepoType, reflect.ValueOf(got.db).Type().String())
+			}
+		})
+	}
+}
+
+func TestNewIdentityService(t *testing.T) {
+	s, err := NewIdentityService(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "*oidc.BasicRepo", reflect.ValueOf(s.db).Type().String())
+
+	_, _, err = s.StartLogin(context.Background(), testRedirect)
+	assert.Equal(t, ErrMissingConfig, err)
+	_, err = s.FinishLogin(context.Background(), "testState", "testCode")
+	assert.Equal(t, ErrMissingConfig, err)
+}
+
+func TestService_StartLogin(t *testing.T) {
+	tests := []struct {
+		name        string
+		redirectURI string
+		wantErr     error
+	}{
+		{
+			name:    "Missing redirect URI",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:        "Login is started",
+			redirectURI: testRedirect,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := initService(t)
+			authURL, state, err := s.StartLogin(context.Background(), tt.redirectURI)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				assert.Contains(t, authURL, "state="+state)
+				st, sErr := s.db.PopState(context.Background(), state)
+				assert.NoError(t, sErr)
+				assert.Contains(t, authURL, "code_challenge="+getChallenge(st.Verifier))
+			}
+		})
+	}
+}
+
+func TestService_FinishLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		noState bool
+		expired bool
+		wantErr error
+	}{
+		{
+			name:    "Missing state",
+			noState: true,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Unknown state",
+			state:   "unknown",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Expired state",
+			expired: true,
+			wantErr: ErrStateExpired,
+		},
+		{
+			name: "User is provisioned",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, idp := initService(t)
+			state, code := startTestLogin(t, s, idp, "test")
+			if tt.expired {
+				st, err := s.db.PopState(context.Background(), state)
+				if err != nil {
+					t.Fatal(err)
+				}
+				st.ExpiresAt = time.Now().Add(-time.Minute)
+				if err = s.db.StoreState(context.Background(), st); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.state != "" || tt.noState {
+				state = tt.state
+			}
+
+			uid, err := s.FinishLogin(context.Background(), state, code)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				u, uErr := s.userService.GetUserByID(context.Background(), uid)
+				assert.NoError(t, uErr)
+				assert.Equal(t, "test", u.Name)
+			}
+		})
+	}
+}
+
+func TestService_FinishLogin_Linked(t *testing.T) {
+	s, idp := initService(t)
+	if err := s.userService.AddUser(context.Background(), user.User{Name: "test", Password: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	state, code := startTestLogin(t, s, idp, "test")
+	uid, err := s.FinishLogin(context.Background(), state, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := s.userService.GetUserByID(context.Background(), uid)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u.Name, "test-"))
+
+	state, code = startTestLogin(t, s, idp, "test")
+	got, err := s.FinishLogin(context.Background(), state, code)
+	assert.NoError(t, err)
+	assert.Equal(t, uid, got)
+
+	_, err = s.FinishLogin(context.Background(), state, code)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func Test_getUserName(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims Claims
+		want   string
+	}{
+		{
+			name:   "Preferred username",
+			claims: Claims{Subject: "sub", Email: "test@example.com", PreferredUsername: "Test"},
+			want:   "test",
+		},
+		{
+			name:   "Email",
+			claims: Claims{Subject: "sub", Email: "test@example.com"},
+			want:   "test@example.com",
+		},
+		{
+			name:   "Subject",
+			claims: Claims{Subject: "sub"},
+			want:   "sub",
+		},
+		{
+			name:   "Long name",
+			claims: Claims{Subject: strings.Repeat("s", 60)},
+			want:   strings.Repeat("s", 50),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getUserName(tt.claims))
+		})
+	}
+}
+
+func initService(t *testing.T) (Service, *oidctest.Server) {
+	idp := initIDP(t)
+	us, err := user.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewService(nil, nil, Config{Issuer: idp.URL, ClientID: testClientID}, us)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, idp
+}
+
+func startTestLogin(t *testing.T, s Service, idp *oidctest.Server, subject string) (string, string) {
+	authURL, state, err := s.StartLogin(context.Background(), testRedirect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redirect, err := idp.Authorize(authURL, subject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state, redirect.Query().Get("code")
+}
+
+func TestService_getLinkedUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	us, err := user.NewService(db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, idp := initService(t)
+	s.userService = us
+	claims := Claims{Issuer: idp.URL, Subject: "test"}
+	if err = s.db.StoreIdentity(context.Background(), Identity{
+		Issuer: claims.Issuer, Subject: claims.Subject, UID: "uid",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(user.GetUserByID)).WithArgs("uid").WillReturnError(sql.ErrConnDone)
+	_, err = s.getLinkedUser(context.Background(), claims)
+	assert.Equal(t, sql.ErrConnDone, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,66 @@
+package org
+
+import "time"
+
+type Role string
+
+const (
+	RoleOwner    Role = "owner"
+	RoleAdmin    Role = "admin"
+	RoleMember   Role = "member"
+	RoleReadOnly Role = "read-only"
+)
+
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Member struct {
+	OrgID     string    `json:"org_id"`
+	UID       string    `json:"uid"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Membership is the organization the user belongs to, along with the user's role in it.
+type Membership struct {
+	Organization
+	Role Role `json:"role"`
+}
+
+// Collection is the team vault owned by the organization.
+// The collection ID is used as the owner of the items stored in the vault.
+type Collection struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Dump is the complete content of the repository, moved between the repositories with the IDs preserved.
+type Dump struct {
+	Organizations []Organization
+	Members       []Member
+	Collections   []Collection
+}
+
+// IsValid checks if the role is one of the known ones.
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember, RoleReadOnly:
+		return true
+	}
+	return false
+}
+
+// CanManage checks if the role permits managing the organization members and collections.
+func (r Role) CanManage() bool {
+	return r == RoleOwner || r == RoleAdmin
+}
+
+// CanWrite checks if the role permits changing the items stored in the organization collections.
+func (r Role) CanWrite() bool {
+	return r.CanManage() || r == RoleMember
+}
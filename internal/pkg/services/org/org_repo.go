@@ -0,0 +1,30 @@
+package org
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrDBMissing   = errors.New("organizations db is missing")
+	ErrExists      = errors.New("the collection with specified name already exists")
+	ErrMissingArgs = errors.New("organization id or user id is not specified")
+	ErrNotFound    = errors.New("organization, member or collection not found")
+)
+
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
+		return NewBasicRepo(), nil
+	}
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
+}
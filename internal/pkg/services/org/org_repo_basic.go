@@ -0,0 +1,310 @@
+package org
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BasicRepo struct {
+	orgs        *sync.Map
+	members     *sync.Map
+	collections *sync.Map
+}
+
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{orgs: &sync.Map{}, members: &sync.Map{}, collections: &sync.Map{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[Organization](w, "orgs", r.orgs); err != nil {
+		return nil, err
+	}
+	if err := storage.Track[Member](w, "members", r.members); err != nil {
+		return nil, err
+	}
+	if err := storage.Track[Collection](w, "collections", r.collections); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteCollection(ctx context.Context, orgID, id string) error {
+	if c, ok := r.collections.Load(id); ok && c.(Collection).OrgID == orgID {
+		_, err := storage.Delete(ctx, r.collections, id)
+		return err
+	}
+	return ErrNotFound
+}
+
+func (r *BasicRepo) DeleteMember(ctx context.Context, orgID, uid string) error {
+	k := memberKey(orgID, uid)
+	if _, ok := r.members.Load(k); ok {
+		_, err := storage.Delete(ctx, r.members, k)
+		return err
+	}
+	return ErrNotFound
+}
+
+func (r *BasicRepo) DeleteOrganization(ctx context.Context, id string) error {
+	if _, ok := r.orgs.Load(id); !ok {
+		return ErrNotFound
+	}
+
+	if _, err := storage.Delete(ctx, r.orgs, id); err != nil {
+		return err
+	}
+	var err error
+	r.members.Range(func(k, v any) bool {
+		if v.(Member).OrgID == id {
+			_, err = storage.Delete(ctx, r.members, k)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	r.collections.Range(func(k, v any) bool {
+		if v.(Collection).OrgID == id {
+			_, err = storage.Delete(ctx, r.collections, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// Export returns the organizations, members and collections sorted by their keys.
+func (r *BasicRepo) Export(_ context.Context) (Dump, error) {
+	var dump Dump
+	r.orgs.Range(func(_, v any) bool {
+		dump.Organizations = append(dump.Organizations, v.(Organization))
+		return true
+	})
+	r.members.Range(func(_, v any) bool {
+		dump.Members = append(dump.Members, v.(Member))
+		return true
+	})
+	r.collections.Range(func(_, v any) bool {
+		dump.Collections = append(dump.Collections, v.(Collection))
+		return true
+	})
+
+	sort.Slice(dump.Organizations, func(i, j int) bool { return dump.Organizations[i].ID < dump.Organizations[j].ID })
+	sort.Slice(dump.Members, func(i, j int) bool {
+		return memberKey(dump.Members[i].OrgID, dump.Members[i].UID) < memberKey(dump.Members[j].OrgID, dump.Members[j].UID)
+	})
+	sort.Slice(dump.Collections, func(i, j int) bool { return dump.Collections[i].ID < dump.Collections[j].ID })
+	return dump, nil
+}
+
+func (r *BasicRepo) GetCollection(_ context.Context, id string) (Collection, error) {
+	if c, ok := r.collections.Load(id); ok {
+		return c.(Collection), nil
+	}
+	return Collection{}, ErrNotFound
+}
+
+func (r *BasicRepo) GetCollections(_ context.Context, orgID string) ([]Collection, error) {
+	if orgID == "" {
+		return nil, ErrMissingArgs
+	}
+
+	cs := make([]Collection, 0)
+	r.collections.Range(func(_, v any) bool {
+		if c := v.(Collection); c.OrgID == orgID {
+			cs = append(cs, c)
+		}
+		return true
+	})
+
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].Name < cs[j].Name
+	})
+	return cs, nil
+}
+
+func (r *BasicRepo) GetMember(_ context.Context, orgID, uid string) (Member, error) {
+	if m, ok := r.members.Load(memberKey(orgID, uid)); ok {
+		return m.(Member), nil
+	}
+	return Member{}, ErrNotFound
+}
+
+func (r *BasicRepo) GetMembers(_ context.Context, orgID string) ([]Member, error) {
+	if orgID == "" {
+		return nil, ErrMissingArgs
+	}
+
+	ms := make([]Member, 0)
+	r.members.Range(func(_, v any) bool {
+		if m := v.(Member); m.OrgID == orgID {
+			ms = append(ms, m)
+		}
+		return true
+	})
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].CreatedAt.Before(ms[j].CreatedAt)
+	})
+	return ms, nil
+}
+
+func (r *BasicRepo) GetOrganizations(_ context.Context, uid string) ([]Membership, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	orgs := make([]Membership, 0)
+	r.members.Range(func(_, v any) bool {
+		m := v.(Member)
+		if m.UID != uid {
+			return true
+		}
+		if o, ok := r.orgs.Load(m.OrgID); ok {
+			orgs = append(orgs, Membership{Organization: o.(Organization), Role: m.Role})
+		}
+		return true
+	})
+
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].Name < orgs[j].Name
+	})
+	return orgs, nil
+}
+
+// Import stores the organizations before their members and collections,
+// so the members and collections of the unknown organizations are rejected.
+// The changes are undone on failure within the unit of work only.
+func (r *BasicRepo) Import(ctx context.Context, dump Dump) error {
+	for _, o := range dump.Organizations {
+		if o.ID == "" || o.Name == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.orgs.Load(o.ID); ok {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.orgs, o.ID, o); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range dump.Members {
+		if m.OrgID == "" || m.UID == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.orgs.Load(m.OrgID); !ok {
+			return ErrNotFound
+		}
+		k := memberKey(m.OrgID, m.UID)
+		if _, ok := r.members.Load(k); ok {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.members, k, m); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range dump.Collections {
+		if c.ID == "" || c.OrgID == "" || c.Name == "" {
+			return ErrMissingArgs
+		}
+		if _, ok := r.orgs.Load(c.OrgID); !ok {
+			return ErrNotFound
+		}
+		if _, ok := r.collections.Load(c.ID); ok || r.hasCollection(c.OrgID, c.Name) {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.collections, c.ID, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BasicRepo) StoreCollection(ctx context.Context, c Collection) (string, error) {
+	if c.OrgID == "" || c.Name == "" {
+		return "", ErrMissingArgs
+	}
+	if _, ok := r.orgs.Load(c.OrgID); !ok {
+		return "", ErrNotFound
+	}
+
+	if r.hasCollection(c.OrgID, c.Name) {
+		return "", ErrExists
+	}
+
+	c.ID = uuid.NewString()
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
+	}
+	if err := storage.Store(ctx, r.collections, c.ID, c); err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
+func (r *BasicRepo) StoreMember(ctx context.Context, m Member) error {
+	if m.OrgID == "" || m.UID == "" {
+		return ErrMissingArgs
+	}
+	if _, ok := r.orgs.Load(m.OrgID); !ok {
+		return ErrNotFound
+	}
+
+	k := memberKey(m.OrgID, m.UID)
+	if em, ok := r.members.Load(k); ok {
+		m.CreatedAt = em.(Member).CreatedAt
+	} else if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	return storage.Store(ctx, r.members, k, m)
+}
+
+func (r *BasicRepo) StoreOrganization(ctx context.Context, org Organization, owner string) (string, error) {
+	if org.Name == "" || owner == "" {
+		return "", ErrMissingArgs
+	}
+
+	org.ID = uuid.NewString()
+	if org.CreatedAt.IsZero() {
+		org.CreatedAt = time.Now().UTC()
+	}
+	if err := storage.Store(ctx, r.orgs, org.ID, org); err != nil {
+		return "", err
+	}
+	err := storage.Store(ctx, r.members, memberKey(org.ID, owner), Member{
+		OrgID:     org.ID,
+		UID:       owner,
+		Role:      RoleOwner,
+		CreatedAt: org.CreatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return org.ID, nil
+}
+
+// hasCollection checks if the organization has the collection with the name.
+func (r *BasicRepo) hasCollection(orgID, name string) bool {
+	var exists bool
+	r.collections.Range(func(_, v any) bool {
+		c := v.(Collection)
+		exists = c.OrgID == orgID && c.Name == name
+		return !exists
+	})
+	return exists
+}
+
+func memberKey(orgID, uid string) string {
+	return orgID + "|" + uid
+}
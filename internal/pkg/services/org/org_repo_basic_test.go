@@ -0,0 +1,187 @@
+package org
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBasicRepo_DeleteMember(t *testing.T) {
+	for _, tt := range getDeleteMemberCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			err := r.DeleteMember(context.Background(), tt.orgID, tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_DeleteOrganization(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              string
+		wantMembers     []string
+		wantCollections []string
+		wantErr         error
+	}{
+		{
+			name: "Unknown organization",
+			id:   "unknown",
+			wantMembers: []string{"testOrg|testOwner", "testOrg|testAdmin", "testOrg|testMember",
+				"testOrg|testReader", "testOrg1|testMember"},
+			wantCollections: []string{"testCollection", "testCollection1"},
+			wantErr:         ErrNotFound,
+		},
+		{
+			name:            "Organization is deleted with its members and collections",
+			id:              "testOrg",
+			wantMembers:     []string{"testOrg1|testMember"},
+			wantCollections: []string{"testCollection1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(getTestRepo())
+			err := r.DeleteOrganization(context.Background(), tt.id)
+			assert.Equal(t, tt.wantErr, err)
+			checkRepoMembers(t, r.members, tt.wantMembers...)
+			checkRepoMembers(t, r.collections, tt.wantCollections...)
+		})
+	}
+}
+
+func TestBasicRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	tr := getTestRepo()
+	dump := Dump{Organizations: tr.orgs, Members: tr.members, Collections: tr.collections}
+
+	r := NewBasicRepo()
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{
+		Organizations: tr.orgs,
+		Members:       []Member{tr.members[4], tr.members[1], tr.members[2], tr.members[0], tr.members[3]},
+		Collections:   tr.collections,
+	}, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Organizations: tr.orgs[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Members: tr.members[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Collections: []Collection{{ID: "c", OrgID: "testOrg", Name: "infra"}}}))
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Organizations: []Organization{{Name: "team"}}}))
+	assert.Equal(t, ErrNotFound, r.Import(ctx, Dump{Members: []Member{{OrgID: "unknown", UID: "testOwner"}}}))
+	assert.Equal(t, ErrNotFound, r.Import(ctx, Dump{Collections: []Collection{{ID: "c", OrgID: "unknown", Name: "x"}}}))
+}
+
+func TestBasicRepo_GetMember(t *testing.T) {
+	for _, tt := range getGetMemberCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetMember(context.Background(), tt.orgID, tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_GetOrganizations(t *testing.T) {
+	for _, tt := range getGetOrganizationsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.GetOrganizations(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_StoreCollection(t *testing.T) {
+	for _, tt := range getStoreCollectionCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.StoreCollection(context.Background(), tt.c)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestBasicRepo_StoreOrganization(t *testing.T) {
+	r := NewBasicRepo()
+	id, err := r.StoreOrganization(context.Background(), Organization{Name: "team"}, "testOwner")
+	assert.NoError(t, err)
+
+	m, err := r.GetMember(context.Background(), id, "testOwner")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleOwner, m.Role)
+}
+
+func TestNewBasicRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantField     string
+		wantFieldType string
+		wantType      string
+	}{
+		{
+			name:          "Basic repo is created",
+			wantField:     "orgs",
+			wantFieldType: "*sync.Map",
+			wantType:      "*org.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBasicRepo()
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.wantType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.wantField, rField.Name)
+			assert.Equal(t, tt.wantFieldType, rField.Type.String())
+		})
+	}
+}
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	r := initPersistentRepo(t, dir)
+	id, err := r.StoreOrganization(ctx, Organization{Name: "team"}, "testOwner")
+	assert.NoError(t, err)
+	assert.NoError(t, r.StoreMember(ctx, Member{OrgID: id, UID: "testMember", Role: RoleMember}))
+	cid, err := r.StoreCollection(ctx, Collection{OrgID: id, Name: "infra"})
+	assert.NoError(t, err)
+	_, err = r.StoreCollection(ctx, Collection{OrgID: id, Name: "marketing"})
+	assert.NoError(t, err)
+	assert.NoError(t, r.DeleteMember(ctx, id, "testMember"))
+	want, err := r.Export(ctx)
+	assert.NoError(t, err)
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	c, err := r.GetCollection(ctx, cid)
+	assert.NoError(t, err)
+	assert.Equal(t, "infra", c.Name)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "orgs", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
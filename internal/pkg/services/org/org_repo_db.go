@@ -0,0 +1,369 @@
+package org
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type DBRepo struct {
+	db *sql.DB
+}
+
+const (
+	DeleteCollection    = "DELETE FROM collections WHERE org_id = $1 AND id = $2"
+	DeleteMember        = "DELETE FROM org_members WHERE org_id = $1 AND uid = $2"
+	DeleteOrganization  = "DELETE FROM organizations WHERE id = $1"
+	ExportCollections   = "SELECT id, org_id, name, created_at FROM collections ORDER BY id"
+	ExportMembers       = "SELECT org_id, uid, role, created_at FROM org_members ORDER BY org_id, uid"
+	ExportOrganizations = "SELECT id, name, created_at FROM organizations ORDER BY id"
+	GetCollection       = "SELECT id, org_id, name, created_at FROM collections WHERE id = $1"
+	GetCollections      = "SELECT id, org_id, name, created_at FROM collections WHERE org_id = $1 ORDER BY name"
+	GetMember           = "SELECT org_id, uid, role, created_at FROM org_members WHERE org_id = $1 AND uid = $2"
+	GetMembers          = "SELECT org_id, uid, role, created_at FROM org_members WHERE org_id = $1 ORDER BY created_at"
+	GetOrganizations    = `
+		SELECT o.id, o.name, o.created_at, m.role FROM organizations o
+		JOIN org_members m ON m.org_id = o.id WHERE m.uid = $1 ORDER BY o.name
+	`
+	ImportCollection   = "INSERT INTO collections(id, org_id, name, created_at) VALUES ($1, $2, $3, $4)"
+	ImportMember       = "INSERT INTO org_members(org_id, uid, role, created_at) VALUES ($1, $2, $3, $4)"
+	ImportOrganization = "INSERT INTO organizations(id, name, created_at) VALUES ($1, $2, $3)"
+	StoreCollection    = "INSERT INTO collections(org_id, name) VALUES ($1, $2) RETURNING id"
+	StoreMember        = `
+		INSERT INTO org_members(org_id, uid, role) VALUES ($1, $2, $3)
+		ON CONFLICT (org_id, uid) DO UPDATE SET role = EXCLUDED.role
+	`
+	StoreOrganization = `
+		WITH o AS (INSERT INTO organizations(name) VALUES ($1) RETURNING id, created_at)
+		INSERT INTO org_members(org_id, uid, role, created_at) SELECT id, $2, $3, created_at FROM o
+		RETURNING org_id
+	`
+)
+
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
+	}
+	return &DBRepo{db: db}, nil
+}
+
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *DBRepo) DeleteCollection(ctx context.Context, orgID, id string) error {
+	if orgID == "" || id == "" {
+		return ErrNotFound
+	}
+	return r.execAffecting(ctx, DeleteCollection, orgID, id)
+}
+
+func (r *DBRepo) DeleteMember(ctx context.Context, orgID, uid string) error {
+	if orgID == "" || uid == "" {
+		return ErrNotFound
+	}
+	return r.execAffecting(ctx, DeleteMember, orgID, uid)
+}
+
+func (r *DBRepo) DeleteOrganization(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrNotFound
+	}
+	return r.execAffecting(ctx, DeleteOrganization, id)
+}
+
+// Export returns the organizations, members and collections sorted by their keys.
+func (r *DBRepo) Export(ctx context.Context) (Dump, error) {
+	var (
+		dump Dump
+		err  error
+	)
+	if dump.Organizations, err = r.exportOrganizations(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Members, err = r.exportMembers(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Collections, err = r.exportCollections(ctx); err != nil {
+		return Dump{}, err
+	}
+	return dump, nil
+}
+
+func (r *DBRepo) GetCollection(ctx context.Context, id string) (Collection, error) {
+	if id == "" {
+		return Collection{}, ErrNotFound
+	}
+
+	var c Collection
+	err := r.conn(ctx).QueryRowContext(ctx, GetCollection, id).Scan(&c.ID, &c.OrgID, &c.Name, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Collection{}, ErrNotFound
+		}
+		return Collection{}, err
+	}
+	return c, nil
+}
+
+func (r *DBRepo) GetCollections(ctx context.Context, orgID string) ([]Collection, error) {
+	if orgID == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetCollections, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	cs := make([]Collection, 0)
+	for rows.Next() {
+		var c Collection
+		if err = rows.Scan(&c.ID, &c.OrgID, &c.Name, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func (r *DBRepo) GetMember(ctx context.Context, orgID, uid string) (Member, error) {
+	if orgID == "" || uid == "" {
+		return Member{}, ErrNotFound
+	}
+
+	var m Member
+	err := r.conn(ctx).QueryRowContext(ctx, GetMember, orgID, uid).Scan(&m.OrgID, &m.UID, &m.Role, &m.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Member{}, ErrNotFound
+		}
+		return Member{}, err
+	}
+	return m, nil
+}
+
+func (r *DBRepo) GetMembers(ctx context.Context, orgID string) ([]Member, error) {
+	if orgID == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetMembers, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	ms := make([]Member, 0)
+	for rows.Next() {
+		var m Member
+		if err = rows.Scan(&m.OrgID, &m.UID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func (r *DBRepo) GetOrganizations(ctx context.Context, uid string) ([]Membership, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetOrganizations, uid)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	orgs := make([]Membership, 0)
+	for rows.Next() {
+		var m Membership
+		if err = rows.Scan(&m.ID, &m.Name, &m.CreatedAt, &m.Role); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, m)
+	}
+	return orgs, nil
+}
+
+// Import stores the organizations before their members and collections,
+// so the members and collections of the unknown organizations are rejected.
+func (r *DBRepo) Import(ctx context.Context, dump Dump) error {
+	for _, o := range dump.Organizations {
+		if o.ID == "" || o.Name == "" {
+			return ErrMissingArgs
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportOrganization, o.ID, o.Name, o.CreatedAt); err != nil {
+			return mapDBError(err)
+		}
+	}
+	for _, m := range dump.Members {
+		if m.OrgID == "" || m.UID == "" {
+			return ErrMissingArgs
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportMember, m.OrgID, m.UID, m.Role, m.CreatedAt); err != nil {
+			return mapDBError(err)
+		}
+	}
+	for _, c := range dump.Collections {
+		if c.ID == "" || c.OrgID == "" || c.Name == "" {
+			return ErrMissingArgs
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportCollection, c.ID, c.OrgID, c.Name, c.CreatedAt); err != nil {
+			return mapDBError(err)
+		}
+	}
+	return nil
+}
+
+func (r *DBRepo) StoreCollection(ctx context.Context, c Collection) (string, error) {
+	if c.OrgID == "" || c.Name == "" {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	if err := r.conn(ctx).QueryRowContext(ctx, StoreCollection, c.OrgID, c.Name).Scan(&id); err != nil {
+		return "", mapDBError(err)
+	}
+	return id, nil
+}
+
+func (r *DBRepo) StoreMember(ctx context.Context, m Member) error {
+	if m.OrgID == "" || m.UID == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, StoreMember, m.OrgID, m.UID, m.Role)
+	return mapDBError(err)
+}
+
+func (r *DBRepo) StoreOrganization(ctx context.Context, org Organization, owner string) (string, error) {
+	if org.Name == "" || owner == "" {
+		return "", ErrMissingArgs
+	}
+
+	var id string
+	if err := r.conn(ctx).QueryRowContext(ctx, StoreOrganization, org.Name, owner, RoleOwner).Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *DBRepo) exportCollections(ctx context.Context) ([]Collection, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportCollections)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var cs []Collection
+	for rows.Next() {
+		var c Collection
+		if err = rows.Scan(&c.ID, &c.OrgID, &c.Name, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func (r *DBRepo) exportMembers(ctx context.Context) ([]Member, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportMembers)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var ms []Member
+	for rows.Next() {
+		var m Member
+		if err = rows.Scan(&m.OrgID, &m.UID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func (r *DBRepo) exportOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var orgs []Organization
+	for rows.Next() {
+		var o Organization
+		if err = rows.Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+	return orgs, nil
+}
+
+func (r *DBRepo) execAffecting(ctx context.Context, query string, args ...any) error {
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *DBRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapDBError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return ErrExists
+		case foreignKeyViolation:
+			return ErrNotFound
+		}
+	}
+	return err
+}
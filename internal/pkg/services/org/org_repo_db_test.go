@@ -0,0 +1,260 @@
+package org
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBRepo_DeleteMember(t *testing.T) {
+	for _, tt := range getDeleteMemberCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.orgID != "" && tt.uid != "" {
+				var rows int64
+				if tt.wantErr == nil {
+					rows = 1
+				}
+				mock.ExpectExec(regexp.QuoteMeta(DeleteMember)).WithArgs(tt.orgID, tt.uid).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.DeleteMember(context.Background(), tt.orgID, tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_Export(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := getTestRepo()
+	orgs := mock.NewRows([]string{"id", "name", "created_at"})
+	for _, o := range tr.orgs {
+		orgs.AddRow(o.ID, o.Name, o.CreatedAt)
+	}
+	members := mock.NewRows([]string{"org_id", "uid", "role", "created_at"})
+	for _, m := range tr.members {
+		members.AddRow(m.OrgID, m.UID, m.Role, m.CreatedAt)
+	}
+	collections := mock.NewRows([]string{"id", "org_id", "name", "created_at"})
+	for _, c := range tr.collections {
+		collections.AddRow(c.ID, c.OrgID, c.Name, c.CreatedAt)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(ExportOrganizations)).WillReturnRows(orgs)
+	mock.ExpectQuery(regexp.QuoteMeta(ExportMembers)).WillReturnRows(members)
+	mock.ExpectQuery(regexp.QuoteMeta(ExportCollections)).WillReturnRows(collections)
+
+	got, err := r.Export(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{Organizations: tr.orgs, Members: tr.members, Collections: tr.collections}, got)
+	checkMetExpectations(t, mock)
+}
+
+func TestDBRepo_GetMember(t *testing.T) {
+	for _, tt := range getGetMemberCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.orgID != "" && tt.uid != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(GetMember)).WithArgs(tt.orgID, tt.uid)
+				if tt.wantErr == nil {
+					eq.WillReturnRows(mock.NewRows([]string{"org_id", "uid", "role", "created_at"}).
+						AddRow(tt.want.OrgID, tt.want.UID, tt.want.Role, tt.want.CreatedAt))
+				} else {
+					eq.WillReturnError(sql.ErrNoRows)
+				}
+			}
+
+			got, err := r.GetMember(context.Background(), tt.orgID, tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_GetOrganizations(t *testing.T) {
+	for _, tt := range getGetOrganizationsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				rows := mock.NewRows([]string{"id", "name", "created_at", "role"})
+				for _, m := range tt.want {
+					rows.AddRow(m.ID, m.Name, m.CreatedAt, m.Role)
+				}
+				mock.ExpectQuery(regexp.QuoteMeta(GetOrganizations)).WithArgs(tt.uid).WillReturnRows(rows)
+			}
+
+			got, err := r.GetOrganizations(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_Import(t *testing.T) {
+	tr := getTestRepo()
+	tests := []struct {
+		name    string
+		dump    Dump
+		dbErr   error
+		wantErr error
+	}{
+		{
+			name:    "Organization ID is missing",
+			dump:    Dump{Organizations: []Organization{{Name: "team"}}},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Organization is unknown",
+			dump:    Dump{Organizations: tr.orgs[:1], Members: tr.members[:1]},
+			dbErr:   &pgconn.PgError{Code: foreignKeyViolation},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Organization is imported",
+			dump: Dump{Organizations: tr.orgs[:1], Members: tr.members[:1], Collections: tr.collections[:1]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != ErrMissingArgs {
+				o, m := tt.dump.Organizations[0], tt.dump.Members[0]
+				mock.ExpectExec(regexp.QuoteMeta(ImportOrganization)).WithArgs(o.ID, o.Name, o.CreatedAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				em := mock.ExpectExec(regexp.QuoteMeta(ImportMember)).WithArgs(m.OrgID, m.UID, m.Role, m.CreatedAt)
+				if tt.dbErr != nil {
+					em.WillReturnError(tt.dbErr)
+				} else {
+					em.WillReturnResult(sqlmock.NewResult(1, 1))
+					c := tt.dump.Collections[0]
+					mock.ExpectExec(regexp.QuoteMeta(ImportCollection)).WithArgs(c.ID, c.OrgID, c.Name, c.CreatedAt).
+						WillReturnResult(sqlmock.NewResult(1, 1))
+				}
+			}
+
+			assert.Equal(t, tt.wantErr, r.Import(context.Background(), tt.dump))
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreCollection(t *testing.T) {
+	for _, tt := range getStoreCollectionCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.c.OrgID != "" && tt.c.Name != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(StoreCollection)).WithArgs(tt.c.OrgID, tt.c.Name)
+				if tt.wantErr != nil {
+					eq.WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+				} else {
+					eq.WillReturnRows(mock.NewRows([]string{"id"}).AddRow("testCollection2"))
+				}
+			}
+
+			got, err := r.StoreCollection(context.Background(), tt.c)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreOrganization(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(StoreOrganization)).WithArgs("team", "testOwner", RoleOwner).
+		WillReturnRows(mock.NewRows([]string{"org_id"}).AddRow("testOrg"))
+
+	got, err := r.StoreOrganization(context.Background(), Organization{Name: "team"}, "testOwner")
+	assert.NoError(t, err)
+	assert.Equal(t, "testOrg", got)
+	checkMetExpectations(t, mock)
+}
+
+func TestNewDBRepo(t *testing.T) {
+	type want struct {
+		repoType  string
+		fieldName string
+		fieldType string
+	}
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "DB is missing",
+			wantErr: true,
+			want: want{
+				repoType:  "*org.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: want{
+				repoType:  "*org.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDBRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want.repoType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.want.fieldName, rField.Name)
+			assert.Equal(t, tt.want.fieldType, rField.Type.String())
+		})
+	}
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
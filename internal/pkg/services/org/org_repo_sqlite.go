@@ -0,0 +1,358 @@
+package org
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteOrganizations = `CREATE TABLE IF NOT EXISTS organizations(
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL)`
+	createSQLiteMembers = `CREATE TABLE IF NOT EXISTS org_members(
+		org_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+		uid TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY(org_id, uid))`
+	createSQLiteCollections = `CREATE TABLE IF NOT EXISTS collections(
+		id TEXT PRIMARY KEY,
+		org_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		UNIQUE(org_id, name))`
+	sqliteDeleteCollection    = "DELETE FROM collections WHERE org_id = ?1 AND id = ?2"
+	sqliteDeleteMember        = "DELETE FROM org_members WHERE org_id = ?1 AND uid = ?2"
+	sqliteDeleteOrganization  = "DELETE FROM organizations WHERE id = ?1"
+	sqliteExportCollections   = "SELECT id, org_id, name, created_at FROM collections ORDER BY id"
+	sqliteExportMembers       = "SELECT org_id, uid, role, created_at FROM org_members ORDER BY org_id, uid"
+	sqliteExportOrganizations = "SELECT id, name, created_at FROM organizations ORDER BY id"
+	sqliteGetCollection       = "SELECT id, org_id, name, created_at FROM collections WHERE id = ?1"
+	sqliteGetCollections      = "SELECT id, org_id, name, created_at FROM collections WHERE org_id = ?1 ORDER BY name"
+	sqliteGetMember           = "SELECT org_id, uid, role, created_at FROM org_members WHERE org_id = ?1 AND uid = ?2"
+	sqliteGetMembers          = `
+		SELECT org_id, uid, role, created_at FROM org_members WHERE org_id = ?1 ORDER BY created_at
+	`
+	sqliteGetOrganizations = `
+		SELECT o.id, o.name, o.created_at, m.role FROM organizations o
+		JOIN org_members m ON m.org_id = o.id WHERE m.uid = ?1 ORDER BY o.name
+	`
+	sqliteStoreCollection   = "INSERT INTO collections(id, org_id, name, created_at) VALUES (?1, ?2, ?3, ?4)"
+	sqliteStoreOrganization = "INSERT INTO organizations(id, name, created_at) VALUES (?1, ?2, ?3)"
+	sqliteImportMember      = "INSERT INTO org_members(org_id, uid, role, created_at) VALUES (?1, ?2, ?3, ?4)"
+	sqliteStoreMember       = `
+		INSERT INTO org_members(org_id, uid, role, created_at) VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (org_id, uid) DO UPDATE SET role = excluded.role
+	`
+)
+
+// NewSQLiteRepo returns the repository keeping the organizations, members and collections
+// in the SQLite database, and creates its tables if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	for _, q := range []string{createSQLiteOrganizations, createSQLiteMembers, createSQLiteCollections} {
+		if _, err := db.ExecContext(context.Background(), q); err != nil {
+			return &SQLiteRepo{}, err
+		}
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteCollection(ctx context.Context, orgID, id string) error {
+	if orgID == "" || id == "" {
+		return ErrNotFound
+	}
+	return r.execAffecting(ctx, sqliteDeleteCollection, orgID, id)
+}
+
+func (r *SQLiteRepo) DeleteMember(ctx context.Context, orgID, uid string) error {
+	if orgID == "" || uid == "" {
+		return ErrNotFound
+	}
+	return r.execAffecting(ctx, sqliteDeleteMember, orgID, uid)
+}
+
+func (r *SQLiteRepo) DeleteOrganization(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrNotFound
+	}
+	return r.execAffecting(ctx, sqliteDeleteOrganization, id)
+}
+
+// Export returns the organizations, members and collections sorted by their keys.
+func (r *SQLiteRepo) Export(ctx context.Context) (Dump, error) {
+	var (
+		dump Dump
+		err  error
+	)
+	if dump.Organizations, err = r.exportOrganizations(ctx); err != nil {
+		return Dump{}, err
+	}
+	if dump.Members, err = r.queryMembers(ctx, sqliteExportMembers); err != nil {
+		return Dump{}, err
+	}
+	if dump.Collections, err = r.queryCollections(ctx, sqliteExportCollections); err != nil {
+		return Dump{}, err
+	}
+	return dump, nil
+}
+
+func (r *SQLiteRepo) GetCollection(ctx context.Context, id string) (Collection, error) {
+	if id == "" {
+		return Collection{}, ErrNotFound
+	}
+
+	var c Collection
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetCollection, id).Scan(&c.ID, &c.OrgID, &c.Name, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Collection{}, ErrNotFound
+		}
+		return Collection{}, err
+	}
+	return c, nil
+}
+
+func (r *SQLiteRepo) GetCollections(ctx context.Context, orgID string) ([]Collection, error) {
+	if orgID == "" {
+		return nil, ErrMissingArgs
+	}
+	return r.queryCollections(ctx, sqliteGetCollections, orgID)
+}
+
+func (r *SQLiteRepo) GetMember(ctx context.Context, orgID, uid string) (Member, error) {
+	if orgID == "" || uid == "" {
+		return Member{}, ErrNotFound
+	}
+
+	var m Member
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetMember, orgID, uid).Scan(&m.OrgID, &m.UID, &m.Role, &m.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Member{}, ErrNotFound
+		}
+		return Member{}, err
+	}
+	return m, nil
+}
+
+func (r *SQLiteRepo) GetMembers(ctx context.Context, orgID string) ([]Member, error) {
+	if orgID == "" {
+		return nil, ErrMissingArgs
+	}
+	return r.queryMembers(ctx, sqliteGetMembers, orgID)
+}
+
+func (r *SQLiteRepo) GetOrganizations(ctx context.Context, uid string) ([]Membership, error) {
+	if uid == "" {
+		return nil, ErrMissingArgs
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteGetOrganizations, uid)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	orgs := make([]Membership, 0)
+	for rows.Next() {
+		var m Membership
+		if err = rows.Scan(&m.ID, &m.Name, &m.CreatedAt, &m.Role); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, m)
+	}
+	return orgs, nil
+}
+
+// Import stores the organizations before their members and collections,
+// so the members and collections of the unknown organizations are rejected.
+func (r *SQLiteRepo) Import(ctx context.Context, dump Dump) error {
+	for _, o := range dump.Organizations {
+		if o.ID == "" || o.Name == "" {
+			return ErrMissingArgs
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreOrganization, o.ID, o.Name, o.CreatedAt.UTC())
+		if err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	for _, m := range dump.Members {
+		if m.OrgID == "" || m.UID == "" {
+			return ErrMissingArgs
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, sqliteImportMember, m.OrgID, m.UID, m.Role, m.CreatedAt.UTC())
+		if err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	for _, c := range dump.Collections {
+		if c.ID == "" || c.OrgID == "" || c.Name == "" {
+			return ErrMissingArgs
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreCollection, c.ID, c.OrgID, c.Name, c.CreatedAt.UTC())
+		if err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) StoreCollection(ctx context.Context, c Collection) (string, error) {
+	if c.OrgID == "" || c.Name == "" {
+		return "", ErrMissingArgs
+	}
+
+	id := uuid.NewString()
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreCollection, id, c.OrgID, c.Name, time.Now().UTC())
+	if err != nil {
+		return "", mapSQLiteError(err)
+	}
+	return id, nil
+}
+
+// StoreMember updates the role of the existing member, keeping the date the user joined the organization.
+func (r *SQLiteRepo) StoreMember(ctx context.Context, m Member) error {
+	if m.OrgID == "" || m.UID == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreMember, m.OrgID, m.UID, m.Role, time.Now().UTC())
+	return mapSQLiteError(err)
+}
+
+// StoreOrganization stores the organization along with its owner, who joins it at the creation time.
+func (r *SQLiteRepo) StoreOrganization(ctx context.Context, org Organization, owner string) (string, error) {
+	if org.Name == "" || owner == "" {
+		return "", ErrMissingArgs
+	}
+
+	id, now := uuid.NewString(), time.Now().UTC()
+	if _, err := r.conn(ctx).ExecContext(ctx, sqliteStoreOrganization, id, org.Name, now); err != nil {
+		return "", err
+	}
+	if _, err := r.conn(ctx).ExecContext(ctx, sqliteImportMember, id, owner, RoleOwner, now); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepo) exportOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteExportOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	var orgs []Organization
+	for rows.Next() {
+		var o Organization
+		if err = rows.Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+	return orgs, nil
+}
+
+func (r *SQLiteRepo) queryCollections(ctx context.Context, query string, args ...any) ([]Collection, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	cs := make([]Collection, 0)
+	for rows.Next() {
+		var c Collection
+		if err = rows.Scan(&c.ID, &c.OrgID, &c.Name, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func (r *SQLiteRepo) queryMembers(ctx context.Context, query string, args ...any) ([]Member, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	ms := make([]Member, 0)
+	for rows.Next() {
+		var m Member
+		if err = rows.Scan(&m.OrgID, &m.UID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func (r *SQLiteRepo) execAffecting(ctx context.Context, query string, args ...any) error {
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func mapSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintUnique:
+			return ErrExists
+		case sqlite3.ErrConstraintForeignKey:
+			return ErrNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,174 @@
+package org
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteMember(t *testing.T) {
+	for _, tt := range getDeleteMemberCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			err := r.DeleteMember(context.Background(), tt.orgID, tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_DeleteOrganization(t *testing.T) {
+	ctx := context.Background()
+	tr := getTestRepo()
+	r := initSQLiteRepo(t, tr)
+	assert.Equal(t, ErrNotFound, r.DeleteOrganization(ctx, "unknown"))
+	assert.NoError(t, r.DeleteOrganization(ctx, "testOrg"))
+
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{
+		Organizations: tr.orgs[1:],
+		Members:       tr.members[4:],
+		Collections:   tr.collections[1:],
+	}, got)
+}
+
+func TestSQLiteRepo_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	tr := getTestRepo()
+	dump := Dump{Organizations: tr.orgs, Members: tr.members, Collections: tr.collections}
+
+	r := initSQLiteRepo(t, testRepo{})
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Dump{
+		Organizations: tr.orgs,
+		Members:       []Member{tr.members[1], tr.members[2], tr.members[0], tr.members[3], tr.members[4]},
+		Collections:   tr.collections,
+	}, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Organizations: tr.orgs[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Members: tr.members[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Collections: []Collection{{ID: "c", OrgID: "testOrg", Name: "infra"}}}))
+	assert.Equal(t, ErrMissingArgs, r.Import(ctx, Dump{Organizations: []Organization{{Name: "team"}}}))
+	assert.Equal(t, ErrNotFound, r.Import(ctx, Dump{Members: []Member{{OrgID: "unknown", UID: "testOwner"}}}))
+	assert.Equal(t, ErrNotFound, r.Import(ctx, Dump{Collections: []Collection{{ID: "c", OrgID: "unknown", Name: "x"}}}))
+}
+
+func TestSQLiteRepo_GetMember(t *testing.T) {
+	for _, tt := range getGetMemberCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.GetMember(context.Background(), tt.orgID, tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_GetOrganizations(t *testing.T) {
+	for _, tt := range getGetOrganizationsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.GetOrganizations(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreCollection(t *testing.T) {
+	for _, tt := range getStoreCollectionCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.StoreCollection(context.Background(), tt.c)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestSQLiteRepo_StoreMember(t *testing.T) {
+	ctx := context.Background()
+	tr := getTestRepo()
+	r := initSQLiteRepo(t, tr)
+	assert.Equal(t, ErrNotFound, r.StoreMember(ctx, Member{OrgID: "unknown", UID: "testUser", Role: RoleMember}))
+	assert.NoError(t, r.StoreMember(ctx, Member{OrgID: "testOrg", UID: "testReader", Role: RoleAdmin}))
+
+	got, err := r.GetMember(ctx, "testOrg", "testReader")
+	assert.NoError(t, err)
+	assert.Equal(t, Member{OrgID: "testOrg", UID: "testReader", Role: RoleAdmin, CreatedAt: tr.members[3].CreatedAt}, got)
+}
+
+func TestSQLiteRepo_StoreOrganization(t *testing.T) {
+	r := initSQLiteRepo(t, testRepo{})
+	id, err := r.StoreOrganization(context.Background(), Organization{Name: "team"}, "testOwner")
+	assert.NoError(t, err)
+
+	m, err := r.GetMember(context.Background(), id, "testOwner")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleOwner, m.Role)
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*org.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*org.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, data testRepo) *SQLiteRepo {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dump := Dump{Organizations: data.orgs, Members: data.members, Collections: data.collections}
+	if err = r.Import(context.Background(), dump); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
@@ -0,0 +1,237 @@
+package org
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRepo struct {
+	orgs        []Organization
+	members     []Member
+	collections []Collection
+}
+
+type deleteMemberCase struct {
+	name    string
+	repo    testRepo
+	orgID   string
+	uid     string
+	wantErr error
+}
+
+type getMemberCase struct {
+	name    string
+	repo    testRepo
+	orgID   string
+	uid     string
+	want    Member
+	wantErr error
+}
+
+type getOrganizationsCase struct {
+	name    string
+	repo    testRepo
+	uid     string
+	want    []Membership
+	wantErr error
+}
+
+type storeCollectionCase struct {
+	name    string
+	repo    testRepo
+	c       Collection
+	wantErr error
+}
+
+var testCreated = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DB is missing",
+			want: "*org.BasicRepo",
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*org.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*org.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRepo(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want, rGot.Type().String())
+		})
+	}
+}
+
+func initBasicRepo(data testRepo) *BasicRepo {
+	r := NewBasicRepo()
+	for _, o := range data.orgs {
+		r.orgs.Store(o.ID, o)
+	}
+	for _, m := range data.members {
+		r.members.Store(memberKey(m.OrgID, m.UID), m)
+	}
+	for _, c := range data.collections {
+		r.collections.Store(c.ID, c)
+	}
+	return r
+}
+
+func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	return &DBRepo{db: db}, mock, err
+}
+
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getTestRepo() testRepo {
+	return testRepo{
+		orgs: []Organization{
+			{ID: "testOrg", Name: "team", CreatedAt: testCreated},
+			{ID: "testOrg1", Name: "another team", CreatedAt: testCreated},
+		},
+		members: []Member{
+			{OrgID: "testOrg", UID: "testOwner", Role: RoleOwner, CreatedAt: testCreated},
+			{OrgID: "testOrg", UID: "testAdmin", Role: RoleAdmin, CreatedAt: testCreated.Add(time.Minute)},
+			{OrgID: "testOrg", UID: "testMember", Role: RoleMember, CreatedAt: testCreated.Add(2 * time.Minute)},
+			{OrgID: "testOrg", UID: "testReader", Role: RoleReadOnly, CreatedAt: testCreated.Add(3 * time.Minute)},
+			{OrgID: "testOrg1", UID: "testMember", Role: RoleOwner, CreatedAt: testCreated},
+		},
+		collections: []Collection{
+			{ID: "testCollection", OrgID: "testOrg", Name: "infra", CreatedAt: testCreated},
+			{ID: "testCollection1", OrgID: "testOrg1", Name: "infra", CreatedAt: testCreated},
+		},
+	}
+}
+
+func getDeleteMemberCases() []deleteMemberCase {
+	return []deleteMemberCase{
+		{
+			name:    "No arguments passed",
+			repo:    getTestRepo(),
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "User is not a member",
+			repo:    getTestRepo(),
+			orgID:   "testOrg1",
+			uid:     "testOwner",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:  "Member is deleted",
+			repo:  getTestRepo(),
+			orgID: "testOrg",
+			uid:   "testMember",
+		},
+	}
+}
+
+func getGetMemberCases() []getMemberCase {
+	tr := getTestRepo()
+	return []getMemberCase{
+		{
+			name:    "No arguments passed",
+			repo:    tr,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "User is not a member",
+			repo:    tr,
+			orgID:   "testOrg1",
+			uid:     "testOwner",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:  "Member is returned",
+			repo:  tr,
+			orgID: "testOrg",
+			uid:   "testAdmin",
+			want:  tr.members[1],
+		},
+	}
+}
+
+func getGetOrganizationsCases() []getOrganizationsCase {
+	tr := getTestRepo()
+	return []getOrganizationsCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "User is not a member of any organization",
+			repo: tr,
+			uid:  "testUser",
+			want: []Membership{},
+		},
+		{
+			name: "User organizations are returned",
+			repo: tr,
+			uid:  "testMember",
+			want: []Membership{
+				{Organization: tr.orgs[1], Role: RoleOwner},
+				{Organization: tr.orgs[0], Role: RoleMember},
+			},
+		},
+	}
+}
+
+func getStoreCollectionCases() []storeCollectionCase {
+	return []storeCollectionCase{
+		{
+			name:    "No arguments passed",
+			repo:    getTestRepo(),
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:    "Name is taken",
+			repo:    getTestRepo(),
+			c:       Collection{OrgID: "testOrg", Name: "infra"},
+			wantErr: ErrExists,
+		},
+		{
+			name: "Collection is stored",
+			repo: getTestRepo(),
+			c:    Collection{OrgID: "testOrg", Name: "marketing"},
+		},
+	}
+}
+
+func checkRepoMembers(t *testing.T, m *sync.Map, want ...string) {
+	var got []string
+	m.Range(func(k, _ any) bool {
+		got = append(got, k.(string))
+		return true
+	})
+	assert.ElementsMatch(t, want, got)
+}
@@ -0,0 +1,281 @@
+package org
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+var (
+	ErrForbidden   = errors.New("the member role doesn't permit the operation")
+	ErrInvalidRole = errors.New("the member role is invalid")
+	ErrLastOwner   = errors.New("the organization must have at least one owner")
+)
+
+type IRepository interface {
+	DeleteCollection(ctx context.Context, orgID, id string) error
+	DeleteMember(ctx context.Context, orgID, uid string) error
+	DeleteOrganization(ctx context.Context, id string) error
+	Export(ctx context.Context) (Dump, error)
+	GetCollection(ctx context.Context, id string) (Collection, error)
+	GetCollections(ctx context.Context, orgID string) ([]Collection, error)
+	GetMember(ctx context.Context, orgID, uid string) (Member, error)
+	GetMembers(ctx context.Context, orgID string) ([]Member, error)
+	GetOrganizations(ctx context.Context, uid string) ([]Membership, error)
+	Import(ctx context.Context, dump Dump) error
+	StoreCollection(ctx context.Context, c Collection) (string, error)
+	StoreMember(ctx context.Context, m Member) error
+	StoreOrganization(ctx context.Context, org Organization, owner string) (string, error)
+}
+
+type Service struct {
+	db          IRepository
+	uow         storage.UnitOfWork
+	dataService data.Service
+}
+
+// NewService returns an instance of the Service with the associated repository.
+// The data microservice is used to remove the items stored in the deleted collections.
+// Without the database handle, the organizations are persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL, ds data.Service) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo, uow: storage.NewUnitOfWork(db), dataService: ds}, err
+}
+
+// AuthorizeVault checks if the user is a member of the organization owning the collection,
+// and returns the user's role in it.
+func (s Service) AuthorizeVault(ctx context.Context, uid, collectionID string) (Role, error) {
+	c, err := s.db.GetCollection(ctx, collectionID)
+	if err != nil {
+		return "", err
+	}
+
+	m, err := s.db.GetMember(ctx, c.OrgID, uid)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", ErrForbidden
+		}
+		return "", err
+	}
+	return m.Role, nil
+}
+
+// CreateOrganization stores a new organization with the user as its owner.
+func (s Service) CreateOrganization(ctx context.Context, uid, name string) (string, error) {
+	return s.db.StoreOrganization(ctx, Organization{Name: name}, uid)
+}
+
+// DeleteOrganization removes the organization along with its collections and their items.
+// Only the organization owner can delete it. The items are removed within the same unit of work,
+// so the failed deletion leaves the organization with all its items.
+func (s Service) DeleteOrganization(ctx context.Context, uid, orgID string) error {
+	if _, err := s.authorize(ctx, uid, orgID, isOwner); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		cs, err := s.db.GetCollections(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		for _, c := range cs {
+			if err = s.dataService.DeleteAllSecureData(ctx, c.ID); err != nil {
+				return err
+			}
+		}
+		return s.db.DeleteOrganization(ctx, orgID)
+	})
+}
+
+// Export returns all organizations along with their members and collections.
+// The items stored in the collections are exported by the data microservice.
+func (s Service) Export(ctx context.Context) (Dump, error) {
+	return s.db.Export(ctx)
+}
+
+// Import stores the exported organizations, members and collections with their IDs, as is.
+// Run it within the unit of work, so the failed import leaves no partial changes behind.
+func (s Service) Import(ctx context.Context, dump Dump) error {
+	return s.db.Import(ctx, dump)
+}
+
+// GetOrganizations returns the list of organizations the user is a member of.
+func (s Service) GetOrganizations(ctx context.Context, uid string) ([]Membership, error) {
+	return s.db.GetOrganizations(ctx, uid)
+}
+
+// DeleteUserMemberships removes the user from all organizations, e.g. when the user account is deleted.
+// If the user is the last owner of any organization, nothing is removed and ErrLastOwner is returned,
+// so the ownership must be transferred first.
+func (s Service) DeleteUserMemberships(ctx context.Context, uid string) error {
+	ms, err := s.db.GetOrganizations(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		if m.Role != RoleOwner {
+			continue
+		}
+		if err = s.checkOtherOwners(ctx, m.ID, uid); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range ms {
+		if err = s.db.DeleteMember(ctx, m.ID, uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMembers returns the list of the organization members. Any member can see the list.
+func (s Service) GetMembers(ctx context.Context, uid, orgID string) ([]Member, error) {
+	if _, err := s.authorize(ctx, uid, orgID, isAny); err != nil {
+		return nil, err
+	}
+	return s.db.GetMembers(ctx, orgID)
+}
+
+// SetMember adds the user to the organization or changes the role of the existing member.
+// The owners and admins can manage the members, but only the owners can grant or revoke the owner role.
+func (s Service) SetMember(ctx context.Context, uid, orgID, memberID string, role Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	r, err := s.authorize(ctx, uid, orgID, Role.CanManage)
+	if err != nil {
+		return err
+	}
+
+	cur, err := s.db.GetMember(ctx, orgID, memberID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if (role == RoleOwner || cur.Role == RoleOwner) && r != RoleOwner {
+		return ErrForbidden
+	}
+	if cur.Role == RoleOwner && role != RoleOwner {
+		if err = s.checkOtherOwners(ctx, orgID, memberID); err != nil {
+			return err
+		}
+	}
+
+	return s.db.StoreMember(ctx, Member{OrgID: orgID, UID: memberID, Role: role})
+}
+
+// RemoveMember removes the user from the organization.
+// The owners and admins can remove other members, and any member can leave the organization.
+func (s Service) RemoveMember(ctx context.Context, uid, orgID, memberID string) error {
+	check := Role.CanManage
+	if uid == memberID {
+		check = isAny
+	}
+
+	r, err := s.authorize(ctx, uid, orgID, check)
+	if err != nil {
+		return err
+	}
+
+	cur, err := s.db.GetMember(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if cur.Role == RoleOwner {
+		if r != RoleOwner {
+			return ErrForbidden
+		}
+		if err = s.checkOtherOwners(ctx, orgID, memberID); err != nil {
+			return err
+		}
+	}
+	return s.db.DeleteMember(ctx, orgID, memberID)
+}
+
+// CreateCollection stores a new collection in the organization. Only the owners and admins can create it.
+func (s Service) CreateCollection(ctx context.Context, uid, orgID, name string) (string, error) {
+	if _, err := s.authorize(ctx, uid, orgID, Role.CanManage); err != nil {
+		return "", err
+	}
+	return s.db.StoreCollection(ctx, Collection{OrgID: orgID, Name: name})
+}
+
+// DeleteCollection removes the collection along with its items within the same unit of work.
+// Only the owners and admins can delete it.
+func (s Service) DeleteCollection(ctx context.Context, uid, orgID, id string) error {
+	if _, err := s.authorize(ctx, uid, orgID, Role.CanManage); err != nil {
+		return err
+	}
+
+	c, err := s.db.GetCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.OrgID != orgID {
+		return ErrNotFound
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.dataService.DeleteAllSecureData(ctx, id); err != nil {
+			return err
+		}
+		return s.db.DeleteCollection(ctx, orgID, id)
+	})
+}
+
+// GetCollections returns the list of the organization collections. Any member can see the list.
+func (s Service) GetCollections(ctx context.Context, uid, orgID string) ([]Collection, error) {
+	if _, err := s.authorize(ctx, uid, orgID, isAny); err != nil {
+		return nil, err
+	}
+	return s.db.GetCollections(ctx, orgID)
+}
+
+// GetCollection returns the collection by the ID, e.g. to name the owner of the items stored in it.
+// The access to the collection is not checked, so the caller must not expose its content.
+func (s Service) GetCollection(ctx context.Context, id string) (Collection, error) {
+	if id == "" {
+		return Collection{}, ErrMissingArgs
+	}
+	return s.db.GetCollection(ctx, id)
+}
+
+// authorize checks if the user is a member of the organization with the role passing the check.
+// The organization is reported as missing to the users outside of it.
+func (s Service) authorize(ctx context.Context, uid, orgID string, check func(Role) bool) (Role, error) {
+	m, err := s.db.GetMember(ctx, orgID, uid)
+	if err != nil {
+		return "", err
+	}
+	if !check(m.Role) {
+		return "", ErrForbidden
+	}
+	return m.Role, nil
+}
+
+func (s Service) checkOtherOwners(ctx context.Context, orgID, uid string) error {
+	ms, err := s.db.GetMembers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		if m.Role == RoleOwner && m.UID != uid {
+			return nil
+		}
+	}
+	return ErrLastOwner
+}
+
+func isAny(Role) bool {
+	return true
+}
+
+func isOwner(r Role) bool {
+	return r == RoleOwner
+}
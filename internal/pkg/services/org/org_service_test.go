@@ -0,0 +1,354 @@
+package org
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+var errTest = errors.New("test error")
+
+// failingRepo fails the deletions of the organizations and collections, as the lost database connection does.
+type failingRepo struct {
+	IRepository
+}
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *sql.DB
+		wantRepoType string
+		wantErr      bool
+	}{
+		{
+			name:         "DB is missing",
+			wantRepoType: "*org.BasicRepo",
+		},
+		{
+			name:         "DB is present",
+			db:           initDB(t),
+			wantRepoType: "*org.DBRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.db, nil, data.Service{})
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rRepo := reflect.ValueOf(got.db)
+			assert.Equal(t, tt.wantRepoType, rRepo.Type().String())
+		})
+	}
+}
+
+func TestService_AuthorizeVault(t *testing.T) {
+	tests := []struct {
+		name         string
+		uid          string
+		collectionID string
+		want         Role
+		wantErr      error
+	}{
+		{
+			name:         "Unknown collection",
+			uid:          "testOwner",
+			collectionID: "unknown",
+			wantErr:      ErrNotFound,
+		},
+		{
+			name:         "User is not a member",
+			uid:          "testOwner",
+			collectionID: "testCollection1",
+			wantErr:      ErrForbidden,
+		},
+		{
+			name:         "Member role is returned",
+			uid:          "testReader",
+			collectionID: "testCollection",
+			want:         RoleReadOnly,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t)
+			got, err := s.AuthorizeVault(context.Background(), tt.uid, tt.collectionID)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_CreateCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		orgID   string
+		wantErr error
+	}{
+		{
+			name:    "User is not a member",
+			uid:     "testUser",
+			orgID:   "testOrg",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Member can't manage collections",
+			uid:     "testMember",
+			orgID:   "testOrg",
+			wantErr: ErrForbidden,
+		},
+		{
+			name:  "Admin creates the collection",
+			uid:   "testAdmin",
+			orgID: "testOrg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t)
+			got, err := s.CreateCollection(context.Background(), tt.uid, tt.orgID, "marketing")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, err == nil, got != "")
+		})
+	}
+}
+
+func TestService_DeleteCollection(t *testing.T) {
+	s := initService(t)
+	ctx := context.Background()
+	if _, err := s.dataService.StoreSecureDataFromPayload(ctx, "testCollection", []byte("secret"),
+		data.SText); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ErrForbidden, s.DeleteCollection(ctx, "testReader", "testOrg", "testCollection"))
+	assert.Equal(t, ErrNotFound, s.DeleteCollection(ctx, "testOwner", "testOrg", "testCollection1"))
+	assert.NoError(t, s.DeleteCollection(ctx, "testAdmin", "testOrg", "testCollection"))
+
+	got, err := s.dataService.GetAllDataByType(ctx, "testCollection", data.SText)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestService_DeleteCollection_Failed(t *testing.T) {
+	s := initService(t)
+	ctx := context.Background()
+	if _, err := s.dataService.StoreSecureDataFromPayload(ctx, "testCollection", []byte("secret"),
+		data.SText); err != nil {
+		t.Fatal(err)
+	}
+
+	s.db = failingRepo{IRepository: s.db}
+	assert.Equal(t, errTest, s.DeleteCollection(ctx, "testAdmin", "testOrg", "testCollection"))
+
+	got, err := s.dataService.GetAllDataByType(ctx, "testCollection", data.SText)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestService_DeleteOrganization(t *testing.T) {
+	s := initService(t)
+	ctx := context.Background()
+
+	assert.Equal(t, ErrForbidden, s.DeleteOrganization(ctx, "testAdmin", "testOrg"))
+	assert.NoError(t, s.DeleteOrganization(ctx, "testOwner", "testOrg"))
+
+	_, err := s.GetMembers(ctx, "testOwner", "testOrg")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestService_DeleteOrganization_Failed(t *testing.T) {
+	s := initService(t)
+	ctx := context.Background()
+	if _, err := s.dataService.StoreSecureDataFromPayload(ctx, "testCollection", []byte("secret"),
+		data.SText); err != nil {
+		t.Fatal(err)
+	}
+
+	s.db = failingRepo{IRepository: s.db}
+	assert.Equal(t, errTest, s.DeleteOrganization(ctx, "testOwner", "testOrg"))
+
+	got, err := s.dataService.GetAllDataByType(ctx, "testCollection", data.SText)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestService_DeleteUserMemberships(t *testing.T) {
+	tests := []struct {
+		name     string
+		uid      string
+		wantOrgs int
+		wantErr  error
+	}{
+		{
+			name:    "No user ID passed",
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name:     "Last owner of one organization keeps all memberships",
+			uid:      "testMember",
+			wantOrgs: 2,
+			wantErr:  ErrLastOwner,
+		},
+		{
+			name: "Memberships are deleted",
+			uid:  "testAdmin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t)
+			assert.Equal(t, tt.wantErr, s.DeleteUserMemberships(context.Background(), tt.uid))
+
+			if tt.uid != "" {
+				orgs, err := s.GetOrganizations(context.Background(), tt.uid)
+				assert.NoError(t, err)
+				assert.Len(t, orgs, tt.wantOrgs)
+			}
+		})
+	}
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		uid      string
+		memberID string
+		wantErr  error
+	}{
+		{
+			name:     "Member can't remove others",
+			uid:      "testMember",
+			memberID: "testReader",
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "Member leaves the organization",
+			uid:      "testMember",
+			memberID: "testMember",
+		},
+		{
+			name:     "Admin can't remove the owner",
+			uid:      "testAdmin",
+			memberID: "testOwner",
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "Last owner can't leave",
+			uid:      "testOwner",
+			memberID: "testOwner",
+			wantErr:  ErrLastOwner,
+		},
+		{
+			name:     "Admin removes the member",
+			uid:      "testAdmin",
+			memberID: "testReader",
+		},
+		{
+			name:     "Unknown member",
+			uid:      "testAdmin",
+			memberID: "testUser",
+			wantErr:  ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t)
+			err := s.RemoveMember(context.Background(), tt.uid, "testOrg", tt.memberID)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_SetMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		uid      string
+		memberID string
+		role     Role
+		wantErr  error
+	}{
+		{
+			name:     "Invalid role",
+			uid:      "testOwner",
+			memberID: "testUser",
+			role:     "guest",
+			wantErr:  ErrInvalidRole,
+		},
+		{
+			name:     "Member can't add others",
+			uid:      "testMember",
+			memberID: "testUser",
+			role:     RoleReadOnly,
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "Admin can't grant the owner role",
+			uid:      "testAdmin",
+			memberID: "testMember",
+			role:     RoleOwner,
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "Admin can't demote the owner",
+			uid:      "testAdmin",
+			memberID: "testOwner",
+			role:     RoleMember,
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "Last owner can't be demoted",
+			uid:      "testOwner",
+			memberID: "testOwner",
+			role:     RoleAdmin,
+			wantErr:  ErrLastOwner,
+		},
+		{
+			name:     "Admin adds a new member",
+			uid:      "testAdmin",
+			memberID: "testUser",
+			role:     RoleMember,
+		},
+		{
+			name:     "Owner grants the owner role",
+			uid:      "testOwner",
+			memberID: "testAdmin",
+			role:     RoleOwner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t)
+			err := s.SetMember(context.Background(), tt.uid, "testOrg", tt.memberID, tt.role)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				m, mErr := s.db.GetMember(context.Background(), "testOrg", tt.memberID)
+				assert.NoError(t, mErr)
+				assert.Equal(t, tt.role, m.Role)
+			}
+		})
+	}
+}
+
+func (failingRepo) DeleteCollection(context.Context, string, string) error {
+	return errTest
+}
+
+func (failingRepo) DeleteOrganization(context.Context, string) error {
+	return errTest
+}
+
+func initService(t *testing.T) Service {
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Service{db: initBasicRepo(getTestRepo()), dataService: ds}
+}
@@ -1,10 +0,0 @@
-package password
-
-type Password struct {
-	UID      string `json:"-"`
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Note     string `json:"note"`
-}
@@ -0,0 +1,55 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type BasicRepo struct {
+	tokens *sync.Map
+}
+
+func NewBasicRepo() *BasicRepo {
+	return &BasicRepo{tokens: &sync.Map{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[time.Time](w, "revocations", r.tokens); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteExpired(ctx context.Context, now time.Time) error {
+	var err error
+	r.tokens.Range(func(k, v any) bool {
+		if !v.(time.Time).After(now) {
+			_, err = storage.Delete(ctx, r.tokens, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (r *BasicRepo) IsRevoked(_ context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, ErrMissingArgs
+	}
+	_, ok := r.tokens.Load(jti)
+	return ok, nil
+}
+
+func (r *BasicRepo) Revoke(ctx context.Context, jti string, exp time.Time) error {
+	if jti == "" {
+		return ErrMissingArgs
+	}
+	return storage.Store(ctx, r.tokens, jti, exp)
+}
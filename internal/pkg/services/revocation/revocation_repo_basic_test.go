@@ -0,0 +1,114 @@
+package revocation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestBasicRepo_DeleteExpired(t *testing.T) {
+	for _, tt := range getDeleteExpiredCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			assert.NoError(t, r.DeleteExpired(context.Background(), tt.now))
+
+			var got []string
+			r.tokens.Range(func(k, _ any) bool {
+				got = append(got, k.(string))
+				return true
+			})
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasicRepo_IsRevoked(t *testing.T) {
+	for _, tt := range getIsRevokedCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			got, err := r.IsRevoked(context.Background(), tt.jti)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBasicRepo_Revoke(t *testing.T) {
+	for _, tt := range getRevokeCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			err := r.Revoke(context.Background(), tt.args.jti, tt.args.exp)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				_, ok := r.tokens.Load(tt.args.jti)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestNewBasicRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantField     string
+		wantFieldType string
+		wantType      string
+	}{
+		{
+			name:          "Basic repo is created",
+			wantField:     "tokens",
+			wantFieldType: "*sync.Map",
+			wantType:      "*revocation.BasicRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBasicRepo()
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.wantType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.wantField, rField.Name)
+			assert.Equal(t, tt.wantFieldType, rField.Type.String())
+		})
+	}
+}
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	exp := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r := initPersistentRepo(t, dir)
+	assert.NoError(t, r.Revoke(ctx, "testJTI", exp.Add(time.Hour)))
+	assert.NoError(t, r.Revoke(ctx, "testJTI1", exp))
+	assert.NoError(t, r.DeleteExpired(ctx, exp))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.IsRevoked(ctx, "testJTI")
+	assert.NoError(t, err)
+	assert.True(t, got)
+	got, err = r.IsRevoked(ctx, "testJTI1")
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "revocations", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,61 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type DBRepo struct {
+	db *sql.DB
+}
+
+const (
+	DeleteExpired = "DELETE FROM revoked_tokens WHERE expires_at <= $1"
+	IsRevoked     = "SELECT jti FROM revoked_tokens WHERE jti = $1"
+	Revoke        = "INSERT INTO revoked_tokens(jti, expires_at) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+)
+
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
+	}
+	return &DBRepo{db: db}, nil
+}
+
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *DBRepo) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteExpired, now)
+	return err
+}
+
+func (r *DBRepo) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, IsRevoked, jti).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *DBRepo) Revoke(ctx context.Context, jti string, exp time.Time) error {
+	if jti == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, Revoke, jti, exp)
+	return err
+}
@@ -0,0 +1,136 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBRepo_DeleteExpired(t *testing.T) {
+	for _, tt := range getDeleteExpiredCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rows int64
+			for _, exp := range tt.repo {
+				if !exp.After(tt.now) {
+					rows++
+				}
+			}
+			mock.ExpectExec(regexp.QuoteMeta(DeleteExpired)).WithArgs(tt.now).
+				WillReturnResult(sqlmock.NewResult(1, rows))
+
+			assert.NoError(t, r.DeleteExpired(context.Background(), tt.now))
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_IsRevoked(t *testing.T) {
+	for _, tt := range getIsRevokedCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.jti != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(IsRevoked)).WithArgs(tt.jti)
+				if _, ok := tt.repo[tt.jti]; ok {
+					eq.WillReturnRows(mock.NewRows([]string{"jti"}).AddRow(tt.jti))
+				} else {
+					eq.WillReturnError(sql.ErrNoRows)
+				}
+			}
+
+			got, err := r.IsRevoked(context.Background(), tt.jti)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_Revoke(t *testing.T) {
+	for _, tt := range getRevokeCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.args.jti != "" {
+				var rows int64
+				if _, ok := tt.repo[tt.args.jti]; !ok {
+					rows = 1
+				}
+				mock.ExpectExec(regexp.QuoteMeta(Revoke)).WithArgs(tt.args.jti, tt.args.exp).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.Revoke(context.Background(), tt.args.jti, tt.args.exp)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestNewDBRepo(t *testing.T) {
+	type want struct {
+		repoType  string
+		fieldName string
+		fieldType string
+	}
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "DB is missing",
+			wantErr: true,
+			want: want{
+				repoType:  "*revocation.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: want{
+				repoType:  "*revocation.DBRepo",
+				fieldName: "db",
+				fieldType: "*sql.DB",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDBRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want.repoType, rGot.Type().String())
+
+			rField := reflect.Indirect(rGot).Type().Field(0)
+			assert.Equal(t, tt.want.fieldName, rField.Name)
+			assert.Equal(t, tt.want.fieldType, rField.Type.String())
+		})
+	}
+}
+
+func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
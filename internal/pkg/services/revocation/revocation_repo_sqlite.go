@@ -0,0 +1,69 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteRevokedTokens = `CREATE TABLE IF NOT EXISTS revoked_tokens(
+		jti TEXT PRIMARY KEY,
+		expires_at TIMESTAMP NOT NULL)`
+	sqliteDeleteExpired = "DELETE FROM revoked_tokens WHERE expires_at <= ?1"
+	sqliteIsRevoked     = "SELECT jti FROM revoked_tokens WHERE jti = ?1"
+	sqliteRevoke        = "INSERT INTO revoked_tokens(jti, expires_at) VALUES (?1, ?2) ON CONFLICT DO NOTHING"
+)
+
+// NewSQLiteRepo returns the repository keeping the revoked tokens in the SQLite database,
+// and creates its table if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	if _, err := db.ExecContext(context.Background(), createSQLiteRevokedTokens); err != nil {
+		return &SQLiteRepo{}, err
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteExpired, now.UTC())
+	return err
+}
+
+func (r *SQLiteRepo) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, ErrMissingArgs
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteIsRevoked, jti).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *SQLiteRepo) Revoke(ctx context.Context, jti string, exp time.Time) error {
+	if jti == "" {
+		return ErrMissingArgs
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteRevoke, jti, exp.UTC())
+	return err
+}
@@ -0,0 +1,123 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteExpired(t *testing.T) {
+	for _, tt := range getDeleteExpiredCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			assert.NoError(t, r.DeleteExpired(context.Background(), tt.now))
+
+			for jti := range tt.repo {
+				got, err := r.IsRevoked(context.Background(), jti)
+				assert.NoError(t, err)
+				assert.Equal(t, contains(tt.want, jti), got)
+			}
+		})
+	}
+}
+
+func TestSQLiteRepo_IsRevoked(t *testing.T) {
+	for _, tt := range getIsRevokedCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			got, err := r.IsRevoked(context.Background(), tt.jti)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSQLiteRepo_Revoke(t *testing.T) {
+	for _, tt := range getRevokeCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initSQLiteRepo(t, tt.repo)
+			err := r.Revoke(context.Background(), tt.args.jti, tt.args.exp)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				got, rErr := r.IsRevoked(context.Background(), tt.args.jti)
+				assert.NoError(t, rErr)
+				assert.True(t, got)
+			}
+		})
+	}
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*revocation.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*revocation.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, data map[string]time.Time) *SQLiteRepo {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for jti, exp := range data {
+		if err = r.Revoke(context.Background(), jti, exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package revocation
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type deleteExpiredCase struct {
+	name string
+	repo map[string]time.Time
+	now  time.Time
+	want []string
+}
+
+type isRevokedCase struct {
+	name    string
+	repo    map[string]time.Time
+	jti     string
+	want    bool
+	wantErr error
+}
+
+type revokeArgs struct {
+	jti string
+	exp time.Time
+}
+
+type revokeCase struct {
+	name    string
+	repo    map[string]time.Time
+	args    revokeArgs
+	wantErr error
+}
+
+var testNow = time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DB is missing",
+			want: "*revocation.BasicRepo",
+		},
+		{
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*revocation.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*revocation.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRepo(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rGot := reflect.ValueOf(got)
+			assert.Equal(t, tt.want, rGot.Type().String())
+		})
+	}
+}
+
+func initBasicRepo(data map[string]time.Time) *BasicRepo {
+	tokens := &sync.Map{}
+	for jti, exp := range data {
+		tokens.Store(jti, exp)
+	}
+	return &BasicRepo{tokens: tokens}
+}
+
+func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	return &DBRepo{db: db}, mock, err
+}
+
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func getDeleteExpiredCases() []deleteExpiredCase {
+	tr := map[string]time.Time{
+		"expired": testNow.Add(-time.Hour),
+		"expires": testNow,
+		"valid":   testNow.Add(time.Hour),
+	}
+	return []deleteExpiredCase{
+		{
+			name: "Empty repo",
+			now:  testNow,
+		},
+		{
+			name: "Expired tokens are deleted",
+			repo: tr,
+			now:  testNow,
+			want: []string{"valid"},
+		},
+	}
+}
+
+func getIsRevokedCases() []isRevokedCase {
+	tr := map[string]time.Time{"revoked": testNow}
+	return []isRevokedCase{
+		{
+			name:    "Missing token ID",
+			repo:    tr,
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "Token is not revoked",
+			repo: tr,
+			jti:  "valid",
+		},
+		{
+			name: "Token is revoked",
+			repo: tr,
+			jti:  "revoked",
+			want: true,
+		},
+	}
+}
+
+func getRevokeCases() []revokeCase {
+	return []revokeCase{
+		{
+			name:    "Missing token ID",
+			args:    revokeArgs{exp: testNow},
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "Token is revoked",
+			args: revokeArgs{jti: "revoked", exp: testNow},
+		},
+		{
+			name: "Token is already revoked",
+			repo: map[string]time.Time{"revoked": testNow},
+			args: revokeArgs{jti: "revoked", exp: testNow},
+		},
+	}
+}
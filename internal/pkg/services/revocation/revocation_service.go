@@ -0,0 +1,57 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type IRepository interface {
+	DeleteExpired(ctx context.Context, now time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	Revoke(ctx context.Context, jti string, exp time.Time) error
+}
+
+type Service struct {
+	db IRepository
+}
+
+// NewService returns an instance of the Service with the associated repository.
+// Without the database handle, the revocation list is persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo}, err
+}
+
+// IsRevoked checks if the token with the specified ID has been revoked.
+func (s Service) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.db.IsRevoked(ctx, jti)
+}
+
+// Revoke adds the token with the specified ID to the revocation list until the token expires.
+// The entries of the expired tokens are evicted by the cleanup.
+func (s Service) Revoke(ctx context.Context, jti string, exp time.Time) error {
+	return s.db.Revoke(ctx, jti, exp)
+}
+
+// RunCleanup evicts the entries of the expired tokens every interval until the context is canceled.
+// The expired tokens are rejected regardless of the list, so the entries are only kept to bound its size.
+func (s Service) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			if err := s.db.DeleteExpired(ctx, t.UTC()); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
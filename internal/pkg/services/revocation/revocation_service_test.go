@@ -0,0 +1,114 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           *sql.DB
+		wantRepoType string
+		wantErr      bool
+	}{
+		{
+			name:         "DB is missing",
+			wantRepoType: "*revocation.BasicRepo",
+		},
+		{
+			name:         "DB is present",
+			db:           initDB(t),
+			wantRepoType: "*revocation.DBRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.db, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			rRepo := reflect.ValueOf(got.db)
+			assert.Equal(t, tt.wantRepoType, rRepo.Type().String())
+		})
+	}
+}
+
+func TestService_IsRevoked(t *testing.T) {
+	for _, tt := range getIsRevokedCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(tt.repo)}
+			got, err := s.IsRevoked(context.Background(), tt.jti)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name    string
+		repo    map[string]time.Time
+		jti     string
+		exp     time.Time
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "Missing token ID",
+			exp:     now.Add(time.Hour),
+			wantErr: ErrMissingArgs,
+		},
+		{
+			name: "Token is revoked",
+			repo: map[string]time.Time{"expired": now.Add(-time.Hour), "valid": now.Add(time.Hour)},
+			jti:  "revoked",
+			exp:  now.Add(time.Hour),
+			want: []string{"expired", "revoked", "valid"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initBasicRepo(tt.repo)
+			s := Service{db: r}
+			err := s.Revoke(context.Background(), tt.jti, tt.exp)
+			assert.Equal(t, tt.wantErr, err)
+			assert.ElementsMatch(t, tt.want, getRevoked(r))
+		})
+	}
+}
+
+func TestService_RunCleanup(t *testing.T) {
+	now := time.Now().UTC()
+	r := initBasicRepo(map[string]time.Time{"expired": now.Add(-time.Hour), "valid": now.Add(time.Hour)})
+	s := Service{db: r}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.RunCleanup(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		_, ok := r.tokens.Load("expired")
+		return !ok
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	assert.Equal(t, []string{"valid"}, getRevoked(r))
+}
+
+func getRevoked(r *BasicRepo) []string {
+	var jtis []string
+	r.tokens.Range(func(k, _ any) bool {
+		jtis = append(jtis, k.(string))
+		return true
+	})
+	return jtis
+}
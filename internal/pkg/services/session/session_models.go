@@ -0,0 +1,18 @@
+package session
+
+import "time"
+
+type Client struct {
+	Device    string `json:"device"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+type Session struct {
+	Client
+	CID       string    `json:"cid"`
+	UID       string    `json:"-"`
+	Token     string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
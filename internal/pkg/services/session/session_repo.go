@@ -1,19 +1,30 @@
 package session
 
 import (
+	"database/sql"
 	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 var (
-	ErrDBMissingURL  = errors.New("session db url is missing")
+	ErrDBMissing     = errors.New("session db is missing")
 	ErrNotFound      = errors.New("session not found")
-	ErrIncorrectData = errors.New("client id or token is not specified")
+	ErrIncorrectData = errors.New("client id, user id or token is not specified")
 	ErrSessionExists = errors.New("session for specified client id already exists")
 )
 
-func NewRepo(repoURL string) (IRepository, error) {
-	if repoURL == "" {
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
 		return NewBasicRepo(), nil
 	}
-	return NewDBRepo(repoURL)
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
 }
@@ -2,7 +2,11 @@ package session
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 type BasicRepo struct {
@@ -13,28 +17,84 @@ func NewBasicRepo() *BasicRepo {
 	return &BasicRepo{tokens: &sync.Map{}}
 }
 
-func (r *BasicRepo) DeleteSession(_ context.Context, cid string) error {
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[Session](w, "sessions", r.tokens); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *BasicRepo) DeleteSession(ctx context.Context, cid string) error {
 	if _, ok := r.tokens.Load(cid); !ok {
 		return ErrNotFound
 	}
-	r.tokens.Delete(cid)
-	return nil
+	_, err := storage.Delete(ctx, r.tokens, cid)
+	return err
+}
+
+func (r *BasicRepo) DeleteUserSessions(ctx context.Context, uid, except string) error {
+	if uid == "" {
+		return ErrIncorrectData
+	}
+
+	var err error
+	r.tokens.Range(func(k, v any) bool {
+		if s := v.(Session); s.UID == uid && s.CID != except {
+			_, err = storage.Delete(ctx, r.tokens, k)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (r *BasicRepo) GetSession(_ context.Context, cid string) (Session, error) {
+	if s, ok := r.tokens.Load(cid); ok {
+		return s.(Session), nil
+	}
+	return Session{}, ErrNotFound
 }
 
-func (r *BasicRepo) GetSession(_ context.Context, cid string) (string, error) {
-	if t, ok := r.tokens.Load(cid); ok {
-		return t.(string), nil
+func (r *BasicRepo) GetUserSessions(_ context.Context, uid string) ([]Session, error) {
+	if uid == "" {
+		return nil, ErrIncorrectData
 	}
-	return "", ErrNotFound
+
+	sessions := make([]Session, 0)
+	r.tokens.Range(func(_, v any) bool {
+		if s := v.(Session); s.UID == uid {
+			sessions = append(sessions, s)
+		}
+		return true
+	})
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
 }
 
-func (r *BasicRepo) StoreSession(_ context.Context, cid, token string) error {
-	if cid == "" || token == "" {
+func (r *BasicRepo) StoreSession(ctx context.Context, session Session) error {
+	if session.CID == "" || session.UID == "" || session.Token == "" {
 		return ErrIncorrectData
 	}
-	if _, ok := r.tokens.Load(cid); ok {
+	if _, ok := r.tokens.Load(session.CID); ok {
 		return ErrSessionExists
 	}
-	r.tokens.Store(cid, token)
-	return nil
+	return storage.Store(ctx, r.tokens, session.CID, session)
+}
+
+func (r *BasicRepo) UpdateLastSeen(ctx context.Context, cid string, lastSeen time.Time) error {
+	v, ok := r.tokens.Load(cid)
+	if !ok {
+		return ErrNotFound
+	}
+
+	s := v.(Session)
+	s.LastSeen = lastSeen
+	return storage.Store(ctx, r.tokens, cid, s)
 }
@@ -4,39 +4,35 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 func TestBasicRepo_DeleteSession(t *testing.T) {
-	for _, tt := range getDeleteSessionCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			err := r.DeleteSession(context.Background(), tt.cid)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testDeleteSession(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_DeleteUserSessions(t *testing.T) {
+	testDeleteUserSessions(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_GetSession(t *testing.T) {
-	for _, tt := range getGetSessionCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.GetSession(context.Background(), tt.cid)
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testGetSession(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_GetUserSessions(t *testing.T) {
+	testGetUserSessions(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_UpdateLastSeen(t *testing.T) {
+	testUpdateLastSeen(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_StoreSession(t *testing.T) {
-	for _, tt := range getStoreSessionCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			err := r.StoreSession(context.Background(), tt.args.cid, tt.args.token)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testStoreSession(t, initTestBasicRepo)
 }
 
 func TestNewBasicRepo(t *testing.T) {
@@ -65,3 +61,43 @@ func TestNewBasicRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := Session{
+		Client:    Client{Device: "laptop", IP: "127.0.0.1", UserAgent: "test-agent"},
+		CID:       "testID",
+		UID:       "testUser",
+		Token:     "testToken",
+		CreatedAt: created,
+		LastSeen:  created,
+	}
+
+	r := initPersistentRepo(t, dir)
+	assert.NoError(t, r.StoreSession(ctx, s))
+	assert.NoError(t, r.StoreSession(ctx, Session{CID: "testID1", UID: "testUser", Token: "testToken1"}))
+	assert.NoError(t, r.DeleteSession(ctx, "testID1"))
+	s.LastSeen = created.Add(time.Hour)
+	assert.NoError(t, r.UpdateLastSeen(ctx, s.CID, s.LastSeen))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetUserSessions(ctx, "testUser")
+	assert.NoError(t, err)
+	assert.Equal(t, []Session{s}, got)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "sessions", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib" // SQL driver
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 type DBRepo struct {
@@ -13,32 +16,35 @@ type DBRepo struct {
 }
 
 const (
-	CreateSessionTable = `CREATE TABLE IF NOT EXISTS sessions(
-    	cid VARCHAR(50),
-	   	token VARCHAR(165),
-	   	PRIMARY KEY (cid)
-	)`
-	DeleteSession = `DELETE FROM sessions WHERE cid = $1`
-	GetSession    = "SELECT token FROM sessions WHERE cid = $1"
-	StoreSession  = "INSERT INTO sessions(cid, token) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING token"
+	DeleteSession      = `DELETE FROM sessions WHERE cid = $1`
+	DeleteUserSessions = "DELETE FROM sessions WHERE uid = $1 AND cid <> $2"
+	GetSession         = `
+		SELECT cid, uid, token, device, ip, user_agent, created_at, last_seen FROM sessions WHERE cid = $1
+	`
+	GetUserSessions = `
+		SELECT cid, uid, token, device, ip, user_agent, created_at, last_seen FROM sessions
+		WHERE uid = $1 ORDER BY created_at
+	`
+	StoreSession = `
+		INSERT INTO sessions(cid, uid, token, device, ip, user_agent, created_at, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT DO NOTHING RETURNING token
+	`
+	UpdateLastSeen = "UPDATE sessions SET last_seen = $2 WHERE cid = $1"
 )
 
-func NewDBRepo(url string) (*DBRepo, error) {
-	if url == "" {
-		return &DBRepo{}, ErrDBMissingURL
-	}
-
-	db, err := sql.Open("pgx", url)
-	if err != nil {
-		return &DBRepo{}, err
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
 	}
+	return &DBRepo{db: db}, nil
+}
 
-	_, err = db.ExecContext(context.Background(), CreateSessionTable)
-	return &DBRepo{db: db}, err
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
 }
 
 func (r *DBRepo) DeleteSession(ctx context.Context, cid string) error {
-	res, err := r.db.ExecContext(ctx, DeleteSession, cid)
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteSession, cid)
 	if err != nil {
 		return err
 	}
@@ -54,17 +60,54 @@ func (r *DBRepo) DeleteSession(ctx context.Context, cid string) error {
 	return nil
 }
 
-func (r *DBRepo) GetSession(ctx context.Context, cid string) (string, error) {
-	var token string
-	err := r.db.QueryRowContext(ctx, GetSession, cid).Scan(&token)
+func (r *DBRepo) DeleteUserSessions(ctx context.Context, uid, except string) error {
+	if uid == "" {
+		return ErrIncorrectData
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, DeleteUserSessions, uid, except)
+	return err
+}
+
+func (r *DBRepo) GetSession(ctx context.Context, cid string) (Session, error) {
+	var s Session
+	err := r.conn(ctx).QueryRowContext(ctx, GetSession, cid).Scan(&s.CID, &s.UID, &s.Token,
+		&s.Device, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastSeen)
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
-		return "", ErrNotFound
+		return Session{}, ErrNotFound
+	}
+	return s, err
+}
+
+func (r *DBRepo) GetUserSessions(ctx context.Context, uid string) ([]Session, error) {
+	if uid == "" {
+		return nil, ErrIncorrectData
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, GetUserSessions, uid)
+	if err != nil {
+		return nil, err
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	defer r.closeRows(rows)
+
+	sessions := make([]Session, 0)
+	for rows.Next() {
+		var s Session
+		if err = rows.Scan(&s.CID, &s.UID, &s.Token, &s.Device, &s.IP, &s.UserAgent,
+			&s.CreatedAt, &s.LastSeen); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
 	}
-	return token, err
+	return sessions, nil
 }
 
-func (r *DBRepo) StoreSession(ctx context.Context, cid, token string) error {
-	res, err := r.db.ExecContext(ctx, StoreSession, cid, token)
+func (r *DBRepo) StoreSession(ctx context.Context, session Session) error {
+	res, err := r.conn(ctx).ExecContext(ctx, StoreSession, session.CID, session.UID, session.Token,
+		session.Device, session.IP, session.UserAgent, session.CreatedAt, session.LastSeen)
 	if err != nil {
 		return err
 	}
@@ -79,3 +122,26 @@ func (r *DBRepo) StoreSession(ctx context.Context, cid, token string) error {
 
 	return nil
 }
+
+func (r *DBRepo) UpdateLastSeen(ctx context.Context, cid string, lastSeen time.Time) error {
+	res, err := r.conn(ctx).ExecContext(ctx, UpdateLastSeen, cid, lastSeen)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *DBRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"database/sql"
 	"reflect"
 	"regexp"
 	"testing"
@@ -20,7 +21,7 @@ func TestDBRepo_DeleteSession(t *testing.T) {
 
 			ee := mock.ExpectExec(regexp.QuoteMeta(DeleteSession)).WithArgs(tt.cid)
 			var rows int64
-			if tt.repo[tt.cid] != "" {
+			if tt.repo[tt.cid].Token != "" {
 				rows = 1
 			}
 			ee.WillReturnResult(sqlmock.NewResult(1, rows))
@@ -32,6 +33,32 @@ func TestDBRepo_DeleteSession(t *testing.T) {
 	}
 }
 
+func TestDBRepo_DeleteUserSessions(t *testing.T) {
+	for _, tt := range getDeleteUserSessionsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.args.uid != "" {
+				var rows int64
+				for cid, v := range tt.repo {
+					if v.UID == tt.args.uid && cid != tt.args.except {
+						rows++
+					}
+				}
+				mock.ExpectExec(regexp.QuoteMeta(DeleteUserSessions)).WithArgs(tt.args.uid, tt.args.except).
+					WillReturnResult(sqlmock.NewResult(1, rows))
+			}
+
+			err = r.DeleteUserSessions(context.Background(), tt.args.uid, tt.args.except)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestDBRepo_GetSession(t *testing.T) {
 	for _, tt := range getGetSessionCases() {
 		t.Run(tt.name, func(t *testing.T) {
@@ -41,9 +68,8 @@ func TestDBRepo_GetSession(t *testing.T) {
 			}
 
 			eq := mock.ExpectQuery(regexp.QuoteMeta(GetSession)).WithArgs(tt.cid)
-			if tt.repo[tt.cid] != "" {
-				rows := mock.NewRows([]string{"token"}).AddRow(tt.want)
-				eq.WillReturnRows(rows)
+			if tt.repo[tt.cid].Token != "" {
+				eq.WillReturnRows(getSessionRows(mock, tt.want))
 			} else {
 				eq.WillReturnError(ErrNotFound)
 			}
@@ -56,6 +82,49 @@ func TestDBRepo_GetSession(t *testing.T) {
 	}
 }
 
+func TestDBRepo_GetUserSessions(t *testing.T) {
+	for _, tt := range getGetUserSessionsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				mock.ExpectQuery(regexp.QuoteMeta(GetUserSessions)).WithArgs(tt.uid).
+					WillReturnRows(getSessionRows(mock, tt.want...))
+			}
+
+			got, err := r.GetUserSessions(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_UpdateLastSeen(t *testing.T) {
+	for _, tt := range getUpdateLastSeenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rows int64
+			if tt.repo[tt.cid].Token != "" {
+				rows = 1
+			}
+			mock.ExpectExec(regexp.QuoteMeta(UpdateLastSeen)).WithArgs(tt.cid, tt.lastSeen).
+				WillReturnResult(sqlmock.NewResult(1, rows))
+
+			err = r.UpdateLastSeen(context.Background(), tt.cid, tt.lastSeen)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestDBRepo_StoreSession(t *testing.T) {
 	for _, tt := range getStoreSessionCases() {
 		t.Run(tt.name, func(t *testing.T) {
@@ -64,8 +133,8 @@ func TestDBRepo_StoreSession(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			_ = getStoreSessionExec(mock, tt.repo, tt.args.cid, tt.args.token)
-			err = r.StoreSession(context.Background(), tt.args.cid, tt.args.token)
+			_ = getStoreSessionExec(mock, tt.repo, tt.session)
+			err = r.StoreSession(context.Background(), tt.session)
 			assert.Equal(t, tt.wantErr, err)
 			checkMetExpectations(t, mock)
 		})
@@ -80,12 +149,12 @@ func TestNewDBRepo(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		url     string
+		db      *sql.DB
 		want    want
 		wantErr bool
 	}{
 		{
-			name:    "Empty repo URL",
+			name:    "DB is missing",
 			wantErr: true,
 			want: want{
 				repoType:  "*session.DBRepo",
@@ -94,19 +163,18 @@ func TestNewDBRepo(t *testing.T) {
 			},
 		},
 		{
-			name: "Wrong Repo URL is present",
-			url:  "postgres://localhost:5432/test",
+			name: "DB is present",
+			db:   initDB(t),
 			want: want{
 				repoType:  "*session.DBRepo",
 				fieldName: "db",
 				fieldType: "*sql.DB",
 			},
-			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewDBRepo(tt.url)
+			got, err := NewDBRepo(tt.db)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rGot := reflect.ValueOf(got)
@@ -119,17 +187,26 @@ func TestNewDBRepo(t *testing.T) {
 	}
 }
 
-func getStoreSessionExec(mock sqlmock.Sqlmock, repo map[string]string, cid, token string) *sqlmock.ExpectedExec {
-	eq := mock.ExpectExec(regexp.QuoteMeta(StoreSession)).WithArgs(cid, token)
-	if cid == "" || token == "" {
+func getStoreSessionExec(mock sqlmock.Sqlmock, repo map[string]Session, session Session) *sqlmock.ExpectedExec {
+	eq := mock.ExpectExec(regexp.QuoteMeta(StoreSession)).WithArgs(session.CID, session.UID, session.Token,
+		session.Device, session.IP, session.UserAgent, session.CreatedAt, session.LastSeen)
+	if session.CID == "" || session.UID == "" || session.Token == "" {
 		return eq.WillReturnError(ErrIncorrectData)
 	}
-	if repo[cid] != "" {
+	if repo[session.CID].Token != "" {
 		return eq.WillReturnError(ErrSessionExists)
 	}
 	return eq.WillReturnResult(sqlmock.NewResult(1, 1))
 }
 
+func getSessionRows(mock sqlmock.Sqlmock, sessions ...Session) *sqlmock.Rows {
+	rows := mock.NewRows([]string{"cid", "uid", "token", "device", "ip", "user_agent", "created_at", "last_seen"})
+	for _, s := range sessions {
+		rows.AddRow(s.CID, s.UID, s.Token, s.Device, s.IP, s.UserAgent, s.CreatedAt, s.LastSeen)
+	}
+	return rows
+}
+
 func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
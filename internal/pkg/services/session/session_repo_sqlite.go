@@ -0,0 +1,145 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteSessions = `CREATE TABLE IF NOT EXISTS sessions(
+		cid TEXT PRIMARY KEY,
+		uid TEXT NOT NULL,
+		token TEXT NOT NULL,
+		device TEXT NOT NULL DEFAULT '',
+		ip TEXT NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL,
+		last_seen TIMESTAMP NOT NULL)`
+	sqliteDeleteSession      = "DELETE FROM sessions WHERE cid = ?1"
+	sqliteDeleteUserSessions = "DELETE FROM sessions WHERE uid = ?1 AND cid <> ?2"
+	sqliteGetSession         = `
+		SELECT cid, uid, token, device, ip, user_agent, created_at, last_seen FROM sessions WHERE cid = ?1
+	`
+	sqliteGetUserSessions = `
+		SELECT cid, uid, token, device, ip, user_agent, created_at, last_seen FROM sessions
+		WHERE uid = ?1 ORDER BY created_at
+	`
+	sqliteStoreSession = `
+		INSERT INTO sessions(cid, uid, token, device, ip, user_agent, created_at, last_seen)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8) ON CONFLICT DO NOTHING
+	`
+	sqliteUpdateLastSeen = "UPDATE sessions SET last_seen = ?2 WHERE cid = ?1"
+)
+
+// NewSQLiteRepo returns the repository keeping the sessions in the SQLite database, and creates its table if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+	if _, err := db.ExecContext(context.Background(), createSQLiteSessions); err != nil {
+		return &SQLiteRepo{}, err
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) DeleteSession(ctx context.Context, cid string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteSession, cid)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrNotFound)
+}
+
+func (r *SQLiteRepo) DeleteUserSessions(ctx context.Context, uid, except string) error {
+	if uid == "" {
+		return ErrIncorrectData
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteUserSessions, uid, except)
+	return err
+}
+
+func (r *SQLiteRepo) GetSession(ctx context.Context, cid string) (Session, error) {
+	var s Session
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetSession, cid).Scan(&s.CID, &s.UID, &s.Token,
+		&s.Device, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastSeen)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrNotFound
+	}
+	return s, err
+}
+
+func (r *SQLiteRepo) GetUserSessions(ctx context.Context, uid string) ([]Session, error) {
+	if uid == "" {
+		return nil, ErrIncorrectData
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteGetUserSessions, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+
+	sessions := make([]Session, 0)
+	for rows.Next() {
+		var s Session
+		if err = rows.Scan(&s.CID, &s.UID, &s.Token, &s.Device, &s.IP, &s.UserAgent,
+			&s.CreatedAt, &s.LastSeen); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *SQLiteRepo) StoreSession(ctx context.Context, session Session) error {
+	if session.CID == "" || session.UID == "" || session.Token == "" {
+		return ErrIncorrectData
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteStoreSession, session.CID, session.UID, session.Token,
+		session.Device, session.IP, session.UserAgent, session.CreatedAt.UTC(), session.LastSeen.UTC())
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrSessionExists)
+}
+
+func (r *SQLiteRepo) UpdateLastSeen(ctx context.Context, cid string, lastSeen time.Time) error {
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteUpdateLastSeen, cid, lastSeen.UTC())
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(res, ErrNotFound)
+}
+
+func (r *SQLiteRepo) checkAffected(res sql.Result, errNone error) error {
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return errNone
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
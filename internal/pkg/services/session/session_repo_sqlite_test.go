@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_DeleteSession(t *testing.T) {
+	testDeleteSession(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_DeleteUserSessions(t *testing.T) {
+	testDeleteUserSessions(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetSession(t *testing.T) {
+	testGetSession(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetUserSessions(t *testing.T) {
+	testGetUserSessions(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_UpdateLastSeen(t *testing.T) {
+	testUpdateLastSeen(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_StoreSession(t *testing.T) {
+	testStoreSession(t, initSQLiteRepo)
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*session.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*session.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+func initSQLiteRepo(t *testing.T, data map[string]Session) IRepository {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for cid, s := range data {
+		s.CID = cid
+		if err = r.StoreSession(context.Background(), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
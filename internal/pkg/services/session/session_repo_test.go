@@ -1,9 +1,12 @@
 package session
 
 import (
+	"context"
+	"database/sql"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -11,52 +14,83 @@ import (
 
 type deleteSessionCase struct {
 	name    string
-	repo    map[string]string
+	repo    map[string]Session
 	cid     string
 	wantErr error
 }
 
 type getSessionCase struct {
 	name    string
-	repo    map[string]string
+	repo    map[string]Session
 	cid     string
-	want    string
+	want    Session
 	wantErr error
 }
 
-type storeSessionArgs struct {
-	cid   string
-	token string
+type getUserSessionsCase struct {
+	name    string
+	repo    map[string]Session
+	uid     string
+	want    []Session
+	wantErr error
+}
+
+type updateLastSeenCase struct {
+	name     string
+	repo     map[string]Session
+	cid      string
+	lastSeen time.Time
+	wantErr  error
+}
+
+type deleteUserSessionsArgs struct {
+	uid    string
+	except string
+}
+
+type deleteUserSessionsCase struct {
+	name    string
+	repo    map[string]Session
+	args    deleteUserSessionsArgs
+	want    []string
+	wantErr error
 }
 
 type storeSessionCase struct {
 	name    string
-	repo    map[string]string
-	args    storeSessionArgs
+	repo    map[string]Session
+	session Session
 	wantErr error
 }
 
+// repoInit creates the repository of the tested backend filled with the sessions keyed by the client ID.
+type repoInit func(t *testing.T, data map[string]Session) IRepository
+
 func TestNewRepo(t *testing.T) {
 	tests := []struct {
 		name    string
-		repoURL string
+		db      *sql.DB
 		want    string
 		wantErr bool
 	}{
 		{
-			name: "Repo URL is missing",
+			name: "DB is missing",
 			want: "*session.BasicRepo",
 		},
 		{
-			name:    "Wrong Repo URL is present",
-			repoURL: "postgres://localhost:5432/test",
-			want:    "*session.DBRepo",
-			wantErr: true,
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*session.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*session.SQLiteRepo",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewRepo(tt.repoURL)
+			got, err := NewRepo(tt.db, nil)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rGot := reflect.ValueOf(got)
@@ -65,63 +99,246 @@ func TestNewRepo(t *testing.T) {
 	}
 }
 
-func initBasicRepo(data map[string]string) *BasicRepo {
+func testDeleteSession(t *testing.T, initRepo repoInit) {
+	for _, tt := range getDeleteSessionCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.DeleteSession(context.Background(), tt.cid)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testDeleteUserSessions(t *testing.T, initRepo repoInit) {
+	for _, tt := range getDeleteUserSessionsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.DeleteUserSessions(context.Background(), tt.args.uid, tt.args.except)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				var got []string
+				for cid := range tt.repo {
+					if _, gErr := r.GetSession(context.Background(), cid); gErr == nil {
+						got = append(got, cid)
+					}
+				}
+				assert.ElementsMatch(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func testGetSession(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetSessionCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			got, err := r.GetSession(context.Background(), tt.cid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testGetUserSessions(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetUserSessionsCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			got, err := r.GetUserSessions(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testUpdateLastSeen(t *testing.T, initRepo repoInit) {
+	for _, tt := range getUpdateLastSeenCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.UpdateLastSeen(context.Background(), tt.cid, tt.lastSeen)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				got, _ := r.GetSession(context.Background(), tt.cid)
+				assert.Equal(t, tt.lastSeen, got.LastSeen)
+			}
+		})
+	}
+}
+
+func testStoreSession(t *testing.T, initRepo repoInit) {
+	for _, tt := range getStoreSessionCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.StoreSession(context.Background(), tt.session)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func initBasicRepo(data map[string]Session) *BasicRepo {
 	tokens := &sync.Map{}
-	for cid, token := range data {
-		tokens.Store(cid, token)
+	for cid, session := range data {
+		session.CID = cid
+		tokens.Store(cid, session)
 	}
 	return &BasicRepo{tokens: tokens}
 }
 
+func initTestBasicRepo(_ *testing.T, data map[string]Session) IRepository {
+	return initBasicRepo(data)
+}
+
 func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
 	db, mock, err := sqlmock.New()
 	return &DBRepo{db: db}, mock, err
 }
 
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
 func getDeleteSessionCases() []deleteSessionCase {
+	ts := Session{CID: "testID", UID: "testUser", Token: "testToken"}
 	return []deleteSessionCase{
 		{
 			name:    "No client ID passed",
-			repo:    map[string]string{"testID": "testToken"},
+			repo:    map[string]Session{"testID": ts},
 			wantErr: ErrNotFound,
 		},
 		{
 			name:    "No client ID present",
-			repo:    map[string]string{"testID": "testToken"},
+			repo:    map[string]Session{"testID": ts},
 			cid:     "testID0",
 			wantErr: ErrNotFound,
 		},
 		{
 			name: "Client ID present",
-			repo: map[string]string{"testID": "testToken"},
+			repo: map[string]Session{"testID": ts},
 			cid:  "testID",
 		},
 	}
 }
 
 func getGetSessionCases() []getSessionCase {
+	ts := Session{CID: "testID", UID: "testUser", Token: "testToken"}
 	return []getSessionCase{
 		{
 			name:    "No client ID passed",
-			repo:    map[string]string{"testID": "testToken"},
+			repo:    map[string]Session{"testID": ts},
 			wantErr: ErrNotFound,
 		},
 		{
 			name:    "No client ID present",
-			repo:    map[string]string{"testID": "testToken"},
+			repo:    map[string]Session{"testID": ts},
 			cid:     "testID0",
 			wantErr: ErrNotFound,
 		},
 		{
 			name: "Client ID present",
-			repo: map[string]string{"testID": "testToken"},
+			repo: map[string]Session{"testID": ts},
 			cid:  "testID",
-			want: "testToken",
+			want: ts,
+		},
+	}
+}
+
+func getGetUserSessionsCases() []getUserSessionsCase {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := Session{
+		Client:    Client{Device: "laptop", IP: "127.0.0.1", UserAgent: "test-agent"},
+		CID:       "testID",
+		UID:       "testUser",
+		Token:     "testToken",
+		CreatedAt: created,
+		LastSeen:  created,
+	}
+	ts1 := Session{CID: "testID1", UID: "testUser", Token: "testToken1", CreatedAt: created.Add(time.Hour)}
+	ts2 := Session{CID: "testID2", UID: "testUser1", Token: "testToken2", CreatedAt: created}
+	tr := map[string]Session{"testID": ts, "testID1": ts1, "testID2": ts2}
+
+	return []getUserSessionsCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrIncorrectData,
+		},
+		{
+			name: "No sessions for user present",
+			repo: tr,
+			uid:  "testUser0",
+			want: []Session{},
+		},
+		{
+			name: "User sessions are returned",
+			repo: tr,
+			uid:  "testUser",
+			want: []Session{ts, ts1},
+		},
+	}
+}
+
+func getUpdateLastSeenCases() []updateLastSeenCase {
+	ts := Session{CID: "testID", UID: "testUser", Token: "testToken"}
+	lastSeen := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []updateLastSeenCase{
+		{
+			name:     "No client ID present",
+			repo:     map[string]Session{"testID": ts},
+			cid:      "testID0",
+			lastSeen: lastSeen,
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:     "Client ID present",
+			repo:     map[string]Session{"testID": ts},
+			cid:      "testID",
+			lastSeen: lastSeen,
+		},
+	}
+}
+
+func getDeleteUserSessionsCases() []deleteUserSessionsCase {
+	tr := map[string]Session{
+		"testID":  {UID: "testUser", Token: "testToken"},
+		"testID1": {UID: "testUser", Token: "testToken1"},
+		"testID2": {UID: "testUser1", Token: "testToken2"},
+	}
+
+	return []deleteUserSessionsCase{
+		{
+			name:    "No user ID passed",
+			repo:    tr,
+			wantErr: ErrIncorrectData,
+		},
+		{
+			name: "No sessions for user present",
+			repo: tr,
+			args: deleteUserSessionsArgs{uid: "testUser0"},
+			want: []string{"testID", "testID1", "testID2"},
+		},
+		{
+			name: "All user sessions are deleted",
+			repo: tr,
+			args: deleteUserSessionsArgs{uid: "testUser"},
+			want: []string{"testID2"},
+		},
+		{
+			name: "Current session is kept",
+			repo: tr,
+			args: deleteUserSessionsArgs{uid: "testUser", except: "testID1"},
+			want: []string{"testID1", "testID2"},
 		},
 	}
 }
 
 func getStoreSessionCases() []storeSessionCase {
+	ts := Session{CID: "testID", UID: "testUser", Token: "testToken"}
 	return []storeSessionCase{
 		{
 			name:    "No arguments passed",
@@ -129,24 +346,24 @@ func getStoreSessionCases() []storeSessionCase {
 		},
 		{
 			name:    "No client ID passed",
-			args:    storeSessionArgs{token: "testToken"},
+			session: Session{UID: "testUser", Token: "testToken"},
 			wantErr: ErrIncorrectData,
 		},
 		{
 			name:    "No token passed",
-			args:    storeSessionArgs{cid: "testID"},
+			session: Session{CID: "testID", UID: "testUser"},
 			wantErr: ErrIncorrectData,
 		},
 		{
 			name:    "Client ID exists",
-			repo:    map[string]string{"testID": "testToken"},
-			args:    storeSessionArgs{cid: "testID", token: "testToken"},
+			repo:    map[string]Session{"testID": ts},
+			session: ts,
 			wantErr: ErrSessionExists,
 		},
 		{
-			name: "All arguments are correct",
-			repo: map[string]string{"testID": "testToken"},
-			args: storeSessionArgs{cid: "testID0", token: "testToken0"},
+			name:    "All arguments are correct",
+			repo:    map[string]Session{"testID": ts},
+			session: Session{CID: "testID0", UID: "testUser", Token: "testToken0"},
 		},
 	}
 }
@@ -2,11 +2,15 @@ package session
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/segmentio/ksuid"
 
 	"github.com/agodlevskii/goph-keeper/internal/pkg/jwt"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/revocation"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 var (
@@ -17,29 +21,42 @@ var (
 
 type IRepository interface {
 	DeleteSession(ctx context.Context, cid string) error
-	GetSession(ctx context.Context, cid string) (string, error)
-	StoreSession(ctx context.Context, cid, token string) error
+	DeleteUserSessions(ctx context.Context, uid, except string) error
+	GetSession(ctx context.Context, cid string) (Session, error)
+	GetUserSessions(ctx context.Context, uid string) ([]Session, error)
+	StoreSession(ctx context.Context, session Session) error
+	UpdateLastSeen(ctx context.Context, cid string, lastSeen time.Time) error
 }
 
+// lastSeenInterval limits how often the session's last-seen time is written to the repository.
+const lastSeenInterval = time.Minute
+
 type Service struct {
-	db IRepository
+	db                IRepository
+	revocationService revocation.Service
 }
 
-// NewService returns an instance of the Service with the associated repository.
-func NewService(repoURL string) (Service, error) {
-	db, err := NewRepo(repoURL)
-	return Service{db: db}, err
+// NewService returns an instance of the Service with the associated repository and token revocation microservice.
+// Without the database handle, the sessions and the revocation list are persisted with the logs if they are passed.
+func NewService(db *sql.DB, w, rw *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	if err != nil {
+		return Service{db: repo}, err
+	}
+
+	rs, err := revocation.NewService(db, rw)
+	return Service{db: repo, revocationService: rs}, err
 }
 
 // RestoreSession gathers the stored client-associated token.
 // If the token is expired, the method deletes it from the repository and returns an error.
 func (s Service) RestoreSession(ctx context.Context, cid string) (string, error) {
-	t, err := s.db.GetSession(ctx, cid)
+	ss, err := s.db.GetSession(ctx, cid)
 	if err != nil {
 		return "", err
 	}
 
-	if exp, eErr := jwt.IsTokenExpired(t); eErr != nil || exp {
+	if exp, eErr := jwt.IsTokenExpired(ss.Token); eErr != nil || exp {
 		_ = s.DeleteSession(ctx, cid)
 		if eErr != nil {
 			return "", eErr
@@ -47,20 +64,100 @@ func (s Service) RestoreSession(ctx context.Context, cid string) (string, error)
 		return "", ErrTokenExpired
 	}
 
-	return t, nil
+	return ss.Token, s.TouchSession(ctx, ss)
 }
 
-// StoreSession generates new client ID and associates the passed token with it.
-func (s Service) StoreSession(ctx context.Context, token string) (string, error) {
+// StoreSession generates new client ID and associates the passed user's token and client details with it.
+func (s Service) StoreSession(ctx context.Context, uid, token string, client Client) (string, error) {
 	cid := generateClientID()
-	return cid, s.db.StoreSession(ctx, cid, token)
+	now := time.Now().UTC()
+	return cid, s.db.StoreSession(ctx, Session{
+		Client:    client,
+		CID:       cid,
+		UID:       uid,
+		Token:     token,
+		CreatedAt: now,
+		LastSeen:  now,
+	})
+}
+
+// GetSession returns the client-associated session.
+func (s Service) GetSession(ctx context.Context, cid string) (Session, error) {
+	return s.db.GetSession(ctx, cid)
+}
+
+// GetUserSessions returns all the user's sessions ordered by the creation time.
+func (s Service) GetUserSessions(ctx context.Context, uid string) ([]Session, error) {
+	return s.db.GetUserSessions(ctx, uid)
+}
+
+// TouchSession updates the session's last-seen time.
+// The repository is not updated if the session has been seen less than a minute ago.
+func (s Service) TouchSession(ctx context.Context, session Session) error {
+	now := time.Now().UTC()
+	if now.Sub(session.LastSeen) < lastSeenInterval {
+		return nil
+	}
+	return s.db.UpdateLastSeen(ctx, session.CID, now)
 }
 
-// DeleteSession deletes the client-associated session.
+// DeleteSession deletes the client-associated session and revokes its token.
 func (s Service) DeleteSession(ctx context.Context, cid string) error {
+	ss, err := s.db.GetSession(ctx, cid)
+	if err != nil {
+		return err
+	}
+	if err = s.revokeToken(ctx, ss.Token); err != nil {
+		return err
+	}
 	return s.db.DeleteSession(ctx, cid)
 }
 
+// DeleteUserSession deletes the client-associated session if it belongs to the specified user.
+// The session token gets revoked.
+func (s Service) DeleteUserSession(ctx context.Context, uid, cid string) error {
+	ss, err := s.db.GetSession(ctx, cid)
+	if err != nil {
+		return err
+	}
+	if ss.UID != uid {
+		return ErrNotFound
+	}
+	if err = s.revokeToken(ctx, ss.Token); err != nil {
+		return err
+	}
+	return s.db.DeleteSession(ctx, cid)
+}
+
+// DeleteUserSessions deletes all the user's sessions, except the one associated with the passed client ID.
+// If the client ID is empty, all the user's sessions get deleted.
+// The tokens of the deleted sessions get revoked.
+func (s Service) DeleteUserSessions(ctx context.Context, uid, except string) error {
+	sessions, err := s.db.GetUserSessions(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	for _, ss := range sessions {
+		if ss.CID == except {
+			continue
+		}
+		if err = s.revokeToken(ctx, ss.Token); err != nil {
+			return err
+		}
+	}
+	return s.db.DeleteUserSessions(ctx, uid, except)
+}
+
+// IsTokenRevoked checks if the token has been revoked.
+func (s Service) IsTokenRevoked(ctx context.Context, token string) (bool, error) {
+	claims, err := jwt.GetTokenClaims(token)
+	if err != nil {
+		return true, err
+	}
+	return s.revocationService.IsRevoked(ctx, claims.ID)
+}
+
 // GenerateToken generates a new JWT token with the specified expiry time.
 func (s Service) GenerateToken(uid string) (string, error) {
 	if uid == "" {
@@ -88,6 +185,22 @@ func (s Service) IsTokenExpired(token string) (bool, error) {
 	return false, nil
 }
 
+// RunRevocationCleanup evicts the expired tokens from the revocation list every interval
+// until the context is canceled.
+func (s Service) RunRevocationCleanup(ctx context.Context, interval time.Duration) {
+	s.revocationService.RunCleanup(ctx, interval)
+}
+
+// revokeToken adds the token to the revocation list until it expires.
+// The tokens that can't be parsed, including the expired ones, are already unusable and don't get revoked.
+func (s Service) revokeToken(ctx context.Context, token string) error {
+	claims, err := jwt.GetTokenClaims(token)
+	if err != nil {
+		return nil
+	}
+	return s.revocationService.Revoke(ctx, claims.ID, claims.ExpiresAt)
+}
+
 func generateClientID() string {
 	return ksuid.New().String()
 }
@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"database/sql"
 	"reflect"
 	"testing"
 	"time"
@@ -9,29 +10,29 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/agodlevskii/goph-keeper/internal/pkg/jwt"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/revocation"
 )
 
 func TestNewService(t *testing.T) {
 	tests := []struct {
 		name         string
-		repoURL      string
+		db           *sql.DB
 		wantRepoType string
 		wantErr      bool
 	}{
 		{
-			name:         "Repo URL is missing",
+			name:         "DB is missing",
 			wantRepoType: "*session.BasicRepo",
 		},
 		{
-			name:         "Wrong Repo URL is present",
-			repoURL:      "postgres://localhost:5432/test",
+			name:         "DB is present",
+			db:           initDB(t),
 			wantRepoType: "*session.DBRepo",
-			wantErr:      true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewService(tt.repoURL)
+			got, err := NewService(tt.db, nil, nil)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rRepo := reflect.ValueOf(got.db)
@@ -53,13 +54,88 @@ func TestService_DeleteSession(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Service{db: initBasicRepo(nil)}
+			s := initService(t, nil)
 			err := s.DeleteSession(context.Background(), tt.cid)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
+func TestService_DeleteUserSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    map[string]Session
+		uid     string
+		cid     string
+		wantErr error
+	}{
+		{
+			name:    "Missing client ID",
+			repo:    map[string]Session{"testID": {UID: "test-user", Token: "token"}},
+			uid:     "test-user",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Session of another user",
+			repo:    map[string]Session{"testID": {UID: "test-user", Token: "token"}},
+			uid:     "test-user1",
+			cid:     "testID",
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Session of the user",
+			repo: map[string]Session{"testID": {UID: "test-user", Token: "token"}},
+			uid:  "test-user",
+			cid:  "testID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t, tt.repo)
+			err := s.DeleteUserSession(context.Background(), tt.uid, tt.cid)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_DeleteUserSessions(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    map[string]Session
+		uid     string
+		except  string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "Missing user ID",
+			wantErr: ErrIncorrectData,
+		},
+		{
+			name: "Other sessions are deleted",
+			repo: map[string]Session{
+				"testID":  {UID: "test-user", Token: "token"},
+				"testID1": {UID: "test-user", Token: "token1"},
+			},
+			uid:    "test-user",
+			except: "testID",
+			want:   []string{"testID"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t, tt.repo)
+			err := s.DeleteUserSessions(context.Background(), tt.uid, tt.except)
+			assert.Equal(t, tt.wantErr, err)
+
+			for _, cid := range tt.want {
+				_, gErr := s.db.GetSession(context.Background(), cid)
+				assert.NoError(t, gErr)
+			}
+		})
+	}
+}
+
 func TestService_GenerateToken(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -74,12 +150,12 @@ func TestService_GenerateToken(t *testing.T) {
 		{
 			name:    "Correct ID",
 			uid:     "test-user",
-			wantLen: 129,
+			wantLen: 189,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Service{db: initBasicRepo(nil)}
+			s := initService(t, nil)
 			got, err := s.GenerateToken(tt.uid)
 			assert.Equal(t, tt.wantLen, len(got))
 			assert.Equal(t, tt.wantErr, err)
@@ -111,7 +187,7 @@ func TestService_GetUIDFromToken(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Service{db: initBasicRepo(nil)}
+			s := initService(t, nil)
 			got, err := s.GetUIDFromToken(tt.token)
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.want, got)
@@ -127,7 +203,7 @@ func TestService_RestoreSession(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		repo    map[string]string
+		repo    map[string]Session
 		cid     string
 		want    string
 		wantErr error
@@ -138,25 +214,25 @@ func TestService_RestoreSession(t *testing.T) {
 		},
 		{
 			name:    "Existing client ID, missing parameter",
-			repo:    map[string]string{"testID": token},
+			repo:    map[string]Session{"testID": {UID: "test-user", Token: token}},
 			wantErr: ErrNotFound,
 		},
 		{
 			name:    "Existing client ID, wrong parameter",
-			repo:    map[string]string{"testID": token},
+			repo:    map[string]Session{"testID": {UID: "test-user", Token: token}},
 			cid:     "testID1",
 			wantErr: ErrNotFound,
 		},
 		{
 			name: "Existing client ID, correct parameter",
-			repo: map[string]string{"testID": token},
+			repo: map[string]Session{"testID": {UID: "test-user", Token: token}},
 			cid:  "testID",
 			want: token,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Service{db: initBasicRepo(tt.repo)}
+			s := initService(t, tt.repo)
 			got, err := s.RestoreSession(context.Background(), tt.cid)
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
@@ -167,32 +243,73 @@ func TestService_RestoreSession(t *testing.T) {
 func TestService_StoreSession(t *testing.T) {
 	tests := []struct {
 		name    string
+		uid     string
 		token   string
 		wantLen int
 		wantErr error
 	}{
 		{
 			name:    "Token is missing",
+			uid:     "test-user",
+			wantErr: ErrIncorrectData,
+			wantLen: 27,
+		},
+		{
+			name:    "User ID is missing",
+			token:   "Test token",
 			wantErr: ErrIncorrectData,
 			wantLen: 27,
 		},
 		{
 			name:    "Token is present",
+			uid:     "test-user",
 			token:   "Test token",
 			wantLen: 27,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Service{db: initBasicRepo(nil)}
+			s := initService(t, nil)
 
-			got, err := s.StoreSession(context.Background(), tt.token)
+			got, err := s.StoreSession(context.Background(), tt.uid, tt.token, Client{Device: "laptop"})
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantLen, len(got))
 		})
 	}
 }
 
+func TestService_TouchSession(t *testing.T) {
+	recent := time.Now().UTC()
+	outdated := recent.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		session Session
+		updated bool
+		wantErr error
+	}{
+		{
+			name:    "Recently seen session",
+			session: Session{CID: "testID", UID: "test-user", Token: "token", LastSeen: recent},
+		},
+		{
+			name:    "Outdated session",
+			session: Session{CID: "testID", UID: "test-user", Token: "token", LastSeen: outdated},
+			updated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t, map[string]Session{tt.session.CID: tt.session})
+			err := s.TouchSession(context.Background(), tt.session)
+			assert.Equal(t, tt.wantErr, err)
+
+			got, _ := s.GetSession(context.Background(), tt.session.CID)
+			assert.Equal(t, tt.updated, got.LastSeen.After(tt.session.LastSeen))
+		})
+	}
+}
+
 func Test_generateClientID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -211,6 +328,48 @@ func Test_generateClientID(t *testing.T) {
 	}
 }
 
+func TestService_IsTokenRevoked(t *testing.T) {
+	token, err := jwt.EncodeToken("test-user", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revToken, err := jwt.EncodeToken("test-user", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{
+			name:  "Active token",
+			token: token,
+		},
+		{
+			name:  "Revoked token",
+			token: revToken,
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := initService(t, map[string]Session{
+				"testID":  {UID: "test-user", Token: token},
+				"testID1": {UID: "test-user", Token: revToken},
+			})
+			if err = s.DeleteSession(context.Background(), "testID1"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, rErr := s.IsTokenRevoked(context.Background(), tt.token)
+			assert.NoError(t, rErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestService_IsTokenExpired(t *testing.T) {
 	token, err := jwt.EncodeToken("test-expired1", 0)
 	if err != nil {
@@ -240,10 +399,18 @@ func TestService_IsTokenExpired(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Service{db: initBasicRepo(nil)}
+			s := initService(t, nil)
 			got, err := s.IsTokenExpired(tt.token)
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func initService(t *testing.T, repo map[string]Session) Service {
+	rs, err := revocation.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Service{db: initBasicRepo(repo), revocationService: rs}
+}
@@ -0,0 +1,16 @@
+package template
+
+// Template is the user-defined type of the custom items.
+type Template struct {
+	UID    string  `json:"-"`
+	ID     string  `json:"-"`
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is the field of the Template, filled in as the custom field of the item.
+type Field struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Required bool   `json:"required"`
+}
@@ -0,0 +1,108 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+type Service struct {
+	dataService data.Service
+}
+
+var (
+	ErrExists   = errors.New("the template with the same name already exists")
+	ErrNotFound = errors.New("requested template not found")
+)
+
+// NewService returns an instance of the Service with pre-defined data microservice.
+// The templates are stored encrypted as the user's items, so they are backed up and exported along with them.
+func NewService(dataService data.Service) Service {
+	return Service{dataService: dataService}
+}
+
+// DeleteTemplate removes the user's template with the unique ID.
+// The items created with the template keep their fields.
+func (s Service) DeleteTemplate(ctx context.Context, uid, id string) error {
+	if uid == "" || id == "" {
+		return ErrNotFound
+	}
+
+	d, err := s.dataService.GetDataByID(ctx, uid, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if d.Type != data.STemplate || d.Shared {
+		return ErrNotFound
+	}
+
+	err = s.dataService.DeleteSecureData(ctx, uid, id)
+	if errors.Is(err, data.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// GetTemplateByName returns the user's template with the specified name.
+func (s Service) GetTemplateByName(ctx context.Context, uid, name string) (Template, error) {
+	templates, err := s.GetTemplates(ctx, uid)
+	if err != nil {
+		return Template{}, err
+	}
+
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Template{}, ErrNotFound
+}
+
+// GetTemplates returns all the user's templates.
+func (s Service) GetTemplates(ctx context.Context, uid string) ([]Template, error) {
+	if uid == "" {
+		return nil, ErrNotFound
+	}
+
+	sd, err := s.dataService.GetAllDataByType(ctx, uid, data.STemplate)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	templates := make([]Template, 0, len(sd))
+	for _, d := range sd {
+		if d.Shared {
+			continue
+		}
+
+		b, dErr := s.dataService.DecryptSecureData(uid, d)
+		if dErr != nil {
+			return nil, dErr
+		}
+
+		t := Template{UID: d.UID, ID: d.ID}
+		if dErr = json.Unmarshal(b, &t); dErr != nil {
+			return nil, dErr
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// StoreTemplate stores the template, unless the user already has the template with the same name.
+func (s Service) StoreTemplate(ctx context.Context, t Template) (string, error) {
+	if _, err := s.GetTemplateByName(ctx, t.UID, t.Name); err == nil {
+		return "", ErrExists
+	} else if !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+	return s.dataService.StoreSecureDataFromPayload(ctx, t.UID, t, data.STemplate)
+}
@@ -0,0 +1,108 @@
+package template
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+func TestService_DeleteTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		id      string
+		wantErr error
+	}{
+		{
+			name:    "Arguments are empty",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Template of another user",
+			uid:     "test1",
+			id:      "test",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Data is not the template",
+			uid:     "test",
+			id:      "text",
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Template is deleted",
+			uid:  "test",
+			id:   "test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ids := initService(t)
+			if id, ok := ids[tt.id]; ok {
+				tt.id = id
+			}
+
+			assert.Equal(t, tt.wantErr, s.DeleteTemplate(context.Background(), tt.uid, tt.id))
+		})
+	}
+}
+
+func TestService_GetTemplateByName(t *testing.T) {
+	s, ids := initService(t)
+
+	got, err := s.GetTemplateByName(context.Background(), "test", "db")
+	assert.NoError(t, err)
+	assert.Equal(t, Template{
+		UID:    "test",
+		ID:     ids["test"],
+		Name:   "db",
+		Fields: []Field{{Name: "host", Kind: "url", Required: true}},
+	}, got)
+
+	_, err = s.GetTemplateByName(context.Background(), "test1", "db")
+	assert.Equal(t, ErrNotFound, err)
+	_, err = s.GetTemplateByName(context.Background(), "", "db")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestService_StoreTemplate(t *testing.T) {
+	s, _ := initService(t)
+
+	_, err := s.StoreTemplate(context.Background(), Template{UID: "test", Name: "db"})
+	assert.Equal(t, ErrExists, err)
+
+	id, err := s.StoreTemplate(context.Background(), Template{UID: "test1", Name: "db"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	got, err := s.GetTemplates(context.Background(), "test1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Template{{UID: "test1", ID: id, Name: "db"}}, got)
+}
+
+// initService stores the user's template, along with the text item, and returns their IDs keyed by "test" and "text".
+func initService(t *testing.T) (Service, map[string]string) {
+	t.Helper()
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(ds)
+
+	tid, err := s.StoreTemplate(context.Background(), Template{
+		UID:    "test",
+		Name:   "db",
+		Fields: []Field{{Name: "host", Kind: "url", Required: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	iid, err := ds.StoreSecureDataFromPayload(context.Background(), "test", map[string]string{"name": "db"}, data.SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, map[string]string{"test": tid, "text": iid}
+}
@@ -5,3 +5,17 @@ type User struct {
 	Name     string `json:"name"`
 	Password string `json:"password"`
 }
+
+// KeyPair is the user's X25519 key pair used to seal the item keys shared with the user.
+// The private key is encrypted by the client with the user's master password, so the server can't read it.
+type KeyPair struct {
+	UID        string `json:"-"`
+	PublicKey  []byte `json:"public_key"`
+	PrivateKey []byte `json:"private_key"`
+}
+
+// Dump is the complete content of the repository, moved between the repositories with the user IDs preserved.
+type Dump struct {
+	Users    []User
+	KeyPairs []KeyPair
+}
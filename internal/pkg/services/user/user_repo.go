@@ -1,18 +1,30 @@
 package user
 
 import (
+	"database/sql"
 	"errors"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 var (
-	ErrDBMissingURL = errors.New("users db url is missing")
-	ErrExists       = errors.New("the user with specified name already exists")
-	ErrNotFound     = errors.New("user not found")
+	ErrDBMissing = errors.New("users db is missing")
+	ErrExists    = errors.New("the user with specified name already exists")
+	ErrNotFound  = errors.New("user not found")
+	ErrNoKeyPair = errors.New("the user has no key pair")
 )
 
-func NewRepo(repoURL string) (IRepository, error) {
-	if repoURL == "" {
+// NewRepo returns the repository built upon the database handle.
+// Without the handle, the repository is kept in memory, and persisted with the log if it is passed.
+func NewRepo(db *sql.DB, w *storage.WAL) (IRepository, error) {
+	if db == nil && w != nil {
+		return NewPersistentRepo(w)
+	}
+	if db == nil {
 		return NewBasicRepo(), nil
 	}
-	return NewDBRepo(repoURL)
+	if storage.IsSQLite(db) {
+		return NewSQLiteRepo(db)
+	}
+	return NewDBRepo(db)
 }
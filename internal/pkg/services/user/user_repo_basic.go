@@ -2,20 +2,39 @@ package user
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 type BasicRepo struct {
 	users *sync.Map
+	keys  *sync.Map
 }
 
 func NewBasicRepo() *BasicRepo {
-	return &BasicRepo{users: &sync.Map{}}
+	return &BasicRepo{users: &sync.Map{}, keys: &sync.Map{}}
+}
+
+// NewPersistentRepo returns the in-memory repository restored from the log, which keeps all further changes.
+func NewPersistentRepo(w *storage.WAL) (*BasicRepo, error) {
+	r := NewBasicRepo()
+	if err := storage.Track[User](w, "users", r.users); err != nil {
+		return nil, err
+	}
+	if err := storage.Track[KeyPair](w, "keys", r.keys); err != nil {
+		return nil, err
+	}
+	if err := w.Recover(); err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
-func (r *BasicRepo) AddUser(_ context.Context, user User) (User, error) {
+func (r *BasicRepo) AddUser(ctx context.Context, user User) (User, error) {
 	if user.Name == "" || user.Password == "" {
 		return User{}, ErrCredMissing
 	}
@@ -23,19 +42,50 @@ func (r *BasicRepo) AddUser(_ context.Context, user User) (User, error) {
 	if _, ok := r.users.Load(user.ID); user.ID != "" && ok {
 		return User{}, ErrExists
 	}
+	if _, err := r.GetUserByName(ctx, user.Name); err == nil {
+		return User{}, ErrExists
+	}
 
 	id := uuid.NewString()
 	user.ID = id
-	r.users.Store(id, user)
+	if err := storage.Store(ctx, r.users, id, user); err != nil {
+		return User{}, err
+	}
 	return user, nil
 }
 
-func (r *BasicRepo) DeleteUser(_ context.Context, uid string) error {
+func (r *BasicRepo) DeleteUser(ctx context.Context, uid string) error {
 	if _, ok := r.users.Load(uid); !ok || uid == "" {
 		return ErrNotFound
 	}
-	r.users.Delete(uid)
-	return nil
+	if _, err := storage.Delete(ctx, r.users, uid); err != nil {
+		return err
+	}
+	_, err := storage.Delete(ctx, r.keys, uid)
+	return err
+}
+
+func (r *BasicRepo) Export(_ context.Context) (Dump, error) {
+	var dump Dump
+	r.users.Range(func(_, v any) bool {
+		dump.Users = append(dump.Users, v.(User))
+		return true
+	})
+	r.keys.Range(func(_, v any) bool {
+		dump.KeyPairs = append(dump.KeyPairs, v.(KeyPair))
+		return true
+	})
+
+	sort.Slice(dump.Users, func(i, j int) bool { return dump.Users[i].ID < dump.Users[j].ID })
+	sort.Slice(dump.KeyPairs, func(i, j int) bool { return dump.KeyPairs[i].UID < dump.KeyPairs[j].UID })
+	return dump, nil
+}
+
+func (r *BasicRepo) GetKeyPair(_ context.Context, uid string) (KeyPair, error) {
+	if kp, ok := r.keys.Load(uid); ok {
+		return kp.(KeyPair), nil
+	}
+	return KeyPair{}, ErrNoKeyPair
 }
 
 func (r *BasicRepo) GetUserByID(_ context.Context, uid string) (User, error) {
@@ -68,3 +118,51 @@ func (r *BasicRepo) GetUserByName(_ context.Context, name string) (User, error)
 	}
 	return user, nil
 }
+
+// Import stores the users with their IDs. The changes are undone on failure within the unit of work only.
+func (r *BasicRepo) Import(ctx context.Context, dump Dump) error {
+	for _, u := range dump.Users {
+		if u.ID == "" || u.Name == "" || u.Password == "" {
+			return ErrCredMissing
+		}
+		if _, ok := r.users.Load(u.ID); ok {
+			return ErrExists
+		}
+		if _, err := r.GetUserByName(ctx, u.Name); err == nil {
+			return ErrExists
+		}
+		if err := storage.Store(ctx, r.users, u.ID, u); err != nil {
+			return err
+		}
+	}
+
+	for _, kp := range dump.KeyPairs {
+		if _, ok := r.keys.Load(kp.UID); ok {
+			return ErrExists
+		}
+		if err := r.StoreKeyPair(ctx, kp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BasicRepo) UpdateUser(ctx context.Context, user User) error {
+	if user.Name == "" || user.Password == "" {
+		return ErrCredMissing
+	}
+	if _, ok := r.users.Load(user.ID); !ok || user.ID == "" {
+		return ErrNotFound
+	}
+	if su, err := r.GetUserByName(ctx, user.Name); err == nil && su.ID != user.ID {
+		return ErrExists
+	}
+	return storage.Store(ctx, r.users, user.ID, user)
+}
+
+func (r *BasicRepo) StoreKeyPair(ctx context.Context, kp KeyPair) error {
+	if _, ok := r.users.Load(kp.UID); !ok || kp.UID == "" {
+		return ErrNotFound
+	}
+	return storage.Store(ctx, r.keys, kp.UID, kp)
+}
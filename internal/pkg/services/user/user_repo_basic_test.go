@@ -6,50 +6,40 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 func TestBasicRepo_AddUser(t *testing.T) {
-	for _, tt := range getAddUserCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.AddUser(context.Background(), tt.user)
-			assert.Equal(t, tt.want.Name, got.Name)
-			assert.Equal(t, tt.want.Password, got.Password)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testAddUser(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_DeleteUser(t *testing.T) {
-	for _, tt := range getDeleteUserCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			err := r.DeleteUser(context.Background(), tt.uid)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testDeleteUser(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_ExportImport(t *testing.T) {
+	testExportImport(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_GetKeyPair(t *testing.T) {
+	testGetKeyPair(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_GetUserByID(t *testing.T) {
-	for _, tt := range getGetUserByIDCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.GetUserByID(context.Background(), tt.uid)
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testGetUserByID(t, initTestBasicRepo)
 }
 
 func TestBasicRepo_GetUserByName(t *testing.T) {
-	for _, tt := range getGetUserByNameCases() {
-		t.Run(tt.name, func(t *testing.T) {
-			r := initBasicRepo(tt.repo)
-			got, err := r.GetUserByName(context.Background(), tt.uName)
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
+	testGetUserByName(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_StoreKeyPair(t *testing.T) {
+	testStoreKeyPair(t, initTestBasicRepo)
+}
+
+func TestBasicRepo_UpdateUser(t *testing.T) {
+	testUpdateUser(t, initTestBasicRepo)
 }
 
 func TestNewBasicRepo(t *testing.T) {
@@ -78,3 +68,43 @@ func TestNewBasicRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPersistentRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	r := initPersistentRepo(t, dir)
+	u, err := r.AddUser(ctx, User{Name: "test", Password: "test"})
+	assert.NoError(t, err)
+	kp := KeyPair{UID: u.ID, PublicKey: []byte("public"), PrivateKey: []byte("private")}
+	assert.NoError(t, r.StoreKeyPair(ctx, kp))
+	_, err = r.AddUser(ctx, User{Name: "deleted", Password: "test"})
+	assert.NoError(t, err)
+	d, err := r.GetUserByName(ctx, "deleted")
+	assert.NoError(t, err)
+	assert.NoError(t, r.DeleteUser(ctx, d.ID))
+
+	// The repository is restored from the log of the one abandoned as if the server crashed.
+	r = initPersistentRepo(t, dir)
+	got, err := r.GetUserByName(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, u, got)
+	gotKP, err := r.GetKeyPair(ctx, u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, kp, gotKP)
+	_, err = r.GetUserByName(ctx, "deleted")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func initPersistentRepo(t *testing.T, dir string) *BasicRepo {
+	t.Helper()
+	w, err := storage.OpenWAL(dir, "users", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewPersistentRepo(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -5,7 +5,14 @@ import (
 	"database/sql"
 	"errors"
 
-	_ "github.com/jackc/pgx/v5/stdlib" // SQL driver
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
 )
 
 type DBRepo struct {
@@ -13,30 +20,31 @@ type DBRepo struct {
 }
 
 const (
-	CreateUserTable = `CREATE TABLE IF NOT EXISTS users(
-    	id UUID DEFAULT gen_random_uuid(),
-    	name VARCHAR(255),
-    	password VARCHAR(255),
-    	UNIQUE(name),
-    	PRIMARY KEY(id))`
 	AddUser       = "INSERT INTO users(name, password) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING id"
 	DeleteUser    = "DELETE FROM users WHERE id = $1"
+	ExportKeys    = "SELECT uid, public_key, private_key FROM user_keys ORDER BY uid"
+	ExportUsers   = "SELECT id, name, password FROM users ORDER BY id"
+	GetKeyPair    = "SELECT uid, public_key, private_key FROM user_keys WHERE uid = $1"
 	GetUserByID   = "SELECT * FROM users WHERE id = $1"
 	GetUserByName = "SELECT * FROM users WHERE name = $1"
+	ImportKeyPair = "INSERT INTO user_keys(uid, public_key, private_key) VALUES ($1, $2, $3)"
+	ImportUser    = "INSERT INTO users(id, name, password) VALUES ($1, $2, $3)"
+	StoreKeyPair  = `
+		INSERT INTO user_keys(uid, public_key, private_key) VALUES ($1, $2, $3)
+		ON CONFLICT (uid) DO UPDATE SET public_key = EXCLUDED.public_key, private_key = EXCLUDED.private_key
+	`
+	UpdateUser = "UPDATE users SET name = $2, password = $3 WHERE id = $1"
 )
 
-func NewDBRepo(url string) (*DBRepo, error) {
-	if url == "" {
-		return &DBRepo{}, ErrDBMissingURL
-	}
-
-	db, err := sql.Open("pgx", url)
-	if err != nil {
-		return &DBRepo{}, err
+func NewDBRepo(db *sql.DB) (*DBRepo, error) {
+	if db == nil {
+		return &DBRepo{}, ErrDBMissing
 	}
+	return &DBRepo{db: db}, nil
+}
 
-	_, err = db.ExecContext(context.Background(), CreateUserTable)
-	return &DBRepo{db: db}, err
+func (r *DBRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
 }
 
 func (r *DBRepo) AddUser(ctx context.Context, user User) (User, error) {
@@ -44,12 +52,13 @@ func (r *DBRepo) AddUser(ctx context.Context, user User) (User, error) {
 		return User{}, ErrCredMissing
 	}
 
+	// The conflicting insert returns no rows, or fails on the unique name if the concurrent insert isn't committed yet.
 	var id string
-	if err := r.db.QueryRowContext(ctx, AddUser, user.Name, user.Password).Scan(&id); err != nil {
+	if err := r.conn(ctx).QueryRowContext(ctx, AddUser, user.Name, user.Password).Scan(&id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return User{}, ErrNotFound
+			return User{}, ErrExists
 		}
-		return User{}, err
+		return User{}, mapPgError(err)
 	}
 
 	user.ID = id
@@ -57,7 +66,7 @@ func (r *DBRepo) AddUser(ctx context.Context, user User) (User, error) {
 }
 
 func (r *DBRepo) DeleteUser(ctx context.Context, uid string) error {
-	res, err := r.db.ExecContext(ctx, DeleteUser, uid)
+	res, err := r.conn(ctx).ExecContext(ctx, DeleteUser, uid)
 	if err != nil {
 		return err
 	}
@@ -73,6 +82,55 @@ func (r *DBRepo) DeleteUser(ctx context.Context, uid string) error {
 	return nil
 }
 
+func (r *DBRepo) Export(ctx context.Context) (Dump, error) {
+	var dump Dump
+	rows, err := r.conn(ctx).QueryContext(ctx, ExportUsers)
+	if err != nil {
+		return Dump{}, err
+	}
+	for rows.Next() {
+		var u User
+		if err = rows.Scan(&u.ID, &u.Name, &u.Password); err != nil {
+			break
+		}
+		dump.Users = append(dump.Users, u)
+	}
+	if err = closeRows(rows, err); err != nil {
+		return Dump{}, err
+	}
+
+	if rows, err = r.conn(ctx).QueryContext(ctx, ExportKeys); err != nil {
+		return Dump{}, err
+	}
+	for rows.Next() {
+		var kp KeyPair
+		if err = rows.Scan(&kp.UID, &kp.PublicKey, &kp.PrivateKey); err != nil {
+			break
+		}
+		dump.KeyPairs = append(dump.KeyPairs, kp)
+	}
+	if err = closeRows(rows, err); err != nil {
+		return Dump{}, err
+	}
+	return dump, nil
+}
+
+func (r *DBRepo) GetKeyPair(ctx context.Context, uid string) (KeyPair, error) {
+	if uid == "" {
+		return KeyPair{}, ErrNoKeyPair
+	}
+
+	var kp KeyPair
+	err := r.conn(ctx).QueryRowContext(ctx, GetKeyPair, uid).Scan(&kp.UID, &kp.PublicKey, &kp.PrivateKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return KeyPair{}, ErrNoKeyPair
+		}
+		return KeyPair{}, err
+	}
+	return kp, nil
+}
+
 func (r *DBRepo) GetUserByID(ctx context.Context, uid string) (User, error) {
 	if uid == "" {
 		return User{}, ErrNotFound
@@ -87,11 +145,95 @@ func (r *DBRepo) GetUserByName(ctx context.Context, name string) (User, error) {
 	return r.getUser(ctx, GetUserByName, name)
 }
 
+func (r *DBRepo) Import(ctx context.Context, dump Dump) error {
+	for _, u := range dump.Users {
+		if u.ID == "" || u.Name == "" || u.Password == "" {
+			return ErrCredMissing
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportUser, u.ID, u.Name, u.Password); err != nil {
+			return mapPgError(err)
+		}
+	}
+	for _, kp := range dump.KeyPairs {
+		if _, err := r.conn(ctx).ExecContext(ctx, ImportKeyPair, kp.UID, kp.PublicKey, kp.PrivateKey); err != nil {
+			return mapPgError(err)
+		}
+	}
+	return nil
+}
+
+func (r *DBRepo) StoreKeyPair(ctx context.Context, kp KeyPair) error {
+	if kp.UID == "" {
+		return ErrNotFound
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, StoreKeyPair, kp.UID, kp.PublicKey, kp.PrivateKey)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return ErrNotFound
+		}
+	}
+	return err
+}
+
+func (r *DBRepo) UpdateUser(ctx context.Context, user User) error {
+	if user.Name == "" || user.Password == "" {
+		return ErrCredMissing
+	}
+	if user.ID == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, UpdateUser, user.ID, user.Name, user.Password)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrExists
+		}
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 func (r *DBRepo) getUser(ctx context.Context, query string, args ...any) (User, error) {
 	var user User
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Name, &user.Password)
+	err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Name, &user.Password)
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		return User{}, ErrNotFound
 	}
-	return user, nil
+	return user, err
+}
+
+func mapPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return ErrExists
+		case foreignKeyViolation:
+			return ErrNotFound
+		}
+	}
+	return err
+}
+
+// closeRows closes the rows, and returns the first error of the iteration.
+func closeRows(rows *sql.Rows, err error) error {
+	if err == nil {
+		err = rows.Err()
+	}
+	if cErr := rows.Close(); err == nil {
+		err = cErr
+	}
+	return err
 }
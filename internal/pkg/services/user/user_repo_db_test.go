@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,6 +52,51 @@ func TestDBRepo_DeleteUser(t *testing.T) {
 	}
 }
 
+func TestDBRepo_GetKeyPair(t *testing.T) {
+	for _, tt := range getGetKeyPairCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.uid != "" {
+				eq := mock.ExpectQuery(regexp.QuoteMeta(GetKeyPair)).WithArgs(tt.uid)
+				if tt.want.UID != "" {
+					eq.WillReturnRows(mock.NewRows([]string{"uid", "public_key", "private_key"}).
+						AddRow(tt.want.UID, tt.want.PublicKey, tt.want.PrivateKey))
+				} else {
+					eq.WillReturnError(sql.ErrNoRows)
+				}
+			}
+
+			got, err := r.GetKeyPair(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
+func TestDBRepo_StoreKeyPair(t *testing.T) {
+	r, mock, err := initDBRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kp := getTestKeyPair()
+	mock.ExpectExec(regexp.QuoteMeta(StoreKeyPair)).WithArgs(kp.UID, kp.PublicKey, kp.PrivateKey).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(StoreKeyPair)).WithArgs("unknown", kp.PublicKey, kp.PrivateKey).
+		WillReturnError(&pgconn.PgError{Code: foreignKeyViolation})
+
+	assert.NoError(t, r.StoreKeyPair(context.Background(), kp))
+	kp.UID = "unknown"
+	assert.Equal(t, ErrNotFound, r.StoreKeyPair(context.Background(), kp))
+	assert.Equal(t, ErrNotFound, r.StoreKeyPair(context.Background(), KeyPair{}))
+	checkMetExpectations(t, mock)
+}
+
 func TestDBRepo_GetUserByID(t *testing.T) {
 	for _, tt := range getGetUserByIDCases() {
 		t.Run(tt.name, func(t *testing.T) {
@@ -105,6 +151,22 @@ func TestDBRepo_GetUserByName(t *testing.T) {
 	}
 }
 
+func TestDBRepo_UpdateUser(t *testing.T) {
+	for _, tt := range getUpdateUserCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, err := initDBRepo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = getUpdateUserExec(mock, tt.repo, tt.user)
+			err = r.UpdateUser(context.Background(), tt.user)
+			assert.Equal(t, tt.wantErr, err)
+			checkMetExpectations(t, mock)
+		})
+	}
+}
+
 func TestNewDBRepo(t *testing.T) {
 	type want struct {
 		repoType  string
@@ -113,12 +175,12 @@ func TestNewDBRepo(t *testing.T) {
 	}
 	tests := []struct {
 		name    string
-		url     string
+		db      *sql.DB
 		want    want
 		wantErr bool
 	}{
 		{
-			name:    "Empty repo URL",
+			name:    "DB is missing",
 			wantErr: true,
 			want: want{
 				repoType:  "*user.DBRepo",
@@ -127,19 +189,18 @@ func TestNewDBRepo(t *testing.T) {
 			},
 		},
 		{
-			name: "Wrong Repo URL is present",
-			url:  "postgres://localhost:5432/test",
+			name: "DB is present",
+			db:   initDB(t),
 			want: want{
 				repoType:  "*user.DBRepo",
 				fieldName: "db",
 				fieldType: "*sql.DB",
 			},
-			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewDBRepo(tt.url)
+			got, err := NewDBRepo(tt.db)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rGot := reflect.ValueOf(got)
@@ -159,11 +220,35 @@ func getAddUserQuery(mock sqlmock.Sqlmock, repo map[string]User, user User) *sql
 
 	eq := mock.ExpectQuery(regexp.QuoteMeta(AddUser)).WithArgs(user.Name, user.Password)
 	if repo[user.ID].Name != "" {
-		return eq.WillReturnError(ErrExists)
+		return eq.WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+	}
+
+	rows := mock.NewRows([]string{"id"})
+	for _, u := range repo {
+		if u.Name == user.Name {
+			return eq.WillReturnRows(rows)
+		}
 	}
+	return eq.WillReturnRows(rows.AddRow("id"))
+}
 
-	rows := mock.NewRows([]string{"id"}).AddRow("id")
-	return eq.WillReturnRows(rows)
+func getUpdateUserExec(mock sqlmock.Sqlmock, repo map[string]User, user User) *sqlmock.ExpectedExec {
+	if user.Name == "" || user.Password == "" || user.ID == "" {
+		return nil
+	}
+
+	ee := mock.ExpectExec(regexp.QuoteMeta(UpdateUser)).WithArgs(user.ID, user.Name, user.Password)
+	for _, u := range repo {
+		if u.Name == user.Name && u.ID != user.ID {
+			return ee.WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+		}
+	}
+
+	var rows int64
+	if repo[user.ID].ID != "" {
+		rows = 1
+	}
+	return ee.WillReturnResult(sqlmock.NewResult(1, rows))
 }
 
 func checkMetExpectations(t *testing.T, mock sqlmock.Sqlmock) {
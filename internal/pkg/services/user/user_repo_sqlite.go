@@ -0,0 +1,235 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type SQLiteRepo struct {
+	db *sql.DB
+}
+
+const (
+	createSQLiteUsers = `CREATE TABLE IF NOT EXISTS users(
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		password TEXT NOT NULL)`
+	createSQLiteUserKeys = `CREATE TABLE IF NOT EXISTS user_keys(
+		uid TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		public_key BLOB NOT NULL,
+		private_key BLOB NOT NULL)`
+	sqliteAddUser       = "INSERT INTO users(id, name, password) VALUES (?1, ?2, ?3) ON CONFLICT DO NOTHING RETURNING id"
+	sqliteDeleteUser    = "DELETE FROM users WHERE id = ?1"
+	sqliteExportKeys    = "SELECT uid, public_key, private_key FROM user_keys ORDER BY uid"
+	sqliteExportUsers   = "SELECT id, name, password FROM users ORDER BY id"
+	sqliteGetKeyPair    = "SELECT uid, public_key, private_key FROM user_keys WHERE uid = ?1"
+	sqliteGetUserByID   = "SELECT id, name, password FROM users WHERE id = ?1"
+	sqliteGetUserByName = "SELECT id, name, password FROM users WHERE name = ?1"
+	sqliteImportKeyPair = "INSERT INTO user_keys(uid, public_key, private_key) VALUES (?1, ?2, ?3)"
+	sqliteImportUser    = "INSERT INTO users(id, name, password) VALUES (?1, ?2, ?3)"
+	sqliteStoreKeyPair  = `
+		INSERT INTO user_keys(uid, public_key, private_key) VALUES (?1, ?2, ?3)
+		ON CONFLICT (uid) DO UPDATE SET public_key = excluded.public_key, private_key = excluded.private_key
+	`
+	sqliteUpdateUser = "UPDATE users SET name = ?2, password = ?3 WHERE id = ?1"
+)
+
+// NewSQLiteRepo returns the repository keeping the users in the SQLite database, and creates its tables if needed.
+func NewSQLiteRepo(db *sql.DB) (*SQLiteRepo, error) {
+	if db == nil {
+		return &SQLiteRepo{}, ErrDBMissing
+	}
+
+	for _, q := range []string{createSQLiteUsers, createSQLiteUserKeys} {
+		if _, err := db.ExecContext(context.Background(), q); err != nil {
+			return &SQLiteRepo{}, err
+		}
+	}
+	return &SQLiteRepo{db: db}, nil
+}
+
+func (r *SQLiteRepo) conn(ctx context.Context) storage.Querier {
+	return storage.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepo) AddUser(ctx context.Context, user User) (User, error) {
+	if user.Name == "" || user.Password == "" {
+		return User{}, ErrCredMissing
+	}
+
+	var id string
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteAddUser, uuid.NewString(), user.Name, user.Password).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, ErrExists
+		}
+		return User{}, err
+	}
+
+	user.ID = id
+	return user, nil
+}
+
+func (r *SQLiteRepo) DeleteUser(ctx context.Context, uid string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteDeleteUser, uid)
+	if err != nil {
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) Export(ctx context.Context) (Dump, error) {
+	var dump Dump
+	rows, err := r.conn(ctx).QueryContext(ctx, sqliteExportUsers)
+	if err != nil {
+		return Dump{}, err
+	}
+	for rows.Next() {
+		var u User
+		if err = rows.Scan(&u.ID, &u.Name, &u.Password); err != nil {
+			break
+		}
+		dump.Users = append(dump.Users, u)
+	}
+	if err = closeRows(rows, err); err != nil {
+		return Dump{}, err
+	}
+
+	if rows, err = r.conn(ctx).QueryContext(ctx, sqliteExportKeys); err != nil {
+		return Dump{}, err
+	}
+	for rows.Next() {
+		var kp KeyPair
+		if err = rows.Scan(&kp.UID, &kp.PublicKey, &kp.PrivateKey); err != nil {
+			break
+		}
+		dump.KeyPairs = append(dump.KeyPairs, kp)
+	}
+	if err = closeRows(rows, err); err != nil {
+		return Dump{}, err
+	}
+	return dump, nil
+}
+
+func (r *SQLiteRepo) GetKeyPair(ctx context.Context, uid string) (KeyPair, error) {
+	if uid == "" {
+		return KeyPair{}, ErrNoKeyPair
+	}
+
+	var kp KeyPair
+	err := r.conn(ctx).QueryRowContext(ctx, sqliteGetKeyPair, uid).Scan(&kp.UID, &kp.PublicKey, &kp.PrivateKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return KeyPair{}, ErrNoKeyPair
+		}
+		return KeyPair{}, err
+	}
+	return kp, nil
+}
+
+func (r *SQLiteRepo) GetUserByID(ctx context.Context, uid string) (User, error) {
+	if uid == "" {
+		return User{}, ErrNotFound
+	}
+	return r.getUser(ctx, sqliteGetUserByID, uid)
+}
+
+func (r *SQLiteRepo) GetUserByName(ctx context.Context, name string) (User, error) {
+	if name == "" {
+		return User{}, ErrNotFound
+	}
+	return r.getUser(ctx, sqliteGetUserByName, name)
+}
+
+func (r *SQLiteRepo) Import(ctx context.Context, dump Dump) error {
+	for _, u := range dump.Users {
+		if u.ID == "" || u.Name == "" || u.Password == "" {
+			return ErrCredMissing
+		}
+		if _, err := r.conn(ctx).ExecContext(ctx, sqliteImportUser, u.ID, u.Name, u.Password); err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	for _, kp := range dump.KeyPairs {
+		if _, err := r.conn(ctx).ExecContext(ctx, sqliteImportKeyPair, kp.UID, kp.PublicKey, kp.PrivateKey); err != nil {
+			return mapSQLiteError(err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) StoreKeyPair(ctx context.Context, kp KeyPair) error {
+	if kp.UID == "" {
+		return ErrNotFound
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, sqliteStoreKeyPair, kp.UID, kp.PublicKey, kp.PrivateKey)
+	if isSQLiteConstraint(err, sqlite3.ErrConstraintForeignKey) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *SQLiteRepo) UpdateUser(ctx context.Context, user User) error {
+	if user.Name == "" || user.Password == "" {
+		return ErrCredMissing
+	}
+	if user.ID == "" {
+		return ErrNotFound
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, sqliteUpdateUser, user.ID, user.Name, user.Password)
+	if err != nil {
+		if isSQLiteConstraint(err, sqlite3.ErrConstraintUnique) {
+			return ErrExists
+		}
+		return err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepo) getUser(ctx context.Context, query string, args ...any) (User, error) {
+	var user User
+	err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Name, &user.Password)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	return user, err
+}
+
+func mapSQLiteError(err error) error {
+	switch {
+	case isSQLiteConstraint(err, sqlite3.ErrConstraintPrimaryKey), isSQLiteConstraint(err, sqlite3.ErrConstraintUnique):
+		return ErrExists
+	case isSQLiteConstraint(err, sqlite3.ErrConstraintForeignKey):
+		return ErrNotFound
+	}
+	return err
+}
+
+func isSQLiteConstraint(err error, code sqlite3.ErrNoExtended) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == code
+}
@@ -0,0 +1,115 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+func TestSQLiteRepo_AddUser(t *testing.T) {
+	testAddUser(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_DeleteUser(t *testing.T) {
+	testDeleteUser(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_ExportImport(t *testing.T) {
+	testExportImport(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetKeyPair(t *testing.T) {
+	testGetKeyPair(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetUserByID(t *testing.T) {
+	testGetUserByID(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_GetUserByName(t *testing.T) {
+	testGetUserByName(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_StoreKeyPair(t *testing.T) {
+	testStoreKeyPair(t, initSQLiteRepo)
+}
+
+func TestSQLiteRepo_UpdateUser(t *testing.T) {
+	testUpdateUser(t, initSQLiteRepo)
+}
+
+func TestNewSQLiteRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *sql.DB
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "DB is missing",
+			want:    "*user.SQLiteRepo",
+			wantErr: ErrDBMissing,
+		},
+		{
+			name: "DB is present",
+			db:   initSQLiteDB(t),
+			want: "*user.SQLiteRepo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLiteRepo(tt.db)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, reflect.ValueOf(got).Type().String())
+		})
+	}
+}
+
+// initSQLiteRepo adds the owners of the key pairs missing from the data, as the keys reference the users.
+func initSQLiteRepo(t *testing.T, data map[string]User, keys ...KeyPair) IRepository {
+	t.Helper()
+	r, err := NewSQLiteRepo(initSQLiteDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, u := range data {
+		if _, err = r.db.ExecContext(ctx, "INSERT INTO users(id, name, password) VALUES (?1, ?2, ?3)",
+			u.ID, u.Name, u.Password); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, kp := range keys {
+		if _, err = r.db.ExecContext(ctx, "INSERT OR IGNORE INTO users(id, name, password) VALUES (?1, ?1, '')",
+			kp.UID); err != nil {
+			t.Fatal(err)
+		}
+		if err = r.StoreKeyPair(ctx, kp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func initSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	st, err := storage.New(context.Background(), storage.Config{
+		URL: storage.SQLiteScheme + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cErr := st.Close(); cErr != nil {
+			t.Error(cErr)
+		}
+	})
+	return st.DB()
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+	"database/sql"
 	"reflect"
 	"sync"
 	"testing"
@@ -24,6 +26,14 @@ type deleteUserCase struct {
 	wantErr error
 }
 
+type getKeyPairCase struct {
+	name    string
+	keys    []KeyPair
+	uid     string
+	want    KeyPair
+	wantErr error
+}
+
 type getUserByIDCase struct {
 	name    string
 	repo    map[string]User
@@ -40,27 +50,41 @@ type getUserByNameCase struct {
 	wantErr error
 }
 
+type updateUserCase struct {
+	name    string
+	repo    map[string]User
+	user    User
+	wantErr error
+}
+
+// repoInit creates the repository of the tested backend filled with the users and their key pairs.
+type repoInit func(t *testing.T, data map[string]User, keys ...KeyPair) IRepository
+
 func TestNewRepo(t *testing.T) {
 	tests := []struct {
 		name    string
-		repoURL string
+		db      *sql.DB
 		want    string
 		wantErr bool
 	}{
 		{
-			name: "Repo URL is missing",
+			name: "DB is missing",
 			want: "*user.BasicRepo",
 		},
 		{
-			name:    "Wrong Repo URL is present",
-			repoURL: "postgres://localhost:5432/test",
-			want:    "*user.DBRepo",
-			wantErr: true,
+			name: "DB is present",
+			db:   initDB(t),
+			want: "*user.DBRepo",
+		},
+		{
+			name: "SQLite DB is present",
+			db:   initSQLiteDB(t),
+			want: "*user.SQLiteRepo",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewRepo(tt.repoURL)
+			got, err := NewRepo(tt.db, nil)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rGot := reflect.ValueOf(got)
@@ -69,12 +93,132 @@ func TestNewRepo(t *testing.T) {
 	}
 }
 
+func testAddUser(t *testing.T, initRepo repoInit) {
+	for _, tt := range getAddUserCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			got, err := r.AddUser(context.Background(), tt.user)
+			assert.Equal(t, tt.want.Name, got.Name)
+			assert.Equal(t, tt.want.Password, got.Password)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testDeleteUser(t *testing.T, initRepo repoInit) {
+	for _, tt := range getDeleteUserCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.DeleteUser(context.Background(), tt.uid)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testExportImport(t *testing.T, initRepo repoInit) {
+	ctx := context.Background()
+	kp := getTestKeyPair()
+	dump := Dump{
+		Users:    []User{{ID: "testID", Name: "test", Password: "test"}, {ID: "testID1", Name: "test1", Password: "test1"}},
+		KeyPairs: []KeyPair{kp},
+	}
+
+	r := initRepo(t, nil)
+	assert.NoError(t, r.Import(ctx, dump))
+	got, err := r.Export(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dump, got)
+
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Users: dump.Users[:1]}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{Users: []User{{ID: "testID2", Name: "test", Password: "test"}}}))
+	assert.Equal(t, ErrExists, r.Import(ctx, Dump{KeyPairs: []KeyPair{kp}}))
+	assert.Equal(t, ErrCredMissing, r.Import(ctx, Dump{Users: []User{{Name: "test2", Password: "test2"}}}))
+
+	unknown := KeyPair{UID: "unknown", PublicKey: kp.PublicKey, PrivateKey: kp.PrivateKey}
+	assert.Equal(t, ErrNotFound, r.Import(ctx, Dump{KeyPairs: []KeyPair{unknown}}))
+}
+
+func testGetKeyPair(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetKeyPairCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, nil, tt.keys...)
+
+			got, err := r.GetKeyPair(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testGetUserByID(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetUserByIDCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			got, err := r.GetUserByID(context.Background(), tt.uid)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testGetUserByName(t *testing.T, initRepo repoInit) {
+	for _, tt := range getGetUserByNameCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			got, err := r.GetUserByName(context.Background(), tt.uName)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func testStoreKeyPair(t *testing.T, initRepo repoInit) {
+	r := initRepo(t, map[string]User{"testID": {ID: "testID", Name: "test", Password: "test"}})
+	kp := getTestKeyPair()
+
+	unknown := KeyPair{UID: "unknown", PublicKey: kp.PublicKey, PrivateKey: kp.PrivateKey}
+	assert.Equal(t, ErrNotFound, r.StoreKeyPair(context.Background(), unknown))
+	assert.NoError(t, r.StoreKeyPair(context.Background(), kp))
+
+	got, err := r.GetKeyPair(context.Background(), kp.UID)
+	assert.NoError(t, err)
+	assert.Equal(t, kp, got)
+
+	assert.NoError(t, r.DeleteUser(context.Background(), kp.UID))
+	_, err = r.GetKeyPair(context.Background(), kp.UID)
+	assert.Equal(t, ErrNoKeyPair, err)
+}
+
+func testUpdateUser(t *testing.T, initRepo repoInit) {
+	for _, tt := range getUpdateUserCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			r := initRepo(t, tt.repo)
+			err := r.UpdateUser(context.Background(), tt.user)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				got, gErr := r.GetUserByID(context.Background(), tt.user.ID)
+				assert.NoError(t, gErr)
+				assert.Equal(t, tt.user, got)
+			}
+		})
+	}
+}
+
 func initBasicRepo(data map[string]User) *BasicRepo {
 	users := &sync.Map{}
 	for uid, user := range data {
 		users.Store(uid, user)
 	}
-	return &BasicRepo{users: users}
+	return &BasicRepo{users: users, keys: &sync.Map{}}
+}
+
+func initTestBasicRepo(_ *testing.T, data map[string]User, keys ...KeyPair) IRepository {
+	r := initBasicRepo(data)
+	for _, kp := range keys {
+		r.keys.Store(kp.UID, kp)
+	}
+	return r
 }
 
 func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
@@ -82,6 +226,15 @@ func initDBRepo() (*DBRepo, sqlmock.Sqlmock, error) {
 	return &DBRepo{db: db}, mock, err
 }
 
+func initDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
 func getAddUserCases() []addUserCase {
 	tu := User{
 		ID:       "testID",
@@ -109,6 +262,12 @@ func getAddUserCases() []addUserCase {
 			user:    tu,
 			wantErr: ErrExists,
 		},
+		{
+			name:    "User name exists",
+			repo:    map[string]User{"otherID": {ID: "otherID", Name: tu.Name, Password: "other"}},
+			user:    User{Name: tu.Name, Password: tu.Password},
+			wantErr: ErrExists,
+		},
 		{
 			name: "All arguments are correct",
 			user: tu,
@@ -183,3 +342,66 @@ func getGetUserByNameCases() []getUserByNameCase {
 		},
 	}
 }
+
+func getUpdateUserCases() []updateUserCase {
+	tu := User{ID: "testID", Name: "test", Password: "test"}
+	tu1 := User{ID: "testID1", Name: "test1", Password: "test1"}
+
+	return []updateUserCase{
+		{
+			name:    "No user passed",
+			repo:    map[string]User{tu.ID: tu},
+			wantErr: ErrCredMissing,
+		},
+		{
+			name:    "User with empty password is passed",
+			repo:    map[string]User{tu.ID: tu},
+			user:    User{ID: "testID", Name: "test"},
+			wantErr: ErrCredMissing,
+		},
+		{
+			name:    "No user ID present",
+			repo:    map[string]User{tu.ID: tu},
+			user:    User{ID: "testID0", Name: "test0", Password: "test0"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "User name is taken",
+			repo:    map[string]User{tu.ID: tu, tu1.ID: tu1},
+			user:    User{ID: "testID", Name: "test1", Password: "test"},
+			wantErr: ErrExists,
+		},
+		{
+			name: "User is updated",
+			repo: map[string]User{tu.ID: tu, tu1.ID: tu1},
+			user: User{ID: "testID", Name: "test0", Password: "test0"},
+		},
+	}
+}
+
+func getTestKeyPair() KeyPair {
+	return KeyPair{UID: "testID", PublicKey: []byte("public"), PrivateKey: []byte("private")}
+}
+
+func getGetKeyPairCases() []getKeyPairCase {
+	kp := getTestKeyPair()
+	return []getKeyPairCase{
+		{
+			name:    "No user ID passed",
+			keys:    []KeyPair{kp},
+			wantErr: ErrNoKeyPair,
+		},
+		{
+			name:    "User has no key pair",
+			keys:    []KeyPair{kp},
+			uid:     "testID1",
+			wantErr: ErrNoKeyPair,
+		},
+		{
+			name: "Key pair is returned",
+			keys: []KeyPair{kp},
+			uid:  "testID",
+			want: kp,
+		},
+	}
+}
@@ -7,49 +7,63 @@ import (
 	"strings"
 
 	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
 )
 
 type IRepository interface {
 	AddUser(ctx context.Context, user User) (User, error)
 	DeleteUser(ctx context.Context, uid string) error
+	Export(ctx context.Context) (Dump, error)
+	GetKeyPair(ctx context.Context, uid string) (KeyPair, error)
 	GetUserByID(ctx context.Context, uid string) (User, error)
 	GetUserByName(ctx context.Context, name string) (User, error)
+	Import(ctx context.Context, dump Dump) error
+	StoreKeyPair(ctx context.Context, kp KeyPair) error
+	UpdateUser(ctx context.Context, user User) error
 }
 
 type Service struct {
-	db IRepository
+	db  IRepository
+	uow storage.UnitOfWork
 }
 
-// NewService returns an instance of the Service with the associated repository.
-func NewService(repoURL string) (Service, error) {
-	db, err := NewRepo(repoURL)
-	return Service{db: db}, err
+// NewService returns an instance of the Service with the associated repository and unit of work.
+// Without the database handle, the users are persisted with the log if it is passed.
+func NewService(db *sql.DB, w *storage.WAL) (Service, error) {
+	repo, err := NewRepo(db, w)
+	return Service{db: repo, uow: storage.NewUnitOfWork(db)}, err
 }
 
-var ErrCredMissing = errors.New("the user is missing one or more required fields")
+var (
+	ErrCredMissing    = errors.New("the user is missing one or more required fields")
+	ErrInvalidKeyPair = errors.New("the key pair is missing or invalid")
+)
 
 // AddUser hashes the passed user's password and stores a new user.
-// If the user with the specified name already exists, it returns an error.
+// If the user with the specified name already exists, it returns ErrExists.
 func (s Service) AddUser(ctx context.Context, user User) error {
 	if user.Name == "" || user.Password == "" {
 		return ErrCredMissing
 	}
-	userExist, err := s.doesUserExist(ctx, user)
-	if err != nil {
-		return err
-	}
-	if userExist {
-		return ErrExists
-	}
 
 	hash, err := enc.HashPassword(user.Password)
 	if err != nil {
 		return err
 	}
-
 	user.Password = hash
-	_, err = s.db.AddUser(ctx, user)
-	return err
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		userExist, uErr := s.doesUserExist(ctx, user)
+		if uErr != nil {
+			return uErr
+		}
+		if userExist {
+			return ErrExists
+		}
+
+		_, uErr = s.db.AddUser(ctx, user)
+		return uErr
+	})
 }
 
 // GetUser gathers the user by its name and compares the passed and the stored passwords.
@@ -69,6 +83,124 @@ func (s Service) GetUser(ctx context.Context, user User) (User, error) {
 	return su, nil
 }
 
+// GetUserByID returns the stored user by the unique ID.
+func (s Service) GetUserByID(ctx context.Context, uid string) (User, error) {
+	return s.db.GetUserByID(ctx, uid)
+}
+
+// GetUserByName returns the stored user by the name without verifying the password.
+// It's intended for the flows where the user's identity has been already proven by other means.
+func (s Service) GetUserByName(ctx context.Context, name string) (User, error) {
+	u, err := s.db.GetUserByName(ctx, strings.ToLower(name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, ErrNotFound
+		}
+		return User{}, err
+	}
+	return u, nil
+}
+
+// GetKeyPair returns the user's key pair with the private key encrypted by the client.
+func (s Service) GetKeyPair(ctx context.Context, uid string) (KeyPair, error) {
+	return s.db.GetKeyPair(ctx, uid)
+}
+
+// GetPublicKey returns the public key of the user with the specified name.
+// The public keys are not secret, any user can seal the item keys to them.
+func (s Service) GetPublicKey(ctx context.Context, name string) ([]byte, error) {
+	u, err := s.GetUserByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	kp, err := s.db.GetKeyPair(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	return kp.PublicKey, nil
+}
+
+// SetKeyPair stores the user's key pair, replacing the existing one.
+// The private key must be encrypted by the client, the server only checks it's present.
+func (s Service) SetKeyPair(ctx context.Context, kp KeyPair) error {
+	if kp.UID == "" || len(kp.PublicKey) != enc.PublicKeySize || len(kp.PrivateKey) == 0 {
+		return ErrInvalidKeyPair
+	}
+	return s.db.StoreKeyPair(ctx, kp)
+}
+
+// Export returns all users along with their key pairs.
+func (s Service) Export(ctx context.Context) (Dump, error) {
+	return s.db.Export(ctx)
+}
+
+// Import stores the exported users with their IDs and password hashes, and their key pairs, as is.
+// If any of the users already exists, nothing is imported.
+func (s Service) Import(ctx context.Context, dump Dump) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.db.Import(ctx, dump)
+	})
+}
+
+// DeleteUser removes the stored user with the unique ID.
+func (s Service) DeleteUser(ctx context.Context, uid string) error {
+	return s.db.DeleteUser(ctx, uid)
+}
+
+// UpdateName changes the name of the user with the unique ID.
+// If another user with the specified name already exists, it returns an error.
+func (s Service) UpdateName(ctx context.Context, uid, name string) error {
+	if uid == "" || name == "" {
+		return ErrCredMissing
+	}
+
+	su, err := s.db.GetUserByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	su.Name = strings.ToLower(name)
+	return s.db.UpdateUser(ctx, su)
+}
+
+// UpdatePassword compares the passed current password with the stored one and replaces it with a new password.
+// If the current password doesn't match, or user is not found in the repository, the methods returns an error.
+func (s Service) UpdatePassword(ctx context.Context, uid, oldPwd, newPwd string) error {
+	if uid == "" || oldPwd == "" || newPwd == "" {
+		return ErrCredMissing
+	}
+
+	su, err := s.db.GetUserByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !enc.VerifyPassword(oldPwd, su.Password) {
+		return ErrNotFound
+	}
+
+	hash, err := enc.HashPassword(newPwd)
+	if err != nil {
+		return err
+	}
+
+	su.Password = hash
+	return s.db.UpdateUser(ctx, su)
+}
+
+// VerifyPassword compares the passed password with the stored password of the user with the unique ID.
+// If the passwords don't match, or user is not found in the repository, the methods returns an error.
+func (s Service) VerifyPassword(ctx context.Context, uid, pwd string) error {
+	su, err := s.db.GetUserByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !enc.VerifyPassword(pwd, su.Password) {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (s Service) doesUserExist(ctx context.Context, user User) (bool, error) {
 	su, err := s.db.GetUserByName(ctx, strings.ToLower(user.Name))
 	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, ErrNotFound) {
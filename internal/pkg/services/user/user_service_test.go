@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"reflect"
 	"testing"
 
@@ -13,24 +14,23 @@ import (
 func TestNewService(t *testing.T) {
 	tests := []struct {
 		name         string
-		repoURL      string
+		db           *sql.DB
 		wantRepoType string
 		wantErr      bool
 	}{
 		{
-			name:         "Repo URL is missing",
+			name:         "DB is missing",
 			wantRepoType: "*user.BasicRepo",
 		},
 		{
-			name:         "Wrong Repo URL is present",
-			repoURL:      "postgres://localhost:5432/test",
+			name:         "DB is present",
+			db:           initDB(t),
 			wantRepoType: "*user.DBRepo",
-			wantErr:      true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewService(tt.repoURL)
+			got, err := NewService(tt.db, nil)
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			rRepo := reflect.ValueOf(got.db)
@@ -85,3 +85,214 @@ func TestService_AddUser(t *testing.T) {
 		})
 	}
 }
+
+func TestService_AddUserConcurrently(t *testing.T) {
+	s := Service{db: initBasicRepo(nil)}
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			errs <- s.AddUser(context.Background(), User{Name: "test", Password: "test"})
+		}()
+	}
+
+	added := 0
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err == nil {
+			added++
+		} else {
+			assert.Equal(t, ErrExists, err)
+		}
+	}
+	assert.Equal(t, 1, added)
+}
+
+func TestService_GetUserByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     map[string]User
+		userName string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "User is missing",
+			repo:     map[string]User{"test": {ID: "test", Name: "test", Password: "test"}},
+			userName: "test1",
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:     "User is found",
+			repo:     map[string]User{"test": {ID: "test", Name: "test", Password: "test"}},
+			userName: "Test",
+			want:     "test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(tt.repo)}
+			got, err := s.GetUserByName(context.Background(), tt.userName)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got.ID)
+		})
+	}
+}
+
+func TestService_UpdateName(t *testing.T) {
+	type args struct {
+		uid  string
+		name string
+	}
+	tests := []struct {
+		name    string
+		repo    map[string]User
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "Name is missing",
+			repo:    map[string]User{"test": {ID: "test", Name: "test", Password: "test"}},
+			args:    args{uid: "test"},
+			wantErr: ErrCredMissing,
+		},
+		{
+			name:    "User is missing",
+			repo:    map[string]User{"test": {ID: "test", Name: "test", Password: "test"}},
+			args:    args{uid: "test1", name: "test1"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Name is taken",
+			repo: map[string]User{
+				"test":  {ID: "test", Name: "test", Password: "test"},
+				"test1": {ID: "test1", Name: "test1", Password: "test1"},
+			},
+			args:    args{uid: "test", name: "Test1"},
+			wantErr: ErrExists,
+		},
+		{
+			name: "Name is updated",
+			repo: map[string]User{"test": {ID: "test", Name: "test", Password: "test"}},
+			args: args{uid: "test", name: "Test1"},
+			want: "test1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(tt.repo)}
+			err := s.UpdateName(context.Background(), tt.args.uid, tt.args.name)
+			assert.Equal(t, tt.wantErr, err)
+
+			if err == nil {
+				u, gErr := s.GetUserByID(context.Background(), tt.args.uid)
+				assert.NoError(t, gErr)
+				assert.Equal(t, tt.want, u.Name)
+			}
+		})
+	}
+}
+
+func TestService_UpdatePassword(t *testing.T) {
+	tp, err := enc.HashPassword("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type args struct {
+		uid    string
+		oldPwd string
+		newPwd string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "New password is missing",
+			args:    args{uid: "test", oldPwd: "test"},
+			wantErr: ErrCredMissing,
+		},
+		{
+			name:    "User is missing",
+			args:    args{uid: "test1", oldPwd: "test", newPwd: "test1"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Current password doesn't match",
+			args:    args{uid: "test", oldPwd: "wrong", newPwd: "test1"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Password is updated",
+			args: args{uid: "test", oldPwd: "test", newPwd: "test1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(map[string]User{"test": {ID: "test", Name: "test", Password: tp}})}
+			uErr := s.UpdatePassword(context.Background(), tt.args.uid, tt.args.oldPwd, tt.args.newPwd)
+			assert.Equal(t, tt.wantErr, uErr)
+
+			if uErr == nil {
+				assert.NoError(t, s.VerifyPassword(context.Background(), tt.args.uid, tt.args.newPwd))
+				assert.Equal(t, ErrNotFound, s.VerifyPassword(context.Background(), tt.args.uid, tt.args.oldPwd))
+			}
+		})
+	}
+}
+
+func TestService_SetKeyPair(t *testing.T) {
+	pub, _, err := enc.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		kp      KeyPair
+		wantErr error
+	}{
+		{
+			name:    "Key pair is missing",
+			kp:      KeyPair{UID: "testID"},
+			wantErr: ErrInvalidKeyPair,
+		},
+		{
+			name:    "Public key is invalid",
+			kp:      KeyPair{UID: "testID", PublicKey: []byte("public"), PrivateKey: []byte("private")},
+			wantErr: ErrInvalidKeyPair,
+		},
+		{
+			name:    "User is not present",
+			kp:      KeyPair{UID: "unknown", PublicKey: pub, PrivateKey: []byte("private")},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "Key pair is stored",
+			kp:   KeyPair{UID: "testID", PublicKey: pub, PrivateKey: []byte("private")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{db: initBasicRepo(map[string]User{"testID": {ID: "testID", Name: "test", Password: "test"}})}
+			err := s.SetKeyPair(context.Background(), tt.kp)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			got, gErr := s.GetPublicKey(context.Background(), "Test")
+			assert.NoError(t, gErr)
+			assert.Equal(t, pub, got)
+		})
+	}
+}
+
+func TestService_GetPublicKey(t *testing.T) {
+	s := Service{db: initBasicRepo(map[string]User{"testID": {ID: "testID", Name: "test", Password: "test"}})}
+
+	_, err := s.GetPublicKey(context.Background(), "unknown")
+	assert.Equal(t, ErrNotFound, err)
+	_, err = s.GetPublicKey(context.Background(), "test")
+	assert.Equal(t, ErrNoKeyPair, err)
+}
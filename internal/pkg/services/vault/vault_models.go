@@ -0,0 +1,49 @@
+package vault
+
+import (
+	"context"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+)
+
+// Version is the current version of the vault archive format.
+const Version = 1
+
+// magic tells the vault archives from the server backups.
+const magic = "GKVAULT"
+
+// Duplicates is the policy applied to the imported item having the same type and name as the stored one.
+type Duplicates string
+
+const (
+	DuplicatesSkip    Duplicates = "skip"
+	DuplicatesReplace Duplicates = "replace"
+	DuplicatesKeep    Duplicates = "keep"
+)
+
+// Summary counts the imported items by the way they were merged into the account,
+// and the sections of the archive skipped as unknown, e.g. the ones of the item types the server doesn't have.
+type Summary struct {
+	Imported        int
+	Replaced        int
+	Skipped         int
+	UnknownSections int
+}
+
+// Vault maps the names of the sections to their items.
+// The items are the decrypted payloads keyed by the field names, without their server-side IDs.
+type Vault map[string][]map[string]any
+
+// Section holds the items of one storage type, e.g. the "card" section holds the cards.
+// The Aliases are the names the earlier archives used for the section, they are read on import.
+type Section struct {
+	Name    string
+	Aliases []string
+	Storage data.StorageType
+}
+
+// Normalizer checks the imported item of the storage type the way the item requests are checked,
+// and returns the payload to store.
+type Normalizer interface {
+	NormalizePayload(ctx context.Context, uid string, t data.StorageType, item map[string]any) (map[string]any, error)
+}
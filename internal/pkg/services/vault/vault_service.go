@@ -0,0 +1,225 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/archive"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+type Service struct {
+	uow         storage.UnitOfWork
+	dataService data.Service
+	sections    []Section
+	normalizer  Normalizer
+}
+
+var ErrDuplicates = errors.New("unknown duplicates policy")
+
+// NewService returns an instance of the Service with the associated data microservice.
+// The unit of work makes the import atomic, so the failed one leaves the account untouched.
+// The archives hold the items of the listed sections only. The imported items are checked by the normalizer,
+// or stored as is, if it's nil.
+func NewService(uow storage.UnitOfWork, ds data.Service, sections []Section, n Normalizer) Service {
+	return Service{uow: uow, dataService: ds, sections: sections, normalizer: n}
+}
+
+// Export writes the archive of all items owned by the user, encrypted with the key derived from the password.
+// The items shared with the user belong to their owners, so they are left out.
+func (s Service) Export(ctx context.Context, uid string, w io.Writer, password string) error {
+	if uid == "" {
+		return data.ErrEmpty
+	}
+
+	v := make(Vault, len(s.sections))
+	for _, sec := range s.sections {
+		var err error
+		if v[sec.Name], err = exportItems(ctx, s.dataService, uid, sec.Storage); err != nil {
+			return err
+		}
+	}
+
+	_, err := archive.Write(w, magic, Version, password, v)
+	return err
+}
+
+// Import merges the archive into the user's account.
+// The item with the same type and name as the stored one is skipped, replaces the stored one,
+// or is stored alongside it, depending on the policy. The empty policy skips the duplicates.
+// The unknown sections are skipped, so the archive of the server with more item types is imported partially.
+func (s Service) Import(ctx context.Context, uid string, r io.Reader, password string, dup Duplicates) (Summary, error) {
+	if uid == "" {
+		return Summary{}, data.ErrEmpty
+	}
+	if dup == "" {
+		dup = DuplicatesSkip
+	}
+	if dup != DuplicatesSkip && dup != DuplicatesReplace && dup != DuplicatesKeep {
+		return Summary{}, ErrDuplicates
+	}
+
+	var v Vault
+	if _, err := archive.Read(r, magic, Version, password, &v); err != nil {
+		return Summary{}, err
+	}
+
+	unknown := 0
+	for name := range v {
+		if !s.hasSection(name) {
+			unknown++
+		}
+	}
+
+	var sum Summary
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		sum = Summary{UnknownSections: unknown}
+		for _, sec := range s.sections {
+			if err := s.importItems(ctx, uid, sec.Storage, getSectionItems(v, sec), dup, &sum); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	return sum, nil
+}
+
+func (s Service) hasSection(name string) bool {
+	for _, sec := range s.sections {
+		if sec.Name == name {
+			return true
+		}
+		for _, alias := range sec.Aliases {
+			if alias == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s Service) importItems(ctx context.Context, uid string, t data.StorageType, items []map[string]any,
+	dup Duplicates, sum *Summary,
+) error {
+	stored, err := getStoredNames(ctx, s.dataService, uid, t)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		delete(item, "id")
+		if s.normalizer != nil {
+			if item, err = s.normalizer.NormalizePayload(ctx, uid, t, item); err != nil {
+				return err
+			}
+		}
+		name, _ := item["name"].(string)
+		id, exists := stored[name]
+
+		switch {
+		case exists && dup == DuplicatesSkip:
+			sum.Skipped++
+		case exists && dup == DuplicatesReplace:
+			if err = s.dataService.UpdateSecureDataFromPayload(ctx, uid, id, item, t); err != nil {
+				return err
+			}
+			sum.Replaced++
+		default:
+			if id, err = s.dataService.StoreSecureDataFromPayload(ctx, uid, item, t); err != nil {
+				return err
+			}
+			if !exists {
+				stored[name] = id
+			}
+			sum.Imported++
+		}
+	}
+	return nil
+}
+
+func exportItems(ctx context.Context, ds data.Service, uid string, t data.StorageType) ([]map[string]any, error) {
+	sd, err := ds.GetAllDataByType(ctx, uid, t)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]map[string]any, 0, len(sd))
+	for _, d := range sd {
+		if d.Shared {
+			continue
+		}
+
+		item, dErr := decryptItem(ds, uid, d)
+		if dErr != nil {
+			return nil, dErr
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// getSectionItems returns the items of the section, along with the ones the earlier archives held under its aliases.
+func getSectionItems(v Vault, sec Section) []map[string]any {
+	items := v[sec.Name]
+	for _, alias := range sec.Aliases {
+		items = append(items, v[alias]...)
+	}
+	return items
+}
+
+// getStoredNames maps the names of the items owned by the user to their IDs.
+func getStoredNames(ctx context.Context, ds data.Service, uid string, t data.StorageType) (map[string]string, error) {
+	sd, err := ds.GetAllDataByType(ctx, uid, t)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(sd))
+	for _, d := range sd {
+		if d.Shared {
+			continue
+		}
+
+		item, dErr := decryptItem(ds, uid, d)
+		if dErr != nil {
+			return nil, dErr
+		}
+		name, _ := item["name"].(string)
+		if _, ok := names[name]; !ok {
+			names[name] = d.ID
+		}
+	}
+	return names, nil
+}
+
+// decryptItem returns the payload of the item without its ID.
+// The payloads stored by the earlier versions hold the ID and the typed values, so the numbers and flags are skipped.
+// The lists are kept, since they hold the custom fields of the items and the fields of the templates.
+func decryptItem(ds data.Service, uid string, d data.SecureData) (map[string]any, error) {
+	b, err := ds.DecryptSecureData(uid, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload map[string]any
+	if err = json.Unmarshal(b, &payload); err != nil {
+		return nil, err
+	}
+
+	item := make(map[string]any, len(payload))
+	for k, v := range payload {
+		switch v.(type) {
+		case string, []any:
+			if k != "id" {
+				item[k] = v
+			}
+		}
+	}
+	return item, nil
+}
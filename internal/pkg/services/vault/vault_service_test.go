@@ -0,0 +1,275 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/archive"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/services/data"
+	"github.com/agodlevskii/goph-keeper/internal/pkg/storage"
+)
+
+const (
+	testUID      = "testID"
+	testPassword = "password"
+)
+
+var errTest = errors.New("test error")
+
+var testSections = []Section{
+	{Name: "binaries", Storage: data.SBinary},
+	{Name: "cards", Storage: data.SCard},
+	{Name: "passwords", Storage: data.SPassword},
+	{Name: "texts", Aliases: []string{"notes"}, Storage: data.SText},
+	{Name: "custom items", Storage: data.SCustom},
+}
+
+func TestNewService(t *testing.T) {
+	ds := initDataService(t)
+	assert.Equal(t, Service{dataService: ds, sections: testSections},
+		NewService(storage.UnitOfWork{}, ds, testSections, nil))
+}
+
+func TestService_Export(t *testing.T) {
+	s, ds := initService(t)
+	storeTestItems(t, ds, testUID)
+
+	sharedID, err := ds.StoreSecureDataFromPayload(context.Background(), "owner", map[string]string{"name": "shared"},
+		data.SText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ds.ShareData(context.Background(), "owner", sharedID, testUID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, data.ErrEmpty, s.Export(context.Background(), "", &bytes.Buffer{}, testPassword))
+	assert.Equal(t, enc.ErrPasswordLength, s.Export(context.Background(), testUID, &bytes.Buffer{}, ""))
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.Export(context.Background(), testUID, &buf, testPassword))
+
+	var got Vault
+	h, err := archive.Read(&buf, magic, Version, testPassword, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, Version, h.Version)
+	assert.Equal(t, getTestVault(), got)
+}
+
+func TestService_Import(t *testing.T) {
+	file := getTestArchive(t, getTestVault())
+	tests := []struct {
+		name     string
+		file     []byte
+		stored   bool
+		password string
+		dup      Duplicates
+		want     Summary
+		wantLen  int
+		wantErr  error
+	}{
+		{
+			name:     "Policy is unknown",
+			password: testPassword,
+			dup:      "merge",
+			wantErr:  ErrDuplicates,
+		},
+		{
+			name:    "Password is missing",
+			wantErr: enc.ErrPasswordLength,
+		},
+		{
+			name:     "Section is unknown",
+			file:     getTestArchive(t, Vault{"keys": {{"name": "key"}}, "texts": {{"name": "text"}}}),
+			password: testPassword,
+			want:     Summary{Imported: 1, UnknownSections: 1},
+			wantLen:  1,
+		},
+		{
+			name:     "Section is named by the alias",
+			file:     getTestArchive(t, Vault{"notes": {{"name": "note"}}, "texts": {{"name": "text"}}}),
+			password: testPassword,
+			want:     Summary{Imported: 2},
+			wantLen:  2,
+		},
+		{
+			name:     "Password is wrong",
+			password: "wrong",
+			wantErr:  archive.ErrIntegrity,
+		},
+		{
+			name:     "Account is empty",
+			password: testPassword,
+			want:     Summary{Imported: 5},
+			wantLen:  5,
+		},
+		{
+			name:     "Duplicates are skipped",
+			stored:   true,
+			password: testPassword,
+			want:     Summary{Skipped: 5},
+			wantLen:  5,
+		},
+		{
+			name:     "Duplicates are replaced",
+			stored:   true,
+			password: testPassword,
+			dup:      DuplicatesReplace,
+			want:     Summary{Replaced: 5},
+			wantLen:  5,
+		},
+		{
+			name:     "Duplicates are kept",
+			stored:   true,
+			password: testPassword,
+			dup:      DuplicatesKeep,
+			want:     Summary{Imported: 5},
+			wantLen:  10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ds := initService(t)
+			if tt.stored {
+				storeTestItems(t, ds, testUID)
+			}
+
+			if tt.file == nil {
+				tt.file = file
+			}
+			got, err := s.Import(context.Background(), testUID, bytes.NewReader(tt.file), tt.password, tt.dup)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+
+			dump, err := ds.Export(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, dump.Data, tt.wantLen)
+		})
+	}
+
+	t.Run("Imported items are normalized", func(t *testing.T) {
+		ds := initDataService(t)
+		s := NewService(storage.UnitOfWork{}, ds, testSections, testNormalizer{})
+		file := getTestArchive(t, Vault{"texts": {{"name": "text", "extra": "dropped"}}})
+		got, err := s.Import(context.Background(), testUID, bytes.NewReader(file), testPassword, DuplicatesSkip)
+		assert.NoError(t, err)
+		assert.Equal(t, Summary{Imported: 1}, got)
+
+		var buf bytes.Buffer
+		assert.NoError(t, s.Export(context.Background(), testUID, &buf, testPassword))
+		var v Vault
+		_, err = archive.Read(&buf, magic, Version, testPassword, &v)
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]any{{"name": "text"}}, v["texts"])
+
+		file = getTestArchive(t, Vault{"texts": {{"name": "invalid"}}, "cards": {{"name": "card"}}})
+		_, err = s.Import(context.Background(), "other", bytes.NewReader(file), testPassword, DuplicatesSkip)
+		assert.Equal(t, errTest, err)
+		dump, err := ds.Export(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, dump.Data, 1)
+	})
+
+	t.Run("Imported items are decrypted by the user", func(t *testing.T) {
+		s, ds := initService(t)
+		_, err := s.Import(context.Background(), "other", bytes.NewReader(file), testPassword, DuplicatesSkip)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, s.Export(context.Background(), "other", &buf, testPassword))
+		var got Vault
+		_, err = archive.Read(&buf, magic, Version, testPassword, &got)
+		assert.NoError(t, err)
+		assert.Equal(t, getTestVault(), got)
+
+		sd, err := ds.GetAllDataByType(context.Background(), testUID, data.SCard)
+		assert.NoError(t, err)
+		assert.Empty(t, sd)
+	})
+}
+
+// testNormalizer keeps the names of the items only, and rejects the item named "invalid".
+type testNormalizer struct{}
+
+func (testNormalizer) NormalizePayload(_ context.Context, _ string, _ data.StorageType,
+	item map[string]any,
+) (map[string]any, error) {
+	if item["name"] == "invalid" {
+		return nil, errTest
+	}
+	return map[string]any{"name": item["name"]}, nil
+}
+
+func initDataService(t *testing.T) data.Service {
+	t.Helper()
+	ds, err := data.NewService(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
+
+func initService(t *testing.T) (Service, data.Service) {
+	t.Helper()
+	ds := initDataService(t)
+	return NewService(storage.UnitOfWork{}, ds, testSections, nil), ds
+}
+
+func getTestVault() Vault {
+	return Vault{
+		"binaries": {{"name": "binary", "data": "dGVzdA==", "note": "note"}},
+		"cards": {{
+			"name": "card", "number": "4111111111111111", "holder": "Test", "exp_date": "01/30", "cvv": "123", "note": "note",
+		}},
+		"passwords": {{"name": "password", "user": "test", "password": "test", "note": "note"}},
+		"texts":     {{"name": "text", "data": "test", "note": "note"}},
+		"custom items": {{
+			"name": "db", "template": "", "note": "",
+			"custom": []any{map[string]any{"name": "host", "kind": "url", "value": "https://db"}},
+		}},
+	}
+}
+
+// storeTestItems stores the items of the test vault for the user, as if they were created by the clients.
+// The card is stored as the earlier versions did, with the ID in the payload, and the export must drop it.
+func storeTestItems(t *testing.T, ds data.Service, uid string) {
+	t.Helper()
+	v := getTestVault()
+	v["cards"] = nil
+	for _, sec := range testSections {
+		for _, item := range v[sec.Name] {
+			if _, err := ds.StoreSecureDataFromPayload(context.Background(), uid, item, sec.Storage); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	c := getTestVault()["cards"][0]
+	typed := struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Number  string `json:"number"`
+		Holder  string `json:"holder"`
+		ExpDate string `json:"exp_date"`
+		CVV     string `json:"cvv"`
+		Note    string `json:"note"`
+	}{"id", c["name"].(string), c["number"].(string), c["holder"].(string), c["exp_date"].(string), c["cvv"].(string),
+		c["note"].(string)}
+	if _, err := ds.StoreSecureDataFromPayload(context.Background(), uid, typed, data.SCard); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getTestArchive(t *testing.T, v Vault) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := archive.Write(&buf, magic, Version, testPassword, v); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,177 @@
+// Package storage opens the database connection pool shared by the repositories of all microservices.
+// The URL scheme selects the backend: PostgreSQL by default, or the SQLite file for the single-node deployments.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+)
+
+// SQLiteScheme prefixes the path to the SQLite database file, e.g. sqlite:///var/lib/goph-keeper/keeper.db.
+const SQLiteScheme = "sqlite://"
+
+// sqliteParams enforce the foreign keys, wait for the locks instead of failing,
+// and take the write lock at the start of the transaction, so the units of work don't deadlock on the upgrade.
+const sqliteParams = "_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate"
+
+// Config describes the database and the limits of the connection pool.
+// The zero limits keep the pgxpool defaults.
+// Without the database, the in-memory repositories are persisted to the data directory, if it is set.
+type Config struct {
+	URL              string
+	MaxConns         int
+	MaxConnLifetime  time.Duration
+	MaxConnIdleTime  time.Duration
+	ConnectTimeout   time.Duration
+	DataDir          string
+	SnapshotInterval time.Duration
+}
+
+// Storage holds the connection pool and the database/sql handle that the repositories are built upon,
+// or the logs of the in-memory repositories.
+type Storage struct {
+	pool     *pgxpool.Pool
+	db       *sql.DB
+	dataDir  string
+	interval time.Duration
+	mu       sync.Mutex
+	wals     []*WAL
+}
+
+// New opens the connection pool and verifies that the database is reachable.
+// If the URL is empty, the storage has no database, and the services fall back to the in-memory repositories.
+func New(ctx context.Context, cfg Config) (*Storage, error) {
+	if cfg.URL == "" {
+		return &Storage{dataDir: cfg.DataDir, interval: cfg.SnapshotInterval}, nil
+	}
+	if strings.HasPrefix(cfg.URL, SQLiteScheme) {
+		return openSQLite(ctx, strings.TrimPrefix(cfg.URL, SQLiteScheme))
+	}
+
+	pCfg, err := getPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, pCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &Storage{pool: pool, db: stdlib.OpenDBFromPool(pool)}, nil
+}
+
+// DB returns the handle to inject into the repositories, or nil if the storage has no database.
+func (s *Storage) DB() *sql.DB {
+	return s.db
+}
+
+// WAL opens the log of the in-memory repository with the specified name.
+// It returns nil if the storage has the database or no data directory, so the repository is not persisted.
+func (s *Storage) WAL(name string) (*WAL, error) {
+	if s.db != nil || s.dataDir == "" {
+		return nil, nil
+	}
+
+	w, err := OpenWAL(s.dataDir, name, s.interval)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.wals = append(s.wals, w)
+	s.mu.Unlock()
+	return w, nil
+}
+
+// Close closes the handle and all connections of the pool, or compacts and closes the logs.
+func (s *Storage) Close() error {
+	if s.db == nil {
+		return s.closeWALs()
+	}
+
+	err := s.db.Close()
+	if s.pool != nil {
+		s.pool.Close()
+	}
+	return err
+}
+
+func (s *Storage) closeWALs() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for _, w := range s.wals {
+		if wErr := w.Close(); wErr != nil {
+			log.Error(wErr)
+			err = wErr
+		}
+	}
+	s.wals = nil
+	return err
+}
+
+// IsSQLite reports whether the handle is opened to the SQLite database.
+// The repositories use it to pick the SQL dialect.
+func IsSQLite(db *sql.DB) bool {
+	if db == nil {
+		return false
+	}
+	_, ok := db.Driver().(*sqlite3.SQLiteDriver)
+	return ok
+}
+
+// openSQLite opens the database file, creating it if necessary.
+// SQLite allows a single writer only, so the handle keeps a single connection, and the writes are queued.
+func openSQLite(ctx context.Context, path string) (*Storage, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	db, err := sql.Open("sqlite3", path+sep+sqliteParams)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Storage{db: db}, nil
+}
+
+func getPoolConfig(cfg Config) (*pgxpool.Config, error) {
+	pCfg, err := pgxpool.ParseConfig(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxConns > 0 {
+		pCfg.MaxConns = int32(cfg.MaxConns)
+	}
+	if cfg.MaxConnLifetime > 0 {
+		pCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		pCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.ConnectTimeout > 0 {
+		pCfg.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	}
+	return pCfg, nil
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantDB  bool
+		wantErr bool
+	}{
+		{
+			name: "Repo URL is missing",
+		},
+		{
+			name:    "Invalid Repo URL is present",
+			cfg:     Config{URL: "postgres://localhost:port/test"},
+			wantErr: true,
+		},
+		{
+			name:    "Wrong Repo URL is present",
+			cfg:     Config{URL: "postgres://localhost:5432/test", ConnectTimeout: time.Second},
+			wantErr: true,
+		},
+		{
+			name:   "SQLite file is created",
+			cfg:    Config{URL: SQLiteScheme + "{dir}/test.db"},
+			wantDB: true,
+		},
+		{
+			name:    "SQLite directory is missing",
+			cfg:     Config{URL: SQLiteScheme + "{dir}/missing/test.db"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.URL = strings.ReplaceAll(tt.cfg.URL, "{dir}", t.TempDir())
+			got, err := New(context.Background(), tt.cfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tt.wantDB, got.DB() != nil)
+				assert.NoError(t, got.Close())
+			}
+		})
+	}
+}
+
+func TestStorage_WAL(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataDir bool
+		want    bool
+	}{
+		{
+			name: "Data directory is missing",
+		},
+		{
+			name:    "Data directory is present",
+			dataDir: true,
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			if tt.dataDir {
+				cfg.DataDir = t.TempDir()
+			}
+			st, err := New(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := st.WAL("test")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got != nil)
+			assert.NoError(t, st.Close())
+			if tt.want {
+				assert.ErrorIs(t, got.Compact(), ErrWALClosed)
+			}
+		})
+	}
+}
+
+func TestIsSQLite(t *testing.T) {
+	pg, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := New(context.Background(), Config{URL: SQLiteScheme + filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { assert.NoError(t, st.Close()) }()
+
+	tests := []struct {
+		name string
+		db   *sql.DB
+		want bool
+	}{
+		{
+			name: "DB is missing",
+		},
+		{
+			name: "Other DB",
+			db:   pg,
+		},
+		{
+			name: "SQLite DB",
+			db:   st.DB(),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSQLite(tt.db))
+		})
+	}
+}
+
+func Test_getPoolConfig(t *testing.T) {
+	type want struct {
+		maxConns        int32
+		maxConnLifetime time.Duration
+		maxConnIdleTime time.Duration
+		connectTimeout  time.Duration
+	}
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    want
+		wantErr bool
+	}{
+		{
+			name: "Limits are set",
+			cfg: Config{
+				URL:             "postgres://localhost:5432/test",
+				MaxConns:        8,
+				MaxConnLifetime: time.Minute * 10,
+				MaxConnIdleTime: time.Minute,
+				ConnectTimeout:  time.Second * 3,
+			},
+			want: want{
+				maxConns:        8,
+				maxConnLifetime: time.Minute * 10,
+				maxConnIdleTime: time.Minute,
+				connectTimeout:  time.Second * 3,
+			},
+		},
+		{
+			name: "Limits are missing",
+			cfg:  Config{URL: "postgres://localhost:5432/test?pool_max_conns=5&connect_timeout=7"},
+			want: want{
+				maxConns:        5,
+				maxConnLifetime: time.Hour,
+				maxConnIdleTime: time.Minute * 30,
+				connectTimeout:  time.Second * 7,
+			},
+		},
+		{
+			name:    "Invalid Repo URL",
+			cfg:     Config{URL: "postgres://localhost:port/test"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getPoolConfig(tt.cfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tt.want.maxConns, got.MaxConns)
+				assert.Equal(t, tt.want.maxConnLifetime, got.MaxConnLifetime)
+				assert.Equal(t, tt.want.maxConnIdleTime, got.MaxConnIdleTime)
+				assert.Equal(t, tt.want.connectTimeout, got.ConnConfig.ConnectTimeout)
+			}
+		})
+	}
+}
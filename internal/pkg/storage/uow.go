@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Querier is implemented by both the database handle and the transaction, so the repositories run the same queries
+// either inside or outside the unit of work.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+type journalKey struct{}
+
+// journal holds the functions undoing the in-memory changes made within the unit of work,
+// and the records of the changes to be written to the logs on commit.
+type journal struct {
+	undo    []func()
+	pending map[*WAL][]walRecord
+}
+
+// basicMu serializes the units of work running against the in-memory repositories.
+var basicMu sync.Mutex
+
+// UnitOfWork runs several repository calls atomically.
+// The repositories join the unit of work through the context passed to the function.
+// The zero value runs the calls against the in-memory repositories.
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork returns the unit of work running the calls in the database transactions.
+// If the database handle is nil, the in-memory repositories are used instead.
+func NewUnitOfWork(db *sql.DB) UnitOfWork {
+	return UnitOfWork{db: db}
+}
+
+// Do runs the function within the unit of work, and commits all changes only if the function succeeds.
+// If the context already belongs to a unit of work, the function joins it.
+func (u UnitOfWork) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	return u.do(ctx, nil, f)
+}
+
+// Snapshot runs the function within the read-only unit of work, which sees the data as of its start,
+// so the reads are consistent with each other while the data is changed concurrently, e.g. for the backup.
+// The in-memory units of work are serialized, so they see no concurrent changes made within the units of work.
+func (u UnitOfWork) Snapshot(ctx context.Context, f func(ctx context.Context) error) error {
+	return u.do(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, f)
+}
+
+func (u UnitOfWork) do(ctx context.Context, opts *sql.TxOptions, f func(ctx context.Context) error) error {
+	if u.db == nil {
+		return doBasic(ctx, f)
+	}
+
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return f(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err = f(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rErr := tx.Rollback(); rErr != nil {
+			log.Error(rErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Conn returns the transaction of the unit of work the context belongs to, or the database handle otherwise.
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// Store stores the value in the in-memory repository map.
+// The change of the tracked map is logged first, and the map is left unchanged if the log fails.
+// Within the unit of work, the previous value is restored if the unit fails.
+func Store(ctx context.Context, m *sync.Map, key, value any) error {
+	tm, isTracked, unlock := lockTracked(m)
+	defer unlock()
+
+	if isTracked {
+		if err := tm.log(ctx, key, value, false); err != nil {
+			return err
+		}
+	}
+
+	prev, ok := m.Load(key)
+	m.Store(key, value)
+	onRollback(ctx, func() {
+		if ok {
+			m.Store(key, prev)
+		} else {
+			m.Delete(key)
+		}
+	})
+	return nil
+}
+
+// Delete removes the value from the in-memory repository map, and reports whether the value was present.
+// The change of the tracked map is logged first, and the map is left unchanged if the log fails.
+// Within the unit of work, the value is restored if the unit fails.
+func Delete(ctx context.Context, m *sync.Map, key any) (bool, error) {
+	tm, isTracked, unlock := lockTracked(m)
+	defer unlock()
+
+	if _, ok := m.Load(key); !ok {
+		return false, nil
+	}
+	if isTracked {
+		if err := tm.log(ctx, key, nil, true); err != nil {
+			return false, err
+		}
+	}
+
+	prev, _ := m.LoadAndDelete(key)
+	onRollback(ctx, func() {
+		m.Store(key, prev)
+	})
+	return true, nil
+}
+
+func doBasic(ctx context.Context, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(journalKey{}).(*journal); ok {
+		return f(ctx)
+	}
+
+	basicMu.Lock()
+	defer basicMu.Unlock()
+
+	j := &journal{}
+	if err := f(context.WithValue(ctx, journalKey{}, j)); err != nil {
+		j.rollback()
+		return err
+	}
+	return j.commit()
+}
+
+// commit writes the changes made within the unit of work to the logs of the tracked maps.
+// If any log fails, the changes are undone, and the logs already written get the records of the restored values,
+// so the recovery doesn't replay the changes either.
+func (j *journal) commit() error {
+	written := make([]*WAL, 0, len(j.pending))
+	for w, recs := range j.pending {
+		w.mu.Lock()
+		err := w.append(recs)
+		w.mu.Unlock()
+		if err != nil {
+			j.rollback()
+			j.revert(written)
+			return err
+		}
+		written = append(written, w)
+	}
+	return nil
+}
+
+func (j *journal) rollback() {
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		j.undo[i]()
+	}
+}
+
+// revert logs the current values of the keys changed within the unit of work.
+func (j *journal) revert(wals []*WAL) {
+	for _, w := range wals {
+		w.mu.Lock()
+		recs := make([]walRecord, 0, len(j.pending[w]))
+		for _, rec := range j.pending[w] {
+			cur, err := w.current(rec)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			recs = append(recs, cur)
+		}
+		if err := w.append(recs); err != nil {
+			log.Error(err)
+		}
+		w.mu.Unlock()
+	}
+}
+
+func onRollback(ctx context.Context, undo func()) {
+	if j, ok := ctx.Value(journalKey{}).(*journal); ok {
+		j.undo = append(j.undo, undo)
+	}
+}
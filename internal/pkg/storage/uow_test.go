@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTest = errors.New("test error")
+
+func TestConn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Querier
+	}{
+		{
+			name: "Outside the unit of work",
+			ctx:  context.Background(),
+			want: db,
+		},
+		{
+			name: "Within the unit of work",
+			ctx:  context.WithValue(context.Background(), txKey{}, tx),
+			want: tx,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Conn(tt.ctx, db))
+		})
+	}
+}
+
+func TestUnitOfWork_Do(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		nested  bool
+		wantErr error
+	}{
+		{
+			name: "Unit succeeds",
+		},
+		{
+			name:    "Unit fails",
+			err:     errTest,
+			wantErr: errTest,
+		},
+		{
+			name:   "Nested unit joins the outer one",
+			nested: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			mock.ExpectBegin()
+			mock.ExpectExec("DELETE FROM test").WillReturnResult(sqlmock.NewResult(0, 1))
+			if tt.err != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			u := NewUnitOfWork(db)
+			err = u.Do(context.Background(), func(ctx context.Context) error {
+				f := func(ctx context.Context) error {
+					_, eErr := Conn(ctx, db).ExecContext(ctx, "DELETE FROM test")
+					assert.NoError(t, eErr)
+					return tt.err
+				}
+				if tt.nested {
+					return u.Do(ctx, f)
+				}
+				return f(ctx)
+			})
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUnitOfWork_DoBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "Unit succeeds",
+			want: map[string]string{"added": "value", "updated": "new"},
+		},
+		{
+			name:    "Unit fails",
+			err:     errTest,
+			want:    map[string]string{"deleted": "value", "updated": "old"},
+			wantErr: errTest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &sync.Map{}
+			m.Store("deleted", "value")
+			m.Store("updated", "old")
+
+			err := UnitOfWork{}.Do(context.Background(), func(ctx context.Context) error {
+				assert.NoError(t, Store(ctx, m, "added", "value"))
+				assert.NoError(t, Store(ctx, m, "updated", "new"))
+				ok, err := Delete(ctx, m, "deleted")
+				assert.True(t, ok)
+				assert.NoError(t, err)
+				ok, err = Delete(ctx, m, "missing")
+				assert.False(t, ok)
+				assert.NoError(t, err)
+				return tt.err
+			})
+			assert.Equal(t, tt.wantErr, err)
+
+			got := make(map[string]string)
+			m.Range(func(k, v any) bool {
+				got[k.(string)] = v.(string)
+				return true
+			})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnitOfWork_Snapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM test").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("testID"))
+	mock.ExpectCommit()
+
+	err = NewUnitOfWork(db).Snapshot(context.Background(), func(ctx context.Context) error {
+		var id string
+		return Conn(ctx, db).QueryRowContext(ctx, "SELECT id FROM test").Scan(&id)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_Snapshot_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st, err := New(ctx, Config{URL: SQLiteScheme + filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	err = NewUnitOfWork(st.DB()).Snapshot(ctx, func(ctx context.Context) error {
+		var n int
+		return Conn(ctx, st.DB()).QueryRowContext(ctx, "SELECT 1").Scan(&n)
+	})
+	assert.NoError(t, err)
+}
+
+func TestStore(t *testing.T) {
+	m := &sync.Map{}
+	assert.NoError(t, Store(context.Background(), m, "key", "value"))
+	assert.NoError(t, Store(context.Background(), m, "key", "new"))
+
+	got, ok := m.Load("key")
+	assert.True(t, ok)
+	assert.Equal(t, "new", got)
+}
+
+func TestDelete(t *testing.T) {
+	m := &sync.Map{}
+	m.Store("key", "value")
+
+	ok, err := Delete(context.Background(), m, "key")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	ok, err = Delete(context.Background(), m, "key")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+}
+
+func TestNewUnitOfWork(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		db   *sql.DB
+		want UnitOfWork
+	}{
+		{
+			name: "DB is missing",
+		},
+		{
+			name: "DB is present",
+			db:   db,
+			want: UnitOfWork{db: db},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewUnitOfWork(tt.db))
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/agodlevskii/goph-keeper/internal/pkg/enc"
+)
+
+const (
+	walExt      = ".wal"
+	snapshotExt = ".snapshot"
+	frameHeader = 4
+)
+
+var (
+	ErrWALClosed  = errors.New("the write-ahead log is closed")
+	ErrMapTracked = errors.New("the map is already tracked")
+)
+
+// tracked binds the in-memory repository maps to the logs persisting their changes.
+var tracked sync.Map
+
+// WAL persists the in-memory repository maps to the data directory.
+// Every change is appended to the write-ahead log as soon as it is made, or on the commit of the unit of work,
+// and the periodic snapshot of all maps compacts the log. Both files are encrypted with the server key.
+type WAL struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+	maps map[string]trackedMap
+	stop chan struct{}
+	done chan struct{}
+}
+
+type trackedMap struct {
+	wal    *WAL
+	name   string
+	m      *sync.Map
+	decode func([]byte) (any, error)
+}
+
+// walRecord is the single change of the map. A batch of records is written as one frame,
+// so the unit of work is either replayed completely or not at all.
+type walRecord struct {
+	Map    string
+	Key    string
+	Value  []byte
+	Delete bool
+}
+
+// OpenWAL opens the log of the repository with the specified name, creating the data directory if necessary.
+// If the interval is positive, the log is compacted periodically until it is closed.
+// The maps must be tracked and recovered before the repository is used.
+func OpenWAL(dir, name string, interval time.Duration) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path+walExt, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WAL{path: path, file: f, maps: make(map[string]trackedMap)}
+	if interval > 0 {
+		w.stop, w.done = make(chan struct{}), make(chan struct{})
+		go w.run(interval)
+	}
+	return w, nil
+}
+
+// Track registers the map under the name, so its changes made with Store and Delete are logged.
+// The values are restored as T on recovery.
+func Track[T any](w *WAL, name string, m *sync.Map) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.maps[name]; ok {
+		return fmt.Errorf("%w: %s", ErrMapTracked, name)
+	}
+
+	tm := trackedMap{wal: w, name: name, m: m, decode: func(b []byte) (any, error) {
+		var v T
+		err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v)
+		return v, err
+	}}
+	if _, loaded := tracked.LoadOrStore(m, tm); loaded {
+		return fmt.Errorf("%w: %s", ErrMapTracked, name)
+	}
+	w.maps[name] = tm
+	return nil
+}
+
+// Recover loads the latest snapshot into the tracked maps, and replays the log on top of it.
+// The frame torn by the crash at the end of the log is dropped along with the rest of the log.
+func (w *WAL) Recover() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.loadSnapshot(); err != nil {
+		return err
+	}
+
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var offset int64
+	for {
+		recs, n, err := readFrame(w.file)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Warnf("%s: dropping the log tail at %d: %v", w.path+walExt, offset, err)
+			break
+		}
+		if err = w.apply(recs); err != nil {
+			return err
+		}
+		offset += n
+	}
+
+	if err := w.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := w.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// Compact writes the snapshot of all tracked maps, and truncates the log.
+// The running units of work are awaited, so the snapshot never contains the changes that may be rolled back.
+func (w *WAL) Compact() error {
+	basicMu.Lock()
+	defer basicMu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return ErrWALClosed
+	}
+	if err := w.writeSnapshot(); err != nil {
+		return err
+	}
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := w.file.Seek(0, io.SeekStart)
+	return err
+}
+
+// Close stops the periodic compaction, compacts the log for the last time, and closes it.
+func (w *WAL) Close() error {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+
+	err := w.Compact()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, tm := range w.maps {
+		tracked.Delete(tm.m)
+	}
+	if w.file != nil {
+		if cErr := w.file.Close(); err == nil {
+			err = cErr
+		}
+		w.file = nil
+	}
+	return err
+}
+
+func (w *WAL) run(interval time.Duration) {
+	defer close(w.done)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+			if err := w.Compact(); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
+
+// append writes the batch of records to the log, and waits until it reaches the disk.
+// The caller must hold the lock.
+func (w *WAL) append(recs []walRecord) error {
+	if w.file == nil {
+		return ErrWALClosed
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(recs); err != nil {
+		return err
+	}
+	data, err := enc.EncryptData(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, frameHeader, frameHeader+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	if _, err = w.file.Write(append(frame, data...)); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+func (w *WAL) apply(recs []walRecord) error {
+	for _, rec := range recs {
+		tm, ok := w.maps[rec.Map]
+		if !ok {
+			log.Warnf("%s: skipping the record of the unknown map %s", w.path+walExt, rec.Map)
+			continue
+		}
+
+		if rec.Delete {
+			tm.m.Delete(rec.Key)
+			continue
+		}
+		v, err := tm.decode(rec.Value)
+		if err != nil {
+			return err
+		}
+		tm.m.Store(rec.Key, v)
+	}
+	return nil
+}
+
+func (w *WAL) loadSnapshot() error {
+	data, err := os.ReadFile(w.path + snapshotExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if data, err = enc.DecryptData(data); err != nil {
+		return err
+	}
+	var snap map[string]map[string][]byte
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&snap); err != nil {
+		return err
+	}
+
+	for name, values := range snap {
+		for k, v := range values {
+			if err = w.apply([]walRecord{{Map: name, Key: k, Value: v}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeSnapshot replaces the snapshot atomically, so the crash leaves either the previous or the new one.
+func (w *WAL) writeSnapshot() error {
+	snap := make(map[string]map[string][]byte, len(w.maps))
+	for name, tm := range w.maps {
+		values := make(map[string][]byte)
+		var err error
+		tm.m.Range(func(k, v any) bool {
+			var b []byte
+			if b, err = encodeValue(v); err != nil {
+				return false
+			}
+			values[fmt.Sprint(k)] = b
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		snap[name] = values
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snap); err != nil {
+		return err
+	}
+	data, err := enc.EncryptData(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	tmp := w.path + snapshotExt + ".tmp"
+	if err = writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.path+snapshotExt)
+}
+
+// log records the change of the tracked map, or postpones it until the commit within the unit of work.
+// The caller must hold the lock.
+func (tm trackedMap) log(ctx context.Context, key, value any, del bool) error {
+	rec := walRecord{Map: tm.name, Key: fmt.Sprint(key), Delete: del}
+	if !del {
+		b, err := encodeValue(value)
+		if err != nil {
+			return err
+		}
+		rec.Value = b
+	}
+
+	if j, ok := ctx.Value(journalKey{}).(*journal); ok {
+		if j.pending == nil {
+			j.pending = make(map[*WAL][]walRecord)
+		}
+		j.pending[tm.wal] = append(j.pending[tm.wal], rec)
+		return nil
+	}
+	return tm.wal.append([]walRecord{rec})
+}
+
+// current returns the record of the present value of the key the record changes.
+// The caller must hold the lock.
+func (w *WAL) current(rec walRecord) (walRecord, error) {
+	cur := walRecord{Map: rec.Map, Key: rec.Key, Delete: true}
+	if tm, ok := w.maps[rec.Map]; ok {
+		if v, ok := tm.m.Load(rec.Key); ok {
+			b, err := encodeValue(v)
+			if err != nil {
+				return walRecord{}, err
+			}
+			cur.Value, cur.Delete = b, false
+		}
+	}
+	return cur, nil
+}
+
+// lockTracked locks the log of the map, if the map is tracked, so the change and its record are made in order.
+// The returned function releases the lock.
+func lockTracked(m *sync.Map) (trackedMap, bool, func()) {
+	v, ok := tracked.Load(m)
+	if !ok {
+		return trackedMap{}, false, func() {}
+	}
+
+	tm := v.(trackedMap)
+	tm.wal.mu.Lock()
+	return tm, true, tm.wal.mu.Unlock
+}
+
+func encodeValue(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func readFrame(r io.Reader) ([]walRecord, int64, error) {
+	header := make([]byte, frameHeader)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(header))
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, 0, err
+	}
+
+	plain, err := enc.DecryptData(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	var recs []walRecord
+	if err = gob.NewDecoder(bytes.NewReader(plain)).Decode(&recs); err != nil {
+		return nil, 0, err
+	}
+	return recs, int64(frameHeader + len(data)), nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
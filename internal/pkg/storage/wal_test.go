@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type walItem struct {
+	Name    string
+	Secret  []byte `json:"-"`
+	Created time.Time
+}
+
+func TestOpenWAL(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		interval time.Duration
+		wantErr  bool
+	}{
+		{
+			name: "Data directory is created",
+			dir:  "data",
+		},
+		{
+			name:     "Log is compacted periodically",
+			dir:      "data",
+			interval: time.Millisecond,
+		},
+		{
+			name:    "Data directory is a file",
+			dir:     "file",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "file"), nil, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			w, err := OpenWAL(filepath.Join(root, tt.dir), "test", tt.interval)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err == nil {
+				assert.FileExists(t, filepath.Join(root, tt.dir, "test"+walExt))
+				assert.NoError(t, w.Close())
+				assert.FileExists(t, filepath.Join(root, tt.dir, "test"+snapshotExt))
+			}
+		})
+	}
+}
+
+func TestTrack(t *testing.T) {
+	w := openTestWAL(t, t.TempDir())
+	m := &sync.Map{}
+
+	assert.NoError(t, Track[walItem](w, "items", m))
+	assert.ErrorIs(t, Track[walItem](w, "items", &sync.Map{}), ErrMapTracked)
+	assert.ErrorIs(t, Track[walItem](w, "other", m), ErrMapTracked)
+}
+
+func TestWAL_Recover(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	w, m := openTestMap(t, dir)
+	assert.NoError(t, Store(ctx, m, "kept", walItem{Name: "kept", Secret: []byte("secret"), Created: created}))
+	assert.NoError(t, Store(ctx, m, "updated", walItem{Name: "old"}))
+	assert.NoError(t, w.Compact())
+	assert.NoError(t, Store(ctx, m, "updated", walItem{Name: "new"}))
+	assert.NoError(t, Store(ctx, m, "deleted", walItem{Name: "deleted"}))
+	_, err := Delete(ctx, m, "deleted")
+	assert.NoError(t, err)
+
+	// The server crashes without closing the log, and the last frame is written partially.
+	crashTestWAL(t, w, []byte{0, 0, 1})
+
+	w, m = openTestMap(t, dir)
+	assert.NoError(t, w.Recover())
+	assert.Equal(t, map[string]walItem{
+		"kept":    {Name: "kept", Secret: []byte("secret"), Created: created},
+		"updated": {Name: "new"},
+	}, getTestItems(m))
+
+	// The torn frame is dropped, so the changes made after the recovery are replayed.
+	assert.NoError(t, Store(ctx, m, "added", walItem{Name: "added"}))
+	crashTestWAL(t, w, nil)
+
+	w, m = openTestMap(t, dir)
+	assert.NoError(t, w.Recover())
+	assert.Len(t, getTestItems(m), 3)
+	assert.NoError(t, w.Close())
+}
+
+func TestWAL_Compact(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	w, m := openTestMap(t, dir)
+	for _, k := range []string{"a", "b", "c"} {
+		assert.NoError(t, Store(ctx, m, k, walItem{Name: k}))
+	}
+	_, err := Delete(ctx, m, "b")
+	assert.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(dir, "test"+walExt))
+	assert.NoError(t, err)
+	assert.NotZero(t, info.Size())
+
+	assert.NoError(t, w.Compact())
+	info, err = os.Stat(filepath.Join(dir, "test"+walExt))
+	assert.NoError(t, err)
+	assert.Zero(t, info.Size())
+	assert.NoError(t, w.Close())
+	assert.ErrorIs(t, w.Compact(), ErrWALClosed)
+
+	w, m = openTestMap(t, dir)
+	assert.NoError(t, w.Recover())
+	assert.Equal(t, map[string]walItem{"a": {Name: "a"}, "c": {Name: "c"}}, getTestItems(m))
+	assert.NoError(t, w.Close())
+}
+
+func TestWAL_UnitOfWork(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	w, m := openTestMap(t, dir)
+	assert.NoError(t, UnitOfWork{}.Do(ctx, func(ctx context.Context) error {
+		assert.NoError(t, Store(ctx, m, "committed", walItem{Name: "committed"}))
+		return nil
+	}))
+	assert.Equal(t, errTest, UnitOfWork{}.Do(ctx, func(ctx context.Context) error {
+		assert.NoError(t, Store(ctx, m, "rolled back", walItem{Name: "rolled back"}))
+		return errTest
+	}))
+	crashTestWAL(t, w, nil)
+
+	w, m = openTestMap(t, dir)
+	assert.NoError(t, w.Recover())
+	assert.Equal(t, map[string]walItem{"committed": {Name: "committed"}}, getTestItems(m))
+	assert.NoError(t, w.Close())
+}
+
+func TestWAL_AppendFails(t *testing.T) {
+	ctx := context.Background()
+	w, m := openTestMap(t, t.TempDir())
+	m.Store("kept", walItem{Name: "kept"})
+	breakTestWAL(t, w)
+
+	assert.Error(t, Store(ctx, m, "added", walItem{Name: "added"}))
+	ok, err := Delete(ctx, m, "kept")
+	assert.False(t, ok)
+	assert.Error(t, err)
+	assert.Equal(t, map[string]walItem{"kept": {Name: "kept"}}, getTestItems(m))
+}
+
+func TestWAL_UnitOfWork_CommitFails(t *testing.T) {
+	ctx := context.Background()
+	w, m := openTestMap(t, t.TempDir())
+	assert.NoError(t, Store(ctx, m, "updated", walItem{Name: "old"}))
+
+	broken := openTestWAL(t, t.TempDir())
+	bm := &sync.Map{}
+	if err := Track[walItem](broken, "broken", bm); err != nil {
+		t.Fatal(err)
+	}
+	breakTestWAL(t, broken)
+
+	assert.Error(t, UnitOfWork{}.Do(ctx, func(ctx context.Context) error {
+		assert.NoError(t, Store(ctx, m, "updated", walItem{Name: "new"}))
+		assert.NoError(t, Store(ctx, m, "added", walItem{Name: "added"}))
+		return Store(ctx, bm, "added", walItem{Name: "added"})
+	}))
+	assert.Equal(t, map[string]walItem{"updated": {Name: "old"}}, getTestItems(m))
+	assert.Empty(t, getTestItems(bm))
+
+	// The log written before the failure gets the restored values, so the recovery matches the memory.
+	dir := filepath.Dir(w.path)
+	crashTestWAL(t, w, nil)
+	w, m = openTestMap(t, dir)
+	assert.NoError(t, w.Recover())
+	assert.Equal(t, map[string]walItem{"updated": {Name: "old"}}, getTestItems(m))
+	assert.NoError(t, w.Close())
+}
+
+func openTestWAL(t *testing.T, dir string) *WAL {
+	t.Helper()
+	w, err := OpenWAL(dir, "test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func openTestMap(t *testing.T, dir string) (*WAL, *sync.Map) {
+	t.Helper()
+	w := openTestWAL(t, dir)
+	m := &sync.Map{}
+	if err := Track[walItem](w, "items", m); err != nil {
+		t.Fatal(err)
+	}
+	return w, m
+}
+
+// crashTestWAL abandons the log without the final compaction, appending the garbage to its end.
+func crashTestWAL(t *testing.T, w *WAL, garbage []byte) {
+	t.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.file.Write(garbage); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	w.file = nil
+	for _, tm := range w.maps {
+		tracked.Delete(tm.m)
+	}
+}
+
+// breakTestWAL closes the file of the log behind its back, so the appends fail.
+func breakTestWAL(t *testing.T, w *WAL) {
+	t.Helper()
+	if err := w.file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for _, tm := range w.maps {
+			tracked.Delete(tm.m)
+		}
+	})
+}
+
+func getTestItems(m *sync.Map) map[string]walItem {
+	items := make(map[string]walItem)
+	m.Range(func(k, v any) bool {
+		items[k.(string)] = v.(walItem)
+		return true
+	})
+	return items
+}